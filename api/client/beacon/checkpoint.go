@@ -16,6 +16,7 @@ import (
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/encoding/ssz/detect"
 	"github.com/theQRL/qrysm/v4/io/file"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/runtime/version"
 	"github.com/theQRL/qrysm/v4/time/slots"
 	"golang.org/x/mod/semver"
@@ -59,6 +60,16 @@ func (o *OriginData) BlockBytes() []byte {
 	return o.bb
 }
 
+// State returns the downloaded BeaconState.
+func (o *OriginData) State() state.BeaconState {
+	return o.st
+}
+
+// BlockRoot returns the root of the downloaded block.
+func (o *OriginData) BlockRoot() [32]byte {
+	return o.br
+}
+
 func fname(prefix string, vu *detect.VersionedUnmarshaler, slot primitives.Slot, root [32]byte) string {
 	return fmt.Sprintf("%s_%s_%s_%d-%#x.ssz", prefix, vu.Config.ConfigName, version.String(vu.Fork), slot, root)
 }
@@ -124,6 +135,80 @@ func DownloadFinalizedData(ctx context.Context, client *Client) (*OriginData, er
 	}, nil
 }
 
+// DownloadCheckpointData downloads the state at the start of the epoch of the given checkpoint along with the
+// checkpoint block, and checks that both match the checkpoint root. Unlike DownloadFinalizedData, the result does not
+// depend on the view of the remote beacon node, so the checkpoint can be agreed upon beforehand.
+func DownloadCheckpointData(ctx context.Context, client *Client, cp *zondpb.Checkpoint) (*OriginData, error) {
+	root := bytesutil.ToBytes32(cp.Root)
+	slot, err := slots.EpochStart(cp.Epoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error computing first slot of epoch=%d", cp.Epoch)
+	}
+	sb, err := client.GetState(ctx, IdFromSlot(slot))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request state by slot from api, slot=%d", slot)
+	}
+	vu, err := detect.FromState(sb)
+	if err != nil {
+		return nil, errors.Wrap(err, "error detecting chain config for checkpoint state")
+	}
+	s, err := vu.UnmarshalBeaconState(sb)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling checkpoint state to correct version")
+	}
+	if s.Slot() != slot {
+		return nil, errors.Wrapf(errCheckpointBlockMismatch, "state slot = %d, checkpoint slot = %d", s.Slot(), slot)
+	}
+	sr, err := s.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute htr for checkpoint state at slot=%d", s.Slot())
+	}
+	// The state root of the latest block header is only filled in when the next slot is processed.
+	h := s.LatestBlockHeader()
+	if bytesutil.ToBytes32(h.StateRoot) == [32]byte{} {
+		h.StateRoot = sr[:]
+	}
+	hr, err := h.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "error computing hash_tree_root of state latest block header")
+	}
+	if hr != root {
+		return nil, errors.Wrapf(errCheckpointBlockMismatch, "state latest block root = %#x, checkpoint root = %#x", hr, root)
+	}
+
+	bb, err := client.GetBlock(ctx, IdFromRoot(root))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting block by root = %#x", root)
+	}
+	b, err := vu.UnmarshalBeaconBlock(bb)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal block to a supported type using the detected fork schedule")
+	}
+	br, err := b.Block().HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "error computing hash_tree_root of retrieved block")
+	}
+	if br != root {
+		return nil, errors.Wrapf(errCheckpointBlockMismatch, "block root = %#x, checkpoint root = %#x", br, root)
+	}
+
+	log.
+		WithField("block_slot", b.Block().Slot()).
+		WithField("state_slot", s.Slot()).
+		WithField("state_root", hexutil.Encode(sr[:])).
+		WithField("block_root", hexutil.Encode(br[:])).
+		Info("Downloaded checkpoint sync state and block.")
+	return &OriginData{
+		st: s,
+		b:  b,
+		sb: sb,
+		bb: bb,
+		vu: vu,
+		br: br,
+		sr: sr,
+	}, nil
+}
+
 // WeakSubjectivityData represents the state root, block root and epoch of the BeaconState + ReadOnlySignedBeaconBlock
 // that falls at the beginning of the current weak subjectivity period. These values can be used to construct
 // a weak subjectivity checkpoint beacon node flag to be used for validation.
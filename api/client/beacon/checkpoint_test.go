@@ -481,3 +481,78 @@ func TestDownloadFinalizedData(t *testing.T) {
 	require.Equal(t, expected.br, od.br)
 	require.Equal(t, expected.sr, od.sr)
 }
+
+func TestDownloadCheckpointData(t *testing.T) {
+	ctx := context.Background()
+	cfg := params.MainnetConfig().Copy()
+
+	epoch := cfg.AltairForkEpoch - 1
+	slot, err := slots.EpochStart(epoch)
+	require.NoError(t, err)
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	fork, err := forkForEpoch(cfg, epoch)
+	require.NoError(t, err)
+	require.NoError(t, st.SetFork(fork))
+	require.NoError(t, st.SetSlot(slot))
+
+	b, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlock())
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockParentRoot(b, cfg.ZeroHash)
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockSlot(b, slot)
+	require.NoError(t, err)
+	b, err = blocktest.SetProposerIndex(b, 0)
+	require.NoError(t, err)
+
+	// the state root of the latest block header is left empty, as it is in a state which has just processed a block
+	header, err := b.Header()
+	require.NoError(t, err)
+	require.NoError(t, st.SetLatestBlockHeader(header.Header))
+	sr, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockStateRoot(b, sr)
+	require.NoError(t, err)
+	mb, err := b.MarshalSSZ()
+	require.NoError(t, err)
+	br, err := b.Block().HashTreeRoot()
+	require.NoError(t, err)
+	ms, err := st.MarshalSSZ()
+	require.NoError(t, err)
+
+	trans := &testRT{rt: func(req *http.Request) (*http.Response, error) {
+		res := &http.Response{Request: req}
+		switch req.URL.Path {
+		case renderGetStatePath(IdFromSlot(slot)):
+			res.StatusCode = http.StatusOK
+			res.Body = io.NopCloser(bytes.NewBuffer(ms))
+		case renderGetBlockPath(IdFromRoot(br)):
+			res.StatusCode = http.StatusOK
+			res.Body = io.NopCloser(bytes.NewBuffer(mb))
+		case getFinalityCheckpointsTpl(IdHead):
+			res.StatusCode = http.StatusOK
+			res.Body = io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"data":{"finalized":{"epoch":"%d","root":"%#x"}}}`, epoch, br)))
+		default:
+			res.StatusCode = http.StatusInternalServerError
+			res.Body = io.NopCloser(bytes.NewBufferString(""))
+		}
+		return res, nil
+	}}
+	c, err := NewClient("http://localhost:3500", client.WithRoundTripper(trans))
+	require.NoError(t, err)
+
+	cp, err := c.GetFinalizedCheckpoint(ctx, IdHead)
+	require.NoError(t, err)
+	require.Equal(t, epoch, cp.Epoch)
+	require.DeepEqual(t, br[:], cp.Root)
+
+	od, err := DownloadCheckpointData(ctx, c, cp)
+	require.NoError(t, err)
+	require.Equal(t, true, bytes.Equal(ms, od.StateBytes()))
+	require.Equal(t, true, bytes.Equal(mb, od.BlockBytes()))
+	require.Equal(t, br, od.BlockRoot())
+	require.Equal(t, sr, od.sr)
+
+	_, err = DownloadCheckpointData(ctx, c, &zondpb.Checkpoint{Epoch: epoch, Root: cfg.ZeroHash[:]})
+	require.ErrorIs(t, err, errCheckpointBlockMismatch)
+}
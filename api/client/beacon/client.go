@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"text/template"
+	"time"
 
 	"github.com/theQRL/qrysm/v4/api/client"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/shared"
@@ -31,7 +32,9 @@ const (
 	getSignedBlockPath             = "/zond/v2/beacon/blocks"
 	getBlockRootPath               = "/zond/v1/beacon/blocks/{{.Id}}/root"
 	getForkForStatePath            = "/zond/v1/beacon/states/{{.Id}}/fork"
+	getFinalityCheckpointsPath     = "/zond/v1/beacon/states/{{.Id}}/finality_checkpoints"
 	getWeakSubjectivityPath        = "/zond/v1/beacon/weak_subjectivity"
+	getGenesisPath                 = "/zond/v1/beacon/genesis"
 	getForkSchedulePath            = "/zond/v1/config/fork_schedule"
 	getConfigSpecPath              = "/zond/v1/config/spec"
 	getStatePath                   = "/zond/v2/debug/beacon/states"
@@ -159,6 +162,66 @@ func (c *Client) GetFork(ctx context.Context, stateId StateOrBlockId) (*zondpb.F
 	return fr.ToConsensus()
 }
 
+var getFinalityCheckpointsTpl = idTemplate(getFinalityCheckpointsPath)
+
+// GetFinalizedCheckpoint queries the Beacon Node API for the finalized checkpoint of the state identified by stateId.
+// State identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded stateRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetFinalizedCheckpoint(ctx context.Context, stateId StateOrBlockId) (*zondpb.Checkpoint, error) {
+	body, err := c.Get(ctx, getFinalityCheckpointsTpl(stateId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting finality checkpoints by state id = %s", stateId)
+	}
+	resp := &struct {
+		Data struct {
+			Finalized *shared.Checkpoint `json:"finalized"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetFinalizedCheckpoint")
+	}
+	if resp.Data.Finalized == nil {
+		return nil, errors.New("finality checkpoints response has no finalized checkpoint")
+	}
+	return resp.Data.Finalized.ToConsensus()
+}
+
+// Genesis holds the genesis time and genesis validators root of the chain a beacon node follows.
+type Genesis struct {
+	Time                  time.Time
+	GenesisValidatorsRoot [32]byte
+}
+
+// GetGenesis retrieves the genesis time and genesis validators root of the chain.
+func (c *Client) GetGenesis(ctx context.Context) (*Genesis, error) {
+	body, err := c.Get(ctx, getGenesisPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting genesis")
+	}
+	resp := &struct {
+		Data struct {
+			GenesisTime           string `json:"genesis_time"`
+			GenesisValidatorsRoot string `json:"genesis_validators_root"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetGenesis")
+	}
+	t, err := strconv.ParseInt(resp.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid genesis time %s", resp.Data.GenesisTime)
+	}
+	gvr, err := hexutil.Decode(resp.Data.GenesisValidatorsRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid genesis validators root %s", resp.Data.GenesisValidatorsRoot)
+	}
+	if len(gvr) != 32 {
+		return nil, errors.Errorf("genesis validators root has length %d, expected 32", len(gvr))
+	}
+	return &Genesis{Time: time.Unix(t, 0), GenesisValidatorsRoot: bytesutil.ToBytes32(gvr)}, nil
+}
+
 // GetForkSchedule retrieve all forks, past present and future, of which this node is aware.
 func (c *Client) GetForkSchedule(ctx context.Context) (forks.OrderedSchedule, error) {
 	body, err := c.Get(ctx, getForkSchedulePath)
@@ -0,0 +1,104 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/api/client"
+	lightclient "github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/light-client"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+)
+
+const (
+	getLightClientBootstrapPath        = "/zond/v1/beacon/light_client/bootstrap"
+	getLightClientExecutionHeaderPath  = "/zond/v1/beacon/light_client/execution_header"
+	getLightClientUpdatesByRangePath   = "/zond/v1/beacon/light_client/updates"
+	getLightClientFinalityUpdatePath   = "/zond/v1/beacon/light_client/finality_update"
+	getLightClientOptimisticUpdatePath = "/zond/v1/beacon/light_client/optimistic_update"
+)
+
+// GetLightClientBootstrap retrieves the light client bootstrap of the block with the given root, which holds the
+// block header, the current sync committee of its post-state and the proof of that committee.
+func (c *Client) GetLightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*zondpbv2.LightClientBootstrap, error) {
+	body, err := c.Get(ctx, fmt.Sprintf("%s/%#x", getLightClientBootstrapPath, blockRoot))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting light client bootstrap of block root %#x", blockRoot)
+	}
+	resp := &lightclient.LightClientBootstrapResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetLightClientBootstrap")
+	}
+	return resp.Data.ToConsensus()
+}
+
+// GetLightClientExecutionHeader retrieves the execution payload header of the block with the given root, along with
+// the Merkle branch of the payload against the body root of the block.
+func (c *Client) GetLightClientExecutionHeader(ctx context.Context, blockRoot [32]byte) (interfaces.ExecutionData, [][]byte, error) {
+	body, err := c.Get(ctx, fmt.Sprintf("%s/%#x", getLightClientExecutionHeaderPath, blockRoot))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error requesting execution payload header of block root %#x", blockRoot)
+	}
+	resp := &lightclient.LightClientExecutionHeaderResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, nil, errors.Wrap(err, "error decoding json response in GetLightClientExecutionHeader")
+	}
+	return resp.Data.ToConsensus(resp.Version)
+}
+
+// GetLightClientUpdatesByRange retrieves the best light client updates of up to count consecutive sync committee
+// periods, starting at startPeriod.
+func (c *Client) GetLightClientUpdatesByRange(ctx context.Context, startPeriod, count uint64) ([]*zondpbv2.LightClientUpdate, error) {
+	params := url.Values{}
+	params.Set("start_period", strconv.FormatUint(startPeriod, 10))
+	params.Set("count", strconv.FormatUint(count, 10))
+	body, err := c.Get(ctx, getLightClientUpdatesByRangePath, client.WithQueryParams(params))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting light client updates, start period=%d, count=%d", startPeriod, count)
+	}
+	var resp []*lightclient.LightClientUpdateWithVersion
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetLightClientUpdatesByRange")
+	}
+	updates := make([]*zondpbv2.LightClientUpdate, len(resp))
+	for i, u := range resp {
+		if u == nil {
+			return nil, errors.Errorf("nil light client update at index %d", i)
+		}
+		updates[i], err = u.Data.ToConsensus()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode light client update at index %d", i)
+		}
+	}
+	return updates, nil
+}
+
+// GetLightClientFinalityUpdate retrieves the latest light client finality update of the beacon node.
+func (c *Client) GetLightClientFinalityUpdate(ctx context.Context) (*zondpbv2.LightClientFinalityUpdate, error) {
+	body, err := c.Get(ctx, getLightClientFinalityUpdatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting light client finality update")
+	}
+	resp := &lightclient.LightClientFinalityUpdateResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetLightClientFinalityUpdate")
+	}
+	return resp.Data.ToConsensus()
+}
+
+// GetLightClientOptimisticUpdate retrieves the latest light client optimistic update of the beacon node.
+func (c *Client) GetLightClientOptimisticUpdate(ctx context.Context) (*zondpbv2.LightClientOptimisticUpdate, error) {
+	body, err := c.Get(ctx, getLightClientOptimisticUpdatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting light client optimistic update")
+	}
+	resp := &lightclient.LightClientOptimisticUpdateResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetLightClientOptimisticUpdate")
+	}
+	return resp.Data.ToConsensus()
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -23,6 +24,13 @@ func WithAuthorizationToken(token string) ReqOption {
 	}
 }
 
+// WithQueryParams is a request functional option that sets the query string of the request url.
+func WithQueryParams(params url.Values) ReqOption {
+	return func(req *http.Request) {
+		req.URL.RawQuery = params.Encode()
+	}
+}
+
 // ClientOpt is a functional option for the Client type (http.Client wrapper)
 type ClientOpt func(*Client)
 
@@ -17,6 +17,7 @@ import (
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 	"github.com/theQRL/qrysm/v4/time/slots"
 	"go.opencensus.io/trace"
 )
@@ -115,6 +116,12 @@ type OptimisticModeFetcher interface {
 	IsOptimisticForRoot(ctx context.Context, root [32]byte) (bool, error)
 }
 
+// LightClientUpdateFetcher retrieves the latest light client updates produced by the node.
+type LightClientUpdateFetcher interface {
+	LightClientFinalityUpdate() *zondpbv2.LightClientFinalityUpdate
+	LightClientOptimisticUpdate() *zondpbv2.LightClientOptimisticUpdate
+}
+
 // FinalizedCheckpt returns the latest finalized checkpoint from chain store.
 func (s *Service) FinalizedCheckpt() *zondpb.Checkpoint {
 	s.cfg.ForkChoiceStore.RLock()
@@ -25,6 +25,8 @@ var (
 	errWSBlockNotFoundInEpoch = errors.New("weak subjectivity root not found in db within epoch")
 	// ErrNotDescendantOfFinalized is returned when a block is not a descendant of the finalized checkpoint
 	ErrNotDescendantOfFinalized = invalidBlock{error: errors.New("not descendant of finalized checkpoint")}
+	// ErrInvalidSignature is returned when a signature of a block, or of an operation it contains, fails to verify.
+	ErrInvalidSignature = errors.New("signature in block failed to verify")
 	// ErrNotCheckpoint is returned when a given checkpoint is not a
 	// checkpoint in any chain known to forkchoice
 	ErrNotCheckpoint = errors.New("not a checkpoint in forkchoice")
@@ -50,6 +52,11 @@ type invalidBlockError interface {
 	LastValidHash() [32]byte
 }
 
+// Unwrap returns the underlying error, so the cause of an invalid block can be inspected.
+func (e invalidBlock) Unwrap() error {
+	return e.error
+}
+
 // BlockRoot returns the invalid block root.
 func (e invalidBlock) BlockRoot() [32]byte {
 	return e.root
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/testing/require"
 )
 
@@ -42,3 +43,12 @@ func TestInvalidRoots(t *testing.T) {
 	require.Equal(t, [32]byte{'a'}, InvalidBlockRoot(newErr))
 	require.DeepEqual(t, roots, InvalidAncestorRoots(newErr))
 }
+
+func TestInvalidBlock_Unwrap(t *testing.T) {
+	sigErr := &dilithium.InvalidSignaturesError{Invalid: []*dilithium.InvalidSignature{{Description: "block signature"}}}
+	err := errors.Wrap(invalidBlock{error: sigErr}, "wrap me")
+
+	var target *dilithium.InvalidSignaturesError
+	require.Equal(t, true, errors.As(err, &target))
+	require.Equal(t, sigErr, target)
+}
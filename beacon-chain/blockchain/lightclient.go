@@ -244,3 +244,37 @@ func NewLightClientUpdateFromOptimisticUpdate(update *zondpbv2.LightClientOptimi
 		SignatureSlot:  update.SignatureSlot,
 	}
 }
+
+// NewLightClientUpdateFromBeaconState - implements https://github.com/ethereum/consensus-specs/blob/3d235740e5f1e641d3b160c8688f26e7dc5a1894/specs/altair/light-client/full-node.md#create_light_client_update
+// The update extends the finality update of the block with the next sync committee of the attested state and its
+// proof, which is what light clients need to move to the next sync committee period.
+func NewLightClientUpdateFromBeaconState(
+	ctx context.Context,
+	state state.BeaconState,
+	block interfaces.ReadOnlySignedBeaconBlock,
+	attestedState state.BeaconState,
+	finalizedBlock interfaces.ReadOnlySignedBeaconBlock) (*zondpbv2.LightClientUpdate, error) {
+	result, err := NewLightClientFinalityUpdateFromBeaconState(ctx, state, block, attestedState, finalizedBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// update.next_sync_committee = attested_state.next_sync_committee
+	nextSyncCommittee, err := attestedState.NextSyncCommittee()
+	if err != nil {
+		return nil, fmt.Errorf("could not get next sync committee %v", err)
+	}
+
+	// update.next_sync_committee_branch = compute_merkle_proof_for_state(attested_state, NEXT_SYNC_COMMITTEE_INDEX)
+	nextSyncCommitteeBranch, err := attestedState.NextSyncCommitteeProof(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get next sync committee proof %v", err)
+	}
+
+	result.NextSyncCommittee = &zondpbv2.SyncCommittee{
+		Pubkeys:         nextSyncCommittee.Pubkeys,
+		AggregatePubkey: nextSyncCommittee.AggregatePubkey,
+	}
+	result.NextSyncCommitteeBranch = nextSyncCommitteeBranch
+	return result, nil
+}
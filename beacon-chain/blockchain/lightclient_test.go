@@ -3,7 +3,9 @@ package blockchain
 import (
 	"context"
 	"testing"
+	"time"
 
+	p2ptesting "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
@@ -14,6 +16,7 @@ import (
 	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
+	"google.golang.org/protobuf/proto"
 )
 
 type testlc struct {
@@ -158,3 +161,42 @@ func TestLightClient_NewLightClientFinalityUpdateFromBeaconState(t *testing.T) {
 		require.DeepSSZEqual(t, zeroHash, leaf, "Leaf is not zero")
 	}
 }
+
+func TestService_QueueLightClientUpdates(t *testing.T) {
+	s := &Service{lightClientBlocks: make(chan *lightClientBlock, 1)}
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	for slot := primitives.Slot(1); slot <= 2; slot++ {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = slot
+		signed, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		// Queueing does not block once the queue is full.
+		s.queueLightClientUpdates(signed, st)
+	}
+	require.Equal(t, 1, len(s.lightClientBlocks))
+	queued := <-s.lightClientBlocks
+	require.Equal(t, primitives.Slot(1), queued.signed.Block().Slot())
+}
+
+func TestService_BroadcastLightClientUpdates(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.SecondsPerSlot = 3
+	params.OverrideBeaconConfig(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &p2ptesting.MockBroadcaster{}
+	genesis := time.Unix(time.Now().Unix()+1, 0)
+	s := &Service{ctx: ctx, genesisTime: genesis, cfg: &config{P2p: p}}
+
+	// Peers ignore updates received before one third of the signature slot.
+	s.broadcastLightClientUpdates([]proto.Message{&zondpbv2.LightClientOptimisticUpdate{}}, 0)
+	require.Equal(t, false, time.Now().Before(genesis.Add(time.Second)))
+	require.Equal(t, 1, len(p.BroadcastMessages))
+
+	// Updates waiting for their signature slot are dropped when the service stops.
+	cancel()
+	s.broadcastLightClientUpdates([]proto.Message{&zondpbv2.LightClientOptimisticUpdate{}}, 10)
+	require.Equal(t, 1, len(p.BroadcastMessages))
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/theQRL/qrysm/v4/config/params"
 	consensus_types "github.com/theQRL/qrysm/v4/consensus-types"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/runtime/version"
@@ -159,3 +161,18 @@ func logBlobSidecar(scs []*zondpb.BlobSidecar, startTime time.Time) {
 		"validationTime": time.Since(startTime),
 	}).Debug("Synced new blob sidecars")
 }
+
+// logInvalidSignatures logs every invalid signature reported by a verbose signature batch verification
+// of the blocks between the provided slots.
+func logInvalidSignatures(err error, startSlot, endSlot primitives.Slot) {
+	var sigErr *dilithium.InvalidSignaturesError
+	if !errors.As(err, &sigErr) {
+		return
+	}
+	for _, sig := range sigErr.Invalid {
+		log.WithFields(sig.Fields()).WithFields(logrus.Fields{
+			"startSlot": startSlot,
+			"endSlot":   endSlot,
+		}).Warn("Invalid signature in block batch")
+	}
+}
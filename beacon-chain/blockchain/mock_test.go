@@ -15,6 +15,7 @@ func testServiceOptsWithDB(t *testing.T) []Option {
 	cs := startup.NewClockSynchronizer()
 	return []Option{
 		WithDatabase(beaconDB),
+		WithBlobStorage(testDB.SetupBlobStorage(t)),
 		WithStateGen(stategen.New(beaconDB, fcs)),
 		WithForkChoiceStore(fcs),
 		WithClockSynchronizer(cs),
@@ -44,6 +44,14 @@ func WithDatabase(beaconDB db.HeadAccessDatabase) Option {
 	}
 }
 
+// WithBlobStorage to store and read blob sidecars.
+func WithBlobStorage(bs db.BlobStorage) Option {
+	return func(s *Service) error {
+		s.cfg.BlobStorage = bs
+		return nil
+	}
+}
+
 // WithChainStartFetcher to retrieve information about genesis.
 func WithChainStartFetcher(f execution.ChainStartFetcher) Option {
 	return func(s *Service) error {
@@ -76,6 +84,14 @@ func WithProposerIdsCache(c *cache.ProposerPayloadIDsCache) Option {
 	}
 }
 
+// WithVerifiedSignatureCache for skipping the attestation signatures already verified on gossip.
+func WithVerifiedSignatureCache(c *cache.VerifiedSignatureCache) Option {
+	return func(s *Service) error {
+		s.cfg.VerifiedSigCache = c
+		return nil
+	}
+}
+
 // WithAttestationPool for attestation lifecycle after chain inclusion.
 func WithAttestationPool(p attestations.Pool) Option {
 	return func(s *Service) error {
@@ -117,6 +117,9 @@ func (s *Service) postBlockProcess(ctx context.Context, signed interfaces.ReadOn
 
 	defer reportAttestationInclusion(b)
 	if headRoot == blockRoot {
+		if features.Get().EnableLightClient {
+			s.queueLightClientUpdates(signed, postState)
+		}
 		// Updating next slot state cache can happen in the background
 		// except in the epoch boundary in which case we lock to handle
 		// the shuffling and proposer caches updates.
@@ -235,18 +238,10 @@ func (s *Service) onBlockBatch(ctx context.Context, blks []consensusblocks.ROBlo
 		sigSet.Join(set)
 	}
 
-	var verify bool
-	if features.Get().EnableVerboseSigVerification {
-		verify, err = sigSet.VerifyVerbosely()
-	} else {
-		verify, err = sigSet.Verify()
-	}
-	if err != nil {
+	if err := s.verifyBlockSignatures(ctx, sigSet); err != nil {
+		logInvalidSignatures(err, blks[0].Block().Slot(), blks[len(blks)-1].Block().Slot())
 		return invalidBlock{error: err}
 	}
-	if !verify {
-		return errors.New("batch block signature verification failed")
-	}
 
 	// blocks have been verified, save them and call the engine
 	pendingNodes := make([]*forkchoicetypes.BlockAndCheckpoints, len(blks))
@@ -353,7 +348,7 @@ func (s *Service) databaseDACheck(ctx context.Context, b consensusblocks.ROBlock
 	if len(commitments) == 0 {
 		return nil
 	}
-	sidecars, err := s.cfg.BeaconDB.BlobSidecarsByRoot(ctx, b.Root())
+	sidecars, err := s.cfg.BlobStorage.BlobSidecarsByRoot(ctx, b.Root())
 	if err != nil {
 		return errors.Wrap(err, "could not get blob sidecars")
 	}
@@ -558,7 +553,7 @@ func (s *Service) isDataAvailable(ctx context.Context, root [32]byte, signed int
 	}
 
 	// Read first from db in case we have the blobs
-	sidecars, err := s.cfg.BeaconDB.BlobSidecarsByRoot(ctx, root)
+	sidecars, err := s.cfg.BlobStorage.BlobSidecarsByRoot(ctx, root)
 	switch {
 	case err == nil:
 		if len(sidecars) >= expected {
@@ -589,7 +584,7 @@ func (s *Service) isDataAvailable(ctx context.Context, root [32]byte, signed int
 				continue
 			}
 			s.blobNotifiers.delete(root)
-			sidecars, err := s.cfg.BeaconDB.BlobSidecarsByRoot(ctx, root)
+			sidecars, err := s.cfg.BlobStorage.BlobSidecarsByRoot(ctx, root)
 			if err != nil {
 				return errors.Wrap(err, "could not get blob sidecars")
 			}
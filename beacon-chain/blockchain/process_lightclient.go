@@ -0,0 +1,226 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/feed"
+	statefeed "github.com/theQRL/qrysm/v4/beacon-chain/core/feed/state"
+	lightclient "github.com/theQRL/qrysm/v4/beacon-chain/core/light-client"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	"go.opencensus.io/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// LightClientFinalityUpdate returns the light client finality update with the highest finalized header produced
+// by the node, or nil if the node has not produced one yet.
+func (s *Service) LightClientFinalityUpdate() *zondpbv2.LightClientFinalityUpdate {
+	s.lightClientLock.RLock()
+	defer s.lightClientLock.RUnlock()
+	return s.lightClientFinalityUpdate
+}
+
+// LightClientOptimisticUpdate returns the light client optimistic update with the highest attested header produced
+// by the node, or nil if the node has not produced one yet.
+func (s *Service) LightClientOptimisticUpdate() *zondpbv2.LightClientOptimisticUpdate {
+	s.lightClientLock.RLock()
+	defer s.lightClientLock.RUnlock()
+	return s.lightClientOptimisticUpdate
+}
+
+// lightClientQueueSize is the number of canonical blocks which may wait for their light client updates to be
+// processed. Blocks arriving while the queue is full are skipped.
+const lightClientQueueSize = 8
+
+// lightClientBlock is a canonical block waiting for its light client updates to be processed.
+type lightClientBlock struct {
+	signed    interfaces.ReadOnlySignedBeaconBlock
+	postState state.BeaconState
+}
+
+// queueLightClientUpdates queues a canonical block for runLightClientUpdates without blocking the caller.
+func (s *Service) queueLightClientUpdates(signed interfaces.ReadOnlySignedBeaconBlock, postState state.BeaconState) {
+	select {
+	case s.lightClientBlocks <- &lightClientBlock{signed: signed, postState: postState}:
+	default:
+		log.WithField("slot", signed.Block().Slot()).Debug("Light client update queue is full, skipping block")
+	}
+}
+
+// runLightClientUpdates processes the light client updates of the queued blocks one at a time, until the service
+// is stopped.
+func (s *Service) runLightClientUpdates() {
+	for {
+		select {
+		case b := <-s.lightClientBlocks:
+			ctx, cancel := context.WithTimeout(s.ctx, slotDeadline)
+			if err := s.processLightClientUpdates(ctx, b.signed, b.postState); err != nil {
+				log.WithError(err).Error("Could not process light client updates")
+			}
+			cancel()
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting routine")
+			return
+		}
+	}
+}
+
+// processLightClientUpdates produces the light client update signed by the sync aggregate of a canonical block.
+// Finality and optimistic updates which are newer than the ones the node produced before are cached, sent to the
+// state feed and broadcast, and the update is saved if it is the best update of its sync committee period.
+// It is only called by runLightClientUpdates, so the best update of a period is not replaced concurrently between
+// reading and saving it.
+func (s *Service) processLightClientUpdates(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock, postState state.BeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "blockChain.processLightClientUpdates")
+	defer span.End()
+
+	if signed.Version() < version.Altair {
+		return nil
+	}
+	syncAggregate, err := signed.Block().Body().SyncAggregate()
+	if err != nil {
+		return errors.Wrap(err, "could not get sync aggregate")
+	}
+	if syncAggregate.SyncCommitteeBits.Count() < params.BeaconConfig().MinSyncCommitteeParticipants {
+		return nil
+	}
+	parentRoot := signed.Block().ParentRoot()
+	attestedState, err := s.cfg.StateGen.StateByRoot(ctx, parentRoot)
+	if err != nil {
+		return errors.Wrapf(err, "could not get attested state, root=%#x", parentRoot)
+	}
+	if slots.ToEpoch(attestedState.Slot()) < params.BeaconConfig().AltairForkEpoch {
+		return nil
+	}
+	finalizedBlock, err := s.lightClientFinalizedBlock(ctx, attestedState)
+	if err != nil {
+		return err
+	}
+	update, err := NewLightClientUpdateFromBeaconState(ctx, postState, signed, attestedState, finalizedBlock)
+	if err != nil {
+		return errors.Wrap(err, "could not create light client update")
+	}
+	s.sendLightClientUpdates(update, signed.Version())
+
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(update.AttestedHeader.Slot))
+	best, err := s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	if err != nil {
+		return errors.Wrapf(err, "could not get light client update of period %d", period)
+	}
+	if best != nil && !lightclient.IsBetterUpdate(update, best) {
+		return nil
+	}
+	return s.cfg.BeaconDB.SaveLightClientUpdate(ctx, period, update)
+}
+
+// lightClientFinalizedBlock returns the block of the finalized checkpoint of the attested state. The genesis block
+// stands for the zero root of a chain which has not finalized yet.
+func (s *Service) lightClientFinalizedBlock(ctx context.Context, attestedState state.BeaconState) (interfaces.ReadOnlySignedBeaconBlock, error) {
+	root := bytesutil.ToBytes32(attestedState.FinalizedCheckpoint().Root)
+	if root == params.BeaconConfig().ZeroHash {
+		b, err := s.cfg.BeaconDB.GenesisBlock(ctx)
+		return b, errors.Wrap(err, "could not get genesis block")
+	}
+	b, err := s.cfg.BeaconDB.Block(ctx, root)
+	return b, errors.Wrapf(err, "could not get finalized block, root=%#x", root)
+}
+
+// sendLightClientUpdates caches, sends to the state feed and broadcasts the finality and optimistic updates derived
+// from the update, if they are newer than the ones previously produced. The updates are broadcast in the background,
+// once peers accept them.
+func (s *Service) sendLightClientUpdates(update *zondpbv2.LightClientUpdate, v int) {
+	finalityUpdate := CreateLightClientFinalityUpdate(update)
+	optimisticUpdate := CreateLightClientOptimisticUpdate(update)
+
+	s.lightClientLock.Lock()
+	newFinality := update.IsFinalityUpdate() && isNewerLightClientFinalityUpdate(finalityUpdate, s.lightClientFinalityUpdate)
+	if newFinality {
+		s.lightClientFinalityUpdate = finalityUpdate
+	}
+	newOptimistic := s.lightClientOptimisticUpdate == nil || optimisticUpdate.AttestedHeader.Slot > s.lightClientOptimisticUpdate.AttestedHeader.Slot
+	if newOptimistic {
+		s.lightClientOptimisticUpdate = optimisticUpdate
+	}
+	s.lightClientLock.Unlock()
+
+	var msgs []proto.Message
+	if newFinality {
+		s.cfg.StateNotifier.StateFeed().Send(&feed.Event{
+			Type: statefeed.LightClientFinalityUpdate,
+			Data: &zondpbv2.LightClientFinalityUpdateWithVersion{
+				Version: lightClientUpdateVersion(v),
+				Data:    finalityUpdate,
+			},
+		})
+		msgs = append(msgs, finalityUpdate)
+	}
+	if newOptimistic {
+		s.cfg.StateNotifier.StateFeed().Send(&feed.Event{
+			Type: statefeed.LightClientOptimisticUpdate,
+			Data: &zondpbv2.LightClientOptimisticUpdateWithVersion{
+				Version: lightClientUpdateVersion(v),
+				Data:    optimisticUpdate,
+			},
+		})
+		msgs = append(msgs, optimisticUpdate)
+	}
+	if len(msgs) > 0 {
+		go s.broadcastLightClientUpdates(msgs, update.SignatureSlot)
+	}
+}
+
+// broadcastLightClientUpdates broadcasts light client updates once one third of their signature slot has passed.
+// Peers ignore updates received earlier, and would not accept them again once pubsub has seen them.
+func (s *Service) broadcastLightClientUpdates(msgs []proto.Message, signatureSlot primitives.Slot) {
+	due := slots.StartTime(uint64(s.genesisTime.Unix()), signatureSlot).Add(time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second / 3)
+	if d := time.Until(due); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+	for _, msg := range msgs {
+		if err := s.cfg.P2p.Broadcast(s.ctx, msg); err != nil {
+			log.WithError(err).Debug("Could not broadcast light client update")
+		}
+	}
+}
+
+// isNewerLightClientFinalityUpdate returns true if the update has a higher finalized header than the previous
+// update, or the same finalized header with a supermajority of the sync committee which the previous update lacks.
+func isNewerLightClientFinalityUpdate(update, previous *zondpbv2.LightClientFinalityUpdate) bool {
+	if previous == nil || update.FinalizedHeader.Slot > previous.FinalizedHeader.Slot {
+		return true
+	}
+	if update.FinalizedHeader.Slot < previous.FinalizedHeader.Slot {
+		return false
+	}
+	return lightclient.HasSupermajority(update.SyncAggregate.SyncCommitteeBits.Count(), update.SyncAggregate.SyncCommitteeBits.Len()) &&
+		!lightclient.HasSupermajority(previous.SyncAggregate.SyncCommitteeBits.Count(), previous.SyncAggregate.SyncCommitteeBits.Len())
+}
+
+func lightClientUpdateVersion(v int) zondpbv2.Version {
+	switch v {
+	case version.Altair:
+		return zondpbv2.Version_ALTAIR
+	case version.Bellatrix:
+		return zondpbv2.Version_BELLATRIX
+	case version.Capella:
+		return zondpbv2.Version_CAPELLA
+	case version.Deneb:
+		return zondpbv2.Version_DENEB
+	default:
+		return zondpbv2.Version_PHASE0
+	}
+}
@@ -14,7 +14,7 @@ func (s *Service) sendNewBlobEvent(root [32]byte, index uint64) {
 
 // ReceiveBlob saves the blob to database and sends the new event
 func (s *Service) ReceiveBlob(ctx context.Context, b *zondpb.BlobSidecar) error {
-	if err := s.cfg.BeaconDB.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{b}); err != nil {
+	if err := s.cfg.BlobStorage.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{b}); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/monitoring/tracing"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
@@ -344,14 +345,43 @@ func (s *Service) validateStateTransition(ctx context.Context, preState state.Be
 		return nil, ErrNotDescendantOfFinalized
 	}
 	stateTransitionStartTime := time.Now()
-	postState, err := transition.ExecuteStateTransition(ctx, preState, signed)
+	set, postState, err := transition.ExecuteStateTransitionNoVerifyAnySig(ctx, preState, signed)
 	if err != nil {
+		return nil, invalidBlock{error: errors.Wrap(err, "could not execute state transition")}
+	}
+	if err := s.verifyBlockSignatures(ctx, set); err != nil {
 		return nil, invalidBlock{error: err}
 	}
 	stateTransitionProcessingTime.Observe(float64(time.Since(stateTransitionStartTime).Milliseconds()))
 	return postState, nil
 }
 
+// verifyBlockSignatures verifies the signatures of one or more blocks, leaving out the attestation signatures
+// which were already verified on gossip. The invalid signatures are located, and reported as a
+// *dilithium.InvalidSignaturesError, when the verbose signature verification is enabled.
+func (s *Service) verifyBlockSignatures(ctx context.Context, set *dilithium.SignatureBatch) error {
+	set = s.cfg.VerifiedSigCache.FilterBatch(set)
+	if len(set.Signatures) == 0 {
+		return nil
+	}
+	var valid bool
+	var err error
+	start := time.Now()
+	if features.Get().EnableVerboseSigVerification {
+		valid, err = set.VerifyVerboselyWithContext(ctx)
+	} else {
+		valid, err = set.VerifyWithContext(ctx)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not batch verify signature")
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	s.cfg.VerifiedSigCache.RecordVerificationTime(time.Since(start), set)
+	return nil
+}
+
 // updateJustificationOnBlock updates the justified checkpoint on DB if the
 // incoming block has updated it on forkchoice.
 func (s *Service) updateJustificationOnBlock(ctx context.Context, preState, postState state.BeaconState, preJustifiedEpoch primitives.Epoch) error {
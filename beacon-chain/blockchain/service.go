@@ -38,6 +38,7 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 	prysmTime "github.com/theQRL/qrysm/v4/time"
 	"github.com/theQRL/qrysm/v4/time/slots"
 	"go.opencensus.io/trace"
@@ -63,6 +64,11 @@ type Service struct {
 	syncComplete         chan struct{}
 	blobNotifiers        *blobNotifierMap
 	blockBeingSynced     *currentlySyncingBlock
+	// latest light client updates, only produced when the light client server is enabled
+	lightClientLock             sync.RWMutex
+	lightClientFinalityUpdate   *zondpbv2.LightClientFinalityUpdate
+	lightClientOptimisticUpdate *zondpbv2.LightClientOptimisticUpdate
+	lightClientBlocks           chan *lightClientBlock
 }
 
 // config options for the service.
@@ -70,8 +76,10 @@ type config struct {
 	BeaconBlockBuf          int
 	ChainStartFetcher       execution.ChainStartFetcher
 	BeaconDB                db.HeadAccessDatabase
+	BlobStorage             db.BlobStorage
 	DepositCache            cache.DepositCache
 	ProposerSlotIndexCache  *cache.ProposerPayloadIDsCache
+	VerifiedSigCache        *cache.VerifiedSignatureCache
 	AttPool                 attestations.Pool
 	ExitPool                voluntaryexits.PoolManager
 	SlashingPool            slashings.PoolManager
@@ -136,6 +144,7 @@ func NewService(ctx context.Context, opts ...Option) (*Service, error) {
 		blobNotifiers:        bn,
 		cfg:                  &config{ProposerSlotIndexCache: cache.NewProposerPayloadIDsCache()},
 		blockBeingSynced:     &currentlySyncingBlock{roots: make(map[[32]byte]struct{})},
+		lightClientBlocks:    make(chan *lightClientBlock, lightClientQueueSize),
 	}
 	for _, opt := range opts {
 		if err := opt(srv); err != nil {
@@ -167,6 +176,9 @@ func (s *Service) Start() {
 	}
 	s.spawnProcessAttestationsRoutine()
 	go s.runLateBlockTasks()
+	if features.Get().EnableLightClient {
+		go s.runLightClientUpdates()
+	}
 }
 
 // Stop the blockchain service's main event loop and associated goroutines.
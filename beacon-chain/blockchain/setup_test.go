@@ -102,6 +102,7 @@ func minimalTestService(t *testing.T, opts ...Option) (*Service, *testServiceReq
 		dc:            dc,
 	}
 	defOpts := []Option{WithDatabase(req.db),
+		WithBlobStorage(testDB.SetupBlobStorage(t)),
 		WithStateNotifier(req.notif),
 		WithStateGen(req.sg),
 		WithForkChoiceStore(req.fcs),
@@ -30,6 +30,7 @@ import (
 	enginev1 "github.com/theQRL/qrysm/v4/proto/engine/v1"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 )
 
 var ErrNilState = errors.New("nil state")
@@ -72,6 +73,8 @@ type ChainService struct {
 	OptimisticRoots             map[[32]byte]bool
 	BlockSlot                   primitives.Slot
 	SyncingRoot                 [32]byte
+	LCFinalityUpdate            *zondpbv2.LightClientFinalityUpdate
+	LCOptimisticUpdate          *zondpbv2.LightClientOptimisticUpdate
 }
 
 func (s *ChainService) Ancestor(ctx context.Context, root []byte, slot primitives.Slot) ([]byte, error) {
@@ -615,3 +618,13 @@ func (c *ChainService) BlockBeingSynced(root [32]byte) bool {
 func (*ChainService) ReceiveBlob(_ context.Context, _ *zondpb.BlobSidecar) error {
 	return nil
 }
+
+// LightClientFinalityUpdate mocks the same method in the chain service.
+func (s *ChainService) LightClientFinalityUpdate() *zondpbv2.LightClientFinalityUpdate {
+	return s.LCFinalityUpdate
+}
+
+// LightClientOptimisticUpdate mocks the same method in the chain service.
+func (s *ChainService) LightClientOptimisticUpdate() *zondpbv2.LightClientOptimisticUpdate {
+	return s.LCOptimisticUpdate
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	lruwrpr "github.com/theQRL/qrysm/v4/cache/lru"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/hash"
+)
+
+// maxVerifiedSignatureCacheSize defines the max number of (signing root, public key, signature)
+// triples the verified signature cache can store. Each entry only holds a 32 byte digest.
+const maxVerifiedSignatureCacheSize = 1 << 17
+
+var (
+	verifiedSignatureCacheHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "verified_signature_cache_hit",
+		Help: "The number of signature verifications skipped because the signature was already verified.",
+	})
+	verifiedSignatureCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "verified_signature_cache_miss",
+		Help: "The number of signatures not found in the verified signature cache.",
+	})
+	verifiedSignatureCacheSavedSeconds = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "verified_signature_cache_saved_cpu_seconds",
+		Help: "The estimated CPU time saved by skipping already verified signatures.",
+	})
+)
+
+// VerifiedSignatureCache records (signing root, public key, signature) triples which
+// were successfully verified, so the same signature is not verified more than once.
+// The signing root commits to the signature domain, which includes the fork version
+// and genesis validators root, so entries never match across forks.
+type VerifiedSignatureCache struct {
+	cache *lru.Cache
+	// verifyCost is a moving average of the time spent to verify a single signature, in nanoseconds.
+	verifyCost atomic.Int64
+}
+
+// NewVerifiedSignatureCache creates a new verified signature cache.
+func NewVerifiedSignatureCache() *VerifiedSignatureCache {
+	return &VerifiedSignatureCache{
+		cache: lruwrpr.New(maxVerifiedSignatureCacheSize),
+	}
+}
+
+// Has returns true if the signature of the public key over the signing root was already verified.
+func (c *VerifiedSignatureCache) Has(root [32]byte, pubKey, sig []byte) bool {
+	if c.cache.Contains(verifiedSignatureKey(root, pubKey, sig)) {
+		verifiedSignatureCacheHit.Inc()
+		verifiedSignatureCacheSavedSeconds.Add(time.Duration(c.verifyCost.Load()).Seconds())
+		return true
+	}
+	verifiedSignatureCacheMiss.Inc()
+	return false
+}
+
+// Add records the signature of the public key over the signing root as verified.
+func (c *VerifiedSignatureCache) Add(root [32]byte, pubKey, sig []byte) {
+	c.cache.Add(verifiedSignatureKey(root, pubKey, sig), true)
+}
+
+// AddBatch records every signature in the provided batch as verified. Callers must
+// only pass batches which were successfully verified. A nil cache ignores the batch.
+func (c *VerifiedSignatureCache) AddBatch(set *dilithium.SignatureBatch) {
+	if c == nil || set == nil || len(set.Signatures) != len(set.PublicKeys) || len(set.Signatures) != len(set.Messages) {
+		return
+	}
+	sigLen := dilithium.SignatureLength()
+	for i, sig := range set.Signatures {
+		if len(sig) != len(set.PublicKeys[i])*sigLen {
+			continue
+		}
+		for j, pubKey := range set.PublicKeys[i] {
+			offset := j * sigLen
+			c.Add(set.Messages[i], pubKey.Marshal(), sig[offset:offset+sigLen])
+		}
+	}
+}
+
+// FilterBatch returns a copy of the batch without the signatures which were already verified. Entries
+// whose signatures were all verified are left out. Malformed entries are kept as they are, for the
+// verifier to reject them. A nil cache returns the batch unchanged.
+func (c *VerifiedSignatureCache) FilterBatch(set *dilithium.SignatureBatch) *dilithium.SignatureBatch {
+	if c == nil || set == nil || len(set.Signatures) != len(set.PublicKeys) || len(set.Signatures) != len(set.Messages) {
+		return set
+	}
+	withIndices := len(set.ValidatorIndices) == len(set.Signatures)
+	filtered := &dilithium.SignatureBatch{
+		Signatures:   make([][]byte, 0, len(set.Signatures)),
+		PublicKeys:   make([][]dilithium.PublicKey, 0, len(set.PublicKeys)),
+		Messages:     make([][32]byte, 0, len(set.Messages)),
+		Descriptions: make([]string, 0, len(set.Descriptions)),
+	}
+	sigLen := dilithium.SignatureLength()
+	for i, sig := range set.Signatures {
+		pubKeys := set.PublicKeys[i]
+		var indices []primitives.ValidatorIndex
+		if withIndices {
+			indices = set.ValidatorIndices[i]
+		}
+		if len(sig) == len(pubKeys)*sigLen && len(pubKeys) > 0 {
+			keptSig := make([]byte, 0, len(sig))
+			keptKeys := make([]dilithium.PublicKey, 0, len(pubKeys))
+			var keptIndices []primitives.ValidatorIndex
+			for j, pubKey := range pubKeys {
+				sigAtIdx := sig[j*sigLen : (j+1)*sigLen]
+				if c.Has(set.Messages[i], pubKey.Marshal(), sigAtIdx) {
+					continue
+				}
+				keptSig = append(keptSig, sigAtIdx...)
+				keptKeys = append(keptKeys, pubKey)
+				if j < len(indices) {
+					keptIndices = append(keptIndices, indices[j])
+				}
+			}
+			if len(keptKeys) == 0 {
+				continue
+			}
+			sig, pubKeys, indices = keptSig, keptKeys, keptIndices
+		}
+		filtered.Signatures = append(filtered.Signatures, sig)
+		filtered.PublicKeys = append(filtered.PublicKeys, pubKeys)
+		filtered.Messages = append(filtered.Messages, set.Messages[i])
+		if i < len(set.Descriptions) {
+			filtered.Descriptions = append(filtered.Descriptions, set.Descriptions[i])
+		}
+		if withIndices {
+			filtered.ValidatorIndices = append(filtered.ValidatorIndices, indices)
+		}
+	}
+	return filtered
+}
+
+// RecordVerificationTime updates the estimated time spent to verify a single signature from the
+// time spent to verify the provided batch, which is used to report the CPU time saved on cache hits.
+func (c *VerifiedSignatureCache) RecordVerificationTime(d time.Duration, set *dilithium.SignatureBatch) {
+	if c == nil || set == nil {
+		return
+	}
+	numSignatures := 0
+	for _, pubKeys := range set.PublicKeys {
+		numSignatures += len(pubKeys)
+	}
+	if numSignatures == 0 {
+		return
+	}
+	perSignature := int64(d) / int64(numSignatures)
+	prev := c.verifyCost.Load()
+	if prev == 0 {
+		c.verifyCost.Store(perSignature)
+		return
+	}
+	// Exponential moving average with a weight of 1/8 for the new sample.
+	c.verifyCost.Store(prev + (perSignature-prev)/8)
+}
+
+// Clear removes all entries from the cache.
+func (c *VerifiedSignatureCache) Clear() {
+	c.cache.Purge()
+}
+
+func verifiedSignatureKey(root [32]byte, pubKey, sig []byte) [32]byte {
+	b := make([]byte, 0, len(root)+len(pubKey)+len(sig))
+	b = append(b, root[:]...)
+	b = append(b, pubKey...)
+	b = append(b, sig...)
+	return hash.Hash(b)
+}
@@ -0,0 +1,99 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/cache"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestVerifiedSignatureCache_AddBatch(t *testing.T) {
+	key1, err := dilithium.RandKey()
+	require.NoError(t, err)
+	key2, err := dilithium.RandKey()
+	require.NoError(t, err)
+	root := [32]byte{'a'}
+	sig1 := key1.Sign(root[:])
+	sig2 := key2.Sign(root[:])
+
+	c := cache.NewVerifiedSignatureCache()
+	assert.Equal(t, false, c.Has(root, key1.PublicKey().Marshal(), sig1.Marshal()))
+
+	c.AddBatch(&dilithium.SignatureBatch{
+		Signatures:   [][]byte{dilithium.UnaggregatedSignatures([]dilithium.Signature{sig1, sig2})},
+		PublicKeys:   [][]dilithium.PublicKey{{key1.PublicKey(), key2.PublicKey()}},
+		Messages:     [][32]byte{root},
+		Descriptions: []string{"test"},
+	})
+	assert.Equal(t, true, c.Has(root, key1.PublicKey().Marshal(), sig1.Marshal()))
+	assert.Equal(t, true, c.Has(root, key2.PublicKey().Marshal(), sig2.Marshal()))
+
+	// Any difference in the signing root, public key or signature must miss.
+	assert.Equal(t, false, c.Has([32]byte{'b'}, key1.PublicKey().Marshal(), sig1.Marshal()))
+	assert.Equal(t, false, c.Has(root, key2.PublicKey().Marshal(), sig1.Marshal()))
+	assert.Equal(t, false, c.Has(root, key1.PublicKey().Marshal(), sig2.Marshal()))
+
+	c.Clear()
+	assert.Equal(t, false, c.Has(root, key1.PublicKey().Marshal(), sig1.Marshal()))
+}
+
+func TestVerifiedSignatureCache_AddBatchIgnoresMalformedSets(t *testing.T) {
+	key, err := dilithium.RandKey()
+	require.NoError(t, err)
+	root := [32]byte{'a'}
+	sig := key.Sign(root[:]).Marshal()
+
+	c := cache.NewVerifiedSignatureCache()
+	c.AddBatch(nil)
+	c.AddBatch(&dilithium.SignatureBatch{
+		Signatures: [][]byte{sig[:len(sig)-1]},
+		PublicKeys: [][]dilithium.PublicKey{{key.PublicKey()}},
+		Messages:   [][32]byte{root},
+	})
+	assert.Equal(t, false, c.Has(root, key.PublicKey().Marshal(), sig))
+}
+
+func TestVerifiedSignatureCache_FilterBatch(t *testing.T) {
+	key1, err := dilithium.RandKey()
+	require.NoError(t, err)
+	key2, err := dilithium.RandKey()
+	require.NoError(t, err)
+	root := [32]byte{'a'}
+	sig1 := key1.Sign(root[:])
+	sig2 := key2.Sign(root[:])
+	set := &dilithium.SignatureBatch{
+		Signatures:       [][]byte{dilithium.UnaggregatedSignatures([]dilithium.Signature{sig1, sig2})},
+		PublicKeys:       [][]dilithium.PublicKey{{key1.PublicKey(), key2.PublicKey()}},
+		Messages:         [][32]byte{root},
+		Descriptions:     []string{"test"},
+		ValidatorIndices: [][]primitives.ValidatorIndex{{1, 2}},
+	}
+
+	var nilCache *cache.VerifiedSignatureCache
+	assert.Equal(t, set, nilCache.FilterBatch(set))
+
+	c := cache.NewVerifiedSignatureCache()
+	assert.DeepEqual(t, set, c.FilterBatch(set))
+
+	// Only the signature of the second key is left to verify.
+	c.Add(root, key1.PublicKey().Marshal(), sig1.Marshal())
+	filtered := c.FilterBatch(set)
+	require.Equal(t, 1, len(filtered.Signatures))
+	assert.DeepEqual(t, sig2.Marshal(), filtered.Signatures[0])
+	assert.DeepEqual(t, []dilithium.PublicKey{key2.PublicKey()}, filtered.PublicKeys[0])
+	assert.DeepEqual(t, [][]primitives.ValidatorIndex{{2}}, filtered.ValidatorIndices)
+	verified, err := filtered.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+	// The provided batch is left untouched.
+	assert.Equal(t, 2, len(set.PublicKeys[0]))
+
+	// Once every signature is verified, the entry is left out of the batch.
+	c.AddBatch(filtered)
+	filtered = c.FilterBatch(set)
+	assert.Equal(t, 0, len(filtered.Signatures))
+	assert.Equal(t, 0, len(filtered.ValidatorIndices))
+}
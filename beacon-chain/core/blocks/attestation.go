@@ -13,7 +13,7 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	"github.com/theQRL/qrysm/v4/runtime/version"
@@ -220,12 +220,11 @@ func VerifyIndexedAttestation(ctx context.Context, beaconState state.ReadOnlyBea
 		return err
 	}
 	indices := indexedAtt.AttestingIndices
-	var pubkeys []bls.PublicKey
+	pubkeys := make([]dilithium.PublicKey, 0, len(indices))
 	for i := 0; i < len(indices); i++ {
-		pubkeyAtIdx := beaconState.PubkeyAtIndex(primitives.ValidatorIndex(indices[i]))
-		pk, err := bls.PublicKeyFromBytes(pubkeyAtIdx[:])
+		pk, err := beaconState.PublicKeyAtIndex(primitives.ValidatorIndex(indices[i]))
 		if err != nil {
-			return errors.Wrap(err, "could not deserialize validator public key")
+			return errors.Wrap(err, "could not get validator public key")
 		}
 		pubkeys = append(pubkeys, pk)
 	}
@@ -36,13 +36,13 @@ func ProcessRandao(
 		return nil, err
 	}
 	body := b.Block().Body()
-	buf, proposerPub, domain, err := randaoSigningData(ctx, beaconState)
+	buf, _, proposerPub, domain, err := randaoSigningData(ctx, beaconState)
 	if err != nil {
 		return nil, err
 	}
 
 	randaoReveal := body.RandaoReveal()
-	if err := verifySignature(buf, proposerPub, randaoReveal[:], domain); err != nil {
+	if err := verifySignatureWithKey(buf, proposerPub, randaoReveal[:], domain); err != nil {
 		return nil, errors.Wrap(err, "could not verify block randao")
 	}
 
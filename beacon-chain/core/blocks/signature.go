@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 
 	"github.com/pkg/errors"
-	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
@@ -25,6 +24,11 @@ func signatureBatch(signedData, pub, signature, domain []byte, desc string) (*di
 	if err != nil {
 		return nil, errors.Wrap(err, "could not convert bytes to public key")
 	}
+	return signatureBatchWithKey(signedData, publicKey, signature, domain, desc)
+}
+
+// retrieves the signature batch from the raw data, parsed public key, signature and domain provided.
+func signatureBatchWithKey(signedData []byte, publicKey dilithium.PublicKey, signature, domain []byte, desc string) (*dilithium.SignatureBatch, error) {
 	signingData := &zondpb.SigningData{
 		ObjectRoot: signedData,
 		Domain:     domain,
@@ -47,10 +51,24 @@ func verifySignature(signedData, pub, signature, domain []byte) error {
 	if err != nil {
 		return err
 	}
+	return verifySignatureBatch(set)
+}
+
+// verifies the signature from the raw data, parsed public key and domain provided.
+func verifySignatureWithKey(signedData []byte, publicKey dilithium.PublicKey, signature, domain []byte) error {
+	set, err := signatureBatchWithKey(signedData, publicKey, signature, domain, signing.UnknownSignature)
+	if err != nil {
+		return err
+	}
+	return verifySignatureBatch(set)
+}
+
+// verifies the signatures of a batch holding a single signed message.
+func verifySignatureBatch(set *dilithium.SignatureBatch) error {
 	if len(set.Signatures) != 1 {
 		return errors.Errorf("signature set contains %d signatures instead of 1", len(set.Signatures))
 	}
-	totalSigsLen := len(set.PublicKeys[0]) * dilithium2.CryptoBytes
+	totalSigsLen := len(set.PublicKeys[0]) * dilithium.SignatureLength()
 	if totalSigsLen != len(set.Signatures[0]) {
 		return errors.Errorf("signature set length is %d instead of %d", len(set.Signatures[0]), totalSigsLen)
 	}
@@ -59,14 +77,14 @@ func verifySignature(signedData, pub, signature, domain []byte) error {
 	sigOffset := 0
 	for _, publicKey := range set.PublicKeys[0] {
 		root := set.Messages[0]
-		rSig, err := dilithium.SignatureFromBytes(sig[sigOffset : sigOffset+dilithium2.CryptoBytes])
+		rSig, err := dilithium.SignatureFromBytes(sig[sigOffset : sigOffset+dilithium.SignatureLength()])
 		if err != nil {
 			return err
 		}
 		if !rSig.Verify(publicKey, root[:]) {
 			return signing.ErrSigFailedToVerify
 		}
-		sigOffset += dilithium2.CryptoBytes
+		sigOffset += dilithium.SignatureLength()
 	}
 	return nil
 }
@@ -81,12 +99,11 @@ func VerifyBlockSignature(beaconState state.ReadOnlyBeaconState,
 	if err != nil {
 		return err
 	}
-	proposer, err := beaconState.ValidatorAtIndex(proposerIndex)
+	proposerPubKey, err := beaconState.PublicKeyAtIndex(proposerIndex)
 	if err != nil {
 		return err
 	}
-	proposerPubKey := proposer.PublicKey
-	return signing.VerifyBlockSigningRoot(proposerPubKey, sig, domain, rootFunc)
+	return signing.VerifyBlockSigningRootWithKey(proposerPubKey, sig, domain, rootFunc)
 }
 
 // VerifyBlockHeaderSignature verifies the proposer signature of a beacon block header.
@@ -96,12 +113,11 @@ func VerifyBlockHeaderSignature(beaconState state.BeaconState, header *zondpb.Si
 	if err != nil {
 		return err
 	}
-	proposer, err := beaconState.ValidatorAtIndex(header.Header.ProposerIndex)
+	proposerPubKey, err := beaconState.PublicKeyAtIndex(header.Header.ProposerIndex)
 	if err != nil {
 		return err
 	}
-	proposerPubKey := proposer.PublicKey
-	return signing.VerifyBlockHeaderSigningRoot(header.Header, proposerPubKey, header.Signature, domain)
+	return signing.VerifyBlockHeaderSigningRootWithKey(header.Header, proposerPubKey, header.Signature, domain)
 }
 
 // VerifyBlockSignatureUsingCurrentFork verifies the proposer signature of a beacon block. This differs
@@ -117,13 +133,12 @@ func VerifyBlockSignatureUsingCurrentFork(beaconState state.ReadOnlyBeaconState,
 	if err != nil {
 		return err
 	}
-	proposer, err := beaconState.ValidatorAtIndex(blk.Block().ProposerIndex())
+	proposerPubKey, err := beaconState.PublicKeyAtIndex(blk.Block().ProposerIndex())
 	if err != nil {
 		return err
 	}
-	proposerPubKey := proposer.PublicKey
 	sig := blk.Signature()
-	return signing.VerifyBlockSigningRoot(proposerPubKey, sig[:], domain, blk.Block().HashTreeRoot)
+	return signing.VerifyBlockSigningRootWithKey(proposerPubKey, sig[:], domain, blk.Block().HashTreeRoot)
 }
 
 // BlockSignatureBatch retrieves the block signature batch from the provided block and its corresponding state.
@@ -136,12 +151,16 @@ func BlockSignatureBatch(beaconState state.ReadOnlyBeaconState,
 	if err != nil {
 		return nil, err
 	}
-	proposer, err := beaconState.ValidatorAtIndex(proposerIndex)
+	proposerPubKey, err := beaconState.PublicKeyAtIndex(proposerIndex)
 	if err != nil {
 		return nil, err
 	}
-	proposerPubKey := proposer.PublicKey
-	return signing.BlockSignatureBatch(proposerPubKey, sig, domain, rootFunc)
+	set, err := signing.BlockSignatureBatchWithKey(proposerPubKey, sig, domain, rootFunc)
+	if err != nil {
+		return nil, err
+	}
+	set.ValidatorIndices = [][]primitives.ValidatorIndex{{proposerIndex}}
+	return set, nil
 }
 
 // RandaoSignatureBatch retrieves the relevant randao specific signature batch object
@@ -151,24 +170,28 @@ func RandaoSignatureBatch(
 	beaconState state.ReadOnlyBeaconState,
 	reveal []byte,
 ) (*dilithium.SignatureBatch, error) {
-	buf, proposerPub, domain, err := randaoSigningData(ctx, beaconState)
+	buf, proposerIdx, proposerPub, domain, err := randaoSigningData(ctx, beaconState)
 	if err != nil {
 		return nil, err
 	}
-	set, err := signatureBatch(buf, proposerPub, reveal, domain, signing.RandaoSignature)
+	set, err := signatureBatchWithKey(buf, proposerPub, reveal, domain, signing.RandaoSignature)
 	if err != nil {
 		return nil, err
 	}
+	set.ValidatorIndices = [][]primitives.ValidatorIndex{{proposerIdx}}
 	return set, nil
 }
 
 // retrieves the randao related signing data from the state.
-func randaoSigningData(ctx context.Context, beaconState state.ReadOnlyBeaconState) ([]byte, []byte, []byte, error) {
+func randaoSigningData(ctx context.Context, beaconState state.ReadOnlyBeaconState) ([]byte, primitives.ValidatorIndex, dilithium.PublicKey, []byte, error) {
 	proposerIdx, err := helpers.BeaconProposerIndex(ctx, beaconState)
 	if err != nil {
-		return nil, nil, nil, errors.Wrap(err, "could not get beacon proposer index")
+		return nil, 0, nil, nil, errors.Wrap(err, "could not get beacon proposer index")
+	}
+	proposerPub, err := beaconState.PublicKeyAtIndex(proposerIdx)
+	if err != nil {
+		return nil, 0, nil, nil, err
 	}
-	proposerPub := beaconState.PubkeyAtIndex(proposerIdx)
 
 	currentEpoch := slots.ToEpoch(beaconState.Slot())
 	buf := make([]byte, 32)
@@ -176,9 +199,9 @@ func randaoSigningData(ctx context.Context, beaconState state.ReadOnlyBeaconStat
 
 	domain, err := signing.Domain(beaconState.Fork(), currentEpoch, params.BeaconConfig().DomainRandao, beaconState.GenesisValidatorsRoot())
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, 0, nil, nil, err
 	}
-	return buf, proposerPub[:], domain, nil
+	return buf, proposerIdx, proposerPub, domain, nil
 }
 
 // Method to break down attestations of the same domain and collect them into a single signature batch.
@@ -192,11 +215,12 @@ func createAttestationSignatureBatch(
 		return nil, nil
 	}
 
-	sigs := make([][]byte, len(atts))
-	pks := make([][]dilithium.PublicKey, len(atts))
-	msgs := make([][32]byte, len(atts))
-	descs := make([]string, len(atts))
-	for i, a := range atts {
+	sigs := make([][]byte, 0, len(atts))
+	pks := make([][]dilithium.PublicKey, 0, len(atts))
+	msgs := make([][32]byte, 0, len(atts))
+	descs := make([]string, 0, len(atts))
+	valIndices := make([][]primitives.ValidatorIndex, 0, len(atts))
+	for _, a := range atts {
 		c, err := helpers.BeaconCommitteeFromState(ctx, beaconState, a.Data.Slot, a.Data.CommitteeIndex)
 		if err != nil {
 			return nil, err
@@ -208,33 +232,35 @@ func createAttestationSignatureBatch(
 		if err := attestation.IsValidAttestationIndices(ctx, ia); err != nil {
 			return nil, err
 		}
-		sigs[i] = ia.Signature
+		root, err := signing.ComputeSigningRoot(ia.Data, domain)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get signing root of object")
+		}
+
 		indices := ia.AttestingIndices
-		pubkeys := make([][]byte, len(indices))
+		pubKeys := make([]dilithium.PublicKey, len(indices))
+		signers := make([]primitives.ValidatorIndex, len(indices))
 		for j := 0; j < len(indices); j++ {
-			pubkeyAtIdx := beaconState.PubkeyAtIndex(primitives.ValidatorIndex(indices[j]))
-			pubkeys[j] = pubkeyAtIdx[:]
-
-			pubKey, err := dilithium.PublicKeyFromBytes(pubkeys[j])
+			pubKey, err := beaconState.PublicKeyAtIndex(primitives.ValidatorIndex(indices[j]))
 			if err != nil {
-				return nil, errors.Wrap(err, "could not convert bytes to public key")
+				return nil, errors.Wrap(err, "could not get validator public key")
 			}
-			pks[i] = append(pks[i], pubKey)
-		}
-
-		root, err := signing.ComputeSigningRoot(ia.Data, domain)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get signing root of object")
+			pubKeys[j] = pubKey
+			signers[j] = primitives.ValidatorIndex(indices[j])
 		}
-		msgs[i] = root
 
-		descs[i] = signing.AttestationSignature
+		sigs = append(sigs, ia.Signature)
+		pks = append(pks, pubKeys)
+		msgs = append(msgs, root)
+		descs = append(descs, signing.AttestationSignature)
+		valIndices = append(valIndices, signers)
 	}
 	return &dilithium.SignatureBatch{
-		Signatures:   sigs,
-		PublicKeys:   pks,
-		Messages:     msgs,
-		Descriptions: descs,
+		Signatures:       sigs,
+		PublicKeys:       pks,
+		Messages:         msgs,
+		Descriptions:     descs,
+		ValidatorIndices: valIndices,
 	}, nil
 }
 
@@ -27,6 +27,12 @@ const (
 	NewHead
 	// MissedSlot is sent when we need to notify users that a slot was missed.
 	MissedSlot
+	// LightClientFinalityUpdate is sent when the node produced a light client finality update with a newer
+	// finalized header.
+	LightClientFinalityUpdate
+	// LightClientOptimisticUpdate is sent when the node produced a light client optimistic update with a newer
+	// attested header.
+	LightClientOptimisticUpdate
 )
 
 // BlockProcessedData is the data sent with BlockProcessed events.
@@ -0,0 +1,67 @@
+package lightclient
+
+import (
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// IsBetterUpdate - implements https://github.com/ethereum/consensus-specs/blob/3d235740e5f1e641d3b160c8688f26e7dc5a1894/specs/altair/light-client/sync-protocol.md#is_better_update
+// It decides which of two updates of the same sync committee period is stored and served as the best update of
+// the period by a full node, and which update a light client applies when its store times out.
+func IsBetterUpdate(newUpdate, oldUpdate *zondpbv2.LightClientUpdate) bool {
+	// Compare supermajority (> 2/3) sync committee participation
+	maxActiveParticipants := newUpdate.SyncAggregate.SyncCommitteeBits.Len()
+	newNumActiveParticipants := newUpdate.SyncAggregate.SyncCommitteeBits.Count()
+	oldNumActiveParticipants := oldUpdate.SyncAggregate.SyncCommitteeBits.Count()
+	newHasSupermajority := HasSupermajority(newNumActiveParticipants, maxActiveParticipants)
+	oldHasSupermajority := HasSupermajority(oldNumActiveParticipants, maxActiveParticipants)
+	if newHasSupermajority != oldHasSupermajority {
+		return newHasSupermajority
+	}
+	if !newHasSupermajority && newNumActiveParticipants != oldNumActiveParticipants {
+		return newNumActiveParticipants > oldNumActiveParticipants
+	}
+
+	// Compare presence of relevant sync committee
+	newHasRelevantSyncCommittee := slots.SyncCommitteePeriod(slots.ToEpoch(newUpdate.AttestedHeader.Slot)) ==
+		slots.SyncCommitteePeriod(slots.ToEpoch(newUpdate.SignatureSlot))
+	oldHasRelevantSyncCommittee := slots.SyncCommitteePeriod(slots.ToEpoch(oldUpdate.AttestedHeader.Slot)) ==
+		slots.SyncCommitteePeriod(slots.ToEpoch(oldUpdate.SignatureSlot))
+	if newHasRelevantSyncCommittee != oldHasRelevantSyncCommittee {
+		return newHasRelevantSyncCommittee
+	}
+
+	// Compare indication of any finality
+	newHasFinality := newUpdate.IsFinalityUpdate()
+	oldHasFinality := oldUpdate.IsFinalityUpdate()
+	if newHasFinality != oldHasFinality {
+		return newHasFinality
+	}
+
+	// Compare sync committee finality
+	if newHasFinality {
+		newHasSyncCommitteeFinality := slots.SyncCommitteePeriod(slots.ToEpoch(newUpdate.FinalizedHeader.Slot)) ==
+			slots.SyncCommitteePeriod(slots.ToEpoch(newUpdate.AttestedHeader.Slot))
+		oldHasSyncCommitteeFinality := slots.SyncCommitteePeriod(slots.ToEpoch(oldUpdate.FinalizedHeader.Slot)) ==
+			slots.SyncCommitteePeriod(slots.ToEpoch(oldUpdate.AttestedHeader.Slot))
+		if newHasSyncCommitteeFinality != oldHasSyncCommitteeFinality {
+			return newHasSyncCommitteeFinality
+		}
+	}
+
+	// Tiebreaker 1: Sync committee participation beyond supermajority
+	if newNumActiveParticipants != oldNumActiveParticipants {
+		return newNumActiveParticipants > oldNumActiveParticipants
+	}
+
+	// Tiebreaker 2: Prefer older data (fewer changes to best)
+	if newUpdate.AttestedHeader.Slot != oldUpdate.AttestedHeader.Slot {
+		return newUpdate.AttestedHeader.Slot < oldUpdate.AttestedHeader.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// HasSupermajority returns true if at least two thirds of a sync committee of the given size participated.
+func HasSupermajority(participants, size uint64) bool {
+	return participants*3 >= size*2
+}
@@ -0,0 +1,97 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestIsBetterUpdate(t *testing.T) {
+	periodSlots := primitives.Slot(params.BeaconConfig().EpochsPerSyncCommitteePeriod) * params.BeaconConfig().SlotsPerEpoch
+	update := func(participants uint64, attestedSlot, signatureSlot primitives.Slot, finalizedSlot primitives.Slot, finality bool) *zondpbv2.LightClientUpdate {
+		bits := bitfield.NewBitvector16()
+		for i := uint64(0); i < participants; i++ {
+			bits.SetBitAt(i, true)
+		}
+		branch := make([][]byte, 6)
+		for i := range branch {
+			branch[i] = make([]byte, 32)
+		}
+		if finality {
+			branch[0][0] = 1
+		}
+		return &zondpbv2.LightClientUpdate{
+			AttestedHeader:  &zondpbv1.BeaconBlockHeader{Slot: attestedSlot},
+			FinalizedHeader: &zondpbv1.BeaconBlockHeader{Slot: finalizedSlot},
+			FinalityBranch:  branch,
+			SyncAggregate:   &zondpbv1.SyncAggregate{SyncCommitteeBits: bits},
+			SignatureSlot:   signatureSlot,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		newUpdate *zondpbv2.LightClientUpdate
+		oldUpdate *zondpbv2.LightClientUpdate
+		want      bool
+	}{
+		{
+			name:      "supermajority beats more participants without supermajority",
+			newUpdate: update(11, 10, 11, 0, false),
+			oldUpdate: update(10, 10, 11, 0, true),
+			want:      true,
+		},
+		{
+			name:      "more participants without supermajority",
+			newUpdate: update(8, 10, 11, 0, true),
+			oldUpdate: update(9, 10, 11, 0, true),
+			want:      false,
+		},
+		{
+			name:      "relevant sync committee",
+			newUpdate: update(12, periodSlots-1, periodSlots, 0, true),
+			oldUpdate: update(12, 10, 11, 0, false),
+			want:      false,
+		},
+		{
+			name:      "finality",
+			newUpdate: update(12, 10, 11, 0, true),
+			oldUpdate: update(16, 10, 11, 0, false),
+			want:      true,
+		},
+		{
+			name:      "sync committee finality",
+			newUpdate: update(12, periodSlots+10, periodSlots+11, 0, true),
+			oldUpdate: update(12, periodSlots+10, periodSlots+11, periodSlots, true),
+			want:      false,
+		},
+		{
+			name:      "participation beyond supermajority",
+			newUpdate: update(16, 10, 11, 0, true),
+			oldUpdate: update(12, 10, 11, 0, true),
+			want:      true,
+		},
+		{
+			name:      "older attested header",
+			newUpdate: update(12, 10, 11, 0, true),
+			oldUpdate: update(12, 9, 11, 0, true),
+			want:      false,
+		},
+		{
+			name:      "older signature slot",
+			newUpdate: update(12, 10, 11, 0, true),
+			oldUpdate: update(12, 10, 12, 0, true),
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsBetterUpdate(tt.newUpdate, tt.oldUpdate))
+		})
+	}
+}
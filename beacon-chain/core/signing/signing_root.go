@@ -111,7 +111,7 @@ func SigningData(rootFunc func() ([32]byte, error), domain []byte) ([32]byte, er
 
 // ComputeDomainVerifySigningRoot computes domain and verifies signing root of an object given the beacon state, validator index and signature.
 func ComputeDomainVerifySigningRoot(st state.ReadOnlyBeaconState, index primitives.ValidatorIndex, epoch primitives.Epoch, obj fssz.HashRoot, domain [4]byte, sig []byte) error {
-	v, err := st.ValidatorAtIndex(index)
+	publicKey, err := st.PublicKeyAtIndex(index)
 	if err != nil {
 		return err
 	}
@@ -119,7 +119,7 @@ func ComputeDomainVerifySigningRoot(st state.ReadOnlyBeaconState, index primitiv
 	if err != nil {
 		return err
 	}
-	return VerifySigningRoot(obj, v.PublicKey, sig, d)
+	return VerifySigningRootWithKey(obj, publicKey, sig, d)
 }
 
 // VerifySigningRoot verifies the signing root of an object given its public key, signature and domain.
@@ -128,6 +128,11 @@ func VerifySigningRoot(obj fssz.HashRoot, pub, signature, domain []byte) error {
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to public key")
 	}
+	return VerifySigningRootWithKey(obj, publicKey, signature, domain)
+}
+
+// VerifySigningRootWithKey verifies the signing root of an object given its parsed public key, signature and domain.
+func VerifySigningRootWithKey(obj fssz.HashRoot, publicKey dilithium.PublicKey, signature, domain []byte) error {
 	sig, err := dilithium.SignatureFromBytes(signature)
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to signature")
@@ -148,6 +153,11 @@ func VerifyBlockHeaderSigningRoot(blkHdr *zondpb.BeaconBlockHeader, pub, signatu
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to public key")
 	}
+	return VerifyBlockHeaderSigningRootWithKey(blkHdr, publicKey, signature, domain)
+}
+
+// VerifyBlockHeaderSigningRootWithKey verifies the signing root of a block header given its parsed public key, signature and domain.
+func VerifyBlockHeaderSigningRootWithKey(blkHdr *zondpb.BeaconBlockHeader, publicKey dilithium.PublicKey, signature, domain []byte) error {
 	sig, err := dilithium.SignatureFromBytes(signature)
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to signature")
@@ -164,13 +174,21 @@ func VerifyBlockHeaderSigningRoot(blkHdr *zondpb.BeaconBlockHeader, pub, signatu
 
 // VerifyBlockSigningRoot verifies the signing root of a block given its public key, signature and domain.
 func VerifyBlockSigningRoot(pub, signature, domain []byte, rootFunc func() ([32]byte, error)) error {
-	set, err := BlockSignatureBatch(pub, signature, domain, rootFunc)
+	publicKey, err := dilithium.PublicKeyFromBytes(pub)
+	if err != nil {
+		return errors.Wrap(err, "could not convert bytes to public key")
+	}
+	return VerifyBlockSigningRootWithKey(publicKey, signature, domain, rootFunc)
+}
+
+// VerifyBlockSigningRootWithKey verifies the signing root of a block given its parsed public key, signature and domain.
+func VerifyBlockSigningRootWithKey(publicKey dilithium.PublicKey, signature, domain []byte, rootFunc func() ([32]byte, error)) error {
+	set, err := BlockSignatureBatchWithKey(publicKey, signature, domain, rootFunc)
 	if err != nil {
 		return err
 	}
 	// We assume only one signature batch is returned here.
 	sig := set.Signatures[0]
-	publicKey := set.PublicKeys[0][0]
 	root := set.Messages[0]
 
 	rSig, err := dilithium.SignatureFromBytes(sig)
@@ -190,6 +208,12 @@ func BlockSignatureBatch(pub, signature, domain []byte, rootFunc func() ([32]byt
 	if err != nil {
 		return nil, errors.Wrap(err, "could not convert bytes to public key")
 	}
+	return BlockSignatureBatchWithKey(publicKey, signature, domain, rootFunc)
+}
+
+// BlockSignatureBatchWithKey collates the signature, message and parsed public key of a block
+// into a signature batch object.
+func BlockSignatureBatchWithKey(publicKey dilithium.PublicKey, signature, domain []byte, rootFunc func() ([32]byte, error)) (*dilithium.SignatureBatch, error) {
 	// utilize custom block hashing function
 	root, err := SigningData(rootFunc, domain)
 	if err != nil {
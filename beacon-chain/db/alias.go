@@ -23,6 +23,10 @@ type HeadAccessDatabase = iface.HeadAccessDatabase
 // not be used often. Prefer a more restrictive interface in this package.
 type Database = iface.Database
 
+// BlobStorage defines the methods to store and read blob sidecars, which are kept outside of the
+// database.
+type BlobStorage = iface.BlobStorage
+
 // SlasherDatabase defines necessary methods for Prysm's slasher implementation.
 type SlasherDatabase = iface.SlasherDatabase
 
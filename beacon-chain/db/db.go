@@ -6,16 +6,17 @@ import (
 	"context"
 
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 )
 
 // NewDB initializes a new DB.
-func NewDB(ctx context.Context, dirPath string) (Database, error) {
-	return kv.NewKVStore(ctx, dirPath)
+func NewDB(ctx context.Context, dirPath string, opts ...kv.KVStoreOption) (Database, error) {
+	return kv.NewKVStore(ctx, dirPath, opts...)
 }
 
-// NewDBFilename uses the KVStoreDatafilePath so that if this layer of
+// NewDBFilename uses the KVStoreDatapath so that if this layer of
 // indirection between db.NewDB->kv.NewKVStore ever changes, it will be easy to remember
 // to also change this filename indirection at the same time.
-func NewDBFilename(dirPath string) string {
-	return kv.KVStoreDatafilePath(dirPath)
+func NewDBFilename(dirPath string, backend engine.Backend) string {
+	return kv.KVStoreDatapath(dirPath, backend)
 }
@@ -0,0 +1,116 @@
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+)
+
+// Era files use the e2store container format: a sequence of records, each made of an 8 byte header
+// followed by the record data. The header holds the 2 byte record type, the length of the data as a
+// little endian uint32 and 2 reserved bytes, which are always zero.
+const headerSize = 8
+
+type recordType [2]byte
+
+var (
+	versionType      = recordType{0x65, 0x32}
+	blockType        = recordType{0x01, 0x00}
+	stateType        = recordType{0x02, 0x00}
+	blindedBlockType = recordType{0x03, 0x00}
+	blobSidecarType  = recordType{0x04, 0x00}
+	slotIndexType    = recordType{0x69, 0x32}
+)
+
+var errInvalidRecord = errors.New("invalid e2store record")
+
+// writeRecord writes a record to w and returns the number of bytes written.
+func writeRecord(w io.Writer, typ recordType, data []byte) (int, error) {
+	if uint64(len(data)) > math.MaxUint32 {
+		return 0, errors.Wrapf(errInvalidRecord, "record of %d bytes is too large", len(data))
+	}
+	var header [headerSize]byte
+	copy(header[:2], typ[:])
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(data)))
+	n, err := w.Write(header[:])
+	if err != nil {
+		return n, err
+	}
+	m, err := w.Write(data)
+	return n + m, err
+}
+
+// readRecord reads the record at the given offset of r.
+func readRecord(r io.ReaderAt, off int64) (recordType, []byte, error) {
+	var header [headerSize]byte
+	if _, err := r.ReadAt(header[:], off); err != nil {
+		return recordType{}, nil, errors.Wrapf(err, "could not read record header at offset %d", off)
+	}
+	if header[6] != 0 || header[7] != 0 {
+		return recordType{}, nil, errors.Wrapf(errInvalidRecord, "reserved bytes of the record at offset %d are not zero", off)
+	}
+	var typ recordType
+	copy(typ[:], header[:2])
+	data := make([]byte, binary.LittleEndian.Uint32(header[2:6]))
+	if _, err := r.ReadAt(data, off+headerSize); err != nil {
+		return recordType{}, nil, errors.Wrapf(err, "could not read record data at offset %d", off)
+	}
+	return typ, data, nil
+}
+
+// slotIndexSize returns the size of a slot index record with the given number of slots, header included.
+func slotIndexSize(count int) int64 {
+	return headerSize + 8 + 8*int64(count) + 8
+}
+
+// encodeSlotIndex encodes the data of a slot index record: the first slot, the offset of the
+// record of each slot, relative to the start of the index record, and the number of slots. The
+// offset of a slot without a record is zero.
+func encodeSlotIndex(start primitives.Slot, offsets []int64) []byte {
+	b := make([]byte, 0, slotIndexSize(len(offsets))-headerSize)
+	b = binary.LittleEndian.AppendUint64(b, uint64(start))
+	for _, o := range offsets {
+		b = binary.LittleEndian.AppendUint64(b, uint64(o))
+	}
+	return binary.LittleEndian.AppendUint64(b, uint64(len(offsets)))
+}
+
+// decodeSlotIndex decodes the data of a slot index record.
+func decodeSlotIndex(data []byte) (primitives.Slot, []int64, error) {
+	if len(data) < 16 || len(data)%8 != 0 {
+		return 0, nil, errors.Wrapf(errInvalidRecord, "slot index of %d bytes", len(data))
+	}
+	count := binary.LittleEndian.Uint64(data[len(data)-8:])
+	if count != uint64(len(data)-16)/8 {
+		return 0, nil, errors.Wrapf(errInvalidRecord, "slot index of %d bytes has a count of %d", len(data), count)
+	}
+	start := primitives.Slot(binary.LittleEndian.Uint64(data))
+	offsets := make([]int64, count)
+	for i := range offsets {
+		offsets[i] = int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+	}
+	return start, offsets, nil
+}
+
+// compress encodes data in the snappy framing format.
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := snappy.NewBufferedWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress decodes data in the snappy framing format.
+func decompress(data []byte) ([]byte, error) {
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
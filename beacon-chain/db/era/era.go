@@ -0,0 +1,416 @@
+// Package era reads and writes era files, which archive the finalized chain history in a portable format.
+// The history is split into eras of SLOTS_PER_HISTORICAL_ROOT slots. The file of an era holds the blocks of
+// its slots with their blob sidecars and the state at the start of the next era, whose block roots commit to
+// every block of the file. The files can therefore be verified on their own, given the previous era file.
+package era
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	statenative "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// SlotsPerEra returns the number of slots covered by an era file.
+func SlotsPerEra() primitives.Slot {
+	return params.BeaconConfig().SlotsPerHistoricalRoot
+}
+
+// StateSlot returns the slot of the state stored in the file of the given era.
+func StateSlot(era uint64) primitives.Slot {
+	return primitives.Slot(era) * SlotsPerEra()
+}
+
+// FileName returns the conventional name of the file of an era, which includes the network name and the
+// first bytes of the root of its state.
+func FileName(era uint64, stateRoot [32]byte) string {
+	return fmt.Sprintf("%s-%05d-%x.era", params.BeaconConfig().ConfigName, era, stateRoot[:4])
+}
+
+// Writer writes the file of an era. The blocks must be written in slot order before the state.
+type Writer struct {
+	w        *bufio.Writer
+	era      uint64
+	off      int64
+	offsets  []int64
+	stateOff int64
+	hasState bool
+}
+
+// NewWriter starts the file of the given era.
+func NewWriter(w io.Writer, era uint64) (*Writer, error) {
+	ew := &Writer{w: bufio.NewWriter(w), era: era}
+	if era > 0 {
+		ew.offsets = make([]int64, SlotsPerEra())
+	}
+	if err := ew.write(versionType, nil); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// WriteBlock writes a block of the era followed by its blob sidecars.
+func (w *Writer) WriteBlock(blk interfaces.ReadOnlySignedBeaconBlock, sidecars []*zondpb.BlobSidecar) error {
+	if w.hasState {
+		return errors.New("block written after the state")
+	}
+	if err := blocks.BeaconBlockIsNil(blk); err != nil {
+		return err
+	}
+	slot := blk.Block().Slot()
+	if w.era == 0 || slot < StateSlot(w.era-1) || slot >= StateSlot(w.era) {
+		return errors.Errorf("block at slot %d is not part of era %d", slot, w.era)
+	}
+	i := slot - StateSlot(w.era-1)
+	for _, o := range w.offsets[i:] {
+		if o != 0 {
+			return errors.Errorf("block at slot %d is not written in slot order", slot)
+		}
+	}
+	enc, err := blk.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal block")
+	}
+	typ := blockType
+	if blk.IsBlinded() {
+		typ = blindedBlockType
+	}
+	w.offsets[i] = w.off
+	if err := w.writeCompressed(typ, enc); err != nil {
+		return err
+	}
+	for _, sc := range sidecars {
+		enc, err := sc.MarshalSSZ()
+		if err != nil {
+			return errors.Wrap(err, "could not marshal blob sidecar")
+		}
+		if err := w.writeCompressed(blobSidecarType, enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteState writes the state at the end of the era.
+func (w *Writer) WriteState(st state.ReadOnlyBeaconState) error {
+	if w.hasState {
+		return errors.New("state already written")
+	}
+	if st.Slot() != StateSlot(w.era) {
+		return errors.Errorf("state at slot %d is not the state of era %d", st.Slot(), w.era)
+	}
+	enc, err := st.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal state")
+	}
+	w.stateOff = w.off
+	w.hasState = true
+	return w.writeCompressed(stateType, enc)
+}
+
+// Close writes the slot indices of the blocks and of the state and flushes the file. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if !w.hasState {
+		return errors.New("no state written")
+	}
+	if w.era > 0 {
+		start := w.off
+		offsets := make([]int64, len(w.offsets))
+		for i, o := range w.offsets {
+			if o != 0 {
+				offsets[i] = o - start
+			}
+		}
+		if err := w.write(slotIndexType, encodeSlotIndex(StateSlot(w.era-1), offsets)); err != nil {
+			return err
+		}
+	}
+	if err := w.write(slotIndexType, encodeSlotIndex(StateSlot(w.era), []int64{w.stateOff - w.off})); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) writeCompressed(typ recordType, data []byte) error {
+	enc, err := compress(data)
+	if err != nil {
+		return errors.Wrap(err, "could not compress record")
+	}
+	return w.write(typ, enc)
+}
+
+func (w *Writer) write(typ recordType, data []byte) error {
+	n, err := writeRecord(w.w, typ, data)
+	w.off += int64(n)
+	return errors.Wrap(err, "could not write record")
+}
+
+// Reader reads the file of an era.
+type Reader struct {
+	f        *os.File
+	era      uint64
+	start    primitives.Slot
+	offsets  []int64
+	stateOff int64
+}
+
+// Open opens the era file at the given path and reads its slot indices.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{f: f}
+	if err := r.readIndices(); err != nil {
+		if cerr := f.Close(); cerr != nil {
+			log.WithError(cerr).Error("Could not close era file")
+		}
+		return nil, errors.Wrapf(err, "could not read era file %s", path)
+	}
+	return r, nil
+}
+
+func (r *Reader) readIndices() error {
+	typ, _, err := readRecord(r.f, 0)
+	if err != nil {
+		return err
+	}
+	if typ != versionType {
+		return errors.Wrap(errInvalidRecord, "file does not start with a version record")
+	}
+	info, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+	stateIndexOff := info.Size() - slotIndexSize(1)
+	stateSlot, stateOffsets, err := r.readIndex(stateIndexOff)
+	if err != nil {
+		return err
+	}
+	if stateSlot%SlotsPerEra() != 0 || len(stateOffsets) != 1 {
+		return errors.Wrap(errInvalidRecord, "invalid state index")
+	}
+	r.era = uint64(stateSlot / SlotsPerEra())
+	r.stateOff = stateIndexOff + stateOffsets[0]
+	if r.era == 0 {
+		return nil
+	}
+	blockIndexOff := stateIndexOff - slotIndexSize(int(SlotsPerEra()))
+	start, offsets, err := r.readIndex(blockIndexOff)
+	if err != nil {
+		return err
+	}
+	if start != StateSlot(r.era-1) || len(offsets) != int(SlotsPerEra()) {
+		return errors.Wrap(errInvalidRecord, "invalid block index")
+	}
+	r.start = start
+	r.offsets = make([]int64, len(offsets))
+	for i, o := range offsets {
+		if o != 0 {
+			r.offsets[i] = blockIndexOff + o
+		}
+	}
+	return nil
+}
+
+func (r *Reader) readIndex(off int64) (primitives.Slot, []int64, error) {
+	if off < headerSize {
+		return 0, nil, errors.Wrap(errInvalidRecord, "file is too short")
+	}
+	typ, data, err := readRecord(r.f, off)
+	if err != nil {
+		return 0, nil, err
+	}
+	if typ != slotIndexType {
+		return 0, nil, errors.Wrapf(errInvalidRecord, "no slot index at offset %d", off)
+	}
+	return decodeSlotIndex(data)
+}
+
+// Era returns the era of the file.
+func (r *Reader) Era() uint64 {
+	return r.era
+}
+
+// State reads the state at the end of the era.
+func (r *Reader) State() (state.BeaconState, error) {
+	typ, data, err := readRecord(r.f, r.stateOff)
+	if err != nil {
+		return nil, err
+	}
+	if typ != stateType {
+		return nil, errors.Wrap(errInvalidRecord, "state index does not point to a state")
+	}
+	if data, err = decompress(data); err != nil {
+		return nil, errors.Wrap(err, "could not decompress state")
+	}
+	return unmarshalState(data, StateSlot(r.era))
+}
+
+// Block reads the block at the given slot of the era and its blob sidecars. It returns nil if the slot has
+// no block.
+func (r *Reader) Block(slot primitives.Slot) (interfaces.ReadOnlySignedBeaconBlock, []*zondpb.BlobSidecar, error) {
+	if r.era == 0 || slot < r.start || slot >= StateSlot(r.era) {
+		return nil, nil, errors.Errorf("slot %d is not part of era %d", slot, r.era)
+	}
+	off := r.offsets[slot-r.start]
+	if off == 0 {
+		return nil, nil, nil
+	}
+	typ, data, err := readRecord(r.f, off)
+	if err != nil {
+		return nil, nil, err
+	}
+	if typ != blockType && typ != blindedBlockType {
+		return nil, nil, errors.Wrapf(errInvalidRecord, "block index of slot %d does not point to a block", slot)
+	}
+	off += headerSize + int64(len(data))
+	if data, err = decompress(data); err != nil {
+		return nil, nil, errors.Wrapf(err, "could not decompress block at slot %d", slot)
+	}
+	blk, err := unmarshalBlock(data, slot, typ == blindedBlockType)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The blob sidecars of the block are stored in the records which follow it.
+	var sidecars []*zondpb.BlobSidecar
+	for {
+		typ, data, err := readRecord(r.f, off)
+		if err != nil {
+			return nil, nil, err
+		}
+		if typ != blobSidecarType {
+			break
+		}
+		off += headerSize + int64(len(data))
+		if data, err = decompress(data); err != nil {
+			return nil, nil, errors.Wrap(err, "could not decompress blob sidecar")
+		}
+		sc := &zondpb.BlobSidecar{}
+		if err := sc.UnmarshalSSZ(data); err != nil {
+			return nil, nil, errors.Wrap(err, "could not unmarshal blob sidecar")
+		}
+		sidecars = append(sidecars, sc)
+	}
+	return blk, sidecars, nil
+}
+
+// Close closes the file.
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
+
+// forkAtSlot returns the version of the fork which is active at the given slot.
+func forkAtSlot(slot primitives.Slot) (int, error) {
+	cfg := params.BeaconConfig()
+	fv, err := forks.NewOrderedSchedule(cfg).VersionForEpoch(slots.ToEpoch(slot))
+	if err != nil {
+		return 0, err
+	}
+	ver, ok := params.ConfigForkVersions(cfg)[fv]
+	if !ok {
+		return 0, errors.Errorf("unknown fork version %#x", fv)
+	}
+	return ver, nil
+}
+
+func unmarshalBlock(enc []byte, slot primitives.Slot, blinded bool) (interfaces.ReadOnlySignedBeaconBlock, error) {
+	ver, err := forkAtSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	var blk interface{ UnmarshalSSZ([]byte) error }
+	switch {
+	case ver == version.Phase0 && !blinded:
+		blk = &zondpb.SignedBeaconBlock{}
+	case ver == version.Altair && !blinded:
+		blk = &zondpb.SignedBeaconBlockAltair{}
+	case ver == version.Bellatrix && !blinded:
+		blk = &zondpb.SignedBeaconBlockBellatrix{}
+	case ver == version.Bellatrix:
+		blk = &zondpb.SignedBlindedBeaconBlockBellatrix{}
+	case ver == version.Capella && !blinded:
+		blk = &zondpb.SignedBeaconBlockCapella{}
+	case ver == version.Capella:
+		blk = &zondpb.SignedBlindedBeaconBlockCapella{}
+	case ver == version.Deneb && !blinded:
+		blk = &zondpb.SignedBeaconBlockDeneb{}
+	case ver == version.Deneb:
+		blk = &zondpb.SignedBlindedBeaconBlockDeneb{}
+	default:
+		return nil, errors.Errorf("unsupported block of fork %s at slot %d", version.String(ver), slot)
+	}
+	if err := blk.UnmarshalSSZ(enc); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal %s block at slot %d", version.String(ver), slot)
+	}
+	wsb, err := blocks.NewSignedBeaconBlock(blk)
+	if err != nil {
+		return nil, err
+	}
+	if wsb.Block().Slot() != slot {
+		return nil, errors.Errorf("block at slot %d is indexed at slot %d", wsb.Block().Slot(), slot)
+	}
+	return wsb, nil
+}
+
+func unmarshalState(enc []byte, slot primitives.Slot) (state.BeaconState, error) {
+	ver, err := forkAtSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	var st state.BeaconState
+	switch ver {
+	case version.Phase0:
+		pb := &zondpb.BeaconState{}
+		if err := pb.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal phase0 state")
+		}
+		st, err = statenative.InitializeFromProtoUnsafePhase0(pb)
+	case version.Altair:
+		pb := &zondpb.BeaconStateAltair{}
+		if err := pb.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal altair state")
+		}
+		st, err = statenative.InitializeFromProtoUnsafeAltair(pb)
+	case version.Bellatrix:
+		pb := &zondpb.BeaconStateBellatrix{}
+		if err := pb.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal bellatrix state")
+		}
+		st, err = statenative.InitializeFromProtoUnsafeBellatrix(pb)
+	case version.Capella:
+		pb := &zondpb.BeaconStateCapella{}
+		if err := pb.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal capella state")
+		}
+		st, err = statenative.InitializeFromProtoUnsafeCapella(pb)
+	case version.Deneb:
+		pb := &zondpb.BeaconStateDeneb{}
+		if err := pb.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal deneb state")
+		}
+		st, err = statenative.InitializeFromProtoUnsafeDeneb(pb)
+	default:
+		return nil, errors.Errorf("unsupported state of fork %s at slot %d", version.String(ver), slot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if st.Slot() != slot {
+		return nil, errors.Errorf("state at slot %d is indexed at slot %d", st.Slot(), slot)
+	}
+	return st, nil
+}
@@ -0,0 +1,104 @@
+package era
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+func TestSlotIndex_RoundTrip(t *testing.T) {
+	enc := encodeSlotIndex(1024, []int64{-64, 0, -8})
+	assert.Equal(t, slotIndexSize(3)-headerSize, int64(len(enc)))
+	start, offsets, err := decodeSlotIndex(enc)
+	require.NoError(t, err)
+	assert.Equal(t, StateSlot(1), start)
+	assert.DeepEqual(t, []int64{-64, 0, -8}, offsets)
+
+	_, _, err = decodeSlotIndex(enc[8:])
+	require.ErrorIs(t, err, errInvalidRecord)
+}
+
+func TestWriter_Reader(t *testing.T) {
+	st, _ := util.DeterministicGenesisState(t, 4)
+	require.NoError(t, st.SetSlot(StateSlot(1)))
+	b1 := util.NewBeaconBlock()
+	b1.Block.Slot = 1
+	b5 := util.NewBeaconBlock()
+	b5.Block.Slot = 5
+	blk1, err := blocks.NewSignedBeaconBlock(b1)
+	require.NoError(t, err)
+	blk5, err := blocks.NewSignedBeaconBlock(b5)
+	require.NoError(t, err)
+	b := util.NewBeaconBlock()
+	b.Block.Slot = StateSlot(1)
+	outside, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	sidecars := make([]*zondpb.BlobSidecar, 2)
+	for i := range sidecars {
+		sidecars[i] = &zondpb.BlobSidecar{
+			BlockRoot:       bytesutil.PadTo([]byte{'a'}, 32),
+			Index:           uint64(i),
+			Slot:            5,
+			BlockParentRoot: bytesutil.PadTo([]byte{'b'}, 32),
+			Blob:            make([]byte, fieldparams.BlobLength),
+			KzgCommitment:   make([]byte, 48),
+			KzgProof:        make([]byte, 48),
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 1)
+	require.NoError(t, err)
+	require.ErrorContains(t, "not part of era 1", w.WriteBlock(outside, nil))
+	require.NoError(t, w.WriteBlock(blk5, sidecars))
+	require.ErrorContains(t, "not written in slot order", w.WriteBlock(blk1, nil))
+	require.ErrorContains(t, "no state written", w.Close())
+	require.NoError(t, w.WriteState(st))
+	require.NoError(t, w.Close())
+
+	path := filepath.Join(t.TempDir(), "test.era")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+	assert.Equal(t, uint64(1), r.Era())
+
+	blk, scs, err := r.Block(1)
+	require.NoError(t, err)
+	assert.Equal(t, nil, blk)
+	assert.Equal(t, 0, len(scs))
+	blk, scs, err = r.Block(5)
+	require.NoError(t, err)
+	want, err := blk5.Block().HashTreeRoot()
+	require.NoError(t, err)
+	got, err := blk.Block().HashTreeRoot()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.DeepSSZEqual(t, sidecars, scs)
+	_, _, err = r.Block(StateSlot(1))
+	require.ErrorContains(t, "not part of era 1", err)
+
+	rst, err := r.State()
+	require.NoError(t, err)
+	assert.DeepSSZEqual(t, st.ToProtoUnsafe(), rst.ToProtoUnsafe())
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.era")
+	require.NoError(t, os.WriteFile(path, []byte{0x65, 0x32, 0, 0, 0, 0, 0, 0}, 0600))
+	_, err := Open(path)
+	require.ErrorIs(t, err, errInvalidRecord)
+
+	require.NoError(t, os.WriteFile(path, make([]byte, 64), 0600))
+	_, err = Open(path)
+	require.ErrorContains(t, "version record", err)
+}
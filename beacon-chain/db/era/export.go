@@ -0,0 +1,233 @@
+package era
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/stategen"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// ExportDatabase describes the set of DB methods that the Exporter needs to read the finalized history.
+type ExportDatabase interface {
+	stategen.HistoryAccessor
+	GenesisState(ctx context.Context) (state.BeaconState, error)
+	FinalizedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, error)
+	IsFinalizedBlock(ctx context.Context, blockRoot [32]byte) bool
+}
+
+// BlobGetter retrieves the blob sidecars of a block.
+type BlobGetter interface {
+	BlobSidecarsByRoot(ctx context.Context, root [32]byte, indices ...uint64) ([]*zondpb.BlobSidecar, error)
+}
+
+// Exporter writes the finalized history of a database to era files.
+type Exporter struct {
+	db            ExportDatabase
+	blobs         BlobGetter
+	history       *stategen.CanonicalHistory
+	genesisRoot   [32]byte
+	finalizedSlot primitives.Slot
+	cache         *lastState
+}
+
+// NewExporter initializes an Exporter of the history finalized in the database. The blob sidecars are read from
+// blobs, which may be nil to export the blocks without their sidecars.
+func NewExporter(ctx context.Context, beaconDB ExportDatabase, blobs BlobGetter) (*Exporter, error) {
+	genesisRoot, err := beaconDB.GenesisBlockRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get genesis block root")
+	}
+	cp, err := beaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	finalizedSlot, err := slots.EpochStart(cp.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	e := &Exporter{
+		db:            beaconDB,
+		blobs:         blobs,
+		genesisRoot:   genesisRoot,
+		finalizedSlot: finalizedSlot,
+		cache:         &lastState{},
+	}
+	e.history = stategen.NewCanonicalHistory(beaconDB, e, e, stategen.WithCache(e.cache))
+	return e, nil
+}
+
+// IsCanonical returns true for the finalized blocks, which are the only blocks exported.
+func (e *Exporter) IsCanonical(ctx context.Context, blockRoot [32]byte) (bool, error) {
+	return blockRoot == e.genesisRoot || e.db.IsFinalizedBlock(ctx, blockRoot), nil
+}
+
+// CurrentSlot returns the slot of the finalized checkpoint, past which the history cannot be exported.
+func (e *Exporter) CurrentSlot() primitives.Slot {
+	return e.finalizedSlot
+}
+
+// LastEra returns the last era whose blocks are all finalized.
+func (e *Exporter) LastEra() uint64 {
+	return uint64(e.finalizedSlot / SlotsPerEra())
+}
+
+// Export writes the file of the given era to dir and returns its path. Consecutive eras are exported faster
+// in ascending order, as the state of an era is then computed from the state of the previous one.
+func (e *Exporter) Export(ctx context.Context, dir string, era uint64) (string, error) {
+	if era > e.LastEra() {
+		return "", errors.Errorf("era %d is not finalized, the last finalized era is %d", era, e.LastEra())
+	}
+	st, err := e.eraState(ctx, era)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not compute state of era %d", era)
+	}
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "could not compute state root")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, FileName(era, stateRoot))
+	tmp := path + ".part"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+	n, err := e.write(ctx, f, era, st)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		if rerr := os.Remove(tmp); rerr != nil {
+			log.WithError(rerr).Error("Could not remove partial era file")
+		}
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	log.WithFields(logrus.Fields{
+		"era":    era,
+		"blocks": n,
+		"path":   path,
+	}).Info("Exported era")
+	return path, nil
+}
+
+// write writes the blocks of the era, which are committed to by the block roots of its state, and the state.
+func (e *Exporter) write(ctx context.Context, f *os.File, era uint64, st state.BeaconState) (int, error) {
+	w, err := NewWriter(f, era)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	if era > 0 {
+		roots := st.BlockRoots()
+		var prev []byte
+		for slot := StateSlot(era - 1); slot < StateSlot(era); slot++ {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			root := roots[slot%SlotsPerEra()]
+			if string(root) == string(prev) {
+				continue
+			}
+			prev = root
+			blk, err := e.db.Block(ctx, bytesutil.ToBytes32(root))
+			if err != nil {
+				return 0, errors.Wrapf(err, "could not get block at slot %d", slot)
+			}
+			if err := blocks.BeaconBlockIsNil(blk); err != nil {
+				return 0, errors.Wrapf(db.ErrNotFound, "no block with root %#x at slot %d", root, slot)
+			}
+			// The root of the first slot may be the last block of the previous era.
+			if blk.Block().Slot() < StateSlot(era-1) {
+				continue
+			}
+			sidecars, err := e.sidecars(ctx, blk, bytesutil.ToBytes32(root))
+			if err != nil {
+				return 0, err
+			}
+			if err := w.WriteBlock(blk, sidecars); err != nil {
+				return 0, err
+			}
+			n++
+		}
+	}
+	if err := w.WriteState(st); err != nil {
+		return 0, err
+	}
+	return n, w.Close()
+}
+
+// eraState returns the state at the end of the era, which is computed from the state after its last block.
+func (e *Exporter) eraState(ctx context.Context, era uint64) (state.BeaconState, error) {
+	if era == 0 {
+		return e.db.GenesisState(ctx)
+	}
+	root, err := e.history.BlockRootForSlot(ctx, StateSlot(era)-1)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := e.db.Block(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := blocks.BeaconBlockIsNil(blk); err != nil {
+		return nil, err
+	}
+	st, err := e.history.ReplayerForSlot(blk.Block().Slot()).ReplayBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.cache.root, e.cache.state = root, st.Copy()
+	return stategen.ReplayProcessSlots(ctx, st, StateSlot(era))
+}
+
+// sidecars returns the stored blob sidecars of a block.
+func (e *Exporter) sidecars(ctx context.Context, blk interfaces.ReadOnlySignedBeaconBlock, root [32]byte) ([]*zondpb.BlobSidecar, error) {
+	if e.blobs == nil || blk.Version() < version.Deneb {
+		return nil, nil
+	}
+	commitments, err := blk.Block().Body().BlobKzgCommitments()
+	if err != nil {
+		return nil, err
+	}
+	if len(commitments) == 0 {
+		return nil, nil
+	}
+	sidecars, err := e.blobs.BlobSidecarsByRoot(ctx, root)
+	if errors.Is(err, db.ErrNotFound) {
+		log.WithField("slot", blk.Block().Slot()).Warn("Blob sidecars are not stored, exporting the block without them")
+		return nil, nil
+	}
+	return sidecars, err
+}
+
+// lastState caches the state after the last block of the previously exported era, from which the state of the
+// next era is replayed.
+type lastState struct {
+	root  [32]byte
+	state state.BeaconState
+}
+
+// ByBlockRoot implements stategen.CachedGetter.
+func (c *lastState) ByBlockRoot(root [32]byte) (state.BeaconState, error) {
+	if c.state == nil || root != c.root {
+		return nil, stategen.ErrNotInCache
+	}
+	return c.state.Copy(), nil
+}
@@ -0,0 +1,377 @@
+package era
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/blockchain/kzg"
+	coreblocks "github.com/theQRL/qrysm/v4/beacon-chain/core/blocks"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/stateutil"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	"google.golang.org/protobuf/proto"
+)
+
+// ImportDatabase describes the set of DB methods that the Importer needs to save the imported history.
+type ImportDatabase interface {
+	GenesisBlockRoot(ctx context.Context) ([32]byte, error)
+	SaveGenesisData(ctx context.Context, state state.BeaconState) error
+	SaveBlocks(ctx context.Context, blocks []interfaces.ReadOnlySignedBeaconBlock) error
+	SaveStateSummaries(ctx context.Context, summaries []*zondpb.StateSummary) error
+	SaveState(ctx context.Context, state state.ReadOnlyBeaconState, blockRoot [32]byte) error
+	SaveJustifiedCheckpoint(ctx context.Context, checkpoint *zondpb.Checkpoint) error
+	SaveFinalizedCheckpoint(ctx context.Context, checkpoint *zondpb.Checkpoint) error
+	SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error
+}
+
+// BlobSaver saves the blob sidecars of a block.
+type BlobSaver interface {
+	SaveBlobSidecar(ctx context.Context, scs []*zondpb.BlobSidecar) error
+}
+
+// Importer verifies era files and saves their history to an empty database. The files must be imported in
+// ascending order, starting from era 0, which holds the genesis state.
+type Importer struct {
+	db                    ImportDatabase
+	blobs                 BlobSaver
+	genesisValidatorsRoot [32]byte
+	next                  uint64
+	genesisRoot           [32]byte
+	lastRoot              [32]byte
+	lastState             state.BeaconState
+}
+
+// NewImporter initializes an Importer into the given database, which must be empty. The blob sidecars are saved
+// to blobs, which may be nil to skip them.
+//
+// The genesis state of era 0 is the trust anchor of the whole import, so it must match the trusted genesis
+// validators root of the network. Otherwise a history forged from its own genesis validators would verify.
+func NewImporter(ctx context.Context, beaconDB ImportDatabase, blobs BlobSaver, genesisValidatorsRoot [32]byte) (*Importer, error) {
+	if genesisValidatorsRoot == [32]byte{} {
+		return nil, errors.New("genesis validators root is required")
+	}
+	_, err := beaconDB.GenesisBlockRoot(ctx)
+	if err == nil {
+		return nil, errors.New("database is not empty")
+	}
+	if !errors.Is(err, db.ErrNotFoundGenesisBlockRoot) {
+		return nil, errors.Wrap(err, "could not get genesis block root")
+	}
+	if blobs != nil {
+		if err := kzg.Start(); err != nil {
+			return nil, errors.Wrap(err, "could not load the KZG trusted setup")
+		}
+	}
+	return &Importer{db: beaconDB, blobs: blobs, genesisValidatorsRoot: genesisValidatorsRoot}, nil
+}
+
+// Import verifies the era file at the given path and saves its blocks, blob sidecars and state. The state of
+// the era becomes the finalized checkpoint.
+func (i *Importer) Import(ctx context.Context, path string) error {
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.WithError(err).Error("Could not close era file")
+		}
+	}()
+	if r.Era() != i.next {
+		return errors.Errorf("era %d is imported out of order, expected era %d", r.Era(), i.next)
+	}
+	st, err := r.State()
+	if err != nil {
+		return err
+	}
+	if err := i.verifyState(st); err != nil {
+		return errors.Wrapf(err, "invalid state in era %d", r.Era())
+	}
+	n := 0
+	if r.Era() == 0 {
+		if err := i.db.SaveGenesisData(ctx, st); err != nil {
+			return errors.Wrap(err, "could not save genesis data")
+		}
+		if i.genesisRoot, err = i.db.GenesisBlockRoot(ctx); err != nil {
+			return errors.Wrap(err, "could not get genesis block root")
+		}
+		i.lastRoot = i.genesisRoot
+	} else if n, err = i.importBlocks(ctx, r, st); err != nil {
+		return errors.Wrapf(err, "could not import blocks of era %d", r.Era())
+	}
+	i.lastState = st
+	i.next++
+	log.WithFields(logrus.Fields{
+		"era":    r.Era(),
+		"blocks": n,
+	}).Info("Imported era")
+	return nil
+}
+
+// Finish saves the state of the last imported era and makes its block the head of the database.
+func (i *Importer) Finish(ctx context.Context) error {
+	if i.lastState == nil {
+		return errors.New("no era imported")
+	}
+	if err := i.db.SaveState(ctx, i.lastState, i.lastRoot); err != nil {
+		return errors.Wrap(err, "could not save state")
+	}
+	cp := &zondpb.Checkpoint{Epoch: slots.ToEpoch(i.lastState.Slot()), Root: i.lastRoot[:]}
+	if err := i.db.SaveJustifiedCheckpoint(ctx, cp); err != nil {
+		return errors.Wrap(err, "could not save justified checkpoint")
+	}
+	return i.db.SaveHeadBlockRoot(ctx, i.lastRoot)
+}
+
+// verifyState checks that the state belongs to the same chain as the previously imported ones. The rest of the
+// state is verified against the blocks of its era by importBlocks.
+func (i *Importer) verifyState(st state.BeaconState) error {
+	fv, err := forks.NewOrderedSchedule(params.BeaconConfig()).VersionForEpoch(slots.ToEpoch(st.Slot()))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(st.Fork().CurrentVersion, fv[:]) {
+		return errors.Errorf("fork version %#x does not match the fork version %#x of the network", st.Fork().CurrentVersion, fv)
+	}
+	if !bytes.Equal(st.GenesisValidatorsRoot(), i.genesisValidatorsRoot[:]) {
+		return errors.Errorf("genesis validators root %#x does not match the genesis validators root %#x of the network", st.GenesisValidatorsRoot(), i.genesisValidatorsRoot)
+	}
+	if i.lastState == nil {
+		return nil
+	}
+	if st.Slot() != StateSlot(i.next) {
+		return errors.Errorf("state slot %d is not the slot %d of the era", st.Slot(), StateSlot(i.next))
+	}
+	// Validators are never removed and their keys never change, so the validators of the previous era must be
+	// the first validators of the state.
+	if st.NumValidators() < i.lastState.NumValidators() {
+		return errors.Errorf("state has %d validators, fewer than the %d of the previous era", st.NumValidators(), i.lastState.NumValidators())
+	}
+	for idx := primitives.ValidatorIndex(0); int(idx) < i.lastState.NumValidators(); idx++ {
+		if st.PubkeyAtIndex(idx) != i.lastState.PubkeyAtIndex(idx) {
+			return errors.Errorf("public key of validator %d does not match the previous era", idx)
+		}
+	}
+	return i.verifyHistoricalRoots(st)
+}
+
+// verifyHistoricalRoots checks that the historical roots or summaries of the state extend the ones of the previous
+// era with the block and state roots of the state, which the state appended at the end of the era.
+func (i *Importer) verifyHistoricalRoots(st state.BeaconState) error {
+	prevRoots, err := i.lastState.HistoricalRoots()
+	if err != nil {
+		return err
+	}
+	roots, err := st.HistoricalRoots()
+	if err != nil {
+		return err
+	}
+	var prevSummaries, summaries []*zondpb.HistoricalSummary
+	if i.lastState.Version() >= version.Capella {
+		if prevSummaries, err = i.lastState.HistoricalSummaries(); err != nil {
+			return err
+		}
+	}
+	if st.Version() >= version.Capella {
+		if summaries, err = st.HistoricalSummaries(); err != nil {
+			return err
+		}
+	}
+	if len(roots) < len(prevRoots) || len(summaries) < len(prevSummaries) ||
+		len(roots)-len(prevRoots)+len(summaries)-len(prevSummaries) != 1 {
+		return errors.New("historical roots do not extend the previous era by one entry")
+	}
+	for j := range prevRoots {
+		if !bytes.Equal(roots[j], prevRoots[j]) {
+			return errors.Errorf("historical root %d does not match the previous era", j)
+		}
+	}
+	for j := range prevSummaries {
+		if !proto.Equal(summaries[j], prevSummaries[j]) {
+			return errors.Errorf("historical summary %d does not match the previous era", j)
+		}
+	}
+	if len(summaries) > len(prevSummaries) {
+		br, err := stateutil.ArraysRoot(st.BlockRoots(), fieldparams.BlockRootsLength)
+		if err != nil {
+			return err
+		}
+		sr, err := stateutil.ArraysRoot(st.StateRoots(), fieldparams.StateRootsLength)
+		if err != nil {
+			return err
+		}
+		last := summaries[len(summaries)-1]
+		if !bytes.Equal(last.BlockSummaryRoot, br[:]) || !bytes.Equal(last.StateSummaryRoot, sr[:]) {
+			return errors.New("last historical summary does not match the block and state roots of the state")
+		}
+		return nil
+	}
+	batchRoot, err := (&zondpb.HistoricalBatch{BlockRoots: st.BlockRoots(), StateRoots: st.StateRoots()}).HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(roots[len(roots)-1], batchRoot[:]) {
+		return errors.New("last historical root does not match the block and state roots of the state")
+	}
+	return nil
+}
+
+// importBlocks verifies the blocks of the era against the block roots of its state and saves them. Every slot
+// of the era must hold either its own block or the root of the previous one, so no block can be left out.
+//
+// The state of the era is not trusted until it is verified against the chain: the state roots of the state must
+// match the state roots of the blocks, and the root of the state of the previous era when its slot is skipped.
+// Block signatures are verified with the validators of the previous era, whose state is trusted. A block whose
+// proposer joined during the era is verified with the state of the era, and must be followed by a block of a
+// validator of the previous era, whose signature commits to it through the parent roots.
+func (i *Importer) importBlocks(ctx context.Context, r *Reader, st state.BeaconState) (int, error) {
+	roots := st.BlockRoots()
+	stateRoots := st.StateRoots()
+	first := StateSlot(r.Era() - 1)
+	if blk, _, err := r.Block(first); err != nil {
+		return 0, err
+	} else if blk == nil {
+		lastStateRoot, err := i.lastState.HashTreeRoot(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.Equal(stateRoots[first%SlotsPerEra()], lastStateRoot[:]) {
+			return 0, errors.Errorf("state root %#x at slot %d does not match the state of the previous era %#x", stateRoots[first%SlotsPerEra()], first, lastStateRoot)
+		}
+	}
+	// unanchored is the slot of the first block, since the last block of a validator of the previous era, whose
+	// proposer joined during the era.
+	var unanchored *primitives.Slot
+	blks := make([]interfaces.ReadOnlySignedBeaconBlock, 0)
+	summaries := make([]*zondpb.StateSummary, 0)
+	sidecars := make([][]*zondpb.BlobSidecar, 0)
+	lastRoot := i.lastRoot
+	for slot := first; slot < StateSlot(r.Era()); slot++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		want := roots[slot%SlotsPerEra()]
+		blk, scs, err := r.Block(slot)
+		if err != nil {
+			return 0, err
+		}
+		if blk == nil {
+			if !bytes.Equal(want, lastRoot[:]) {
+				return 0, errors.Errorf("missing block with root %#x at slot %d", want, slot)
+			}
+			continue
+		}
+		root, err := blk.Block().HashTreeRoot()
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.Equal(want, root[:]) {
+			return 0, errors.Errorf("block root %#x at slot %d does not match the state block root %#x", root, slot, want)
+		}
+		if sr := blk.Block().StateRoot(); !bytes.Equal(stateRoots[slot%SlotsPerEra()], sr[:]) {
+			return 0, errors.Errorf("state root %#x of the block at slot %d does not match the state root %#x of the state", sr, slot, stateRoots[slot%SlotsPerEra()])
+		}
+		if slot == 0 {
+			// The genesis block is not signed and was saved with the genesis state.
+			if root != i.genesisRoot {
+				return 0, errors.Errorf("genesis block root %#x does not match %#x", root, i.genesisRoot)
+			}
+			lastRoot = root
+			continue
+		}
+		if blk.Block().ParentRoot() != lastRoot {
+			return 0, errors.Errorf("parent root %#x of the block at slot %d does not match %#x", blk.Block().ParentRoot(), slot, lastRoot)
+		}
+		signer := state.ReadOnlyBeaconState(i.lastState)
+		if int(blk.Block().ProposerIndex()) >= i.lastState.NumValidators() {
+			signer = st
+			if unanchored == nil {
+				s := slot
+				unanchored = &s
+			}
+		} else {
+			unanchored = nil
+		}
+		if err := coreblocks.VerifyBlockSignatureUsingCurrentFork(signer, blk); err != nil {
+			return 0, errors.Wrapf(err, "invalid signature of the block at slot %d", slot)
+		}
+		if err := i.verifySidecars(blk, root, scs); err != nil {
+			return 0, errors.Wrapf(err, "invalid blob sidecars of the block at slot %d", slot)
+		}
+		blks = append(blks, blk)
+		summaries = append(summaries, &zondpb.StateSummary{Slot: slot, Root: root[:]})
+		sidecars = append(sidecars, scs)
+		lastRoot = root
+	}
+	if unanchored != nil {
+		return 0, errors.Errorf("block at slot %d is proposed by a validator which joined during the era, and no later block of a validator of the previous era commits to it", *unanchored)
+	}
+	header := st.LatestBlockHeader()
+	headerRoot, err := header.HashTreeRoot()
+	if err != nil {
+		return 0, err
+	}
+	if headerRoot != lastRoot {
+		return 0, errors.Errorf("last block root %#x does not match the latest block header of the state %#x", lastRoot, headerRoot)
+	}
+
+	if err := i.db.SaveBlocks(ctx, blks); err != nil {
+		return 0, errors.Wrap(err, "could not save blocks")
+	}
+	if err := i.db.SaveStateSummaries(ctx, summaries); err != nil {
+		return 0, errors.Wrap(err, "could not save state summaries")
+	}
+	if i.blobs != nil {
+		for _, scs := range sidecars {
+			if len(scs) == 0 {
+				continue
+			}
+			if err := i.blobs.SaveBlobSidecar(ctx, scs); err != nil {
+				return 0, errors.Wrap(err, "could not save blob sidecars")
+			}
+		}
+	}
+	cp := &zondpb.Checkpoint{Epoch: slots.ToEpoch(st.Slot()), Root: lastRoot[:]}
+	if err := i.db.SaveFinalizedCheckpoint(ctx, cp); err != nil {
+		return 0, errors.Wrap(err, "could not save finalized checkpoint")
+	}
+	i.lastRoot = lastRoot
+	return len(blks), nil
+}
+
+// verifySidecars checks the blob sidecars of a block against its KZG commitments. The sidecars of a block may
+// be missing, when they were not stored by the exporting node, but they cannot be incomplete.
+func (i *Importer) verifySidecars(blk interfaces.ReadOnlySignedBeaconBlock, root [32]byte, scs []*zondpb.BlobSidecar) error {
+	if len(scs) == 0 {
+		return nil
+	}
+	if blk.Version() < version.Deneb {
+		return errors.Errorf("blob sidecars of a %s block", version.String(blk.Version()))
+	}
+	commitments, err := blk.Block().Body().BlobKzgCommitments()
+	if err != nil {
+		return err
+	}
+	for j, sc := range scs {
+		if sc.Index != uint64(j) || sc.Slot != blk.Block().Slot() || !bytes.Equal(sc.BlockRoot, root[:]) {
+			return errors.Errorf("blob sidecar %d does not belong to the block", j)
+		}
+		if j >= len(commitments) || !bytes.Equal(sc.KzgCommitment, commitments[j]) {
+			return errors.Errorf("KZG commitment of blob sidecar %d does not match the block", j)
+		}
+	}
+	if i.blobs == nil {
+		return nil
+	}
+	return kzg.IsDataAvailable(commitments, scs)
+}
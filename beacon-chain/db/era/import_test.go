@@ -0,0 +1,257 @@
+package era
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/transition"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	testDB "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// nextBlock returns a signed block at the given slot on top of the state, and the state after it.
+func nextBlock(t *testing.T, st state.BeaconState, keys []dilithium.DilithiumKey, slot primitives.Slot) (interfaces.ReadOnlySignedBeaconBlock, state.BeaconState) {
+	ctx := context.Background()
+	pre, err := transition.ProcessSlots(ctx, st.Copy(), slot)
+	require.NoError(t, err)
+	proposer, err := helpers.BeaconProposerIndex(ctx, pre)
+	require.NoError(t, err)
+	parent, err := pre.LatestBlockHeader().HashTreeRoot()
+	require.NoError(t, err)
+	reveal, err := util.RandaoReveal(pre, slots.ToEpoch(slot), keys)
+	require.NoError(t, err)
+	b := util.NewBeaconBlock()
+	b.Block.Slot = slot
+	b.Block.ProposerIndex = proposer
+	b.Block.ParentRoot = parent[:]
+	b.Block.Body.RandaoReveal = reveal
+	sig, err := util.BlockSignature(st, b.Block, keys)
+	require.NoError(t, err)
+	b.Signature = sig.Marshal()
+	blk, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	st, err = transition.ExecuteStateTransition(ctx, st, blk)
+	require.NoError(t, err)
+	return blk, st
+}
+
+// exportChain saves a finalized chain with blocks in the first two eras and exports eras 0 to 2. It returns the
+// genesis validators root of the chain along with its blocks and the paths of the era files.
+func exportChain(t *testing.T) ([]interfaces.ReadOnlySignedBeaconBlock, []string, [32]byte) {
+	ctx := context.Background()
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.ConfigName = "test"
+	params.OverrideBeaconConfig(cfg)
+	st, keys := util.DeterministicGenesisState(t, 64)
+	gvr := bytesutil.ToBytes32(st.GenesisValidatorsRoot())
+	// The database is closed before returning, as only one database can be open at a time.
+	beaconDB, err := kv.NewKVStore(ctx, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveGenesisData(ctx, st))
+
+	var chain []interfaces.ReadOnlySignedBeaconBlock
+	var blk interfaces.ReadOnlySignedBeaconBlock
+	for _, slot := range []primitives.Slot{1, 2, 5, StateSlot(1) + 6} {
+		blk, st = nextBlock(t, st, keys, slot)
+		require.NoError(t, beaconDB.SaveBlock(ctx, blk))
+		root, err := blk.Block().HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, beaconDB.SaveStateSummary(ctx, &zondpb.StateSummary{Slot: slot, Root: root[:]}))
+		chain = append(chain, blk)
+	}
+	root, err := blk.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveFinalizedCheckpoint(ctx, &zondpb.Checkpoint{Epoch: slots.ToEpoch(StateSlot(2)), Root: root[:]}))
+
+	e, err := NewExporter(ctx, beaconDB, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), e.LastEra())
+	_, err = e.Export(ctx, t.TempDir(), 3)
+	require.ErrorContains(t, "not finalized", err)
+	dir := t.TempDir()
+	paths := make([]string, 0, 3)
+	for era := uint64(0); era <= e.LastEra(); era++ {
+		path, err := e.Export(ctx, dir, era)
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+	require.NoError(t, beaconDB.Close())
+	return chain, paths, gvr
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	chain, paths, gvr := exportChain(t)
+
+	r, err := Open(paths[1])
+	require.NoError(t, err)
+	blk, _, err := r.Block(5)
+	require.NoError(t, err)
+	assert.DeepEqual(t, chain[2], blk)
+	blk, _, err = r.Block(3)
+	require.NoError(t, err)
+	assert.Equal(t, nil, blk)
+	require.NoError(t, r.Close())
+
+	beaconDB := testDB.SetupDB(t)
+	_, err = NewImporter(ctx, beaconDB, nil, [32]byte{})
+	require.ErrorContains(t, "genesis validators root is required", err)
+	i, err := NewImporter(ctx, beaconDB, nil, gvr)
+	require.NoError(t, err)
+	require.ErrorContains(t, "out of order", i.Import(ctx, paths[1]))
+	for _, path := range paths {
+		require.NoError(t, i.Import(ctx, path))
+	}
+	require.NoError(t, i.Finish(ctx))
+
+	for _, blk := range chain {
+		root, err := blk.Block().HashTreeRoot()
+		require.NoError(t, err)
+		assert.Equal(t, true, beaconDB.IsFinalizedBlock(ctx, root))
+	}
+	last, err := chain[len(chain)-1].Block().HashTreeRoot()
+	require.NoError(t, err)
+	cp, err := beaconDB.FinalizedCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, slots.ToEpoch(StateSlot(2)), cp.Epoch)
+	assert.DeepEqual(t, last[:], cp.Root)
+	head, err := beaconDB.HeadBlock(ctx)
+	require.NoError(t, err)
+	headRoot, err := head.Block().HashTreeRoot()
+	require.NoError(t, err)
+	assert.Equal(t, last, headRoot)
+	st, err := beaconDB.State(ctx, last)
+	require.NoError(t, err)
+	assert.Equal(t, StateSlot(2), st.Slot())
+
+	_, err = NewImporter(ctx, beaconDB, nil, gvr)
+	require.ErrorContains(t, "not empty", err)
+}
+
+func TestImport_Invalid(t *testing.T) {
+	ctx := context.Background()
+	_, paths, gvr := exportChain(t)
+	r, err := Open(paths[1])
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+	st, err := r.State()
+	require.NoError(t, err)
+
+	// rewrite copies the blocks of era 1, except the blocks that are dropped or replaced, followed by the given state.
+	rewrite := func(t *testing.T, st state.BeaconState, replace map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock) string {
+		path := filepath.Join(t.TempDir(), "invalid.era")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		w, err := NewWriter(f, 1)
+		require.NoError(t, err)
+		for slot := StateSlot(0); slot < StateSlot(1); slot++ {
+			blk, _, err := r.Block(slot)
+			require.NoError(t, err)
+			if b, ok := replace[slot]; ok {
+				blk = b
+			}
+			if blk != nil {
+				require.NoError(t, w.WriteBlock(blk, nil))
+			}
+		}
+		require.NoError(t, w.WriteState(st))
+		require.NoError(t, w.Close())
+		require.NoError(t, f.Close())
+		return path
+	}
+	importEra := func(t *testing.T, path string) error {
+		i, err := NewImporter(ctx, testDB.SetupDB(t), nil, gvr)
+		require.NoError(t, err)
+		require.NoError(t, i.Import(ctx, paths[0]))
+		return i.Import(ctx, path)
+	}
+
+	t.Run("missing block", func(t *testing.T) {
+		path := rewrite(t, st, map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{2: nil})
+		require.ErrorContains(t, "missing block", importEra(t, path))
+	})
+	t.Run("tampered block", func(t *testing.T) {
+		blk, _, err := r.Block(2)
+		require.NoError(t, err)
+		pb, err := blk.Proto()
+		require.NoError(t, err)
+		tampered := pb.(*zondpb.SignedBeaconBlock)
+		tampered.Block.Body.Graffiti = make([]byte, 32)
+		tampered.Block.Body.Graffiti[0] = 'x'
+		blk, err = blocks.NewSignedBeaconBlock(tampered)
+		require.NoError(t, err)
+		path := rewrite(t, st, map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{2: blk})
+		require.ErrorContains(t, "does not match the state block root", importEra(t, path))
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		blk, _, err := r.Block(5)
+		require.NoError(t, err)
+		pb, err := blk.Proto()
+		require.NoError(t, err)
+		tampered := pb.(*zondpb.SignedBeaconBlock)
+		tampered.Signature[0] ^= 0xff
+		blk, err = blocks.NewSignedBeaconBlock(tampered)
+		require.NoError(t, err)
+		path := rewrite(t, st, map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{5: blk})
+		require.ErrorContains(t, "invalid signature of the block at slot 5", importEra(t, path))
+	})
+	t.Run("forged state root", func(t *testing.T) {
+		forged := st.Copy()
+		require.NoError(t, forged.UpdateStateRootAtIndex(5, [32]byte{'x'}))
+		// Keep the historical root consistent with the forged state roots.
+		batch, err := (&zondpb.HistoricalBatch{BlockRoots: forged.BlockRoots(), StateRoots: forged.StateRoots()}).HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, forged.SetHistoricalRoots([][]byte{batch[:]}))
+		path := rewrite(t, forged, nil)
+		require.ErrorContains(t, "of the block at slot 5 does not match the state root", importEra(t, path))
+	})
+	t.Run("forged validator", func(t *testing.T) {
+		forged := st.Copy()
+		v, err := forged.ValidatorAtIndex(3)
+		require.NoError(t, err)
+		v.PublicKey[0] ^= 0xff
+		require.NoError(t, forged.UpdateValidatorAtIndex(3, v))
+		path := rewrite(t, forged, nil)
+		require.ErrorContains(t, "public key of validator 3 does not match the previous era", importEra(t, path))
+	})
+	t.Run("forged historical root", func(t *testing.T) {
+		forged := st.Copy()
+		roots, err := forged.HistoricalRoots()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(roots))
+		require.NoError(t, forged.SetHistoricalRoots([][]byte{make([]byte, 32)}))
+		path := rewrite(t, forged, nil)
+		require.ErrorContains(t, "last historical root does not match", importEra(t, path))
+	})
+	t.Run("forged genesis", func(t *testing.T) {
+		// A genesis state with its own validators, which could sign a whole forged history.
+		forged, _ := util.DeterministicGenesisState(t, 32)
+		path := filepath.Join(t.TempDir(), "forged.era")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		w, err := NewWriter(f, 0)
+		require.NoError(t, err)
+		require.NoError(t, w.WriteState(forged))
+		require.NoError(t, w.Close())
+		require.NoError(t, f.Close())
+		i, err := NewImporter(ctx, testDB.SetupDB(t), nil, gvr)
+		require.NoError(t, err)
+		require.ErrorContains(t, "does not match the genesis validators root", i.Import(ctx, path))
+	})
+}
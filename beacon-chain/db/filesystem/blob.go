@@ -0,0 +1,578 @@
+// Package filesystem implements the storage of blob sidecars as SSZ encoded files, laid out on
+// disk by epoch and block root:
+//
+//	<base>/<epoch>/<0x block root>/<index>.ssz
+//
+// Keeping every epoch in its own directory makes pruning blobs past the retention period a matter
+// of removing directories, instead of rewriting a database.
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/io/file"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// BlobsDirName is the name of the blob storage directory in the data directory of the node.
+	BlobsDirName = "blobs"
+
+	sszExt  = ".ssz"
+	partExt = ".part"
+
+	// sidecarSlotOffset is the offset of the slot in a SSZ encoded blob sidecar, which follows
+	// the 32 bytes block root and the 8 bytes index.
+	sidecarSlotOffset = 40
+)
+
+var (
+	errBlobSlotMismatch     = errors.New("sidecar slot mismatch")
+	errBlobParentMismatch   = errors.New("sidecar parent root mismatch")
+	errBlobRootMismatch     = errors.New("sidecar root mismatch")
+	errBlobProposerMismatch = errors.New("sidecar proposer index mismatch")
+	errBlobSidecarLimit     = errors.New("sidecar exceeds maximum number of blobs")
+	errBlobIndexOutOfRange  = errors.New("sidecar index out of range")
+	errEmptySidecar         = errors.New("nil or empty blob sidecars")
+)
+
+// blobIndices records which sidecars of a block are stored.
+type blobIndices [fieldparams.MaxBlobsPerBlock]bool
+
+type blockBlobs struct {
+	slot    primitives.Slot
+	indices blobIndices
+}
+
+// BlobStorageOption configures a BlobStorage.
+type BlobStorageOption func(*BlobStorage)
+
+// WithBlobRetentionEpochs sets the number of epochs for which blobs are kept, counting back from
+// the current epoch. It defaults to MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS.
+func WithBlobRetentionEpochs(e primitives.Epoch) BlobStorageOption {
+	return func(bs *BlobStorage) {
+		bs.retentionEpochs = e
+	}
+}
+
+// BlobStorage stores blob sidecars on the filesystem. The blocks and indices of the stored
+// sidecars are indexed in memory when the storage is created, so that lookups do not have to
+// touch the disk for blobs which are not there. Blobs are only pruned once the storage knows the
+// genesis clock of the chain, see WaitForClock.
+type BlobStorage struct {
+	base            string
+	retentionEpochs primitives.Epoch
+
+	lock        sync.RWMutex
+	blocks      map[[32]byte]*blockBlobs
+	slots       map[primitives.Slot][][32]byte
+	clock       *startup.Clock
+	prunedEpoch primitives.Epoch
+}
+
+// NewBlobStorage creates the blob storage in the base directory and indexes the blobs it
+// already holds.
+func NewBlobStorage(base string, opts ...BlobStorageOption) (*BlobStorage, error) {
+	bs := &BlobStorage{
+		base:            base,
+		retentionEpochs: params.BeaconNetworkConfig().MinEpochsForBlobsSidecarsRequest,
+		blocks:          make(map[[32]byte]*blockBlobs),
+		slots:           make(map[primitives.Slot][][32]byte),
+	}
+	for _, o := range opts {
+		o(bs)
+	}
+	if err := file.MkdirAll(base); err != nil {
+		return nil, errors.Wrapf(err, "could not create blob storage directory %s", base)
+	}
+	if err := bs.index(); err != nil {
+		return nil, errors.Wrap(err, "could not index blob storage")
+	}
+	return bs, nil
+}
+
+// WaitForClock blocks until the genesis clock of the chain is known, then prunes the blobs which are
+// older than the retention period. Blobs are pruned against the current epoch of the clock from then on.
+func (bs *BlobStorage) WaitForClock(ctx context.Context, cw startup.ClockWaiter) error {
+	clock, err := cw.WaitForClock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not receive genesis clock")
+	}
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	bs.clock = clock
+	return bs.prune()
+}
+
+// Path returns the base directory of the blob storage.
+func (bs *BlobStorage) Path() string {
+	return bs.base
+}
+
+// SaveBlobSidecar saves the sidecars of a block, merging them with the sidecars of the block
+// which are already stored. Every sidecar is written to a temporary file which is renamed once
+// synced, so that a sidecar file is never partially written. The first save of a new epoch prunes
+// the epochs which fell out of the retention period.
+func (bs *BlobStorage) SaveBlobSidecar(ctx context.Context, scs []*zondpb.BlobSidecar) error {
+	_, span := trace.StartSpan(ctx, "BlobStorage.SaveBlobSidecar")
+	defer span.End()
+
+	if len(scs) == 0 {
+		return errEmptySidecar
+	}
+	scs = append([]*zondpb.BlobSidecar(nil), scs...)
+	sortSidecars(scs)
+	scs, err := validUniqueSidecars(scs)
+	if err != nil {
+		return err
+	}
+	if scs[len(scs)-1].Index >= fieldparams.MaxBlobsPerBlock {
+		return errors.Wrapf(errBlobIndexOutOfRange, "%d >= %d", scs[len(scs)-1].Index, fieldparams.MaxBlobsPerBlock)
+	}
+	root := bytesutil.ToBytes32(scs[0].BlockRoot)
+	slot := scs[0].Slot
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	b, ok := bs.blocks[root]
+	if ok && b.slot != slot {
+		return errors.Wrapf(errBlobSlotMismatch, "%d != %d", slot, b.slot)
+	}
+	if !ok {
+		b = &blockBlobs{slot: slot}
+	}
+	dir := bs.blockDir(root, slot)
+	if err := os.MkdirAll(dir, params.BeaconIoConfig().ReadWriteExecutePermissions); err != nil {
+		return err
+	}
+	if !ok {
+		// The directories of a new block, and of a new epoch, are only durable once their parents are synced.
+		for _, d := range []string{filepath.Dir(dir), bs.base} {
+			if err := syncDir(d); err != nil {
+				return errors.Wrap(err, "could not sync blob storage directory")
+			}
+		}
+	}
+	for _, sc := range scs {
+		if b.indices[sc.Index] {
+			continue
+		}
+		if err := writeSidecar(dir, sc); err != nil {
+			return err
+		}
+		if !ok {
+			bs.blocks[root] = b
+			bs.slots[slot] = append(bs.slots[slot], root)
+			ok = true
+		}
+		b.indices[sc.Index] = true
+	}
+	if bs.clock != nil && slots.ToEpoch(bs.clock.CurrentSlot()) > bs.prunedEpoch {
+		if err := bs.prune(); err != nil {
+			log.WithError(err).Error("Could not prune blob storage")
+		}
+	}
+	return nil
+}
+
+// BlobSidecarsByRoot retrieves the blobs for the given beacon block root.
+// If the `indices` argument is omitted, all blobs for the root will be returned.
+// Otherwise, the result will be filtered to only include the specified indices.
+// An error wrapping db.ErrNotFound is returned if a requested index is not stored.
+func (bs *BlobStorage) BlobSidecarsByRoot(ctx context.Context, root [32]byte, indices ...uint64) ([]*zondpb.BlobSidecar, error) {
+	_, span := trace.StartSpan(ctx, "BlobStorage.BlobSidecarsByRoot")
+	defer span.End()
+
+	bs.lock.RLock()
+	b, ok := bs.blocks[root]
+	var entry blockBlobs
+	if ok {
+		entry = *b
+	}
+	bs.lock.RUnlock()
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return bs.readSidecars(root, entry, indices...)
+}
+
+// BlobSidecarsBySlot retrieves the blobs of the block at the given slot.
+// If the `indices` argument is omitted, all blobs for the block will be returned.
+// Otherwise, the result will be filtered to only include the specified indices.
+// An error wrapping db.ErrNotFound is returned if a requested index is not stored.
+func (bs *BlobStorage) BlobSidecarsBySlot(ctx context.Context, slot primitives.Slot, indices ...uint64) ([]*zondpb.BlobSidecar, error) {
+	_, span := trace.StartSpan(ctx, "BlobStorage.BlobSidecarsBySlot")
+	defer span.End()
+
+	bs.lock.RLock()
+	roots := bs.slots[slot]
+	var root [32]byte
+	var entry blockBlobs
+	if len(roots) > 0 {
+		// Blobs of equivocating blocks are kept apart, the blobs of the first block seen at the
+		// slot are returned.
+		root = roots[0]
+		entry = *bs.blocks[root]
+	}
+	bs.lock.RUnlock()
+	if len(roots) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return bs.readSidecars(root, entry, indices...)
+}
+
+// DeleteBlobSidecar removes the blobs of the given beacon block root.
+func (bs *BlobStorage) DeleteBlobSidecar(ctx context.Context, root [32]byte) error {
+	_, span := trace.StartSpan(ctx, "BlobStorage.DeleteBlobSidecar")
+	defer span.End()
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	b, ok := bs.blocks[root]
+	if !ok {
+		return nil
+	}
+	if err := os.RemoveAll(bs.blockDir(root, b.slot)); err != nil {
+		return errors.Wrapf(err, "could not remove blobs of block %#x", root)
+	}
+	bs.removeFromIndex(root, b.slot)
+	return nil
+}
+
+func (bs *BlobStorage) readSidecars(root [32]byte, b blockBlobs, indices ...uint64) ([]*zondpb.BlobSidecar, error) {
+	if len(indices) == 0 {
+		for i, stored := range b.indices {
+			if stored {
+				indices = append(indices, uint64(i))
+			}
+		}
+	}
+	dir := bs.blockDir(root, b.slot)
+	scs := make([]*zondpb.BlobSidecar, len(indices))
+	for i, idx := range indices {
+		if idx >= fieldparams.MaxBlobsPerBlock || !b.indices[idx] {
+			return nil, errors.Wrapf(db.ErrNotFound, "BlobSidecars missing index: index %d", idx)
+		}
+		enc, err := os.ReadFile(filepath.Join(dir, sidecarFileName(idx)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The blobs were pruned or deleted after being looked up.
+				return nil, errors.Wrapf(db.ErrNotFound, "BlobSidecars missing index: index %d", idx)
+			}
+			return nil, err
+		}
+		sc := &zondpb.BlobSidecar{}
+		if err := sc.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal blob sidecar %d of block %#x", idx, root)
+		}
+		scs[i] = sc
+	}
+	return scs, nil
+}
+
+// prune removes the epochs which are older than the retention period, counting back from the
+// current epoch. Blobs are not pruned before the clock is known. It is called with the lock held.
+func (bs *BlobStorage) prune() error {
+	if bs.clock == nil {
+		return nil
+	}
+	current := slots.ToEpoch(bs.clock.CurrentSlot())
+	bs.prunedEpoch = current
+	if current <= bs.retentionEpochs {
+		return nil
+	}
+	minEpoch := current - bs.retentionEpochs
+	entries, err := os.ReadDir(bs.base)
+	if err != nil {
+		return err
+	}
+	var pruned int
+	for _, e := range entries {
+		epoch, ok := parseEpochDir(e)
+		if !ok || epoch >= minEpoch {
+			continue
+		}
+		epochDir := filepath.Join(bs.base, e.Name())
+		rootDirs, err := os.ReadDir(epochDir)
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(epochDir); err != nil {
+			return err
+		}
+		for _, rd := range rootDirs {
+			root, ok := parseRootDir(rd)
+			if !ok {
+				continue
+			}
+			if b, ok := bs.blocks[root]; ok {
+				bs.removeFromIndex(root, b.slot)
+				pruned++
+			}
+		}
+	}
+	if pruned > 0 {
+		log.WithField("blocks", pruned).WithField("minimumEpoch", minEpoch).Debug("Pruned blobs")
+	}
+	return nil
+}
+
+// index walks the blob storage directory to build the in-memory index of the stored blobs.
+// Temporary files left by an interrupted write are removed.
+func (bs *BlobStorage) index() error {
+	epochDirs, err := os.ReadDir(bs.base)
+	if err != nil {
+		return err
+	}
+	for _, ed := range epochDirs {
+		if _, ok := parseEpochDir(ed); !ok {
+			continue
+		}
+		rootDirs, err := os.ReadDir(filepath.Join(bs.base, ed.Name()))
+		if err != nil {
+			return err
+		}
+		for _, rd := range rootDirs {
+			root, ok := parseRootDir(rd)
+			if !ok {
+				continue
+			}
+			dir := filepath.Join(bs.base, ed.Name(), rd.Name())
+			b, err := indexBlockDir(dir)
+			if err != nil {
+				return err
+			}
+			if b == nil {
+				continue
+			}
+			bs.blocks[root] = b
+			bs.slots[b.slot] = append(bs.slots[b.slot], root)
+		}
+	}
+	if len(bs.blocks) > 0 {
+		log.WithField("blocks", len(bs.blocks)).WithField("path", bs.base).Info("Indexed blob storage")
+	}
+	return nil
+}
+
+func indexBlockDir(dir string) (*blockBlobs, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var b *blockBlobs
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasSuffix(name, partExt) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		idx, err := strconv.ParseUint(strings.TrimSuffix(name, sszExt), 10, 64)
+		if err != nil || !strings.HasSuffix(name, sszExt) || idx >= fieldparams.MaxBlobsPerBlock {
+			continue
+		}
+		if b == nil {
+			slot, err := readSidecarSlot(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			b = &blockBlobs{slot: slot}
+		}
+		b.indices[idx] = true
+	}
+	return b, nil
+}
+
+func readSidecarSlot(path string) (primitives.Slot, error) {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close blob sidecar file")
+		}
+	}()
+	var slot [8]byte
+	if _, err := f.ReadAt(slot[:], sidecarSlotOffset); err != nil {
+		if err == io.EOF {
+			return 0, errors.Errorf("blob sidecar file %s is truncated", path)
+		}
+		return 0, err
+	}
+	return primitives.Slot(bytesutil.FromBytes8(slot[:])), nil
+}
+
+func writeSidecar(dir string, sc *zondpb.BlobSidecar) error {
+	enc, err := sc.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal blob sidecar")
+	}
+	name := sidecarFileName(sc.Index)
+	f, err := os.CreateTemp(dir, name+".*"+partExt)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(enc); err != nil {
+		return cleanupPartial(f, err)
+	}
+	if err := f.Sync(); err != nil {
+		return cleanupPartial(f, err)
+	}
+	if err := f.Close(); err != nil {
+		return cleanupPartial(nil, err, tmp)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		return cleanupPartial(nil, err, tmp)
+	}
+	// The rename is only durable once the directory entry is synced.
+	return errors.Wrap(syncDir(dir), "could not sync blob sidecar directory")
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir) // #nosec G304
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		if closeErr := d.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close blob sidecar directory")
+		}
+		return err
+	}
+	return d.Close()
+}
+
+func cleanupPartial(f *os.File, err error, paths ...string) error {
+	if f != nil {
+		paths = append(paths, f.Name())
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close partial blob sidecar file")
+		}
+	}
+	for _, p := range paths {
+		if rmErr := os.Remove(p); rmErr != nil {
+			log.WithError(rmErr).Error("Could not remove partial blob sidecar file")
+		}
+	}
+	return errors.Wrap(err, "could not write blob sidecar")
+}
+
+func (bs *BlobStorage) removeFromIndex(root [32]byte, slot primitives.Slot) {
+	delete(bs.blocks, root)
+	roots := bs.slots[slot]
+	for i, r := range roots {
+		if r == root {
+			roots = append(roots[:i], roots[i+1:]...)
+			break
+		}
+	}
+	if len(roots) == 0 {
+		delete(bs.slots, slot)
+		return
+	}
+	bs.slots[slot] = roots
+}
+
+func (bs *BlobStorage) blockDir(root [32]byte, slot primitives.Slot) string {
+	return filepath.Join(bs.base, strconv.FormatUint(uint64(slots.ToEpoch(slot)), 10), fmt.Sprintf("%#x", root))
+}
+
+func sidecarFileName(index uint64) string {
+	return strconv.FormatUint(index, 10) + sszExt
+}
+
+func parseEpochDir(e os.DirEntry) (primitives.Epoch, bool) {
+	if !e.IsDir() {
+		return 0, false
+	}
+	epoch, err := strconv.ParseUint(e.Name(), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return primitives.Epoch(epoch), true
+}
+
+func parseRootDir(e os.DirEntry) ([32]byte, bool) {
+	if !e.IsDir() {
+		return [32]byte{}, false
+	}
+	root, err := hexutil.Decode(e.Name())
+	if err != nil || len(root) != 32 {
+		return [32]byte{}, false
+	}
+	return bytesutil.ToBytes32(root), true
+}
+
+// validUniqueSidecars ensures that all sidecars have the same slot, parent root, block root, and proposer index, and no more than MAX_BLOBS_PER_BLOCK.
+func validUniqueSidecars(scs []*zondpb.BlobSidecar) ([]*zondpb.BlobSidecar, error) {
+	if len(scs) == 0 {
+		return nil, errEmptySidecar
+	}
+
+	// If there's only 1 sidecar, we've got nothing to compare.
+	if len(scs) == 1 {
+		return scs, nil
+	}
+
+	prev := scs[0]
+	didx := 1
+	for i := 1; i < len(scs); i++ {
+		sc := scs[i]
+		if sc.Slot != prev.Slot {
+			return nil, errors.Wrapf(errBlobSlotMismatch, "%d != %d", sc.Slot, prev.Slot)
+		}
+		if !bytes.Equal(sc.BlockParentRoot, prev.BlockParentRoot) {
+			return nil, errors.Wrapf(errBlobParentMismatch, "%x != %x", sc.BlockParentRoot, prev.BlockParentRoot)
+		}
+		if !bytes.Equal(sc.BlockRoot, prev.BlockRoot) {
+			return nil, errors.Wrapf(errBlobRootMismatch, "%x != %x", sc.BlockRoot, prev.BlockRoot)
+		}
+		if sc.ProposerIndex != prev.ProposerIndex {
+			return nil, errors.Wrapf(errBlobProposerMismatch, "%d != %d", sc.ProposerIndex, prev.ProposerIndex)
+		}
+		// skip duplicate
+		if sc.Index == prev.Index {
+			continue
+		}
+		if didx != i {
+			scs[didx] = scs[i]
+		}
+		prev = scs[i]
+		didx += 1
+	}
+
+	if didx > fieldparams.MaxBlobsPerBlock {
+		return nil, errors.Wrapf(errBlobSidecarLimit, "%d > %d", didx, fieldparams.MaxBlobsPerBlock)
+	}
+	return scs[0:didx], nil
+}
+
+// sortSidecars sorts the sidecars by their index.
+func sortSidecars(scs []*zondpb.BlobSidecar) {
+	sort.Slice(scs, func(i, j int) bool {
+		return scs[i].Index < scs[j].Index
+	})
+}
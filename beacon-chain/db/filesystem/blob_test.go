@@ -0,0 +1,414 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assertions"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+func setupBlobStorage(t testing.TB, opts ...BlobStorageOption) *BlobStorage {
+	bs, err := NewBlobStorage(filepath.Join(t.TempDir(), BlobsDirName), opts...)
+	require.NoError(t, err)
+	return bs
+}
+
+func equalBlobSlices(expect []*zondpb.BlobSidecar, got []*zondpb.BlobSidecar) error {
+	if len(expect) != len(got) {
+		return fmt.Errorf("mismatched lengths, expect=%d, got=%d", len(expect), len(got))
+	}
+	for i := 0; i < len(expect); i++ {
+		es := expect[i]
+		gs := got[i]
+		var e string
+		assertions.DeepEqual(assertions.SprintfAssertionLoggerFn(&e), es, gs)
+		if e != "" {
+			return errors.New(e)
+		}
+	}
+	return nil
+}
+
+func TestBlobStorage_BlobSidecars(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, 0)
+		require.ErrorContains(t, "nil or empty blob sidecars", bs.SaveBlobSidecar(ctx, scs))
+	})
+	t.Run("empty by root", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		got, err := bs.BlobSidecarsByRoot(ctx, [32]byte{})
+		require.ErrorIs(t, err, db.ErrNotFound)
+		require.Equal(t, 0, len(got))
+	})
+	t.Run("empty by slot", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		got, err := bs.BlobSidecarsBySlot(ctx, 1)
+		require.ErrorIs(t, err, db.ErrNotFound)
+		require.Equal(t, 0, len(got))
+	})
+	t.Run("save and retrieve by root (one)", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, 1)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot))
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+	})
+	t.Run("save and retrieve by root (max), per batch", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot))
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+	})
+	t.Run("save and retrieve by root, max and individually", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+		// Sidecars saved out of order are returned in index order.
+		for i := len(scs) - 1; i >= 0; i-- {
+			require.NoError(t, bs.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{scs[i]}))
+		}
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot))
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+	})
+	t.Run("save and retrieve valid subset by root", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot), 0, 3)
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices([]*zondpb.BlobSidecar{scs[0], scs[3]}, got))
+	})
+	t.Run("error for invalid index when retrieving by root", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, 2)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot), uint64(len(scs)))
+		require.ErrorIs(t, err, db.ErrNotFound)
+		require.Equal(t, 0, len(got))
+		_, err = bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot), fieldparams.MaxBlobsPerBlock)
+		require.ErrorIs(t, err, db.ErrNotFound)
+	})
+	t.Run("save and retrieve by slot", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+		got, err := bs.BlobSidecarsBySlot(ctx, scs[0].Slot)
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+
+		got, err = bs.BlobSidecarsBySlot(ctx, scs[0].Slot, 0, 3)
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices([]*zondpb.BlobSidecar{scs[0], scs[3]}, got))
+
+		_, err = bs.BlobSidecarsBySlot(ctx, scs[0].Slot+1)
+		require.ErrorIs(t, err, db.ErrNotFound)
+	})
+	t.Run("delete works", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs))
+		root := bytesutil.ToBytes32(scs[0].BlockRoot)
+		require.NoError(t, bs.DeleteBlobSidecar(ctx, root))
+		got, err := bs.BlobSidecarsByRoot(ctx, root)
+		require.ErrorIs(t, err, db.ErrNotFound)
+		require.Equal(t, 0, len(got))
+		_, err = bs.BlobSidecarsBySlot(ctx, scs[0].Slot)
+		require.ErrorIs(t, err, db.ErrNotFound)
+		_, err = os.Stat(bs.blockDir(root, scs[0].Slot))
+		require.Equal(t, true, os.IsNotExist(err))
+		// Deleting missing blobs is not an error.
+		require.NoError(t, bs.DeleteBlobSidecar(ctx, root))
+	})
+	t.Run("slot mismatch with stored sidecars", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, 2)
+		require.NoError(t, bs.SaveBlobSidecar(ctx, scs[:1]))
+		scs[1].Slot++
+		require.ErrorIs(t, bs.SaveBlobSidecar(ctx, scs[1:]), errBlobSlotMismatch)
+	})
+	t.Run("index out of range", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, 1)
+		scs[0].Index = fieldparams.MaxBlobsPerBlock
+		require.ErrorIs(t, bs.SaveBlobSidecar(ctx, scs), errBlobIndexOutOfRange)
+	})
+	t.Run("save equivocating blobs", func(t *testing.T) {
+		bs := setupBlobStorage(t)
+		scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock/2)
+		eScs := generateEquivocatingBlobSidecars(t, fieldparams.MaxBlobsPerBlock/2)
+
+		for i, sc := range scs {
+			require.NoError(t, bs.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{sc}))
+			require.NoError(t, bs.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{eScs[i]}))
+		}
+
+		got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(scs[0].BlockRoot))
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+
+		got, err = bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(eScs[0].BlockRoot))
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(eScs, got))
+
+		got, err = bs.BlobSidecarsBySlot(ctx, scs[0].Slot)
+		require.NoError(t, err)
+		require.NoError(t, equalBlobSlices(scs, got))
+	})
+}
+
+func TestBlobStorage_Index(t *testing.T) {
+	ctx := context.Background()
+	base := filepath.Join(t.TempDir(), BlobsDirName)
+	bs, err := NewBlobStorage(base)
+	require.NoError(t, err)
+	scs := generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock)
+	require.NoError(t, bs.SaveBlobSidecar(ctx, []*zondpb.BlobSidecar{scs[0], scs[2]}))
+	root := bytesutil.ToBytes32(scs[0].BlockRoot)
+
+	// A write interrupted before its rename leaves a partial file behind.
+	partial := filepath.Join(bs.blockDir(root, scs[0].Slot), "1.ssz.123"+partExt)
+	require.NoError(t, os.WriteFile(partial, []byte{1, 2, 3}, 0600))
+	// Unknown entries of the blob storage directory are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(base, "README"), []byte{}, 0600))
+
+	bs, err = NewBlobStorage(base)
+	require.NoError(t, err)
+	_, err = os.Stat(partial)
+	require.Equal(t, true, os.IsNotExist(err), "Partial file was not removed")
+	got, err := bs.BlobSidecarsBySlot(ctx, scs[0].Slot)
+	require.NoError(t, err)
+	require.NoError(t, equalBlobSlices([]*zondpb.BlobSidecar{scs[0], scs[2]}, got))
+	_, err = bs.BlobSidecarsByRoot(ctx, root, 1)
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
+
+func TestBlobStorage_Prune(t *testing.T) {
+	ctx := context.Background()
+	const retention = primitives.Epoch(10)
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	genesis := time.Now()
+	var now time.Time
+	// setEpoch moves the clock to the start of the given epoch.
+	setEpoch := func(e primitives.Epoch) {
+		now = genesis.Add(time.Duration(uint64(e)*uint64(slotsPerEpoch)*params.BeaconConfig().SecondsPerSlot) * time.Second)
+	}
+	cs := startup.NewClockSynchronizer()
+	require.NoError(t, cs.SetClock(startup.NewClock(genesis, [32]byte{}, startup.WithNower(func() time.Time { return now }))))
+	base := filepath.Join(t.TempDir(), BlobsDirName)
+	bs, err := NewBlobStorage(base, WithBlobRetentionEpochs(retention))
+	require.NoError(t, err)
+
+	old := generateBlobSidecars(t, 2)
+	oldRoot := bytesutil.ToBytes32(old[0].BlockRoot)
+	oldEpoch := slots.ToEpoch(old[0].Slot)
+	kept := generateBlobSidecars(t, 1)
+	kept[0].BlockRoot = bytesutil.PadTo([]byte{'k'}, 32)
+	kept[0].Slot = old[0].Slot + primitives.Slot(retention.Mul(uint64(slotsPerEpoch)))
+	newer := generateBlobSidecars(t, 1)
+	newer[0].BlockRoot = bytesutil.PadTo([]byte{'n'}, 32)
+	newer[0].Slot = kept[0].Slot + slotsPerEpoch
+
+	// Blobs are not pruned before the clock is known, whatever the epochs of the saved blobs.
+	setEpoch(oldEpoch + retention + 1)
+	require.NoError(t, bs.SaveBlobSidecar(ctx, old))
+	require.NoError(t, bs.SaveBlobSidecar(ctx, newer))
+	_, err = bs.BlobSidecarsByRoot(ctx, oldRoot)
+	require.NoError(t, err)
+	require.NoError(t, bs.DeleteBlobSidecar(ctx, bytesutil.ToBytes32(newer[0].BlockRoot)))
+
+	// Blobs of the last retention epochs are kept.
+	setEpoch(oldEpoch + retention)
+	require.NoError(t, bs.WaitForClock(ctx, cs))
+	require.NoError(t, bs.SaveBlobSidecar(ctx, kept))
+	_, err = bs.BlobSidecarsByRoot(ctx, oldRoot)
+	require.NoError(t, err)
+
+	// The first save of the next epoch prunes the oldest epoch, even if the saved blobs are older.
+	setEpoch(oldEpoch + retention + 1)
+	older := generateBlobSidecars(t, 1)
+	older[0].BlockRoot = bytesutil.PadTo([]byte{'o'}, 32)
+	older[0].Slot = kept[0].Slot - 1
+	require.NoError(t, bs.SaveBlobSidecar(ctx, older))
+	_, err = bs.BlobSidecarsByRoot(ctx, oldRoot)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = bs.BlobSidecarsBySlot(ctx, old[0].Slot)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = os.Stat(filepath.Join(base, fmt.Sprintf("%d", oldEpoch)))
+	require.Equal(t, true, os.IsNotExist(err), "Epoch directory was not removed")
+	_, err = bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(kept[0].BlockRoot))
+	require.NoError(t, err)
+	require.NoError(t, bs.SaveBlobSidecar(ctx, newer))
+
+	// A shorter retention period prunes the blob storage once the clock is known.
+	bs, err = NewBlobStorage(base, WithBlobRetentionEpochs(0))
+	require.NoError(t, err)
+	_, err = bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(kept[0].BlockRoot))
+	require.NoError(t, err)
+	require.NoError(t, bs.WaitForClock(ctx, cs))
+	_, err = bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(kept[0].BlockRoot))
+	require.ErrorIs(t, err, db.ErrNotFound)
+	got, err := bs.BlobSidecarsByRoot(ctx, bytesutil.ToBytes32(newer[0].BlockRoot))
+	require.NoError(t, err)
+	require.NoError(t, equalBlobSlices(newer, got))
+}
+
+func generateBlobSidecars(t *testing.T, n uint64) []*zondpb.BlobSidecar {
+	blobSidecars := make([]*zondpb.BlobSidecar, n)
+	for i := uint64(0); i < n; i++ {
+		blobSidecars[i] = generateBlobSidecar(t, i)
+	}
+	return blobSidecars
+}
+
+func generateBlobSidecar(t *testing.T, index uint64) *zondpb.BlobSidecar {
+	blob := make([]byte, 131072)
+	_, err := rand.Read(blob)
+	require.NoError(t, err)
+	kzgCommitment := make([]byte, 48)
+	_, err = rand.Read(kzgCommitment)
+	require.NoError(t, err)
+	kzgProof := make([]byte, 48)
+	_, err = rand.Read(kzgProof)
+	require.NoError(t, err)
+	return &zondpb.BlobSidecar{
+		BlockRoot:       bytesutil.PadTo([]byte{'a'}, 32),
+		Index:           index,
+		Slot:            100,
+		BlockParentRoot: bytesutil.PadTo([]byte{'b'}, 32),
+		ProposerIndex:   101,
+		Blob:            blob,
+		KzgCommitment:   kzgCommitment,
+		KzgProof:        kzgProof,
+	}
+}
+
+func generateEquivocatingBlobSidecars(t *testing.T, n uint64) []*zondpb.BlobSidecar {
+	blobSidecars := make([]*zondpb.BlobSidecar, n)
+	for i := uint64(0); i < n; i++ {
+		blobSidecars[i] = generateBlobSidecar(t, i)
+		blobSidecars[i].BlockRoot = bytesutil.PadTo([]byte{'c'}, 32)
+		blobSidecars[i].ProposerIndex = 102
+	}
+	return blobSidecars
+}
+
+func Test_validUniqueSidecars_validation(t *testing.T) {
+	tests := []struct {
+		name string
+		scs  []*zondpb.BlobSidecar
+		err  error
+	}{
+		{name: "empty", scs: []*zondpb.BlobSidecar{}, err: errEmptySidecar},
+		{name: "too many sidecars", scs: generateBlobSidecars(t, fieldparams.MaxBlobsPerBlock+1), err: errBlobSidecarLimit},
+		{name: "invalid slot", scs: []*zondpb.BlobSidecar{{Slot: 1}, {Slot: 2}}, err: errBlobSlotMismatch},
+		{name: "invalid proposer index", scs: []*zondpb.BlobSidecar{{ProposerIndex: 1}, {ProposerIndex: 2}}, err: errBlobProposerMismatch},
+		{name: "invalid root", scs: []*zondpb.BlobSidecar{{BlockRoot: []byte{1}}, {BlockRoot: []byte{2}}}, err: errBlobRootMismatch},
+		{name: "invalid parent root", scs: []*zondpb.BlobSidecar{{BlockParentRoot: []byte{1}}, {BlockParentRoot: []byte{2}}}, err: errBlobParentMismatch},
+		{name: "happy path", scs: []*zondpb.BlobSidecar{{Index: 0}, {Index: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validUniqueSidecars(tt.scs)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validUniqueSidecars_dedup(t *testing.T) {
+	cases := []struct {
+		name     string
+		scs      []*zondpb.BlobSidecar
+		expected []*zondpb.BlobSidecar
+		err      error
+	}{
+		{
+			name:     "duplicate sidecar",
+			scs:      []*zondpb.BlobSidecar{{Index: 1}, {Index: 1}},
+			expected: []*zondpb.BlobSidecar{{Index: 1}},
+		},
+		{
+			name:     "single sidecar",
+			scs:      []*zondpb.BlobSidecar{{Index: 1}},
+			expected: []*zondpb.BlobSidecar{{Index: 1}},
+		},
+		{
+			name:     "multiple duplicates",
+			scs:      []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 2}, {Index: 3}, {Index: 3}},
+			expected: []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 3}},
+		},
+		{
+			name:     "ok number after de-dupe, > 6 before",
+			scs:      []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 2}, {Index: 2}, {Index: 2}, {Index: 3}, {Index: 3}},
+			expected: []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 3}},
+		},
+		{
+			name:     "max unique, no dupes",
+			scs:      []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}, {Index: 5}, {Index: 6}},
+			expected: []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}, {Index: 5}, {Index: 6}},
+		},
+		{
+			name: "too many unique",
+			scs:  []*zondpb.BlobSidecar{{Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}, {Index: 5}, {Index: 6}, {Index: 7}},
+			err:  errBlobSidecarLimit,
+		},
+		{
+			name: "too many unique with dupes",
+			scs:  []*zondpb.BlobSidecar{{Index: 1}, {Index: 1}, {Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}, {Index: 5}, {Index: 6}, {Index: 7}},
+			err:  errBlobSidecarLimit,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			u, err := validUniqueSidecars(c.scs)
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, len(c.expected), len(u))
+		})
+	}
+}
+
+func TestBlobStorage_sortSidecars(t *testing.T) {
+	scs := []*zondpb.BlobSidecar{
+		{Index: 6},
+		{Index: 4},
+		{Index: 2},
+		{Index: 1},
+		{Index: 3},
+		{Index: 5},
+		{},
+	}
+	sortSidecars(scs)
+	for i := 0; i < len(scs)-1; i++ {
+		require.Equal(t, uint64(i), scs[i].Index)
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/filters"
 	slashertypes "github.com/theQRL/qrysm/v4/beacon-chain/slasher/types"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/monitoring/backup"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 )
 
 // ReadOnlyDatabase defines a struct which only has read access to database methods.
@@ -39,6 +41,9 @@ type ReadOnlyDatabase interface {
 	StateSummary(ctx context.Context, blockRoot [32]byte) (*zondpb.StateSummary, error)
 	HasStateSummary(ctx context.Context, blockRoot [32]byte) bool
 	HighestSlotStatesBelow(ctx context.Context, slot primitives.Slot) ([]state.ReadOnlyBeaconState, error)
+	StateDiff(ctx context.Context, slot primitives.Slot) ([]byte, error)
+	StateDiffSlot(ctx context.Context, blockRoot [32]byte) (primitives.Slot, error)
+	LowestStateDiffSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, error)
 	// Checkpoint operations.
 	JustifiedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, error)
 	FinalizedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, error)
@@ -55,13 +60,15 @@ type ReadOnlyDatabase interface {
 	FeeRecipientByValidatorID(ctx context.Context, id primitives.ValidatorIndex) (common.Address, error)
 	RegistrationByValidatorID(ctx context.Context, id primitives.ValidatorIndex) (*zondpb.ValidatorRegistrationV1, error)
 
-	// Blob operations.
-	BlobSidecarsByRoot(ctx context.Context, beaconBlockRoot [32]byte, indices ...uint64) ([]*zondpb.BlobSidecar, error)
-	BlobSidecarsBySlot(ctx context.Context, slot primitives.Slot, indices ...uint64) ([]*zondpb.BlobSidecar, error)
-
 	// origin checkpoint sync support
 	OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	BackfillBlockRoot(ctx context.Context) ([32]byte, error)
+	// history pruning support
+	HistoryPrunedSlot(ctx context.Context) (primitives.Slot, error)
+
+	// Light client operations.
+	LightClientUpdate(ctx context.Context, period uint64) (*zondpbv2.LightClientUpdate, error)
+	LightClientUpdates(ctx context.Context, startPeriod, endPeriod uint64) (map[uint64]*zondpbv2.LightClientUpdate, error)
 }
 
 // NoHeadAccessDatabase defines a struct without access to chain head data.
@@ -80,6 +87,7 @@ type NoHeadAccessDatabase interface {
 	DeleteStates(ctx context.Context, blockRoots [][32]byte) error
 	SaveStateSummary(ctx context.Context, summary *zondpb.StateSummary) error
 	SaveStateSummaries(ctx context.Context, summaries []*zondpb.StateSummary) error
+	SaveStateDiff(ctx context.Context, slot primitives.Slot, blockRoot [32]byte, enc []byte) error
 	// Checkpoint operations.
 	SaveJustifiedCheckpoint(ctx context.Context, checkpoint *zondpb.Checkpoint) error
 	SaveFinalizedCheckpoint(ctx context.Context, checkpoint *zondpb.Checkpoint) error
@@ -94,11 +102,11 @@ type NoHeadAccessDatabase interface {
 	SaveFeeRecipientsByValidatorIDs(ctx context.Context, ids []primitives.ValidatorIndex, addrs []common.Address) error
 	SaveRegistrationsByValidatorIDs(ctx context.Context, ids []primitives.ValidatorIndex, regs []*zondpb.ValidatorRegistrationV1) error
 
-	// Blob operations.
-	SaveBlobSidecar(ctx context.Context, sidecars []*zondpb.BlobSidecar) error
-	DeleteBlobSidecar(ctx context.Context, beaconBlockRoot [32]byte) error
+	// Light client operations.
+	SaveLightClientUpdate(ctx context.Context, period uint64, update *zondpbv2.LightClientUpdate) error
 
 	CleanUpDirtyStates(ctx context.Context, slotsPerArchivedPoint primitives.Slot) error
+	PruneHistory(ctx context.Context, beforeSlot primitives.Slot) (int, error)
 }
 
 // HeadAccessDatabase defines a struct with access to reading chain head data.
@@ -117,6 +125,7 @@ type HeadAccessDatabase interface {
 	// initialization method needed for origin checkpoint sync
 	SaveOrigin(ctx context.Context, serState, serBlock []byte) error
 	SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error
+	SaveBackfillBlocks(ctx context.Context, blks []blocks.ROBlock, childRoot [32]byte) error
 }
 
 // SlasherDatabase interface for persisting data related to detecting slashable offenses on Ethereum.
@@ -175,4 +184,16 @@ type Database interface {
 
 	DatabasePath() string
 	ClearDB() error
+
+	// MigrateBlobSidecars moves the blob sidecars stored in the database into the blob storage.
+	MigrateBlobSidecars(ctx context.Context, dst BlobStorage) error
+}
+
+// BlobStorage defines the methods to store and read blob sidecars, which are kept outside of the
+// database.
+type BlobStorage interface {
+	BlobSidecarsByRoot(ctx context.Context, beaconBlockRoot [32]byte, indices ...uint64) ([]*zondpb.BlobSidecar, error)
+	BlobSidecarsBySlot(ctx context.Context, slot primitives.Slot, indices ...uint64) ([]*zondpb.BlobSidecar, error)
+	SaveBlobSidecar(ctx context.Context, sidecars []*zondpb.BlobSidecar) error
+	DeleteBlobSidecar(ctx context.Context, beaconBlockRoot [32]byte) error
 }
@@ -13,7 +13,7 @@ import (
 )
 
 func TestStore_Backup(t *testing.T) {
-	db, err := NewKVStore(context.Background(), t.TempDir())
+	db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(testBackend()))
 	require.NoError(t, err, "Failed to instantiate DB")
 	ctx := context.Background()
 
@@ -39,12 +39,12 @@ func TestStore_Backup(t *testing.T) {
 	require.NoError(t, db.Close(), "Failed to close database")
 
 	oldFilePath := filepath.Join(backupsPath, files[0].Name())
-	newFilePath := filepath.Join(backupsPath, DatabaseFileName)
+	newFilePath := KVStoreDatapath(backupsPath, testBackend())
 	// We rename the file to match the database file name
 	// our NewKVStore function expects when opening a database.
 	require.NoError(t, os.Rename(oldFilePath, newFilePath))
 
-	backedDB, err := NewKVStore(ctx, backupsPath)
+	backedDB, err := NewKVStore(ctx, backupsPath, WithBackend(testBackend()))
 	require.NoError(t, err, "Failed to instantiate DB")
 	t.Cleanup(func() {
 		require.NoError(t, backedDB.Close(), "Failed to close database")
@@ -53,7 +53,7 @@ func TestStore_Backup(t *testing.T) {
 }
 
 func TestStore_BackupMultipleBuckets(t *testing.T) {
-	db, err := NewKVStore(context.Background(), t.TempDir())
+	db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(testBackend()))
 	require.NoError(t, err, "Failed to instantiate DB")
 	ctx := context.Background()
 
@@ -83,12 +83,12 @@ func TestStore_BackupMultipleBuckets(t *testing.T) {
 	require.NoError(t, db.Close(), "Failed to close database")
 
 	oldFilePath := filepath.Join(backupsPath, files[0].Name())
-	newFilePath := filepath.Join(backupsPath, DatabaseFileName)
+	newFilePath := KVStoreDatapath(backupsPath, testBackend())
 	// We rename the file to match the database file name
 	// our NewKVStore function expects when opening a database.
 	require.NoError(t, os.Rename(oldFilePath, newFilePath))
 
-	backedDB, err := NewKVStore(ctx, backupsPath)
+	backedDB, err := NewKVStore(ctx, backupsPath, WithBackend(testBackend()))
 	require.NoError(t, err, "Failed to instantiate DB")
 	t.Cleanup(func() {
 		require.NoError(t, backedDB.Close(), "Failed to close database")
@@ -10,6 +10,7 @@ import (
 	ssz "github.com/prysmaticlabs/fastssz"
 	"github.com/theQRL/go-zond/common"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/filters"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
@@ -19,7 +20,6 @@ import (
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/runtime/version"
 	"github.com/theQRL/qrysm/v4/time/slots"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -35,7 +35,7 @@ func (s *Store) Block(ctx context.Context, blockRoot [32]byte) (interfaces.ReadO
 		return v.(interfaces.ReadOnlySignedBeaconBlock), nil
 	}
 	var blk interfaces.ReadOnlySignedBeaconBlock
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -57,7 +57,7 @@ func (s *Store) OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	defer span.End()
 
 	var root [32]byte
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		rootSlice := bkt.Get(originCheckpointBlockRootKey)
 		if rootSlice == nil {
@@ -70,13 +70,13 @@ func (s *Store) OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	return root, err
 }
 
-// BackfillBlockRoot keeps track of the highest block available before the OriginCheckpointBlockRoot
+// BackfillBlockRoot keeps track of the lowest block backfilled below the OriginCheckpointBlockRoot
 func (s *Store) BackfillBlockRoot(ctx context.Context) ([32]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BackfillBlockRoot")
 	defer span.End()
 
 	var root [32]byte
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		rootSlice := bkt.Get(backfillBlockRootKey)
 		if len(rootSlice) == 0 {
@@ -94,7 +94,7 @@ func (s *Store) HeadBlock(ctx context.Context) (interfaces.ReadOnlySignedBeaconB
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadBlock")
 	defer span.End()
 	var headBlock interfaces.ReadOnlySignedBeaconBlock
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		headRoot := bkt.Get(headBlockRootKey)
 		if headRoot == nil {
@@ -118,7 +118,7 @@ func (s *Store) Blocks(ctx context.Context, f *filters.QueryFilter) ([]interface
 	blocks := make([]interfaces.ReadOnlySignedBeaconBlock, 0)
 	blockRoots := make([][32]byte, 0)
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 
 		keys, err := blockRootsByFilter(ctx, tx, f)
@@ -149,7 +149,7 @@ func (s *Store) BlockRoots(ctx context.Context, f *filters.QueryFilter) ([][32]b
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockRoots")
 	defer span.End()
 	blockRoots := make([][32]byte, 0)
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		keys, err := blockRootsByFilter(ctx, tx, f)
 		if err != nil {
 			return err
@@ -174,7 +174,7 @@ func (s *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 		return true
 	}
 	exists := false
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		exists = bkt.Get(blockRoot[:]) != nil
 		return nil
@@ -190,7 +190,7 @@ func (s *Store) BlocksBySlot(ctx context.Context, slot primitives.Slot) ([]inter
 	defer span.End()
 
 	blocks := make([]interfaces.ReadOnlySignedBeaconBlock, 0)
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		roots, err := blockRootsBySlot(ctx, tx, slot)
 		if err != nil {
@@ -214,7 +214,7 @@ func (s *Store) BlockRootsBySlot(ctx context.Context, slot primitives.Slot) (boo
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockRootsBySlot")
 	defer span.End()
 	blockRoots := make([][32]byte, 0)
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		var err error
 		blockRoots, err = blockRootsBySlot(ctx, tx, slot)
 		return err
@@ -240,7 +240,7 @@ func (s *Store) DeleteBlock(ctx context.Context, root [32]byte) error {
 		return err
 	}
 
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bkt := tx.Bucket(finalizedBlockRootsIndexBucket)
 		if b := bkt.Get(root[:]); b != nil {
 			return ErrDeleteJustifiedAndFinalized
@@ -277,7 +277,7 @@ func (s *Store) SaveBlock(ctx context.Context, signed interfaces.ReadOnlySignedB
 // to the DB for future checks.
 func (s *Store) shouldSaveBlinded(ctx context.Context) (bool, error) {
 	var saveBlinded bool
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		metadataBkt := tx.Bucket(chainMetadataBucket)
 		saveBlinded = len(metadataBkt.Get(saveBlindedBeaconBlocksKey)) > 0
 		return nil
@@ -292,55 +292,81 @@ func (s *Store) SaveBlocks(ctx context.Context, blks []interfaces.ReadOnlySigned
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBlocks")
 	defer span.End()
 
-	// Performing marshaling, hashing, and indexing outside the bolt transaction
-	// to minimize the time we hold the DB lock.
-	blockRoots := make([][]byte, len(blks))
-	encodedBlocks := make([][]byte, len(blks))
-	indicesForBlocks := make([]map[string][]byte, len(blks))
+	enc, err := s.encodeBlocks(ctx, blks)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx engine.Tx) error {
+		return s.putBlocks(ctx, tx, enc)
+	})
+}
+
+// encodedBlocks holds blocks along with their roots, encodings and indices, which are computed before
+// opening a transaction.
+type encodedBlocks struct {
+	blks        []interfaces.ReadOnlySignedBeaconBlock
+	roots       [][]byte
+	encoded     [][]byte
+	indices     []map[string][]byte
+	saveBlinded bool
+}
+
+// encodeBlocks performs marshaling, hashing, and indexing outside the bolt transaction
+// to minimize the time we hold the DB lock.
+func (s *Store) encodeBlocks(ctx context.Context, blks []interfaces.ReadOnlySignedBeaconBlock) (*encodedBlocks, error) {
+	enc := &encodedBlocks{
+		blks:    blks,
+		roots:   make([][]byte, len(blks)),
+		encoded: make([][]byte, len(blks)),
+		indices: make([]map[string][]byte, len(blks)),
+	}
 	for i, blk := range blks {
 		blockRoot, err := blk.Block().HashTreeRoot()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		enc, err := s.marshalBlock(ctx, blk)
+		e, err := s.marshalBlock(ctx, blk)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		blockRoots[i] = blockRoot[:]
-		encodedBlocks[i] = enc
-		indicesByBucket := createBlockIndicesFromBlock(ctx, blk.Block())
-		indicesForBlocks[i] = indicesByBucket
+		enc.roots[i] = blockRoot[:]
+		enc.encoded[i] = e
+		enc.indices[i] = createBlockIndicesFromBlock(ctx, blk.Block())
 	}
 	saveBlinded, err := s.shouldSaveBlinded(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return s.db.Update(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket(blocksBucket)
-		for i, blk := range blks {
-			if existingBlock := bkt.Get(blockRoots[i]); existingBlock != nil {
-				continue
-			}
-			if err := updateValueForIndices(ctx, indicesForBlocks[i], blockRoots[i], tx); err != nil {
-				return errors.Wrap(err, "could not update DB indices")
-			}
-			if saveBlinded {
-				blindedBlock, err := blk.ToBlinded()
-				if err != nil {
-					if !errors.Is(err, blocks.ErrUnsupportedVersion) {
-						return err
-					}
-				} else {
-					blk = blindedBlock
+	enc.saveBlinded = saveBlinded
+	return enc, nil
+}
+
+// putBlocks saves the encoded blocks which are not in the DB yet, along with their indices.
+func (s *Store) putBlocks(ctx context.Context, tx engine.Tx, enc *encodedBlocks) error {
+	bkt := tx.Bucket(blocksBucket)
+	for i, blk := range enc.blks {
+		if existingBlock := bkt.Get(enc.roots[i]); existingBlock != nil {
+			continue
+		}
+		if err := updateValueForIndices(ctx, enc.indices[i], enc.roots[i], tx); err != nil {
+			return errors.Wrap(err, "could not update DB indices")
+		}
+		if enc.saveBlinded {
+			blindedBlock, err := blk.ToBlinded()
+			if err != nil {
+				if !errors.Is(err, blocks.ErrUnsupportedVersion) {
+					return err
 				}
-			}
-			s.blockCache.Set(string(blockRoots[i]), blk, int64(len(encodedBlocks[i])))
-			if err := bkt.Put(blockRoots[i], encodedBlocks[i]); err != nil {
-				return err
+			} else {
+				blk = blindedBlock
 			}
 		}
-		return nil
-	})
+		s.blockCache.Set(string(enc.roots[i]), blk, int64(len(enc.encoded[i])))
+		if err := bkt.Put(enc.roots[i], enc.encoded[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SaveHeadBlockRoot to the db.
@@ -348,7 +374,7 @@ func (s *Store) SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
 	hasStateSummary := s.HasStateSummary(ctx, blockRoot)
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		hasStateInDB := tx.Bucket(stateBucket).Get(blockRoot[:]) != nil
 		if !(hasStateInDB || hasStateSummary) {
 			return errors.New("no state or state summary found with head block root")
@@ -364,7 +390,7 @@ func (s *Store) GenesisBlock(ctx context.Context) (interfaces.ReadOnlySignedBeac
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisBlock")
 	defer span.End()
 	var blk interfaces.ReadOnlySignedBeaconBlock
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		root := bkt.Get(genesisBlockRootKey)
 		enc := bkt.Get(root)
@@ -382,7 +408,7 @@ func (s *Store) GenesisBlockRoot(ctx context.Context) ([32]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisBlockRoot")
 	defer span.End()
 	var root [32]byte
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		r := bkt.Get(genesisBlockRootKey)
 		if len(r) == 0 {
@@ -398,7 +424,7 @@ func (s *Store) GenesisBlockRoot(ctx context.Context) ([32]byte, error) {
 func (s *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveGenesisBlockRoot")
 	defer span.End()
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(genesisBlockRootKey, blockRoot[:])
 	})
@@ -411,7 +437,7 @@ func (s *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) er
 func (s *Store) SaveOriginCheckpointBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveOriginCheckpointBlockRoot")
 	defer span.End()
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(originCheckpointBlockRootKey, blockRoot[:])
 	})
@@ -422,7 +448,7 @@ func (s *Store) SaveOriginCheckpointBlockRoot(ctx context.Context, blockRoot [32
 func (s *Store) SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBackfillBlockRoot")
 	defer span.End()
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(backfillBlockRootKey, blockRoot[:])
 	})
@@ -438,12 +464,12 @@ func (s *Store) HighestRootsBelowSlot(ctx context.Context, slot primitives.Slot)
 	defer span.End()
 
 	sk := bytesutil.Uint64ToBytesBigEndian(uint64(slot))
-	err = s.db.View(func(tx *bolt.Tx) error {
+	err = s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blockSlotIndicesBucket)
 		c := bkt.Cursor()
 		// The documentation for Seek says:
 		// "If the key does not exist then the next key is used. If no keys follow, a nil key is returned."
-		seekPast := func(ic *bolt.Cursor, k []byte) ([]byte, []byte) {
+		seekPast := func(ic engine.Cursor, k []byte) ([]byte, []byte) {
 			ik, iv := ic.Seek(k)
 			// So if there are slots in the index higher than the requested slot, sl will be equal to the key that is
 			// one higher than the value we want. If the slot argument is higher than the highest value in the index,
@@ -493,7 +519,7 @@ func (s *Store) FeeRecipientByValidatorID(ctx context.Context, id primitives.Val
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.FeeRecipientByValidatorID")
 	defer span.End()
 	var addr []byte
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(feeRecipientBucket)
 		addr = bkt.Get(bytesutil.Uint64ToBytesBigEndian(uint64(id)))
 		// IF the fee recipient is not found in the standard fee recipient bucket, then
@@ -526,7 +552,7 @@ func (s *Store) SaveFeeRecipientsByValidatorIDs(ctx context.Context, ids []primi
 		return errors.New("validatorIDs and feeRecipients must be the same length")
 	}
 
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bkt := tx.Bucket(feeRecipientBucket)
 		for i, id := range ids {
 			if err := bkt.Put(bytesutil.Uint64ToBytesBigEndian(uint64(id)), feeRecipients[i].Bytes()); err != nil {
@@ -543,7 +569,7 @@ func (s *Store) RegistrationByValidatorID(ctx context.Context, id primitives.Val
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.RegistrationByValidatorID")
 	defer span.End()
 	reg := &zondpb.ValidatorRegistrationV1{}
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(registrationBucket)
 		enc := bkt.Get(bytesutil.Uint64ToBytesBigEndian(uint64(id)))
 		if enc == nil {
@@ -564,7 +590,7 @@ func (s *Store) SaveRegistrationsByValidatorIDs(ctx context.Context, ids []primi
 		return errors.New("ids and registrations must be the same length")
 	}
 
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bkt := tx.Bucket(registrationBucket)
 		for i, id := range ids {
 			enc, err := encode(ctx, regs[i])
@@ -580,7 +606,7 @@ func (s *Store) SaveRegistrationsByValidatorIDs(ctx context.Context, ids []primi
 }
 
 // blockRootsByFilter retrieves the block roots given the filter criteria.
-func blockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFilter) ([][]byte, error) {
+func blockRootsByFilter(ctx context.Context, tx engine.Tx, f *filters.QueryFilter) ([][]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.blockRootsByFilter")
 	defer span.End()
 
@@ -641,7 +667,7 @@ func blockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFilter
 // However, if step is one, the implemented logic won’t skip half of the slots in the range.
 func blockRootsBySlotRange(
 	ctx context.Context,
-	bkt *bolt.Bucket,
+	bkt engine.Bucket,
 	startSlotEncoded, endSlotEncoded, startEpochEncoded, endEpochEncoded, slotStepEncoded interface{},
 ) ([][]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.blockRootsBySlotRange")
@@ -708,7 +734,7 @@ func blockRootsBySlotRange(
 }
 
 // blockRootsBySlot retrieves the block roots by slot
-func blockRootsBySlot(ctx context.Context, tx *bolt.Tx, slot primitives.Slot) ([][32]byte, error) {
+func blockRootsBySlot(ctx context.Context, tx engine.Tx, slot primitives.Slot) ([][32]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.blockRootsBySlot")
 	defer span.End()
 
@@ -1036,3 +1036,54 @@ func TestStore_RegistrationsByValidatorID(t *testing.T) {
 	want := errors.Wrap(ErrNotFoundFeeRecipient, "validator id 3")
 	require.Equal(t, want.Error(), err.Error())
 }
+
+// benchmarkBlocks returns distinct blocks for the block benchmarks.
+func benchmarkBlocks(b *testing.B, n int) ([]interfaces.ReadOnlySignedBeaconBlock, [][32]byte) {
+	blks := make([]interfaces.ReadOnlySignedBeaconBlock, n)
+	roots := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		blk := util.NewBeaconBlockCapella()
+		blk.Block.Slot = primitives.Slot(i)
+		wsb, err := blocks.NewSignedBeaconBlock(blk)
+		require.NoError(b, err)
+		blks[i] = wsb
+		roots[i], err = wsb.Block().HashTreeRoot()
+		require.NoError(b, err)
+	}
+	return blks, roots
+}
+
+func BenchmarkStore_SaveBlock(b *testing.B) {
+	for _, backend := range testBackends {
+		b.Run(string(backend), func(b *testing.B) {
+			db := setupDBWithBackend(b, backend)
+			blks, _ := benchmarkBlocks(b, b.N)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, db.SaveBlock(context.Background(), blks[i]))
+			}
+		})
+	}
+}
+
+func BenchmarkStore_Block(b *testing.B) {
+	const numBlocks = 1000
+	for _, backend := range testBackends {
+		b.Run(string(backend), func(b *testing.B) {
+			ctx := context.Background()
+			db := setupDBWithBackend(b, backend)
+			blks, roots := benchmarkBlocks(b, numBlocks)
+			require.NoError(b, db.SaveBlocks(ctx, blks))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Read from the database rather than from the block cache.
+				root := roots[i%numBlocks]
+				db.blockCache.Del(string(root[:]))
+				_, err := db.Block(ctx, root)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/monitoring/tracing"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -20,7 +20,7 @@ func (s *Store) JustifiedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, er
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.JustifiedCheckpoint")
 	defer span.End()
 	var checkpoint *zondpb.Checkpoint
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(checkpointBucket)
 		enc := bkt.Get(justifiedCheckpointKey)
 		if enc == nil {
@@ -38,7 +38,7 @@ func (s *Store) FinalizedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, er
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.FinalizedCheckpoint")
 	defer span.End()
 	var checkpoint *zondpb.Checkpoint
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(checkpointBucket)
 		enc := bkt.Get(finalizedCheckpointKey)
 		if enc == nil {
@@ -70,7 +70,7 @@ func (s *Store) SaveFinalizedCheckpoint(ctx context.Context, checkpoint *zondpb.
 		return err
 	}
 	hasStateSummary := s.HasStateSummary(ctx, bytesutil.ToBytes32(checkpoint.Root))
-	err = s.db.Update(func(tx *bolt.Tx) error {
+	err = s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(checkpointBucket)
 		hasStateInDB := tx.Bucket(stateBucket).Get(checkpoint.Root) != nil
 		if !(hasStateInDB || hasStateSummary) {
@@ -99,7 +99,7 @@ func (s *Store) saveCheckpoint(ctx context.Context, key []byte, checkpoint *zond
 		return err
 	}
 	hasStateSummary := s.HasStateSummary(ctx, bytesutil.ToBytes32(checkpoint.Root))
-	err = s.db.Update(func(tx *bolt.Tx) error {
+	err = s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(checkpointBucket)
 		hasStateInDB := tx.Bucket(stateBucket).Get(checkpoint.Root) != nil
 		if !(hasStateInDB || hasStateSummary) {
@@ -115,7 +115,7 @@ func (s *Store) saveCheckpoint(ctx context.Context, key []byte, checkpoint *zond
 }
 
 // Recovers and saves state summary for a given root if the root has a block in the DB.
-func recoverStateSummary(ctx context.Context, tx *bolt.Tx, root []byte) error {
+func recoverStateSummary(ctx context.Context, tx engine.Tx, root []byte) error {
 	blkBucket := tx.Bucket(blocksBucket)
 	blkEnc := blkBucket.Get(root)
 	if blkEnc == nil {
@@ -0,0 +1,60 @@
+package kv
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+)
+
+// convertBatchSize is the number of keys copied per transaction when converting a database.
+const convertBatchSize = 10000
+
+// ConvertBackend copies the database stored with the from backend in the directory path
+// into a new database of the to backend, next to it. The database must not be in use.
+// The source database is left untouched, so that it can be removed once the converted
+// database has been checked.
+func ConvertBackend(dirPath string, from, to engine.Backend) error {
+	if from == to {
+		return errors.New("source and target backends are the same")
+	}
+	srcPath := KVStoreDatapath(dirPath, from)
+	if _, err := os.Stat(srcPath); err != nil {
+		return errors.Wrapf(err, "could not find %s database", from)
+	}
+	dstPath := KVStoreDatapath(dirPath, to)
+	if _, err := os.Stat(dstPath); !os.IsNotExist(err) {
+		return errors.Errorf("a %s database already exists at %s", to, dstPath)
+	}
+	src, err := engine.Open(from, srcPath, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.WithError(err).Error("Failed to close source database")
+		}
+	}()
+
+	// Convert into a temporary path first, so that an interrupted conversion
+	// does not leave a partial database where the node would open it.
+	tmpPath := dstPath + ".tmp"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	dst, err := engine.Open(to, tmpPath, &engine.Options{NoSync: true})
+	if err != nil {
+		return err
+	}
+	log.WithField("from", srcPath).WithField("to", dstPath).Info("Converting database")
+	if err := engine.Copy(src, dst, convertBatchSize); err != nil {
+		if closeErr := dst.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Failed to close target database")
+		}
+		return errors.Wrap(err, "could not copy database")
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dstPath)
+}
@@ -0,0 +1,61 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+func TestConvertBackend(t *testing.T) {
+	for _, tt := range []struct {
+		from, to engine.Backend
+	}{
+		{from: engine.Bolt, to: engine.Pebble},
+		{from: engine.Pebble, to: engine.Bolt},
+	} {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			db, err := NewKVStore(ctx, dir, WithBackend(tt.from))
+			require.NoError(t, err)
+			head := util.NewBeaconBlock()
+			head.Block.Slot = 100
+			wsb, err := blocks.NewSignedBeaconBlock(head)
+			require.NoError(t, err)
+			require.NoError(t, db.SaveBlock(ctx, wsb))
+			root, err := head.Block.HashTreeRoot()
+			require.NoError(t, err)
+			st, err := util.NewBeaconState()
+			require.NoError(t, err)
+			require.NoError(t, db.SaveState(ctx, st, root))
+			require.NoError(t, db.SaveHeadBlockRoot(ctx, root))
+			require.NoError(t, db.Close())
+
+			require.NoError(t, ConvertBackend(dir, tt.from, tt.to))
+			_, err = os.Stat(KVStoreDatapath(dir, tt.to) + ".tmp")
+			assert.Equal(t, true, os.IsNotExist(err), "Temporary database was not renamed")
+			require.ErrorContains(t, "already exists", ConvertBackend(dir, tt.from, tt.to))
+
+			converted, err := NewKVStore(ctx, dir, WithBackend(tt.to))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, converted.Close())
+			})
+			assert.Equal(t, true, converted.HasBlock(ctx, root))
+			assert.Equal(t, true, converted.HasState(ctx, root))
+			headBlock, err := converted.HeadBlock(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, head.Block.Slot, headBlock.Block().Slot())
+		})
+	}
+}
+
+func TestConvertBackend_SameBackend(t *testing.T) {
+	require.ErrorContains(t, "are the same", ConvertBackend(t.TempDir(), engine.Bolt, engine.Bolt))
+}
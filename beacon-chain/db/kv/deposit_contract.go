@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/theQRL/go-zond/common"
-	bolt "go.etcd.io/bbolt"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"go.opencensus.io/trace"
 )
 
@@ -15,7 +15,7 @@ func (s *Store) DepositContractAddress(ctx context.Context) ([]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DepositContractAddress")
 	defer span.End()
 	var addr []byte
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		addr = chainInfo.Get(depositContractAddressKey)
 		return nil
@@ -30,7 +30,7 @@ func (s *Store) SaveDepositContractAddress(ctx context.Context, addr common.Addr
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.VerifyContractAddress")
 	defer span.End()
 
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		expectedAddress := chainInfo.Get(depositContractAddressKey)
 		if expectedAddress != nil {
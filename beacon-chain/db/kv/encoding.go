@@ -8,6 +8,7 @@ import (
 	"github.com/golang/snappy"
 	fastssz "github.com/prysmaticlabs/fastssz"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 	"go.opencensus.io/trace"
 	"google.golang.org/protobuf/proto"
 )
@@ -72,6 +73,8 @@ func isSSZStorageFormat(obj interface{}) bool {
 		return true
 	case *zondpb.ValidatorRegistrationV1:
 		return true
+	case *zondpbv2.LightClientUpdate:
+		return true
 	default:
 		return false
 	}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	prombolt "github.com/prysmaticlabs/prombbolt"
+	"github.com/theQRL/qrysm/v4/config/params"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltAllocSize = 8 * 1024 * 1024
+	// Specifies the initial mmap size of bolt.
+	boltMmapSize = 536870912
+)
+
+type boltDB struct {
+	db *bolt.DB
+}
+
+func openBolt(path string, opts *Options) (*boltDB, error) {
+	db, err := bolt.Open(
+		path,
+		params.BeaconIoConfig().ReadWritePermissions,
+		&bolt.Options{
+			Timeout:         1 * time.Second,
+			InitialMmapSize: boltMmapSize,
+			NoSync:          opts.NoSync,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, ErrDatabaseLocked
+		}
+		return nil, err
+	}
+	db.AllocSize = boltAllocSize
+	return &boltDB{db: db}, nil
+}
+
+// UnwrapBolt returns the bbolt database underneath a database of the bolt backend.
+func UnwrapBolt(db DB) (*bolt.DB, bool) {
+	b, ok := db.(*boltDB)
+	if !ok {
+		return nil, false
+	}
+	return b.db, true
+}
+
+func (b *boltDB) View(fn func(Tx) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (b *boltDB) Update(fn func(Tx) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (b *boltDB) Path() string {
+	return b.db.Path()
+}
+
+func (b *boltDB) Collector(blockedBuckets ...[]byte) prometheus.Collector {
+	return prombolt.New("boltDB", b.db, blockedBuckets...)
+}
+
+func (b *boltDB) Close() error {
+	if b.db.NoSync {
+		if err := b.db.Sync(); err != nil {
+			return err
+		}
+	}
+	return b.db.Close()
+}
+
+// boltError translates the bbolt errors that have an engine equivalent.
+func boltError(err error) error {
+	switch {
+	case errors.Is(err, bolt.ErrBucketNotFound):
+		return ErrBucketNotFound
+	case errors.Is(err, bolt.ErrTxNotWritable):
+		return ErrTxNotWritable
+	case errors.Is(err, bolt.ErrKeyRequired):
+		return ErrKeyRequired
+	default:
+		return err
+	}
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) Bucket(name []byte) Bucket {
+	b := t.tx.Bucket(name)
+	if b == nil {
+		return nil
+	}
+	return boltBucket{b}
+}
+
+func (t boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	b, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return boltBucket{b}, nil
+}
+
+func (t boltTx) DeleteBucket(name []byte) error {
+	return boltError(t.tx.DeleteBucket(name))
+}
+
+func (t boltTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return fn(name, boltBucket{b})
+	})
+}
+
+type boltBucket struct {
+	b *bolt.Bucket
+}
+
+func (b boltBucket) Get(key []byte) []byte {
+	return b.b.Get(key)
+}
+
+func (b boltBucket) Put(key, value []byte) error {
+	return boltError(b.b.Put(key, value))
+}
+
+func (b boltBucket) Delete(key []byte) error {
+	return boltError(b.b.Delete(key))
+}
+
+func (b boltBucket) Cursor() Cursor {
+	return b.b.Cursor()
+}
+
+func (b boltBucket) ForEach(fn func(k, v []byte) error) error {
+	return b.b.ForEach(fn)
+}
@@ -0,0 +1,168 @@
+// Package engine abstracts the key-value storage engine underneath the beacon node database. It
+// exposes the transactional, bucketed model of bbolt, which the kv package is written against, and
+// implements it on top of bbolt and of the Pebble LSM engine.
+package engine
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Backend names a storage engine.
+type Backend string
+
+const (
+	// Bolt stores the database in a single bbolt file.
+	Bolt Backend = "bolt"
+	// Pebble stores the database in a Pebble LSM directory.
+	Pebble Backend = "pebble"
+)
+
+var (
+	// ErrUnknownBackend is returned when a backend name is not one of the supported backends.
+	ErrUnknownBackend = errors.New("unknown database backend")
+	// ErrBucketNotFound is returned when deleting a bucket that does not exist.
+	ErrBucketNotFound = errors.New("bucket not found")
+	// ErrTxNotWritable is returned when writing in a read-only transaction.
+	ErrTxNotWritable = errors.New("tx not writable")
+	// ErrKeyRequired is returned when writing an empty key.
+	ErrKeyRequired = errors.New("key required")
+	// ErrDatabaseLocked is returned when the database is already opened by another process.
+	ErrDatabaseLocked = errors.New("cannot obtain database lock, database may be in use by another process")
+)
+
+// ParseBackend returns the backend with the given name. An empty name selects bolt, the
+// default backend.
+func ParseBackend(name string) (Backend, error) {
+	switch b := Backend(name); b {
+	case "":
+		return Bolt, nil
+	case Bolt, Pebble:
+		return b, nil
+	default:
+		return "", errors.Wrapf(ErrUnknownBackend, "%q", name)
+	}
+}
+
+// DB is a key-value database made of named buckets, read and written in transactions.
+type DB interface {
+	// View runs fn in a read-only transaction, over a consistent view of the database.
+	View(fn func(Tx) error) error
+	// Update runs fn in a read-write transaction. Writes are committed if fn returns nil and rolled
+	// back otherwise. Read-write transactions are serialized.
+	Update(fn func(Tx) error) error
+	// Path returns the file or directory the database is stored at.
+	Path() string
+	// Collector returns a prometheus collector of the engine's metrics. Collectors of the same
+	// backend share their metric names, so only one of them can be registered at a time.
+	Collector(blockedBuckets ...[]byte) prometheus.Collector
+	// Close closes the database.
+	Close() error
+}
+
+// Tx is a database transaction. Buckets, values and cursors are only valid for the lifetime of the
+// transaction they were obtained from.
+type Tx interface {
+	// Bucket returns the bucket with the given name, or nil if it does not exist.
+	Bucket(name []byte) Bucket
+	// CreateBucketIfNotExists returns the bucket with the given name, creating it if needed.
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+	// DeleteBucket deletes a bucket and all of its keys.
+	DeleteBucket(name []byte) error
+	// ForEach calls fn for every bucket, in name order.
+	ForEach(fn func(name []byte, b Bucket) error) error
+}
+
+// Bucket is a sorted collection of keys and values.
+type Bucket interface {
+	// Get returns the value of a key, or nil if the key does not exist.
+	Get(key []byte) []byte
+	// Put sets the value of a key.
+	Put(key, value []byte) error
+	// Delete deletes a key. Deleting a key that does not exist is not an error.
+	Delete(key []byte) error
+	// Cursor returns a cursor over the keys of the bucket.
+	Cursor() Cursor
+	// ForEach calls fn for every key of the bucket, in key order.
+	ForEach(fn func(k, v []byte) error) error
+}
+
+// Cursor iterates over the keys of a bucket in key order. Its methods return a nil key once the
+// cursor moved past either end of the bucket.
+type Cursor interface {
+	First() (key, value []byte)
+	Last() (key, value []byte)
+	Next() (key, value []byte)
+	Prev() (key, value []byte)
+	// Seek moves the cursor to the first key greater than or equal to seek.
+	Seek(seek []byte) (key, value []byte)
+}
+
+// Options configure how a database is opened.
+type Options struct {
+	// NoSync disables syncing writes to disk, for databases that are thrown away on a crash. The
+	// database is synced when it is closed.
+	NoSync bool
+}
+
+// Open opens the database of the given backend at path, creating it if needed.
+func Open(backend Backend, path string, opts *Options) (DB, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	switch backend {
+	case Bolt:
+		return openBolt(path, opts)
+	case Pebble:
+		return openPebble(path, opts)
+	default:
+		return nil, errors.Wrapf(ErrUnknownBackend, "%q", backend)
+	}
+}
+
+// Copy copies every bucket of src into dst. Keys are copied in batches of batchSize per transaction,
+// so that copying a large database does not hold long-running transactions.
+func Copy(src, dst DB, batchSize int) error {
+	var names [][]byte
+	if err := src.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, _ Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := dst.Update(func(tx Tx) error {
+			_, err := tx.CreateBucketIfNotExists(name)
+			return err
+		}); err != nil {
+			return err
+		}
+		var next []byte
+		for done := false; !done; {
+			if err := src.View(func(tx Tx) error {
+				c := tx.Bucket(name).Cursor()
+				return dst.Update(func(dtx Tx) error {
+					b := dtx.Bucket(name)
+					k, v := c.First()
+					if next != nil {
+						k, v = c.Seek(next)
+					}
+					for n := 0; n < batchSize && k != nil; n++ {
+						if err := b.Put(k, v); err != nil {
+							return err
+						}
+						k, v = c.Next()
+					}
+					next = append([]byte(nil), k...)
+					done = k == nil
+					return nil
+				})
+			}); err != nil {
+				return errors.Wrapf(err, "could not copy bucket %s", name)
+			}
+		}
+	}
+	return nil
+}
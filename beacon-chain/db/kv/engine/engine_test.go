@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+var backends = []Backend{Bolt, Pebble}
+
+func setupDB(t testing.TB, backend Backend) DB {
+	db, err := Open(backend, filepath.Join(t.TempDir(), string(backend)), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db
+}
+
+func TestParseBackend(t *testing.T) {
+	b, err := ParseBackend("pebble")
+	require.NoError(t, err)
+	assert.Equal(t, Pebble, b)
+	b, err = ParseBackend("")
+	require.NoError(t, err)
+	assert.Equal(t, Bolt, b)
+	_, err = ParseBackend("leveldb")
+	require.ErrorIs(t, err, ErrUnknownBackend)
+}
+
+func TestDB_Buckets(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(string(backend), func(t *testing.T) {
+			db := setupDB(t, backend)
+			require.NoError(t, db.View(func(tx Tx) error {
+				assert.Equal(t, nil, tx.Bucket([]byte("a")))
+				_, err := tx.CreateBucketIfNotExists([]byte("a"))
+				assert.NotNil(t, err, "Created a bucket in a read-only transaction")
+				return nil
+			}))
+			require.NoError(t, db.Update(func(tx Tx) error {
+				for _, name := range []string{"b", "a", "ab"} {
+					b, err := tx.CreateBucketIfNotExists([]byte(name))
+					require.NoError(t, err)
+					require.NoError(t, b.Put([]byte("k"), []byte(name)))
+				}
+				return nil
+			}))
+			require.NoError(t, db.View(func(tx Tx) error {
+				var names []string
+				require.NoError(t, tx.ForEach(func(name []byte, b Bucket) error {
+					names = append(names, string(name))
+					assert.DeepEqual(t, name, b.Get([]byte("k")))
+					return nil
+				}))
+				assert.DeepEqual(t, []string{"a", "ab", "b"}, names)
+				return nil
+			}))
+			require.NoError(t, db.Update(func(tx Tx) error {
+				require.NoError(t, tx.DeleteBucket([]byte("a")))
+				require.ErrorIs(t, tx.DeleteBucket([]byte("a")), ErrBucketNotFound)
+				return nil
+			}))
+			require.NoError(t, db.Update(func(tx Tx) error {
+				assert.Equal(t, nil, tx.Bucket([]byte("a")))
+				b, err := tx.CreateBucketIfNotExists([]byte("a"))
+				require.NoError(t, err)
+				assert.DeepEqual(t, []byte(nil), b.Get([]byte("k")), "Deleted bucket kept its keys")
+				assert.DeepEqual(t, []byte("ab"), tx.Bucket([]byte("ab")).Get([]byte("k")))
+				return nil
+			}))
+		})
+	}
+}
+
+func TestDB_Update(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(string(backend), func(t *testing.T) {
+			db := setupDB(t, backend)
+			name := []byte("bucket")
+			require.NoError(t, db.Update(func(tx Tx) error {
+				b, err := tx.CreateBucketIfNotExists(name)
+				require.NoError(t, err)
+				require.ErrorIs(t, b.Put(nil, []byte("v")), ErrKeyRequired)
+				require.NoError(t, b.Put([]byte("k1"), []byte("v1")))
+				require.NoError(t, b.Put([]byte("empty"), []byte{}))
+				// Writes are visible to the transaction that made them.
+				assert.DeepEqual(t, []byte("v1"), b.Get([]byte("k1")))
+				return nil
+			}))
+			errRollback := errors.New("rollback")
+			err := db.Update(func(tx Tx) error {
+				b := tx.Bucket(name)
+				require.NoError(t, b.Put([]byte("k2"), []byte("v2")))
+				require.NoError(t, b.Delete([]byte("k1")))
+				return errRollback
+			})
+			require.ErrorIs(t, err, errRollback)
+			require.NoError(t, db.View(func(tx Tx) error {
+				b := tx.Bucket(name)
+				assert.DeepEqual(t, []byte("v1"), b.Get([]byte("k1")))
+				assert.DeepEqual(t, []byte(nil), b.Get([]byte("k2")))
+				v := b.Get([]byte("empty"))
+				assert.NotNil(t, v, "Empty value read as missing")
+				assert.Equal(t, 0, len(v))
+				require.ErrorIs(t, b.Put([]byte("k3"), []byte("v3")), ErrTxNotWritable)
+				return nil
+			}))
+			require.NoError(t, db.Update(func(tx Tx) error {
+				require.NoError(t, tx.Bucket(name).Delete([]byte("k1")))
+				require.NoError(t, tx.Bucket(name).Delete([]byte("missing")))
+				return nil
+			}))
+			require.NoError(t, db.View(func(tx Tx) error {
+				assert.DeepEqual(t, []byte(nil), tx.Bucket(name).Get([]byte("k1")))
+				return nil
+			}))
+		})
+	}
+}
+
+// failingReader fails the reads of keys which are present.
+type failingReader struct {
+	pebble.Reader
+}
+
+var errRead = errors.New("read failed")
+
+func (r failingReader) Get(key []byte) ([]byte, io.Closer, error) {
+	v, closer, err := r.Reader.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := closer.Close(); err != nil {
+		return nil, nil, err
+	}
+	return v, nil, errRead
+}
+
+func TestPebble_ReadError(t *testing.T) {
+	db := setupDB(t, Pebble)
+	name := []byte("bucket")
+	require.NoError(t, db.Update(func(tx Tx) error {
+		b, err := tx.CreateBucketIfNotExists(name)
+		require.NoError(t, err)
+		return b.Put([]byte("k1"), []byte("v1"))
+	}))
+	err := db.Update(func(tx Tx) error {
+		b := tx.Bucket(name)
+		require.NoError(t, b.Put([]byte("k2"), []byte("v2")))
+		tx.(*pebbleTx).r = failingReader{Reader: tx.(*pebbleTx).r}
+		assert.DeepEqual(t, []byte(nil), b.Get([]byte("k1")))
+		// Missing keys are not read errors.
+		assert.DeepEqual(t, []byte(nil), b.Get([]byte("missing")))
+		return nil
+	})
+	require.ErrorIs(t, err, errRead)
+	err = db.View(func(tx Tx) error {
+		b := tx.Bucket(name)
+		// A failed read does not commit the writes of its transaction.
+		assert.DeepEqual(t, []byte(nil), b.Get([]byte("k2")))
+		tx.(*pebbleTx).r = failingReader{Reader: tx.(*pebbleTx).r}
+		assert.DeepEqual(t, []byte(nil), b.Get([]byte("k1")))
+		return nil
+	})
+	require.ErrorIs(t, err, errRead)
+}
+
+func TestDB_Cursor(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(string(backend), func(t *testing.T) {
+			db := setupDB(t, backend)
+			require.NoError(t, db.Update(func(tx Tx) error {
+				b, err := tx.CreateBucketIfNotExists([]byte("bucket"))
+				require.NoError(t, err)
+				for _, k := range []string{"b", "d", "a", "c"} {
+					require.NoError(t, b.Put([]byte(k), []byte("v"+k)))
+				}
+				// Keys of other buckets are not iterated over.
+				other, err := tx.CreateBucketIfNotExists([]byte("bucket2"))
+				require.NoError(t, err)
+				return other.Put([]byte("0"), []byte("other"))
+			}))
+			require.NoError(t, db.View(func(tx Tx) error {
+				c := tx.Bucket([]byte("bucket")).Cursor()
+				var keys []string
+				for k, v := c.First(); k != nil; k, v = c.Next() {
+					assert.Equal(t, "v"+string(k), string(v))
+					keys = append(keys, string(k))
+				}
+				assert.DeepEqual(t, []string{"a", "b", "c", "d"}, keys)
+				keys = nil
+				for k, _ := c.Last(); k != nil; k, _ = c.Prev() {
+					keys = append(keys, string(k))
+				}
+				assert.DeepEqual(t, []string{"d", "c", "b", "a"}, keys)
+
+				k, _ := c.Seek([]byte("bb"))
+				assert.Equal(t, "c", string(k))
+				k, _ = c.Seek([]byte("b"))
+				assert.Equal(t, "b", string(k))
+				k, _ = c.Seek([]byte("e"))
+				assert.DeepEqual(t, []byte(nil), k)
+
+				var n int
+				require.NoError(t, tx.Bucket([]byte("bucket")).ForEach(func(_, _ []byte) error {
+					n++
+					return nil
+				}))
+				assert.Equal(t, 4, n)
+				return nil
+			}))
+		})
+	}
+}
+
+func TestOpen_Locked(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(string(backend), func(t *testing.T) {
+			db := setupDB(t, backend)
+			_, err := Open(backend, db.Path(), nil)
+			assert.NotNil(t, err, "Opened a database twice")
+		})
+	}
+}
+
+func TestCopy(t *testing.T) {
+	for _, from := range backends {
+		for _, to := range backends {
+			t.Run(fmt.Sprintf("%s to %s", from, to), func(t *testing.T) {
+				src := setupDB(t, from)
+				dst := setupDB(t, to)
+				const numKeys = 25
+				require.NoError(t, src.Update(func(tx Tx) error {
+					for _, name := range []string{"a", "b", "empty"} {
+						b, err := tx.CreateBucketIfNotExists([]byte(name))
+						require.NoError(t, err)
+						if name == "empty" {
+							continue
+						}
+						for i := 0; i < numKeys; i++ {
+							require.NoError(t, b.Put([]byte(fmt.Sprintf("%s%03d", name, i)), []byte{byte(i)}))
+						}
+					}
+					return nil
+				}))
+				require.NoError(t, Copy(src, dst, 10))
+				require.NoError(t, dst.View(func(tx Tx) error {
+					require.NotNil(t, tx.Bucket([]byte("empty")))
+					for _, name := range []string{"a", "b"} {
+						var n int
+						require.NoError(t, tx.Bucket([]byte(name)).ForEach(func(k, v []byte) error {
+							assert.Equal(t, fmt.Sprintf("%s%03d", name, n), string(k))
+							assert.DeepEqual(t, []byte{byte(n)}, v)
+							n++
+							return nil
+						}))
+						assert.Equal(t, numKeys, n)
+					}
+					return nil
+				}))
+			})
+		}
+	}
+}
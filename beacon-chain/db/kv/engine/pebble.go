@@ -0,0 +1,367 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	pebbleCacheSize    = 256 * 1024 * 1024
+	pebbleMemTableSize = 64 * 1024 * 1024
+)
+
+// Buckets are emulated with key prefixes. The name of every bucket is recorded under the registry
+// prefix, and the keys of a bucket are prefixed by the length of its name followed by its name. As
+// bucket names are not empty, data keys never collide with the registry.
+const pebbleRegistryPrefix = byte(0)
+
+func bucketRegistryKey(name []byte) []byte {
+	return append([]byte{pebbleRegistryPrefix}, name...)
+}
+
+func bucketPrefix(name []byte) ([]byte, error) {
+	if len(name) == 0 || len(name) > 255 {
+		return nil, errors.Errorf("invalid bucket name length %d", len(name))
+	}
+	return append([]byte{byte(len(name))}, name...), nil
+}
+
+// prefixUpperBound returns the smallest key greater than all keys starting with prefix, or nil if
+// there is none.
+func prefixUpperBound(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// pebbleDB implements the bucketed transactions of bbolt on top of Pebble. Read-only transactions
+// read from a snapshot of the database, read-write transactions are indexed batches, so that they
+// read their own writes, and are serialized like bbolt writers are.
+type pebbleDB struct {
+	db        *pebble.DB
+	path      string
+	writeOpts *pebble.WriteOptions
+	writeLock sync.Mutex
+}
+
+func openPebble(path string, opts *Options) (*pebbleDB, error) {
+	cache := pebble.NewCache(pebbleCacheSize)
+	defer cache.Unref()
+	db, err := pebble.Open(path, &pebble.Options{
+		Cache:        cache,
+		MemTableSize: pebbleMemTableSize,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open pebble database")
+	}
+	writeOpts := pebble.Sync
+	if opts.NoSync {
+		writeOpts = pebble.NoSync
+	}
+	return &pebbleDB{db: db, path: path, writeOpts: writeOpts}, nil
+}
+
+func (p *pebbleDB) View(fn func(Tx) error) error {
+	snap := p.db.NewSnapshot()
+	tx := &pebbleTx{r: snap}
+	defer func() {
+		tx.closeIters()
+		if err := snap.Close(); err != nil {
+			log.WithError(err).Error("Could not close pebble snapshot")
+		}
+	}()
+	return tx.result(fn(tx))
+}
+
+func (p *pebbleDB) Update(fn func(Tx) error) error {
+	p.writeLock.Lock()
+	defer p.writeLock.Unlock()
+	batch := p.db.NewIndexedBatch()
+	tx := &pebbleTx{r: batch, batch: batch}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			log.WithError(err).Error("Could not close pebble batch")
+		}
+	}()
+	err := tx.result(fn(tx))
+	tx.closeIters()
+	if err != nil {
+		return err
+	}
+	return batch.Commit(p.writeOpts)
+}
+
+func (p *pebbleDB) Path() string {
+	return p.path
+}
+
+func (p *pebbleDB) Collector(_ ...[]byte) prometheus.Collector {
+	return newPebbleCollector(p.db)
+}
+
+func (p *pebbleDB) Close() error {
+	if p.writeOpts == pebble.NoSync {
+		if err := p.db.Flush(); err != nil {
+			return err
+		}
+	}
+	return p.db.Close()
+}
+
+type pebbleTx struct {
+	r     pebble.Reader
+	batch *pebble.Batch
+	iters []*pebble.Iterator
+	// err is the first read error of the transaction. Reads cannot return errors, like bbolt's which
+	// cannot fail, so they fail the transaction instead.
+	err error
+}
+
+// result returns the error of the transaction function, or the first read error of the transaction,
+// so that a transaction which could not read a key neither succeeds nor commits.
+func (t *pebbleTx) result(err error) error {
+	if err != nil {
+		return err
+	}
+	return t.err
+}
+
+func (t *pebbleTx) get(key []byte) []byte {
+	v, closer, err := t.r.Get(key)
+	if err != nil {
+		if !errors.Is(err, pebble.ErrNotFound) && t.err == nil {
+			t.err = errors.Wrap(err, "could not read pebble key")
+		}
+		return nil
+	}
+	// Values are only valid until the closer is closed, unlike bbolt's which are valid for the
+	// lifetime of the transaction.
+	value := make([]byte, len(v))
+	copy(value, v)
+	if err := closer.Close(); err != nil {
+		log.WithError(err).Error("Could not release pebble value")
+	}
+	return value
+}
+
+func (t *pebbleTx) newIter(prefix []byte) *pebble.Iterator {
+	it := t.r.NewIter(&pebble.IterOptions{LowerBound: prefix, UpperBound: prefixUpperBound(prefix)})
+	t.iters = append(t.iters, it)
+	return it
+}
+
+func (t *pebbleTx) closeIters() {
+	for _, it := range t.iters {
+		if err := it.Close(); err != nil {
+			log.WithError(err).Error("Could not close pebble iterator")
+		}
+	}
+	t.iters = nil
+}
+
+func (t *pebbleTx) Bucket(name []byte) Bucket {
+	prefix, err := bucketPrefix(name)
+	if err != nil || t.get(bucketRegistryKey(name)) == nil {
+		return nil
+	}
+	return &pebbleBucket{tx: t, prefix: prefix}
+}
+
+func (t *pebbleTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	if b := t.Bucket(name); b != nil {
+		return b, nil
+	}
+	if t.batch == nil {
+		return nil, ErrTxNotWritable
+	}
+	prefix, err := bucketPrefix(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.batch.Set(bucketRegistryKey(name), nil, nil); err != nil {
+		return nil, err
+	}
+	return &pebbleBucket{tx: t, prefix: prefix}, nil
+}
+
+func (t *pebbleTx) DeleteBucket(name []byte) error {
+	if t.batch == nil {
+		return ErrTxNotWritable
+	}
+	if t.Bucket(name) == nil {
+		return ErrBucketNotFound
+	}
+	prefix, err := bucketPrefix(name)
+	if err != nil {
+		return err
+	}
+	if err := t.batch.DeleteRange(prefix, prefixUpperBound(prefix), nil); err != nil {
+		return err
+	}
+	return t.batch.Delete(bucketRegistryKey(name), nil)
+}
+
+func (t *pebbleTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	it := t.newIter([]byte{pebbleRegistryPrefix})
+	for valid := it.First(); valid; valid = it.Next() {
+		name := append([]byte(nil), it.Key()[1:]...)
+		prefix, err := bucketPrefix(name)
+		if err != nil {
+			return err
+		}
+		if err := fn(name, &pebbleBucket{tx: t, prefix: prefix}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type pebbleBucket struct {
+	tx     *pebbleTx
+	prefix []byte
+}
+
+func (b *pebbleBucket) key(k []byte) []byte {
+	key := make([]byte, len(b.prefix)+len(k))
+	copy(key, b.prefix)
+	copy(key[len(b.prefix):], k)
+	return key
+}
+
+func (b *pebbleBucket) Get(key []byte) []byte {
+	return b.tx.get(b.key(key))
+}
+
+func (b *pebbleBucket) Put(key, value []byte) error {
+	if b.tx.batch == nil {
+		return ErrTxNotWritable
+	}
+	if len(key) == 0 {
+		return ErrKeyRequired
+	}
+	return b.tx.batch.Set(b.key(key), value, nil)
+}
+
+func (b *pebbleBucket) Delete(key []byte) error {
+	if b.tx.batch == nil {
+		return ErrTxNotWritable
+	}
+	return b.tx.batch.Delete(b.key(key), nil)
+}
+
+func (b *pebbleBucket) Cursor() Cursor {
+	return &pebbleCursor{it: b.tx.newIter(b.prefix), prefix: b.prefix}
+}
+
+func (b *pebbleBucket) ForEach(fn func(k, v []byte) error) error {
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type pebbleCursor struct {
+	it     *pebble.Iterator
+	prefix []byte
+}
+
+// entry returns copies of the key and value the iterator is positioned at, as they are only valid
+// until the iterator moves.
+func (c *pebbleCursor) entry(valid bool) ([]byte, []byte) {
+	if !valid {
+		return nil, nil
+	}
+	k := append([]byte(nil), c.it.Key()[len(c.prefix):]...)
+	v := make([]byte, len(c.it.Value()))
+	copy(v, c.it.Value())
+	return k, v
+}
+
+func (c *pebbleCursor) First() ([]byte, []byte) {
+	return c.entry(c.it.First())
+}
+
+func (c *pebbleCursor) Last() ([]byte, []byte) {
+	return c.entry(c.it.Last())
+}
+
+func (c *pebbleCursor) Next() ([]byte, []byte) {
+	return c.entry(c.it.Next())
+}
+
+func (c *pebbleCursor) Prev() ([]byte, []byte) {
+	return c.entry(c.it.Prev())
+}
+
+func (c *pebbleCursor) Seek(seek []byte) ([]byte, []byte) {
+	key := append(append([]byte(nil), c.prefix...), seek...)
+	return c.entry(c.it.SeekGE(key))
+}
+
+// pebbleCollector exports the metrics of a pebble database.
+type pebbleCollector struct {
+	db              *pebble.DB
+	diskUsage       *prometheus.Desc
+	compactionDebt  *prometheus.Desc
+	compactions     *prometheus.Desc
+	flushes         *prometheus.Desc
+	memTableSize    *prometheus.Desc
+	readAmp         *prometheus.Desc
+	blockCacheHits  *prometheus.Desc
+	blockCacheMiss  *prometheus.Desc
+	blockCacheBytes *prometheus.Desc
+}
+
+func newPebbleCollector(db *pebble.DB) *pebbleCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("pebbleDB", "", name), help, nil, nil)
+	}
+	return &pebbleCollector{
+		db:              db,
+		diskUsage:       desc("disk_usage_bytes", "Total disk space used by the database, in bytes."),
+		compactionDebt:  desc("compaction_debt_bytes", "Estimated number of bytes that need to be compacted to reach a stable state."),
+		compactions:     desc("compactions_total", "Number of compactions."),
+		flushes:         desc("flushes_total", "Number of memtable flushes."),
+		memTableSize:    desc("memtable_size_bytes", "Size of the memtables, in bytes."),
+		readAmp:         desc("read_amplification", "Number of sublevels a read may have to look at."),
+		blockCacheHits:  desc("block_cache_hits_total", "Number of block cache hits."),
+		blockCacheMiss:  desc("block_cache_misses_total", "Number of block cache misses."),
+		blockCacheBytes: desc("block_cache_size_bytes", "Size of the block cache, in bytes."),
+	}
+}
+
+func (c *pebbleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.diskUsage
+	ch <- c.compactionDebt
+	ch <- c.compactions
+	ch <- c.flushes
+	ch <- c.memTableSize
+	ch <- c.readAmp
+	ch <- c.blockCacheHits
+	ch <- c.blockCacheMiss
+	ch <- c.blockCacheBytes
+}
+
+func (c *pebbleCollector) Collect(ch chan<- prometheus.Metric) {
+	m := c.db.Metrics()
+	ch <- prometheus.MustNewConstMetric(c.diskUsage, prometheus.GaugeValue, float64(m.DiskSpaceUsage()))
+	ch <- prometheus.MustNewConstMetric(c.compactionDebt, prometheus.GaugeValue, float64(m.Compact.EstimatedDebt))
+	ch <- prometheus.MustNewConstMetric(c.compactions, prometheus.CounterValue, float64(m.Compact.Count))
+	ch <- prometheus.MustNewConstMetric(c.flushes, prometheus.CounterValue, float64(m.Flush.Count))
+	ch <- prometheus.MustNewConstMetric(c.memTableSize, prometheus.GaugeValue, float64(m.MemTable.Size))
+	ch <- prometheus.MustNewConstMetric(c.readAmp, prometheus.GaugeValue, float64(m.ReadAmp()))
+	ch <- prometheus.MustNewConstMetric(c.blockCacheHits, prometheus.CounterValue, float64(m.BlockCache.Hits))
+	ch <- prometheus.MustNewConstMetric(c.blockCacheMiss, prometheus.CounterValue, float64(m.BlockCache.Misses))
+	ch <- prometheus.MustNewConstMetric(c.blockCacheBytes, prometheus.GaugeValue, float64(m.BlockCache.Size))
+}
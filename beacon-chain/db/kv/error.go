@@ -21,3 +21,6 @@ var ErrNotFoundBackfillBlockRoot = errors.Wrap(ErrNotFound, "BackfillBlockRoot")
 
 // ErrNotFoundFeeRecipient is a not found error specifically for the fee recipient getter
 var ErrNotFoundFeeRecipient = errors.Wrap(ErrNotFound, "fee recipient")
+
+// errNotConnectedToFinalized is returned when backfilled blocks do not link to the finalized block roots index.
+var errNotConnectedToFinalized = errors.New("blocks are not connected to the finalized chain")
@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/monitoring/tracing"
 	v2 "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 	"google.golang.org/protobuf/proto"
 )
@@ -22,7 +22,7 @@ func (s *Store) SaveExecutionChainData(ctx context.Context, data *v2.ETH1ChainDa
 		return err
 	}
 
-	err := s.db.Update(func(tx *bolt.Tx) error {
+	err := s.db.Update(func(tx engine.Tx) error {
 		bkt := tx.Bucket(powchainBucket)
 		enc, err := proto.Marshal(data)
 		if err != nil {
@@ -40,7 +40,7 @@ func (s *Store) ExecutionChainData(ctx context.Context) (*v2.ETH1ChainData, erro
 	defer span.End()
 
 	var data *v2.ETH1ChainData
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(powchainBucket)
 		enc := bkt.Get(powchainDataKey)
 		if len(enc) == 0 {
@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/filters"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/monitoring/tracing"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -37,7 +38,7 @@ var containerFinalizedButNotCanonical = []byte("recent block needs reindexing to
 //
 // This method ensures that all blocks from the current finalized epoch are considered "final" while
 // maintaining only canonical and finalized blocks older than the current finalized epoch.
-func (s *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, checkpoint *zondpb.Checkpoint) error {
+func (s *Store) updateFinalizedBlockRoots(ctx context.Context, tx engine.Tx, checkpoint *zondpb.Checkpoint) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.updateFinalizedBlockRoots")
 	defer span.End()
 
@@ -163,6 +164,68 @@ func (s *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, chec
 	return bkt.Put(previousFinalizedCheckpointKey, enc)
 }
 
+// SaveBackfillBlocks saves a chain of finalized blocks, sorted by slot, whose highest block is the parent
+// of the finalized block childRoot. The blocks are added to the finalized block roots index in the same
+// transaction, so that backfilled blocks are part of the canonical chain like the ones finalized by the node.
+func (s *Store) SaveBackfillBlocks(ctx context.Context, blks []blocks.ROBlock, childRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBackfillBlocks")
+	defer span.End()
+	if len(blks) == 0 {
+		return nil
+	}
+
+	sbs := make([]interfaces.ReadOnlySignedBeaconBlock, len(blks))
+	containers := make([][]byte, len(blks))
+	for i := range blks {
+		sbs[i] = blks[i].ReadOnlySignedBeaconBlock
+		child := childRoot
+		if i < len(blks)-1 {
+			child = blks[i+1].Root()
+		}
+		if i > 0 && blks[i].Block().ParentRoot() != blks[i-1].Root() {
+			return errors.Wrapf(errNotConnectedToFinalized, "parent of block %#x is not block %#x", blks[i].Root(), blks[i-1].Root())
+		}
+		parentRoot := blks[i].Block().ParentRoot()
+		enc, err := encode(ctx, &zondpb.FinalizedBlockRootContainer{ParentRoot: parentRoot[:], ChildRoot: child[:]})
+		if err != nil {
+			tracing.AnnotateError(span, err)
+			return err
+		}
+		containers[i] = enc
+	}
+	enc, err := s.encodeBlocks(ctx, sbs)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return err
+	}
+	highest := blks[len(blks)-1].Root()
+	return s.db.Update(func(tx engine.Tx) error {
+		bkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+		childEnc := bkt.Get(childRoot[:])
+		if childEnc == nil || bytes.Equal(childEnc, containerFinalizedButNotCanonical) {
+			return errors.Wrapf(errNotConnectedToFinalized, "block %#x is not in the finalized block roots index", childRoot)
+		}
+		child := &zondpb.FinalizedBlockRootContainer{}
+		if err := decode(ctx, childEnc, child); err != nil {
+			return err
+		}
+		if !bytes.Equal(child.ParentRoot, highest[:]) {
+			return errors.Wrapf(errNotConnectedToFinalized, "parent of block %#x is %#x, not %#x", childRoot, child.ParentRoot, highest)
+		}
+		if err := s.putBlocks(ctx, tx, enc); err != nil {
+			return err
+		}
+		for i := range blks {
+			r := blks[i].Root()
+			if err := bkt.Put(r[:], containers[i]); err != nil {
+				tracing.AnnotateError(span, err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // IsFinalizedBlock returns true if the block root is present in the finalized block root index.
 // A beacon block root contained exists in this index if it is considered finalized and canonical.
 // Note: beacon blocks from the latest finalized epoch return true, whether or not they are
@@ -172,7 +235,7 @@ func (s *Store) IsFinalizedBlock(ctx context.Context, blockRoot [32]byte) bool {
 	defer span.End()
 
 	var exists bool
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		exists = tx.Bucket(finalizedBlockRootsIndexBucket).Get(blockRoot[:]) != nil
 		// Check genesis block root.
 		if !exists {
@@ -195,7 +258,7 @@ func (s *Store) FinalizedChildBlock(ctx context.Context, blockRoot [32]byte) (in
 	defer span.End()
 
 	var blk interfaces.ReadOnlySignedBeaconBlock
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		blkBytes := tx.Bucket(finalizedBlockRootsIndexBucket).Get(blockRoot[:])
 		if blkBytes == nil {
 			return nil
@@ -199,6 +199,46 @@ func sszRootOrDie(t *testing.T, block interfaces.ReadOnlySignedBeaconBlock) []by
 	return root[:]
 }
 
+func TestStore_SaveBackfillBlocks(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, db.SaveGenesisBlockRoot(ctx, genesisBlockRoot))
+	blks := makeBlocks(t, 0, 10, genesisBlockRoot)
+	ro := make([]consensusblocks.ROBlock, len(blks))
+	for i := range blks {
+		var err error
+		ro[i], err = consensusblocks.NewROBlock(blks[i])
+		require.NoError(t, err)
+	}
+	// The node was checkpoint synced from the block at index 6.
+	require.NoError(t, db.SaveBlocks(ctx, blks[6:]))
+	origin := ro[6].Root()
+	require.NoError(t, db.SaveOriginCheckpointBlockRoot(ctx, origin))
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	require.NoError(t, db.SaveState(ctx, st, origin))
+	require.NoError(t, db.SaveFinalizedCheckpoint(ctx, &zondpb.Checkpoint{Epoch: 0, Root: origin[:]}))
+
+	// A batch which is not the parent of the lowest block is refused.
+	require.ErrorIs(t, db.SaveBackfillBlocks(ctx, ro[2:5], origin), errNotConnectedToFinalized)
+	require.ErrorIs(t, db.SaveBackfillBlocks(ctx, ro[0:2], ro[3].Root()), errNotConnectedToFinalized)
+	require.ErrorIs(t, db.SaveBackfillBlocks(ctx, []consensusblocks.ROBlock{ro[3], ro[5]}, origin), errNotConnectedToFinalized)
+	assert.Equal(t, false, db.HasBlock(ctx, ro[4].Root()))
+
+	require.NoError(t, db.SaveBackfillBlocks(ctx, ro[3:6], origin))
+	require.NoError(t, db.SaveBackfillBlocks(ctx, ro[0:3], ro[3].Root()))
+	for i, b := range ro {
+		assert.Equal(t, true, db.HasBlock(ctx, b.Root()), "block at index %d was not saved", i)
+		assert.Equal(t, true, db.IsFinalizedBlock(ctx, b.Root()), "block at index %d is not finalized", i)
+	}
+	for i := 0; i < 6; i++ {
+		child, err := db.FinalizedChildBlock(ctx, ro[i].Root())
+		require.NoError(t, err)
+		assert.DeepEqual(t, blks[i+1], child, "child of the block at index %d", i)
+	}
+}
+
 func makeBlocks(t *testing.T, i, n uint64, previousRoot [32]byte) []interfaces.ReadOnlySignedBeaconBlock {
 	blocks := make([]*zondpb.SignedBeaconBlock, n)
 	ifaceBlocks := make([]interfaces.ReadOnlySignedBeaconBlock, n)
@@ -1,5 +1,5 @@
-// Package kv defines a bolt-db, key-value store implementation
-// of the Database interface defined by a Prysm beacon node.
+// Package kv defines a key-value store implementation of the Database
+// interface defined by a Prysm beacon node, on top of bolt-db or pebble.
 package kv
 
 import (
@@ -7,19 +7,16 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	prombolt "github.com/prysmaticlabs/prombbolt"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/iface"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/config/features"
-	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/io/file"
-	bolt "go.etcd.io/bbolt"
 )
 
 var _ iface.Database = (*Store)(nil)
@@ -34,12 +31,11 @@ const (
 	BeaconNodeDbDirName = "beaconchaindata"
 	// DatabaseFileName is the name of the beacon node database.
 	DatabaseFileName = "beaconchain.db"
+	// PebbleDatabaseDirName is the name of the directory of the beacon node database when stored with pebble.
+	PebbleDatabaseDirName = "beaconchain.pebble"
 
-	boltAllocSize = 8 * 1024 * 1024
 	// The size of hash length in bytes
 	hashLength = 32
-	// Specifies the initial mmap size of bolt.
-	mmapSize = 536870912
 )
 
 var (
@@ -82,13 +78,15 @@ var blockedBuckets = [][]byte{
 }
 
 // Store defines an implementation of the Prysm Database interface
-// using BoltDB as the underlying persistent kv-store for Ethereum Beacon Nodes.
+// using BoltDB or Pebble as the underlying persistent kv-store for Ethereum Beacon Nodes.
 type Store struct {
-	db                  *bolt.DB
+	db                  engine.DB
+	backend             engine.Backend
 	databasePath        string
 	blockCache          *ristretto.Cache
 	validatorEntryCache *ristretto.Cache
 	stateSummaryCache   *stateSummaryCache
+	metricsRegisterer   prometheus.Registerer
 	ctx                 context.Context
 }
 
@@ -99,6 +97,46 @@ func KVStoreDatafilePath(dirPath string) string {
 	return path.Join(dirPath, DatabaseFileName)
 }
 
+// KVStoreDatapath returns the path of the file or directory the database
+// of the given backend is stored at in the directory path.
+func KVStoreDatapath(dirPath string, backend engine.Backend) string {
+	if backend == engine.Pebble {
+		return path.Join(dirPath, PebbleDatabaseDirName)
+	}
+	return KVStoreDatafilePath(dirPath)
+}
+
+// DetectBackend returns the backend of the database stored in the directory
+// path, and false if there is no database in it.
+func DetectBackend(dirPath string) (engine.Backend, bool, error) {
+	hasPebble, err := file.HasDir(KVStoreDatapath(dirPath, engine.Pebble))
+	if err != nil {
+		return "", false, err
+	}
+	switch {
+	case file.FileExists(KVStoreDatafilePath(dirPath)):
+		return engine.Bolt, true, nil
+	case hasPebble:
+		return engine.Pebble, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+type storeConfig struct {
+	backend engine.Backend
+}
+
+// KVStoreOption configures how NewKVStore opens the database.
+type KVStoreOption func(*storeConfig)
+
+// WithBackend sets the storage engine of the database. Bolt is used by default.
+func WithBackend(backend engine.Backend) KVStoreOption {
+	return func(c *storeConfig) {
+		c.backend = backend
+	}
+}
+
 var Buckets = [][]byte{
 	attestationsBucket,
 	blocksBucket,
@@ -131,12 +169,21 @@ var Buckets = [][]byte{
 	registrationBucket,
 
 	blobsBucket,
+
+	lightClientUpdatesBucket,
+
+	stateDiffBucket,
+	stateDiffRootIndexBucket,
 }
 
-// NewKVStore initializes a new boltDB key-value store at the directory
+// NewKVStore initializes a new key-value store at the directory
 // path specified, creates the kv-buckets based on the schema, and stores
 // an open connection db object as a property of the Store struct.
-func NewKVStore(ctx context.Context, dirPath string) (*Store, error) {
+func NewKVStore(ctx context.Context, dirPath string, opts ...KVStoreOption) (*Store, error) {
+	cfg := &storeConfig{backend: engine.Bolt}
+	for _, o := range opts {
+		o(cfg)
+	}
 	hasDir, err := file.HasDir(dirPath)
 	if err != nil {
 		return nil, err
@@ -146,23 +193,25 @@ func NewKVStore(ctx context.Context, dirPath string) (*Store, error) {
 			return nil, err
 		}
 	}
-	datafile := KVStoreDatafilePath(dirPath)
-	log.Infof("Opening Bolt DB at %s", datafile)
-	boltDB, err := bolt.Open(
-		datafile,
-		params.BeaconIoConfig().ReadWritePermissions,
-		&bolt.Options{
-			Timeout:         1 * time.Second,
-			InitialMmapSize: mmapSize,
-		},
-	)
+	// Refuse to create an empty database next to the existing database of another backend.
+	existing, ok, err := DetectBackend(dirPath)
 	if err != nil {
-		if errors.Is(err, bolt.ErrTimeout) {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		return nil, err
+	}
+	if ok && existing != cfg.backend {
+		if _, err := os.Stat(KVStoreDatapath(dirPath, cfg.backend)); os.IsNotExist(err) {
+			return nil, fmt.Errorf(
+				"the database in %s uses the %s backend, use --db-backend=%s or convert it with `qrysmctl db convert`",
+				dirPath, existing, existing,
+			)
 		}
+	}
+	datapath := KVStoreDatapath(dirPath, cfg.backend)
+	log.WithField("backend", cfg.backend).Infof("Opening DB at %s", datapath)
+	db, err := engine.Open(cfg.backend, datapath, nil)
+	if err != nil {
 		return nil, err
 	}
-	boltDB.AllocSize = boltAllocSize
 	blockCache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1000,           // number of keys to track frequency of (1000).
 		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
@@ -182,19 +231,22 @@ func NewKVStore(ctx context.Context, dirPath string) (*Store, error) {
 	}
 
 	kv := &Store{
-		db:                  boltDB,
+		db:                  db,
+		backend:             cfg.backend,
 		databasePath:        dirPath,
 		blockCache:          blockCache,
 		validatorEntryCache: validatorCache,
 		stateSummaryCache:   newStateSummaryCache(),
-		ctx:                 ctx,
+		// The database metrics are labelled by path, so that the stores opened by one process do not collide.
+		metricsRegisterer: prometheus.WrapRegistererWith(prometheus.Labels{"path": dirPath}, prometheus.DefaultRegisterer),
+		ctx:               ctx,
 	}
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
+	if err := kv.db.Update(func(tx engine.Tx) error {
 		return createBuckets(tx, Buckets...)
 	}); err != nil {
 		return nil, err
 	}
-	if err = prometheus.Register(createBoltCollector(kv.db)); err != nil {
+	if err = kv.metricsRegisterer.Register(kv.db.Collector(blockedBuckets...)); err != nil {
 		return nil, err
 	}
 	// Setup the type of block storage used depending on whether or not this is a fresh database.
@@ -202,7 +254,7 @@ func NewKVStore(ctx context.Context, dirPath string) (*Store, error) {
 		return nil, err
 	}
 
-	if err := checkEpochsForBlobSidecarsRequestBucket(boltDB); err != nil {
+	if err := checkEpochsForBlobSidecarsRequestBucket(kv.db); err != nil {
 		return nil, errors.Wrap(err, "failed to check epochs for blob sidecars request bucket")
 	}
 
@@ -214,16 +266,16 @@ func (s *Store) ClearDB() error {
 	if _, err := os.Stat(s.databasePath); os.IsNotExist(err) {
 		return nil
 	}
-	prometheus.Unregister(createBoltCollector(s.db))
-	if err := os.Remove(path.Join(s.databasePath, DatabaseFileName)); err != nil {
+	s.metricsRegisterer.Unregister(s.db.Collector(blockedBuckets...))
+	if err := os.RemoveAll(KVStoreDatapath(s.databasePath, s.backend)); err != nil {
 		return errors.Wrap(err, "could not remove database file")
 	}
 	return nil
 }
 
-// Close closes the underlying BoltDB database.
+// Close closes the underlying database.
 func (s *Store) Close() error {
-	prometheus.Unregister(createBoltCollector(s.db))
+	s.metricsRegisterer.Unregister(s.db.Collector(blockedBuckets...))
 
 	// Before DB closes, we should dump the cached state summary objects to DB.
 	if err := s.saveCachedStateSummariesDB(s.ctx); err != nil {
@@ -238,6 +290,11 @@ func (s *Store) DatabasePath() string {
 	return s.databasePath
 }
 
+// Backend is the storage engine of this database.
+func (s *Store) Backend() engine.Backend {
+	return s.backend
+}
+
 func (s *Store) setupBlockStorageType(ctx context.Context) error {
 	// We check if we want to save blinded beacon blocks by checking a key in the db
 	// otherwise, we check the last stored block and set that key in the DB if it is blinded.
@@ -250,7 +307,7 @@ func (s *Store) setupBlockStorageType(ctx context.Context) error {
 	saveFull := features.Get().SaveFullExecutionPayloads
 
 	var saveBlinded bool
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		// If we have a key stating we wish to save blinded beacon blocks, then we set saveBlinded to true.
 		metadataBkt := tx.Bucket(chainMetadataBucket)
 		keyExists := len(metadataBkt.Get(saveBlindedBeaconBlocksKey)) > 0
@@ -294,7 +351,7 @@ func (s *Store) setupBlockStorageType(ctx context.Context) error {
 	return nil
 }
 
-func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
+func createBuckets(tx engine.Tx, buckets ...[]byte) error {
 	for _, bucket := range buckets {
 		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
 			return err
@@ -302,8 +359,3 @@ func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
 	}
 	return nil
 }
-
-// createBoltCollector returns a prometheus collector specifically configured for boltdb.
-func createBoltCollector(db *bolt.DB) prometheus.Collector {
-	return prombolt.New("boltDB", db, blockedBuckets...)
-}
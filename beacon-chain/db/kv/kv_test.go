@@ -2,20 +2,37 @@ package kv
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"testing"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
-	bolt "go.etcd.io/bbolt"
 )
 
+// The kv tests run against bolt by default. Run them against another backend with
+// go test ./beacon-chain/db/kv -args -db-backend=pebble
+var dbBackendFlag = flag.String("db-backend", string(engine.Bolt), "Database backend to run the tests against")
+
+// testBackends are the backends compared by the backend benchmarks.
+var testBackends = []engine.Backend{engine.Bolt, engine.Pebble}
+
+func testBackend() engine.Backend {
+	return engine.Backend(*dbBackendFlag)
+}
+
 // setupDB instantiates and returns a Store instance.
 func setupDB(t testing.TB) *Store {
-	db, err := NewKVStore(context.Background(), t.TempDir())
+	return setupDBWithBackend(t, testBackend())
+}
+
+// setupDBWithBackend instantiates and returns a Store instance of the given backend.
+func setupDBWithBackend(t testing.TB, backend engine.Backend) *Store {
+	db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(backend))
 	require.NoError(t, err, "Failed to instantiate DB")
 	t.Cleanup(func() {
 		require.NoError(t, db.Close(), "Failed to close database")
@@ -23,6 +40,36 @@ func setupDB(t testing.TB) *Store {
 	return db
 }
 
+func TestNewKVStore_BackendMismatch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db, err := NewKVStore(ctx, dir, WithBackend(engine.Bolt))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = NewKVStore(ctx, dir, WithBackend(engine.Pebble))
+	require.ErrorContains(t, "uses the bolt backend", err)
+
+	backend, ok, err := DetectBackend(dir)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+	require.Equal(t, engine.Bolt, backend)
+}
+
+func TestNewKVStore_SeveralStoresRegisterMetrics(t *testing.T) {
+	db1 := setupDB(t)
+	db2, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(testBackend()))
+	require.NoError(t, err)
+
+	// Closing a store leaves the metrics of the other store registered.
+	require.NoError(t, db2.Close())
+	err = db1.metricsRegisterer.Register(db1.db.Collector(blockedBuckets...))
+	require.ErrorContains(t, "duplicate metrics collector registration attempted", err)
+	err = db2.metricsRegisterer.Register(db2.db.Collector(blockedBuckets...))
+	require.NoError(t, err)
+	require.Equal(t, true, db2.metricsRegisterer.Unregister(db2.db.Collector(blockedBuckets...)))
+}
+
 func Test_setupBlockStorageType(t *testing.T) {
 	ctx := context.Background()
 	t.Run("fresh database with feature enabled to store full blocks should store full blocks", func(t *testing.T) {
@@ -72,7 +119,7 @@ func Test_setupBlockStorageType(t *testing.T) {
 	})
 	t.Run("existing database with blinded blocks but no key in metadata bucket should continue storing blinded blocks", func(t *testing.T) {
 		store := setupDB(t)
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
+		require.NoError(t, store.db.Update(func(tx engine.Tx) error {
 			return tx.Bucket(chainMetadataBucket).Put(saveBlindedBeaconBlocksKey, []byte{1})
 		}))
 
@@ -91,7 +138,7 @@ func Test_setupBlockStorageType(t *testing.T) {
 		require.DeepEqual(t, wrappedBlock, retrievedBlk)
 
 		// We then delete the key from the bucket.
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
+		require.NoError(t, store.db.Update(func(tx engine.Tx) error {
 			return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
 		}))
 
@@ -100,7 +147,7 @@ func Test_setupBlockStorageType(t *testing.T) {
 		require.NoError(t, err)
 
 		var shouldSaveBlinded bool
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
+		require.NoError(t, store.db.Update(func(tx engine.Tx) error {
 			bkt := tx.Bucket(chainMetadataBucket)
 			shouldSaveBlinded = len(bkt.Get(saveBlindedBeaconBlocksKey)) > 0
 			return nil
@@ -126,7 +173,7 @@ func Test_setupBlockStorageType(t *testing.T) {
 	})
 	t.Run("existing database with full blocks type should continue storing full blocks", func(t *testing.T) {
 		store := setupDB(t)
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
+		require.NoError(t, store.db.Update(func(tx engine.Tx) error {
 			return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
 		}))
 
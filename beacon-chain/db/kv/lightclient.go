@@ -0,0 +1,74 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"go.opencensus.io/trace"
+)
+
+// SaveLightClientUpdate saves the best light client update of a sync committee period, replacing any update
+// previously saved for that period.
+func (s *Store) SaveLightClientUpdate(ctx context.Context, period uint64, update *zondpbv2.LightClientUpdate) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveLightClientUpdate")
+	defer span.End()
+
+	enc, err := encode(ctx, update)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx engine.Tx) error {
+		bkt := tx.Bucket(lightClientUpdatesBucket)
+		return bkt.Put(bytesutil.Uint64ToBytesBigEndian(period), enc)
+	})
+}
+
+// LightClientUpdate retrieves the best light client update of a sync committee period. A nil update is returned
+// if no update was saved for the period.
+func (s *Store) LightClientUpdate(ctx context.Context, period uint64) (*zondpbv2.LightClientUpdate, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.LightClientUpdate")
+	defer span.End()
+
+	var update *zondpbv2.LightClientUpdate
+	err := s.db.View(func(tx engine.Tx) error {
+		enc := tx.Bucket(lightClientUpdatesBucket).Get(bytesutil.Uint64ToBytesBigEndian(period))
+		if enc == nil {
+			return nil
+		}
+		update = &zondpbv2.LightClientUpdate{}
+		return decode(ctx, enc, update)
+	})
+	return update, err
+}
+
+// LightClientUpdates retrieves the best light client updates of the sync committee periods in [startPeriod,
+// endPeriod], keyed by period. Periods without a saved update are absent from the result.
+func (s *Store) LightClientUpdates(ctx context.Context, startPeriod, endPeriod uint64) (map[uint64]*zondpbv2.LightClientUpdate, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.LightClientUpdates")
+	defer span.End()
+
+	if startPeriod > endPeriod {
+		return nil, errors.Errorf("start period %d is greater than end period %d", startPeriod, endPeriod)
+	}
+	updates := make(map[uint64]*zondpbv2.LightClientUpdate)
+	err := s.db.View(func(tx engine.Tx) error {
+		c := tx.Bucket(lightClientUpdatesBucket).Cursor()
+		for k, v := c.Seek(bytesutil.Uint64ToBytesBigEndian(startPeriod)); k != nil; k, v = c.Next() {
+			period := binary.BigEndian.Uint64(k)
+			if period > endPeriod {
+				break
+			}
+			update := &zondpbv2.LightClientUpdate{}
+			if err := decode(ctx, v, update); err != nil {
+				return err
+			}
+			updates[period] = update
+		}
+		return nil
+	})
+	return updates, err
+}
@@ -0,0 +1,104 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/go-qrllib/dilithium"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func testLightClientUpdate(slot primitives.Slot) *zondpbv2.LightClientUpdate {
+	header := func(s primitives.Slot) *zondpbv1.BeaconBlockHeader {
+		return &zondpbv1.BeaconBlockHeader{
+			Slot:       s,
+			ParentRoot: make([]byte, 32),
+			StateRoot:  make([]byte, 32),
+			BodyRoot:   make([]byte, 32),
+		}
+	}
+	branch := func(n int) [][]byte {
+		b := make([][]byte, n)
+		for i := range b {
+			b[i] = bytesutil.PadTo([]byte{byte(i)}, 32)
+		}
+		return b
+	}
+	pubkeys := make([][]byte, fieldparams.SyncCommitteeLength)
+	for i := range pubkeys {
+		pubkeys[i] = make([]byte, dilithium.CryptoPublicKeyBytes)
+	}
+	return &zondpbv2.LightClientUpdate{
+		AttestedHeader: header(slot),
+		NextSyncCommittee: &zondpbv2.SyncCommittee{
+			Pubkeys:         pubkeys,
+			AggregatePubkey: make([]byte, fieldparams.SyncCommitteeLength*dilithium.CryptoPublicKeyBytes),
+		},
+		NextSyncCommitteeBranch: branch(5),
+		FinalizedHeader:         header(0),
+		FinalityBranch:          branch(6),
+		SyncAggregate: &zondpbv1.SyncAggregate{
+			SyncCommitteeBits:      []byte{0xff, 0xff},
+			SyncCommitteeSignature: make([]byte, dilithium.CryptoBytes*fieldparams.SyncCommitteeLength),
+		},
+		SignatureSlot: slot + 1,
+	}
+}
+
+func TestStore_LightClientUpdate(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	got, err := db.LightClientUpdate(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*zondpbv2.LightClientUpdate)(nil), got)
+
+	update := testLightClientUpdate(10)
+	require.NoError(t, db.SaveLightClientUpdate(ctx, 1, update))
+	got, err = db.LightClientUpdate(ctx, 1)
+	require.NoError(t, err)
+	assert.DeepSSZEqual(t, update, got)
+
+	// A later save replaces the update of the period.
+	update = testLightClientUpdate(20)
+	require.NoError(t, db.SaveLightClientUpdate(ctx, 1, update))
+	got, err = db.LightClientUpdate(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(20), got.AttestedHeader.Slot)
+}
+
+func TestStore_LightClientUpdates(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	for _, period := range []uint64{1, 2, 3, 5, 256} {
+		require.NoError(t, db.SaveLightClientUpdate(ctx, period, testLightClientUpdate(primitives.Slot(period))))
+	}
+
+	updates, err := db.LightClientUpdates(ctx, 2, 5)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(updates))
+	for _, period := range []uint64{2, 3, 5} {
+		u, ok := updates[period]
+		require.Equal(t, true, ok)
+		assert.Equal(t, primitives.Slot(period), u.AttestedHeader.Slot)
+	}
+
+	updates, err = db.LightClientUpdates(ctx, 4, 300)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(updates))
+	assert.Equal(t, primitives.Slot(256), updates[256].AttestedHeader.Slot)
+
+	updates, err = db.LightClientUpdates(ctx, 6, 255)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(updates))
+
+	_, err = db.LightClientUpdates(ctx, 3, 2)
+	require.ErrorContains(t, "start period 3 is greater than end period 2", err)
+}
@@ -4,16 +4,16 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 )
 
 var migrationArchivedIndex0Key = []byte("archive_index_0")
 
-func migrateArchivedIndex(ctx context.Context, db *bolt.DB) error {
-	if updateErr := db.Update(func(tx *bolt.Tx) error {
+func migrateArchivedIndex(ctx context.Context, db engine.DB) error {
+	if updateErr := db.Update(func(tx engine.Tx) error {
 		mb := tx.Bucket(migrationsBucket)
 		if b := mb.Get(migrationArchivedIndex0Key); bytes.Equal(b, migrationCompleted) {
 			return nil // Migration already completed.
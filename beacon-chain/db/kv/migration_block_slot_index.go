@@ -5,14 +5,14 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
-	bolt "go.etcd.io/bbolt"
 )
 
 var migrationBlockSlotIndex0Key = []byte("block_slot_index_0")
 
-func migrateBlockSlotIndex(ctx context.Context, db *bolt.DB) error {
-	if updateErr := db.Update(func(tx *bolt.Tx) error {
+func migrateBlockSlotIndex(ctx context.Context, db engine.DB) error {
+	if updateErr := db.Update(func(tx engine.Tx) error {
 		mb := tx.Bucket(migrationsBucket)
 		if b := mb.Get(migrationBlockSlotIndex0Key); bytes.Equal(b, migrationCompleted) {
 			return nil // Migration already completed.
@@ -4,21 +4,21 @@ import (
 	"context"
 	"testing"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/testing/assert"
-	"go.etcd.io/bbolt"
 )
 
 func Test_migrateBlockSlotIndex(t *testing.T) {
 	tests := []struct {
 		name  string
-		setup func(t *testing.T, db *bbolt.DB)
-		eval  func(t *testing.T, db *bbolt.DB)
+		setup func(t *testing.T, db engine.DB)
+		eval  func(t *testing.T, db engine.DB)
 	}{
 		{
 			name: "only runs once",
-			setup: func(t *testing.T, db *bbolt.DB) {
-				err := db.Update(func(tx *bbolt.Tx) error {
+			setup: func(t *testing.T, db engine.DB) {
+				err := db.Update(func(tx engine.Tx) error {
 					if err := tx.Bucket(blockSlotIndicesBucket).Put([]byte("2048"), []byte("foo")); err != nil {
 						return err
 					}
@@ -26,8 +26,8 @@ func Test_migrateBlockSlotIndex(t *testing.T) {
 				})
 				assert.NoError(t, err)
 			},
-			eval: func(t *testing.T, db *bbolt.DB) {
-				err := db.View(func(tx *bbolt.Tx) error {
+			eval: func(t *testing.T, db engine.DB) {
+				err := db.View(func(tx engine.Tx) error {
 					v := tx.Bucket(blockSlotIndicesBucket).Get([]byte("2048"))
 					assert.DeepEqual(t, []byte("foo"), v, "Did not receive correct data for key 2048")
 					return nil
@@ -37,14 +37,14 @@ func Test_migrateBlockSlotIndex(t *testing.T) {
 		},
 		{
 			name: "migrates and deletes entries",
-			setup: func(t *testing.T, db *bbolt.DB) {
-				err := db.Update(func(tx *bbolt.Tx) error {
+			setup: func(t *testing.T, db engine.DB) {
+				err := db.Update(func(tx engine.Tx) error {
 					return tx.Bucket(blockSlotIndicesBucket).Put([]byte("2048"), []byte("foo"))
 				})
 				assert.NoError(t, err)
 			},
-			eval: func(t *testing.T, db *bbolt.DB) {
-				err := db.View(func(tx *bbolt.Tx) error {
+			eval: func(t *testing.T, db engine.DB) {
+				err := db.View(func(tx engine.Tx) error {
 					k := uint64(2048)
 					v := tx.Bucket(blockSlotIndicesBucket).Get(bytesutil.Uint64ToBytesBigEndian(k))
 					assert.DeepEqual(t, []byte("foo"), v, "Did not receive correct data for key %d", k)
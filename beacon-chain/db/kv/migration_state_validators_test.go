@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/golang/snappy"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	state_native "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
 	"github.com/theQRL/qrysm/v4/config/features"
@@ -14,7 +15,6 @@ import (
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
-	"go.etcd.io/bbolt"
 )
 
 func Test_migrateStateValidators(t *testing.T) {
@@ -27,7 +27,7 @@ func Test_migrateStateValidators(t *testing.T) {
 			name: "only runs once",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -38,7 +38,7 @@ func Test_migrateStateValidators(t *testing.T) {
 			},
 			eval: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// check if the migration is completed, per migration table.
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					migrationCompleteOrNot := tx.Bucket(migrationsBucket).Get(migrationStateValidatorsKey)
 					assert.DeepEqual(t, migrationCompleted, migrationCompleteOrNot, "migration is not complete")
 					return nil
@@ -50,7 +50,7 @@ func Test_migrateStateValidators(t *testing.T) {
 			name: "once migrated, always enable flag",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -67,7 +67,7 @@ func Test_migrateStateValidators(t *testing.T) {
 				defer resetCfg()
 
 				// check if the migration is completed, per migration table.
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					migrationCompleteOrNot := tx.Bucket(migrationsBucket).Get(migrationStateValidatorsKey)
 					assert.DeepEqual(t, migrationCompleted, migrationCompleteOrNot, "migration is not complete")
 					return nil
@@ -114,7 +114,7 @@ func Test_migrateStateValidators(t *testing.T) {
 			name: "migrates validators and adds them to new buckets",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -125,7 +125,7 @@ func Test_migrateStateValidators(t *testing.T) {
 			},
 			eval: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// check whether the new buckets are present
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					valBkt := tx.Bucket(stateValidatorsBucket)
 					assert.NotNil(t, valBkt)
 					idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
@@ -169,7 +169,7 @@ func Test_migrateStateValidators(t *testing.T) {
 				require.Equal(t, len(vals), validatorsFoundCount)
 
 				// check if the state validator indexes are stored properly
-				err = dbStore.db.View(func(tx *bbolt.Tx) error {
+				err = dbStore.db.View(func(tx engine.Tx) error {
 					rcvdValhashBytes := tx.Bucket(blockRootValidatorHashesBucket).Get(blockRoot[:])
 					rcvdValHashes, sErr := snappy.Decode(nil, rcvdValhashBytes)
 					assert.NoError(t, sErr)
@@ -217,7 +217,7 @@ func Test_migrateAltairStateValidators(t *testing.T) {
 			name: "migrates validators and adds them to new buckets",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -228,7 +228,7 @@ func Test_migrateAltairStateValidators(t *testing.T) {
 			},
 			eval: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// check whether the new buckets are present
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					valBkt := tx.Bucket(stateValidatorsBucket)
 					assert.NotNil(t, valBkt)
 					idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
@@ -272,7 +272,7 @@ func Test_migrateAltairStateValidators(t *testing.T) {
 				require.Equal(t, len(vals), validatorsFoundCount)
 
 				// check if the state validator indexes are stored properly
-				err = dbStore.db.View(func(tx *bbolt.Tx) error {
+				err = dbStore.db.View(func(tx engine.Tx) error {
 					rcvdValhashBytes := tx.Bucket(blockRootValidatorHashesBucket).Get(blockRoot[:])
 					rcvdValHashes, sErr := snappy.Decode(nil, rcvdValhashBytes)
 					assert.NoError(t, sErr)
@@ -324,7 +324,7 @@ func Test_migrateBellatrixStateValidators(t *testing.T) {
 			name: "migrates validators and adds them to new buckets",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -335,7 +335,7 @@ func Test_migrateBellatrixStateValidators(t *testing.T) {
 			},
 			eval: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// check whether the new buckets are present
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					valBkt := tx.Bucket(stateValidatorsBucket)
 					assert.NotNil(t, valBkt)
 					idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
@@ -379,7 +379,7 @@ func Test_migrateBellatrixStateValidators(t *testing.T) {
 				require.Equal(t, len(vals), validatorsFoundCount)
 
 				// check if the state validator indexes are stored properly
-				err = dbStore.db.View(func(tx *bbolt.Tx) error {
+				err = dbStore.db.View(func(tx engine.Tx) error {
 					rcvdValhashBytes := tx.Bucket(blockRootValidatorHashesBucket).Get(blockRoot[:])
 					rcvdValHashes, sErr := snappy.Decode(nil, rcvdValhashBytes)
 					assert.NoError(t, sErr)
@@ -431,7 +431,7 @@ func Test_migrateCapellaStateValidators(t *testing.T) {
 			name: "migrates validators and adds them to new buckets",
 			setup: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// create some new buckets that should be present for this migration
-				err := dbStore.db.Update(func(tx *bbolt.Tx) error {
+				err := dbStore.db.Update(func(tx engine.Tx) error {
 					_, err := tx.CreateBucketIfNotExists(stateValidatorsBucket)
 					assert.NoError(t, err)
 					_, err = tx.CreateBucketIfNotExists(blockRootValidatorHashesBucket)
@@ -442,7 +442,7 @@ func Test_migrateCapellaStateValidators(t *testing.T) {
 			},
 			eval: func(t *testing.T, dbStore *Store, state state.BeaconState, vals []*v1alpha1.Validator) {
 				// check whether the new buckets are present
-				err := dbStore.db.View(func(tx *bbolt.Tx) error {
+				err := dbStore.db.View(func(tx engine.Tx) error {
 					valBkt := tx.Bucket(stateValidatorsBucket)
 					assert.NotNil(t, valBkt)
 					idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
@@ -486,7 +486,7 @@ func Test_migrateCapellaStateValidators(t *testing.T) {
 				require.Equal(t, len(vals), validatorsFoundCount)
 
 				// check if the state validator indexes are stored properly
-				err = dbStore.db.View(func(tx *bbolt.Tx) error {
+				err = dbStore.db.View(func(tx engine.Tx) error {
 					rcvdValhashBytes := tx.Bucket(blockRootValidatorHashesBucket).Get(blockRoot[:])
 					rcvdValHashes, sErr := snappy.Decode(nil, rcvdValhashBytes)
 					assert.NoError(t, sErr)
@@ -0,0 +1,233 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"go.opencensus.io/trace"
+)
+
+// pruneHistoryBatchSlots is the number of slots pruned in a single transaction, so that pruning a long
+// history does not hold the DB lock for too long.
+const pruneHistoryBatchSlots = primitives.Slot(1024)
+
+// HistoryPrunedSlot returns the slot below which the blocks and states were deleted by PruneHistory.
+// The genesis block and state are never pruned. It returns 0 if the history was never pruned.
+func (s *Store) HistoryPrunedSlot(ctx context.Context) (primitives.Slot, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.HistoryPrunedSlot")
+	defer span.End()
+
+	var slot primitives.Slot
+	err := s.db.View(func(tx engine.Tx) error {
+		if enc := tx.Bucket(chainMetadataBucket).Get(historyPrunedSlotKey); enc != nil {
+			slot = bytesutil.BytesToSlotBigEndian(enc)
+		}
+		return nil
+	})
+	return slot, err
+}
+
+// PruneHistory deletes the blocks, state summaries, states and state diffs, along with their indices,
+// of every slot between genesis and the given slot, exclusive. It returns the number of deleted blocks.
+// The caller is responsible for only pruning finalized history, and for pruning below an archived point,
+// so that the states above the given slot can still be rebuilt.
+//
+// When the slot of the archived point is skipped, its state is stored under the root of the highest block
+// below the slot. That block, its state summary and its state are kept, until a later call prunes them.
+func (s *Store) PruneHistory(ctx context.Context, beforeSlot primitives.Slot) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneHistory")
+	defer span.End()
+
+	pruned, err := s.HistoryPrunedSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if pruned >= beforeSlot {
+		return 0, nil
+	}
+	// The block kept by the previous call, if any, is the highest block below the pruned slot.
+	if pruned > 0 {
+		slot, _, err := s.highestBlockBelowSlot(ctx, pruned)
+		if err != nil {
+			return 0, err
+		}
+		if slot > 0 {
+			pruned = slot
+		}
+	}
+	var keep [][32]byte
+	if _, roots, err := s.BlockRootsBySlot(ctx, beforeSlot); err != nil {
+		return 0, err
+	} else if len(roots) == 0 {
+		_, keep, err = s.highestBlockBelowSlot(ctx, beforeSlot)
+		if err != nil {
+			return 0, err
+		}
+	}
+	var numBlocks int
+	for start := pruned; start < beforeSlot; {
+		if ctx.Err() != nil {
+			return numBlocks, ctx.Err()
+		}
+		end := start + pruneHistoryBatchSlots
+		if end > beforeSlot {
+			end = beforeSlot
+		}
+		n, err := s.pruneHistoryRange(ctx, start, end, keep)
+		if err != nil {
+			return numBlocks, errors.Wrapf(err, "could not prune history between slots %d and %d", start, end)
+		}
+		numBlocks += n
+		start = end
+	}
+	return numBlocks, nil
+}
+
+// highestBlockBelowSlot returns the slot and roots of the highest block below the slot. Unlike
+// HighestRootsBelowSlot, it returns no roots rather than an error when not even a genesis block is saved.
+func (s *Store) highestBlockBelowSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, [][32]byte, error) {
+	fs, roots, err := s.HighestRootsBelowSlot(ctx, slot)
+	if errors.Is(err, ErrNotFoundGenesisBlockRoot) {
+		return 0, nil, nil
+	}
+	return fs, roots, err
+}
+
+// pruneHistoryRange deletes the history of the slots in [start, end), skipping genesis and the kept
+// block roots, and records end as the pruned slot in the same transaction.
+func (s *Store) pruneHistoryRange(ctx context.Context, start, end primitives.Slot, keep [][32]byte) (int, error) {
+	if start == 0 {
+		start = 1
+	}
+	var numBlocks int
+	err := s.db.Update(func(tx engine.Tx) error {
+		genesisRoot := tx.Bucket(blocksBucket).Get(genesisBlockRootKey)
+
+		blockSlots, blockRoots, err := rootsInSlotRange(tx.Bucket(blockSlotIndicesBucket), start, end)
+		if err != nil {
+			return errors.Wrap(err, "corrupt value in block slot index")
+		}
+		// The slot index of a kept block only indexes the kept block.
+		kept := make(map[string][]byte)
+		for i, r := range blockRoots {
+			if isKept(keep, r) {
+				kept[string(blockSlots[i])] = append(kept[string(blockSlots[i])], r[:]...)
+			}
+		}
+		for _, k := range blockSlots {
+			if err := tx.Bucket(blockSlotIndicesBucket).Delete(k); err != nil {
+				return err
+			}
+		}
+		for k, v := range kept {
+			if err := tx.Bucket(blockSlotIndicesBucket).Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		for _, r := range blockRoots {
+			if isKept(keep, r) {
+				continue
+			}
+			numBlocks++
+			for _, bkt := range [][]byte{blocksBucket, blockParentRootIndicesBucket, finalizedBlockRootsIndexBucket, stateSummaryBucket} {
+				if err := tx.Bucket(bkt).Delete(r[:]); err != nil {
+					return err
+				}
+			}
+			// The parent of every pruned block is pruned as well, except for genesis.
+			if genesisRoot != nil {
+				indices := map[string][]byte{string(blockParentRootIndicesBucket): genesisRoot}
+				if err := deleteValueForIndices(ctx, indices, r[:], tx); err != nil {
+					return errors.Wrap(err, "could not delete root for DB indices")
+				}
+			}
+			s.blockCache.Del(string(r[:]))
+			s.stateSummaryCache.delete(r)
+		}
+
+		stateSlots, stateRoots, err := rootsInSlotRange(tx.Bucket(stateSlotIndicesBucket), start, end)
+		if err != nil {
+			return errors.Wrap(err, "corrupt value in state slot index")
+		}
+		for i, r := range stateRoots {
+			if (genesisRoot != nil && bytes.Equal(r[:], genesisRoot)) || isKept(keep, r) {
+				continue
+			}
+			if err := s.deleteState(ctx, tx, r[:], bytesutil.BytesToSlotBigEndian(stateSlots[i])); err != nil {
+				return err
+			}
+		}
+
+		if err := pruneStateDiffs(tx, start, end); err != nil {
+			return err
+		}
+		return tx.Bucket(chainMetadataBucket).Put(historyPrunedSlotKey, bytesutil.SlotToBytesBigEndian(end))
+	})
+	return numBlocks, err
+}
+
+func isKept(keep [][32]byte, root [32]byte) bool {
+	for _, k := range keep {
+		if k == root {
+			return true
+		}
+	}
+	return false
+}
+
+// rootsInSlotRange returns the roots stored in a slot index bucket for the slots in [start, end),
+// along with the slot key of each root.
+func rootsInSlotRange(bkt engine.Bucket, start, end primitives.Slot) ([][]byte, [][32]byte, error) {
+	var keys [][]byte
+	var roots [][32]byte
+	max := bytesutil.SlotToBytesBigEndian(end)
+	c := bkt.Cursor()
+	for k, v := c.Seek(bytesutil.SlotToBytesBigEndian(start)); k != nil && bytes.Compare(k, max) < 0; k, v = c.Next() {
+		r, err := splitRoots(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		for range r {
+			keys = append(keys, bytesutil.SafeCopyBytes(k))
+		}
+		roots = append(roots, r...)
+	}
+	return keys, roots, nil
+}
+
+// pruneStateDiffs deletes the state diffs stored at the slots in [start, end) and their block root index.
+func pruneStateDiffs(tx engine.Tx, start, end primitives.Slot) error {
+	var keys [][]byte
+	max := bytesutil.SlotToBytesBigEndian(end)
+	c := tx.Bucket(stateDiffBucket).Cursor()
+	for k, _ := c.Seek(bytesutil.SlotToBytesBigEndian(start)); k != nil && bytes.Compare(k, max) < 0; k, _ = c.Next() {
+		keys = append(keys, bytesutil.SafeCopyBytes(k))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	for _, k := range keys {
+		if err := tx.Bucket(stateDiffBucket).Delete(k); err != nil {
+			return err
+		}
+	}
+
+	var roots [][]byte
+	c = tx.Bucket(stateDiffRootIndexBucket).Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		slot := bytesutil.BytesToSlotBigEndian(v)
+		if slot >= start && slot < end {
+			roots = append(roots, bytesutil.SafeCopyBytes(k))
+		}
+	}
+	for _, r := range roots {
+		if err := tx.Bucket(stateDiffRootIndexBucket).Delete(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/filters"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+func TestStore_PruneHistory(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	roots := make([][32]byte, 11)
+	for i := range roots {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = primitives.Slot(i)
+		if i > 0 {
+			b.Block.ParentRoot = roots[i-1][:]
+		}
+		wsb, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveBlock(ctx, wsb))
+		roots[i], err = b.Block.HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, db.SaveStateSummary(ctx, &zondpb.StateSummary{Slot: primitives.Slot(i), Root: roots[i][:]}))
+	}
+	require.NoError(t, db.SaveGenesisBlockRoot(ctx, roots[0]))
+	for _, i := range []int{0, 4, 8} {
+		st, err := util.NewBeaconState()
+		require.NoError(t, err)
+		require.NoError(t, st.SetSlot(primitives.Slot(i)))
+		require.NoError(t, db.SaveState(ctx, st, roots[i]))
+		require.NoError(t, db.SaveStateDiff(ctx, primitives.Slot(i), roots[i], []byte{byte(i)}))
+	}
+
+	slot, err := db.HistoryPrunedSlot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(0), slot)
+
+	n, err := db.PruneHistory(ctx, 6)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	slot, err = db.HistoryPrunedSlot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(6), slot)
+
+	for i, r := range roots {
+		kept := i == 0 || i >= 6
+		assert.Equal(t, kept, db.HasBlock(ctx, r), "block at slot %d", i)
+		assert.Equal(t, kept, db.HasStateSummary(ctx, r), "state summary at slot %d", i)
+	}
+	for _, i := range []int{0, 4, 8} {
+		kept := i != 4
+		assert.Equal(t, kept, db.HasState(ctx, roots[i]), "state at slot %d", i)
+		_, err := db.StateDiff(ctx, primitives.Slot(i))
+		assert.Equal(t, kept, err == nil, "state diff at slot %d", i)
+		_, err = db.StateDiffSlot(ctx, roots[i])
+		assert.Equal(t, kept, err == nil, "state diff root index at slot %d", i)
+	}
+	genesis, err := db.GenesisBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(0), genesis.Block().Slot())
+	children, err := db.BlockRoots(ctx, filters.NewFilter().SetParentRoot(roots[0][:]))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(children))
+	_, found, err := db.BlockRootsBySlot(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(found))
+
+	// Pruning below the pruned slot is a no-op.
+	n, err = db.PruneHistory(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Pruning spans multiple batches. The slot has no block, so the highest block below it is kept.
+	n, err = db.PruneHistory(ctx, 3*pruneHistoryBatchSlots)
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, true, db.HasBlock(ctx, roots[10]))
+	slot, err = db.HistoryPrunedSlot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3*pruneHistoryBatchSlots, slot)
+	assert.Equal(t, false, db.HasState(ctx, roots[8]))
+	assert.Equal(t, true, db.HasState(ctx, roots[0]))
+}
+
+func TestStore_PruneHistory_SkippedArchivePoint(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	// The archive points at slots 4 and 8 are skipped, so their states are stored under the roots of the
+	// blocks at slots 3 and 7.
+	roots := make(map[int][32]byte)
+	parent := [32]byte{}
+	for _, i := range []int{0, 1, 2, 3, 5, 6, 7, 9, 10} {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = primitives.Slot(i)
+		b.Block.ParentRoot = parent[:]
+		wsb, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveBlock(ctx, wsb))
+		parent, err = b.Block.HashTreeRoot()
+		require.NoError(t, err)
+		roots[i] = parent
+		require.NoError(t, db.SaveStateSummary(ctx, &zondpb.StateSummary{Slot: primitives.Slot(i), Root: parent[:]}))
+	}
+	require.NoError(t, db.SaveGenesisBlockRoot(ctx, roots[0]))
+	for _, i := range []int{0, 3, 7} {
+		st, err := util.NewBeaconState()
+		require.NoError(t, err)
+		require.NoError(t, st.SetSlot(primitives.Slot(i)))
+		require.NoError(t, db.SaveState(ctx, st, roots[i]))
+	}
+
+	n, err := db.PruneHistory(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	for i, r := range roots {
+		kept := i == 0 || i >= 3
+		assert.Equal(t, kept, db.HasBlock(ctx, r), "block at slot %d", i)
+		assert.Equal(t, kept, db.HasStateSummary(ctx, r), "state summary at slot %d", i)
+	}
+	assert.Equal(t, true, db.HasState(ctx, roots[3]), "state of the skipped archive point")
+	_, found, err := db.BlockRootsBySlot(ctx, 3)
+	require.NoError(t, err)
+	assert.DeepEqual(t, [][32]byte{roots[3]}, found)
+
+	// The block kept for the previous archive point is pruned along with the next range.
+	n, err = db.PruneHistory(ctx, 8)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	for i, r := range roots {
+		kept := i == 0 || i >= 7
+		assert.Equal(t, kept, db.HasBlock(ctx, r), "block at slot %d", i)
+		assert.Equal(t, kept, db.HasStateSummary(ctx, r), "state summary at slot %d", i)
+	}
+	assert.Equal(t, false, db.HasState(ctx, roots[3]), "state of the pruned archive point")
+	assert.Equal(t, true, db.HasState(ctx, roots[7]), "state of the skipped archive point")
+	assert.Equal(t, true, db.HasState(ctx, roots[0]), "genesis state")
+	slot, err := db.HistoryPrunedSlot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(8), slot)
+}
+
+func TestStore_PruneHistory_NoBlocks(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	// Without any block, not even genesis, there is nothing to keep nor to prune.
+	n, err := db.PruneHistory(ctx, 64)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	n, err = db.PruneHistory(ctx, 128)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	slot, err := db.HistoryPrunedSlot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(128), slot)
+}
@@ -8,7 +8,7 @@ package kv
 // corresponding attestations.
 var (
 	attestationsBucket      = []byte("attestations")
-	blobsBucket             = []byte("blobs")
+	blobsBucket             = []byte("blobs") // Legacy blob sidecars, migrated to the blob storage.
 	blocksBucket            = []byte("blocks")
 	stateBucket             = []byte("state")
 	stateSummaryBucket      = []byte("state-summary")
@@ -22,6 +22,13 @@ var (
 	feeRecipientBucket      = []byte("fee-recipient")
 	registrationBucket      = []byte("registration")
 
+	// Best light client update of each sync committee period, keyed by period.
+	lightClientUpdatesBucket = []byte("light-client-updates")
+
+	// Hierarchical state diffs of historical states keyed by slot, and the slot index of their block roots.
+	stateDiffBucket          = []byte("state-diff")
+	stateDiffRootIndexBucket = []byte("state-diff-root-index")
+
 	// Deprecated: This bucket was migrated in PR 6461. Do not use, except for migrations.
 	slotsHasObjectBucket = []byte("slots-has-objects")
 	// Deprecated: This bucket was migrated in PR 6461. Do not use, except for migrations.
@@ -67,6 +74,8 @@ var (
 	originCheckpointBlockRootKey = []byte("origin-checkpoint-block-root")
 	// block root tracking the progress of backfill, or pointing at genesis if backfill has not been initiated
 	backfillBlockRootKey = []byte("backfill-block-root")
+	// slot below which history pruning deleted the blocks and states, except for genesis
+	historyPrunedSlotKey = []byte("history-pruned-slot")
 
 	// Deprecated: This index key was migrated in PR 6461. Do not use, except for migrations.
 	lastArchivedIndexKey = []byte("last-archived")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/snappy"
 	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/genesis"
 	statenative "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
@@ -19,7 +20,6 @@ import (
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/time"
 	"github.com/theQRL/qrysm/v4/time/slots"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -80,7 +80,7 @@ func (s *Store) GenesisState(ctx context.Context) (state.BeaconState, error) {
 	}
 
 	var st state.BeaconState
-	err = s.db.View(func(tx *bolt.Tx) error {
+	err = s.db.View(func(tx engine.Tx) error {
 		// Retrieve genesis block's signing root from blocks bucket,
 		// to look up what the genesis state is.
 		bucket := tx.Bucket(blocksBucket)
@@ -140,7 +140,7 @@ func (s *Store) SaveStates(ctx context.Context, states []state.ReadOnlyBeaconSta
 		multipleEncs[i] = stateBytes
 	}
 
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(stateBucket)
 		for i, rt := range blockRoots {
 			indicesByBucket := createStateIndicesFromStateSlot(ctx, states[i].Slot())
@@ -175,7 +175,7 @@ func (s *Store) SaveStatesEfficient(ctx context.Context, states []state.ReadOnly
 		return err
 	}
 
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		return s.saveStatesEfficientInternal(ctx, tx, blockRoots, states, validatorKeys, validatorsEntries)
 	}); err != nil {
 		return err
@@ -213,7 +213,7 @@ func getValidators(states []state.ReadOnlyBeaconState) ([][]byte, map[string]*zo
 	return validatorKeys, validatorsEntries, nil
 }
 
-func (s *Store) saveStatesEfficientInternal(ctx context.Context, tx *bolt.Tx, blockRoots [][32]byte, states []state.ReadOnlyBeaconState, validatorKeys [][]byte, validatorsEntries map[string]*zondpb.Validator) error {
+func (s *Store) saveStatesEfficientInternal(ctx context.Context, tx engine.Tx, blockRoots [][32]byte, states []state.ReadOnlyBeaconState, validatorKeys [][]byte, validatorsEntries map[string]*zondpb.Validator) error {
 	bucket := tx.Bucket(stateBucket)
 	valIdxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 	for i, rt := range blockRoots {
@@ -386,7 +386,7 @@ func getDenebPbState(rawState interface{}) (*zondpb.BeaconStateDeneb, error) {
 	return pbState, nil
 }
 
-func (s *Store) storeValidatorEntriesSeparately(ctx context.Context, tx *bolt.Tx, validatorsEntries map[string]*zondpb.Validator) error {
+func (s *Store) storeValidatorEntriesSeparately(ctx context.Context, tx engine.Tx, validatorsEntries map[string]*zondpb.Validator) error {
 	valBkt := tx.Bucket(stateValidatorsBucket)
 	for hashStr, validatorEntry := range validatorsEntries {
 		key := []byte(hashStr)
@@ -416,7 +416,7 @@ func (s *Store) HasState(ctx context.Context, blockRoot [32]byte) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasState")
 	defer span.End()
 	hasState := false
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(stateBucket)
 		stBytes := bkt.Get(blockRoot[:])
 		if len(stBytes) > 0 {
@@ -435,7 +435,7 @@ func (s *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteState")
 	defer span.End()
 
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bkt.Get(genesisBlockRootKey)
 
@@ -472,44 +472,50 @@ func (s *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 		if err != nil {
 			return err
 		}
-		indicesByBucket := createStateIndicesFromStateSlot(ctx, slot)
-		if err := deleteValueForIndices(ctx, indicesByBucket, blockRoot[:], tx); err != nil {
-			return errors.Wrap(err, "could not delete root for DB indices")
-		}
+		return s.deleteState(ctx, tx, blockRoot[:], slot)
+	})
+}
 
-		ok, err := s.isStateValidatorMigrationOver()
+// deleteState removes the state of the block root at the given slot, along with its slot index
+// and validator entry keys. It does not check whether the state is safe to delete.
+func (s *Store) deleteState(ctx context.Context, tx engine.Tx, blockRoot []byte, slot primitives.Slot) error {
+	indicesByBucket := createStateIndicesFromStateSlot(ctx, slot)
+	if err := deleteValueForIndices(ctx, indicesByBucket, blockRoot, tx); err != nil {
+		return errors.Wrap(err, "could not delete root for DB indices")
+	}
+
+	ok, err := s.isStateValidatorMigrationOver()
+	if err != nil {
+		return err
+	}
+	if ok {
+		// remove the validator entry keys for the corresponding state.
+		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
+		compressedValidatorHashes := idxBkt.Get(blockRoot)
+		err = idxBkt.Delete(blockRoot)
 		if err != nil {
 			return err
 		}
-		if ok {
-			// remove the validator entry keys for the corresponding state.
-			idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
-			compressedValidatorHashes := idxBkt.Get(blockRoot[:])
-			err = idxBkt.Delete(blockRoot[:])
-			if err != nil {
-				return err
-			}
 
-			// remove the respective validator entries from the cache.
-			if len(compressedValidatorHashes) == 0 {
-				return errors.Errorf("invalid compressed validator keys length")
-			}
-			validatorHashes, sErr := snappy.Decode(nil, compressedValidatorHashes)
-			if sErr != nil {
-				return errors.Wrap(sErr, "failed to uncompress validator keys")
-			}
-			if len(validatorHashes)%hashLength != 0 {
-				return errors.Errorf("invalid validator keys length: %d", len(validatorHashes))
-			}
-			for i := 0; i < len(validatorHashes); i += hashLength {
-				key := validatorHashes[i : i+hashLength]
-				s.validatorEntryCache.Del(key)
-				validatorEntryCacheDelete.Inc()
-			}
+		// remove the respective validator entries from the cache.
+		if len(compressedValidatorHashes) == 0 {
+			return errors.Errorf("invalid compressed validator keys length")
 		}
+		validatorHashes, sErr := snappy.Decode(nil, compressedValidatorHashes)
+		if sErr != nil {
+			return errors.Wrap(sErr, "failed to uncompress validator keys")
+		}
+		if len(validatorHashes)%hashLength != 0 {
+			return errors.Errorf("invalid validator keys length: %d", len(validatorHashes))
+		}
+		for i := 0; i < len(validatorHashes); i += hashLength {
+			key := validatorHashes[i : i+hashLength]
+			s.validatorEntryCache.Del(key)
+			validatorEntryCacheDelete.Inc()
+		}
+	}
 
-		return bkt.Delete(blockRoot[:])
-	})
+	return tx.Bucket(stateBucket).Delete(blockRoot)
 }
 
 // DeleteStates by block roots.
@@ -686,7 +692,7 @@ func (s *Store) validatorEntries(ctx context.Context, blockRoot [32]byte) ([]*zo
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.validatorEntries")
 	defer span.End()
 	var validatorEntries []*zondpb.Validator
-	err = s.db.View(func(tx *bolt.Tx) error {
+	err = s.db.View(func(tx engine.Tx) error {
 		// get the validator keys from the index bucket
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		valKey := idxBkt.Get(blockRoot[:])
@@ -746,7 +752,7 @@ func (s *Store) stateBytes(ctx context.Context, blockRoot [32]byte) ([]byte, err
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.stateBytes")
 	defer span.End()
 	var dst []byte
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(stateBucket)
 		stBytes := bkt.Get(blockRoot[:])
 		if len(stBytes) == 0 {
@@ -764,7 +770,7 @@ func (s *Store) stateBytes(ctx context.Context, blockRoot [32]byte) ([]byte, err
 }
 
 // slotByBlockRoot retrieves the corresponding slot of the input block root.
-func (s *Store) slotByBlockRoot(ctx context.Context, tx *bolt.Tx, blockRoot []byte) (primitives.Slot, error) {
+func (s *Store) slotByBlockRoot(ctx context.Context, tx engine.Tx, blockRoot []byte) (primitives.Slot, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.slotByBlockRoot")
 	defer span.End()
 
@@ -823,7 +829,7 @@ func (s *Store) HighestSlotStatesBelow(ctx context.Context, slot primitives.Slot
 	defer span.End()
 
 	var best []byte
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(stateSlotIndicesBucket)
 		c := bkt.Cursor()
 		for s, root := c.First(); s != nil; s, root = c.Next() {
@@ -908,7 +914,7 @@ func (s *Store) CleanUpDirtyStates(ctx context.Context, slotsPerArchivedPoint pr
 	}
 	deletedRoots := make([][32]byte, 0)
 
-	err = s.db.View(func(tx *bolt.Tx) error {
+	err = s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(stateSlotIndicesBucket)
 		return bkt.ForEach(func(k, v []byte) error {
 			if ctx.Err() != nil {
@@ -953,7 +959,7 @@ func (s *Store) isStateValidatorMigrationOver() (bool, error) {
 	// if the flag is not enabled, but the migration is over, then
 	// follow the new code path as if the flag is enabled.
 	returnFlag := false
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		mb := tx.Bucket(migrationsBucket)
 		b := mb.Get(migrationStateValidatorsKey)
 		returnFlag = bytes.Equal(b, migrationCompleted)
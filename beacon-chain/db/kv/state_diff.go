@@ -0,0 +1,78 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"go.opencensus.io/trace"
+)
+
+// SaveStateDiff saves the encoded hierarchical state diff of the state of the block root,
+// which is stored at the given slot.
+func (s *Store) SaveStateDiff(ctx context.Context, slot primitives.Slot, blockRoot [32]byte, enc []byte) error {
+	_, span := trace.StartSpan(ctx, "BeaconDB.SaveStateDiff")
+	defer span.End()
+
+	key := bytesutil.SlotToBytesBigEndian(slot)
+	return s.db.Update(func(tx engine.Tx) error {
+		if err := tx.Bucket(stateDiffBucket).Put(key, enc); err != nil {
+			return err
+		}
+		return tx.Bucket(stateDiffRootIndexBucket).Put(blockRoot[:], key)
+	})
+}
+
+// StateDiff returns the encoded hierarchical state diff stored at the given slot.
+func (s *Store) StateDiff(ctx context.Context, slot primitives.Slot) ([]byte, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.StateDiff")
+	defer span.End()
+
+	var enc []byte
+	err := s.db.View(func(tx engine.Tx) error {
+		v := tx.Bucket(stateDiffBucket).Get(bytesutil.SlotToBytesBigEndian(slot))
+		if v == nil {
+			return errors.Wrap(ErrNotFound, fmt.Sprintf("no state diff at slot %d", slot))
+		}
+		enc = bytesutil.SafeCopyBytes(v)
+		return nil
+	})
+	return enc, err
+}
+
+// StateDiffSlot returns the slot the hierarchical state diff of the state of the block root is stored at.
+func (s *Store) StateDiffSlot(ctx context.Context, blockRoot [32]byte) (primitives.Slot, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.StateDiffSlot")
+	defer span.End()
+
+	var slot primitives.Slot
+	err := s.db.View(func(tx engine.Tx) error {
+		v := tx.Bucket(stateDiffRootIndexBucket).Get(blockRoot[:])
+		if v == nil {
+			return errors.Wrap(ErrNotFound, fmt.Sprintf("no state diff for block root %#x", blockRoot))
+		}
+		slot = bytesutil.BytesToSlotBigEndian(v)
+		return nil
+	})
+	return slot, err
+}
+
+// LowestStateDiffSlot returns the lowest slot at or above the given slot that a hierarchical state diff is stored at.
+func (s *Store) LowestStateDiffSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.LowestStateDiffSlot")
+	defer span.End()
+
+	var lowest primitives.Slot
+	err := s.db.View(func(tx engine.Tx) error {
+		k, _ := tx.Bucket(stateDiffBucket).Cursor().Seek(bytesutil.SlotToBytesBigEndian(slot))
+		if k == nil {
+			return errors.Wrap(ErrNotFound, fmt.Sprintf("no state diff at or above slot %d", slot))
+		}
+		lowest = bytesutil.BytesToSlotBigEndian(k)
+		return nil
+	})
+	return lowest, err
+}
@@ -0,0 +1,42 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestStore_StateDiff(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	_, err := db.StateDiff(ctx, 64)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = db.StateDiffSlot(ctx, [32]byte{'a'})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = db.LowestStateDiffSlot(ctx, 0)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.SaveStateDiff(ctx, 64, [32]byte{'a'}, []byte("a")))
+	require.NoError(t, db.SaveStateDiff(ctx, 320, [32]byte{'b'}, []byte("b")))
+
+	enc, err := db.StateDiff(ctx, 320)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []byte("b"), enc)
+	slot, err := db.StateDiffSlot(ctx, [32]byte{'a'})
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(64), slot)
+
+	for _, tt := range []struct {
+		from, lowest primitives.Slot
+	}{{0, 64}, {64, 64}, {65, 320}, {320, 320}} {
+		slot, err = db.LowestStateDiffSlot(ctx, tt.from)
+		require.NoError(t, err)
+		assert.Equal(t, tt.lowest, slot, "from slot %d", tt.from)
+	}
+	_, err = db.LowestStateDiffSlot(ctx, 321)
+	require.ErrorIs(t, err, ErrNotFound)
+}
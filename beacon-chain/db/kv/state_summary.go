@@ -3,9 +3,9 @@ package kv
 import (
 	"context"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -46,7 +46,7 @@ func (s *Store) StateSummary(ctx context.Context, blockRoot [32]byte) (*zondpb.S
 		return s.stateSummaryCache.get(blockRoot), nil
 	}
 	var enc []byte
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		enc = tx.Bucket(stateSummaryBucket).Get(blockRoot[:])
 		return nil
 	}); err != nil {
@@ -72,7 +72,7 @@ func (s *Store) HasStateSummary(ctx context.Context, blockRoot [32]byte) bool {
 	}
 
 	var hasSummary bool
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		enc := tx.Bucket(stateSummaryBucket).Get(blockRoot[:])
 		hasSummary = len(enc) > 0
 		return nil
@@ -93,7 +93,7 @@ func (s *Store) saveCachedStateSummariesDB(ctx context.Context) error {
 		}
 		encs[i] = enc
 	}
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		for i, s := range summaries {
 			if err := bucket.Put(s.Root, encs[i]); err != nil {
@@ -111,7 +111,7 @@ func (s *Store) saveCachedStateSummariesDB(ctx context.Context) error {
 // deleteStateSummary deletes a state summary object from the db using input block root.
 func (s *Store) deleteStateSummary(blockRoot [32]byte) error {
 	s.stateSummaryCache.delete(blockRoot)
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		return bucket.Delete(blockRoot[:])
 	})
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/config/params"
@@ -18,7 +20,6 @@ import (
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
-	bolt "go.etcd.io/bbolt"
 )
 
 func TestStateNil(t *testing.T) {
@@ -80,7 +81,7 @@ func TestState_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.DeepSSZEqual(t, st.ToProtoUnsafe(), savedS.ToProtoUnsafe(), "saved state with validators and retrieved state are not matching")
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r[:])
 		require.NotEqual(t, 0, len(data))
@@ -89,7 +90,7 @@ func TestState_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -132,7 +133,7 @@ func TestStateAltair_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.DeepSSZEqual(t, st.ToProtoUnsafe(), savedS.ToProtoUnsafe(), "saved state with validators and retrieved state are not matching")
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r[:])
 		require.NotEqual(t, 0, len(data))
@@ -141,7 +142,7 @@ func TestStateAltair_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -196,7 +197,7 @@ func TestState_CanSaveRetrieveValidatorEntriesFromCache(t *testing.T) {
 	}
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -242,7 +243,7 @@ func TestState_CanSaveRetrieveValidatorEntriesWithoutCache(t *testing.T) {
 	require.DeepSSZEqual(t, st.ToProtoUnsafe(), savedS.ToProtoUnsafe(), "saved state with validators and retrieved state are not matching")
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r[:])
 		require.NotEqual(t, 0, len(data))
@@ -251,7 +252,7 @@ func TestState_CanSaveRetrieveValidatorEntriesWithoutCache(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -314,7 +315,7 @@ func TestState_DeleteState(t *testing.T) {
 	}
 
 	// check if the index of the first state is deleted.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r1[:])
 		require.Equal(t, 0, len(data))
@@ -323,7 +324,7 @@ func TestState_DeleteState(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r2[:])
 		require.NotEqual(t, 0, len(data))
@@ -332,7 +333,7 @@ func TestState_DeleteState(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -834,7 +835,7 @@ func TestStateBellatrix_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.DeepSSZEqual(t, st.ToProtoUnsafe(), savedS.ToProtoUnsafe(), "saved state with validators and retrieved state are not matching")
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r[:])
 		require.NotEqual(t, 0, len(data))
@@ -843,7 +844,7 @@ func TestStateBellatrix_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -970,7 +971,7 @@ func TestStateDeneb_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.DeepSSZEqual(t, st.Validators(), savedS.Validators(), "saved state with validators and retrieved state are not matching")
 
 	// check if the index of the second state is still present.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		idxBkt := tx.Bucket(blockRootValidatorHashesBucket)
 		data := idxBkt.Get(r[:])
 		require.NotEqual(t, 0, len(data))
@@ -979,7 +980,7 @@ func TestStateDeneb_CanSaveRetrieveValidatorEntries(t *testing.T) {
 	require.NoError(t, err)
 
 	// check if all the validator entries are still intact in the validator entry bucket.
-	err = db.db.Update(func(tx *bolt.Tx) error {
+	err = db.db.Update(func(tx engine.Tx) error {
 		valBkt := tx.Bucket(stateValidatorsBucket)
 		// if any of the original validator entry is not present, then fail the test.
 		for _, val := range stateValidators {
@@ -999,3 +1000,47 @@ func BenchmarkState_CheckStateSaveTime_10(b *testing.B) { checkStateSaveTime(b,
 
 func BenchmarkState_CheckStateReadTime_1(b *testing.B)  { checkStateReadTime(b, 1) }
 func BenchmarkState_CheckStateReadTime_10(b *testing.B) { checkStateReadTime(b, 10) }
+
+// benchmarkState returns a state with validators for the state benchmarks.
+func benchmarkState(b *testing.B) state.BeaconState {
+	vals := validators(10000)
+	for _, v := range vals {
+		v.PublicKey = bytesutil.PadTo(v.PublicKey, dilithium.CryptoPublicKeyBytes)
+	}
+	st, err := util.NewBeaconState()
+	require.NoError(b, err)
+	require.NoError(b, st.SetValidators(vals))
+	return st
+}
+
+func BenchmarkStore_SaveState(b *testing.B) {
+	for _, backend := range testBackends {
+		b.Run(string(backend), func(b *testing.B) {
+			db := setupDBWithBackend(b, backend)
+			st := benchmarkState(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, db.SaveState(context.Background(), st, bytesutil.ToBytes32(bytesutil.Bytes8(uint64(i)))))
+			}
+		})
+	}
+}
+
+func BenchmarkStore_State(b *testing.B) {
+	for _, backend := range testBackends {
+		b.Run(string(backend), func(b *testing.B) {
+			ctx := context.Background()
+			db := setupDBWithBackend(b, backend)
+			st := benchmarkState(b)
+			r := [32]byte{'A'}
+			require.NoError(b, db.SaveState(ctx, st, r))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := db.State(ctx, r)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
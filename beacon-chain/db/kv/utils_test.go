@@ -5,10 +5,10 @@ import (
 	"crypto/rand"
 	"testing"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
-	bolt "go.etcd.io/bbolt"
 )
 
 func Test_deleteValueForIndices(t *testing.T) {
@@ -115,7 +115,7 @@ func Test_deleteValueForIndices(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := db.db.Update(func(tx *bolt.Tx) error {
+			err := db.db.Update(func(tx engine.Tx) error {
 				for k, idx := range tt.inputIndices {
 					bkt := tx.Bucket([]byte(k))
 					require.NoError(t, bkt.Put(idx, tt.inputIndices[k]))
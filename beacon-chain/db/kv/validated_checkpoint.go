@@ -3,8 +3,8 @@ package kv
 import (
 	"context"
 
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -13,7 +13,7 @@ func (s *Store) LastValidatedCheckpoint(ctx context.Context) (*zondpb.Checkpoint
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.LastValidatedCheckpoint")
 	defer span.End()
 	var checkpoint *zondpb.Checkpoint
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx engine.Tx) error {
 		bkt := tx.Bucket(checkpointBucket)
 		enc := bkt.Get(lastValidatedCheckpointKey)
 		if enc == nil {
@@ -0,0 +1,21 @@
+package pruner
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	historyPrunedSlot = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "history_pruned_slot",
+			Help: "Slot below which the blocks and states were deleted by history pruning.",
+		},
+	)
+	historyPrunedBlocks = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "history_pruned_blocks_total",
+			Help: "Count of blocks deleted by history pruning.",
+		},
+	)
+)
@@ -0,0 +1,187 @@
+// Package pruner deletes the finalized blocks and states which are older than the history retention period,
+// for nodes which do not need to serve the full chain history.
+package pruner
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+var _ runtime.Service = (*Service)(nil)
+
+// Database describes the set of DB methods that the pruner Service needs to function.
+type Database interface {
+	FinalizedCheckpoint(ctx context.Context) (*zondpb.Checkpoint, error)
+	PruneHistory(ctx context.Context, beforeSlot primitives.Slot) (int, error)
+}
+
+// ServiceOption represents a functional option for the pruner Service.
+type ServiceOption func(*Service) error
+
+// WithEnablePruning toggles history pruning, which is disabled by default.
+func WithEnablePruning(enabled bool) ServiceOption {
+	return func(s *Service) error {
+		s.enabled = enabled
+		return nil
+	}
+}
+
+// WithRetentionEpochs sets the number of epochs before the finalized checkpoint for which the history is kept.
+// The retention period cannot be shorter than MIN_EPOCHS_FOR_BLOCK_REQUESTS, during which peers may request blocks.
+func WithRetentionEpochs(epochs primitives.Epoch) ServiceOption {
+	return func(s *Service) error {
+		if min := params.BeaconNetworkConfig().MinEpochsForBlockRequests; epochs < min {
+			return errors.Errorf("history retention of %d epochs is shorter than MIN_EPOCHS_FOR_BLOCK_REQUESTS=%d", epochs, min)
+		}
+		s.retentionEpochs = epochs
+		return nil
+	}
+}
+
+// WithArchiveInterval rounds the pruned slot down to a multiple of the interval between the stored cold states,
+// so that the states above the pruned slot can still be rebuilt.
+func WithArchiveInterval(interval primitives.Slot) ServiceOption {
+	return func(s *Service) error {
+		s.archiveInterval = interval
+		return nil
+	}
+}
+
+// WithBackfillStatus makes the service record the pruned slot in the backfill Status, which reports the blocks
+// the node can serve.
+func WithBackfillStatus(bfs *backfill.Status) ServiceOption {
+	return func(s *Service) error {
+		s.bfs = bfs
+		return nil
+	}
+}
+
+// Service deletes the blocks, state summaries and states which are more than the retention period older than the
+// finalized checkpoint, once per epoch. The genesis block and state are never pruned.
+type Service struct {
+	ctx             context.Context
+	cancel          context.CancelFunc
+	db              Database
+	cw              startup.ClockWaiter
+	bfs             *backfill.Status
+	enabled         bool
+	retentionEpochs primitives.Epoch
+	archiveInterval primitives.Slot
+}
+
+// NewService initializes the pruner Service.
+func NewService(ctx context.Context, db Database, cw startup.ClockWaiter, opts ...ServiceOption) (*Service, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:             ctx,
+		cancel:          cancel,
+		db:              db,
+		cw:              cw,
+		retentionEpochs: params.BeaconNetworkConfig().MinEpochsForBlockRequests,
+	}
+	for _, o := range opts {
+		if err := o(s); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Start prunes the history at the start of every epoch.
+func (s *Service) Start() {
+	if !s.enabled {
+		return
+	}
+	clock, err := s.cw.WaitForClock(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("History pruning failed to start while waiting for genesis data")
+		return
+	}
+	log.WithField("retentionEpochs", s.retentionEpochs).Info("Pruning history older than the retention period")
+	ticker := slots.NewSlotTicker(clock.GenesisTime(), params.BeaconConfig().SecondsPerSlot)
+	defer ticker.Done()
+	for {
+		select {
+		case slot := <-ticker.C():
+			if !slots.IsEpochStart(slot) {
+				continue
+			}
+			if err := s.prune(s.ctx); err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				log.WithError(err).Error("Could not prune history")
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop the pruner service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the pruner service.
+func (s *Service) Status() error {
+	return nil
+}
+
+// prune deletes the history below the pruned slot of the current finalized checkpoint.
+func (s *Service) prune(ctx context.Context) error {
+	cp, err := s.db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	slot, ok := s.prunedSlot(cp.Epoch)
+	if !ok {
+		return nil
+	}
+	// The blocks are reported as missing before they are deleted, so that no partial range is served meanwhile.
+	if s.bfs != nil {
+		s.bfs.MarkPruned(slot)
+	}
+	start := time.Now()
+	n, err := s.db.PruneHistory(ctx, slot)
+	if err != nil {
+		return err
+	}
+	historyPrunedSlot.Set(float64(slot))
+	if n > 0 {
+		historyPrunedBlocks.Add(float64(n))
+		log.WithFields(logrus.Fields{
+			"prunedSlot": slot,
+			"blocks":     n,
+			"duration":   time.Since(start),
+		}).Debug("Pruned history")
+	}
+	return nil
+}
+
+// prunedSlot returns the slot below which the history is deleted, given the finalized epoch.
+func (s *Service) prunedSlot(finalized primitives.Epoch) (primitives.Slot, bool) {
+	if finalized <= s.retentionEpochs {
+		return 0, false
+	}
+	slot, err := slots.EpochStart(finalized - s.retentionEpochs)
+	if err != nil {
+		return 0, false
+	}
+	if s.archiveInterval > 0 {
+		slot -= slot % s.archiveInterval
+	}
+	return slot, slot > 0
+}
@@ -0,0 +1,83 @@
+package pruner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+type mockDB struct {
+	finalized *zondpb.Checkpoint
+	pruned    []primitives.Slot
+}
+
+func (db *mockDB) FinalizedCheckpoint(context.Context) (*zondpb.Checkpoint, error) {
+	return db.finalized, nil
+}
+
+func (db *mockDB) PruneHistory(_ context.Context, beforeSlot primitives.Slot) (int, error) {
+	db.pruned = append(db.pruned, beforeSlot)
+	return 1, nil
+}
+
+func TestNewService_RetentionEpochs(t *testing.T) {
+	min := params.BeaconNetworkConfig().MinEpochsForBlockRequests
+	s, err := NewService(context.Background(), &mockDB{}, startup.NewClockSynchronizer())
+	require.NoError(t, err)
+	assert.Equal(t, min, s.retentionEpochs)
+
+	_, err = NewService(context.Background(), &mockDB{}, startup.NewClockSynchronizer(), WithRetentionEpochs(min-1))
+	require.ErrorContains(t, "shorter than MIN_EPOCHS_FOR_BLOCK_REQUESTS", err)
+}
+
+func TestService_PrunedSlot(t *testing.T) {
+	min := params.BeaconNetworkConfig().MinEpochsForBlockRequests
+	spe := params.BeaconConfig().SlotsPerEpoch
+	tests := []struct {
+		name      string
+		finalized primitives.Epoch
+		interval  primitives.Slot
+		slot      primitives.Slot
+		ok        bool
+	}{
+		{name: "within retention", finalized: min},
+		{name: "past retention", finalized: min + 3, slot: 3 * spe, ok: true},
+		{name: "aligned to archive interval", finalized: min + 3, interval: 2 * spe, slot: 2 * spe, ok: true},
+		{name: "below archive interval", finalized: min + 1, interval: 2 * spe},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewService(context.Background(), &mockDB{}, startup.NewClockSynchronizer(), WithRetentionEpochs(min), WithArchiveInterval(tt.interval))
+			require.NoError(t, err)
+			slot, ok := s.prunedSlot(tt.finalized)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.slot, slot)
+		})
+	}
+}
+
+func TestService_Prune(t *testing.T) {
+	ctx := context.Background()
+	min := params.BeaconNetworkConfig().MinEpochsForBlockRequests
+	db := &mockDB{finalized: &zondpb.Checkpoint{Epoch: 1}}
+	bfs := backfill.NewStatus(nil)
+	s, err := NewService(ctx, db, startup.NewClockSynchronizer(), WithRetentionEpochs(min+1), WithBackfillStatus(bfs))
+	require.NoError(t, err)
+
+	require.NoError(t, s.prune(ctx))
+	assert.Equal(t, 0, len(db.pruned))
+
+	db.finalized.Epoch = min + 3
+	require.NoError(t, s.prune(ctx))
+	want := 2 * params.BeaconConfig().SlotsPerEpoch
+	assert.DeepEqual(t, []primitives.Slot{want}, db.pruned)
+	assert.Equal(t, want, bfs.PrunedSlot())
+	assert.Equal(t, false, bfs.SlotCovered(want-1))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/cmd"
 	"github.com/theQRL/qrysm/v4/io/file"
 	"github.com/theQRL/qrysm/v4/io/prompt"
@@ -22,7 +23,17 @@ func Restore(cliCtx *cli.Context) error {
 	targetDir := cliCtx.String(cmd.RestoreTargetDirFlag.Name)
 
 	restoreDir := path.Join(targetDir, kv.BeaconNodeDbDirName)
-	if file.FileExists(path.Join(restoreDir, kv.DatabaseFileName)) {
+	// Backups of pebble databases are directories.
+	backend := engine.Bolt
+	isDir, err := file.HasDir(sourceFile)
+	if err != nil {
+		return err
+	}
+	if isDir {
+		backend = engine.Pebble
+	}
+	restorePath := kv.KVStoreDatapath(restoreDir, backend)
+	if _, err := os.Stat(restorePath); err == nil {
 		resp, err := prompt.ValidatePrompt(
 			os.Stdin, dbExistsYesNoPrompt, prompt.ValidateYesOrNo,
 		)
@@ -37,7 +48,14 @@ func Restore(cliCtx *cli.Context) error {
 	if err := file.MkdirAll(restoreDir); err != nil {
 		return err
 	}
-	if err := file.CopyFile(sourceFile, path.Join(restoreDir, kv.DatabaseFileName)); err != nil {
+	if backend == engine.Pebble {
+		if err := os.RemoveAll(restorePath); err != nil {
+			return err
+		}
+		if err := file.CopyDir(sourceFile, restorePath); err != nil {
+			return err
+		}
+	} else if err := file.CopyFile(sourceFile, restorePath); err != nil {
 		return err
 	}
 
@@ -9,6 +9,7 @@ import (
 
 	logTest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
 	"github.com/theQRL/qrysm/v4/cmd"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
@@ -69,3 +70,47 @@ func TestRestore(t *testing.T) {
 	assert.LogsContain(t, logHook, "Restore completed successfully")
 
 }
+
+func TestRestore_Pebble(t *testing.T) {
+	ctx := context.Background()
+
+	backupDb, err := kv.NewKVStore(ctx, t.TempDir(), kv.WithBackend(engine.Pebble))
+	require.NoError(t, err)
+	head := util.NewBeaconBlock()
+	head.Block.Slot = 5000
+	wsb, err := blocks.NewSignedBeaconBlock(head)
+	require.NoError(t, err)
+	require.NoError(t, backupDb.SaveBlock(ctx, wsb))
+	root, err := head.Block.HashTreeRoot()
+	require.NoError(t, err)
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	require.NoError(t, backupDb.SaveState(ctx, st, root))
+	require.NoError(t, backupDb.SaveHeadBlockRoot(ctx, root))
+	backupsDir := path.Join(t.TempDir(), "backups")
+	require.NoError(t, backupDb.Backup(ctx, backupsDir, false))
+	require.NoError(t, backupDb.Close())
+	backups, err := os.ReadDir(backupsDir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(backups))
+
+	restoreDir := t.TempDir()
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(cmd.RestoreSourceFileFlag.Name, "", "")
+	set.String(cmd.RestoreTargetDirFlag.Name, "", "")
+	require.NoError(t, set.Set(cmd.RestoreSourceFileFlag.Name, path.Join(backupsDir, backups[0].Name())))
+	require.NoError(t, set.Set(cmd.RestoreTargetDirFlag.Name, restoreDir))
+	cliCtx := cli.NewContext(&app, set, nil)
+
+	assert.NoError(t, Restore(cliCtx))
+
+	restoredDb, err := kv.NewKVStore(ctx, path.Join(restoreDir, kv.BeaconNodeDbDirName), kv.WithBackend(engine.Pebble))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, restoredDb.Close())
+	}()
+	headBlock, err := restoredDb.HeadBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(5000), headBlock.Block().Slot(), "Restored database has incorrect data")
+}
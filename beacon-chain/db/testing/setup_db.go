@@ -4,9 +4,11 @@ package testing
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/filesystem"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/iface"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/slasherkv"
@@ -26,6 +28,15 @@ func SetupDB(t testing.TB) db.Database {
 	return s
 }
 
+// SetupBlobStorage instantiates and returns a blob storage in a temporary directory.
+func SetupBlobStorage(t testing.TB) db.BlobStorage {
+	bs, err := filesystem.NewBlobStorage(filepath.Join(t.TempDir(), filesystem.BlobsDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
 // SetupSlasherDB --
 func SetupSlasherDB(t testing.TB) iface.SlasherDatabase {
 	s, err := slasherkv.NewKVStore(context.Background(), t.TempDir())
@@ -5,10 +5,12 @@ import (
 
 	fastssz "github.com/prysmaticlabs/fastssz"
 	"github.com/theQRL/go-zond/common"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/hdiff"
 	"github.com/theQRL/qrysm/v4/cmd"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	tracing2 "github.com/theQRL/qrysm/v4/monitoring/tracing"
 	"github.com/urfave/cli/v2"
 )
@@ -88,6 +90,26 @@ func configureSlotsPerArchivedPoint(cliCtx *cli.Context) error {
 	return nil
 }
 
+// stateDiffHierarchy returns the hierarchy of the state diffs that archived states are saved as,
+// or nil if archived states are saved in full.
+func stateDiffHierarchy(cliCtx *cli.Context) (*hdiff.Hierarchy, error) {
+	if !cliCtx.IsSet(flags.StateDiffIntervals.Name) {
+		return nil, nil
+	}
+	values := cliCtx.IntSlice(flags.StateDiffIntervals.Name)
+	intervals := make([]primitives.Slot, len(values))
+	for i, v := range values {
+		if v <= 0 {
+			return nil, fmt.Errorf("state diff interval %d is not positive", v)
+		}
+		intervals[i] = primitives.Slot(v)
+		if intervals[i]%params.BeaconConfig().SlotsPerEpoch != 0 {
+			return nil, fmt.Errorf("state diff interval %d is not a multiple of %d slots per epoch", v, params.BeaconConfig().SlotsPerEpoch)
+		}
+	}
+	return hdiff.NewHierarchy(intervals)
+}
+
 func configureEth1Config(cliCtx *cli.Context) error {
 	c := params.BeaconConfig().Copy()
 	if cliCtx.IsSet(flags.ChainID.Name) {
@@ -198,6 +220,12 @@ func configureExecutionSetting(cliCtx *cli.Context) error {
 	return params.SetActive(c)
 }
 
+func configureSignatureVerification(cliCtx *cli.Context) {
+	if cliCtx.IsSet(flags.SignatureVerificationConcurrency.Name) {
+		dilithium.SetVerificationConcurrency(cliCtx.Int(flags.SignatureVerificationConcurrency.Name))
+	}
+}
+
 func configureFastSSZHashingAlgorithm() {
 	fastssz.EnableVectorizedHTR = true
 }
@@ -53,6 +53,38 @@ func TestConfigureSlotsPerArchivedPoint(t *testing.T) {
 	assert.Equal(t, primitives.Slot(100), params.BeaconConfig().SlotsPerArchivedPoint)
 }
 
+func TestStateDiffHierarchy(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	cliCtx := cli.NewContext(&app, set, nil)
+	h, err := stateDiffHierarchy(cliCtx)
+	require.NoError(t, err)
+	assert.Equal(t, true, h == nil, "Enabled state diffs without the flag")
+
+	for _, tt := range []struct {
+		value string
+		err   string
+	}{
+		{value: "8192,1024,128"},
+		{value: "8192,1000", err: "not a multiple of"},
+		{value: "8192,-128", err: "not positive"},
+		{value: "1024,8192", err: "invalid state diff hierarchy"},
+	} {
+		set := flag.NewFlagSet("test", 0)
+		set.Var(cli.NewIntSlice(), flags.StateDiffIntervals.Name, "")
+		require.NoError(t, set.Set(flags.StateDiffIntervals.Name, tt.value))
+		cliCtx := cli.NewContext(&app, set, nil)
+		h, err := stateDiffHierarchy(cliCtx)
+		if tt.err != "" {
+			require.ErrorContains(t, tt.err, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.DeepEqual(t, []primitives.Slot{8192, 1024, 128}, h.Intervals())
+	}
+}
+
 func TestConfigureProofOfWork(t *testing.T) {
 	params.SetupTestConfigCleanup(t)
 
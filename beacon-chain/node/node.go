@@ -26,7 +26,10 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/cache/depositcache"
 	"github.com/theQRL/qrysm/v4/beacon-chain/cache/depositsnapshot"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/filesystem"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/pruner"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db/slasherkv"
 	interopcoldstart "github.com/theQRL/qrysm/v4/beacon-chain/deterministic-genesis"
 	"github.com/theQRL/qrysm/v4/beacon-chain/execution"
@@ -76,6 +79,8 @@ type serviceFlagOpts struct {
 	blockchainFlagOpts     []blockchain.Option
 	executionChainFlagOpts []execution.Option
 	builderOpts            []builder.Option
+	backfillOpts           []backfill.ServiceOption
+	prunerOpts             []pruner.ServiceOption
 }
 
 // BeaconNode defines a struct that handles the services running a random beacon chain
@@ -89,6 +94,8 @@ type BeaconNode struct {
 	lock                    sync.RWMutex
 	stop                    chan struct{} // Channel to wait for termination notifications.
 	db                      db.Database
+	dbBackend               engine.Backend
+	blobStorage             *filesystem.BlobStorage
 	slasherDB               db.SlasherDatabase
 	attestationPool         attestations.Pool
 	exitPool                voluntaryexits.PoolManager
@@ -97,6 +104,7 @@ type BeaconNode struct {
 	dilithiumToExecPool     blstoexec.PoolManager
 	depositCache            cache.DepositCache
 	proposerIdsCache        *cache.ProposerPayloadIDsCache
+	verifiedSigCache        *cache.VerifiedSignatureCache
 	stateFeed               *event.Feed
 	blockFeed               *event.Feed
 	opFeed                  *event.Feed
@@ -156,6 +164,7 @@ func New(cliCtx *cli.Context, opts ...Option) (*BeaconNode, error) {
 	if err := kv.ConfigureBlobRetentionEpoch(cliCtx); err != nil {
 		return nil, err
 	}
+	configureSignatureVerification(cliCtx)
 	configureFastSSZHashingAlgorithm()
 
 	// Initializes any forks here.
@@ -182,6 +191,7 @@ func New(cliCtx *cli.Context, opts ...Option) (*BeaconNode, error) {
 		slasherAttestationsFeed: new(event.Feed),
 		serviceFlagOpts:         &serviceFlagOpts{},
 		proposerIdsCache:        cache.NewProposerPayloadIDsCache(),
+		verifiedSigCache:        cache.NewVerifiedSignatureCache(),
 	}
 
 	beacon.initialSyncComplete = make(chan struct{})
@@ -255,7 +265,17 @@ func New(cliCtx *cli.Context, opts ...Option) (*BeaconNode, error) {
 	}
 
 	log.Debugln("Registering Sync Service")
-	if err := beacon.registerSyncService(beacon.initialSyncComplete); err != nil {
+	if err := beacon.registerSyncService(beacon.initialSyncComplete, bfs); err != nil {
+		return nil, err
+	}
+
+	log.Debugln("Registering Backfill Service")
+	if err := beacon.registerBackfillService(bfs, beacon.initialSyncComplete); err != nil {
+		return nil, err
+	}
+
+	log.Debugln("Registering Pruner Service")
+	if err := beacon.registerPrunerService(bfs); err != nil {
 		return nil, err
 	}
 
@@ -296,7 +316,7 @@ func New(cliCtx *cli.Context, opts ...Option) (*BeaconNode, error) {
 	// db.DatabasePath is the path to the containing directory
 	// db.NewDBFilename expands that to the canonical full path using
 	// the same construction as NewDB()
-	c, err := newBeaconNodePromCollector(db.NewDBFilename(beacon.db.DatabasePath()))
+	c, err := newBeaconNodePromCollector(db.NewDBFilename(beacon.db.DatabasePath(), beacon.dbBackend))
 	if err != nil {
 		return nil, err
 	}
@@ -375,9 +395,19 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context, depositAddress string) error {
 	clearDB := cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearDB := cliCtx.Bool(cmd.ForceClearDB.Name)
 
+	backend, err := engine.ParseBackend(cliCtx.String(flags.DBBackendFlag.Name))
+	if err != nil {
+		return err
+	}
+	b.dbBackend = backend
+	blobPath := cliCtx.String(flags.BlobStoragePathFlag.Name)
+	if blobPath == "" {
+		blobPath = filepath.Join(baseDir, filesystem.BlobsDirName)
+	}
+
 	log.WithField("database-path", dbPath).Info("Checking DB")
 
-	d, err := db.NewDB(b.ctx, dbPath)
+	d, err := db.NewDB(b.ctx, dbPath, kv.WithBackend(backend))
 	if err != nil {
 		return err
 	}
@@ -399,7 +429,10 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context, depositAddress string) error {
 		if err := d.ClearDB(); err != nil {
 			return errors.Wrap(err, "could not clear database")
 		}
-		d, err = db.NewDB(b.ctx, dbPath)
+		if err := os.RemoveAll(blobPath); err != nil {
+			return errors.Wrap(err, "could not clear blob storage")
+		}
+		d, err = db.NewDB(b.ctx, dbPath, kv.WithBackend(backend))
 		if err != nil {
 			return errors.Wrap(err, "could not create new database")
 		}
@@ -409,7 +442,26 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context, depositAddress string) error {
 		return err
 	}
 
+	var blobOpts []filesystem.BlobStorageOption
+	if cliCtx.IsSet(flags.BlobRetentionEpoch.Name) {
+		blobOpts = append(blobOpts, filesystem.WithBlobRetentionEpochs(primitives.Epoch(cliCtx.Uint64(flags.BlobRetentionEpoch.Name))))
+	}
+	bs, err := filesystem.NewBlobStorage(blobPath, blobOpts...)
+	if err != nil {
+		return errors.Wrap(err, "could not open blob storage")
+	}
+	if err := d.MigrateBlobSidecars(b.ctx, bs); err != nil {
+		return errors.Wrap(err, "could not migrate blob sidecars to the blob storage")
+	}
+
 	b.db = d
+	b.blobStorage = bs
+	go func() {
+		// Blobs are pruned against the current epoch, which is only known once the genesis clock is.
+		if err := bs.WaitForClock(b.ctx, b.clockWaiter); err != nil && b.ctx.Err() == nil {
+			log.WithError(err).Error("Could not prune blob storage")
+		}
+	}()
 
 	var depositCache cache.DepositCache
 	if features.Get().EnableEIP4881 {
@@ -514,6 +566,14 @@ func (b *BeaconNode) startSlasherDB(cliCtx *cli.Context) error {
 
 func (b *BeaconNode) startStateGen(ctx context.Context, bfs *backfill.Status, fc forkchoice.ForkChoicer) error {
 	opts := []stategen.StateGenOption{stategen.WithBackfillStatus(bfs)}
+	h, err := stateDiffHierarchy(b.cliCtx)
+	if err != nil {
+		return errors.Wrap(err, "could not configure state diffs")
+	}
+	if h != nil {
+		log.WithField("intervals", h).Info("Saving archived states as hierarchical state diffs")
+		opts = append(opts, stategen.WithStateDiffHierarchy(h))
+	}
 	sg := stategen.New(b.db, fc, opts...)
 
 	cp, err := b.db.FinalizedCheckpoint(ctx)
@@ -555,6 +615,7 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 		NoDiscovery:       cliCtx.Bool(cmd.NoDiscovery.Name),
 		StaticPeers:       slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.StaticPeers.Name)),
 		BootstrapNodeAddr: bootstrapNodeAddrs,
+		DNSDiscoveryURLs:  cliCtx.StringSlice(cmd.DNSDiscoveryURL.Name),
 		RelayNodeAddr:     cliCtx.String(cmd.RelayNode.Name),
 		DataDir:           dataDir,
 		LocalIP:           cliCtx.String(cmd.P2PIP.Name),
@@ -564,6 +625,7 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 		StaticPeerID:      cliCtx.Bool(cmd.P2PStaticID.Name),
 		MetaDataDir:       cliCtx.String(cmd.P2PMetadata.Name),
 		TCPPort:           cliCtx.Uint(cmd.P2PTCPPort.Name),
+		QUICPort:          cliCtx.Uint(cmd.P2PQUICPort.Name),
 		UDPPort:           cliCtx.Uint(cmd.P2PUDPPort.Name),
 		MaxPeers:          cliCtx.Uint(cmd.P2PMaxPeers.Name),
 		AllowListCIDR:     cliCtx.String(cmd.P2PAllowList.Name),
@@ -622,6 +684,7 @@ func (b *BeaconNode) registerBlockchainService(fc forkchoice.ForkChoicer, gs *st
 		b.serviceFlagOpts.blockchainFlagOpts,
 		blockchain.WithForkChoiceStore(fc),
 		blockchain.WithDatabase(b.db),
+		blockchain.WithBlobStorage(b.blobStorage),
 		blockchain.WithDepositCache(b.depositCache),
 		blockchain.WithChainStartFetcher(web3Service),
 		blockchain.WithExecutionEngineCaller(web3Service),
@@ -636,6 +699,7 @@ func (b *BeaconNode) registerBlockchainService(fc forkchoice.ForkChoicer, gs *st
 		blockchain.WithSlasherAttestationsFeed(b.slasherAttestationsFeed),
 		blockchain.WithFinalizedStateAtStartUp(b.finalizedStateAtStartUp),
 		blockchain.WithProposerIdsCache(b.proposerIdsCache),
+		blockchain.WithVerifiedSignatureCache(b.verifiedSigCache),
 		blockchain.WithClockSynchronizer(gs),
 		blockchain.WithSyncComplete(syncComplete),
 	)
@@ -679,7 +743,7 @@ func (b *BeaconNode) registerPOWChainService() error {
 	return b.services.RegisterService(web3Service)
 }
 
-func (b *BeaconNode) registerSyncService(initialSyncComplete chan struct{}) error {
+func (b *BeaconNode) registerSyncService(initialSyncComplete chan struct{}, bfs *backfill.Status) error {
 	var web3Service *execution.Service
 	if err := b.services.FetchService(&web3Service); err != nil {
 		return err
@@ -698,6 +762,7 @@ func (b *BeaconNode) registerSyncService(initialSyncComplete chan struct{}) erro
 	rs := regularsync.NewService(
 		b.ctx,
 		regularsync.WithDatabase(b.db),
+		regularsync.WithBlobStorage(b.blobStorage),
 		regularsync.WithP2P(b.fetchP2P()),
 		regularsync.WithChainService(chainService),
 		regularsync.WithInitialSync(initSync),
@@ -715,6 +780,8 @@ func (b *BeaconNode) registerSyncService(initialSyncComplete chan struct{}) erro
 		regularsync.WithExecutionPayloadReconstructor(web3Service),
 		regularsync.WithClockWaiter(b.clockWaiter),
 		regularsync.WithInitialSyncComplete(initialSyncComplete),
+		regularsync.WithBackfillStatus(bfs),
+		regularsync.WithVerifiedSignatureCache(b.verifiedSigCache),
 	)
 	return b.services.RegisterService(rs)
 }
@@ -727,6 +794,7 @@ func (b *BeaconNode) registerInitialSyncService(complete chan struct{}) error {
 
 	is := initialsync.NewService(b.ctx, &initialsync.Config{
 		DB:                  b.db,
+		BlobStorage:         b.blobStorage,
 		Chain:               chainService,
 		P2P:                 b.fetchP2P(),
 		StateNotifier:       b,
@@ -737,6 +805,44 @@ func (b *BeaconNode) registerInitialSyncService(complete chan struct{}) error {
 	return b.services.RegisterService(is)
 }
 
+func (b *BeaconNode) registerBackfillService(bfs *backfill.Status, initialSyncComplete chan struct{}) error {
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+
+	fetcher := initialsync.NewBackfillFetcher(b.ctx, &initialsync.Config{
+		DB:          b.db,
+		Chain:       chainService,
+		P2P:         b.fetchP2P(),
+		ClockWaiter: b.clockWaiter,
+	})
+	// Copy the flag options, so that appending to them does not write into their backing array.
+	opts := make([]backfill.ServiceOption, 0, len(b.serviceFlagOpts.backfillOpts)+1)
+	opts = append(opts, b.serviceFlagOpts.backfillOpts...)
+	opts = append(opts, backfill.WithInitialSyncComplete(initialSyncComplete))
+	bf, err := backfill.NewService(b.ctx, bfs, b.db, fetcher, b.clockWaiter, opts...)
+	if err != nil {
+		return errors.Wrap(err, "could not create backfill service")
+	}
+	return b.services.RegisterService(bf)
+}
+
+func (b *BeaconNode) registerPrunerService(bfs *backfill.Status) error {
+	// Copy the flag options, so that appending to them does not write into their backing array.
+	opts := make([]pruner.ServiceOption, 0, len(b.serviceFlagOpts.prunerOpts)+2)
+	opts = append(opts, b.serviceFlagOpts.prunerOpts...)
+	opts = append(opts,
+		pruner.WithArchiveInterval(b.stateGen.ArchiveInterval()),
+		pruner.WithBackfillStatus(bfs),
+	)
+	p, err := pruner.NewService(b.ctx, b.db, b.clockWaiter, opts...)
+	if err != nil {
+		return errors.Wrap(err, "could not create pruner service")
+	}
+	return b.services.RegisterService(p)
+}
+
 func (b *BeaconNode) registerSlasherService() error {
 	if !features.Get().EnableSlasher {
 		return nil
@@ -828,6 +934,7 @@ func (b *BeaconNode) registerRPCService(router *mux.Router) error {
 		CertFlag:                      cert,
 		KeyFlag:                       key,
 		BeaconDB:                      b.db,
+		BlobStorage:                   b.blobStorage,
 		Broadcaster:                   p2pService,
 		PeersFetcher:                  p2pService,
 		PeerManager:                   p2pService,
@@ -842,6 +949,7 @@ func (b *BeaconNode) registerRPCService(router *mux.Router) error {
 		AttestationReceiver:           chainService,
 		GenesisTimeFetcher:            chainService,
 		GenesisFetcher:                chainService,
+		LightClientUpdateFetcher:      chainService,
 		OptimisticModeFetcher:         chainService,
 		AttestationsPool:              b.attestationPool,
 		ExitPool:                      b.exitPool,
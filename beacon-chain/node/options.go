@@ -3,7 +3,9 @@ package node
 import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/blockchain"
 	"github.com/theQRL/qrysm/v4/beacon-chain/builder"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/pruner"
 	"github.com/theQRL/qrysm/v4/beacon-chain/execution"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
 )
 
 // Option for beacon node configuration.
@@ -32,3 +34,19 @@ func WithBuilderFlagOptions(opts []builder.Option) Option {
 		return nil
 	}
 }
+
+// WithBackfillOptions includes functional options for the backfill service related to CLI flags.
+func WithBackfillOptions(opts []backfill.ServiceOption) Option {
+	return func(bn *BeaconNode) error {
+		bn.serviceFlagOpts.backfillOpts = opts
+		return nil
+	}
+}
+
+// WithPrunerOptions includes functional options for the history pruner service related to CLI flags.
+func WithPrunerOptions(opts []pruner.ServiceOption) Option {
+	return func(bn *BeaconNode) error {
+		bn.serviceFlagOpts.prunerOpts = opts
+		return nil
+	}
+}
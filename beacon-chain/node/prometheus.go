@@ -2,7 +2,8 @@ package node
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
+	"path/filepath"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -49,11 +50,26 @@ func (bc *bcnodeCollector) Collect(ch chan<- prometheus.Metric) {
 }
 
 func (bc *bcnodeCollector) getCurrentDbBytes() (float64, error) {
-	fs, err := os.Stat(bc.dbPath)
+	var size int64
+	// The database is a single file for bolt, and a directory of files for pebble.
+	err := filepath.WalkDir(bc.dbPath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
 	if err != nil {
 		return 0, fmt.Errorf("could not collect database file size for prometheus, path=%s, err=%s", bc.dbPath, err)
 	}
-	return float64(fs.Size()), nil
+	return float64(size), nil
 }
 
 func (bc *bcnodeCollector) unregister() {
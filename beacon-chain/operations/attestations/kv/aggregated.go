@@ -11,7 +11,7 @@ import (
 	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	attaggregation "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation/attestations"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	"go.opencensus.io/trace"
 )
 
@@ -50,13 +50,10 @@ func (c *AttCaches) aggregateUnaggregatedAtts(ctx context.Context, unaggregatedA
 		leftOverUnaggregatedAtt = c.aggregateParallel(attsByDataRoot, leftOverUnaggregatedAtt)
 	} else {
 		for _, atts := range attsByDataRoot {
-			aggregated, err := attaggregation.AggregateDisjointOneBitAtts(atts)
+			aggregated, err := attestation.MergeSigners(atts...)
 			if err != nil {
 				return errors.Wrap(err, "could not aggregate unaggregated attestations")
 			}
-			if aggregated == nil {
-				return errors.New("could not aggregate unaggregated attestations")
-			}
 			if helpers.IsAggregated(aggregated) {
 				if err := c.SaveAggregatedAttestations([]*zondpb.Attestation{aggregated}); err != nil {
 					return err
@@ -101,15 +98,11 @@ func (c *AttCaches) aggregateParallel(atts map[[32]byte][]*zondpb.Attestation, l
 		go func() {
 			defer wg.Done()
 			for as := range ch {
-				aggregated, err := attaggregation.AggregateDisjointOneBitAtts(as)
+				aggregated, err := attestation.MergeSigners(as...)
 				if err != nil {
 					log.WithError(err).Error("could not aggregate unaggregated attestations")
 					continue
 				}
-				if aggregated == nil {
-					log.Error("nil aggregated attestation")
-					continue
-				}
 				if helpers.IsAggregated(aggregated) {
 					if err := c.SaveAggregatedAttestations([]*zondpb.Attestation{aggregated}); err != nil {
 						log.WithError(err).Error("could not save aggregated attestation")
@@ -139,7 +132,8 @@ func (c *AttCaches) aggregateParallel(atts map[[32]byte][]*zondpb.Attestation, l
 	return leftOver
 }
 
-// SaveAggregatedAttestation saves an aggregated attestation in cache.
+// SaveAggregatedAttestation saves an aggregated attestation in cache. The cache keeps a single
+// attestation per attestation data, its signers are merged with the signers of the attestation.
 func (c *AttCaches) SaveAggregatedAttestation(att *zondpb.Attestation) error {
 	if err := helpers.ValidateNilAttestation(att); err != nil {
 		return err
@@ -167,21 +161,18 @@ func (c *AttCaches) SaveAggregatedAttestation(att *zondpb.Attestation) error {
 	if err != nil {
 		return errors.Wrap(err, "could not tree hash attestation")
 	}
-	copiedAtt := zondpb.CopyAttestation(att)
 	c.aggregatedAttLock.Lock()
 	defer c.aggregatedAttLock.Unlock()
-	atts, ok := c.aggregatedAtt[r]
-	if !ok {
-		atts := []*zondpb.Attestation{copiedAtt}
-		c.aggregatedAtt[r] = atts
-		return nil
+	var merged *zondpb.Attestation
+	if existing, ok := c.aggregatedAtt[r]; ok {
+		merged, err = attestation.MergeSigners(existing, att)
+	} else {
+		merged, err = attestation.MergeSigners(att)
 	}
-
-	atts, err = attaggregation.Aggregate(append(atts, copiedAtt))
 	if err != nil {
-		return err
+		return errors.Wrap(err, "could not merge attestation signers")
 	}
-	c.aggregatedAtt[r] = atts
+	c.aggregatedAtt[r] = merged
 
 	return nil
 }
@@ -207,7 +198,7 @@ func (c *AttCaches) AggregatedAttestations() []*zondpb.Attestation {
 	atts := make([]*zondpb.Attestation, 0)
 
 	for _, a := range c.aggregatedAtt {
-		atts = append(atts, a...)
+		atts = append(atts, a)
 	}
 
 	return atts
@@ -224,15 +215,17 @@ func (c *AttCaches) AggregatedAttestationsBySlotIndex(ctx context.Context, slot
 	c.aggregatedAttLock.RLock()
 	defer c.aggregatedAttLock.RUnlock()
 	for _, a := range c.aggregatedAtt {
-		if slot == a[0].Data.Slot && committeeIndex == a[0].Data.CommitteeIndex {
-			atts = append(atts, a...)
+		if slot == a.Data.Slot && committeeIndex == a.Data.CommitteeIndex {
+			atts = append(atts, a)
 		}
 	}
 
 	return atts
 }
 
-// DeleteAggregatedAttestation deletes the aggregated attestations in cache.
+// DeleteAggregatedAttestation deletes the signers of the attestation from the cached attestation
+// with the same data. The signers are matched by aggregation bits, so the attestation does not
+// need to carry their signatures.
 func (c *AttCaches) DeleteAggregatedAttestation(att *zondpb.Attestation) error {
 	if err := helpers.ValidateNilAttestation(att); err != nil {
 		return err
@@ -251,23 +244,19 @@ func (c *AttCaches) DeleteAggregatedAttestation(att *zondpb.Attestation) error {
 
 	c.aggregatedAttLock.Lock()
 	defer c.aggregatedAttLock.Unlock()
-	attList, ok := c.aggregatedAtt[r]
+	existing, ok := c.aggregatedAtt[r]
 	if !ok {
 		return nil
 	}
 
-	filtered := make([]*zondpb.Attestation, 0)
-	for _, a := range attList {
-		if c, err := att.AggregationBits.Contains(a.AggregationBits); err != nil {
-			return err
-		} else if !c {
-			filtered = append(filtered, a)
-		}
+	remaining, err := attestation.ExtractSigners(existing, att.AggregationBits.Not())
+	if err != nil {
+		return err
 	}
-	if len(filtered) == 0 {
+	if remaining.AggregationBits.Count() == 0 {
 		delete(c.aggregatedAtt, r)
 	} else {
-		c.aggregatedAtt[r] = filtered
+		c.aggregatedAtt[r] = remaining
 	}
 
 	return nil
@@ -285,13 +274,11 @@ func (c *AttCaches) HasAggregatedAttestation(att *zondpb.Attestation) (bool, err
 
 	c.aggregatedAttLock.RLock()
 	defer c.aggregatedAttLock.RUnlock()
-	if atts, ok := c.aggregatedAtt[r]; ok {
-		for _, a := range atts {
-			if c, err := a.AggregationBits.Contains(att.AggregationBits); err != nil {
-				return false, err
-			} else if c {
-				return true, nil
-			}
+	if a, ok := c.aggregatedAtt[r]; ok {
+		if c, err := a.AggregationBits.Contains(att.AggregationBits); err != nil {
+			return false, err
+		} else if c {
+			return true, nil
 		}
 	}
 
@@ -9,39 +9,62 @@ import (
 	"github.com/pkg/errors"
 	fssz "github.com/prysmaticlabs/fastssz"
 	"github.com/theQRL/go-bitfield"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/config/features"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
 )
 
+// signedAtt hydrates the attestation and signs it in canonical form, with the validator at a
+// committee position having the position as its index.
+func signedAtt(att *zondpb.Attestation) *zondpb.Attestation {
+	att = util.HydrateAttestation(att)
+	att.Signature = []byte{}
+	att.SignatureValidatorIndex = []uint64{}
+	for _, pos := range att.AggregationBits.BitIndices() {
+		att.Signature = append(att.Signature, bytesutil.PadTo(bytesutil.ToBytes(uint64(pos)+1, 8), dilithium2.CryptoBytes)...)
+		att.SignatureValidatorIndex = append(att.SignatureValidatorIndex, uint64(pos))
+	}
+	return att
+}
+
 func TestKV_Aggregated_AggregateUnaggregatedAttestations(t *testing.T) {
-	resetFn := features.InitWithReset(&features.Flags{
-		AggregateParallel: true,
-	})
-	defer resetFn()
+	for _, parallel := range []bool{false, true} {
+		resetFn := features.InitWithReset(&features.Flags{
+			AggregateParallel: parallel,
+		})
 
-	cache := NewAttCaches()
-	priv, err := bls.RandKey()
-	require.NoError(t, err)
-	sig1 := priv.Sign([]byte{'a'})
-	sig2 := priv.Sign([]byte{'b'})
-	att1 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig1.Marshal()})
-	att2 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1010}, Signature: sig1.Marshal()})
-	att3 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1100}, Signature: sig1.Marshal()})
-	att4 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig2.Marshal()})
-	att5 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig1.Marshal()})
-	att6 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1010}, Signature: sig1.Marshal()})
-	att7 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1100}, Signature: sig1.Marshal()})
-	att8 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig2.Marshal()})
-	atts := []*zondpb.Attestation{att1, att2, att3, att4, att5, att6, att7, att8}
-	require.NoError(t, cache.SaveUnaggregatedAttestations(atts))
-	require.NoError(t, cache.AggregateUnaggregatedAttestations(context.Background()))
-
-	require.Equal(t, 1, len(cache.AggregatedAttestationsBySlotIndex(context.Background(), 1, 0)), "Did not aggregate correctly")
-	require.Equal(t, 1, len(cache.AggregatedAttestationsBySlotIndex(context.Background(), 2, 0)), "Did not aggregate correctly")
+		cache := NewAttCaches()
+		att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1001}})
+		att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1010}})
+		att3 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1100}})
+		att4 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1001}})
+		att5 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1001}})
+		att6 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1010}})
+		att7 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1100}})
+		att8 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1001}})
+		// The only attestation of its data is left unaggregated.
+		att9 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b1001}})
+		atts := []*zondpb.Attestation{att1, att2, att3, att4, att5, att6, att7, att8, att9}
+		require.NoError(t, cache.SaveUnaggregatedAttestations(atts))
+		require.NoError(t, cache.AggregateUnaggregatedAttestations(context.Background()))
+
+		for _, slot := range []primitives.Slot{1, 2} {
+			aggregated := cache.AggregatedAttestationsBySlotIndex(context.Background(), slot, 0)
+			require.Equal(t, 1, len(aggregated), "Did not aggregate correctly")
+			assert.DeepEqual(t, signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: slot}, AggregationBits: bitfield.Bitlist{0b1111}}), aggregated[0])
+		}
+		assert.Equal(t, 0, len(cache.AggregatedAttestationsBySlotIndex(context.Background(), 3, 0)))
+		unaggregated, err := cache.UnaggregatedAttestations()
+		require.NoError(t, err)
+		assert.DeepEqual(t, []*zondpb.Attestation{att9}, unaggregated)
+		resetFn()
+	}
 }
 
 func TestKV_Aggregated_SaveAggregatedAttestation(t *testing.T) {
@@ -88,13 +111,23 @@ func TestKV_Aggregated_SaveAggregatedAttestation(t *testing.T) {
 			count: 0,
 		},
 		{
-			name: "normal save",
+			name: "signers not in canonical form",
 			att: util.HydrateAttestation(&zondpb.Attestation{
 				Data: &zondpb.AttestationData{
 					Slot: 1,
 				},
 				AggregationBits: bitfield.Bitlist{0b1101},
 			}),
+			wantErrString: "attestation signers are not in canonical form",
+		},
+		{
+			name: "normal save",
+			att: signedAtt(&zondpb.Attestation{
+				Data: &zondpb.AttestationData{
+					Slot: 1,
+				},
+				AggregationBits: bitfield.Bitlist{0b1101},
+			}),
 			count: 1,
 		},
 	}
@@ -131,9 +164,9 @@ func TestKV_Aggregated_SaveAggregatedAttestations(t *testing.T) {
 		{
 			name: "no duplicates",
 			atts: []*zondpb.Attestation{
-				util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
+				signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
 					AggregationBits: bitfield.Bitlist{0b1101}}),
-				util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
+				signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
 					AggregationBits: bitfield.Bitlist{0b1101}}),
 			},
 			count: 1,
@@ -166,9 +199,9 @@ func TestKV_Aggregated_SaveAggregatedAttestations_SomeGoodSomeBad(t *testing.T)
 		{
 			name: "the first attestation is bad",
 			atts: []*zondpb.Attestation{
-				util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
+				signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
 					AggregationBits: bitfield.Bitlist{0b1100}}),
-				util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
+				signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1},
 					AggregationBits: bitfield.Bitlist{0b1101}}),
 			},
 			count: 1,
@@ -194,9 +227,9 @@ func TestKV_Aggregated_SaveAggregatedAttestations_SomeGoodSomeBad(t *testing.T)
 func TestKV_Aggregated_AggregatedAttestations(t *testing.T) {
 	cache := NewAttCaches()
 
-	att1 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1101}})
-	att2 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1101}})
-	att3 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b1101}})
+	att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1101}})
+	att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b1101}})
+	att3 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b1101}})
 	atts := []*zondpb.Attestation{att1, att2, att3}
 
 	for _, att := range atts {
@@ -246,28 +279,28 @@ func TestKV_Aggregated_DeleteAggregatedAttestation(t *testing.T) {
 		assert.NoError(t, cache.DeleteAggregatedAttestation(att))
 	})
 
-	t.Run("non-filtered deletion", func(t *testing.T) {
+	t.Run("deletion of all signers", func(t *testing.T) {
 		cache := NewAttCaches()
-		att1 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11010}})
-		att2 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b11010}})
-		att3 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b11010}})
-		att4 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b10101}})
-		atts := []*zondpb.Attestation{att1, att2, att3, att4}
+		att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11010}})
+		att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b11010}})
+		att3 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b11010}})
+		atts := []*zondpb.Attestation{att1, att2, att3}
 		require.NoError(t, cache.SaveAggregatedAttestations(atts))
 		require.NoError(t, cache.DeleteAggregatedAttestation(att1))
-		require.NoError(t, cache.DeleteAggregatedAttestation(att3))
+		// The signatures of the deleted attestation are not needed.
+		require.NoError(t, cache.DeleteAggregatedAttestation(util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b11110}})))
 
 		returned := cache.AggregatedAttestations()
 		wanted := []*zondpb.Attestation{att2}
 		assert.DeepEqual(t, wanted, returned)
 	})
 
-	t.Run("filtered deletion", func(t *testing.T) {
+	t.Run("deletion of some signers", func(t *testing.T) {
 		cache := NewAttCaches()
-		att1 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b110101}})
-		att2 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110111}})
-		att3 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110100}})
-		att4 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110101}})
+		att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b110101}})
+		att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110111}})
+		att3 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110100}})
+		att4 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b110101}})
 		atts := []*zondpb.Attestation{att1, att2, att3, att4}
 		require.NoError(t, cache.SaveAggregatedAttestations(atts))
 
@@ -275,11 +308,15 @@ func TestKV_Aggregated_DeleteAggregatedAttestation(t *testing.T) {
 		require.NoError(t, cache.DeleteAggregatedAttestation(att4))
 
 		returned := cache.AggregatedAttestations()
-		wanted := []*zondpb.Attestation{att1, att2}
 		sort.Slice(returned, func(i, j int) bool {
-			return string(returned[i].AggregationBits) < string(returned[j].AggregationBits)
+			return returned[i].Data.Slot < returned[j].Data.Slot
 		})
+		wanted := []*zondpb.Attestation{att1, signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b100010}})}
 		assert.DeepEqual(t, wanted, returned)
+
+		// Deleted signers are not saved again.
+		require.NoError(t, cache.SaveAggregatedAttestation(att3))
+		assert.DeepEqual(t, wanted[1], cache.AggregatedAttestationsBySlotIndex(context.Background(), 2, 0)[0])
 	})
 }
 
@@ -404,7 +441,7 @@ func TestKV_Aggregated_HasAggregatedAttestation(t *testing.T) {
 					Data: util.HydrateAttestationData(&zondpb.AttestationData{
 						Slot: 1,
 					}),
-					AggregationBits: bitfield.Bitlist{0b1100111},
+					AggregationBits: bitfield.Bitlist{0b1100011},
 				},
 			},
 			input: &zondpb.Attestation{
@@ -460,6 +497,9 @@ func TestKV_Aggregated_HasAggregatedAttestation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			for i, att := range tt.existing {
+				tt.existing[i] = signedAtt(att)
+			}
 			cache := NewAttCaches()
 			require.NoError(t, cache.SaveAggregatedAttestations(tt.existing))
 
@@ -492,8 +532,8 @@ func TestKV_Aggregated_HasAggregatedAttestation(t *testing.T) {
 func TestKV_Aggregated_DuplicateAggregatedAttestations(t *testing.T) {
 	cache := NewAttCaches()
 
-	att1 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1101}})
-	att2 := util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1111}})
+	att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1101}})
+	att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b1111}})
 	atts := []*zondpb.Attestation{att1, att2}
 
 	for _, att := range atts {
@@ -506,3 +546,27 @@ func TestKV_Aggregated_DuplicateAggregatedAttestations(t *testing.T) {
 	assert.DeepSSZEqual(t, att2, returned[0], "Did not receive correct aggregated atts")
 	assert.Equal(t, 1, len(returned), "Did not receive correct aggregated atts")
 }
+
+func TestKV_Aggregated_MergesSigners(t *testing.T) {
+	cache := NewAttCaches()
+
+	att1 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b100011}})
+	att2 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b101100}})
+	att3 := signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b110010}})
+	require.NoError(t, cache.SaveAggregatedAttestations([]*zondpb.Attestation{att1, att2}))
+
+	// The pool holds a single attestation with all the signers.
+	returned := cache.AggregatedAttestations()
+	require.Equal(t, 1, len(returned))
+	assert.DeepEqual(t, signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b101111}}), returned[0])
+
+	// An attestation disagreeing on the validator at a committee position is rejected.
+	conflict := zondpb.CopyAttestation(att3)
+	conflict.SignatureValidatorIndex[0] = 100
+	require.ErrorIs(t, cache.SaveAggregatedAttestation(conflict), attestation.ErrSignerMismatch)
+
+	require.NoError(t, cache.SaveAggregatedAttestation(att3))
+	returned = cache.AggregatedAttestations()
+	require.Equal(t, 1, len(returned))
+	assert.DeepEqual(t, signedAtt(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b111111}}), returned[0])
+}
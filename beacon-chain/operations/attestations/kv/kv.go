@@ -20,7 +20,7 @@ var hashFn = hash.HashProto
 // such are unaggregated, aggregated or attestations within a block.
 type AttCaches struct {
 	aggregatedAttLock  sync.RWMutex
-	aggregatedAtt      map[[32]byte][]*zondpb.Attestation
+	aggregatedAtt      map[[32]byte]*zondpb.Attestation // the attestation with all known signers, by data root
 	unAggregateAttLock sync.RWMutex
 	unAggregatedAtt    map[[32]byte]*zondpb.Attestation
 	forkchoiceAttLock  sync.RWMutex
@@ -37,7 +37,7 @@ func NewAttCaches() *AttCaches {
 	c := cache.New(secsInEpoch*time.Second, 2*secsInEpoch*time.Second)
 	pool := &AttCaches{
 		unAggregatedAtt: make(map[[32]byte]*zondpb.Attestation),
-		aggregatedAtt:   make(map[[32]byte][]*zondpb.Attestation),
+		aggregatedAtt:   make(map[[32]byte]*zondpb.Attestation),
 		forkchoiceAtt:   make(map[[32]byte]*zondpb.Attestation),
 		blockAtt:        make(map[[32]byte][]*zondpb.Attestation),
 		seenAtt:         c,
@@ -7,44 +7,44 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
 )
 
-func validAttesterSlashingForValIdx(t *testing.T, beaconState state.BeaconState, privs []bls.SecretKey, valIdx ...uint64) *zondpb.AttesterSlashing {
+func validAttesterSlashingForValIdx(t *testing.T, beaconState state.BeaconState, privs []dilithium.DilithiumKey, valIdx ...uint64) *zondpb.AttesterSlashing {
 	var slashings []*zondpb.AttesterSlashing
 	for _, idx := range valIdx {
 		slashing, err := util.GenerateAttesterSlashingForValidator(beaconState, privs[idx], primitives.ValidatorIndex(idx))
 		require.NoError(t, err)
 		slashings = append(slashings, slashing)
 	}
-	var allSig1 []bls.Signature
-	var allSig2 []bls.Signature
+	var allSig1 []dilithium.Signature
+	var allSig2 []dilithium.Signature
 	for _, slashing := range slashings {
 		sig1 := slashing.Attestation_1.Signature
 		sig2 := slashing.Attestation_2.Signature
-		sigFromBytes1, err := bls.SignatureFromBytes(sig1)
+		sigFromBytes1, err := dilithium.SignatureFromBytes(sig1)
 		require.NoError(t, err)
-		sigFromBytes2, err := bls.SignatureFromBytes(sig2)
+		sigFromBytes2, err := dilithium.SignatureFromBytes(sig2)
 		require.NoError(t, err)
 		allSig1 = append(allSig1, sigFromBytes1)
 		allSig2 = append(allSig2, sigFromBytes2)
 	}
-	aggSig1 := bls.AggregateSignatures(allSig1)
-	aggSig2 := bls.AggregateSignatures(allSig2)
+	aggSig1 := dilithium.UnaggregatedSignatures(allSig1)
+	aggSig2 := dilithium.UnaggregatedSignatures(allSig2)
 	aggSlashing := &zondpb.AttesterSlashing{
 		Attestation_1: &zondpb.IndexedAttestation{
 			AttestingIndices: valIdx,
 			Data:             slashings[0].Attestation_1.Data,
-			Signature:        aggSig1.Marshal(),
+			Signature:        aggSig1,
 		},
 		Attestation_2: &zondpb.IndexedAttestation{
 			AttestingIndices: valIdx,
 			Data:             slashings[0].Attestation_2.Data,
-			Signature:        aggSig2.Marshal(),
+			Signature:        aggSig2,
 		},
 	}
 	return aggSlashing
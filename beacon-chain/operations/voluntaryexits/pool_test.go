@@ -8,8 +8,7 @@ import (
 	state_native "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
 	"github.com/theQRL/qrysm/v4/config/params"
 	types "github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
-	"github.com/theQRL/qrysm/v4/crypto/bls/common"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
@@ -55,7 +54,7 @@ func TestExitsForInclusion(t *testing.T) {
 	numValidators := 2 * params.BeaconConfig().MaxVoluntaryExits
 	validators := make([]*zondpb.Validator, numValidators)
 	exits := make([]*zondpb.VoluntaryExit, numValidators)
-	privKeys := make([]common.SecretKey, numValidators)
+	privKeys := make([]dilithium.DilithiumKey, numValidators)
 
 	for i := range validators {
 		v := &zondpb.Validator{}
@@ -65,7 +64,7 @@ func TestExitsForInclusion(t *testing.T) {
 		} else {
 			v.ExitEpoch = params.BeaconConfig().FarFutureEpoch
 		}
-		priv, err := bls.RandKey()
+		priv, err := dilithium.RandKey()
 		require.NoError(t, err)
 		privKeys[i] = priv
 		pubkey := priv.PublicKey().Marshal()
@@ -15,6 +15,7 @@ type Config struct {
 	StaticPeers         []string
 	BootstrapNodeAddr   []string
 	Discv5BootStrapAddr []string
+	DNSDiscoveryURLs    []string
 	RelayNodeAddr       string
 	LocalIP             string
 	HostAddress         string
@@ -23,6 +24,7 @@ type Config struct {
 	DataDir             string
 	MetaDataDir         string
 	TCPPort             uint
+	QUICPort            uint
 	UDPPort             uint
 	MaxPeers            uint
 	AllowListCIDR       string
@@ -15,12 +15,20 @@ import (
 	"github.com/theQRL/go-zond/p2p/enode"
 	"github.com/theQRL/go-zond/p2p/enr"
 	"github.com/theQRL/qrysm/v4/beacon-chain/cache"
+	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/config/params"
 	ecdsaprysm "github.com/theQRL/qrysm/v4/crypto/ecdsa"
 	"github.com/theQRL/qrysm/v4/runtime/version"
 	"github.com/theQRL/qrysm/v4/time/slots"
 )
 
+// dnsMixTimeout is how long discovery waits for a node from the source whose turn it is, either discv5 or the dns
+// node trees, before taking a node from whichever source has one.
+const dnsMixTimeout = 100 * time.Millisecond
+
+// quicENRKey is the ENR key of the udp port of a node's libp2p quic transport.
+const quicENRKey = "quic"
+
 // Listener defines the discovery V5 network interface that is used
 // to communicate with other peers.
 type Listener interface {
@@ -91,6 +99,9 @@ func (s *Service) RefreshENR() {
 // listen for new nodes watches for new nodes in the network and adds them to the peerstore.
 func (s *Service) listenForNewNodes() {
 	iterator := s.dv5Listener.RandomNodes()
+	if s.dnsIterator != nil {
+		iterator = mixNodes(dnsMixTimeout, iterator, s.dnsIterator)
+	}
 	iterator = enode.Filter(iterator, s.filterPeer)
 	defer iterator.Close()
 	for {
@@ -167,6 +178,7 @@ func (s *Service) createListener(
 		ipAddr,
 		int(s.cfg.UDPPort),
 		int(s.cfg.TCPPort),
+		int(s.cfg.QUICPort),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create local node")
@@ -215,7 +227,7 @@ func (s *Service) createListener(
 func (s *Service) createLocalNode(
 	privKey *ecdsa.PrivateKey,
 	ipAddr net.IP,
-	udpPort, tcpPort int,
+	udpPort, tcpPort, quicPort int,
 ) (*enode.LocalNode, error) {
 	db, err := enode.OpenDB("")
 	if err != nil {
@@ -229,6 +241,9 @@ func (s *Service) createLocalNode(
 	localNode.Set(ipEntry)
 	localNode.Set(udpEntry)
 	localNode.Set(tcpEntry)
+	if features.Get().EnableQUIC {
+		localNode.Set(enr.WithEntry(quicENRKey, uint16(quicPort)))
+	}
 	localNode.SetFallbackIP(ipAddr)
 	localNode.SetFallbackUDP(udpPort)
 
@@ -403,6 +418,9 @@ func convertToMultiAddr(nodes []*enode.Node) []ma.Multiaddr {
 	return multiAddrs
 }
 
+// convertToAddrInfo returns the dialable addresses of a node along with its tcp multiaddr. When quic is
+// enabled and the node advertises a quic port, its quic address is listed ahead of the tcp one, so
+// that libp2p dials quic first and falls back to tcp.
 func convertToAddrInfo(node *enode.Node) (*peer.AddrInfo, ma.Multiaddr, error) {
 	multiAddr, err := convertToSingleMultiAddr(node)
 	if err != nil {
@@ -412,9 +430,30 @@ func convertToAddrInfo(node *enode.Node) (*peer.AddrInfo, ma.Multiaddr, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	if features.Get().EnableQUIC {
+		quicAddr, err := convertToQUICMultiAddr(node)
+		if err != nil {
+			return nil, nil, err
+		}
+		if quicAddr != nil {
+			info.Addrs = append([]ma.Multiaddr{quicAddr}, info.Addrs...)
+		}
+	}
 	return info, multiAddr, nil
 }
 
+// convertToQUICMultiAddr returns the quic multiaddr of a node, or nil if its ENR has no quic entry.
+func convertToQUICMultiAddr(node *enode.Node) (ma.Multiaddr, error) {
+	var quicPort uint16
+	if err := node.Record().Load(enr.WithEntry(quicENRKey, &quicPort)); err != nil {
+		if enr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "could not retrieve quic port")
+	}
+	return QUICMultiAddressBuilder(node.IP().String(), uint(quicPort))
+}
+
 func convertToSingleMultiAddr(node *enode.Node) (ma.Multiaddr, error) {
 	pubkey := node.Pubkey()
 	assertedKey, err := ecdsaprysm.ConvertToInterfacePubkey(pubkey)
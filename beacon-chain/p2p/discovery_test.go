@@ -28,6 +28,7 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers/scorers"
 	testp2p "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/wrapper"
 	leakybucket "github.com/theQRL/qrysm/v4/container/leaky-bucket"
@@ -140,7 +141,7 @@ func TestMultiAddrsConversion_InvalidIPAddr(t *testing.T) {
 		genesisTime:           time.Now(),
 		genesisValidatorsRoot: bytesutil.PadTo([]byte{'A'}, 32),
 	}
-	node, err := s.createLocalNode(pkey, addr, 0, 0)
+	node, err := s.createLocalNode(pkey, addr, 0, 0, 0)
 	require.NoError(t, err)
 	multiAddr := convertToMultiAddr([]*enode.Node{node.Node()})
 	assert.Equal(t, 0, len(multiAddr), "Invalid ip address converted successfully")
@@ -167,6 +168,52 @@ func TestMultiAddrConversion_OK(t *testing.T) {
 	require.LogsDoNotContain(t, hook, "Could not get multiaddr")
 }
 
+func TestConvertToAddrInfo_QUIC(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{EnableQUIC: true})
+	defer resetCfg()
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	s := &Service{
+		genesisTime:           time.Now(),
+		genesisValidatorsRoot: bytesutil.PadTo([]byte{'A'}, 32),
+	}
+	node, err := s.createLocalNode(pkey, ipAddr, 2000, 3000, 4000)
+	require.NoError(t, err)
+
+	info, multiAddr, err := convertToAddrInfo(node.Node())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(info.Addrs))
+	assert.Equal(t, fmt.Sprintf("/ip4/%s/udp/4000/quic-v1", ipAddr), info.Addrs[0].String(), "Expected quic to be dialed first")
+	assert.Equal(t, fmt.Sprintf("/ip4/%s/tcp/3000", ipAddr), info.Addrs[1].String())
+	assert.Equal(t, fmt.Sprintf("/ip4/%s/tcp/3000/p2p/%s", ipAddr, info.ID), multiAddr.String())
+
+	// Without quic, peers are only dialed over tcp, even if they advertise quic.
+	features.Init(&features.Flags{})
+	info, _, err = convertToAddrInfo(node.Node())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(info.Addrs))
+	assert.Equal(t, fmt.Sprintf("/ip4/%s/tcp/3000", ipAddr), info.Addrs[0].String())
+}
+
+func TestCreateLocalNode_QUICEntry(t *testing.T) {
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	s := &Service{
+		genesisTime:           time.Now(),
+		genesisValidatorsRoot: bytesutil.PadTo([]byte{'A'}, 32),
+	}
+	node, err := s.createLocalNode(pkey, ipAddr, 2000, 3000, 4000)
+	require.NoError(t, err)
+	var quicPort uint16
+	err = node.Node().Record().Load(enr.WithEntry(quicENRKey, &quicPort))
+	assert.Equal(t, true, enr.IsNotFound(err), "Expected no quic entry when quic is disabled")
+
+	resetCfg := features.InitWithReset(&features.Flags{EnableQUIC: true})
+	defer resetCfg()
+	node, err = s.createLocalNode(pkey, ipAddr, 2000, 3000, 4000)
+	require.NoError(t, err)
+	require.NoError(t, node.Node().Record().Load(enr.WithEntry(quicENRKey, &quicPort)))
+	assert.Equal(t, uint16(4000), quicPort)
+}
+
 func TestStaticPeering_PeersAreAdded(t *testing.T) {
 	cs := startup.NewClockSynchronizer()
 	cfg := &Config{
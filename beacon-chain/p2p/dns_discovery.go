@@ -0,0 +1,20 @@
+package p2p
+
+import (
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/p2p/dnsdisc"
+	"github.com/theQRL/go-zond/p2p/enode"
+)
+
+// newDNSIterator returns an iterator over the nodes of the given EIP-1459 node trees (enrtree:// URLs). The root of
+// every tree must be signed by the key in its URL, and the trees are resolved again periodically, so that published
+// node lists can be rotated without a restart. The system resolver is used when resolver is nil.
+func newDNSIterator(urls []string, resolver dnsdisc.Resolver) (enode.Iterator, error) {
+	for _, u := range urls {
+		if _, _, err := dnsdisc.ParseURL(u); err != nil {
+			return nil, errors.Wrapf(err, "invalid dns discovery url %s", u)
+		}
+	}
+	c := dnsdisc.NewClient(dnsdisc.Config{Resolver: resolver})
+	return c.NewIterator(urls...)
+}
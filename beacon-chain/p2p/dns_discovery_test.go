@@ -0,0 +1,112 @@
+package p2p
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/theQRL/go-zond/crypto"
+	"github.com/theQRL/go-zond/p2p/dnsdisc"
+	"github.com/theQRL/go-zond/p2p/enode"
+	"github.com/theQRL/go-zond/p2p/enr"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+// mapResolver serves TXT records from memory, standing in for DNS.
+type mapResolver map[string]string
+
+func (m mapResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := m[name]; ok {
+		return []string{txt}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func testDNSNodes(t *testing.T, n int) []*enode.Node {
+	nodes := make([]*enode.Node, n)
+	for i := range nodes {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		var r enr.Record
+		r.Set(enr.IP(net.IPv4(127, 0, 0, byte(i+1))))
+		r.Set(enr.TCP(13000))
+		r.Set(enr.UDP(12000))
+		require.NoError(t, enode.SignV4(&r, key))
+		nodes[i], err = enode.New(enode.ValidSchemes, &r)
+		require.NoError(t, err)
+	}
+	return nodes
+}
+
+func TestNewDNSIterator(t *testing.T) {
+	nodes := testDNSNodes(t, 5)
+	tree, err := dnsdisc.MakeTree(1, nodes, nil)
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	domain := "nodes.example.org"
+	url, err := tree.Sign(key, domain)
+	require.NoError(t, err)
+	resolver := mapResolver(tree.ToTXT(domain))
+
+	it, err := newDNSIterator([]string{url}, resolver)
+	require.NoError(t, err)
+	defer it.Close()
+	seen := make(map[enode.ID]bool)
+	for i := 0; i < 100 && len(seen) < len(nodes); i++ {
+		require.Equal(t, true, it.Next())
+		seen[it.Node().ID()] = true
+	}
+	for _, n := range nodes {
+		assert.Equal(t, true, seen[n.ID()], "node %s was not discovered", n.ID())
+	}
+}
+
+func TestNewDNSIterator_WrongSigner(t *testing.T) {
+	tree, err := dnsdisc.MakeTree(1, testDNSNodes(t, 2), nil)
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	domain := "nodes.example.org"
+	_, err = tree.Sign(key, domain)
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	empty, err := dnsdisc.MakeTree(1, nil, nil)
+	require.NoError(t, err)
+	otherURL, err := empty.Sign(other, domain)
+	require.NoError(t, err)
+
+	// The tree is served for a url carrying another key, so its root signature must be rejected.
+	it, err := newDNSIterator([]string{otherURL}, mapResolver(tree.ToTXT(domain)))
+	require.NoError(t, err)
+	next := make(chan bool)
+	go func() {
+		next <- it.Next()
+	}()
+	select {
+	case <-next:
+		t.Fatal("iterator returned a node of a tree with an invalid signature")
+	case <-time.After(time.Second):
+	}
+	it.Close()
+	assert.Equal(t, false, <-next)
+}
+
+func TestNewDNSIterator_InvalidURL(t *testing.T) {
+	_, err := newDNSIterator([]string{"enrtree://nodes.example.org"}, mapResolver{})
+	require.ErrorContains(t, "invalid dns discovery url", err)
+}
+
+func TestMixNodes(t *testing.T) {
+	nodes := testDNSNodes(t, 4)
+	it := mixNodes(dnsMixTimeout, enode.IterNodes(nodes[:2]), enode.IterNodes(nodes[2:]))
+	defer it.Close()
+	got := enode.ReadNodes(it, len(nodes))
+	require.Equal(t, len(nodes), len(got))
+
+	single := enode.IterNodes(nodes)
+	assert.Equal(t, single, mixNodes(dnsMixTimeout, single))
+}
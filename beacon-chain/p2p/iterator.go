@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"time"
 
 	"github.com/theQRL/go-zond/p2p/enode"
 )
@@ -34,3 +35,17 @@ func (f *filterIter) Next() bool {
 	}
 	return false
 }
+
+// mixNodes combines the nodes of several discovery sources into a single iterator, which takes nodes from each
+// source in turn. A source which has no node ready within the timeout is skipped for that turn, so that a slow
+// source does not hold back the others.
+func mixNodes(timeout time.Duration, sources ...enode.Iterator) enode.Iterator {
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	mix := enode.NewFairMix(timeout)
+	for _, it := range sources {
+		mix.AddSource(it)
+	}
+	return mix
+}
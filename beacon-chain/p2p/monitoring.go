@@ -5,6 +5,7 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -24,6 +25,11 @@ var (
 		Help: "The number of peers in a given state.",
 	},
 		[]string{"state"})
+	connectionsByTransport = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "p2p_connections_by_transport",
+		Help: "The number of open libp2p connections by transport.",
+	},
+		[]string{"transport"})
 	connectedPeersCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "connected_libp2p_peers",
 		Help: "Tracks the total number of connected libp2p peers by agent string",
@@ -156,6 +162,14 @@ func (s *Service) updateMetrics() {
 	p2pPeerCount.WithLabelValues("Disconnecting").Set(float64(len(s.peers.Disconnecting())))
 	p2pPeerCount.WithLabelValues("Bad").Set(float64(len(s.peers.Bad())))
 
+	connsByTransport := map[string]float64{"tcp": 0, "quic": 0}
+	for _, conn := range s.Host().Network().Conns() {
+		connsByTransport[connTransport(conn.RemoteMultiaddr())]++
+	}
+	for transport, total := range connsByTransport {
+		connectionsByTransport.WithLabelValues(transport).Set(total)
+	}
+
 	store := s.Host().Peerstore()
 	numConnectedPeersByClient := make(map[string]float64)
 	peerScoresByClient := make(map[string][]float64)
@@ -183,6 +197,17 @@ func (s *Service) updateMetrics() {
 	}
 }
 
+// connTransport names the transport of a connection from its remote multiaddr.
+func connTransport(addr ma.Multiaddr) string {
+	if _, err := addr.ValueForProtocol(ma.P_QUIC_V1); err == nil {
+		return "quic"
+	}
+	if _, err := addr.ValueForProtocol(ma.P_TCP); err == nil {
+		return "tcp"
+	}
+	return "other"
+}
+
 func average(xs []float64) float64 {
 	if len(xs) == 0 {
 		return 0
@@ -10,6 +10,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/muxer/mplex"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
+	libp2pquic "github.com/libp2p/go-libp2p/p2p/transport/quic"
 	"github.com/libp2p/go-libp2p/p2p/transport/tcp"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
@@ -30,21 +31,41 @@ func MultiAddressBuilder(ipAddr string, port uint) (ma.Multiaddr, error) {
 	return ma.NewMultiaddr(fmt.Sprintf("/ip6/%s/tcp/%d", ipAddr, port))
 }
 
+// QUICMultiAddressBuilder takes in an ip address string and udp port to produce a go multiaddr format
+// for the quic transport.
+func QUICMultiAddressBuilder(ipAddr string, port uint) (ma.Multiaddr, error) {
+	parsedIP := net.ParseIP(ipAddr)
+	if parsedIP.To4() == nil && parsedIP.To16() == nil {
+		return nil, errors.Errorf("invalid ip address provided: %s", ipAddr)
+	}
+	if parsedIP.To4() != nil {
+		return ma.NewMultiaddr(fmt.Sprintf("/ip4/%s/udp/%d/quic-v1", ipAddr, port))
+	}
+	return ma.NewMultiaddr(fmt.Sprintf("/ip6/%s/udp/%d/quic-v1", ipAddr, port))
+}
+
 // buildOptions for the libp2p host.
 func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Option {
 	cfg := s.cfg
-	listen, err := MultiAddressBuilder(ip.String(), cfg.TCPPort)
-	if err != nil {
-		log.WithError(err).Fatal("Failed to p2p listen")
-	}
+	listenIP := ip.String()
 	if cfg.LocalIP != "" {
 		if net.ParseIP(cfg.LocalIP) == nil {
 			log.Fatalf("Invalid local ip provided: %s", cfg.LocalIP)
 		}
-		listen, err = MultiAddressBuilder(cfg.LocalIP, cfg.TCPPort)
+		listenIP = cfg.LocalIP
+	}
+	listen, err := MultiAddressBuilder(listenIP, cfg.TCPPort)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to p2p listen")
+	}
+	listenAddrs := []ma.Multiaddr{listen}
+	enableQUIC := features.Get().EnableQUIC
+	if enableQUIC {
+		quicListen, err := QUICMultiAddressBuilder(listenIP, cfg.QUICPort)
 		if err != nil {
 			log.WithError(err).Fatal("Failed to p2p listen")
 		}
+		listenAddrs = append(listenAddrs, quicListen)
 	}
 	ifaceKey, err := ecdsaprysm.ConvertToInterfacePrivkey(priKey)
 	if err != nil {
@@ -58,7 +79,7 @@ func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 
 	options := []libp2p.Option{
 		privKeyOption(priKey),
-		libp2p.ListenAddrs(listen),
+		libp2p.ListenAddrs(listenAddrs...),
 		libp2p.UserAgent(version.BuildData()),
 		libp2p.ConnectionGater(s),
 		libp2p.Transport(tcp.NewTCPTransport),
@@ -66,6 +87,12 @@ func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 		libp2p.Muxer("/mplex/6.7.0", mplex.DefaultTransport),
 	}
 
+	// The quic transport brings its own encryption and stream multiplexing, so the security and
+	// muxer options only apply to tcp connections.
+	if enableQUIC {
+		options = append(options, libp2p.Transport(libp2pquic.NewTransport))
+	}
+
 	options = append(options, libp2p.Security(noise.ID, noise.New))
 
 	if cfg.EnableUPnP {
@@ -85,6 +112,14 @@ func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 			} else {
 				addrs = append(addrs, external)
 			}
+			if enableQUIC {
+				external, err := QUICMultiAddressBuilder(cfg.HostAddress, cfg.QUICPort)
+				if err != nil {
+					log.WithError(err).Error("Unable to create external quic multiaddress")
+				} else {
+					addrs = append(addrs, external)
+				}
+			}
 			return addrs
 		}))
 	}
@@ -96,6 +131,14 @@ func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 			} else {
 				addrs = append(addrs, external)
 			}
+			if enableQUIC {
+				external, err := ma.NewMultiaddr(fmt.Sprintf("/dns4/%s/udp/%d/quic-v1", cfg.HostDNS, cfg.QUICPort))
+				if err != nil {
+					log.WithError(err).Error("Unable to create external quic multiaddress")
+				} else {
+					addrs = append(addrs, external)
+				}
+			}
 			return addrs
 		}))
 	}
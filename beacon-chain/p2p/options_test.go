@@ -11,10 +11,12 @@ import (
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multiaddr"
 	gethCrypto "github.com/theQRL/go-zond/crypto"
 	"github.com/theQRL/go-zond/p2p/enode"
 	"github.com/theQRL/go-zond/p2p/enr"
 	mock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
+	"github.com/theQRL/qrysm/v4/config/features"
 	"github.com/theQRL/qrysm/v4/config/params"
 	ecdsaprysm "github.com/theQRL/qrysm/v4/crypto/ecdsa"
 	"github.com/theQRL/qrysm/v4/network"
@@ -127,3 +129,36 @@ func TestDefaultMultiplexers(t *testing.T) {
 	assert.Equal(t, protocol.ID("/mplex/6.7.0"), cfg.Muxers[1].ID)
 
 }
+
+func TestQUICTransport(t *testing.T) {
+	p2pCfg := &Config{
+		TCPPort:       2000,
+		UDPPort:       2000,
+		QUICPort:      3000,
+		StateNotifier: &mock.MockStateNotifier{},
+	}
+	svc := &Service{cfg: p2pCfg}
+	var err error
+	svc.privKey, err = privKey(svc.cfg)
+	require.NoError(t, err)
+
+	var cfg libp2p.Config
+	require.NoError(t, cfg.Apply(svc.buildOptions(net.ParseIP("127.0.0.1"), svc.privKey)...))
+	assert.Equal(t, 1, len(cfg.Transports))
+	assert.DeepEqual(t, []string{"/ip4/127.0.0.1/tcp/2000"}, addrStrings(cfg.ListenAddrs))
+
+	resetCfg := features.InitWithReset(&features.Flags{EnableQUIC: true})
+	defer resetCfg()
+	cfg = libp2p.Config{}
+	require.NoError(t, cfg.Apply(svc.buildOptions(net.ParseIP("127.0.0.1"), svc.privKey)...))
+	assert.Equal(t, 2, len(cfg.Transports))
+	assert.DeepEqual(t, []string{"/ip4/127.0.0.1/tcp/2000", "/ip4/127.0.0.1/udp/3000/quic-v1"}, addrStrings(cfg.ListenAddrs))
+}
+
+func addrStrings(addrs []multiaddr.Multiaddr) []string {
+	s := make([]string, len(addrs))
+	for i, a := range addrs {
+		s[i] = a.String()
+	}
+	return s
+}
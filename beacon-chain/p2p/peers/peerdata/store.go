@@ -61,6 +61,8 @@ type PeerData struct {
 	BadResponses         int
 	ProcessedBlocks      uint64
 	BlockProviderUpdated time.Time
+	Bandwidth            float64
+	Latency              time.Duration
 	// Gossip Scoring data.
 	TopicScores      map[string]*zondpb.TopicScoreSnapshot
 	GossipScore      float64
@@ -0,0 +1,203 @@
+package scorers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers/peerdata"
+	"github.com/theQRL/qrysm/v4/config/features"
+)
+
+var _ Scorer = (*BandwidthScorer)(nil)
+
+const (
+	// DefaultBandwidthTargetBandwidth defines the default throughput, in bytes per second, of a peer that
+	// is neither rewarded nor penalized.
+	DefaultBandwidthTargetBandwidth = float64(512 * 1024)
+	// DefaultBandwidthTargetLatency defines the default round trip time of a peer that is neither
+	// rewarded nor penalized.
+	DefaultBandwidthTargetLatency = 250 * time.Millisecond
+	// DefaultBandwidthSmoothing defines the default weight of a new sample in the moving averages.
+	DefaultBandwidthSmoothing = 0.2
+	// DefaultBandwidthMinResponseSize defines the default size of the smallest response whose throughput
+	// is sampled. Smaller responses are dominated by latency, so they only sample latency.
+	DefaultBandwidthMinResponseSize = uint64(16 * 1024)
+)
+
+// BandwidthScorer represents a scoring service which measures the sustained throughput and round trip
+// latency of the req/resp requests made to peers.
+type BandwidthScorer struct {
+	config *BandwidthScorerConfig
+	store  *peerdata.Store
+}
+
+// BandwidthScorerConfig holds configuration parameters for bandwidth scoring service.
+type BandwidthScorerConfig struct {
+	// TargetBandwidth is the throughput, in bytes per second, at which the bandwidth half of the score
+	// is zero. Faster peers are rewarded, up to twice the target, and slower peers are penalized.
+	TargetBandwidth float64
+	// TargetLatency is the round trip time at which the latency half of the score is zero. Peers
+	// answering faster are rewarded, up to half the target, and slower peers are penalized.
+	TargetLatency time.Duration
+	// Smoothing is the weight of a new sample in the exponential moving averages of the throughput
+	// and latency of a peer, in (0; 1].
+	Smoothing float64
+	// MinResponseSize is the size, in bytes, of the smallest response whose throughput is sampled.
+	MinResponseSize uint64
+}
+
+// newBandwidthScorer creates new bandwidth scoring service.
+func newBandwidthScorer(store *peerdata.Store, config *BandwidthScorerConfig) *BandwidthScorer {
+	if config == nil {
+		config = &BandwidthScorerConfig{}
+	}
+	scorer := &BandwidthScorer{
+		config: config,
+		store:  store,
+	}
+	if scorer.config.TargetBandwidth == 0 {
+		scorer.config.TargetBandwidth = DefaultBandwidthTargetBandwidth
+	}
+	if scorer.config.TargetLatency == 0 {
+		scorer.config.TargetLatency = DefaultBandwidthTargetLatency
+	}
+	if scorer.config.Smoothing <= 0 || scorer.config.Smoothing > 1 {
+		scorer.config.Smoothing = DefaultBandwidthSmoothing
+	}
+	if scorer.config.MinResponseSize == 0 {
+		scorer.config.MinResponseSize = DefaultBandwidthMinResponseSize
+	}
+	return scorer
+}
+
+// Score returns calculated peer score, in [-1; 1]. Peers that have not been measured yet score 0.
+func (s *BandwidthScorer) Score(pid peer.ID) float64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.score(pid)
+}
+
+// score is a lock-free version of Score.
+func (s *BandwidthScorer) score(pid peer.ID) float64 {
+	peerData, ok := s.store.PeerData(pid)
+	if !ok {
+		return 0
+	}
+	score := float64(0)
+	// Each half of the score is the log2 of the ratio of the peer's measurement to the target, capped
+	// at a factor of two either way, so that a peer twice as fast as the target gets the full reward.
+	if peerData.Bandwidth > 0 {
+		score += 0.5 * cappedLog2(peerData.Bandwidth/s.config.TargetBandwidth)
+	}
+	if peerData.Latency > 0 {
+		score += 0.5 * cappedLog2(float64(s.config.TargetLatency)/float64(peerData.Latency))
+	}
+	return math.Round(score*ScoreRoundingFactor) / ScoreRoundingFactor
+}
+
+// cappedLog2 returns log2(x) bounded to [-1; 1].
+func cappedLog2(x float64) float64 {
+	return math.Max(-1, math.Min(1, math.Log2(x)))
+}
+
+// Params exposes peer scorer parameters.
+func (s *BandwidthScorer) Params() *BandwidthScorerConfig {
+	return s.config
+}
+
+// IsBadPeer states if the peer is to be considered bad.
+// Slow peers are still useful, so this scorer never marks peers as bad, and relies on scores to
+// favour fast peers when selecting and pruning peers.
+func (_ *BandwidthScorer) IsBadPeer(_ peer.ID) bool {
+	return false
+}
+
+// BadPeers returns the peers that are considered bad.
+// No peers are considered bad by bandwidth scorer.
+func (_ *BandwidthScorer) BadPeers() []peer.ID {
+	return []peer.ID{}
+}
+
+// RecordResponse samples the latency and throughput of a req/resp request made to a peer: latency is
+// the time it took for the first chunk of the response to arrive, and elapsed the time it took for the
+// whole response of the given size to arrive.
+func (s *BandwidthScorer) RecordResponse(pid peer.ID, size uint64, latency, elapsed time.Duration) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	peerData := s.store.PeerDataGetOrCreate(pid)
+	if latency > 0 {
+		peerData.Latency = time.Duration(s.smooth(float64(peerData.Latency), float64(latency)))
+	}
+	if size >= s.config.MinResponseSize && elapsed > 0 {
+		peerData.Bandwidth = s.smooth(peerData.Bandwidth, float64(size)/elapsed.Seconds())
+	}
+}
+
+// RecordFailure samples a req/resp request made to a peer which failed or timed out after elapsed. The
+// failure is folded into the moving averages as a slow response: its latency is at least twice the target,
+// and its throughput at most half the target, so that peers failing requests rank below slow peers.
+func (s *BandwidthScorer) RecordFailure(pid peer.ID, elapsed time.Duration) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	peerData := s.store.PeerDataGetOrCreate(pid)
+	latency := elapsed
+	if latency < 2*s.config.TargetLatency {
+		latency = 2 * s.config.TargetLatency
+	}
+	peerData.Latency = time.Duration(s.smooth(float64(peerData.Latency), float64(latency)))
+	bandwidth := s.config.TargetBandwidth / 2
+	if peerData.Bandwidth > 0 {
+		bandwidth = math.Min(bandwidth, peerData.Bandwidth)
+	}
+	peerData.Bandwidth = s.smooth(peerData.Bandwidth, bandwidth)
+}
+
+// smooth folds a sample into an exponential moving average. The first sample sets the average.
+func (s *BandwidthScorer) smooth(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return s.config.Smoothing*sample + (1-s.config.Smoothing)*avg
+}
+
+// Bandwidth returns the moving average of the throughput of a peer, in bytes per second, or 0 if it
+// has not been measured yet.
+func (s *BandwidthScorer) Bandwidth(pid peer.ID) float64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	if peerData, ok := s.store.PeerData(pid); ok {
+		return peerData.Bandwidth
+	}
+	return 0
+}
+
+// Latency returns the moving average of the round trip latency of a peer, or 0 if it has not been
+// measured yet.
+func (s *BandwidthScorer) Latency(pid peer.ID) time.Duration {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	if peerData, ok := s.store.PeerData(pid); ok {
+		return peerData.Latency
+	}
+	return 0
+}
+
+// FormatScorePretty returns full scoring information in a human-readable format.
+func (s *BandwidthScorer) FormatScorePretty(pid peer.ID) string {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	if !features.Get().EnablePeerScorer {
+		return "disabled"
+	}
+	var bandwidth float64
+	var latency time.Duration
+	if peerData, ok := s.store.PeerData(pid); ok {
+		bandwidth, latency = peerData.Bandwidth, peerData.Latency
+	}
+	return fmt.Sprintf("[score: %0.2f, bandwidth: %0.1fKiB/s, latency: %v]",
+		s.score(pid), bandwidth/1024, latency.Round(time.Millisecond))
+}
@@ -0,0 +1,164 @@
+package scorers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers/scorers"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+)
+
+func TestScorers_Bandwidth_Score(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	target := scorers.DefaultBandwidthTargetBandwidth
+	size := uint64(4 * scorers.DefaultBandwidthMinResponseSize)
+	// elapsedAt returns the time it takes to receive a response of the test size at a given throughput.
+	elapsedAt := func(bandwidth float64) time.Duration {
+		return time.Duration(float64(size) / bandwidth * float64(time.Second))
+	}
+	latency := scorers.DefaultBandwidthTargetLatency
+
+	tests := []struct {
+		name   string
+		update func(scorer *scorers.BandwidthScorer)
+		check  func(scorer *scorers.BandwidthScorer)
+	}{
+		{
+			name: "nonexistent peer",
+			update: func(scorer *scorers.BandwidthScorer) {
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, 0.0, scorer.Score("peer1"), "Unexpected score")
+				assert.Equal(t, 0.0, scorer.Bandwidth("peer1"))
+				assert.Equal(t, time.Duration(0), scorer.Latency("peer1"))
+			},
+		},
+		{
+			name: "peer at target",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, latency, elapsedAt(target))
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, 0.0, scorer.Score("peer1"), "Unexpected score")
+				assert.Equal(t, latency, scorer.Latency("peer1"))
+			},
+		},
+		{
+			name: "fast peer",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, latency/2, elapsedAt(2*target))
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, 1.0, scorer.Score("peer1"), "Unexpected score")
+			},
+		},
+		{
+			name: "score is capped",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, latency/10, elapsedAt(10*target))
+				scorer.RecordResponse("peer2", size, latency*10, elapsedAt(target/10))
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, 1.0, scorer.Score("peer1"), "Unexpected score")
+				assert.Equal(t, -1.0, scorer.Score("peer2"), "Unexpected score")
+			},
+		},
+		{
+			name: "slow peer",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, 2*latency, elapsedAt(target/2))
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, -1.0, scorer.Score("peer1"), "Unexpected score")
+				assert.Equal(t, false, scorer.IsBadPeer("peer1"), "Unexpected bad peer")
+			},
+		},
+		{
+			name: "small responses only sample latency",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", 1, latency, time.Millisecond)
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, 0.0, scorer.Bandwidth("peer1"))
+				assert.Equal(t, latency, scorer.Latency("peer1"))
+			},
+		},
+		{
+			name: "samples are smoothed",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, latency, elapsedAt(target))
+				scorer.RecordResponse("peer1", size, 2*latency, elapsedAt(2*target))
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				smoothing := scorer.Params().Smoothing
+				assert.Equal(t, roundScore(target*(1+smoothing)), roundScore(scorer.Bandwidth("peer1")))
+				assert.Equal(t, time.Duration(float64(latency)*(1+smoothing)), scorer.Latency("peer1"))
+			},
+		},
+		{
+			name: "failure of unmeasured peer",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordFailure("peer1", time.Millisecond)
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, -1.0, scorer.Score("peer1"))
+				assert.Equal(t, 2*latency, scorer.Latency("peer1"))
+			},
+		},
+		{
+			name: "failure after timeout",
+			update: func(scorer *scorers.BandwidthScorer) {
+				scorer.RecordResponse("peer1", size, latency, elapsedAt(target))
+				scorer.RecordFailure("peer1", 10*latency)
+			},
+			check: func(scorer *scorers.BandwidthScorer) {
+				assert.Equal(t, roundScore(target*(1-scorer.Params().Smoothing/2)), roundScore(scorer.Bandwidth("peer1")))
+				assert.Equal(t, time.Duration(float64(latency)*(1+9*scorer.Params().Smoothing)), scorer.Latency("peer1"))
+				assert.Equal(t, true, scorer.Score("peer1") < 0, "Unexpected score")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+				ScorerParams: &scorers.Config{},
+			})
+			scorer := peerStatuses.Scorers().BandwidthScorer()
+			tt.update(scorer)
+			tt.check(scorer)
+		})
+	}
+}
+
+func TestScorers_Bandwidth_OverallScore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &scorers.Config{},
+	})
+	s := peerStatuses.Scorers()
+	scorer := s.BandwidthScorer()
+	size := 4 * scorer.Params().MinResponseSize
+	for _, pid := range []peer.ID{"fast", "slow"} {
+		peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	}
+	scorer.RecordResponse("fast", size, scorer.Params().TargetLatency/2,
+		time.Duration(float64(size)/(2*scorer.Params().TargetBandwidth)*float64(time.Second)))
+	scorer.RecordResponse("slow", size, 2*scorer.Params().TargetLatency,
+		time.Duration(float64(size)/(scorer.Params().TargetBandwidth/2)*float64(time.Second)))
+
+	// Bandwidth weighs in the overall score, so that slow peers are pruned first, but never marks a peer as bad.
+	assert.Equal(t, 1.0, scorer.Score("fast"), "Unexpected bandwidth score of fast peer")
+	assert.Equal(t, -1.0, scorer.Score("slow"), "Unexpected bandwidth score of slow peer")
+	assert.Equal(t, 0.2, s.Score("fast"), "Unexpected overall score of fast peer")
+	assert.Equal(t, -0.2, s.Score("slow"), "Unexpected overall score of slow peer")
+	assert.Equal(t, false, s.IsBadPeer("slow"), "Unexpected bad peer")
+}
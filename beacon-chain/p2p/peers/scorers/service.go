@@ -36,6 +36,7 @@ type Service struct {
 		blockProviderScorer *BlockProviderScorer
 		peerStatusScorer    *PeerStatusScorer
 		gossipScorer        *GossipScorer
+		bandwidthScorer     *BandwidthScorer
 	}
 	weights     map[Scorer]float64
 	totalWeight float64
@@ -47,6 +48,7 @@ type Config struct {
 	BlockProviderScorerConfig *BlockProviderScorerConfig
 	PeerStatusScorerConfig    *PeerStatusScorerConfig
 	GossipScorerConfig        *GossipScorerConfig
+	BandwidthScorerConfig     *BandwidthScorerConfig
 }
 
 // NewService provides fully initialized peer scoring service.
@@ -58,13 +60,15 @@ func NewService(ctx context.Context, store *peerdata.Store, config *Config) *Ser
 
 	// Register scorers.
 	s.scorers.badResponsesScorer = newBadResponsesScorer(store, config.BadResponsesScorerConfig)
-	s.setScorerWeight(s.scorers.badResponsesScorer, 0.3)
+	s.setScorerWeight(s.scorers.badResponsesScorer, 0.25)
 	s.scorers.blockProviderScorer = newBlockProviderScorer(store, config.BlockProviderScorerConfig)
 	s.setScorerWeight(s.scorers.blockProviderScorer, 0.0)
 	s.scorers.peerStatusScorer = newPeerStatusScorer(store, config.PeerStatusScorerConfig)
-	s.setScorerWeight(s.scorers.peerStatusScorer, 0.3)
+	s.setScorerWeight(s.scorers.peerStatusScorer, 0.25)
 	s.scorers.gossipScorer = newGossipScorer(store, config.GossipScorerConfig)
-	s.setScorerWeight(s.scorers.gossipScorer, 0.4)
+	s.setScorerWeight(s.scorers.gossipScorer, 0.3)
+	s.scorers.bandwidthScorer = newBandwidthScorer(store, config.BandwidthScorerConfig)
+	s.setScorerWeight(s.scorers.bandwidthScorer, 0.2)
 
 	// Start background tasks.
 	go s.loop(ctx)
@@ -92,6 +96,11 @@ func (s *Service) GossipScorer() *GossipScorer {
 	return s.scorers.gossipScorer
 }
 
+// BandwidthScorer exposes the peer's bandwidth and latency scoring service.
+func (s *Service) BandwidthScorer() *BandwidthScorer {
+	return s.scorers.bandwidthScorer
+}
+
 // ActiveScorersCount returns number of scorers that can affect score (have non-zero weight).
 func (s *Service) ActiveScorersCount() int {
 	cnt := 0
@@ -120,6 +129,7 @@ func (s *Service) ScoreNoLock(pid peer.ID) float64 {
 	score += s.scorers.blockProviderScorer.score(pid) * s.scorerWeight(s.scorers.blockProviderScorer)
 	score += s.scorers.peerStatusScorer.score(pid) * s.scorerWeight(s.scorers.peerStatusScorer)
 	score += s.scorers.gossipScorer.score(pid) * s.scorerWeight(s.scorers.gossipScorer)
+	score += s.scorers.bandwidthScorer.score(pid) * s.scorerWeight(s.scorers.bandwidthScorer)
 	return math.Round(score*ScoreRoundingFactor) / ScoreRoundingFactor
 }
 
@@ -860,6 +860,37 @@ func TestPrunePeers_TrustedPeers(t *testing.T) {
 	}
 }
 
+func TestPrunePeers_SlowPeers(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{
+		EnablePeerScorer: true,
+	})
+	defer resetCfg()
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		PeerLimit:    30,
+		ScorerParams: &scorers.Config{},
+	})
+	for i := 0; i < 15; i++ {
+		createPeer(t, p, nil, network.DirOutbound, peerdata.PeerConnectionState(zondpb.ConnectionState_CONNECTED))
+	}
+	// The connected peer limit is exceeded by three inbound peers, so three of them are pruned.
+	var fast []peer.ID
+	for i := 0; i < 17; i++ {
+		fast = append(fast, createPeer(t, p, nil, network.DirInbound, peerdata.PeerConnectionState(zondpb.ConnectionState_CONNECTED)))
+	}
+	slow := createPeer(t, p, nil, network.DirInbound, peerdata.PeerConnectionState(zondpb.ConnectionState_CONNECTED))
+	bw := p.Scorers().BandwidthScorer()
+	target := bw.Params().TargetBandwidth
+	for _, pid := range fast {
+		bw.RecordResponse(pid, uint64(target), 50*time.Millisecond, time.Second)
+	}
+	bw.RecordResponse(slow, uint64(target), 2*time.Second, 20*time.Second)
+	assert.Equal(t, true, p.Scorers().Score(slow) < p.Scorers().Score(fast[0]), "Slow peer does not score below fast peers")
+
+	peersToPrune := p.PeersToPrune()
+	require.Equal(t, 3, len(peersToPrune))
+	assert.Equal(t, slow, peersToPrune[0], "Slow peer is not pruned first")
+}
+
 func TestStatus_BestPeer(t *testing.T) {
 	type peerConfig struct {
 		headSlot       primitives.Slot
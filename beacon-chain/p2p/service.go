@@ -74,6 +74,7 @@ type Service struct {
 	subnetsLockLock       sync.Mutex // Lock access to subnetsLock
 	initializationLock    sync.Mutex
 	dv5Listener           Listener
+	dnsIterator           enode.Iterator
 	startupErr            error
 	ctx                   context.Context
 	host                  host.Host
@@ -102,6 +103,14 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 
 	cfg.Discv5BootStrapAddr = dv5Nodes
 
+	if len(s.cfg.DNSDiscoveryURLs) > 0 && !s.cfg.NoDiscovery {
+		s.dnsIterator, err = newDNSIterator(s.cfg.DNSDiscoveryURLs, nil)
+		if err != nil {
+			log.WithError(err).Error("Failed to create dns discovery iterator")
+			return nil, err
+		}
+	}
+
 	ipAddr := prysmnetwork.IPAddr()
 	s.privKey, err = privKey(s.cfg)
 	if err != nil {
@@ -50,12 +50,18 @@ type TestP2P struct {
 
 // NewTestP2P initializes a new p2p test service.
 func NewTestP2P(t *testing.T) *TestP2P {
+	return NewTestP2PWithHost(t, bhost.NewBlankHost(swarmt.GenSwarm(t)))
+}
+
+// NewTestP2PWithHost initializes a new p2p test service on the given libp2p host, such as a
+// mocknet host. The options are passed on to the floodsub router.
+func NewTestP2PWithHost(t *testing.T, h host.Host, opts ...pubsub.Option) *TestP2P {
 	ctx := context.Background()
-	h := bhost.NewBlankHost(swarmt.GenSwarm(t))
-	ps, err := pubsub.NewFloodSub(ctx, h,
+	opts = append([]pubsub.Option{
 		pubsub.WithMessageSigning(false),
 		pubsub.WithStrictSignatureVerification(false),
-	)
+	}, opts...)
+	ps, err := pubsub.NewFloodSub(ctx, h, opts...)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,70 @@
+package simnet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+)
+
+// Clock is the simulated time shared by all the nodes of a network. It starts at genesis and only
+// moves when the test advances it, so that slots pass instantly and deterministically.
+//
+// The clock only drives the slot seen by the services through their startup.Clock. It does not drive
+// libp2p, whose latencies, stream deadlines and pubsub heartbeats use the wall clock, nor the parts of
+// the sync services which read the wall clock directly, such as the status handshake and the target
+// slot of initial sync. Scenarios running those services therefore start the network with a genesis
+// time in the past, and advance the clock up to the wall clock, see CatchUp.
+type Clock struct {
+	sync.RWMutex
+	genesis time.Time
+	vr      [32]byte
+	now     time.Time
+}
+
+// NewClock returns a clock stopped at the given genesis time.
+func NewClock(genesis time.Time, vr [32]byte) *Clock {
+	return &Clock{genesis: genesis, vr: vr, now: genesis}
+}
+
+// Now returns the simulated time.
+func (c *Clock) Now() time.Time {
+	c.RLock()
+	defer c.RUnlock()
+	return c.now
+}
+
+// Advance moves the simulated time forward.
+func (c *Clock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// AdvanceSlots moves the simulated time forward by the given number of slots.
+func (c *Clock) AdvanceSlots(n primitives.Slot) {
+	c.Advance(time.Duration(uint64(n)*params.BeaconConfig().SecondsPerSlot) * time.Second)
+}
+
+// CatchUp moves the simulated time forward to the wall clock, so that the services reading either
+// clock agree on the current slot.
+func (c *Clock) CatchUp() {
+	c.Lock()
+	defer c.Unlock()
+	if now := time.Now(); now.After(c.now) {
+		c.now = now
+	}
+}
+
+// CurrentSlot returns the slot of the simulated time.
+func (c *Clock) CurrentSlot() primitives.Slot {
+	return c.Startup().CurrentSlot()
+}
+
+// Startup returns a startup.Clock reading the simulated time, which can be passed to the
+// services of a node in place of the wall clock.
+func (c *Clock) Startup() *startup.Clock {
+	return startup.NewClock(c.genesis, c.vr, startup.WithNower(c.Now))
+}
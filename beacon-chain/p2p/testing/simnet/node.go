@@ -0,0 +1,117 @@
+package simnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	ssz "github.com/prysmaticlabs/fastssz"
+	p2ptest "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+)
+
+// Behaviour describes how a node of the network deviates from the protocol.
+type Behaviour int
+
+const (
+	// Honest nodes relay every gossip message they receive.
+	Honest Behaviour = iota
+	// Withhold nodes drop every gossip message they receive, so they never relay the messages of
+	// other nodes. Messages they publish themselves are still sent.
+	Withhold
+	// Corrupt nodes tamper with every gossip message they receive before relaying it.
+	Corrupt
+)
+
+func (b Behaviour) String() string {
+	switch b {
+	case Honest:
+		return "honest"
+	case Withhold:
+		return "withhold"
+	case Corrupt:
+		return "corrupt"
+	default:
+		return fmt.Sprintf("behaviour(%d)", int(b))
+	}
+}
+
+// Node is a beacon node p2p service of a simulated network. It is a p2p.P2P implementation, so it can
+// be passed to the sync services under test, on a libp2p host of the network's mocknet.
+type Node struct {
+	*p2ptest.TestP2P
+	// Index is the position of the node in the network.
+	Index int
+	// Clock reads the simulated time of the network.
+	Clock *startup.Clock
+
+	lock      sync.RWMutex
+	behaviour Behaviour
+}
+
+// Behaviour returns how the node deviates from the protocol.
+func (n *Node) Behaviour() Behaviour {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.behaviour
+}
+
+func (n *Node) setBehaviour(b Behaviour) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.behaviour = b
+}
+
+// Topic returns the full name of a gossip topic, given one of the topic formats of the p2p package,
+// for the fork digest and encoding of the node.
+func (n *Node) Topic(format string) string {
+	return fmt.Sprintf(format, n.Digest) + n.Encoding().ProtocolSuffix()
+}
+
+// Publish gossips a message on the topic with the given format.
+func (n *Node) Publish(ctx context.Context, format string, msg ssz.Marshaler) error {
+	buf := new(bytes.Buffer)
+	if _, err := n.Encoding().EncodeGossip(buf, msg); err != nil {
+		return err
+	}
+	return n.PublishToTopic(ctx, n.Topic(format), buf.Bytes())
+}
+
+// Subscribe subscribes to the topic with the given format.
+func (n *Node) Subscribe(format string) (*pubsub.Subscription, error) {
+	return n.SubscribeToTopic(n.Topic(format))
+}
+
+// Receive waits for the next message of a subscription and decodes it into msg. It fails if no
+// message arrives within the timeout.
+func (n *Node) Receive(sub *pubsub.Subscription, msg ssz.Unmarshaler, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	m, err := sub.Next(ctx)
+	if err != nil {
+		return err
+	}
+	return n.Encoding().DecodeGossip(m.Data, msg)
+}
+
+// inspect applies the behaviour of the node and the packet loss of the network to the gossip
+// messages of an incoming rpc, before the router handles them.
+func (n *Node) inspect(rpc *pubsub.RPC, lost func() bool) {
+	behaviour := n.Behaviour()
+	kept := rpc.Publish[:0]
+	for _, m := range rpc.Publish {
+		if behaviour == Withhold || lost() {
+			continue
+		}
+		if behaviour == Corrupt && len(m.Data) > 0 {
+			data := append([]byte(nil), m.Data...)
+			data[len(data)-1] ^= 0xff
+			m.Data = data
+		}
+		kept = append(kept, m)
+	}
+	rpc.Publish = kept
+}
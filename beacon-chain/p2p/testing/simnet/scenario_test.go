@@ -0,0 +1,285 @@
+package simnet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/theQRL/go-bitfield"
+	mock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
+	dbtest "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
+	doublylinkedtree "github.com/theQRL/qrysm/v4/beacon-chain/forkchoice/doubly-linked-tree"
+	forkchoicetypes "github.com/theQRL/qrysm/v4/beacon-chain/forkchoice/types"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	p2ptypes "github.com/theQRL/qrysm/v4/beacon-chain/p2p/types"
+	beaconsync "github.com/theQRL/qrysm/v4/beacon-chain/sync"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+// initSyncFlags sets the flags read by the sync services for the duration of the test.
+func initSyncFlags(t *testing.T) {
+	resetFlags := flags.Get()
+	flags.Init(&flags.GlobalFlags{
+		MinimumSyncPeers:           1,
+		BlockBatchLimit:            64,
+		BlockBatchLimitBurstFactor: 10,
+		BlobBatchLimit:             8,
+		BlobBatchLimitBurstFactor:  2,
+		BlockBytesLimit:            2 * 1024 * 1024,
+		BlobBytesLimit:             64 * 1024,
+	})
+	t.Cleanup(func() {
+		flags.Init(resetFlags)
+	})
+}
+
+// genesisBefore returns a genesis time such that the given number of slots passed since genesis.
+func genesisBefore(slots primitives.Slot) time.Time {
+	return time.Now().Add(-time.Duration(uint64(slots)*params.BeaconConfig().SecondsPerSlot) * time.Second)
+}
+
+// newChain returns the chain of a node, at the genesis block, over a database of its own.
+func newChain(t *testing.T, node *Node, genesis interfaces.ReadOnlySignedBeaconBlock) *mock.ChainService {
+	ctx := context.Background()
+	d := dbtest.SetupDB(t)
+	require.NoError(t, d.SaveBlock(ctx, genesis))
+	root, err := genesis.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, d.SaveGenesisBlockRoot(ctx, root))
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	return &mock.ChainService{
+		DB:                  d,
+		State:               st,
+		Root:                root[:],
+		Block:               genesis,
+		Genesis:             node.Clock.GenesisTime(),
+		ValidatorsRoot:      node.Clock.GenesisValidatorsRoot(),
+		FinalizedCheckPoint: &zondpb.Checkpoint{Root: params.BeaconConfig().ZeroHash[:]},
+	}
+}
+
+// A node partitioned from the network while blocks are gossiped catches up with the chain once the network heals.
+// Its initial-sync service requests the missing blocks by range from the regular sync services of its peers.
+func TestScenario_LongRangeSyncCatchUp(t *testing.T) {
+	initSyncFlags(t)
+	chainLength := 2 * params.BeaconConfig().SlotsPerEpoch
+	n := New(t, &Config{Nodes: 3, Latency: 2 * time.Millisecond, GenesisTime: genesisBefore(chainLength)})
+	subs := subscribeAll(t, n)
+	genesis := util.NewBeaconBlock()
+	chains := make([]*mock.ChainService, len(n.Nodes()))
+	for i, node := range n.Nodes() {
+		chains[i] = newChain(t, node, testBlock(t, genesis))
+	}
+
+	// Node 0 proposes two epochs of blocks, which node 1 receives over gossip while node 2 is partitioned away.
+	ctx := context.Background()
+	n.Partition([]int{0, 1}, []int{2})
+	parent := genesis
+	for slot := primitives.Slot(1); slot <= chainLength; slot++ {
+		n.Clock().AdvanceSlots(1)
+		root, err := parent.Block.HashTreeRoot()
+		require.NoError(t, err)
+		b := util.NewBeaconBlock()
+		b.Block.Slot = slot
+		b.Block.ParentRoot = root[:]
+		require.NoError(t, n.Node(0).Publish(ctx, p2p.BlockSubnetTopicFormat, b))
+		require.NoError(t, chains[0].ReceiveBlock(ctx, testBlock(t, b), [32]byte{}))
+
+		got, err := receiveBlock(n.Node(1), subs[1], delivered)
+		require.NoError(t, err)
+		require.NoError(t, chains[1].ReceiveBlock(ctx, testBlock(t, got), [32]byte{}))
+		parent = b
+	}
+	_, err := receiveBlock(n.Node(2), subs[2], notDelivered)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, chainLength, chains[1].HeadSlot())
+	assert.Equal(t, primitives.Slot(0), chains[2].HeadSlot())
+
+	// Once healed, the status handshake tells node 2 that its peers are ahead, and it syncs from them.
+	n.Clock().CatchUp()
+	for i := 0; i < 2; i++ {
+		n.Node(i).StartServices(t, chains[i], false)
+	}
+	syncing := n.Node(2).StartServices(t, chains[2], true)
+	n.Heal()
+	deadline := time.Now().Add(time.Minute)
+	for !syncing.InitialSync.Synced() {
+		require.Equal(t, false, time.Now().After(deadline), "Node 2 did not sync within a minute")
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, chainLength, chains[2].HeadSlot())
+	assert.DeepEqual(t, chains[0].Root, chains[2].Root)
+}
+
+// voter is a node of the partition scenario. It imports the blocks it receives into its fork choice, along with the
+// votes of their attestations, and its regular sync service serves them to its peers.
+type voter struct {
+	*Node
+	chain *mock.ChainService
+	fc    *doublylinkedtree.ForkChoice
+}
+
+func newVoter(t *testing.T, node *Node, genesis interfaces.ReadOnlySignedBeaconBlock, validators int) *voter {
+	v := &voter{Node: node, chain: newChain(t, node, genesis), fc: doublylinkedtree.New()}
+	balances := make([]uint64, validators)
+	for i := range balances {
+		balances[i] = params.BeaconConfig().MaxEffectiveBalance
+	}
+	v.fc.SetBalancesByRooter(func(context.Context, [32]byte) ([]uint64, error) {
+		return balances, nil
+	})
+	v.fc.SetGenesisTime(uint64(node.Clock.GenesisTime().Unix()))
+	ctx := context.Background()
+	require.NoError(t, v.insert(ctx, genesis))
+	root, err := genesis.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, v.fc.UpdateJustifiedCheckpoint(ctx, &forkchoicetypes.Checkpoint{Root: root}))
+	require.NoError(t, v.fc.UpdateFinalizedCheckpoint(&forkchoicetypes.Checkpoint{Root: root}))
+	return v
+}
+
+// insert adds a block to the fork choice of the voter.
+func (v *voter) insert(ctx context.Context, b interfaces.ReadOnlySignedBeaconBlock) error {
+	st, err := util.NewBeaconState()
+	if err != nil {
+		return err
+	}
+	if err := st.SetSlot(b.Block().Slot()); err != nil {
+		return err
+	}
+	parent := b.Block().ParentRoot()
+	if err := st.SetLatestBlockHeader(&zondpb.BeaconBlockHeader{Slot: b.Block().Slot(), ParentRoot: parent[:]}); err != nil {
+		return err
+	}
+	root, err := b.Block().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	return v.fc.InsertNode(ctx, st, root)
+}
+
+// receive imports a gossiped block. Missing ancestors are requested by root from the given peer first, the way the
+// regular sync service resolves pending blocks.
+func (v *voter) receive(t *testing.T, from *Node, b interfaces.ReadOnlySignedBeaconBlock) {
+	ctx := context.Background()
+	chain := []interfaces.ReadOnlySignedBeaconBlock{b}
+	for parent := b.Block().ParentRoot(); !v.fc.HasNode(parent); parent = chain[0].Block().ParentRoot() {
+		req := &p2ptypes.BeaconBlockByRootsReq{parent}
+		got, err := beaconsync.SendBeaconBlocksByRootRequest(ctx, v.Clock, v, from.PeerID(), req, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(got), "Peer did not serve block %#x", parent)
+		chain = append([]interfaces.ReadOnlySignedBeaconBlock{got[0]}, chain...)
+	}
+	for _, blk := range chain {
+		require.NoError(t, v.chain.DB.SaveBlock(ctx, blk))
+		require.NoError(t, v.insert(ctx, blk))
+		for _, att := range blk.Block().Body().Attestations() {
+			var indices []uint64
+			for _, i := range att.AggregationBits.BitIndices() {
+				indices = append(indices, uint64(i))
+			}
+			v.fc.ProcessAttestation(ctx, indices, bytesutil.ToBytes32(att.Data.BeaconBlockRoot), att.Data.Target.Epoch)
+		}
+	}
+}
+
+func (v *voter) head(t *testing.T) [32]byte {
+	root, err := v.fc.Head(context.Background())
+	require.NoError(t, err)
+	return root
+}
+
+// propose builds a block on top of the head of the voter, carrying the votes of the given validators for that head.
+func (v *voter) propose(t *testing.T, slot primitives.Slot, validators int, attesters ...int) *zondpb.SignedBeaconBlock {
+	head := v.head(t)
+	bits := bitfield.NewBitlist(uint64(validators))
+	for _, i := range attesters {
+		bits.SetBitAt(uint64(i), true)
+	}
+	b := util.NewBeaconBlock()
+	b.Block.Slot = slot
+	b.Block.ProposerIndex = primitives.ValidatorIndex(v.Index)
+	b.Block.ParentRoot = head[:]
+	b.Block.Body.Attestations = []*zondpb.Attestation{util.HydrateAttestation(&zondpb.Attestation{
+		AggregationBits: bits,
+		Data:            &zondpb.AttestationData{Slot: slot - 1, BeaconBlockRoot: head[:]},
+	})}
+	return b
+}
+
+// Two sides of a partition build competing forks. Once the network heals, the nodes of the minority side fetch the
+// fork of the majority side from their peers, and every node converges on the head with the most votes.
+//
+// Node i runs validator i, and the committee of every slot is the whole validator set, so the aggregation bits of an
+// attestation are the indices of the attesting validators.
+func TestScenario_ForkChoiceConvergesAfterPartition(t *testing.T) {
+	initSyncFlags(t)
+	const validators = 5
+	majority, minority := []int{0, 1, 2}, []int{3, 4}
+	n := New(t, &Config{Nodes: validators, Latency: 2 * time.Millisecond, GenesisTime: genesisBefore(params.BeaconConfig().SlotsPerEpoch)})
+	subs := subscribeAll(t, n)
+	genesis := testBlock(t, util.NewBeaconBlock())
+	voters := make([]*voter, validators)
+	for i, node := range n.Nodes() {
+		voters[i] = newVoter(t, node, genesis, validators)
+		node.StartServices(t, voters[i].chain, false)
+	}
+
+	// gossip publishes a block of the proposer, and has every node reachable from it import the block. The proposer
+	// receives its own block too.
+	ctx := context.Background()
+	gossip := func(proposer int, b *zondpb.SignedBeaconBlock, receivers []int) {
+		require.NoError(t, n.Node(proposer).Publish(ctx, p2p.BlockSubnetTopicFormat, b))
+		for _, i := range receivers {
+			got, err := receiveBlock(n.Node(i), subs[i], delivered)
+			require.NoError(t, err)
+			voters[i].receive(t, n.Node(proposer), testBlock(t, got))
+		}
+	}
+
+	n.Partition(majority, minority)
+	n.Clock().AdvanceSlots(1)
+	gossip(0, voters[0].propose(t, 1, validators), majority)
+	gossip(3, voters[3].propose(t, 1, validators), minority)
+	n.Clock().AdvanceSlots(1)
+	gossip(1, voters[1].propose(t, 2, validators, majority...), majority)
+	gossip(4, voters[4].propose(t, 2, validators, minority...), minority)
+
+	majorityHead, minorityHead := voters[0].head(t), voters[3].head(t)
+	require.NotEqual(t, majorityHead, minorityHead, "The sides of the partition did not fork")
+	for _, i := range majority {
+		assert.Equal(t, majorityHead, voters[i].head(t))
+	}
+	for _, i := range minority {
+		assert.Equal(t, minorityHead, voters[i].head(t))
+	}
+
+	// After the heal, the next block of the majority does not extend the head of the minority, which requests its
+	// missing ancestors from the proposer and reorgs to it.
+	n.Heal()
+	require.NoError(t, n.WaitForTopicPeers(p2p.BlockSubnetTopicFormat, 5*time.Second))
+	n.Clock().AdvanceSlots(1)
+	b := voters[2].propose(t, 3, validators, majority...)
+	gossip(2, b, []int{0, 1, 2, 3, 4})
+	want, err := b.Block.HashTreeRoot()
+	require.NoError(t, err)
+	for i, v := range voters {
+		assert.Equal(t, want, v.head(t), "Node %d did not converge", i)
+	}
+}
+
+func testBlock(t *testing.T, b *zondpb.SignedBeaconBlock) interfaces.ReadOnlySignedBeaconBlock {
+	sb, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	return sb
+}
@@ -0,0 +1,102 @@
+package simnet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
+	"github.com/theQRL/qrysm/v4/beacon-chain/operations/attestations"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	beaconsync "github.com/theQRL/qrysm/v4/beacon-chain/sync"
+	initialsync "github.com/theQRL/qrysm/v4/beacon-chain/sync/initial-sync"
+	mockSync "github.com/theQRL/qrysm/v4/beacon-chain/sync/initial-sync/testing"
+)
+
+// Services are the sync services run by a node on its libp2p host.
+type Services struct {
+	// Chain is the chain of the node. Blocks synced by the node are saved to Chain.DB.
+	Chain *mock.ChainService
+	// Sync is the regular sync service of the node.
+	Sync *beaconsync.Service
+	// InitialSync is the initial-sync service of the node, if it runs one.
+	InitialSync *initialsync.Service
+}
+
+// StartServices starts the regular sync service of the node over the given chain, whose DB must be set. The
+// service serves the req/resp protocols from the chain's database, and runs the status handshake with the peers
+// the node connects to afterwards, so nodes must be connected, e.g. by healing the network, once their services
+// started.
+//
+// When initialSync is set, the node also runs the initial-sync service, which catches up with the chain of its
+// peers, and the regular sync service subscribes to gossip once the node is synced. Otherwise the node is never
+// synced, and gossip is left to the test. The services are stopped when the test ends.
+func (n *Node) StartServices(t *testing.T, chain *mock.ChainService, initialSync bool) *Services {
+	ctx, cancel := context.WithCancel(context.Background())
+	cs := startup.NewClockSynchronizer()
+	synced := make(chan struct{})
+	s := &Services{Chain: chain}
+	var checker beaconsync.Checker = &mockSync.Sync{IsSyncing: true}
+	if initialSync {
+		s.InitialSync = initialsync.NewService(ctx, &initialsync.Config{
+			P2P:                 n,
+			DB:                  chain.DB,
+			Chain:               chain,
+			StateNotifier:       chain.StateNotifier(),
+			BlockNotifier:       chain.BlockNotifier(),
+			ClockWaiter:         cs,
+			InitialSyncComplete: synced,
+		})
+		checker = s.InitialSync
+	}
+	s.Sync = beaconsync.NewService(ctx,
+		beaconsync.WithP2P(n),
+		beaconsync.WithDatabase(chain.DB),
+		beaconsync.WithChainService(chain),
+		beaconsync.WithInitialSync(checker),
+		beaconsync.WithAttestationPool(attestations.NewPool()),
+		beaconsync.WithBlockNotifier(chain.BlockNotifier()),
+		beaconsync.WithOperationNotifier(chain.OperationNotifier()),
+		beaconsync.WithClockWaiter(cs),
+		beaconsync.WithInitialSyncComplete(synced),
+	)
+	if s.Sync == nil {
+		t.Fatal("Could not create the regular sync service")
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := s.Sync.Stop(); err != nil {
+			t.Log(err)
+		}
+	})
+
+	s.Sync.Start()
+	if s.InitialSync != nil {
+		go s.InitialSync.Start()
+	}
+	if err := cs.SetClock(n.Clock); err != nil {
+		t.Fatal(err)
+	}
+	// The rpc handlers are registered once the service receives the clock. Peers connecting before would fail the
+	// status handshake.
+	status := protocol.ID(p2p.RPCStatusTopicV1 + n.Encoding().ProtocolSuffix())
+	deadline := time.Now().Add(5 * time.Second)
+	for !hasProtocol(n.Host().Mux().Protocols(), status) {
+		if time.Now().After(deadline) {
+			t.Fatal("The regular sync service did not register its rpc handlers")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return s
+}
+
+func hasProtocol(protocols []protocol.ID, p protocol.ID) bool {
+	for _, q := range protocols {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+// Package simnet runs networks of beacon node p2p services in process, on a libp2p mocknet, so that
+// the interaction of the p2p, sync and initial-sync packages can be tested as ordinary go tests.
+//
+// The nodes of a network share a simulated clock. Links between nodes have a configurable latency
+// and bandwidth, gossip can be lost, nodes can be partitioned from each other and individual nodes
+// can be made byzantine. Nodes can run the regular sync and initial-sync services on their host.
+package simnet
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-bitfield"
+	p2ptest "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/wrapper"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+)
+
+// Config of a simulated network.
+type Config struct {
+	// Nodes is the number of nodes of the network.
+	Nodes int
+	// Latency is the latency of every link.
+	Latency time.Duration
+	// Bandwidth is the bandwidth of every link, in bytes per second. Zero means unlimited.
+	Bandwidth float64
+	// PacketLoss is the probability for each gossip message received by a node to be lost.
+	PacketLoss float64
+	// Seed seeds the packet loss, so that runs are reproducible.
+	Seed int64
+	// GenesisTime is the genesis time of the simulated clock. It defaults to the start of the test.
+	// Scenarios running the sync services set it in the past, see Clock.
+	GenesisTime time.Time
+	// GenesisValidatorsRoot is used to compute the fork digest of the network.
+	GenesisValidatorsRoot [32]byte
+}
+
+// Network is a simulated network of nodes. All nodes are linked and connected to each other when
+// the network is created.
+type Network struct {
+	t     *testing.T
+	mn    mocknet.Mocknet
+	nodes []*Node
+	clock *Clock
+
+	lock       sync.Mutex
+	rng        *rand.Rand
+	packetLoss float64
+}
+
+// New creates a simulated network. It is closed when the test ends.
+func New(t *testing.T, cfg *Config) *Network {
+	if cfg.Nodes < 1 {
+		t.Fatalf("A simulated network needs at least one node, got %d", cfg.Nodes)
+	}
+	genesis := cfg.GenesisTime
+	if genesis.IsZero() {
+		genesis = time.Now()
+	}
+	digest, err := forks.ForkDigestFromEpoch(params.BeaconConfig().GenesisEpoch, cfg.GenesisValidatorsRoot[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &Network{
+		t:          t,
+		mn:         mocknet.New(),
+		clock:      NewClock(genesis, cfg.GenesisValidatorsRoot),
+		rng:        rand.New(rand.NewSource(cfg.Seed)), // #nosec G404 -- Reproducible packet loss.
+		packetLoss: cfg.PacketLoss,
+	}
+	t.Cleanup(func() {
+		if err := n.mn.Close(); err != nil {
+			t.Log(err)
+		}
+	})
+	n.mn.SetLinkDefaults(mocknet.LinkOptions{Latency: cfg.Latency, Bandwidth: cfg.Bandwidth})
+
+	for i := 0; i < cfg.Nodes; i++ {
+		h, err := n.mn.GenPeer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		node := &Node{Index: i, Clock: n.clock.Startup()}
+		node.TestP2P = p2ptest.NewTestP2PWithHost(t, &deadlineHost{Host: h}, pubsub.WithAppSpecificRpcInspector(
+			func(_ peer.ID, rpc *pubsub.RPC) error {
+				node.inspect(rpc, n.lost)
+				return nil
+			}))
+		node.Digest = digest
+		node.LocalMetadata = wrapper.WrappedMetadataV1(&zondpb.MetaDataV1{
+			Attnets:  bitfield.NewBitvector64(),
+			Syncnets: bitfield.NewBitvector4(),
+		})
+		n.nodes = append(n.nodes, node)
+	}
+	if err := n.mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	n.connectAll()
+	return n
+}
+
+// Nodes returns the nodes of the network.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Node returns the node with the given index.
+func (n *Network) Node(i int) *Node {
+	return n.nodes[i]
+}
+
+// Clock returns the simulated clock of the network.
+func (n *Network) Clock() *Clock {
+	return n.clock
+}
+
+// SetPacketLoss changes the probability for each gossip message received by a node to be lost.
+func (n *Network) SetPacketLoss(p float64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.packetLoss = p
+}
+
+func (n *Network) lost() bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.packetLoss > 0 && n.rng.Float64() < n.packetLoss
+}
+
+// SetByzantine changes how a node deviates from the protocol. Use Honest to restore it.
+func (n *Network) SetByzantine(i int, b Behaviour) {
+	n.nodes[i].setBehaviour(b)
+}
+
+// Cut removes the link between two nodes, disconnecting them. They stay apart until the network is
+// healed.
+func (n *Network) Cut(i, j int) {
+	a, b := n.nodes[i].PeerID(), n.nodes[j].PeerID()
+	if len(n.mn.LinksBetweenPeers(a, b)) > 0 {
+		if err := n.mn.UnlinkPeers(a, b); err != nil {
+			n.t.Fatal(err)
+		}
+	}
+	if err := n.mn.DisconnectPeers(a, b); err != nil {
+		n.t.Fatal(err)
+	}
+}
+
+// Partition splits the network into groups of node indices. Nodes can only reach the nodes of their
+// own group. Nodes missing from all groups are left connected to every node.
+func (n *Network) Partition(groups ...[]int) {
+	for gi, g := range groups {
+		for _, other := range groups[gi+1:] {
+			for _, i := range g {
+				for _, j := range other {
+					n.Cut(i, j)
+				}
+			}
+		}
+	}
+}
+
+// Heal links and connects all nodes again, undoing cuts and partitions.
+func (n *Network) Heal() {
+	for i, a := range n.nodes {
+		for _, b := range n.nodes[i+1:] {
+			if len(n.mn.LinksBetweenPeers(a.PeerID(), b.PeerID())) > 0 {
+				continue
+			}
+			if _, err := n.mn.LinkPeers(a.PeerID(), b.PeerID()); err != nil {
+				n.t.Fatal(err)
+			}
+		}
+	}
+	n.connectAll()
+}
+
+func (n *Network) connectAll() {
+	for i, a := range n.nodes {
+		for _, b := range n.nodes[i+1:] {
+			if len(a.Host().Network().ConnsToPeer(b.PeerID())) > 0 {
+				continue
+			}
+			if _, err := n.mn.ConnectPeers(a.PeerID(), b.PeerID()); err != nil {
+				n.t.Fatal(err)
+			}
+		}
+	}
+}
+
+// WaitForTopicPeers waits until every node knows that all the nodes it is connected to subscribed
+// to the topic with the given format, so that gossip published afterwards is delivered. All nodes
+// must have subscribed to the topic.
+func (n *Network) WaitForTopicPeers(format string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		ready := true
+		for _, a := range n.nodes {
+			want := 0
+			for _, b := range n.nodes {
+				if a != b && len(a.Host().Network().ConnsToPeer(b.PeerID())) > 0 {
+					want++
+				}
+			}
+			if len(a.PubSub().ListPeers(a.Topic(format))) < want {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("nodes did not see the subscriptions of their peers to %s within %v", format, timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
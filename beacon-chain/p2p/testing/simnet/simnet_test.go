@@ -0,0 +1,133 @@
+package simnet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+const (
+	delivered    = time.Second
+	notDelivered = 200 * time.Millisecond
+)
+
+func subscribeAll(t *testing.T, n *Network) []*pubsub.Subscription {
+	subs := make([]*pubsub.Subscription, len(n.Nodes()))
+	for i, node := range n.Nodes() {
+		sub, err := node.Subscribe(p2p.BlockSubnetTopicFormat)
+		require.NoError(t, err)
+		subs[i] = sub
+	}
+	require.NoError(t, n.WaitForTopicPeers(p2p.BlockSubnetTopicFormat, 5*time.Second))
+	return subs
+}
+
+func publishBlock(t *testing.T, node *Node, slot primitives.Slot) *zondpb.SignedBeaconBlock {
+	b := util.NewBeaconBlock()
+	b.Block.Slot = slot
+	require.NoError(t, node.Publish(context.Background(), p2p.BlockSubnetTopicFormat, b))
+	return b
+}
+
+func receiveBlock(node *Node, sub *pubsub.Subscription, timeout time.Duration) (*zondpb.SignedBeaconBlock, error) {
+	b := &zondpb.SignedBeaconBlock{}
+	return b, node.Receive(sub, b, timeout)
+}
+
+func TestNetwork_Gossip(t *testing.T) {
+	n := New(t, &Config{Nodes: 4, Latency: 5 * time.Millisecond})
+	subs := subscribeAll(t, n)
+
+	publishBlock(t, n.Node(0), 1)
+	for i, node := range n.Nodes() {
+		b, err := receiveBlock(node, subs[i], delivered)
+		require.NoError(t, err)
+		assert.Equal(t, primitives.Slot(1), b.Block.Slot)
+	}
+}
+
+func TestNetwork_Partition(t *testing.T) {
+	n := New(t, &Config{Nodes: 4})
+	subs := subscribeAll(t, n)
+
+	n.Partition([]int{0, 1}, []int{2, 3})
+	publishBlock(t, n.Node(0), 1)
+	_, err := receiveBlock(n.Node(1), subs[1], delivered)
+	require.NoError(t, err)
+	_, err = receiveBlock(n.Node(2), subs[2], notDelivered)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	n.Heal()
+	require.NoError(t, n.WaitForTopicPeers(p2p.BlockSubnetTopicFormat, 5*time.Second))
+	publishBlock(t, n.Node(0), 2)
+	b, err := receiveBlock(n.Node(2), subs[2], delivered)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(2), b.Block.Slot)
+}
+
+func TestNetwork_PacketLoss(t *testing.T) {
+	n := New(t, &Config{Nodes: 2, PacketLoss: 1})
+	subs := subscribeAll(t, n)
+
+	publishBlock(t, n.Node(0), 1)
+	_, err := receiveBlock(n.Node(1), subs[1], notDelivered)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	n.SetPacketLoss(0)
+	publishBlock(t, n.Node(0), 2)
+	b, err := receiveBlock(n.Node(1), subs[1], delivered)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(2), b.Block.Slot)
+}
+
+func TestNetwork_Byzantine(t *testing.T) {
+	// Node 2 can only hear from node 0 through node 1.
+	n := New(t, &Config{Nodes: 3})
+	n.Cut(0, 2)
+	subs := subscribeAll(t, n)
+
+	n.SetByzantine(1, Withhold)
+	publishBlock(t, n.Node(0), 1)
+	_, err := receiveBlock(n.Node(2), subs[2], notDelivered)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	n.SetByzantine(1, Corrupt)
+	sent := publishBlock(t, n.Node(0), 2)
+	b, err := receiveBlock(n.Node(2), subs[2], delivered)
+	require.Equal(t, false, errors.Is(err, context.DeadlineExceeded), "Expected the corrupted block to be relayed")
+	// A corrupted block either fails to decode or differs from the block that was sent.
+	if err == nil {
+		want, err := sent.HashTreeRoot()
+		require.NoError(t, err)
+		got, err := b.HashTreeRoot()
+		require.NoError(t, err)
+		assert.NotEqual(t, want, got, "Expected a corrupted block")
+	}
+
+	n.SetByzantine(1, Honest)
+	publishBlock(t, n.Node(0), 3)
+	b, err = receiveBlock(n.Node(2), subs[2], delivered)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(3), b.Block.Slot)
+}
+
+func TestClock(t *testing.T) {
+	n := New(t, &Config{Nodes: 2})
+	c := n.Clock()
+	assert.Equal(t, primitives.Slot(0), c.CurrentSlot())
+
+	c.AdvanceSlots(5)
+	assert.Equal(t, primitives.Slot(5), c.CurrentSlot())
+	for _, node := range n.Nodes() {
+		assert.Equal(t, primitives.Slot(5), node.Clock.CurrentSlot())
+	}
+}
@@ -0,0 +1,131 @@
+package simnet
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// deadlineHost wraps a mocknet host, whose streams do not support deadlines, so that the streams it opens and
+// handles do. The rpc handlers of the sync services refuse streams on which they cannot set a deadline.
+type deadlineHost struct {
+	host.Host
+}
+
+// NewStream opens a stream supporting deadlines.
+func (h *deadlineHost) NewStream(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+	s, err := h.Host.NewStream(ctx, p, pids...)
+	if err != nil {
+		return nil, err
+	}
+	return &deadlineStream{Stream: s}, nil
+}
+
+// SetStreamHandler sets a handler of streams supporting deadlines.
+func (h *deadlineHost) SetStreamHandler(pid protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(pid, func(s network.Stream) {
+		handler(&deadlineStream{Stream: s})
+	})
+}
+
+// SetStreamHandlerMatch sets a handler of streams supporting deadlines.
+func (h *deadlineHost) SetStreamHandlerMatch(pid protocol.ID, match func(protocol.ID) bool, handler network.StreamHandler) {
+	h.Host.SetStreamHandlerMatch(pid, match, func(s network.Stream) {
+		handler(&deadlineStream{Stream: s})
+	})
+}
+
+// deadlineStream emulates the deadlines of a stream on the wall clock. Reads and writes fail once their deadline
+// passed, and the stream is reset when a deadline passes while a read or a write is pending.
+type deadlineStream struct {
+	network.Stream
+	read, write deadline
+}
+
+func (s *deadlineStream) Read(b []byte) (int, error) {
+	if err := s.read.begin(); err != nil {
+		return 0, err
+	}
+	defer s.read.end()
+	return s.Stream.Read(b)
+}
+
+func (s *deadlineStream) Write(b []byte) (int, error) {
+	if err := s.write.begin(); err != nil {
+		return 0, err
+	}
+	defer s.write.end()
+	return s.Stream.Write(b)
+}
+
+func (s *deadlineStream) SetDeadline(t time.Time) error {
+	s.read.set(t, s.reset)
+	s.write.set(t, s.reset)
+	return nil
+}
+
+func (s *deadlineStream) SetReadDeadline(t time.Time) error {
+	s.read.set(t, s.reset)
+	return nil
+}
+
+func (s *deadlineStream) SetWriteDeadline(t time.Time) error {
+	s.write.set(t, s.reset)
+	return nil
+}
+
+func (s *deadlineStream) reset() {
+	_err := s.Stream.Reset()
+	_ = _err
+}
+
+// deadline of the reads or the writes of a stream.
+type deadline struct {
+	lock    sync.Mutex
+	at      time.Time
+	timer   *time.Timer
+	pending int
+}
+
+func (d *deadline) set(t time.Time, reset func()) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.at = t
+	if t.IsZero() {
+		return
+	}
+	d.timer = time.AfterFunc(time.Until(t), func() {
+		d.lock.Lock()
+		pending := d.pending > 0
+		d.lock.Unlock()
+		if pending {
+			reset()
+		}
+	})
+}
+
+func (d *deadline) begin() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.at.IsZero() && !time.Now().Before(d.at) {
+		return os.ErrDeadlineExceeded
+	}
+	d.pending++
+	return nil
+}
+
+func (d *deadline) end() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.pending--
+}
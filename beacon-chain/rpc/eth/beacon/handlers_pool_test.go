@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/theQRL/go-bitfield"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/go-zond/common/hexutil"
 	blockchainmock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/transition"
@@ -45,7 +46,8 @@ func TestListAttestations(t *testing.T) {
 				Root:  bytesutil.PadTo([]byte("targetroot1"), 32),
 			},
 		},
-		Signature: bytesutil.PadTo([]byte("signature1"), 96),
+		Signature:               bytesutil.PadTo([]byte("signature1"), 2*dilithium2.CryptoBytes),
+		SignatureValidatorIndex: []uint64{0, 9},
 	}
 	att2 := &zondpbv1alpha1.Attestation{
 		AggregationBits: []byte{1, 10},
@@ -62,7 +64,8 @@ func TestListAttestations(t *testing.T) {
 				Root:  bytesutil.PadTo([]byte("targetroot2"), 32),
 			},
 		},
-		Signature: bytesutil.PadTo([]byte("signature2"), 96),
+		Signature:               bytesutil.PadTo([]byte("signature2"), 2*dilithium2.CryptoBytes),
+		SignatureValidatorIndex: []uint64{0, 9},
 	}
 	att3 := &zondpbv1alpha1.Attestation{
 		AggregationBits: bitfield.NewBitlist(8),
@@ -17,7 +17,6 @@ import (
 	state_native "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls/common"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/crypto/hash"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
@@ -619,7 +618,7 @@ func TestSubmitSignedDilithiumToExecutionChanges_Ok(t *testing.T) {
 	validators := make([]*zondpbv1alpha1.Validator, numValidators)
 	dilithiumChanges := make([]*zondpbv2.DilithiumToExecutionChange, numValidators)
 	spb.Balances = make([]uint64, numValidators)
-	privKeys := make([]common.SecretKey, numValidators)
+	privKeys := make([]dilithium.DilithiumKey, numValidators)
 	maxEffectiveBalance := params.BeaconConfig().MaxEffectiveBalance
 	executionAddress := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13}
 
@@ -716,7 +715,7 @@ func TestSubmitSignedDilithiumToExecutionChanges_Bellatrix(t *testing.T) {
 	validators := make([]*zondpbv1alpha1.Validator, numValidators)
 	dilithiumChanges := make([]*zondpbv2.DilithiumToExecutionChange, numValidators)
 	spb.Balances = make([]uint64, numValidators)
-	privKeys := make([]common.SecretKey, numValidators)
+	privKeys := make([]dilithium.DilithiumKey, numValidators)
 	maxEffectiveBalance := params.BeaconConfig().MaxEffectiveBalance
 	executionAddress := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13}
 
@@ -828,7 +827,7 @@ func TestSubmitSignedDilithiumToExecutionChanges_Failures(t *testing.T) {
 	validators := make([]*zondpbv1alpha1.Validator, numValidators)
 	dilithiumChanges := make([]*zondpbv2.DilithiumToExecutionChange, numValidators)
 	spb.Balances = make([]uint64, numValidators)
-	privKeys := make([]common.SecretKey, numValidators)
+	privKeys := make([]dilithium.DilithiumKey, numValidators)
 	maxEffectiveBalance := params.BeaconConfig().MaxEffectiveBalance
 	executionAddress := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13}
 
@@ -32,48 +32,48 @@ func TestBlobs(t *testing.T) {
 	cfg.DenebForkEpoch = 1
 	params.OverrideBeaconConfig(cfg)
 
-	db := testDB.SetupDB(t)
+	bs := testDB.SetupBlobStorage(t)
 	blockroot := bytesutil.PadTo([]byte("blockroot"), 32)
-	require.NoError(t, db.SaveBlobSidecar(context.Background(), []*zond.BlobSidecar{
+	require.NoError(t, bs.SaveBlobSidecar(context.Background(), []*zond.BlobSidecar{
 		{
 			BlockRoot:       blockroot,
 			Index:           0,
 			Slot:            123,
-			BlockParentRoot: []byte("blockparentroot"),
+			BlockParentRoot: bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength),
 			ProposerIndex:   123,
-			Blob:            []byte("blob0"),
-			KzgCommitment:   []byte("kzgcommitment0"),
-			KzgProof:        []byte("kzgproof0"),
+			Blob:            bytesutil.PadTo([]byte("blob0"), fieldparams.BlobLength),
+			KzgCommitment:   bytesutil.PadTo([]byte("kzgcommitment0"), fieldparams.BLSPubkeyLength),
+			KzgProof:        bytesutil.PadTo([]byte("kzgproof0"), fieldparams.BLSPubkeyLength),
 		},
 		{
 			BlockRoot:       blockroot,
 			Index:           1,
 			Slot:            123,
-			BlockParentRoot: []byte("blockparentroot"),
+			BlockParentRoot: bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength),
 			ProposerIndex:   123,
-			Blob:            []byte("blob1"),
-			KzgCommitment:   []byte("kzgcommitment1"),
-			KzgProof:        []byte("kzgproof1"),
+			Blob:            bytesutil.PadTo([]byte("blob1"), fieldparams.BlobLength),
+			KzgCommitment:   bytesutil.PadTo([]byte("kzgcommitment1"), fieldparams.BLSPubkeyLength),
+			KzgProof:        bytesutil.PadTo([]byte("kzgproof1"), fieldparams.BLSPubkeyLength),
 		},
 		{
 			BlockRoot:       blockroot,
 			Index:           2,
 			Slot:            123,
-			BlockParentRoot: []byte("blockparentroot"),
+			BlockParentRoot: bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength),
 			ProposerIndex:   123,
-			Blob:            []byte("blob2"),
-			KzgCommitment:   []byte("kzgcommitment2"),
-			KzgProof:        []byte("kzgproof2"),
+			Blob:            bytesutil.PadTo([]byte("blob2"), fieldparams.BlobLength),
+			KzgCommitment:   bytesutil.PadTo([]byte("kzgcommitment2"), fieldparams.BLSPubkeyLength),
+			KzgProof:        bytesutil.PadTo([]byte("kzgproof2"), fieldparams.BLSPubkeyLength),
 		},
 		{
 			BlockRoot:       blockroot,
 			Index:           3,
 			Slot:            123,
-			BlockParentRoot: []byte("blockparentroot"),
+			BlockParentRoot: bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength),
 			ProposerIndex:   123,
-			Blob:            []byte("blob3"),
-			KzgCommitment:   []byte("kzgcommitment3"),
-			KzgProof:        []byte("kzgproof3"),
+			Blob:            bytesutil.PadTo([]byte("blob3"), fieldparams.BlobLength),
+			KzgCommitment:   bytesutil.PadTo([]byte("kzgcommitment3"), fieldparams.BLSPubkeyLength),
+			KzgProof:        bytesutil.PadTo([]byte("kzgproof3"), fieldparams.BLSPubkeyLength),
 		},
 	}))
 
@@ -99,7 +99,7 @@ func TestBlobs(t *testing.T) {
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
 			ChainInfoFetcher: &mockChain.ChainService{Root: blockroot},
-			BeaconDB:         db,
+			BlobStorage:      bs,
 		}
 
 		s.Blobs(writer, request)
@@ -113,41 +113,41 @@ func TestBlobs(t *testing.T) {
 		assert.Equal(t, "0x626c6f636b726f6f740000000000000000000000000000000000000000000000", sidecar.BlockRoot)
 		assert.Equal(t, "0", sidecar.Index)
 		assert.Equal(t, "123", sidecar.Slot)
-		assert.Equal(t, "0x626c6f636b706172656e74726f6f74", sidecar.BlockParentRoot)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength)), sidecar.BlockParentRoot)
 		assert.Equal(t, "123", sidecar.ProposerIndex)
-		assert.Equal(t, "0x626c6f6230", sidecar.Blob)
-		assert.Equal(t, "0x6b7a67636f6d6d69746d656e7430", sidecar.KZGCommitment)
-		assert.Equal(t, "0x6b7a6770726f6f6630", sidecar.KZGProof)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blob0"), fieldparams.BlobLength)), sidecar.Blob)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgcommitment0"), fieldparams.BLSPubkeyLength)), sidecar.KZGCommitment)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgproof0"), fieldparams.BLSPubkeyLength)), sidecar.KZGProof)
 		sidecar = resp.Data[1]
 		require.NotNil(t, sidecar)
 		assert.Equal(t, "0x626c6f636b726f6f740000000000000000000000000000000000000000000000", sidecar.BlockRoot)
 		assert.Equal(t, "1", sidecar.Index)
 		assert.Equal(t, "123", sidecar.Slot)
-		assert.Equal(t, "0x626c6f636b706172656e74726f6f74", sidecar.BlockParentRoot)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength)), sidecar.BlockParentRoot)
 		assert.Equal(t, "123", sidecar.ProposerIndex)
-		assert.Equal(t, "0x626c6f6231", sidecar.Blob)
-		assert.Equal(t, "0x6b7a67636f6d6d69746d656e7431", sidecar.KZGCommitment)
-		assert.Equal(t, "0x6b7a6770726f6f6631", sidecar.KZGProof)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blob1"), fieldparams.BlobLength)), sidecar.Blob)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgcommitment1"), fieldparams.BLSPubkeyLength)), sidecar.KZGCommitment)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgproof1"), fieldparams.BLSPubkeyLength)), sidecar.KZGProof)
 		sidecar = resp.Data[2]
 		require.NotNil(t, sidecar)
 		assert.Equal(t, "0x626c6f636b726f6f740000000000000000000000000000000000000000000000", sidecar.BlockRoot)
 		assert.Equal(t, "2", sidecar.Index)
 		assert.Equal(t, "123", sidecar.Slot)
-		assert.Equal(t, "0x626c6f636b706172656e74726f6f74", sidecar.BlockParentRoot)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength)), sidecar.BlockParentRoot)
 		assert.Equal(t, "123", sidecar.ProposerIndex)
-		assert.Equal(t, "0x626c6f6232", sidecar.Blob)
-		assert.Equal(t, "0x6b7a67636f6d6d69746d656e7432", sidecar.KZGCommitment)
-		assert.Equal(t, "0x6b7a6770726f6f6632", sidecar.KZGProof)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blob2"), fieldparams.BlobLength)), sidecar.Blob)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgcommitment2"), fieldparams.BLSPubkeyLength)), sidecar.KZGCommitment)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgproof2"), fieldparams.BLSPubkeyLength)), sidecar.KZGProof)
 		sidecar = resp.Data[3]
 		require.NotNil(t, sidecar)
 		assert.Equal(t, "0x626c6f636b726f6f740000000000000000000000000000000000000000000000", sidecar.BlockRoot)
 		assert.Equal(t, "3", sidecar.Index)
 		assert.Equal(t, "123", sidecar.Slot)
-		assert.Equal(t, "0x626c6f636b706172656e74726f6f74", sidecar.BlockParentRoot)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength)), sidecar.BlockParentRoot)
 		assert.Equal(t, "123", sidecar.ProposerIndex)
-		assert.Equal(t, "0x626c6f6233", sidecar.Blob)
-		assert.Equal(t, "0x6b7a67636f6d6d69746d656e7433", sidecar.KZGCommitment)
-		assert.Equal(t, "0x6b7a6770726f6f6633", sidecar.KZGProof)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blob3"), fieldparams.BlobLength)), sidecar.Blob)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgcommitment3"), fieldparams.BLSPubkeyLength)), sidecar.KZGCommitment)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgproof3"), fieldparams.BLSPubkeyLength)), sidecar.KZGProof)
 	})
 	t.Run("finalized", func(t *testing.T) {
 		u := "http://foo.example/finalized"
@@ -156,7 +156,7 @@ func TestBlobs(t *testing.T) {
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
 			ChainInfoFetcher: &mockChain.ChainService{FinalizedCheckPoint: &zond.Checkpoint{Root: blockroot}},
-			BeaconDB:         db,
+			BlobStorage:      bs,
 		}
 
 		s.Blobs(writer, request)
@@ -173,7 +173,7 @@ func TestBlobs(t *testing.T) {
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
 			ChainInfoFetcher: &mockChain.ChainService{CurrentJustifiedCheckPoint: &zond.Checkpoint{Root: blockroot}},
-			BeaconDB:         db,
+			BlobStorage:      bs,
 		}
 
 		s.Blobs(writer, request)
@@ -189,7 +189,7 @@ func TestBlobs(t *testing.T) {
 		writer := httptest.NewRecorder()
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
-			BeaconDB: db,
+			BlobStorage: bs,
 		}
 
 		s.Blobs(writer, request)
@@ -205,7 +205,7 @@ func TestBlobs(t *testing.T) {
 		writer := httptest.NewRecorder()
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
-			BeaconDB: db,
+			BlobStorage: bs,
 		}
 
 		s.Blobs(writer, request)
@@ -221,7 +221,7 @@ func TestBlobs(t *testing.T) {
 		writer := httptest.NewRecorder()
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
-			BeaconDB: db,
+			BlobStorage: bs,
 		}
 
 		s.Blobs(writer, request)
@@ -235,11 +235,11 @@ func TestBlobs(t *testing.T) {
 		assert.Equal(t, "0x626c6f636b726f6f740000000000000000000000000000000000000000000000", sidecar.BlockRoot)
 		assert.Equal(t, "2", sidecar.Index)
 		assert.Equal(t, "123", sidecar.Slot)
-		assert.Equal(t, "0x626c6f636b706172656e74726f6f74", sidecar.BlockParentRoot)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blockparentroot"), fieldparams.RootLength)), sidecar.BlockParentRoot)
 		assert.Equal(t, "123", sidecar.ProposerIndex)
-		assert.Equal(t, "0x626c6f6232", sidecar.Blob)
-		assert.Equal(t, "0x6b7a67636f6d6d69746d656e7432", sidecar.KZGCommitment)
-		assert.Equal(t, "0x6b7a6770726f6f6632", sidecar.KZGProof)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("blob2"), fieldparams.BlobLength)), sidecar.Blob)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgcommitment2"), fieldparams.BLSPubkeyLength)), sidecar.KZGCommitment)
+		assert.Equal(t, hexutil.Encode(bytesutil.PadTo([]byte("kzgproof2"), fieldparams.BLSPubkeyLength)), sidecar.KZGProof)
 	})
 	t.Run("slot before Deneb fork", func(t *testing.T) {
 		u := "http://foo.example/31"
@@ -272,7 +272,9 @@ func TestBlobs(t *testing.T) {
 		assert.Equal(t, true, strings.Contains(e.Message, "could not parse block ID"))
 	})
 	t.Run("ssz", func(t *testing.T) {
-		require.NoError(t, db.SaveBlobSidecar(context.Background(), []*zond.BlobSidecar{
+		// The blobs of a block are stored at a single slot, so the block is saved to its own storage.
+		bs := testDB.SetupBlobStorage(t)
+		require.NoError(t, bs.SaveBlobSidecar(context.Background(), []*zond.BlobSidecar{
 			{
 				BlockRoot:       blockroot,
 				Index:           0,
@@ -291,7 +293,7 @@ func TestBlobs(t *testing.T) {
 		writer.Body = &bytes.Buffer{}
 		s := &Server{
 			ChainInfoFetcher: &mockChain.ChainService{FinalizedCheckPoint: &zond.Checkpoint{Root: blockroot}},
-			BeaconDB:         db,
+			BlobStorage:      bs,
 		}
 
 		s.Blobs(writer, request)
@@ -7,5 +7,5 @@ import (
 
 type Server struct {
 	ChainInfoFetcher blockchain.ChainInfoFetcher
-	BeaconDB         db.ReadOnlyDatabase
+	BlobStorage      db.BlobStorage
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/theQRL/qrysm/v4/proto/migration"
 	zondpbservice "github.com/theQRL/qrysm/v4/proto/zond/service"
 	zondpb "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
 	"github.com/theQRL/qrysm/v4/runtime/version"
 	"github.com/theQRL/qrysm/v4/time/slots"
 	"google.golang.org/grpc/codes"
@@ -47,19 +48,25 @@ const (
 	PayloadAttributesTopic = "payload_attributes"
 	// BlobSidecarTopic represents a new blob sidecar event topic
 	BlobSidecarTopic = "blob_sidecar"
+	// LightClientFinalityUpdateTopic represents a new light client finality update event topic.
+	LightClientFinalityUpdateTopic = "light_client_finality_update"
+	// LightClientOptimisticUpdateTopic represents a new light client optimistic update event topic.
+	LightClientOptimisticUpdateTopic = "light_client_optimistic_update"
 )
 
 var casesHandled = map[string]bool{
-	HeadTopic:                       true,
-	BlockTopic:                      true,
-	AttestationTopic:                true,
-	VoluntaryExitTopic:              true,
-	FinalizedCheckpointTopic:        true,
-	ChainReorgTopic:                 true,
-	SyncCommitteeContributionTopic:  true,
-	DilithiumToExecutionChangeTopic: true,
-	PayloadAttributesTopic:          true,
-	BlobSidecarTopic:                true,
+	HeadTopic:                        true,
+	BlockTopic:                       true,
+	AttestationTopic:                 true,
+	VoluntaryExitTopic:               true,
+	FinalizedCheckpointTopic:         true,
+	ChainReorgTopic:                  true,
+	SyncCommitteeContributionTopic:   true,
+	DilithiumToExecutionChangeTopic:  true,
+	PayloadAttributesTopic:           true,
+	BlobSidecarTopic:                 true,
+	LightClientFinalityUpdateTopic:   true,
+	LightClientOptimisticUpdateTopic: true,
 }
 
 // StreamEvents allows requesting all events from a set of topics defined in the Ethereum consensus API standard.
@@ -227,6 +234,24 @@ func (s *Server) handleStateEvents(
 			return nil
 		}
 		return streamData(stream, FinalizedCheckpointTopic, finalizedCheckpoint)
+	case statefeed.LightClientFinalityUpdate:
+		if _, ok := requestedTopics[LightClientFinalityUpdateTopic]; !ok {
+			return nil
+		}
+		update, ok := event.Data.(*zondpbv2.LightClientFinalityUpdateWithVersion)
+		if !ok {
+			return nil
+		}
+		return streamData(stream, LightClientFinalityUpdateTopic, update)
+	case statefeed.LightClientOptimisticUpdate:
+		if _, ok := requestedTopics[LightClientOptimisticUpdateTopic]; !ok {
+			return nil
+		}
+		update, ok := event.Data.(*zondpbv2.LightClientOptimisticUpdateWithVersion)
+		if !ok {
+			return nil
+		}
+		return streamData(stream, LightClientOptimisticUpdateTopic, update)
 	case statefeed.Reorg:
 		if _, ok := requestedTopics[ChainReorgTopic]; !ok {
 			return nil
@@ -23,6 +23,9 @@ func PrepareStateFetchGRPCError(err error) error {
 	if parseErr, ok := err.(*lookup.StateIdParseError); ok {
 		return status.Errorf(codes.InvalidArgument, "Invalid state ID: %v", parseErr)
 	}
+	if prunedErr, ok := err.(*lookup.HistoryPrunedError); ok {
+		return status.Errorf(codes.NotFound, "State pruned: %v", prunedErr)
+	}
 	return status.Errorf(codes.Internal, "Invalid state ID: %v", err)
 }
 
@@ -41,6 +44,9 @@ func HandleGetBlockError(blk interfaces.ReadOnlySignedBeaconBlock, err error) er
 	if invalidBlockIdErr, ok := err.(*lookup.BlockIdParseError); ok {
 		return status.Errorf(codes.InvalidArgument, "Invalid block ID: %v", invalidBlockIdErr)
 	}
+	if prunedErr, ok := err.(*lookup.HistoryPrunedError); ok {
+		return status.Errorf(codes.NotFound, "Block pruned: %v", prunedErr)
+	}
 	if err != nil {
 		return status.Errorf(codes.Internal, "Could not get block from block ID: %v", err)
 	}
@@ -0,0 +1,362 @@
+package lightclient
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/shared"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	enginev1 "github.com/theQRL/qrysm/v4/proto/engine/v1"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+)
+
+var errNilValue = errors.New("nil value")
+
+func (b *LightClientBootstrap) ToConsensus() (*zondpbv2.LightClientBootstrap, error) {
+	if b == nil {
+		return nil, errNilValue
+	}
+	header, err := headerToConsensus(b.Header)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "Header")
+	}
+	committee, err := b.CurrentSyncCommittee.ToConsensus()
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "CurrentSyncCommittee")
+	}
+	branch, err := branchToConsensus(b.CurrentSyncCommitteeBranch)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "CurrentSyncCommitteeBranch")
+	}
+	return &zondpbv2.LightClientBootstrap{
+		Header:                     header,
+		CurrentSyncCommittee:       committee,
+		CurrentSyncCommitteeBranch: branch,
+	}, nil
+}
+
+func (c *SyncCommittee) ToConsensus() (*zondpbv2.SyncCommittee, error) {
+	if c == nil {
+		return nil, errNilValue
+	}
+	pubkeys := make([][]byte, len(c.Pubkeys))
+	for i, pk := range c.Pubkeys {
+		var err error
+		pubkeys[i], err = shared.DecodeHexWithLength(pk, dilithium.PublicKeyLength())
+		if err != nil {
+			return nil, shared.NewDecodeError(err, "Pubkeys["+strconv.Itoa(i)+"]")
+		}
+	}
+	aggregatePubkey, err := hexutil.Decode(c.AggregatePubkey)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "AggregatePubkey")
+	}
+	return &zondpbv2.SyncCommittee{
+		Pubkeys:         pubkeys,
+		AggregatePubkey: aggregatePubkey,
+	}, nil
+}
+
+func (u *LightClientUpdate) ToConsensus() (*zondpbv2.LightClientUpdate, error) {
+	if u == nil {
+		return nil, errNilValue
+	}
+	attestedHeader, err := headerToConsensus(u.AttestedHeader)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "AttestedHeader")
+	}
+	nextSyncCommittee, err := u.NextSyncCommittee.ToConsensus()
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "NextSyncCommittee")
+	}
+	nextSyncCommitteeBranch, err := branchToConsensus(u.NextSyncCommitteeBranch)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "NextSyncCommitteeBranch")
+	}
+	finalizedHeader, err := headerToConsensus(u.FinalizedHeader)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "FinalizedHeader")
+	}
+	finalityBranch, err := branchToConsensus(u.FinalityBranch)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "FinalityBranch")
+	}
+	syncAggregate, err := syncAggregateToConsensus(u.SyncAggregate)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SyncAggregate")
+	}
+	signatureSlot, err := strconv.ParseUint(u.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SignatureSlot")
+	}
+	return &zondpbv2.LightClientUpdate{
+		AttestedHeader:          attestedHeader,
+		NextSyncCommittee:       nextSyncCommittee,
+		NextSyncCommitteeBranch: nextSyncCommitteeBranch,
+		FinalizedHeader:         finalizedHeader,
+		FinalityBranch:          finalityBranch,
+		SyncAggregate:           syncAggregate,
+		SignatureSlot:           primitives.Slot(signatureSlot),
+	}, nil
+}
+
+func (u *LightClientFinalityUpdate) ToConsensus() (*zondpbv2.LightClientFinalityUpdate, error) {
+	if u == nil {
+		return nil, errNilValue
+	}
+	attestedHeader, err := headerToConsensus(u.AttestedHeader)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "AttestedHeader")
+	}
+	finalizedHeader, err := headerToConsensus(u.FinalizedHeader)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "FinalizedHeader")
+	}
+	finalityBranch, err := branchToConsensus(u.FinalityBranch)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "FinalityBranch")
+	}
+	syncAggregate, err := syncAggregateToConsensus(u.SyncAggregate)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SyncAggregate")
+	}
+	signatureSlot, err := strconv.ParseUint(u.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SignatureSlot")
+	}
+	return &zondpbv2.LightClientFinalityUpdate{
+		AttestedHeader:  attestedHeader,
+		FinalizedHeader: finalizedHeader,
+		FinalityBranch:  finalityBranch,
+		SyncAggregate:   syncAggregate,
+		SignatureSlot:   primitives.Slot(signatureSlot),
+	}, nil
+}
+
+func (u *LightClientOptimisticUpdate) ToConsensus() (*zondpbv2.LightClientOptimisticUpdate, error) {
+	if u == nil {
+		return nil, errNilValue
+	}
+	attestedHeader, err := headerToConsensus(u.AttestedHeader)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "AttestedHeader")
+	}
+	syncAggregate, err := syncAggregateToConsensus(u.SyncAggregate)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SyncAggregate")
+	}
+	signatureSlot, err := strconv.ParseUint(u.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SignatureSlot")
+	}
+	return &zondpbv2.LightClientOptimisticUpdate{
+		AttestedHeader: attestedHeader,
+		SyncAggregate:  syncAggregate,
+		SignatureSlot:  primitives.Slot(signatureSlot),
+	}, nil
+}
+
+// ToConsensus decodes the execution payload header of a block of the given fork version and its Merkle branch.
+func (h *LightClientExecutionHeader) ToConsensus(v string) (interfaces.ExecutionData, [][]byte, error) {
+	if h == nil {
+		return nil, nil, errNilValue
+	}
+	enc, err := hexutil.Decode(h.Execution)
+	if err != nil {
+		return nil, nil, shared.NewDecodeError(err, "Execution")
+	}
+	ver, err := version.FromString(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var execution interfaces.ExecutionData
+	switch ver {
+	case version.Bellatrix:
+		header := &enginev1.ExecutionPayloadHeader{}
+		if err := header.UnmarshalSSZ(enc); err != nil {
+			return nil, nil, shared.NewDecodeError(err, "Execution")
+		}
+		execution, err = blocks.WrappedExecutionPayloadHeader(header)
+	case version.Capella:
+		header := &enginev1.ExecutionPayloadHeaderCapella{}
+		if err := header.UnmarshalSSZ(enc); err != nil {
+			return nil, nil, shared.NewDecodeError(err, "Execution")
+		}
+		execution, err = blocks.WrappedExecutionPayloadHeaderCapella(header, 0)
+	case version.Deneb:
+		header := &enginev1.ExecutionPayloadHeaderDeneb{}
+		if err := header.UnmarshalSSZ(enc); err != nil {
+			return nil, nil, shared.NewDecodeError(err, "Execution")
+		}
+		execution, err = blocks.WrappedExecutionPayloadHeaderDeneb(header, 0)
+	default:
+		return nil, nil, errors.Errorf("no execution payload header in version %s", v)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	branch, err := branchToConsensus(h.ExecutionBranch)
+	if err != nil {
+		return nil, nil, shared.NewDecodeError(err, "ExecutionBranch")
+	}
+	return execution, branch, nil
+}
+
+// executionHeaderFromConsensus returns the SSZ encoded header of the execution payload of a block of the given
+// fork version.
+func executionHeaderFromConsensus(execution interfaces.ExecutionData, v int) ([]byte, error) {
+	if execution.IsBlinded() {
+		return execution.MarshalSSZ()
+	}
+	switch v {
+	case version.Bellatrix:
+		header, err := blocks.PayloadToHeader(execution)
+		if err != nil {
+			return nil, err
+		}
+		return header.MarshalSSZ()
+	case version.Capella:
+		header, err := blocks.PayloadToHeaderCapella(execution)
+		if err != nil {
+			return nil, err
+		}
+		return header.MarshalSSZ()
+	case version.Deneb:
+		header, err := blocks.PayloadToHeaderDeneb(execution)
+		if err != nil {
+			return nil, err
+		}
+		return header.MarshalSSZ()
+	default:
+		return nil, errors.Errorf("no execution payload in version %s", version.String(v))
+	}
+}
+
+func updateFromConsensus(u *zondpbv2.LightClientUpdate) *LightClientUpdate {
+	return &LightClientUpdate{
+		AttestedHeader:          headerFromConsensus(u.AttestedHeader),
+		NextSyncCommittee:       syncCommitteeFromConsensus(u.NextSyncCommittee),
+		NextSyncCommitteeBranch: branchFromConsensus(u.NextSyncCommitteeBranch),
+		FinalizedHeader:         headerFromConsensus(u.FinalizedHeader),
+		FinalityBranch:          branchFromConsensus(u.FinalityBranch),
+		SyncAggregate:           syncAggregateFromConsensus(u.SyncAggregate),
+		SignatureSlot:           strconv.FormatUint(uint64(u.SignatureSlot), 10),
+	}
+}
+
+func headerFromConsensus(h *zondpbv1.BeaconBlockHeader) *shared.BeaconBlockHeader {
+	if h == nil {
+		return nil
+	}
+	return &shared.BeaconBlockHeader{
+		Slot:          strconv.FormatUint(uint64(h.Slot), 10),
+		ProposerIndex: strconv.FormatUint(uint64(h.ProposerIndex), 10),
+		ParentRoot:    hexutil.Encode(h.ParentRoot),
+		StateRoot:     hexutil.Encode(h.StateRoot),
+		BodyRoot:      hexutil.Encode(h.BodyRoot),
+	}
+}
+
+func syncCommitteeFromConsensus(c *zondpbv2.SyncCommittee) *SyncCommittee {
+	if c == nil {
+		return nil
+	}
+	pubkeys := make([]string, len(c.Pubkeys))
+	for i, pk := range c.Pubkeys {
+		pubkeys[i] = hexutil.Encode(pk)
+	}
+	return &SyncCommittee{
+		Pubkeys:         pubkeys,
+		AggregatePubkey: hexutil.Encode(c.AggregatePubkey),
+	}
+}
+
+func syncAggregateFromConsensus(a *zondpbv1.SyncAggregate) *shared.SyncAggregate {
+	if a == nil {
+		return nil
+	}
+	return &shared.SyncAggregate{
+		SyncCommitteeBits:      hexutil.Encode(a.SyncCommitteeBits),
+		SyncCommitteeSignature: hexutil.Encode(a.SyncCommitteeSignature),
+	}
+}
+
+func branchFromConsensus(branch [][]byte) []string {
+	result := make([]string, len(branch))
+	for i, leaf := range branch {
+		result[i] = hexutil.Encode(leaf)
+	}
+	return result
+}
+
+func headerToConsensus(h *shared.BeaconBlockHeader) (*zondpbv1.BeaconBlockHeader, error) {
+	if h == nil {
+		return nil, errNilValue
+	}
+	slot, err := strconv.ParseUint(h.Slot, 10, 64)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "Slot")
+	}
+	proposerIndex, err := strconv.ParseUint(h.ProposerIndex, 10, 64)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "ProposerIndex")
+	}
+	parentRoot, err := shared.DecodeHexWithLength(h.ParentRoot, fieldparams.RootLength)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "ParentRoot")
+	}
+	stateRoot, err := shared.DecodeHexWithLength(h.StateRoot, fieldparams.RootLength)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "StateRoot")
+	}
+	bodyRoot, err := shared.DecodeHexWithLength(h.BodyRoot, fieldparams.RootLength)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "BodyRoot")
+	}
+	return &zondpbv1.BeaconBlockHeader{
+		Slot:          primitives.Slot(slot),
+		ProposerIndex: primitives.ValidatorIndex(proposerIndex),
+		ParentRoot:    parentRoot,
+		StateRoot:     stateRoot,
+		BodyRoot:      bodyRoot,
+	}, nil
+}
+
+// syncAggregateToConsensus decodes a sync aggregate. The signature holds one signature per participant, so its
+// length depends on the sync committee bits.
+func syncAggregateToConsensus(a *shared.SyncAggregate) (*zondpbv1.SyncAggregate, error) {
+	if a == nil {
+		return nil, errNilValue
+	}
+	bits, err := shared.DecodeHexWithLength(a.SyncCommitteeBits, fieldparams.SyncAggregateSyncCommitteeBytesLength)
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SyncCommitteeBits")
+	}
+	sig, err := shared.DecodeHexWithMaxLength(a.SyncCommitteeSignature, fieldparams.SyncCommitteeLength*dilithium.SignatureLength())
+	if err != nil {
+		return nil, shared.NewDecodeError(err, "SyncCommitteeSignature")
+	}
+	return &zondpbv1.SyncAggregate{
+		SyncCommitteeBits:      bits,
+		SyncCommitteeSignature: sig,
+	}, nil
+}
+
+func branchToConsensus(branch []string) ([][]byte, error) {
+	result := make([][]byte, len(branch))
+	for i, leaf := range branch {
+		var err error
+		result[i], err = shared.DecodeHexWithLength(leaf, fieldparams.RootLength)
+		if err != nil {
+			return nil, shared.NewDecodeError(err, "["+strconv.Itoa(i)+"]")
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestLightClientUpdate_ToConsensus(t *testing.T) {
+	update := testUpdate(10)
+	got, err := updateFromConsensus(update).ToConsensus()
+	require.NoError(t, err)
+	require.DeepEqual(t, update, got)
+
+	// The signature only holds the signatures of the participants.
+	update.SyncAggregate.SyncCommitteeBits = []byte{0x03, 0x00}
+	update.SyncAggregate.SyncCommitteeSignature = make([]byte, 2*dilithium.SignatureLength())
+	got, err = updateFromConsensus(update).ToConsensus()
+	require.NoError(t, err)
+	require.DeepEqual(t, update, got)
+
+	u := updateFromConsensus(update)
+	u.SyncAggregate = nil
+	_, err = u.ToConsensus()
+	require.ErrorContains(t, "SyncAggregate", err)
+
+	u = updateFromConsensus(update)
+	u.FinalityBranch[0] = "0x01"
+	_, err = u.ToConsensus()
+	require.ErrorContains(t, "FinalityBranch", err)
+}
@@ -0,0 +1,316 @@
+package lightclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/api"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	http2 "github.com/theQRL/qrysm/v4/network/http"
+	"github.com/theQRL/qrysm/v4/proto/migration"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	"go.opencensus.io/trace"
+)
+
+// MaxRequestLightClientUpdates is the maximum number of light client updates served by a single updates request.
+const MaxRequestLightClientUpdates = 128
+
+// GetLightClientBootstrap is an HTTP handler for Beacon API getLightClientBootstrap. It serves the header of the
+// requested block along with the current sync committee of its post-state and the proof of that committee, from
+// which a light client initializes its store.
+func (s *Server) GetLightClientBootstrap(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "lightclient.GetLightClientBootstrap")
+	defer span.End()
+
+	rawRoot := mux.Vars(r)["block_root"]
+	root, err := hexutil.Decode(rawRoot)
+	if err != nil || len(root) != 32 {
+		http2.HandleError(w, fmt.Sprintf("invalid block root: %s", rawRoot), http.StatusBadRequest)
+		return
+	}
+	blk, err := s.BeaconDB.Block(ctx, [32]byte(root))
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get block").Error(), http.StatusInternalServerError)
+		return
+	}
+	if blk == nil || blk.IsNil() {
+		http2.HandleError(w, fmt.Sprintf("block %#x not found", root), http.StatusNotFound)
+		return
+	}
+	if blk.Version() < version.Altair {
+		http2.HandleError(w, "light client bootstrap is not available before the Altair fork", http.StatusBadRequest)
+		return
+	}
+	stateRoot := blk.Block().StateRoot()
+	st, err := s.Stater.State(ctx, stateRoot[:])
+	if err != nil {
+		http2.HandleError(w, errors.Wrapf(err, "could not get state of block %#x", root).Error(), http.StatusNotFound)
+		return
+	}
+	header, err := blk.Header()
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get block header").Error(), http.StatusInternalServerError)
+		return
+	}
+	committee, err := st.CurrentSyncCommittee()
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get current sync committee").Error(), http.StatusInternalServerError)
+		return
+	}
+	branch, err := st.CurrentSyncCommitteeProof(ctx)
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get current sync committee proof").Error(), http.StatusInternalServerError)
+		return
+	}
+	bootstrap := &zondpbv2.LightClientBootstrap{
+		Header: migration.V1Alpha1SignedHeaderToV1(header).GetMessage(),
+		CurrentSyncCommittee: &zondpbv2.SyncCommittee{
+			Pubkeys:         committee.Pubkeys,
+			AggregatePubkey: committee.AggregatePubkey,
+		},
+		CurrentSyncCommitteeBranch: branch,
+	}
+
+	w.Header().Set(api.VersionHeader, version.String(blk.Version()))
+	if http2.SszRequested(r) {
+		sszResp, err := bootstrap.MarshalSSZ()
+		if err != nil {
+			http2.HandleError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http2.WriteSsz(w, sszResp, "light_client_bootstrap.ssz")
+		return
+	}
+	http2.WriteJson(w, &LightClientBootstrapResponse{
+		Version: version.String(blk.Version()),
+		Data: &LightClientBootstrap{
+			Header:                     headerFromConsensus(bootstrap.Header),
+			CurrentSyncCommittee:       syncCommitteeFromConsensus(bootstrap.CurrentSyncCommittee),
+			CurrentSyncCommitteeBranch: branchFromConsensus(bootstrap.CurrentSyncCommitteeBranch),
+		},
+	})
+}
+
+// GetLightClientExecutionHeader is an HTTP handler serving the SSZ encoded execution payload header of the requested
+// block, along with the Merkle branch of the payload against the body root of the block. A light client verifies the
+// execution payload of a verified header with it, without downloading the block.
+func (s *Server) GetLightClientExecutionHeader(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "lightclient.GetLightClientExecutionHeader")
+	defer span.End()
+
+	rawRoot := mux.Vars(r)["block_root"]
+	root, err := hexutil.Decode(rawRoot)
+	if err != nil || len(root) != 32 {
+		http2.HandleError(w, fmt.Sprintf("invalid block root: %s", rawRoot), http.StatusBadRequest)
+		return
+	}
+	blk, err := s.BeaconDB.Block(ctx, [32]byte(root))
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get block").Error(), http.StatusInternalServerError)
+		return
+	}
+	if blk == nil || blk.IsNil() {
+		http2.HandleError(w, fmt.Sprintf("block %#x not found", root), http.StatusNotFound)
+		return
+	}
+	if blk.Version() < version.Bellatrix {
+		http2.HandleError(w, "execution payload is not available before the Bellatrix fork", http.StatusBadRequest)
+		return
+	}
+	body := blk.Block().Body()
+	execution, err := body.Execution()
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get execution payload").Error(), http.StatusInternalServerError)
+		return
+	}
+	header, err := executionHeaderFromConsensus(execution, blk.Version())
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get execution payload header").Error(), http.StatusInternalServerError)
+		return
+	}
+	branch, err := blocks.PayloadProof(body)
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get execution payload proof").Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.VersionHeader, version.String(blk.Version()))
+	http2.WriteJson(w, &LightClientExecutionHeaderResponse{
+		Version: version.String(blk.Version()),
+		Data: &LightClientExecutionHeader{
+			Execution:       hexutil.Encode(header),
+			ExecutionBranch: branchFromConsensus(branch),
+		},
+	})
+}
+
+// GetLightClientUpdatesByRange is an HTTP handler for Beacon API getLightClientUpdatesByRange. It serves the best
+// updates of `count` consecutive sync committee periods starting at `start_period`, stopping at the first period
+// without an update. SSZ responses are a sequence of chunks, each made of the little-endian uint64 length of the
+// rest of the chunk, the fork digest of the update and the update.
+func (s *Server) GetLightClientUpdatesByRange(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "lightclient.GetLightClientUpdatesByRange")
+	defer span.End()
+
+	startPeriod, err := strconv.ParseUint(r.URL.Query().Get("start_period"), 10, 64)
+	if err != nil {
+		http2.HandleError(w, "start_period is required and must be a number", http.StatusBadRequest)
+		return
+	}
+	count, err := strconv.ParseUint(r.URL.Query().Get("count"), 10, 64)
+	if err != nil || count == 0 {
+		http2.HandleError(w, "count is required and must be a positive number", http.StatusBadRequest)
+		return
+	}
+	if count > MaxRequestLightClientUpdates {
+		count = MaxRequestLightClientUpdates
+	}
+	// Clamp the range to the last period, so that the end period does not overflow.
+	if count-1 > math.MaxUint64-startPeriod {
+		count = math.MaxUint64 - startPeriod + 1
+	}
+	endPeriod := startPeriod + count - 1
+	updates, err := s.BeaconDB.LightClientUpdates(ctx, startPeriod, endPeriod)
+	if err != nil {
+		http2.HandleError(w, errors.Wrap(err, "could not get light client updates").Error(), http.StatusInternalServerError)
+		return
+	}
+	ordered := make([]*zondpbv2.LightClientUpdate, 0, len(updates))
+	for period := startPeriod; period <= endPeriod; period++ {
+		update, ok := updates[period]
+		if !ok {
+			break
+		}
+		ordered = append(ordered, update)
+		if period == endPeriod {
+			break
+		}
+	}
+
+	if http2.SszRequested(r) {
+		gvr := s.GenesisFetcher.GenesisValidatorsRoot()
+		var sszResp []byte
+		for _, update := range ordered {
+			digest, err := forks.ForkDigestFromEpoch(slots.ToEpoch(update.AttestedHeader.Slot), gvr[:])
+			if err != nil {
+				http2.HandleError(w, errors.Wrap(err, "could not get fork digest").Error(), http.StatusInternalServerError)
+				return
+			}
+			enc, err := update.MarshalSSZ()
+			if err != nil {
+				http2.HandleError(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sszResp = binary.LittleEndian.AppendUint64(sszResp, uint64(len(digest)+len(enc)))
+			sszResp = append(sszResp, digest[:]...)
+			sszResp = append(sszResp, enc...)
+		}
+		http2.WriteSsz(w, sszResp, "light_client_updates.ssz")
+		return
+	}
+	resp := make([]*LightClientUpdateWithVersion, len(ordered))
+	for i, update := range ordered {
+		resp[i] = &LightClientUpdateWithVersion{
+			Version: versionAtSlot(update.AttestedHeader.Slot),
+			Data:    updateFromConsensus(update),
+		}
+	}
+	http2.WriteJson(w, resp)
+}
+
+// GetLightClientFinalityUpdate is an HTTP handler for Beacon API getLightClientFinalityUpdate. It serves the latest
+// finality update produced by the node.
+func (s *Server) GetLightClientFinalityUpdate(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "lightclient.GetLightClientFinalityUpdate")
+	defer span.End()
+
+	update := s.LightClientUpdateFetcher.LightClientFinalityUpdate()
+	if update == nil {
+		http2.HandleError(w, "no light client finality update is available", http.StatusNotFound)
+		return
+	}
+
+	v := versionAtSlot(update.AttestedHeader.Slot)
+	w.Header().Set(api.VersionHeader, v)
+	if http2.SszRequested(r) {
+		sszResp, err := update.MarshalSSZ()
+		if err != nil {
+			http2.HandleError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http2.WriteSsz(w, sszResp, "light_client_finality_update.ssz")
+		return
+	}
+	http2.WriteJson(w, &LightClientFinalityUpdateResponse{
+		Version: v,
+		Data: &LightClientFinalityUpdate{
+			AttestedHeader:  headerFromConsensus(update.AttestedHeader),
+			FinalizedHeader: headerFromConsensus(update.FinalizedHeader),
+			FinalityBranch:  branchFromConsensus(update.FinalityBranch),
+			SyncAggregate:   syncAggregateFromConsensus(update.SyncAggregate),
+			SignatureSlot:   strconv.FormatUint(uint64(update.SignatureSlot), 10),
+		},
+	})
+}
+
+// GetLightClientOptimisticUpdate is an HTTP handler for Beacon API getLightClientOptimisticUpdate. It serves the
+// latest optimistic update produced by the node.
+func (s *Server) GetLightClientOptimisticUpdate(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "lightclient.GetLightClientOptimisticUpdate")
+	defer span.End()
+
+	update := s.LightClientUpdateFetcher.LightClientOptimisticUpdate()
+	if update == nil {
+		http2.HandleError(w, "no light client optimistic update is available", http.StatusNotFound)
+		return
+	}
+
+	v := versionAtSlot(update.AttestedHeader.Slot)
+	w.Header().Set(api.VersionHeader, v)
+	if http2.SszRequested(r) {
+		sszResp, err := update.MarshalSSZ()
+		if err != nil {
+			http2.HandleError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http2.WriteSsz(w, sszResp, "light_client_optimistic_update.ssz")
+		return
+	}
+	http2.WriteJson(w, &LightClientOptimisticUpdateResponse{
+		Version: v,
+		Data: &LightClientOptimisticUpdate{
+			AttestedHeader: headerFromConsensus(update.AttestedHeader),
+			SyncAggregate:  syncAggregateFromConsensus(update.SyncAggregate),
+			SignatureSlot:  strconv.FormatUint(uint64(update.SignatureSlot), 10),
+		},
+	})
+}
+
+// versionAtSlot returns the name of the fork active at the slot.
+func versionAtSlot(slot primitives.Slot) string {
+	epoch := slots.ToEpoch(slot)
+	cfg := params.BeaconConfig()
+	switch {
+	case epoch >= cfg.DenebForkEpoch:
+		return version.String(version.Deneb)
+	case epoch >= cfg.CapellaForkEpoch:
+		return version.String(version.Capella)
+	case epoch >= cfg.BellatrixForkEpoch:
+		return version.String(version.Bellatrix)
+	case epoch >= cfg.AltairForkEpoch:
+		return version.String(version.Altair)
+	default:
+		return version.String(version.Phase0)
+	}
+}
@@ -0,0 +1,293 @@
+package lightclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/theQRL/go-zond/common/hexutil"
+	mockChain "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
+	testDB "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/container/trie"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	http2 "github.com/theQRL/qrysm/v4/network/http"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+func testUpdate(slot primitives.Slot) *zondpbv2.LightClientUpdate {
+	header := func(s primitives.Slot) *zondpbv1.BeaconBlockHeader {
+		return &zondpbv1.BeaconBlockHeader{
+			Slot:       s,
+			ParentRoot: make([]byte, 32),
+			StateRoot:  make([]byte, 32),
+			BodyRoot:   make([]byte, 32),
+		}
+	}
+	branch := func(n int) [][]byte {
+		b := make([][]byte, n)
+		for i := range b {
+			b[i] = make([]byte, 32)
+		}
+		return b
+	}
+	pubkeys := make([][]byte, fieldparams.SyncCommitteeLength)
+	for i := range pubkeys {
+		pubkeys[i] = make([]byte, dilithium.PublicKeyLength())
+	}
+	return &zondpbv2.LightClientUpdate{
+		AttestedHeader: header(slot),
+		NextSyncCommittee: &zondpbv2.SyncCommittee{
+			Pubkeys:         pubkeys,
+			AggregatePubkey: make([]byte, fieldparams.SyncCommitteeLength*dilithium.PublicKeyLength()),
+		},
+		NextSyncCommitteeBranch: branch(5),
+		FinalizedHeader:         header(0),
+		FinalityBranch:          branch(6),
+		SyncAggregate: &zondpbv1.SyncAggregate{
+			SyncCommitteeBits:      []byte{0xff, 0xff},
+			SyncCommitteeSignature: make([]byte, dilithium.SignatureLength()*fieldparams.SyncCommitteeLength),
+		},
+		SignatureSlot: slot + 1,
+	}
+}
+
+func TestGetLightClientBootstrap(t *testing.T) {
+	db := testDB.SetupDB(t)
+
+	t.Run("invalid root", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/bootstrap/0x1234", nil)
+		request = mux.SetURLVars(request, map[string]string{"block_root": "0x1234"})
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{BeaconDB: db}
+
+		s.GetLightClientBootstrap(writer, request)
+
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+		e := &http2.DefaultErrorJson{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+		assert.Equal(t, "invalid block root: 0x1234", e.Message)
+	})
+	t.Run("unknown block", func(t *testing.T) {
+		root := "0x" + string(bytes.Repeat([]byte("ab"), 32))
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/bootstrap/"+root, nil)
+		request = mux.SetURLVars(request, map[string]string{"block_root": root})
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{BeaconDB: db}
+
+		s.GetLightClientBootstrap(writer, request)
+
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+}
+
+func TestGetLightClientExecutionHeader(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+	s := &Server{BeaconDB: db}
+	request := func(t *testing.T, blk interfaces.ReadOnlySignedBeaconBlock) *httptest.ResponseRecorder {
+		require.NoError(t, db.SaveBlock(ctx, blk))
+		root, err := blk.Block().HashTreeRoot()
+		require.NoError(t, err)
+		rawRoot := hexutil.Encode(root[:])
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/execution_header/"+rawRoot, nil)
+		request = mux.SetURLVars(request, map[string]string{"block_root": rawRoot})
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.GetLightClientExecutionHeader(writer, request)
+		return writer
+	}
+
+	t.Run("capella", func(t *testing.T) {
+		b := util.NewBeaconBlockCapella()
+		b.Block.Body.ExecutionPayload.BlockNumber = 7
+		blk, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		writer := request(t, blk)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		resp := &LightClientExecutionHeaderResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "capella", resp.Version)
+		execution, branch, err := resp.Data.ToConsensus(resp.Version)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), execution.BlockNumber())
+		payloadRoot, err := execution.HashTreeRoot()
+		require.NoError(t, err)
+		bodyRoot, err := blk.Block().Body().HashTreeRoot()
+		require.NoError(t, err)
+		assert.Equal(t, true, trie.VerifyMerkleProof(bodyRoot[:], payloadRoot[:], zondpbv2.ExecutionPayloadIndex, branch))
+	})
+	t.Run("altair", func(t *testing.T) {
+		blk, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlockAltair())
+		require.NoError(t, err)
+		writer := request(t, blk)
+
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+}
+
+func TestGetLightClientUpdatesByRange(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	params.OverrideBeaconConfig(cfg)
+
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+	periodSlots := primitives.Slot(params.BeaconConfig().EpochsPerSyncCommitteePeriod) * params.BeaconConfig().SlotsPerEpoch
+	for _, period := range []uint64{1, 2, 4} {
+		require.NoError(t, db.SaveLightClientUpdate(ctx, period, testUpdate(primitives.Slot(period)*periodSlots)))
+	}
+	s := &Server{
+		BeaconDB:       db,
+		GenesisFetcher: &mockChain.ChainService{},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/updates?start_period=1&count=4", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.GetLightClientUpdatesByRange(writer, request)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		var resp []*LightClientUpdateWithVersion
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &resp))
+		// The updates stop at the first period without an update.
+		require.Equal(t, 2, len(resp))
+		assert.Equal(t, "altair", resp[0].Version)
+		assert.Equal(t, strconv.FormatUint(uint64(periodSlots), 10), resp[0].Data.AttestedHeader.Slot)
+		assert.Equal(t, strconv.FormatUint(uint64(2*periodSlots+1), 10), resp[1].Data.SignatureSlot)
+	})
+	t.Run("ssz", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/updates?start_period=2&count=1", nil)
+		request.Header.Set("Accept", "application/octet-stream")
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.GetLightClientUpdatesByRange(writer, request)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		enc, err := testUpdate(2 * periodSlots).MarshalSSZ()
+		require.NoError(t, err)
+		body := writer.Body.Bytes()
+		require.Equal(t, 8+4+len(enc), len(body))
+		assert.Equal(t, uint64(4+len(enc)), binary.LittleEndian.Uint64(body[:8]))
+		assert.DeepEqual(t, enc, body[12:])
+	})
+	t.Run("last period", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/updates?start_period=18446744073709551615&count=2", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.GetLightClientUpdatesByRange(writer, request)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		var resp []*LightClientUpdateWithVersion
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &resp))
+		assert.Equal(t, 0, len(resp))
+	})
+	t.Run("missing count", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/updates?start_period=1", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.GetLightClientUpdatesByRange(writer, request)
+
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+}
+
+func TestGetLightClientFinalityUpdate(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	params.OverrideBeaconConfig(cfg)
+
+	t.Run("no update", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/finality_update", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{LightClientUpdateFetcher: &mockChain.ChainService{}}
+
+		s.GetLightClientFinalityUpdate(writer, request)
+
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+	t.Run("ok", func(t *testing.T) {
+		u := testUpdate(100)
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/finality_update", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{LightClientUpdateFetcher: &mockChain.ChainService{
+			LCFinalityUpdate: &zondpbv2.LightClientFinalityUpdate{
+				AttestedHeader:  u.AttestedHeader,
+				FinalizedHeader: u.FinalizedHeader,
+				FinalityBranch:  u.FinalityBranch,
+				SyncAggregate:   u.SyncAggregate,
+				SignatureSlot:   u.SignatureSlot,
+			},
+		}}
+
+		s.GetLightClientFinalityUpdate(writer, request)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, "altair", writer.Header().Get("Eth-Consensus-Version"))
+		resp := &LightClientFinalityUpdateResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "100", resp.Data.AttestedHeader.Slot)
+		assert.Equal(t, "101", resp.Data.SignatureSlot)
+		assert.Equal(t, 6, len(resp.Data.FinalityBranch))
+		assert.Equal(t, "0xffff", resp.Data.SyncAggregate.SyncCommitteeBits)
+	})
+}
+
+func TestGetLightClientOptimisticUpdate(t *testing.T) {
+	t.Run("no update", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/optimistic_update", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{LightClientUpdateFetcher: &mockChain.ChainService{}}
+
+		s.GetLightClientOptimisticUpdate(writer, request)
+
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+	t.Run("ok", func(t *testing.T) {
+		u := testUpdate(100)
+		request := httptest.NewRequest("GET", "http://foo.example/zond/v1/beacon/light_client/optimistic_update", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s := &Server{LightClientUpdateFetcher: &mockChain.ChainService{
+			LCOptimisticUpdate: &zondpbv2.LightClientOptimisticUpdate{
+				AttestedHeader: u.AttestedHeader,
+				SyncAggregate:  u.SyncAggregate,
+				SignatureSlot:  u.SignatureSlot,
+			},
+		}}
+
+		s.GetLightClientOptimisticUpdate(writer, request)
+
+		assert.Equal(t, http.StatusOK, writer.Code)
+		resp := &LightClientOptimisticUpdateResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "100", resp.Data.AttestedHeader.Slot)
+	})
+}
@@ -59,6 +59,10 @@ func WriteStateFetchError(w http.ResponseWriter, err error) {
 	if parseErr, ok := err.(*lookup.StateIdParseError); ok {
 		http2.HandleError(w, "Invalid state ID: "+parseErr.Error(), http.StatusBadRequest)
 	}
+	if prunedErr, ok := err.(*lookup.HistoryPrunedError); ok {
+		http2.HandleError(w, "State pruned: "+prunedErr.Error(), http.StatusNotFound)
+		return
+	}
 	http2.HandleError(w, "Could not get state: "+err.Error(), http.StatusInternalServerError)
 }
 
@@ -69,6 +73,10 @@ func WriteBlockFetchError(w http.ResponseWriter, blk interfaces.ReadOnlySignedBe
 		http2.HandleError(w, "Invalid block ID: "+invalidBlockIdErr.Error(), http.StatusBadRequest)
 		return false
 	}
+	if prunedErr, ok := err.(*lookup.HistoryPrunedError); ok {
+		http2.HandleError(w, "Block pruned: "+prunedErr.Error(), http.StatusNotFound)
+		return false
+	}
 	if err != nil {
 		http2.HandleError(w, "Could not get block from block ID: %s"+err.Error(), http.StatusInternalServerError)
 		return false
@@ -108,7 +108,7 @@ func TestGetAggregateAttestation(t *testing.T) {
 		Signature: sig22,
 	}
 	root33 := bytesutil.PadTo([]byte("root3_3"), 32)
-	sig33 := dilithium.NewAggregateSignature().Marshal()
+	sig33 := make([]byte, dilithium.SignatureLength())
 	attslot33 := &zondpbalpha.Attestation{
 		AggregationBits: []byte{1, 0, 0, 1},
 		Data: &zondpbalpha.AttestationData{
@@ -1368,7 +1368,7 @@ func TestGetAttestationData(t *testing.T) {
 
 func TestProduceSyncCommitteeContribution(t *testing.T) {
 	root := bytesutil.PadTo([]byte("0xcf8e0d4e9587369b2301d0790347320302cc0943d5a1884560367e8208d920f2"), 32)
-	sig := dilithium.NewAggregateSignature().Marshal()
+	sig := make([]byte, dilithium.SignatureLength())
 	messsage := &zondpbalpha.SyncCommitteeMessage{
 		Slot:           1,
 		BlockRoot:      root,
@@ -94,6 +94,9 @@ func (p *BeaconDbBlocker) Block(ctx context.Context, id []byte) (interfaces.Read
 				e := NewBlockIdParseError(err)
 				return nil, &e
 			}
+			if err := historyPruned(ctx, p.BeaconDB, primitives.Slot(slot)); err != nil {
+				return nil, err
+			}
 			blks, err := p.BeaconDB.BlocksBySlot(ctx, primitives.Slot(slot))
 			if err != nil {
 				return nil, errors.Wrapf(err, "could not retrieve blocks for slot %d", slot)
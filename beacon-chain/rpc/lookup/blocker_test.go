@@ -6,11 +6,13 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/theQRL/go-zond/common/hexutil"
 	mock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
 	dbtesting "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/testutil"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpbalpha "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
@@ -147,4 +149,18 @@ func TestGetBlock(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("pruned", func(t *testing.T) {
+		_, err := beaconDB.PruneHistory(ctx, 20)
+		require.NoError(t, err)
+		_, err = fetcher.Block(ctx, []byte("19"))
+		var prunedErr *HistoryPrunedError
+		require.Equal(t, true, errors.As(err, &prunedErr))
+		result, err := fetcher.Block(ctx, []byte("20"))
+		require.NoError(t, err)
+		assert.Equal(t, primitives.Slot(20), result.Block().Slot())
+		result, err = fetcher.Block(ctx, []byte("genesis"))
+		require.NoError(t, err)
+		assert.Equal(t, primitives.Slot(0), result.Block().Slot())
+	})
 }
@@ -72,6 +72,40 @@ func (e *StateRootNotFoundError) Error() string {
 	return e.message
 }
 
+// HistoryPrunedError represents an error scenario where the requested slot is older than the history kept by the node.
+type HistoryPrunedError struct {
+	message string
+}
+
+// NewHistoryPrunedError creates a new error instance.
+func NewHistoryPrunedError(slot, prunedSlot primitives.Slot) HistoryPrunedError {
+	return HistoryPrunedError{
+		message: fmt.Sprintf("history at slot %d was pruned, the oldest available slot is %d", slot, prunedSlot),
+	}
+}
+
+// Error returns the underlying error message.
+func (e *HistoryPrunedError) Error() string {
+	return e.message
+}
+
+// historyPruned returns a HistoryPrunedError if the blocks and states at the slot were deleted by history pruning.
+// The genesis block and state are never pruned.
+func historyPruned(ctx context.Context, beaconDB db.ReadOnlyDatabase, slot primitives.Slot) error {
+	if slot == params.BeaconConfig().GenesisSlot {
+		return nil
+	}
+	prunedSlot, err := beaconDB.HistoryPrunedSlot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get history pruned slot")
+	}
+	if slot < prunedSlot {
+		e := NewHistoryPrunedError(slot, prunedSlot)
+		return &e
+	}
+	return nil
+}
+
 // Stater is responsible for retrieving states.
 type Stater interface {
 	State(ctx context.Context, id []byte) (state.BeaconState, error)
@@ -230,6 +264,10 @@ func (p *BeaconDbStater) StateBySlot(ctx context.Context, target primitives.Slot
 	if target > p.GenesisTimeFetcher.CurrentSlot() {
 		return nil, errors.New("requested slot is in the future")
 	}
+	// Replaying over pruned blocks would treat their slots as skipped and return a wrong state.
+	if err := historyPruned(ctx, p.BeaconDB, target); err != nil {
+		return nil, err
+	}
 
 	st, err := p.ReplayerBuilder.ReplayerForSlot(target).ReplayBlocks(ctx)
 	if err != nil {
@@ -317,6 +355,9 @@ func (p *BeaconDbStater) stateRootBySlot(ctx context.Context, slot primitives.Sl
 	if slot > currentSlot {
 		return nil, errors.New("slot cannot be in the future")
 	}
+	if err := historyPruned(ctx, p.BeaconDB, slot); err != nil {
+		return nil, err
+	}
 	blks, err := p.BeaconDB.BlocksBySlot(ctx, slot)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get blocks")
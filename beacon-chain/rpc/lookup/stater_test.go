@@ -11,6 +11,7 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/stategen"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 
+	"github.com/pkg/errors"
 	"github.com/theQRL/go-zond/common/hexutil"
 	chainMock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
 	testDB "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
@@ -189,6 +190,7 @@ func TestGetState(t *testing.T) {
 
 	t.Run("slot", func(t *testing.T) {
 		p := BeaconDbStater{
+			BeaconDB:           testDB.SetupDB(t),
 			GenesisTimeFetcher: &chainMock.ChainService{Slot: &headSlot},
 			ChainInfoFetcher: &chainMock.ChainService{
 				CanonicalRoots: map[[32]byte]bool{
@@ -422,8 +424,33 @@ func TestStateBySlot_AfterHeadSlot(t *testing.T) {
 	mock := &chainMock.ChainService{State: headSt, Slot: &currentSlot}
 	mockReplayer := mockstategen.NewMockReplayerBuilder()
 	mockReplayer.SetMockStateForSlot(slotSt, 101)
-	p := BeaconDbStater{ChainInfoFetcher: mock, GenesisTimeFetcher: mock, ReplayerBuilder: mockReplayer}
+	p := BeaconDbStater{BeaconDB: testDB.SetupDB(t), ChainInfoFetcher: mock, GenesisTimeFetcher: mock, ReplayerBuilder: mockReplayer}
 	st, err := p.StateBySlot(context.Background(), 101)
 	require.NoError(t, err)
 	assert.Equal(t, primitives.Slot(101), st.Slot())
 }
+
+func TestStateBySlot_HistoryPruned(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	_, err := db.PruneHistory(ctx, 64)
+	require.NoError(t, err)
+	slotSt, err := statenative.InitializeFromProtoPhase0(&zondpb.BeaconState{Slot: 64})
+	require.NoError(t, err)
+	currentSlot := primitives.Slot(100)
+	mock := &chainMock.ChainService{Slot: &currentSlot}
+	mockReplayer := mockstategen.NewMockReplayerBuilder()
+	mockReplayer.SetMockStateForSlot(slotSt, 64)
+	p := BeaconDbStater{BeaconDB: db, ChainInfoFetcher: mock, GenesisTimeFetcher: mock, ReplayerBuilder: mockReplayer}
+
+	_, err = p.StateBySlot(ctx, 63)
+	var prunedErr *HistoryPrunedError
+	require.Equal(t, true, errors.As(err, &prunedErr))
+	assert.ErrorContains(t, "history at slot 63 was pruned, the oldest available slot is 64", err)
+	_, err = p.StateRoot(ctx, []byte("63"))
+	require.Equal(t, true, errors.As(err, &prunedErr))
+
+	st, err := p.StateBySlot(ctx, 64)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(64), st.Slot())
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	corenet "github.com/libp2p/go-libp2p/core/network"
@@ -118,6 +119,25 @@ func (s *Server) RemoveTrustedPeer(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// ListPeerBandwidth retrieves the measured bandwidth and latency of the node's connected peers, along
+// with their scores. Peers that have not served a request yet report zero bandwidth and latency.
+func (s *Server) ListPeerBandwidth(w http.ResponseWriter, _ *http.Request) {
+	peerStatus := s.PeersFetcher.Peers()
+	scorer := peerStatus.Scorers()
+	connected := peerStatus.Connected()
+	allPeers := make([]*PeerBandwidth, 0, len(connected))
+	for _, id := range connected {
+		allPeers = append(allPeers, &PeerBandwidth{
+			PeerID:                  id.String(),
+			Score:                   strconv.FormatFloat(scorer.Score(id), 'f', -1, 64),
+			BandwidthScore:          strconv.FormatFloat(scorer.BandwidthScorer().Score(id), 'f', -1, 64),
+			BandwidthBytesPerSecond: strconv.FormatUint(uint64(scorer.BandwidthScorer().Bandwidth(id)), 10),
+			LatencyMilliseconds:     strconv.FormatInt(scorer.BandwidthScorer().Latency(id).Milliseconds(), 10),
+		})
+	}
+	http2.WriteJson(w, &PeerBandwidthResponse{Peers: allPeers})
+}
+
 // httpPeerInfo does the same thing as peerInfo function in node.go but returns the
 // http peer response.
 func httpPeerInfo(peerStatus *peers.Status, id peer.ID) (*Peer, error) {
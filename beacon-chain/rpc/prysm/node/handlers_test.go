@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	corenet "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -157,6 +158,45 @@ func TestListTrustedPeers_NoPeersReturnsEmptyArray(t *testing.T) {
 	assert.Equal(t, 0, len(peers))
 }
 
+func TestListPeerBandwidth(t *testing.T) {
+	ids := libp2ptest.GeneratePeerIDs(2)
+	peerFetcher := &mockp2p.MockPeersProvider{}
+	peerFetcher.ClearPeers()
+	peerStatus := peerFetcher.Peers()
+	for _, id := range ids {
+		peerStatus.Add(nil, id, nil, corenet.DirOutbound)
+		peerStatus.SetConnectionState(id, peers.PeerConnected)
+	}
+	scorer := peerStatus.Scorers().BandwidthScorer()
+	size := 4 * scorer.Params().MinResponseSize
+	scorer.RecordResponse(ids[0], size, 100*time.Millisecond, time.Second)
+	s := Server{PeersFetcher: peerFetcher}
+
+	url := "http://anything.is.fine"
+	request := httptest.NewRequest("GET", url, nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.ListPeerBandwidth(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	resp := &PeerBandwidthResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	require.Equal(t, len(ids), len(resp.Peers))
+	byID := make(map[string]*PeerBandwidth)
+	for _, p := range resp.Peers {
+		byID[p.PeerID] = p
+	}
+	measured := byID[ids[0].String()]
+	require.NotNil(t, measured)
+	assert.Equal(t, strconv.FormatUint(size, 10), measured.BandwidthBytesPerSecond)
+	assert.Equal(t, "100", measured.LatencyMilliseconds)
+	assert.Equal(t, strconv.FormatFloat(scorer.Score(ids[0]), 'f', -1, 64), measured.BandwidthScore)
+	unmeasured := byID[ids[1].String()]
+	require.NotNil(t, unmeasured)
+	assert.Equal(t, "0", unmeasured.BandwidthBytesPerSecond)
+	assert.Equal(t, "0", unmeasured.LatencyMilliseconds)
+	assert.Equal(t, "0", unmeasured.BandwidthScore)
+}
+
 func TestAddTrustedPeer(t *testing.T) {
 	peerFetcher := &mockp2p.MockPeersProvider{}
 	peerFetcher.ClearPeers()
@@ -8,6 +8,18 @@ type PeersResponse struct {
 	Peers []*Peer `json:"Peers"`
 }
 
+type PeerBandwidthResponse struct {
+	Peers []*PeerBandwidth `json:"peers"`
+}
+
+type PeerBandwidth struct {
+	PeerID                  string `json:"peer_id"`
+	Score                   string `json:"score"`
+	BandwidthScore          string `json:"bandwidth_score"`
+	BandwidthBytesPerSecond string `json:"bandwidth_bytes_per_second"`
+	LatencyMilliseconds     string `json:"latency_ms"`
+}
+
 type Peer struct {
 	PeerID             string `json:"peer_id"`
 	Enr                string `json:"enr"`
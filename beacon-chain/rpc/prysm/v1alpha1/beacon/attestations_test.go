@@ -713,8 +713,9 @@ func TestServer_AttestationPool_Pagination_OutOfRange(t *testing.T) {
 				Source:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{1}, 32)},
 				Target:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{1}, 32)},
 			},
-			AggregationBits: bitfield.Bitlist{0b1101},
-			Signature:       bytesutil.PadTo([]byte{1}, dilithium2.CryptoBytes),
+			AggregationBits:         bitfield.Bitlist{0b1101},
+			Signature:               bytesutil.PadTo([]byte{1}, 2*dilithium2.CryptoBytes),
+			SignatureValidatorIndex: []uint64{0, 2},
 		},
 		{
 			Data: &zondpb.AttestationData{
@@ -723,8 +724,9 @@ func TestServer_AttestationPool_Pagination_OutOfRange(t *testing.T) {
 				Source:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{2}, 32)},
 				Target:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{2}, 32)},
 			},
-			AggregationBits: bitfield.Bitlist{0b1101},
-			Signature:       bytesutil.PadTo([]byte{2}, dilithium2.CryptoBytes),
+			AggregationBits:         bitfield.Bitlist{0b1101},
+			Signature:               bytesutil.PadTo([]byte{2}, 2*dilithium2.CryptoBytes),
+			SignatureValidatorIndex: []uint64{0, 2},
 		},
 		{
 			Data: &zondpb.AttestationData{
@@ -733,8 +735,9 @@ func TestServer_AttestationPool_Pagination_OutOfRange(t *testing.T) {
 				Source:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{3}, 32)},
 				Target:          &zondpb.Checkpoint{Root: bytesutil.PadTo([]byte{3}, 32)},
 			},
-			AggregationBits: bitfield.Bitlist{0b1101},
-			Signature:       bytesutil.PadTo([]byte{3}, dilithium2.CryptoBytes),
+			AggregationBits:         bitfield.Bitlist{0b1101},
+			Signature:               bytesutil.PadTo([]byte{3}, 2*dilithium2.CryptoBytes),
+			SignatureValidatorIndex: []uint64{0, 2},
 		},
 	}
 	require.NoError(t, bs.AttestationsPool.SaveAggregatedAttestations(atts))
@@ -758,6 +761,8 @@ func TestServer_AttestationPool_Pagination_DefaultPageSize(t *testing.T) {
 	for i := 0; i < len(atts); i++ {
 		att := util.NewAttestation()
 		att.Data.Slot = primitives.Slot(i)
+		att.Signature = make([]byte, 2*dilithium2.CryptoBytes)
+		att.SignatureValidatorIndex = []uint64{0, 2}
 		atts[i] = att
 	}
 	require.NoError(t, bs.AttestationsPool.SaveAggregatedAttestations(atts))
@@ -780,6 +785,8 @@ func TestServer_AttestationPool_Pagination_CustomPageSize(t *testing.T) {
 	for i := 0; i < len(atts); i++ {
 		att := util.NewAttestation()
 		att.Data.Slot = primitives.Slot(i)
+		att.Signature = make([]byte, 2*dilithium2.CryptoBytes)
+		att.SignatureValidatorIndex = []uint64{0, 2}
 		atts[i] = att
 	}
 	require.NoError(t, bs.AttestationsPool.SaveAggregatedAttestations(atts))
@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/core"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"reflect"
 	"testing"
 	"time"
@@ -19,7 +20,6 @@ import (
 	mockSync "github.com/theQRL/qrysm/v4/beacon-chain/sync/initial-sync/testing"
 	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
 	"github.com/theQRL/qrysm/v4/config/params"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
@@ -60,7 +60,7 @@ func TestSubmitAggregateAndProof_CantFindValidatorIndex(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'A'})
 	req := &zondpb.AggregateSelectionRequest{CommitteeIndex: 1, SlotSignature: sig.Marshal(), PublicKey: pubKey(3)}
@@ -88,7 +88,7 @@ func TestSubmitAggregateAndProof_IsAggregatorAndNoAtts(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'A'})
 	v, err := s.ValidatorAtIndex(1)
@@ -122,7 +122,7 @@ func TestSubmitAggregateAndProof_UnaggregateOk(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'B'})
 	v, err := beaconState.ValidatorAtIndex(1)
@@ -160,7 +160,7 @@ func TestSubmitAggregateAndProof_AggregateOk(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'B'})
 	v, err := beaconState.ValidatorAtIndex(1)
@@ -200,7 +200,7 @@ func TestSubmitAggregateAndProof_AggregateNotOk(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'B'})
 	v, err := beaconState.ValidatorAtIndex(1)
@@ -215,7 +215,7 @@ func TestSubmitAggregateAndProof_AggregateNotOk(t *testing.T) {
 	assert.Equal(t, 0, len(aggregatedAtts), "Wanted aggregated attestation")
 }
 
-func generateAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []bls.SecretKey) (*zondpb.Attestation, error) {
+func generateAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []dilithium.DilithiumKey) (*zondpb.Attestation, error) {
 	aggBits := bitfield.NewBitlist(4)
 	aggBits.SetBitAt(index, true)
 	aggBits.SetBitAt(index+1, true)
@@ -232,7 +232,7 @@ func generateAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []bls.S
 		return nil, err
 	}
 
-	sigs := make([]bls.Signature, len(attestingIndices))
+	sigs := make([]dilithium.Signature, len(attestingIndices))
 	var zeroSig [96]byte
 	att.Signature = zeroSig[:]
 
@@ -241,19 +241,20 @@ func generateAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []bls.S
 		if err != nil {
 			return nil, err
 		}
-		sig, err := bls.SignatureFromBytes(sb)
+		sig, err := dilithium.SignatureFromBytes(sb)
 		if err != nil {
 			return nil, err
 		}
 		sigs[i] = sig
 	}
 
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = dilithium.UnaggregatedSignatures(sigs)
+	att.SignatureValidatorIndex = attestingIndices
 
 	return att, nil
 }
 
-func generateUnaggregatedAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []bls.SecretKey) (*zondpb.Attestation, error) {
+func generateUnaggregatedAtt(state state.ReadOnlyBeaconState, index uint64, privKeys []dilithium.DilithiumKey) (*zondpb.Attestation, error) {
 	aggBits := bitfield.NewBitlist(4)
 	aggBits.SetBitAt(index, true)
 	att := util.HydrateAttestation(&zondpb.Attestation{
@@ -275,7 +276,7 @@ func generateUnaggregatedAtt(state state.ReadOnlyBeaconState, index uint64, priv
 		return nil, err
 	}
 
-	sigs := make([]bls.Signature, len(attestingIndices))
+	sigs := make([]dilithium.Signature, len(attestingIndices))
 	var zeroSig [96]byte
 	att.Signature = zeroSig[:]
 
@@ -288,7 +289,8 @@ func generateUnaggregatedAtt(state state.ReadOnlyBeaconState, index uint64, priv
 		sigs[i] = sig
 	}
 
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = dilithium.UnaggregatedSignatures(sigs)
+	att.SignatureValidatorIndex = attestingIndices
 
 	return att, nil
 }
@@ -329,7 +331,7 @@ func TestSubmitAggregateAndProof_PreferOwnAttestation(t *testing.T) {
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'B'})
 	v, err := beaconState.ValidatorAtIndex(1)
@@ -360,14 +362,12 @@ func TestSubmitAggregateAndProof_SelectsMostBitsWhenOwnAttestationNotPresent(t *
 	// This test creates two distinct attestations, neither of which contain the validator's index,
 	// index 0. This test should choose the most bits attestation, att1.
 	beaconState, privKeys := util.DeterministicGenesisState(t, fieldparams.RootLength)
-	att0, err := generateAtt(beaconState, 0, privKeys)
+	att0, err := generateUnaggregatedAtt(beaconState, 2, privKeys)
 	require.NoError(t, err)
 	att0.Data.BeaconBlockRoot = bytesutil.PadTo([]byte("foo"), fieldparams.RootLength)
-	att0.AggregationBits = bitfield.Bitlist{0b11100}
-	att1, err := generateAtt(beaconState, 2, privKeys)
+	att1, err := generateAtt(beaconState, 1, privKeys)
 	require.NoError(t, err)
 	att1.Data.BeaconBlockRoot = bytesutil.PadTo([]byte("bar"), fieldparams.RootLength)
-	att1.AggregationBits = bitfield.Bitlist{0b11110}
 
 	err = beaconState.SetSlot(beaconState.Slot() + params.BeaconConfig().MinAttestationInclusionDelay)
 	require.NoError(t, err)
@@ -380,7 +380,7 @@ func TestSubmitAggregateAndProof_SelectsMostBitsWhenOwnAttestationNotPresent(t *
 		TimeFetcher: &mock.ChainService{Genesis: time.Now()},
 	}
 
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte{'B'})
 	v, err := beaconState.ValidatorAtIndex(1)
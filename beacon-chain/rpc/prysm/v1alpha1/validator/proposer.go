@@ -266,7 +266,7 @@ func (vs *Server) ProposeBeaconBlock(ctx context.Context, req *zondpb.GenericSig
 			sidecars[i] = sc.Message
 		}
 		if len(scs) > 0 {
-			if err := vs.BeaconDB.SaveBlobSidecar(ctx, sidecars); err != nil {
+			if err := vs.BlobStorage.SaveBlobSidecar(ctx, sidecars); err != nil {
 				return nil, err
 			}
 		}
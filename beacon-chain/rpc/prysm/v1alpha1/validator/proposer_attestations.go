@@ -2,18 +2,21 @@ package validator
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/altair"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/blocks"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/time"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation"
-	attaggregation "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation/attestations"
+	"github.com/theQRL/qrysm/v4/runtime/version"
 	"go.opencensus.io/trace"
 )
 
@@ -39,40 +42,7 @@ func (vs *Server) packAttestations(ctx context.Context, latestState state.Beacon
 	}
 	atts = append(atts, uAtts...)
 
-	// Remove duplicates from both aggregated/unaggregated attestations. This
-	// prevents inefficient aggregates being created.
-	atts, err = proposerAtts(atts).dedup()
-	if err != nil {
-		return nil, err
-	}
-
-	attsByDataRoot := make(map[[32]byte][]*zondpb.Attestation, len(atts))
-	for _, att := range atts {
-		attDataRoot, err := att.Data.HashTreeRoot()
-		if err != nil {
-			return nil, err
-		}
-		attsByDataRoot[attDataRoot] = append(attsByDataRoot[attDataRoot], att)
-	}
-
-	attsForInclusion := proposerAtts(make([]*zondpb.Attestation, 0))
-	for _, as := range attsByDataRoot {
-		as, err := attaggregation.Aggregate(as)
-		if err != nil {
-			return nil, err
-		}
-		attsForInclusion = append(attsForInclusion, as...)
-	}
-	deduped, err := attsForInclusion.dedup()
-	if err != nil {
-		return nil, err
-	}
-	sorted, err := deduped.sortByProfitability()
-	if err != nil {
-		return nil, err
-	}
-	atts = sorted.limitToMaxAttestations()
-	return atts, nil
+	return proposerAtts(atts).pack(ctx, latestState, attestationsMaxBytes(latestState))
 }
 
 // filter separates attestation list into two groups: valid and invalid attestations.
@@ -92,135 +62,349 @@ func (a proposerAtts) filter(ctx context.Context, st state.BeaconState) (propose
 	return validAtts, invalidAtts
 }
 
-// sortByProfitability orders attestations by highest slot and by highest aggregation bit count.
-func (a proposerAtts) sortByProfitability() (proposerAtts, error) {
-	if len(a) < 2 {
-		return a, nil
+const (
+	// attestationsBlockReserve is the part of the gossip size limit left to the execution payload
+	// and the other operations of a block when packing attestations.
+	attestationsBlockReserve = 2 << 20 // 2 MiB
+	// attestationFixedBytes is the size an attestation takes in a block body regardless of its
+	// signers: the attestation data and four offsets, one in the body and one for each of the
+	// variable size fields of the attestation.
+	attestationFixedBytes = 128 + 4*4
+)
+
+// attestationSignerBytes is the size every signer adds to an attestation, its signature and its
+// signature validator index.
+var attestationSignerBytes = uint64(dilithium.SignatureLength()) + 8
+
+// attestationsMaxBytes is the number of bytes the attestations of a block may take. Blocks are
+// gossiped uncompressed and Dilithium signatures do not compress, so the attestations are bound by
+// the gossip size limit rather than by the SSZ limits of the block body.
+func attestationsMaxBytes(st state.ReadOnlyBeaconState) uint64 {
+	maxSize := params.BeaconNetworkConfig().GossipMaxSize
+	if st.Version() >= version.Bellatrix {
+		maxSize = params.BeaconNetworkConfig().GossipMaxSizeBellatrix
 	}
-	return a.sortByProfitabilityUsingMaxCover()
+	if maxSize <= attestationsBlockReserve {
+		return 0
+	}
+	return maxSize - attestationsBlockReserve
 }
 
-// sortByProfitabilityUsingMaxCover orders attestations by highest slot and by highest aggregation bit count.
-// Duplicate bits are counted only once, using max-cover algorithm.
-func (a proposerAtts) sortByProfitabilityUsingMaxCover() (proposerAtts, error) {
-	// Separate attestations by slot, as slot number takes higher precedence when sorting.
-	var slots []primitives.Slot
-	attsBySlot := map[primitives.Slot]proposerAtts{}
-	for _, att := range a {
-		if _, ok := attsBySlot[att.Data.Slot]; !ok {
-			slots = append(slots, att.Data.Slot)
+// pack selects the attestations to include in a block. Dilithium signatures cannot be aggregated,
+// so an attestation carries one signature per signer and overlapping attestations would repeat
+// signatures in the block. Instead, the signers of all attestations with the same data are merged
+// into a single attestation holding each signature once. The merged attestations are then picked
+// greedily by proposer reward per byte, leaving out signers whose participation flags are already
+// set in the state or by an attestation picked before. The result holds at most MAX_ATTESTATIONS
+// attestations taking at most maxBytes bytes of the block body.
+func (a proposerAtts) pack(ctx context.Context, st state.BeaconState, maxBytes uint64) (proposerAtts, error) {
+	ctx, span := trace.StartSpan(ctx, "ProposerServer.packAttestationsByReward")
+	defer span.End()
+
+	p, err := newAttPacker(st)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := p.candidates(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	// A validator is in a single committee per epoch, so picking an attestation only changes the
+	// rewards of the attestations of the same committee.
+	byCommittee := make(map[committeeKey][]*attCandidate)
+	for _, c := range candidates {
+		byCommittee[c.key()] = append(byCommittee[c.key()], c)
+		if err := c.updateRewards(); err != nil {
+			return nil, err
 		}
-		attsBySlot[att.Data.Slot] = append(attsBySlot[att.Data.Slot], att)
 	}
 
-	selectAtts := func(atts proposerAtts) (proposerAtts, error) {
-		if len(atts) < 2 {
-			return atts, nil
+	maxAtts := params.BeaconConfig().MaxAttestations
+	packed := make(proposerAtts, 0, len(candidates))
+	remaining := maxBytes
+	for uint64(len(packed)) < maxAtts && remaining >= attestationFixedBytes+attestationSignerBytes {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		candidates := make([]*bitfield.Bitlist64, len(atts))
-		for i := 0; i < len(atts); i++ {
-			var err error
-			candidates[i], err = atts[i].AggregationBits.ToBitlist64()
-			if err != nil {
-				return nil, err
+		best := -1
+		for i, c := range candidates {
+			if c.reward > 0 && (best < 0 || c.betterThan(candidates[best])) {
+				best = i
 			}
 		}
-		// Add selected candidates on top, those that are not selected - append at bottom.
-		selectedKeys, _, err := aggregation.MaxCover(candidates, len(candidates), true /* allowOverlaps */)
-		if err == nil {
-			// Pick selected attestations first, leftover attestations will be appended at the end.
-			// Both lists will be sorted by number of bits set.
-			selectedAtts := make(proposerAtts, selectedKeys.Count())
-			leftoverAtts := make(proposerAtts, selectedKeys.Not().Count())
-			for i, key := range selectedKeys.BitIndices() {
-				selectedAtts[i] = atts[key]
-			}
-			for i, key := range selectedKeys.Not().BitIndices() {
-				leftoverAtts[i] = atts[key]
+		if best < 0 {
+			break
+		}
+		c := candidates[best]
+		candidates[best] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+
+		att, positions := c.attestation(remaining)
+		if att == nil {
+			continue
+		}
+		packed = append(packed, att)
+		remaining -= c.size(len(positions))
+		if err := c.setParticipation(positions); err != nil {
+			return nil, err
+		}
+		for _, other := range byCommittee[c.key()] {
+			if err := other.updateRewards(); err != nil {
+				return nil, err
 			}
-			sort.Slice(selectedAtts, func(i, j int) bool {
-				return selectedAtts[i].AggregationBits.Count() > selectedAtts[j].AggregationBits.Count()
-			})
-			sort.Slice(leftoverAtts, func(i, j int) bool {
-				return leftoverAtts[i].AggregationBits.Count() > leftoverAtts[j].AggregationBits.Count()
-			})
-			return append(selectedAtts, leftoverAtts...), nil
 		}
-		return atts, nil
-	}
-
-	// Select attestations. Slots are sorted from higher to lower at this point. Within slots attestations
-	// are sorted to maximize profitability (greedily selected, with previous attestations' bits
-	// evaluated before including any new attestation).
-	var sortedAtts proposerAtts
-	sort.Slice(slots, func(i, j int) bool {
-		return slots[i] > slots[j]
-	})
-	for _, slot := range slots {
-		selected, err := selectAtts(attsBySlot[slot])
+	}
+	span.AddAttributes(
+		trace.Int64Attribute("attestations", int64(len(packed))),
+		trace.Int64Attribute("bytes", int64(maxBytes-remaining)),
+	)
+	return packed, nil
+}
+
+// attPacker holds what is needed to compute the proposer reward of the signers of attestations.
+// The participation is a copy of the state's, updated as attestations are packed.
+type attPacker struct {
+	st                    state.BeaconState
+	totalBalance          uint64
+	currentParticipation  []byte
+	previousParticipation []byte
+}
+
+func newAttPacker(st state.BeaconState) (*attPacker, error) {
+	totalBalance, err := helpers.TotalActiveBalance(st)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get total active balance")
+	}
+	p := &attPacker{st: st, totalBalance: totalBalance}
+	if st.Version() == version.Phase0 {
+		// Phase 0 states do not record participation flags, every signer is rewarded.
+		p.currentParticipation = make([]byte, st.NumValidators())
+		p.previousParticipation = make([]byte, st.NumValidators())
+		return p, nil
+	}
+	if p.currentParticipation, err = st.CurrentEpochParticipation(); err != nil {
+		return nil, err
+	}
+	if p.previousParticipation, err = st.PreviousEpochParticipation(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// candidates merges the attestations with the same data. Candidates are returned in the order of
+// the first attestation of their data, so that packing is deterministic.
+func (p *attPacker) candidates(ctx context.Context, atts []*zondpb.Attestation) ([]*attCandidate, error) {
+	candidates := make([]*attCandidate, 0, len(atts))
+	byDataRoot := make(map[[32]byte]*attCandidate, len(atts))
+	for _, att := range atts {
+		root, err := att.Data.HashTreeRoot()
 		if err != nil {
 			return nil, err
 		}
-		sortedAtts = append(sortedAtts, selected...)
+		c, ok := byDataRoot[root]
+		if !ok {
+			c, err = p.newCandidate(ctx, att.Data)
+			if err != nil {
+				return nil, err
+			}
+			byDataRoot[root] = c
+			candidates = append(candidates, c)
+		}
+		if err := c.add(att); err != nil {
+			return nil, err
+		}
 	}
-
-	return sortedAtts, nil
+	return candidates, nil
 }
 
-// limitToMaxAttestations limits attestations to maximum attestations per block.
-func (a proposerAtts) limitToMaxAttestations() proposerAtts {
-	if uint64(len(a)) > params.BeaconConfig().MaxAttestations {
-		return a[:params.BeaconConfig().MaxAttestations]
+func (p *attPacker) newCandidate(ctx context.Context, data *zondpb.AttestationData) (*attCandidate, error) {
+	committee, err := helpers.BeaconCommitteeFromState(ctx, p.st, data.Slot, data.CommitteeIndex)
+	if err != nil {
+		return nil, err
+	}
+	c := &attCandidate{
+		packer:      p,
+		data:        data,
+		committee:   committee,
+		positions:   make(map[primitives.ValidatorIndex]int, len(committee)),
+		sigs:        make([][]byte, len(committee)),
+		baseRewards: make([]uint64, len(committee)),
+		rewards:     make([]uint64, len(committee)),
+	}
+	for i, idx := range committee {
+		c.positions[idx] = i
+	}
+	c.participation = p.previousParticipation
+	if data.Target.Epoch == time.CurrentEpoch(p.st) {
+		c.participation = p.currentParticipation
+	}
+
+	// Attestations which would not set any flag are left without flags, they cannot be included in
+	// the block.
+	flags, err := altair.AttestationParticipationFlagIndices(p.st, data, p.st.Slot()-data.Slot)
+	if err != nil {
+		return c, nil
 	}
-	return a
+	cfg := params.BeaconConfig()
+	for _, f := range []flagWeight{
+		{index: cfg.TimelySourceFlagIndex, weight: cfg.TimelySourceWeight},
+		{index: cfg.TimelyTargetFlagIndex, weight: cfg.TimelyTargetWeight},
+		{index: cfg.TimelyHeadFlagIndex, weight: cfg.TimelyHeadWeight},
+	} {
+		if flags[f.index] {
+			c.flags = append(c.flags, f)
+		}
+	}
+	return c, nil
+}
+
+type flagWeight struct {
+	index  uint8
+	weight uint64
+}
+
+type committeeKey struct {
+	slot  primitives.Slot
+	index primitives.CommitteeIndex
+}
+
+// attCandidate is an attestation merging the signers of all the attestations with the same data,
+// indexed by their position in the committee.
+type attCandidate struct {
+	packer        *attPacker
+	data          *zondpb.AttestationData
+	committee     []primitives.ValidatorIndex
+	positions     map[primitives.ValidatorIndex]int
+	sigs          [][]byte
+	baseRewards   []uint64
+	flags         []flagWeight
+	participation []byte
+	// rewards holds the proposer reward numerator of each signer given the participation packed so
+	// far, and reward their sum.
+	rewards []uint64
+	reward  uint64
+	signers int
 }
 
-// dedup removes duplicate attestations (ones with the same bits set on).
-// Important: not only exact duplicates are removed, but proper subsets are removed too
-// (their known bits are redundant and are already contained in their supersets).
-func (a proposerAtts) dedup() (proposerAtts, error) {
-	if len(a) < 2 {
-		return a, nil
+func (c *attCandidate) key() committeeKey {
+	return committeeKey{slot: c.data.Slot, index: c.data.CommitteeIndex}
+}
+
+// add merges the signers of the attestation, keeping the first signature seen for each signer.
+// Signatures which do not match a set aggregation bit are ignored.
+func (c *attCandidate) add(att *zondpb.Attestation) error {
+	if att.AggregationBits.Len() != uint64(len(c.committee)) ||
+		len(att.Signature) != len(att.SignatureValidatorIndex)*dilithium.SignatureLength() {
+		return nil
 	}
-	attsByDataRoot := make(map[[32]byte][]*zondpb.Attestation, len(a))
-	for _, att := range a {
-		attDataRoot, err := att.Data.HashTreeRoot()
+	for i, idx := range att.SignatureValidatorIndex {
+		pos, ok := c.positions[primitives.ValidatorIndex(idx)]
+		if !ok || c.sigs[pos] != nil || !att.AggregationBits.BitAt(uint64(pos)) {
+			continue
+		}
+		br, err := altair.BaseRewardWithTotalBalance(c.packer.st, primitives.ValidatorIndex(idx), c.packer.totalBalance)
 		if err != nil {
+			return err
+		}
+		c.sigs[pos] = att.Signature[i*dilithium.SignatureLength() : (i+1)*dilithium.SignatureLength()]
+		c.baseRewards[pos] = br
+	}
+	return nil
+}
+
+// updateRewards computes the reward of every signer from the flags it would set.
+func (c *attCandidate) updateRewards() error {
+	c.reward, c.signers = 0, 0
+	for pos, sig := range c.sigs {
+		c.rewards[pos] = 0
+		if sig == nil {
 			continue
 		}
-		attsByDataRoot[attDataRoot] = append(attsByDataRoot[attDataRoot], att)
-	}
-
-	uniqAtts := make([]*zondpb.Attestation, 0, len(a))
-	for _, atts := range attsByDataRoot {
-		for i := 0; i < len(atts); i++ {
-			a := atts[i]
-			for j := i + 1; j < len(atts); j++ {
-				b := atts[j]
-				if c, err := a.AggregationBits.Contains(b.AggregationBits); err != nil {
-					return nil, err
-				} else if c {
-					// a contains b, b is redundant.
-					atts[j] = atts[len(atts)-1]
-					atts[len(atts)-1] = nil
-					atts = atts[:len(atts)-1]
-					j--
-				} else if c, err := b.AggregationBits.Contains(a.AggregationBits); err != nil {
-					return nil, err
-				} else if c {
-					// b contains a, a is redundant.
-					atts[i] = atts[len(atts)-1]
-					atts[len(atts)-1] = nil
-					atts = atts[:len(atts)-1]
-					i--
-					break
-				}
+		idx := c.committee[pos]
+		if uint64(idx) >= uint64(len(c.participation)) {
+			return fmt.Errorf("index %d exceeds participation length %d", idx, len(c.participation))
+		}
+		for _, f := range c.flags {
+			has, err := altair.HasValidatorFlag(c.participation[idx], f.index)
+			if err != nil {
+				return err
+			}
+			if !has {
+				c.rewards[pos] += c.baseRewards[pos] * f.weight
+			}
+		}
+		if c.rewards[pos] > 0 {
+			c.reward += c.rewards[pos]
+			c.signers++
+		}
+	}
+	return nil
+}
+
+// setParticipation sets the flags of the signers at the positions in the packed participation.
+func (c *attCandidate) setParticipation(positions []int) error {
+	for _, pos := range positions {
+		idx := c.committee[pos]
+		for _, f := range c.flags {
+			flag, err := altair.AddValidatorFlag(c.participation[idx], f.index)
+			if err != nil {
+				return err
 			}
+			c.participation[idx] = flag
 		}
-		uniqAtts = append(uniqAtts, atts...)
 	}
+	return nil
+}
+
+// size is the number of bytes the attestation takes in a block body with the given number of
+// signers.
+func (c *attCandidate) size(signers int) uint64 {
+	return attestationFixedBytes + uint64(len(c.committee))/8 + 1 + uint64(signers)*attestationSignerBytes
+}
+
+// betterThan orders candidates by reward per byte, then by slot to prefer recent attestations.
+func (c *attCandidate) betterThan(other *attCandidate) bool {
+	r := float64(c.reward) / float64(c.size(c.signers))
+	or := float64(other.reward) / float64(other.size(other.signers))
+	if r != or {
+		return r > or
+	}
+	return c.data.Slot > other.data.Slot
+}
 
-	return uniqAtts, nil
+// attestation returns the attestation of the signers which reward the proposer, with their
+// committee positions. If it would take more than maxBytes, only the signers with the highest
+// rewards which fit are kept. A nil attestation is returned when no signer fits.
+func (c *attCandidate) attestation(maxBytes uint64) (*zondpb.Attestation, []int) {
+	positions := make([]int, 0, c.signers)
+	for pos, r := range c.rewards {
+		if r > 0 {
+			positions = append(positions, pos)
+		}
+	}
+	if c.size(len(positions)) > maxBytes {
+		if c.size(1) > maxBytes {
+			return nil, nil
+		}
+		sort.SliceStable(positions, func(i, j int) bool {
+			return c.rewards[positions[i]] > c.rewards[positions[j]]
+		})
+		positions = positions[:(maxBytes-c.size(0))/attestationSignerBytes]
+		sort.Ints(positions)
+	}
+
+	att := &zondpb.Attestation{
+		AggregationBits:         bitfield.NewBitlist(uint64(len(c.committee))),
+		Data:                    zondpb.CopyAttestationData(c.data),
+		Signature:               make([]byte, 0, len(positions)*dilithium.SignatureLength()),
+		SignatureValidatorIndex: make([]uint64, 0, len(positions)),
+	}
+	for _, pos := range positions {
+		att.AggregationBits.SetBitAt(uint64(pos), true)
+		att.Signature = append(att.Signature, c.sigs[pos]...)
+		att.SignatureValidatorIndex = append(att.SignatureValidatorIndex, uint64(c.committee[pos]))
+	}
+	return att, positions
 }
 
 // This filters the input attestations to return a list of valid attestations to be packaged inside a beacon block.
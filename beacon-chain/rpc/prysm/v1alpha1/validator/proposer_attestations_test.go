@@ -1,425 +1,255 @@
 package validator
 
 import (
-	"bytes"
-	"sort"
+	"context"
 	"testing"
 
 	"github.com/theQRL/go-bitfield"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
 )
 
-func TestProposer_ProposerAtts_sortByProfitability(t *testing.T) {
-	atts := proposerAtts([]*zondpb.Attestation{
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 4}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11000000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 4}, AggregationBits: bitfield.Bitlist{0b11110000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b11000000}}),
-	})
-	want := proposerAtts([]*zondpb.Attestation{
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 4}, AggregationBits: bitfield.Bitlist{0b11110000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 4}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 3}, AggregationBits: bitfield.Bitlist{0b11000000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11100000}}),
-		util.HydrateAttestation(&zondpb.Attestation{Data: &zondpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b11000000}}),
+// packingTestState returns a state at the slot with active validators holding the maximum
+// effective balance, and a distinct block root for every slot.
+func packingTestState(t testing.TB, numValidators uint64, slot primitives.Slot) state.BeaconState {
+	// The committees of all packing test states share a seed, so committees cached by other tests
+	// must not be used.
+	helpers.ClearCache()
+	cfg := params.BeaconConfig()
+	pubKey := make([]byte, dilithium2.CryptoPublicKeyBytes)
+	st, err := util.NewBeaconStateCapella(func(s *zondpb.BeaconStateCapella) error {
+		s.Slot = slot
+		s.Validators = make([]*zondpb.Validator, numValidators)
+		s.Balances = make([]uint64, numValidators)
+		for i := range s.Validators {
+			s.Validators[i] = &zondpb.Validator{
+				PublicKey:                  pubKey,
+				WithdrawalCredentials:      make([]byte, 32),
+				EffectiveBalance:           cfg.MaxEffectiveBalance,
+				ActivationEligibilityEpoch: 0,
+				ActivationEpoch:            0,
+				ExitEpoch:                  cfg.FarFutureEpoch,
+				WithdrawableEpoch:          cfg.FarFutureEpoch,
+			}
+			s.Balances[i] = cfg.MaxEffectiveBalance
+		}
+		s.CurrentEpochParticipation = make([]byte, numValidators)
+		s.PreviousEpochParticipation = make([]byte, numValidators)
+		for i := range s.BlockRoots {
+			s.BlockRoots[i] = bytesutil.ToBytes(uint64(i)+1, 32)
+		}
+		return nil
 	})
-	atts, err := atts.sortByProfitability()
-	if err != nil {
-		t.Error(err)
+	require.NoError(t, err)
+	return st
+}
+
+// packingTestData returns the data of an attestation of the state's chain, voting for the head
+// when head is set and for an unknown block otherwise.
+func packingTestData(t testing.TB, st state.BeaconState, slot primitives.Slot, committeeIndex primitives.CommitteeIndex, head bool) *zondpb.AttestationData {
+	epoch := slots.ToEpoch(slot)
+	source := st.PreviousJustifiedCheckpoint()
+	if epoch == slots.ToEpoch(st.Slot()) {
+		source = st.CurrentJustifiedCheckpoint()
+	}
+	targetRoot, err := helpers.BlockRoot(st, epoch)
+	require.NoError(t, err)
+	blockRoot := bytesutil.PadTo([]byte("unknown block"), 32)
+	if head {
+		blockRoot, err = helpers.BlockRootAtSlot(st, slot)
+		require.NoError(t, err)
 	}
-	require.DeepEqual(t, want, atts)
+	return &zondpb.AttestationData{
+		Slot:            slot,
+		CommitteeIndex:  committeeIndex,
+		BeaconBlockRoot: blockRoot,
+		Source:          source,
+		Target:          &zondpb.Checkpoint{Epoch: epoch, Root: targetRoot},
+	}
+}
+
+// packingTestSig returns the signature used for a validator in packing tests.
+func packingTestSig(idx primitives.ValidatorIndex) []byte {
+	return bytesutil.PadTo(bytesutil.ToBytes(uint64(idx)+1, 8), dilithium.SignatureLength())
 }
 
-func TestProposer_ProposerAtts_sortByProfitabilityUsingMaxCover(t *testing.T) {
-	type testData struct {
-		slot primitives.Slot
-		bits bitfield.Bitlist
+// packingTestAtt returns an attestation signed by the committee members at the positions.
+func packingTestAtt(t testing.TB, st state.BeaconState, data *zondpb.AttestationData, positions ...int) *zondpb.Attestation {
+	committee, err := helpers.BeaconCommitteeFromState(context.Background(), st, data.Slot, data.CommitteeIndex)
+	require.NoError(t, err)
+	att := &zondpb.Attestation{
+		AggregationBits: bitfield.NewBitlist(uint64(len(committee))),
+		Data:            data,
 	}
-	getAtts := func(data []testData) proposerAtts {
-		var atts proposerAtts
-		for _, att := range data {
-			atts = append(atts, util.HydrateAttestation(&zondpb.Attestation{
-				Data: &zondpb.AttestationData{Slot: att.slot}, AggregationBits: att.bits}))
-		}
-		return atts
+	for _, pos := range positions {
+		att.AggregationBits.SetBitAt(uint64(pos), true)
+		att.Signature = append(att.Signature, packingTestSig(committee[pos])...)
+		att.SignatureValidatorIndex = append(att.SignatureValidatorIndex, uint64(committee[pos]))
 	}
+	return att
+}
 
-	t.Run("no atts", func(t *testing.T) {
-		atts := getAtts([]testData{})
-		want := getAtts([]testData{})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+// signerPositions returns the committee positions of the signers of the attestation, checking that
+// its signatures match its aggregation bits.
+func signerPositions(t *testing.T, st state.BeaconState, att *zondpb.Attestation) []int {
+	committee, err := helpers.BeaconCommitteeFromState(context.Background(), st, att.Data.Slot, att.Data.CommitteeIndex)
+	require.NoError(t, err)
+	positions := att.AggregationBits.BitIndices()
+	require.Equal(t, len(positions), len(att.SignatureValidatorIndex))
+	require.Equal(t, len(positions)*dilithium.SignatureLength(), len(att.Signature))
+	for i, pos := range positions {
+		assert.Equal(t, uint64(committee[pos]), att.SignatureValidatorIndex[i])
+		sig := att.Signature[i*dilithium.SignatureLength() : (i+1)*dilithium.SignatureLength()]
+		assert.DeepEqual(t, packingTestSig(committee[pos]), sig)
+	}
+	return positions
+}
 
-	t.Run("single att", func(t *testing.T) {
-		atts := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-		})
-		want := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-		})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+func TestProposer_ProposerAtts_pack_MergesSigners(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	data := packingTestData(t, st, 129, 0, true)
 
-	t.Run("single att per slot", func(t *testing.T) {
-		atts := getAtts([]testData{
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-		})
-		want := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+	// A signature which is not backed by an aggregation bit is dropped.
+	inconsistent := packingTestAtt(t, st, data, 7)
+	inconsistent.AggregationBits.SetBitAt(7, false)
+	inconsistent.AggregationBits.SetBitAt(6, true)
 
-	t.Run("two atts on one of the slots", func(t *testing.T) {
-		atts := getAtts([]testData{
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-			{4, bitfield.Bitlist{0b11110000, 0b1}},
-		})
-		want := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11110000, 0b1}},
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+	atts := proposerAtts{
+		packingTestAtt(t, st, data, 0, 1, 2),
+		packingTestAtt(t, st, data, 2, 3),
+		packingTestAtt(t, st, data, 5),
+		packingTestAtt(t, st, data, 1),
+		inconsistent,
+	}
+	packed, err := atts.pack(context.Background(), st, attestationsMaxBytes(st))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(packed))
+	assert.DeepEqual(t, data, packed[0].Data)
+	assert.DeepEqual(t, []int{0, 1, 2, 3, 5}, signerPositions(t, st, packed[0]))
+}
 
-	t.Run("compare to native sort", func(t *testing.T) {
-		// The max-cover based approach will select 0b00001100 instead, despite lower bit count
-		// (since it has two new/unknown bits).
-		t.Run("max-cover", func(t *testing.T) {
-			atts := getAtts([]testData{
-				{1, bitfield.Bitlist{0b11000011, 0b1}},
-				{1, bitfield.Bitlist{0b11001000, 0b1}},
-				{1, bitfield.Bitlist{0b00001100, 0b1}},
-			})
-			want := getAtts([]testData{
-				{1, bitfield.Bitlist{0b11000011, 0b1}},
-				{1, bitfield.Bitlist{0b00001100, 0b1}},
-				{1, bitfield.Bitlist{0b11001000, 0b1}},
-			})
-			atts, err := atts.sortByProfitability()
-			if err != nil {
-				t.Error(err)
-			}
-			require.DeepEqual(t, want, atts)
-		})
-	})
+func TestProposer_ProposerAtts_pack_SkipsRecordedParticipation(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	data := packingTestData(t, st, 129, 0, true)
+	committee, err := helpers.BeaconCommitteeFromState(context.Background(), st, data.Slot, data.CommitteeIndex)
+	require.NoError(t, err)
 
-	t.Run("multiple slots", func(t *testing.T) {
-		atts := getAtts([]testData{
-			{2, bitfield.Bitlist{0b11100000, 0b1}},
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-			{4, bitfield.Bitlist{0b11110000, 0b1}},
-			{1, bitfield.Bitlist{0b11100000, 0b1}},
-			{3, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		want := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11110000, 0b1}},
-			{4, bitfield.Bitlist{0b11100000, 0b1}},
-			{3, bitfield.Bitlist{0b11000000, 0b1}},
-			{2, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+	cfg := params.BeaconConfig()
+	participation, err := st.CurrentEpochParticipation()
+	require.NoError(t, err)
+	// The first signer has all its flags set, the second only its source flag.
+	participation[committee[0]] = 1<<cfg.TimelySourceFlagIndex | 1<<cfg.TimelyTargetFlagIndex | 1<<cfg.TimelyHeadFlagIndex
+	participation[committee[1]] = 1 << cfg.TimelySourceFlagIndex
+	participation[committee[3]] = participation[committee[0]]
+	require.NoError(t, st.SetCurrentParticipationBits(participation))
 
-	t.Run("selected and non selected atts sorted by bit count", func(t *testing.T) {
-		// Items at slot 4, must be first split into two lists by max-cover, with
-		// 0b10000011 scoring higher (as it provides more info in addition to already selected
-		// attestations) than 0b11100001 (despite naive bit count suggesting otherwise). Then,
-		// both selected and non-selected attestations must be additionally sorted by bit count.
-		atts := getAtts([]testData{
-			{4, bitfield.Bitlist{0b00000001, 0b1}},
-			{4, bitfield.Bitlist{0b11100001, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-			{2, bitfield.Bitlist{0b11100000, 0b1}},
-			{4, bitfield.Bitlist{0b10000011, 0b1}},
-			{4, bitfield.Bitlist{0b11111000, 0b1}},
-			{1, bitfield.Bitlist{0b11100000, 0b1}},
-			{3, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		want := getAtts([]testData{
-			{4, bitfield.Bitlist{0b11111000, 0b1}},
-			{4, bitfield.Bitlist{0b10000011, 0b1}},
-			{4, bitfield.Bitlist{0b11100001, 0b1}},
-			{4, bitfield.Bitlist{0b00000001, 0b1}},
-			{3, bitfield.Bitlist{0b11000000, 0b1}},
-			{2, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11100000, 0b1}},
-			{1, bitfield.Bitlist{0b11000000, 0b1}},
-		})
-		atts, err := atts.sortByProfitability()
-		if err != nil {
-			t.Error(err)
-		}
-		require.DeepEqual(t, want, atts)
-	})
+	atts := proposerAtts{
+		packingTestAtt(t, st, data, 0, 1, 2),
+		// All the signers of this attestation are recorded.
+		packingTestAtt(t, st, packingTestData(t, st, 129, 0, false), 0, 3),
+	}
+	packed, err := atts.pack(context.Background(), st, attestationsMaxBytes(st))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(packed))
+	assert.DeepEqual(t, []int{1, 2}, signerPositions(t, st, packed[0]))
+
+	// The packer does not modify the state.
+	got, err := st.CurrentEpochParticipation()
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), got[committee[2]])
 }
 
-func TestProposer_ProposerAtts_dedup(t *testing.T) {
-	data1 := util.HydrateAttestationData(&zondpb.AttestationData{
-		Slot: 4,
-	})
-	data2 := util.HydrateAttestationData(&zondpb.AttestationData{
-		Slot: 5,
-	})
-	tests := []struct {
-		name string
-		atts proposerAtts
-		want proposerAtts
-	}{
-		{
-			name: "nil list",
-			atts: nil,
-			want: proposerAtts(nil),
-		},
-		{
-			name: "empty list",
-			atts: proposerAtts{},
-			want: proposerAtts{},
-		},
-		{
-			name: "single item",
-			atts: proposerAtts{
-				&zondpb.Attestation{AggregationBits: bitfield.Bitlist{}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{AggregationBits: bitfield.Bitlist{}},
-			},
-		},
-		{
-			name: "two items no duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10111110, 0x01}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01111111, 0x01}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01111111, 0x01}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10111110, 0x01}},
-			},
-		},
-		{
-			name: "two items with duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0xba, 0x01}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0xba, 0x01}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0xba, 0x01}},
-			},
-		},
-		{
-			name: "sorted no duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00101011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100000, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00010000, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00101011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100000, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00010000, 0b1}},
-			},
-		},
-		{
-			name: "sorted with duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-			},
-		},
-		{
-			name: "all equal",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-			},
-		},
-		{
-			name: "unsorted no duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00100010, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00010000, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00100010, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00010000, 0b1}},
-			},
-		},
-		{
-			name: "unsorted with duplicates",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10100101, 0b1}},
-			},
-		},
-		{
-			name: "no proper subset (same root)",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00011001, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00011001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b10000001, 0b1}},
-			},
-		},
-		{
-			name: "proper subset (same root)",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-			},
-		},
-		{
-			name: "no proper subset (different root)",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000101, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b10000001, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00011001, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00011001, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b10000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000101, 0b1}},
-			},
-		},
-		{
-			name: "proper subset (different root 1)",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00000001, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b01101101, 0b1}},
-			},
-		},
-		{
-			name: "proper subset (different root 2)",
-			atts: proposerAtts{
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}},
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-			},
-			want: proposerAtts{
-				&zondpb.Attestation{Data: data2, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-				&zondpb.Attestation{Data: data1, AggregationBits: bitfield.Bitlist{0b11001111, 0b1}},
-			},
-		},
+func TestProposer_ProposerAtts_pack_ConflictingData(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	head := packingTestData(t, st, 129, 0, true)
+	other := packingTestData(t, st, 129, 0, false)
+
+	// Signers voting for both heads are only packed once, with the more rewarding data.
+	atts := proposerAtts{
+		packingTestAtt(t, st, other, 0, 1, 2, 3),
+		packingTestAtt(t, st, head, 2, 3),
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			atts, err := tt.atts.dedup()
-			if err != nil {
-				t.Error(err)
-			}
-			sort.Slice(atts, func(i, j int) bool {
-				if atts[i].AggregationBits.Count() == atts[j].AggregationBits.Count() {
-					if atts[i].Data.Slot == atts[j].Data.Slot {
-						return bytes.Compare(atts[i].AggregationBits, atts[j].AggregationBits) <= 0
-					}
-					return atts[i].Data.Slot > atts[j].Data.Slot
-				}
-				return atts[i].AggregationBits.Count() > atts[j].AggregationBits.Count()
-			})
-			assert.DeepEqual(t, tt.want, atts)
-		})
+	packed, err := atts.pack(context.Background(), st, attestationsMaxBytes(st))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(packed))
+	assert.DeepEqual(t, head, packed[0].Data)
+	assert.DeepEqual(t, []int{2, 3}, signerPositions(t, st, packed[0]))
+	assert.DeepEqual(t, other, packed[1].Data)
+	assert.DeepEqual(t, []int{0, 1}, signerPositions(t, st, packed[1]))
+}
+
+func TestProposer_ProposerAtts_pack_ByteLimit(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	head := packingTestData(t, st, 129, 0, true)
+	late := packingTestData(t, st, 128, 0, true)
+
+	// The attestation of the previous slot sets the head flag and is packed first, the other one
+	// is cut down to the signers which fit.
+	atts := proposerAtts{
+		packingTestAtt(t, st, late, 0, 1, 2, 3),
+		packingTestAtt(t, st, head, 0, 1, 2, 3),
 	}
+	committee, err := helpers.BeaconCommitteeFromState(context.Background(), st, head.Slot, head.CommitteeIndex)
+	require.NoError(t, err)
+	bitlistBytes := uint64(len(bitfield.NewBitlist(uint64(len(committee)))))
+	maxBytes := 2*(attestationFixedBytes+bitlistBytes) + 6*attestationSignerBytes
+	packed, err := atts.pack(context.Background(), st, maxBytes+attestationSignerBytes-1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(packed))
+	assert.DeepEqual(t, head, packed[0].Data)
+	assert.DeepEqual(t, []int{0, 1, 2, 3}, signerPositions(t, st, packed[0]))
+	assert.DeepEqual(t, late, packed[1].Data)
+	assert.Equal(t, 2, len(signerPositions(t, st, packed[1])))
+
+	// The size accounting matches the encoded size of the attestations in the block body.
+	size := 0
+	for _, att := range packed {
+		size += att.SizeSSZ() + 4
+	}
+	assert.Equal(t, maxBytes, uint64(size))
+
+	packed, err = atts.pack(context.Background(), st, attestationFixedBytes+bitlistBytes)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(packed))
+}
+
+func TestProposer_ProposerAtts_pack_MaxAttestations(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	var atts proposerAtts
+	for _, slot := range []primitives.Slot{128, 129} {
+		for pos := 0; pos < 80; pos++ {
+			data := packingTestData(t, st, slot, 0, false)
+			data.BeaconBlockRoot = bytesutil.ToBytes(uint64(pos)+1000, 32)
+			atts = append(atts, packingTestAtt(t, st, data, pos))
+		}
+	}
+	packed, err := atts.pack(context.Background(), st, attestationsMaxBytes(st))
+	require.NoError(t, err)
+	assert.Equal(t, params.BeaconConfig().MaxAttestations, uint64(len(packed)))
+}
+
+func TestProposer_ProposerAtts_pack_Empty(t *testing.T) {
+	st := packingTestState(t, 16384, 130)
+	packed, err := proposerAtts(nil).pack(context.Background(), st, attestationsMaxBytes(st))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(packed))
+}
+
+func TestProposer_attestationsMaxBytes(t *testing.T) {
+	st := packingTestState(t, 64, 0)
+	assert.Equal(t, params.BeaconNetworkConfig().GossipMaxSizeBellatrix-attestationsBlockReserve, attestationsMaxBytes(st))
+
+	phase0, err := util.NewBeaconState()
+	require.NoError(t, err)
+	assert.Equal(t, params.BeaconNetworkConfig().GossipMaxSize-attestationsBlockReserve, attestationsMaxBytes(phase0))
 }
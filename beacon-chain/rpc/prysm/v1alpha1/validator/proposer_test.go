@@ -43,6 +43,7 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/container/trie"
 	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/encoding/ssz"
 	enginev1 "github.com/theQRL/qrysm/v4/proto/engine/v1"
@@ -612,7 +613,7 @@ func getProposerServer(db db.HeadAccessDatabase, headState state.BeaconState, he
 	}
 }
 
-func injectSlashings(t *testing.T, st state.BeaconState, keys []bls.SecretKey, server *Server) ([]*zondpb.ProposerSlashing, []*zondpb.AttesterSlashing) {
+func injectSlashings(t *testing.T, st state.BeaconState, keys []dilithium.DilithiumKey, server *Server) ([]*zondpb.ProposerSlashing, []*zondpb.AttesterSlashing) {
 	proposerSlashings := make([]*zondpb.ProposerSlashing, params.BeaconConfig().MaxProposerSlashings)
 	for i := primitives.ValidatorIndex(0); uint64(i) < params.BeaconConfig().MaxProposerSlashings; i++ {
 		proposerSlashing, err := util.GenerateProposerSlashingForValidator(st, keys[i], i /* validator index */)
@@ -716,10 +717,10 @@ func TestProposer_ProposeBlock_OK(t *testing.T) {
 				blk := &zondpb.GenericSignedBeaconBlock_Deneb{Deneb: &zondpb.SignedBeaconBlockAndBlobsDeneb{
 					Block: blockToPropose,
 					Blobs: []*zondpb.SignedBlobSidecar{
-						{Message: &zondpb.BlobSidecar{Index: 0, Slot: 5, BlockParentRoot: parent[:]}},
-						{Message: &zondpb.BlobSidecar{Index: 1, Slot: 5, BlockParentRoot: parent[:]}},
-						{Message: &zondpb.BlobSidecar{Index: 2, Slot: 5, BlockParentRoot: parent[:]}},
-						{Message: &zondpb.BlobSidecar{Index: 3, Slot: 5, BlockParentRoot: parent[:]}},
+						{Message: testBlobSidecar(0, 5, parent[:])},
+						{Message: testBlobSidecar(1, 5, parent[:])},
+						{Message: testBlobSidecar(2, 5, parent[:])},
+						{Message: testBlobSidecar(3, 5, parent[:])},
 					},
 				}}
 				return &zondpb.GenericSignedBeaconBlock{Block: blk}
@@ -792,12 +793,14 @@ func TestProposer_ProposeBlock_OK(t *testing.T) {
 
 			c := &mock.ChainService{Root: bsRoot[:], State: beaconState}
 			db := dbutil.SetupDB(t)
+			bs := dbutil.SetupBlobStorage(t)
 			proposerServer := &Server{
 				BlockReceiver: c,
 				BlockNotifier: c.BlockNotifier(),
 				P2P:           mockp2p.NewTestP2P(t),
 				BlockBuilder:  &builderTest.MockBuilderService{HasConfigured: true, PayloadCapella: emptyPayloadCapella(), PayloadDeneb: emptyPayloadDeneb(), BlobBundle: &enginev1.BlobsBundle{KzgCommitments: [][]byte{{0x01}}, Proofs: [][]byte{{0x02}}, Blobs: [][]byte{{0x03}}}},
 				BeaconDB:      db,
+				BlobStorage:   bs,
 			}
 			blockToPropose := tt.block(bsRoot)
 			res, err := proposerServer.ProposeBeaconBlock(context.Background(), blockToPropose)
@@ -810,7 +813,7 @@ func TestProposer_ProposeBlock_OK(t *testing.T) {
 				}
 			}
 			if tt.name == "deneb block has blobs" {
-				scs, err := db.BlobSidecarsBySlot(ctx, blockToPropose.GetDeneb().Block.Block.Slot)
+				scs, err := bs.BlobSidecarsBySlot(ctx, blockToPropose.GetDeneb().Block.Block.Slot)
 				require.NoError(t, err)
 				assert.Equal(t, 4, len(scs))
 				for i, sc := range scs {
@@ -821,6 +824,19 @@ func TestProposer_ProposeBlock_OK(t *testing.T) {
 	}
 }
 
+// testBlobSidecar returns a blob sidecar with fields of valid lengths, so that it can be stored.
+func testBlobSidecar(index uint64, slot primitives.Slot, parentRoot []byte) *zondpb.BlobSidecar {
+	return &zondpb.BlobSidecar{
+		BlockRoot:       make([]byte, fieldparams.RootLength),
+		Index:           index,
+		Slot:            slot,
+		BlockParentRoot: parentRoot,
+		Blob:            make([]byte, fieldparams.BlobLength),
+		KzgCommitment:   make([]byte, 48),
+		KzgProof:        make([]byte, 48),
+	}
+}
+
 func TestProposer_ComputeStateRoot_OK(t *testing.T) {
 	db := dbutil.SetupDB(t)
 	ctx := context.Background()
@@ -2448,7 +2464,7 @@ func TestProposer_FilterAttestation(t *testing.T) {
 					assert.NoError(t, err)
 					domain, err := signing.Domain(st.Fork(), 0, params.BeaconConfig().DomainBeaconAttester, params.BeaconConfig().ZeroHash[:])
 					require.NoError(t, err)
-					sigs := make([]bls.Signature, len(attestingIndices))
+					sigs := make([]dilithium.Signature, len(attestingIndices))
 					var zeroSig [96]byte
 					atts[i].Signature = zeroSig[:]
 
@@ -2458,7 +2474,7 @@ func TestProposer_FilterAttestation(t *testing.T) {
 						sig := privKeys[indice].Sign(hashTreeRoot[:])
 						sigs[i] = sig
 					}
-					atts[i].Signature = bls.AggregateSignatures(sigs).Marshal()
+					atts[i].Signature = dilithium.UnaggregatedSignatures(sigs)
 				}
 				return atts
 			},
@@ -2590,7 +2606,7 @@ func TestProposer_DeleteAttsInPool_Aggregated(t *testing.T) {
 	s := &Server{
 		AttPool: attestations.NewPool(),
 	}
-	priv, err := bls.RandKey()
+	priv, err := dilithium.RandKey()
 	require.NoError(t, err)
 	sig := priv.Sign([]byte("foo")).Marshal()
 	aggregatedAtts := []*zondpb.Attestation{
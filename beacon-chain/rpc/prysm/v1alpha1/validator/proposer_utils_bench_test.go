@@ -1,66 +1,164 @@
 package validator
 
 import (
-	"fmt"
+	"context"
+	"math/rand"
 	"testing"
 
 	"github.com/theQRL/go-bitfield"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
-	aggtesting "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation/testing"
 	"github.com/theQRL/qrysm/v4/testing/require"
 )
 
-func BenchmarkProposerAtts_sortByProfitability(b *testing.B) {
-	bitlistLen := params.BeaconConfig().MaxValidatorsPerCommittee
+// benchmarkPool describes the attestation pool of a proposer on mainnet.
+type benchmarkPool struct {
+	// slots is the number of slots before the proposal with attestations in the pool.
+	slots primitives.Slot
+	// participation is the share of every committee attesting.
+	participation float64
+	// aggregators is the number of aggregates of every attestation data, each holding a random
+	// share aggregateShare of its signers.
+	aggregators    int
+	aggregateShare float64
+	// headSplit is the share of attesters voting for another head.
+	headSplit float64
+	// included is the share of the attesters of all but the last slot whose participation is
+	// already recorded in the state.
+	included float64
+}
+
+// generate fills the state's participation and returns the pool. All the attestations share the
+// same signature bytes, the packer does not verify them.
+func (p benchmarkPool) generate(b *testing.B, st state.BeaconState) proposerAtts {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	sigs := make([]byte, params.BeaconConfig().MaxValidatorsPerCommittee*dilithium2.CryptoBytes)
+	rng.Read(sigs)
+
+	cfg := params.BeaconConfig()
+	allFlags := byte(1<<cfg.TimelySourceFlagIndex | 1<<cfg.TimelyTargetFlagIndex | 1<<cfg.TimelyHeadFlagIndex)
+	current, err := st.CurrentEpochParticipation()
+	require.NoError(b, err)
+	previous, err := st.PreviousEpochParticipation()
+	require.NoError(b, err)
+
+	newAtt := func(data *zondpb.AttestationData, committee []primitives.ValidatorIndex, positions []int) *zondpb.Attestation {
+		att := &zondpb.Attestation{
+			AggregationBits:         bitfield.NewBitlist(uint64(len(committee))),
+			Data:                    data,
+			Signature:               sigs[:len(positions)*dilithium2.CryptoBytes],
+			SignatureValidatorIndex: make([]uint64, len(positions)),
+		}
+		for i, pos := range positions {
+			att.AggregationBits.SetBitAt(uint64(pos), true)
+			att.SignatureValidatorIndex[i] = uint64(committee[pos])
+		}
+		return att
+	}
+
+	var atts proposerAtts
+	for slot := st.Slot() - p.slots; slot < st.Slot(); slot++ {
+		count := helpers.SlotCommitteeCount(uint64(st.NumValidators()))
+		for ci := primitives.CommitteeIndex(0); uint64(ci) < count; ci++ {
+			committee, err := helpers.BeaconCommitteeFromState(ctx, st, slot, ci)
+			require.NoError(b, err)
+			signers := make(map[bool][]int)
+			for pos, idx := range committee {
+				if rng.Float64() >= p.participation {
+					continue
+				}
+				head := rng.Float64() >= p.headSplit
+				signers[head] = append(signers[head], pos)
+				if slot < st.Slot()-1 && rng.Float64() < p.included {
+					if slot.Div(uint64(cfg.SlotsPerEpoch)) == st.Slot().Div(uint64(cfg.SlotsPerEpoch)) {
+						current[idx] = allFlags
+					} else {
+						previous[idx] = allFlags
+					}
+				}
+			}
+			for _, head := range []bool{true, false} {
+				positions := signers[head]
+				if len(positions) == 0 {
+					continue
+				}
+				data := packingTestData(b, st, slot, ci, head)
+				for _, pos := range positions {
+					atts = append(atts, newAtt(data, committee, []int{pos}))
+				}
+				for i := 0; i < p.aggregators; i++ {
+					var agg []int
+					for _, pos := range positions {
+						if rng.Float64() < p.aggregateShare {
+							agg = append(agg, pos)
+						}
+					}
+					if len(agg) > 0 {
+						atts = append(atts, newAtt(data, committee, agg))
+					}
+				}
+			}
+		}
+	}
+	require.NoError(b, st.SetCurrentParticipationBits(current))
+	require.NoError(b, st.SetPreviousParticipationBits(previous))
+	return atts
+}
 
+func BenchmarkProposerAtts_pack(b *testing.B) {
+	// 16 committees of 128 validators per slot.
+	numValidators := 16 * params.BeaconConfig().TargetCommitteeSize * uint64(params.BeaconConfig().SlotsPerEpoch)
 	tests := []struct {
-		name   string
-		inputs []bitfield.Bitlist
+		name string
+		pool benchmarkPool
 	}{
 		{
-			name:   "256 attestations with single bit set",
-			inputs: aggtesting.BitlistsWithSingleBitSet(256, bitlistLen),
+			name: "last slot",
+			pool: benchmarkPool{slots: 1, participation: 0.95, aggregators: 16, aggregateShare: 0.9},
 		},
 		{
-			name:   "256 attestations with 64 random bits set",
-			inputs: aggtesting.BitlistsWithSingleBitSet(256, bitlistLen),
+			name: "last slot with head split",
+			pool: benchmarkPool{slots: 1, participation: 0.95, aggregators: 16, aggregateShare: 0.9, headSplit: 0.1},
 		},
 		{
-			name:   "512 attestations with single bit set",
-			inputs: aggtesting.BitlistsWithSingleBitSet(512, bitlistLen),
+			name: "32 slots mostly included",
+			pool: benchmarkPool{slots: 32, participation: 0.95, aggregators: 16, aggregateShare: 0.9, headSplit: 0.05, included: 0.9},
 		},
 		{
-			name:   "1024 attestations with 64 random bits set",
-			inputs: aggtesting.BitlistsWithMultipleBitSet(b, 1024, bitlistLen, 64),
+			name: "32 slots missed proposals",
+			pool: benchmarkPool{slots: 32, participation: 0.95, aggregators: 16, aggregateShare: 0.9, headSplit: 0.05, included: 0.5},
 		},
-		{
-			name:   "1024 attestations with 512 random bits set",
-			inputs: aggtesting.BitlistsWithMultipleBitSet(b, 1024, bitlistLen, 512),
-		},
-		{
-			name:   "1024 attestations with 1000 random bits set",
-			inputs: aggtesting.BitlistsWithMultipleBitSet(b, 1024, bitlistLen, 1000),
-		},
-	}
-
-	runner := func(atts []*zondpb.Attestation) {
-		attsCopy := make(proposerAtts, len(atts))
-		for i, att := range atts {
-			attsCopy[i] = zondpb.CopyAttestation(att)
-		}
-		_, err := attsCopy.sortByProfitability()
-		require.NoError(b, err, "Could not sort attestations by profitability")
 	}
 
 	for _, tt := range tests {
-		b.Run(fmt.Sprintf("max-cover_%s", tt.name), func(b *testing.B) {
-			b.StopTimer()
-			atts := aggtesting.MakeAttestationsFromBitlists(tt.inputs)
-			b.StartTimer()
+		b.Run(tt.name, func(b *testing.B) {
+			st := packingTestState(b, numValidators, 2*params.BeaconConfig().SlotsPerEpoch+40)
+			atts := tt.pool.generate(b, st)
+			maxBytes := attestationsMaxBytes(st)
+			b.ResetTimer()
+
+			var packed proposerAtts
 			for i := 0; i < b.N; i++ {
-				runner(atts)
+				var err error
+				packed, err = atts.pack(context.Background(), st, maxBytes)
+				require.NoError(b, err)
+			}
+
+			b.StopTimer()
+			size, signers := 0, uint64(0)
+			for _, att := range packed {
+				size += att.SizeSSZ()
+				signers += att.AggregationBits.Count()
 			}
+			b.ReportMetric(float64(len(atts)), "pool")
+			b.ReportMetric(float64(len(packed)), "atts")
+			b.ReportMetric(float64(signers), "signers")
+			b.ReportMetric(float64(size)/float64(1<<20), "MiB")
 		})
 	}
 }
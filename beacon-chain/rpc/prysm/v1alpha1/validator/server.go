@@ -71,6 +71,7 @@ type Server struct {
 	StateGen               stategen.StateManager
 	ReplayerBuilder        stategen.ReplayerBuilder
 	BeaconDB               db.HeadAccessDatabase
+	BlobStorage            db.BlobStorage
 	ExecutionEngineCaller  execution.EngineCaller
 	BlockBuilder           builder.BlockBuilder
 	DilithiumChangesPool   blstoexec.PoolManager
@@ -18,7 +18,7 @@ import (
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/container/trie"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
@@ -1265,7 +1265,7 @@ func TestServer_CheckDoppelGanger(t *testing.T) {
 }
 
 func createStateSetupPhase0(t *testing.T, head primitives.Epoch) (state.BeaconState,
-	state.BeaconState, []bls.SecretKey) {
+	state.BeaconState, []dilithium.DilithiumKey) {
 	gs, keys := util.DeterministicGenesisState(t, 64)
 	hs := gs.Copy()
 
@@ -1282,7 +1282,7 @@ func createStateSetupPhase0(t *testing.T, head primitives.Epoch) (state.BeaconSt
 }
 
 func createStateSetupAltair(t *testing.T, head primitives.Epoch) (state.BeaconState,
-	state.BeaconState, []bls.SecretKey) {
+	state.BeaconState, []dilithium.DilithiumKey) {
 	gs, keys := util.DeterministicGenesisStateAltair(t, 64)
 	hs := gs.Copy()
 
@@ -37,6 +37,7 @@ import (
 	rpcBuilder "github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/builder"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/debug"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/events"
+	lightclient "github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/light-client"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/node"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/rewards"
 	"github.com/theQRL/qrysm/v4/beacon-chain/rpc/eth/validator"
@@ -89,6 +90,7 @@ type Config struct {
 	BeaconMonitoringHost          string
 	BeaconMonitoringPort          int
 	BeaconDB                      db.HeadAccessDatabase
+	BlobStorage                   db.BlobStorage
 	ChainInfoFetcher              blockchain.ChainInfoFetcher
 	HeadFetcher                   blockchain.HeadFetcher
 	CanonicalFetcher              blockchain.CanonicalFetcher
@@ -102,6 +104,7 @@ type Config struct {
 	ExecutionChainInfoFetcher     execution.ChainInfoFetcher
 	GenesisTimeFetcher            blockchain.TimeFetcher
 	GenesisFetcher                blockchain.GenesisFetcher
+	LightClientUpdateFetcher      blockchain.LightClientUpdateFetcher
 	EnableDebugRPCEndpoints       bool
 	MockEth1Votes                 bool
 	AttestationsPool              attestations.Pool
@@ -195,11 +198,13 @@ func (s *Service) Start() {
 	grpcprometheus.EnableHandlingTimeHistogram()
 
 	var stateCache stategen.CachedGetter
+	historyOpts := make([]stategen.CanonicalHistoryOption, 0, 2)
 	if s.cfg.StateGen != nil {
 		stateCache = s.cfg.StateGen.CombinedCache()
+		historyOpts = append(historyOpts, stategen.WithStateDiffs(s.cfg.StateGen))
 	}
-	withCache := stategen.WithCache(stateCache)
-	ch := stategen.NewCanonicalHistory(s.cfg.BeaconDB, s.cfg.ChainInfoFetcher, s.cfg.ChainInfoFetcher, withCache)
+	historyOpts = append(historyOpts, stategen.WithCache(stateCache))
+	ch := stategen.NewCanonicalHistory(s.cfg.BeaconDB, s.cfg.ChainInfoFetcher, s.cfg.ChainInfoFetcher, historyOpts...)
 	stater := &lookup.BeaconDbStater{
 		BeaconDB:           s.cfg.BeaconDB,
 		ChainInfoFetcher:   s.cfg.ChainInfoFetcher,
@@ -235,10 +240,24 @@ func (s *Service) Start() {
 
 	blobServer := &blob.Server{
 		ChainInfoFetcher: s.cfg.ChainInfoFetcher,
-		BeaconDB:         s.cfg.BeaconDB,
+		BlobStorage:      s.cfg.BlobStorage,
 	}
 	s.cfg.Router.HandleFunc("/zond/v1/beacon/blob_sidecars/{block_id}", blobServer.Blobs).Methods(http.MethodGet)
 
+	if features.Get().EnableLightClient {
+		lightClientServer := &lightclient.Server{
+			BeaconDB:                 s.cfg.BeaconDB,
+			Stater:                   stater,
+			GenesisFetcher:           s.cfg.GenesisFetcher,
+			LightClientUpdateFetcher: s.cfg.LightClientUpdateFetcher,
+		}
+		s.cfg.Router.HandleFunc("/zond/v1/beacon/light_client/bootstrap/{block_root}", lightClientServer.GetLightClientBootstrap).Methods(http.MethodGet)
+		s.cfg.Router.HandleFunc("/zond/v1/beacon/light_client/execution_header/{block_root}", lightClientServer.GetLightClientExecutionHeader).Methods(http.MethodGet)
+		s.cfg.Router.HandleFunc("/zond/v1/beacon/light_client/updates", lightClientServer.GetLightClientUpdatesByRange).Methods(http.MethodGet)
+		s.cfg.Router.HandleFunc("/zond/v1/beacon/light_client/finality_update", lightClientServer.GetLightClientFinalityUpdate).Methods(http.MethodGet)
+		s.cfg.Router.HandleFunc("/zond/v1/beacon/light_client/optimistic_update", lightClientServer.GetLightClientOptimisticUpdate).Methods(http.MethodGet)
+	}
+
 	coreService := &core.Service{
 		HeadFetcher:        s.cfg.HeadFetcher,
 		GenesisTimeFetcher: s.cfg.GenesisTimeFetcher,
@@ -281,6 +300,7 @@ func (s *Service) Start() {
 		ReplayerBuilder:        ch,
 		ExecutionEngineCaller:  s.cfg.ExecutionEngineCaller,
 		BeaconDB:               s.cfg.BeaconDB,
+		BlobStorage:            s.cfg.BlobStorage,
 		ProposerSlotIndexCache: s.cfg.ProposerIdsCache,
 		BlockBuilder:           s.cfg.BlockBuilder,
 		DilithiumChangesPool:   s.cfg.DilithiumChangesPool,
@@ -366,6 +386,7 @@ func (s *Service) Start() {
 	s.cfg.Router.HandleFunc("/qrysm/node/trusted_peers", nodeServerPrysm.ListTrustedPeer).Methods(http.MethodGet)
 	s.cfg.Router.HandleFunc("/qrysm/node/trusted_peers", nodeServerPrysm.AddTrustedPeer).Methods(http.MethodPost)
 	s.cfg.Router.HandleFunc("/qrysm/node/trusted_peers/{peer_id}", nodeServerPrysm.RemoveTrustedPeer).Methods(http.MethodDelete)
+	s.cfg.Router.HandleFunc("/qrysm/node/peer_bandwidth", nodeServerPrysm.ListPeerBandwidth).Methods(http.MethodGet)
 
 	beaconChainServer := &beaconv1alpha1.Server{
 		Ctx:                         s.ctx,
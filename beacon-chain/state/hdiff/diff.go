@@ -0,0 +1,543 @@
+// Package hdiff implements the hierarchical state diffs used to store historical beacon states.
+//
+// A stored state is either a snapshot, the full SSZ encoding of the state, or a diff against a
+// base state stored at a lower slot. A diff stores the large, mostly unchanged fields of the state
+// element-wise: the validators, balances, participation flags, inactivity scores, block roots,
+// state roots and randao mixes. The remaining fields of the state are small and are stored in full.
+// Both kinds of entries are snappy compressed.
+package hdiff
+
+import (
+	"encoding/binary"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/fastssz"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	statenative "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrInvalidEncoding is returned when an encoded state snapshot or diff can not be decoded.
+var ErrInvalidEncoding = errors.New("invalid state diff encoding")
+
+const (
+	kindSnapshot byte = iota
+	kindDiff
+)
+
+const rootLength = 32
+
+// Fields of the beacon state protobuf messages that are diffed element-wise.
+const (
+	validatorsField                 protoreflect.Name = "validators"
+	balancesField                   protoreflect.Name = "balances"
+	blockRootsField                 protoreflect.Name = "block_roots"
+	stateRootsField                 protoreflect.Name = "state_roots"
+	randaoMixesField                protoreflect.Name = "randao_mixes"
+	previousEpochParticipationField protoreflect.Name = "previous_epoch_participation"
+	currentEpochParticipationField  protoreflect.Name = "current_epoch_participation"
+	inactivityScoresField           protoreflect.Name = "inactivity_scores"
+)
+
+var diffedFields = map[protoreflect.Name]bool{
+	validatorsField:                 true,
+	balancesField:                   true,
+	blockRootsField:                 true,
+	stateRootsField:                 true,
+	randaoMixesField:                true,
+	previousEpochParticipationField: true,
+	currentEpochParticipationField:  true,
+	inactivityScoresField:           true,
+}
+
+// Snapshot encodes the full state.
+func Snapshot(st state.BeaconState) ([]byte, error) {
+	m, err := stateProto(st)
+	if err != nil {
+		return nil, err
+	}
+	marshaler, ok := m.Interface().(ssz.Marshaler)
+	if !ok {
+		return nil, errors.Errorf("state of version %s can not be SSZ encoded", version.String(st.Version()))
+	}
+	enc, err := marshaler.MarshalSSZ()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal state")
+	}
+	b := make([]byte, 0, len(enc)+2)
+	b = append(b, kindSnapshot, byte(st.Version()))
+	return snappy.Encode(nil, append(b, enc...)), nil
+}
+
+// Diff encodes the target state as a diff against the base state, which is stored at the base slot.
+func Diff(baseSlot primitives.Slot, base, target state.BeaconState) ([]byte, error) {
+	bm, err := stateProto(base)
+	if err != nil {
+		return nil, err
+	}
+	tm, err := stateProto(target)
+	if err != nil {
+		return nil, err
+	}
+	header, err := marshalHeader(tm)
+	if err != nil {
+		return nil, err
+	}
+
+	b := []byte{kindDiff}
+	b = binary.LittleEndian.AppendUint64(b, uint64(baseSlot))
+	b = append(b, byte(target.Version()))
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(header)))
+	b = append(b, header...)
+
+	bVals, tVals := validators(bm), validators(tm)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(tVals)))
+	var changed []int
+	for i, v := range tVals {
+		if i >= len(bVals) || !validatorEqual(bVals[i], v) {
+			changed = append(changed, i)
+		}
+	}
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(changed)))
+	for _, i := range changed {
+		b = binary.LittleEndian.AppendUint64(b, uint64(i))
+		b, err = tVals[i].MarshalSSZTo(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not marshal validator %d", i)
+		}
+	}
+
+	for _, name := range []protoreflect.Name{blockRootsField, stateRootsField, randaoMixesField} {
+		b, err = appendRootsDiff(b, bytesList(bm, name), bytesList(tm, name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not diff %s", name)
+		}
+	}
+	for _, name := range []protoreflect.Name{balancesField, inactivityScoresField} {
+		b = appendUint64sDiff(b, uint64List(bm, name), uint64List(tm, name))
+	}
+	for _, name := range []protoreflect.Name{previousEpochParticipationField, currentEpochParticipationField} {
+		b = appendBytesDiff(b, bytesField(bm, name), bytesField(tm, name))
+	}
+	return snappy.Encode(nil, b), nil
+}
+
+// Entry is a decoded state snapshot or diff.
+type Entry struct {
+	kind     byte
+	baseSlot primitives.Slot
+	payload  []byte
+}
+
+// Decode decodes an encoded state snapshot or diff.
+func Decode(enc []byte) (*Entry, error) {
+	payload, err := snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not snappy decode state diff")
+	}
+	r := &reader{b: payload}
+	e := &Entry{kind: r.byte()}
+	switch e.kind {
+	case kindSnapshot:
+	case kindDiff:
+		e.baseSlot = primitives.Slot(r.uint64())
+	default:
+		return nil, errors.Wrapf(ErrInvalidEncoding, "unknown entry kind %d", e.kind)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	e.payload = r.b
+	return e, nil
+}
+
+// IsSnapshot returns true if the entry is a full state.
+func (e *Entry) IsSnapshot() bool {
+	return e.kind == kindSnapshot
+}
+
+// BaseSlot returns the slot of the state that the diff was computed against.
+func (e *Entry) BaseSlot() primitives.Slot {
+	return e.baseSlot
+}
+
+// State returns the state of the entry. The base state is ignored for snapshots, and for diffs it must be
+// the state stored at the base slot of the entry.
+func (e *Entry) State(base state.BeaconState) (state.BeaconState, error) {
+	r := &reader{b: e.payload}
+	m, err := emptyStateProto(int(r.byte()))
+	if err != nil {
+		return nil, err
+	}
+	if e.IsSnapshot() {
+		unmarshaler, ok := m.(ssz.Unmarshaler)
+		if !ok || r.err != nil {
+			return nil, errors.Wrap(ErrInvalidEncoding, "could not decode snapshot")
+		}
+		if err := unmarshaler.UnmarshalSSZ(r.b); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal state")
+		}
+		return initializeState(m)
+	}
+	if base == nil || base.IsNil() {
+		return nil, errors.New("nil base state")
+	}
+	bm, err := stateProto(base)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(r.bytes(r.uint64()), m); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal state header")
+	}
+	tm := m.ProtoReflect()
+
+	bVals := validators(bm)
+	size := uint64((&zondpb.Validator{}).SizeSSZ())
+	vals := make([]*zondpb.Validator, r.length(len(bVals), 8+size))
+	for i := 0; i < len(vals) && i < len(bVals); i++ {
+		vals[i] = zondpb.CopyValidator(bVals[i])
+	}
+	for n := r.uint64(); n > 0 && r.err == nil; n-- {
+		i := r.uint64()
+		v := &zondpb.Validator{}
+		if err := v.UnmarshalSSZ(r.bytes(size)); err != nil && r.err == nil {
+			return nil, errors.Wrapf(err, "could not unmarshal validator %d", i)
+		}
+		if i >= uint64(len(vals)) {
+			return nil, errors.Wrapf(ErrInvalidEncoding, "validator index %d out of range", i)
+		}
+		vals[i] = v
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	for i, v := range vals {
+		if v == nil {
+			return nil, errors.Wrapf(ErrInvalidEncoding, "missing validator %d", i)
+		}
+	}
+	setList(tm, validatorsField, len(vals), func(i int) protoreflect.Value {
+		return protoreflect.ValueOfMessage(vals[i].ProtoReflect())
+	})
+
+	for _, name := range []protoreflect.Name{blockRootsField, stateRootsField, randaoMixesField} {
+		roots, err := r.rootsDiff(bytesList(bm, name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode %s", name)
+		}
+		setList(tm, name, len(roots), func(i int) protoreflect.Value {
+			return protoreflect.ValueOfBytes(roots[i])
+		})
+	}
+	for _, name := range []protoreflect.Name{balancesField, inactivityScoresField} {
+		u := r.uint64sDiff(uint64List(bm, name))
+		setList(tm, name, len(u), func(i int) protoreflect.Value {
+			return protoreflect.ValueOfUint64(u[i])
+		})
+	}
+	for _, name := range []protoreflect.Name{previousEpochParticipationField, currentEpochParticipationField} {
+		b := r.bytesDiff(bytesField(bm, name))
+		if fd := tm.Descriptor().Fields().ByName(name); fd != nil {
+			tm.Set(fd, protoreflect.ValueOfBytes(b))
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.b) != 0 {
+		return nil, errors.Wrapf(ErrInvalidEncoding, "%d trailing bytes", len(r.b))
+	}
+	return initializeState(m)
+}
+
+func stateProto(st state.BeaconState) (protoreflect.Message, error) {
+	if st == nil || st.IsNil() {
+		return nil, errors.New("nil state")
+	}
+	m, ok := st.ToProtoUnsafe().(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("state of version %s has no protobuf representation", version.String(st.Version()))
+	}
+	return m.ProtoReflect(), nil
+}
+
+func emptyStateProto(v int) (proto.Message, error) {
+	switch v {
+	case version.Phase0:
+		return &zondpb.BeaconState{}, nil
+	case version.Altair:
+		return &zondpb.BeaconStateAltair{}, nil
+	case version.Bellatrix:
+		return &zondpb.BeaconStateBellatrix{}, nil
+	case version.Capella:
+		return &zondpb.BeaconStateCapella{}, nil
+	case version.Deneb:
+		return &zondpb.BeaconStateDeneb{}, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidEncoding, "unknown state version %d", v)
+	}
+}
+
+func initializeState(m proto.Message) (state.BeaconState, error) {
+	switch m := m.(type) {
+	case *zondpb.BeaconState:
+		return statenative.InitializeFromProtoUnsafePhase0(m)
+	case *zondpb.BeaconStateAltair:
+		return statenative.InitializeFromProtoUnsafeAltair(m)
+	case *zondpb.BeaconStateBellatrix:
+		return statenative.InitializeFromProtoUnsafeBellatrix(m)
+	case *zondpb.BeaconStateCapella:
+		return statenative.InitializeFromProtoUnsafeCapella(m)
+	case *zondpb.BeaconStateDeneb:
+		return statenative.InitializeFromProtoUnsafeDeneb(m)
+	default:
+		return nil, errors.Errorf("unknown state type %T", m)
+	}
+}
+
+// marshalHeader encodes all the fields of the state that are not diffed element-wise.
+func marshalHeader(m protoreflect.Message) ([]byte, error) {
+	header := m.New()
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !diffedFields[fd.Name()] {
+			header.Set(fd, v)
+		}
+		return true
+	})
+	b, err := proto.Marshal(header.Interface())
+	return b, errors.Wrap(err, "could not marshal state header")
+}
+
+func list(m protoreflect.Message, name protoreflect.Name) protoreflect.List {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || !fd.IsList() {
+		return nil
+	}
+	return m.Get(fd).List()
+}
+
+func setList(m protoreflect.Message, name protoreflect.Name, n int, value func(int) protoreflect.Value) {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || !fd.IsList() || n == 0 {
+		return
+	}
+	l := m.Mutable(fd).List()
+	for i := 0; i < n; i++ {
+		l.Append(value(i))
+	}
+}
+
+func validators(m protoreflect.Message) []*zondpb.Validator {
+	l := list(m, validatorsField)
+	if l == nil {
+		return nil
+	}
+	vals := make([]*zondpb.Validator, l.Len())
+	for i := range vals {
+		vals[i], _ = l.Get(i).Message().Interface().(*zondpb.Validator)
+	}
+	return vals
+}
+
+func bytesList(m protoreflect.Message, name protoreflect.Name) [][]byte {
+	l := list(m, name)
+	if l == nil {
+		return nil
+	}
+	b := make([][]byte, l.Len())
+	for i := range b {
+		b[i] = l.Get(i).Bytes()
+	}
+	return b
+}
+
+func uint64List(m protoreflect.Message, name protoreflect.Name) []uint64 {
+	l := list(m, name)
+	if l == nil {
+		return nil
+	}
+	u := make([]uint64, l.Len())
+	for i := range u {
+		u[i] = l.Get(i).Uint()
+	}
+	return u
+}
+
+func bytesField(m protoreflect.Message, name protoreflect.Name) []byte {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.IsList() {
+		return nil
+	}
+	return m.Get(fd).Bytes()
+}
+
+func validatorEqual(a, b *zondpb.Validator) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.EffectiveBalance == b.EffectiveBalance &&
+		a.Slashed == b.Slashed &&
+		a.ActivationEligibilityEpoch == b.ActivationEligibilityEpoch &&
+		a.ActivationEpoch == b.ActivationEpoch &&
+		a.ExitEpoch == b.ExitEpoch &&
+		a.WithdrawableEpoch == b.WithdrawableEpoch &&
+		string(a.WithdrawalCredentials) == string(b.WithdrawalCredentials) &&
+		string(a.PublicKey) == string(b.PublicKey)
+}
+
+// appendRootsDiff encodes the roots that differ from the base roots with their index.
+func appendRootsDiff(b []byte, base, target [][]byte) ([]byte, error) {
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(target)))
+	var changed []int
+	for i, r := range target {
+		if len(r) != rootLength {
+			return nil, errors.Errorf("root %d has length %d", i, len(r))
+		}
+		if i >= len(base) || string(base[i]) != string(r) {
+			changed = append(changed, i)
+		}
+	}
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(changed)))
+	for _, i := range changed {
+		b = binary.LittleEndian.AppendUint64(b, uint64(i))
+		b = append(b, target[i]...)
+	}
+	return b, nil
+}
+
+// appendUint64sDiff encodes the difference of every value to the base value as a varint.
+// Balances and inactivity scores change for most validators in every epoch, but by small amounts.
+func appendUint64sDiff(b []byte, base, target []uint64) []byte {
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(target)))
+	for i, v := range target {
+		var bv uint64
+		if i < len(base) {
+			bv = base[i]
+		}
+		b = binary.AppendVarint(b, int64(v-bv))
+	}
+	return b
+}
+
+// appendBytesDiff encodes every byte XORed with the base byte, which leaves runs of zeros for
+// snappy to compress.
+func appendBytesDiff(b []byte, base, target []byte) []byte {
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(target)))
+	for i, v := range target {
+		if i < len(base) {
+			v ^= base[i]
+		}
+		b = append(b, v)
+	}
+	return b
+}
+
+// reader consumes an encoded diff, recording the first decoding error.
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.b)) < n {
+		r.err = errors.Wrapf(ErrInvalidEncoding, "need %d bytes, have %d", n, len(r.b))
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (r *reader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.err = errors.Wrap(ErrInvalidEncoding, "invalid varint")
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// length reads the length of a list that extends a base list of the given length, where every
+// element beyond the base list takes at least elementSize bytes of the remaining encoding.
+func (r *reader) length(baseLen int, elementSize uint64) int {
+	n := r.uint64()
+	if n > uint64(baseLen)+uint64(len(r.b))/elementSize {
+		r.err = errors.Wrapf(ErrInvalidEncoding, "list length %d exceeds encoding", n)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) rootsDiff(base [][]byte) ([][]byte, error) {
+	roots := make([][]byte, r.length(len(base), 8+rootLength))
+	copy(roots, base)
+	for n := r.uint64(); n > 0 && r.err == nil; n-- {
+		i := r.uint64()
+		root := r.bytes(rootLength)
+		if i >= uint64(len(roots)) {
+			return nil, errors.Wrapf(ErrInvalidEncoding, "root index %d out of range", i)
+		}
+		roots[i] = root
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	for i, root := range roots {
+		if root == nil {
+			return nil, errors.Wrapf(ErrInvalidEncoding, "missing root %d", i)
+		}
+	}
+	return roots, nil
+}
+
+func (r *reader) uint64sDiff(base []uint64) []uint64 {
+	vals := make([]uint64, r.length(0, 1))
+	for i := range vals {
+		if i < len(base) {
+			vals[i] = base[i]
+		}
+		vals[i] += uint64(r.varint())
+	}
+	return vals
+}
+
+func (r *reader) bytesDiff(base []byte) []byte {
+	b := append([]byte{}, r.bytes(uint64(r.length(0, 1)))...)
+	for i := range b {
+		if i < len(base) {
+			b[i] ^= base[i]
+		}
+	}
+	return b
+}
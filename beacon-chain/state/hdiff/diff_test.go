@@ -0,0 +1,224 @@
+package hdiff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/snappy"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+func genesisState(t testing.TB, v int) state.BeaconState {
+	var st state.BeaconState
+	var err error
+	switch v {
+	case version.Phase0:
+		st, err = util.NewBeaconState()
+	case version.Altair:
+		st, err = util.NewBeaconStateAltair()
+	case version.Bellatrix:
+		st, err = util.NewBeaconStateBellatrix()
+	case version.Capella:
+		st, err = util.NewBeaconStateCapella()
+	case version.Deneb:
+		st, err = util.NewBeaconStateDeneb()
+	default:
+		t.Fatalf("unknown version %d", v)
+	}
+	require.NoError(t, err)
+	const numValidators = 32
+	vals := make([]*zondpb.Validator, numValidators)
+	bals := make([]uint64, numValidators)
+	for i := range vals {
+		vals[i] = &zondpb.Validator{
+			PublicKey:             bytesutil.PadTo([]byte{byte(i)}, dilithium2.CryptoPublicKeyBytes),
+			WithdrawalCredentials: make([]byte, 32),
+			EffectiveBalance:      params.BeaconConfig().MaxEffectiveBalance,
+			ExitEpoch:             params.BeaconConfig().FarFutureEpoch,
+			WithdrawableEpoch:     params.BeaconConfig().FarFutureEpoch,
+		}
+		bals[i] = params.BeaconConfig().MaxEffectiveBalance
+	}
+	require.NoError(t, st.SetValidators(vals))
+	require.NoError(t, st.SetBalances(bals))
+	if v >= version.Altair {
+		pubkeys := make([][]byte, fieldparams.SyncCommitteeLength)
+		for i := range pubkeys {
+			pubkeys[i] = vals[i].PublicKey
+		}
+		sc := &zondpb.SyncCommittee{Pubkeys: pubkeys, AggregatePubkey: make([]byte, fieldparams.SyncCommitteeLength*dilithium2.CryptoPublicKeyBytes)}
+		require.NoError(t, st.SetCurrentSyncCommittee(sc))
+		require.NoError(t, st.SetNextSyncCommittee(sc))
+		require.NoError(t, st.SetInactivityScores(make([]uint64, numValidators)))
+		require.NoError(t, st.SetPreviousParticipationBits(make([]byte, numValidators)))
+		require.NoError(t, st.SetCurrentParticipationBits(make([]byte, numValidators)))
+	}
+	return st
+}
+
+// advance returns a copy of the state with every kind of diffed field modified.
+func advance(t testing.TB, st state.BeaconState) state.BeaconState {
+	st = st.Copy()
+	require.NoError(t, st.SetSlot(st.Slot()+64))
+	require.NoError(t, st.SetEth1DepositIndex(st.Eth1DepositIndex()+1))
+
+	bal, err := st.BalanceAtIndex(0)
+	require.NoError(t, err)
+	require.NoError(t, st.UpdateBalancesAtIndex(0, bal-1000))
+	require.NoError(t, st.UpdateBalancesAtIndex(1, bal+1000))
+	v, err := st.ValidatorAtIndex(2)
+	require.NoError(t, err)
+	v.ExitEpoch = 10
+	require.NoError(t, st.UpdateValidatorAtIndex(2, v))
+	v = zondpb.CopyValidator(v)
+	v.PublicKey = make([]byte, len(v.PublicKey))
+	v.PublicKey[0] = 'a'
+	require.NoError(t, st.AppendValidator(v))
+	require.NoError(t, st.AppendBalance(bal))
+
+	require.NoError(t, st.UpdateBlockRootAtIndex(3, [32]byte{'b'}))
+	require.NoError(t, st.UpdateStateRootAtIndex(4, [32]byte{'s'}))
+	require.NoError(t, st.UpdateRandaoMixesAtIndex(5, [32]byte{'r'}))
+
+	if st.Version() >= version.Altair {
+		require.NoError(t, st.AppendInactivityScore(7))
+		require.NoError(t, st.AppendCurrentParticipationBits(3))
+		require.NoError(t, st.AppendPreviousParticipationBits(0))
+		require.NoError(t, st.ModifyCurrentParticipationBits(func(val []byte) ([]byte, error) {
+			val[0] = 7
+			return val, nil
+		}))
+	}
+	return st
+}
+
+func requireStatesEqual(t testing.TB, want, got state.BeaconState) {
+	assert.Equal(t, want.Version(), got.Version())
+	wantRoot, err := want.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	gotRoot, err := got.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, wantRoot, gotRoot)
+}
+
+func TestSnapshot(t *testing.T) {
+	for v := version.Phase0; v <= version.Deneb; v++ {
+		t.Run(version.String(v), func(t *testing.T) {
+			st := genesisState(t, v)
+			enc, err := Snapshot(st)
+			require.NoError(t, err)
+			e, err := Decode(enc)
+			require.NoError(t, err)
+			assert.Equal(t, true, e.IsSnapshot())
+			got, err := e.State(nil)
+			require.NoError(t, err)
+			requireStatesEqual(t, st, got)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for v := version.Phase0; v <= version.Deneb; v++ {
+		t.Run(version.String(v), func(t *testing.T) {
+			base := genesisState(t, v)
+			target := advance(t, base)
+			enc, err := Diff(32, base, target)
+			require.NoError(t, err)
+			snapshot, err := Snapshot(target)
+			require.NoError(t, err)
+			assert.Equal(t, true, len(enc) < len(snapshot), "Diff of %d bytes is not smaller than snapshot of %d bytes", len(enc), len(snapshot))
+
+			e, err := Decode(enc)
+			require.NoError(t, err)
+			assert.Equal(t, false, e.IsSnapshot())
+			assert.Equal(t, primitives.Slot(32), e.BaseSlot())
+			got, err := e.State(base)
+			require.NoError(t, err)
+			requireStatesEqual(t, target, got)
+
+			// Diffs of a diffed state against its base apply to the rebuilt state.
+			next := advance(t, got)
+			enc, err = Diff(64, got, next)
+			require.NoError(t, err)
+			e, err = Decode(enc)
+			require.NoError(t, err)
+			got, err = e.State(got)
+			require.NoError(t, err)
+			requireStatesEqual(t, next, got)
+
+			_, err = e.State(nil)
+			require.ErrorContains(t, "nil base state", err)
+		})
+	}
+}
+
+func TestDiff_AcrossForks(t *testing.T) {
+	base := genesisState(t, version.Phase0)
+	target := advance(t, genesisState(t, version.Deneb))
+	enc, err := Diff(0, base, target)
+	require.NoError(t, err)
+	e, err := Decode(enc)
+	require.NoError(t, err)
+	got, err := e.State(base)
+	require.NoError(t, err)
+	requireStatesEqual(t, target, got)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	base := genesisState(t, version.Capella)
+	enc, err := Diff(0, base, advance(t, base))
+	require.NoError(t, err)
+	payload, err := snappy.Decode(nil, enc)
+	require.NoError(t, err)
+
+	_, err = Decode([]byte("not snappy"))
+	require.ErrorContains(t, "could not snappy decode", err)
+	_, err = Decode(snappy.Encode(nil, []byte{7}))
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	e, err := Decode(snappy.Encode(nil, payload[:len(payload)-1]))
+	require.NoError(t, err)
+	_, err = e.State(base)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	e, err = Decode(snappy.Encode(nil, append(payload, 0)))
+	require.NoError(t, err)
+	_, err = e.State(base)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+}
+
+func BenchmarkDiff(b *testing.B) {
+	base := genesisState(b, version.Capella)
+	target := advance(b, base)
+	snapshot, err := Snapshot(target)
+	require.NoError(b, err)
+	b.Run("encode", func(b *testing.B) {
+		var enc []byte
+		for i := 0; i < b.N; i++ {
+			enc, err = Diff(0, base, target)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(len(enc)), "diff_bytes")
+		b.ReportMetric(float64(len(snapshot)), "snapshot_bytes")
+	})
+	b.Run("rebuild", func(b *testing.B) {
+		enc, err := Diff(0, base, target)
+		require.NoError(b, err)
+		for i := 0; i < b.N; i++ {
+			e, err := Decode(enc)
+			require.NoError(b, err)
+			_, err = e.State(base)
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package hdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+)
+
+var errInvalidHierarchy = errors.New("invalid state diff hierarchy")
+
+// Hierarchy describes the layers of stored states, from the coarsest to the finest interval.
+// A state is stored at every slot that is a multiple of the finest interval. States at a multiple
+// of the coarsest interval are stored in full, every other state is stored as a diff against the
+// state at the closest multiple of the next coarser interval below it. Any stored state can
+// therefore be rebuilt from one snapshot and at most one diff per layer.
+type Hierarchy struct {
+	intervals []primitives.Slot
+}
+
+// NewHierarchy returns a hierarchy with the given layer intervals, ordered from the coarsest to the
+// finest. Every interval must be a multiple of the one that follows it.
+func NewHierarchy(intervals []primitives.Slot) (*Hierarchy, error) {
+	if len(intervals) == 0 {
+		return nil, errors.Wrap(errInvalidHierarchy, "no intervals")
+	}
+	for i, interval := range intervals {
+		if interval == 0 {
+			return nil, errors.Wrap(errInvalidHierarchy, "interval of 0 slots")
+		}
+		if i > 0 && (interval >= intervals[i-1] || intervals[i-1]%interval != 0) {
+			return nil, errors.Wrapf(errInvalidHierarchy, "interval %d is not a proper divisor of %d", interval, intervals[i-1])
+		}
+	}
+	return &Hierarchy{intervals: append([]primitives.Slot{}, intervals...)}, nil
+}
+
+// Intervals returns the slot intervals of the layers, from the coarsest to the finest.
+func (h *Hierarchy) Intervals() []primitives.Slot {
+	return append([]primitives.Slot{}, h.intervals...)
+}
+
+// SnapshotInterval is the number of slots between two states stored in full.
+func (h *Hierarchy) SnapshotInterval() primitives.Slot {
+	return h.intervals[0]
+}
+
+// StorageInterval is the number of slots between two stored states.
+func (h *Hierarchy) StorageInterval() primitives.Slot {
+	return h.intervals[len(h.intervals)-1]
+}
+
+// IsStorageSlot returns true if a state is stored at the given slot.
+func (h *Hierarchy) IsStorageSlot(slot primitives.Slot) bool {
+	return slot%h.StorageInterval() == 0
+}
+
+// Layer returns the coarsest layer the given storage slot belongs to, 0 being the snapshot layer.
+func (h *Hierarchy) Layer(slot primitives.Slot) (int, bool) {
+	for i, interval := range h.intervals {
+		if slot%interval == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// BaseSlot returns the slot of the state that the state stored at the given slot is diffed against.
+// It returns false for snapshot slots and for slots that are not storage slots.
+func (h *Hierarchy) BaseSlot(slot primitives.Slot) (primitives.Slot, bool) {
+	layer, ok := h.Layer(slot)
+	if !ok || layer == 0 {
+		return 0, false
+	}
+	interval := h.intervals[layer-1]
+	return slot - slot%interval, true
+}
+
+func (h *Hierarchy) String() string {
+	s := make([]string, len(h.intervals))
+	for i, interval := range h.intervals {
+		s[i] = fmt.Sprintf("%d", interval)
+	}
+	return strings.Join(s, ",")
+}
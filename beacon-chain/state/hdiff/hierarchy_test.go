@@ -0,0 +1,70 @@
+package hdiff
+
+import (
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestNewHierarchy(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []primitives.Slot
+		valid     bool
+	}{
+		{name: "no intervals"},
+		{name: "zero interval", intervals: []primitives.Slot{64, 0}},
+		{name: "not a divisor", intervals: []primitives.Slot{64, 48}},
+		{name: "ascending", intervals: []primitives.Slot{32, 64}},
+		{name: "duplicate", intervals: []primitives.Slot{64, 64}},
+		{name: "single layer", intervals: []primitives.Slot{64}, valid: true},
+		{name: "layers", intervals: []primitives.Slot{1024, 256, 32}, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHierarchy(tt.intervals)
+			if !tt.valid {
+				require.ErrorIs(t, err, errInvalidHierarchy)
+				return
+			}
+			require.NoError(t, err)
+			assert.DeepEqual(t, tt.intervals, h.Intervals())
+		})
+	}
+}
+
+func TestHierarchy_Layers(t *testing.T) {
+	h, err := NewHierarchy([]primitives.Slot{1024, 256, 32})
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(1024), h.SnapshotInterval())
+	assert.Equal(t, primitives.Slot(32), h.StorageInterval())
+	assert.Equal(t, "1024,256,32", h.String())
+
+	tests := []struct {
+		slot     primitives.Slot
+		stored   bool
+		layer    int
+		baseSlot primitives.Slot
+		isDiff   bool
+	}{
+		{slot: 0, stored: true, layer: 0},
+		{slot: 1, stored: false},
+		{slot: 32, stored: true, layer: 2, baseSlot: 0, isDiff: true},
+		{slot: 256, stored: true, layer: 1, baseSlot: 0, isDiff: true},
+		{slot: 288, stored: true, layer: 2, baseSlot: 256, isDiff: true},
+		{slot: 1000, stored: false},
+		{slot: 2048, stored: true, layer: 0},
+		{slot: 2048 + 768 + 96, stored: true, layer: 2, baseSlot: 2048 + 768, isDiff: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.stored, h.IsStorageSlot(tt.slot), "slot %d", tt.slot)
+		layer, ok := h.Layer(tt.slot)
+		assert.Equal(t, tt.stored, ok, "slot %d", tt.slot)
+		assert.Equal(t, tt.layer, layer, "slot %d", tt.slot)
+		baseSlot, ok := h.BaseSlot(tt.slot)
+		assert.Equal(t, tt.isDiff, ok, "slot %d", tt.slot)
+		assert.Equal(t, tt.baseSlot, baseSlot, "slot %d", tt.slot)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/theQRL/qrysm/v4/config/features"
 	consensus_types "github.com/theQRL/qrysm/v4/consensus-types"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/runtime/version"
@@ -180,6 +181,51 @@ func (b *BeaconState) PubkeyAtIndex(idx primitives.ValidatorIndex) [dilithium2.C
 	return bytesutil.ToBytes2592(v.PublicKey)
 }
 
+// PublicKeyAtIndex returns the parsed public key of the validator at the given index.
+// Parsed keys are kept in a registry shared by the copies of the state, so lookups do
+// not parse or hash the 2592-byte key again.
+func (b *BeaconState) PublicKeyAtIndex(idx primitives.ValidatorIndex) (dilithium.PublicKey, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var v *zondpb.Validator
+	if features.Get().EnableExperimentalState {
+		if b.validatorsMultiValue == nil {
+			return nil, state.ErrNilValidatorsInState
+		}
+		var err error
+		v, err = b.validatorsMultiValue.At(b, uint64(idx))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if b.validators == nil {
+			return nil, state.ErrNilValidatorsInState
+		}
+		if uint64(len(b.validators)) <= uint64(idx) {
+			return nil, errors.Wrapf(consensus_types.ErrOutOfBounds, "validator index %d does not exist", idx)
+		}
+		v = b.validators[idx]
+	}
+	if v == nil {
+		return nil, errors.Errorf("nil validator at index %d", idx)
+	}
+	if b.pubkeyRegistry == nil {
+		return dilithium.PublicKeyFromBytes(v.PublicKey)
+	}
+
+	key, ok, err := b.pubkeyRegistry.Add(idx, v.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// A copy of the state recorded a different validator at the index, which only
+		// happens when the registry cannot be replaced, so the key is parsed again.
+		return dilithium.PublicKeyFromBytes(v.PublicKey)
+	}
+	return key, nil
+}
+
 // NumValidators returns the size of the validator registry.
 func (b *BeaconState) NumValidators() int {
 	b.lock.RLock()
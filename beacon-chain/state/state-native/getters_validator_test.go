@@ -3,10 +3,12 @@ package state_native_test
 import (
 	"testing"
 
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/go-zond/common/hexutil"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	statenative "github.com/theQRL/qrysm/v4/beacon-chain/state/state-native"
 	testtmpl "github.com/theQRL/qrysm/v4/beacon-chain/state/testing"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
@@ -66,3 +68,88 @@ func TestValidatorIndexes(t *testing.T) {
 		require.Equal(t, hexutil.Encode(readOnlyBytes[:]), hexutil.Encode(byteValue[:]))
 	})
 }
+
+func TestBeaconState_PublicKeyAtIndex(t *testing.T) {
+	pubkey := func(i byte) []byte {
+		return bytesutil.PadTo([]byte{i + 1}, dilithium2.CryptoPublicKeyBytes)
+	}
+	st, err := statenative.InitializeFromProtoPhase0(&zondpb.BeaconState{
+		Validators: []*zondpb.Validator{{PublicKey: pubkey(0)}, {PublicKey: pubkey(1)}},
+	})
+	require.NoError(t, err)
+
+	key, err := st.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(1), key.Marshal())
+	again, err := st.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.Equal(t, key, again, "Expected the key to be parsed once")
+
+	_, err = st.PublicKeyAtIndex(2)
+	require.ErrorContains(t, "validator index 2 does not exist", err)
+
+	// Copies share the parsed keys, and keep their own keys once they diverge.
+	cpy1, cpy2 := st.Copy(), st.Copy()
+	cpyKey, err := cpy1.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.Equal(t, key, cpyKey)
+	require.NoError(t, cpy1.AppendValidator(&zondpb.Validator{PublicKey: pubkey(2)}))
+	require.NoError(t, cpy2.AppendValidator(&zondpb.Validator{PublicKey: pubkey(3)}))
+	key1, err := cpy1.PublicKeyAtIndex(2)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(2), key1.Marshal())
+	key2, err := cpy2.PublicKeyAtIndex(2)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(3), key2.Marshal())
+	_, err = st.PublicKeyAtIndex(2)
+	require.ErrorContains(t, "validator index 2 does not exist", err)
+
+	// Replacing the validators only keeps the keys of the unchanged validators.
+	key0, err := cpy1.PublicKeyAtIndex(0)
+	require.NoError(t, err)
+	require.NoError(t, cpy1.SetValidators([]*zondpb.Validator{{PublicKey: pubkey(0)}, {PublicKey: pubkey(4)}}))
+	key, err = cpy1.PublicKeyAtIndex(0)
+	require.NoError(t, err)
+	require.Equal(t, key0, key, "Expected the key of the unchanged validator to be kept")
+	key, err = cpy1.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(4), key.Marshal())
+	key, err = st.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(1), key.Marshal())
+}
+
+func TestBeaconState_AdoptPublicKeys(t *testing.T) {
+	pubkey := func(i byte) []byte {
+		return bytesutil.PadTo([]byte{i + 1}, dilithium2.CryptoPublicKeyBytes)
+	}
+	st, err := statenative.InitializeFromProtoPhase0(&zondpb.BeaconState{
+		Validators: []*zondpb.Validator{{PublicKey: pubkey(0)}, {PublicKey: pubkey(1)}},
+	})
+	require.NoError(t, err)
+	key0, err := st.PublicKeyAtIndex(0)
+	require.NoError(t, err)
+	key1, err := st.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+
+	// A state decoded separately, as when it is loaded from the database, with a different
+	// validator at index 1 and an additional validator.
+	loaded, err := statenative.InitializeFromProtoPhase0(&zondpb.BeaconState{
+		Validators: []*zondpb.Validator{{PublicKey: pubkey(0)}, {PublicKey: pubkey(2)}, {PublicKey: pubkey(3)}},
+	})
+	require.NoError(t, err)
+	loaded.AdoptPublicKeys(st)
+	key, err := loaded.PublicKeyAtIndex(0)
+	require.NoError(t, err)
+	require.Equal(t, key0, key, "Expected the key to be adopted")
+	key, err = loaded.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(2), key.Marshal())
+	key, err = loaded.PublicKeyAtIndex(2)
+	require.NoError(t, err)
+	require.DeepEqual(t, pubkey(3), key.Marshal())
+
+	key, err = st.PublicKeyAtIndex(1)
+	require.NoError(t, err)
+	require.Equal(t, key1, key)
+}
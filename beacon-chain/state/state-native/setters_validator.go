@@ -2,11 +2,13 @@ package state_native
 
 import (
 	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/state-native/types"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/stateutil"
 	"github.com/theQRL/qrysm/v4/config/features"
 	consensus_types "github.com/theQRL/qrysm/v4/consensus-types"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/runtime/version"
@@ -32,6 +34,7 @@ func (b *BeaconState) SetValidators(val []*zondpb.Validator) error {
 	b.markFieldAsDirty(types.Validators)
 	b.rebuildTrie[types.Validators] = true
 	b.valMapHandler = stateutil.NewValMapHandler(val)
+	b.pubkeyRegistry = b.pubkeyRegistry.ForValidators(val)
 	return nil
 }
 
@@ -248,6 +251,7 @@ func (b *BeaconState) AppendValidator(val *zondpb.Validator) error {
 	defer b.lock.Unlock()
 
 	b.valMapHandler.Set(bytesutil.ToBytes2592(val.PublicKey), valIdx)
+	b.registerPubkey(valIdx, val.PublicKey)
 	b.markFieldAsDirty(types.Validators)
 	b.addDirtyIndices(types.Validators, []uint64{uint64(valIdx)})
 	return nil
@@ -339,3 +343,51 @@ func (b *BeaconState) SetInactivityScores(val []uint64) error {
 	b.markFieldAsDirty(types.InactivityScores)
 	return nil
 }
+
+// registerPubkey records the public key of the validator at the index in the pubkey
+// registry. The registry is shared with the copies of the state, so when a copy already
+// recorded a different key at the index, the state stops sharing the registry.
+// This assumes that the lock is held.
+func (b *BeaconState) registerPubkey(idx primitives.ValidatorIndex, pubKey []byte) {
+	if b.pubkeyRegistry == nil {
+		return
+	}
+	if _, ok, err := b.pubkeyRegistry.Add(idx, pubKey); err != nil || ok {
+		// Malformed keys are not recorded, they fail to parse on lookup as well.
+		return
+	}
+	key, err := dilithium.PublicKeyFromBytes(pubKey)
+	if err != nil {
+		return
+	}
+	b.pubkeyRegistry = b.pubkeyRegistry.Copy()
+	b.pubkeyRegistry.Set(idx, pubKey, key)
+}
+
+// AdoptPublicKeys makes the state use the public keys parsed for another state, for the
+// validators both states have in common. States loaded from the database do not share the
+// keys parsed for the other states, which would otherwise be parsed again on lookup.
+func (b *BeaconState) AdoptPublicKeys(from state.ReadOnlyBeaconState) {
+	other, ok := from.(*BeaconState)
+	if !ok || other == b {
+		return
+	}
+	other.lock.RLock()
+	registry := other.pubkeyRegistry
+	other.lock.RUnlock()
+	if registry == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var vals []*zondpb.Validator
+	if features.Get().EnableExperimentalState {
+		if b.validatorsMultiValue != nil {
+			vals = b.validatorsMultiValue.Value(b)
+		}
+	} else {
+		vals = b.validators
+	}
+	b.pubkeyRegistry = registry.ForValidators(vals)
+}
@@ -169,6 +169,7 @@ func InitializeFromProtoUnsafePhase0(st *zondpb.BeaconState) (state.BeaconState,
 		stateFieldLeaves: make(map[types.FieldIndex]*fieldtrie.FieldTrie, fieldCount),
 		rebuildTrie:      make(map[types.FieldIndex]bool, fieldCount),
 		valMapHandler:    stateutil.NewValMapHandler(st.Validators),
+		pubkeyRegistry:   stateutil.NewPubkeyRegistry(len(st.Validators)),
 	}
 
 	if features.Get().EnableExperimentalState {
@@ -275,6 +276,7 @@ func InitializeFromProtoUnsafeAltair(st *zondpb.BeaconStateAltair) (state.Beacon
 		stateFieldLeaves: make(map[types.FieldIndex]*fieldtrie.FieldTrie, fieldCount),
 		rebuildTrie:      make(map[types.FieldIndex]bool, fieldCount),
 		valMapHandler:    stateutil.NewValMapHandler(st.Validators),
+		pubkeyRegistry:   stateutil.NewPubkeyRegistry(len(st.Validators)),
 	}
 
 	if features.Get().EnableExperimentalState {
@@ -385,6 +387,7 @@ func InitializeFromProtoUnsafeBellatrix(st *zondpb.BeaconStateBellatrix) (state.
 		stateFieldLeaves: make(map[types.FieldIndex]*fieldtrie.FieldTrie, fieldCount),
 		rebuildTrie:      make(map[types.FieldIndex]bool, fieldCount),
 		valMapHandler:    stateutil.NewValMapHandler(st.Validators),
+		pubkeyRegistry:   stateutil.NewPubkeyRegistry(len(st.Validators)),
 	}
 
 	if features.Get().EnableExperimentalState {
@@ -499,6 +502,7 @@ func InitializeFromProtoUnsafeCapella(st *zondpb.BeaconStateCapella) (state.Beac
 		stateFieldLeaves: make(map[types.FieldIndex]*fieldtrie.FieldTrie, fieldCount),
 		rebuildTrie:      make(map[types.FieldIndex]bool, fieldCount),
 		valMapHandler:    stateutil.NewValMapHandler(st.Validators),
+		pubkeyRegistry:   stateutil.NewPubkeyRegistry(len(st.Validators)),
 	}
 
 	if features.Get().EnableExperimentalState {
@@ -612,6 +616,7 @@ func InitializeFromProtoUnsafeDeneb(st *zondpb.BeaconStateDeneb) (state.BeaconSt
 		stateFieldLeaves: make(map[types.FieldIndex]*fieldtrie.FieldTrie, fieldCount),
 		rebuildTrie:      make(map[types.FieldIndex]bool, fieldCount),
 		valMapHandler:    stateutil.NewValMapHandler(st.Validators),
+		pubkeyRegistry:   stateutil.NewPubkeyRegistry(len(st.Validators)),
 	}
 
 	if features.Get().EnableExperimentalState {
@@ -759,6 +764,8 @@ func (b *BeaconState) Copy() state.BeaconState {
 
 		// Share the reference to validator index map.
 		valMapHandler: b.valMapHandler,
+		// Share the registry of parsed public keys, it is only appended to.
+		pubkeyRegistry: b.pubkeyRegistry,
 	}
 
 	if features.Get().EnableExperimentalState {
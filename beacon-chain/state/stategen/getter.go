@@ -27,7 +27,7 @@ func (s *State) HasState(ctx context.Context, blockRoot [32]byte) (bool, error)
 	if has {
 		return true, nil
 	}
-	return s.beaconDB.HasState(ctx, blockRoot), nil
+	return s.beaconDB.HasState(ctx, blockRoot) || s.hasStateDiff(ctx, blockRoot), nil
 }
 
 // hasStateInCache returns true if the state exists in cache.
@@ -208,7 +208,10 @@ func (s *State) loadStateByRoot(ctx context.Context, blockRoot [32]byte) (state.
 
 	// Short circuit if the state is already in the DB.
 	if s.beaconDB.HasState(ctx, blockRoot) {
-		return s.beaconDB.State(ctx, blockRoot)
+		return s.stateFromDB(ctx, blockRoot)
+	}
+	if s.hasStateDiff(ctx, blockRoot) {
+		return s.StateDiffByRoot(ctx, blockRoot)
 	}
 
 	summary, err := s.stateSummary(ctx, blockRoot)
@@ -306,10 +309,16 @@ func (s *State) latestAncestor(ctx context.Context, blockRoot [32]byte) (state.B
 
 		// Does the state exists in DB.
 		if s.beaconDB.HasState(ctx, parentRoot) {
-			s, err := s.beaconDB.State(ctx, parentRoot)
+			s, err := s.stateFromDB(ctx, parentRoot)
 			return s, errors.Wrap(err, "failed to retrieve state from db")
 		}
 
+		// Does the state exist as a state diff in DB.
+		if s.hasStateDiff(ctx, parentRoot) {
+			s, err := s.StateDiffByRoot(ctx, parentRoot)
+			return s, errors.Wrap(err, "failed to rebuild state from state diffs")
+		}
+
 		b, err = s.beaconDB.Block(ctx, parentRoot)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to retrieve block from db")
@@ -338,3 +347,28 @@ func (s *State) slotAvailable(slot primitives.Slot) bool {
 	}
 	return s.backfillStatus.SlotCovered(slot)
 }
+
+// stateFromDB loads the state of the block root from the database, with the public keys already
+// parsed for the finalized state.
+func (s *State) stateFromDB(ctx context.Context, blockRoot [32]byte) (state.BeaconState, error) {
+	st, err := s.beaconDB.State(ctx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	s.adoptPublicKeys(st)
+	return st, nil
+}
+
+// adoptPublicKeys makes a state which was not copied from the finalized state use the public
+// keys parsed for it.
+func (s *State) adoptPublicKeys(st state.BeaconState) {
+	if st == nil || st.IsNil() || s.finalizedInfo == nil {
+		return
+	}
+	s.finalizedInfo.lock.RLock()
+	defer s.finalizedInfo.lock.RUnlock()
+	if s.finalizedInfo.state == nil || s.finalizedInfo.state.IsNil() {
+		return
+	}
+	st.AdoptPublicKeys(s.finalizedInfo.state)
+}
@@ -20,8 +20,20 @@ func WithCache(c CachedGetter) CanonicalHistoryOption {
 	}
 }
 
+// WithStateDiffs rebuilds the historical states that are not stored in full from hierarchical state diffs.
+func WithStateDiffs(g StateDiffGetter) CanonicalHistoryOption {
+	return func(h *CanonicalHistory) {
+		h.diffs = g
+	}
+}
+
 type CanonicalHistoryOption func(*CanonicalHistory)
 
+// StateDiffGetter rebuilds states from hierarchical state diffs.
+type StateDiffGetter interface {
+	StateDiffByRoot(ctx context.Context, blockRoot [32]byte) (state.BeaconState, error)
+}
+
 func NewCanonicalHistory(h HistoryAccessor, cc CanonicalChecker, cs CurrentSlotter, opts ...CanonicalHistoryOption) *CanonicalHistory {
 	ch := &CanonicalHistory{
 		h:  h,
@@ -39,6 +51,7 @@ type CanonicalHistory struct {
 	cc    CanonicalChecker
 	cs    CurrentSlotter
 	cache CachedGetter
+	diffs StateDiffGetter
 }
 
 func (c *CanonicalHistory) ReplayerForSlot(target primitives.Slot) Replayer {
@@ -132,7 +145,11 @@ func (c *CanonicalHistory) getState(ctx context.Context, blockRoot [32]byte) (st
 			return nil, errors.Wrap(err, "error reading from state cache during state replay")
 		}
 	}
-	return c.h.StateOrError(ctx, blockRoot)
+	st, err := c.h.StateOrError(ctx, blockRoot)
+	if c.diffs != nil && errors.Is(err, db.ErrNotFoundState) {
+		return c.diffs.StateDiffByRoot(ctx, blockRoot)
+	}
+	return st, err
 }
 
 // ancestorChain works backwards through the chain lineage, accumulating blocks and checking for a saved state.
@@ -25,4 +25,17 @@ var (
 			Help: "Time it took to replay to slot",
 		},
 	)
+	stateDiffBytes = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "state_diff_bytes",
+			Help:    "The size of the stored hierarchical state diffs and snapshots",
+			Buckets: prometheus.ExponentialBuckets(1<<16, 4, 8),
+		},
+	)
+	stateDiffRebuildSummary = promauto.NewSummary(
+		prometheus.SummaryOpts{
+			Name: "state_diff_rebuild_milliseconds",
+			Help: "Time it took to rebuild a state from hierarchical state diffs",
+		},
+	)
 )
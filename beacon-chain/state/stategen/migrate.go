@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
@@ -44,7 +45,7 @@ func (s *State) MigrateToCold(ctx context.Context, fRoot [32]byte) error {
 			return ctx.Err()
 		}
 
-		if slot%s.slotsPerArchivedPoint == 0 && slot != 0 {
+		if s.isArchivePoint(slot) && slot != 0 {
 			cached, exists, err := s.epochBoundaryStateCache.getBySlot(slot)
 			if err != nil {
 				return fmt.Errorf("could not get epoch boundary state for slot %d", slot)
@@ -81,6 +82,24 @@ func (s *State) MigrateToCold(ctx context.Context, fRoot [32]byte) error {
 				}
 			}
 
+			if s.stateDiffs != nil {
+				if aState == nil {
+					aState, err = s.StateByRoot(ctx, aRoot)
+					if err != nil {
+						return err
+					}
+				}
+				if err := s.saveStateDiff(ctx, slot, aRoot, aState); err != nil {
+					return errors.Wrapf(err, "could not save state diff for slot %d", slot)
+				}
+				log.WithFields(
+					logrus.Fields{
+						"slot": aState.Slot(),
+						"root": hex.EncodeToString(bytesutil.Trunc(aRoot[:])),
+					}).Info("Saved state diff in DB")
+				continue
+			}
+
 			if s.beaconDB.HasState(ctx, aRoot) {
 				// If you are migrating a state and its already part of the hot state cache saved to the db,
 				// you can just remove it from the hot state cache as it becomes redundant.
@@ -13,17 +13,17 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
 	"github.com/theQRL/qrysm/v4/beacon-chain/forkchoice"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/hdiff"
 	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"go.opencensus.io/trace"
 )
 
 var defaultHotStateDBInterval primitives.Slot = 128
 
-var populatePubkeyCacheOnce sync.Once
+var populatePubkeyRegistryOnce sync.Once
 
 // StateManager represents a management object that handles the internal
 // logic of maintaining both hot and cold states in DB.
@@ -54,6 +54,7 @@ type State struct {
 	backfillStatus          *backfill.Status
 	migrationLock           *sync.Mutex
 	fc                      forkchoice.ForkChoicer
+	stateDiffs              *stateDiffs
 }
 
 // This tracks the config in the event of long non-finality,
@@ -84,6 +85,14 @@ func WithBackfillStatus(bfs *backfill.Status) StateGenOption {
 	}
 }
 
+// WithStateDiffHierarchy stores cold states as hierarchical state diffs in the given hierarchy,
+// instead of storing full states at every archived point.
+func WithStateDiffHierarchy(h *hdiff.Hierarchy) StateGenOption {
+	return func(sg *State) {
+		sg.stateDiffs = newStateDiffs(h)
+	}
+}
+
 // New returns a new state management object.
 func New(beaconDB db.NoHeadAccessDatabase, fc forkchoice.ForkChoicer, opts ...StateGenOption) *State {
 	s := &State{
@@ -143,22 +152,22 @@ func (s *State) Resume(ctx context.Context, fState state.BeaconState) (state.Bea
 
 	s.finalizedInfo = &finalizedInfo{slot: fState.Slot(), root: fRoot, state: fState.Copy()}
 
-	// Pre-populate the pubkey cache with the validator public keys from the finalized state.
-	// This process takes about 30 seconds on mainnet with 450,000 validators.
-	go populatePubkeyCacheOnce.Do(func() {
-		log.Debug("Populating pubkey cache")
+	// Pre-populate the pubkey registry of the finalized state, which is shared with the
+	// states derived from it, so block processing does not parse the keys on demand.
+	go populatePubkeyRegistryOnce.Do(func() {
+		log.Debug("Populating pubkey registry")
 		start := time.Now()
-		if err := fState.ReadFromEveryValidator(func(_ int, val state.ReadOnlyValidator) error {
+		for i := 0; i < fState.NumValidators(); i++ {
 			if ctx.Err() != nil {
-				return ctx.Err()
+				log.WithError(ctx.Err()).Error("Failed to populate pubkey registry")
+				return
+			}
+			if _, err := fState.PublicKeyAtIndex(primitives.ValidatorIndex(i)); err != nil {
+				log.WithError(err).Error("Failed to populate pubkey registry")
+				return
 			}
-			pub := val.PublicKey()
-			_, err := dilithium.PublicKeyFromBytes(pub[:])
-			return err
-		}); err != nil {
-			log.WithError(err).Error("Failed to populate pubkey cache")
 		}
-		log.WithField("duration", time.Since(start)).Debug("Done populating pubkey cache")
+		log.WithField("duration", time.Since(start)).Debug("Done populating pubkey registry")
 	})
 
 	return fState, nil
@@ -0,0 +1,199 @@
+package stategen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/hdiff"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"go.opencensus.io/trace"
+)
+
+// stateDiffs tracks the hierarchy that cold states are stored in when they are stored as
+// hierarchical state diffs instead of full states at every archived point.
+type stateDiffs struct {
+	hierarchy *hdiff.Hierarchy
+	lock      sync.Mutex
+	// bases holds the most recently rebuilt state of every layer that other layers are diffed against,
+	// so that consecutive diffs do not rebuild their base states from the DB.
+	bases []*stateDiffBase
+}
+
+type stateDiffBase struct {
+	slot  primitives.Slot
+	state state.BeaconState
+}
+
+func newStateDiffs(h *hdiff.Hierarchy) *stateDiffs {
+	return &stateDiffs{
+		hierarchy: h,
+		bases:     make([]*stateDiffBase, len(h.Intervals())-1),
+	}
+}
+
+func (d *stateDiffs) cachedBase(slot primitives.Slot) state.BeaconState {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, b := range d.bases {
+		if b != nil && b.slot == slot {
+			return b.state.Copy()
+		}
+	}
+	return nil
+}
+
+func (d *stateDiffs) cacheBase(slot primitives.Slot, st state.BeaconState) {
+	layer, ok := d.hierarchy.Layer(slot)
+	if !ok || layer >= len(d.bases) {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.bases[layer] = &stateDiffBase{slot: slot, state: st.Copy()}
+}
+
+// ArchiveInterval returns the interval between the cold states which can be loaded without replaying blocks from
+// an older state. With state diffs these are the snapshots, as every diff is applied to the snapshot below it.
+func (s *State) ArchiveInterval() primitives.Slot {
+	if s.stateDiffs != nil {
+		return s.stateDiffs.hierarchy.SnapshotInterval()
+	}
+	return s.slotsPerArchivedPoint
+}
+
+// isArchivePoint returns true if the cold state at the slot is stored in the DB.
+func (s *State) isArchivePoint(slot primitives.Slot) bool {
+	if s.stateDiffs != nil {
+		return s.stateDiffs.hierarchy.IsStorageSlot(slot)
+	}
+	return slot%s.slotsPerArchivedPoint == 0
+}
+
+// saveStateDiff stores the state of the block root at the given slot of the state diff hierarchy.
+func (s *State) saveStateDiff(ctx context.Context, slot primitives.Slot, blockRoot [32]byte, st state.BeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.saveStateDiff")
+	defer span.End()
+
+	lowest, err := s.beaconDB.LowestStateDiffSlot(ctx, slot)
+	if err == nil && lowest == slot {
+		return nil
+	}
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return err
+	}
+
+	baseSlot, isDiff := s.stateDiffs.hierarchy.BaseSlot(slot)
+	if isDiff {
+		// The base state is missing when the node started storing state diffs, or was synced from a checkpoint,
+		// after the base slot. The lowest state stored after the base slot is then a snapshot, which this state
+		// is diffed against instead.
+		lowest, err := s.beaconDB.LowestStateDiffSlot(ctx, baseSlot)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			isDiff = false
+		case err != nil:
+			return err
+		case lowest >= slot:
+			isDiff = false
+		default:
+			baseSlot = lowest
+		}
+	}
+
+	var enc []byte
+	if isDiff {
+		base, err := s.stateAtDiffSlot(ctx, baseSlot)
+		if err != nil {
+			return errors.Wrapf(err, "could not get base state at slot %d", baseSlot)
+		}
+		enc, err = hdiff.Diff(baseSlot, base, st)
+		if err != nil {
+			return errors.Wrap(err, "could not compute state diff")
+		}
+	} else {
+		enc, err = hdiff.Snapshot(st)
+		if err != nil {
+			return errors.Wrap(err, "could not encode state snapshot")
+		}
+	}
+	if err := s.beaconDB.SaveStateDiff(ctx, slot, blockRoot, enc); err != nil {
+		return err
+	}
+	stateDiffBytes.Observe(float64(len(enc)))
+	s.stateDiffs.cacheBase(slot, st)
+	return nil
+}
+
+// stateAtDiffSlot rebuilds the state stored at the given slot of the state diff hierarchy.
+func (s *State) stateAtDiffSlot(ctx context.Context, slot primitives.Slot) (state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.stateAtDiffSlot")
+	defer span.End()
+
+	if st := s.stateDiffs.cachedBase(slot); st != nil {
+		return st, nil
+	}
+	enc, err := s.beaconDB.StateDiff(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	e, err := hdiff.Decode(enc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decode state diff at slot %d", slot)
+	}
+	var base state.BeaconState
+	if !e.IsSnapshot() {
+		if e.BaseSlot() >= slot {
+			return nil, fmt.Errorf("state diff at slot %d has base slot %d", slot, e.BaseSlot())
+		}
+		base, err = s.stateAtDiffSlot(ctx, e.BaseSlot())
+		if err != nil {
+			return nil, err
+		}
+	}
+	st, err := e.State(base)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not rebuild state at slot %d", slot)
+	}
+	s.stateDiffs.cacheBase(slot, st)
+	return st, nil
+}
+
+// StateDiffByRoot rebuilds the state of the block root from the hierarchical state diffs in the DB.
+// It returns an error wrapping db.ErrNotFoundState if no state diff is stored for the block root.
+func (s *State) StateDiffByRoot(ctx context.Context, blockRoot [32]byte) (state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.StateDiffByRoot")
+	defer span.End()
+
+	if s.stateDiffs == nil {
+		return nil, errors.Wrapf(db.ErrNotFoundState, "state diffs are disabled, block root=%#x", blockRoot)
+	}
+	slot, err := s.beaconDB.StateDiffSlot(ctx, blockRoot)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, errors.Wrapf(db.ErrNotFoundState, "no state diff for block root=%#x", blockRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	st, err := s.stateAtDiffSlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	stateDiffRebuildSummary.Observe(float64(time.Since(start).Milliseconds()))
+	s.adoptPublicKeys(st)
+	return st, nil
+}
+
+// hasStateDiff returns true if a state diff is stored for the block root.
+func (s *State) hasStateDiff(ctx context.Context, blockRoot [32]byte) bool {
+	if s.stateDiffs == nil {
+		return false
+	}
+	_, err := s.beaconDB.StateDiffSlot(ctx, blockRoot)
+	return err == nil
+}
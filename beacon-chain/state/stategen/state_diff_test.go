@@ -0,0 +1,229 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/snappy"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/transition"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	testDB "github.com/theQRL/qrysm/v4/beacon-chain/db/testing"
+	doublylinkedtree "github.com/theQRL/qrysm/v4/beacon-chain/forkchoice/doubly-linked-tree"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/hdiff"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+func TestState_SaveStateDiff(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	h, err := hdiff.NewHierarchy([]primitives.Slot{8, 4, 2})
+	require.NoError(t, err)
+	service := New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h))
+
+	st, _ := util.DeterministicGenesisState(t, 32)
+	states := make(map[[32]byte]state.BeaconState)
+	// The base slot 0 of the first states is not stored, so the state at slot 2 is
+	// a snapshot that the states up to the next snapshot at slot 8 are diffed against.
+	wantBase := map[primitives.Slot]primitives.Slot{4: 2, 6: 4, 10: 8, 12: 8, 14: 12}
+	for slot := primitives.Slot(2); slot <= 14; slot += 2 {
+		st = st.Copy()
+		require.NoError(t, st.SetSlot(slot))
+		require.NoError(t, st.UpdateBalancesAtIndex(primitives.ValidatorIndex(slot), uint64(slot)))
+		root := [32]byte{byte(slot)}
+		require.NoError(t, service.saveStateDiff(ctx, slot, root, st))
+		states[root] = st
+
+		enc, err := beaconDB.StateDiff(ctx, slot)
+		require.NoError(t, err)
+		e, err := hdiff.Decode(enc)
+		require.NoError(t, err)
+		base, isDiff := wantBase[slot]
+		assert.Equal(t, !isDiff, e.IsSnapshot(), "slot %d", slot)
+		assert.Equal(t, base, e.BaseSlot(), "slot %d", slot)
+	}
+
+	// Rebuild the states without the cached base states.
+	service = New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h))
+	for root, want := range states {
+		has, err := service.HasState(ctx, root)
+		require.NoError(t, err)
+		assert.Equal(t, true, has)
+		got, err := service.StateDiffByRoot(ctx, root)
+		require.NoError(t, err)
+		wantRoot, err := want.HashTreeRoot(ctx)
+		require.NoError(t, err)
+		gotRoot, err := got.HashTreeRoot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, wantRoot, gotRoot, "slot %d", want.Slot())
+	}
+
+	_, err = service.StateDiffByRoot(ctx, [32]byte{'a'})
+	require.ErrorIs(t, err, db.ErrNotFoundState)
+	_, err = New(beaconDB, doublylinkedtree.New()).StateDiffByRoot(ctx, [32]byte{2})
+	require.ErrorIs(t, err, db.ErrNotFoundState)
+}
+
+func TestMigrateToCold_StateDiffs(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+
+	h, err := hdiff.NewHierarchy([]primitives.Slot{2, 1})
+	require.NoError(t, err)
+	service := New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h))
+	beaconState, _ := util.DeterministicGenesisState(t, 32)
+	require.NoError(t, beaconState.SetSlot(1))
+	b := util.NewBeaconBlock()
+	b.Block.Slot = 2
+	fRoot, err := b.Block.HashTreeRoot()
+	require.NoError(t, err)
+	util.SaveBlock(t, ctx, service.beaconDB, b)
+	require.NoError(t, service.epochBoundaryStateCache.put(fRoot, beaconState))
+	require.NoError(t, service.MigrateToCold(ctx, fRoot))
+	require.LogsContain(t, hook, "Saved state diff in DB")
+
+	assert.Equal(t, false, beaconDB.HasState(ctx, fRoot), "Saved full state")
+	require.NoError(t, service.epochBoundaryStateCache.delete(fRoot))
+	gotState, err := service.StateByRoot(ctx, fRoot)
+	require.NoError(t, err)
+	assert.DeepSSZEqual(t, beaconState.ToProtoUnsafe(), gotState.ToProtoUnsafe(), "Did not save state diff")
+}
+
+func TestCanonicalHistory_StateDiffs(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	h, err := hdiff.NewHierarchy([]primitives.Slot{2})
+	require.NoError(t, err)
+	service := New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h))
+	st, _ := util.DeterministicGenesisState(t, 32)
+	require.NoError(t, st.SetSlot(2))
+	require.NoError(t, service.saveStateDiff(ctx, 2, [32]byte{'a'}, st))
+
+	ch := NewCanonicalHistory(beaconDB, nil, nil)
+	_, err = ch.getState(ctx, [32]byte{'a'})
+	require.ErrorIs(t, err, db.ErrNotFoundState)
+
+	ch = NewCanonicalHistory(beaconDB, nil, nil, WithStateDiffs(service))
+	got, err := ch.getState(ctx, [32]byte{'a'})
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(2), got.Slot())
+	_, err = ch.getState(ctx, [32]byte{'b'})
+	require.ErrorIs(t, err, db.ErrNotFoundState)
+}
+
+// benchmarkBlock returns a signed block at the given slot on top of the state, and the state after it.
+func benchmarkBlock(b *testing.B, st state.BeaconState, keys []dilithium.DilithiumKey, slot primitives.Slot) (interfaces.ReadOnlySignedBeaconBlock, state.BeaconState) {
+	ctx := context.Background()
+	pre, err := transition.ProcessSlots(ctx, st.Copy(), slot)
+	require.NoError(b, err)
+	proposer, err := helpers.BeaconProposerIndex(ctx, pre)
+	require.NoError(b, err)
+	parent, err := pre.LatestBlockHeader().HashTreeRoot()
+	require.NoError(b, err)
+	reveal, err := util.RandaoReveal(pre, slots.ToEpoch(slot), keys)
+	require.NoError(b, err)
+	pb := util.NewBeaconBlock()
+	pb.Block.Slot = slot
+	pb.Block.ProposerIndex = proposer
+	pb.Block.ParentRoot = parent[:]
+	pb.Block.Body.RandaoReveal = reveal
+	sig, err := util.BlockSignature(st, pb.Block, keys)
+	require.NoError(b, err)
+	pb.Signature = sig.Marshal()
+	blk, err := blocks.NewSignedBeaconBlock(pb)
+	require.NoError(b, err)
+	st, err = transition.ExecuteStateTransition(ctx, st, blk)
+	require.NoError(b, err)
+	return blk, st
+}
+
+// BenchmarkArchivedState compares loading a historical state as archive nodes do, from the state of the last
+// archived point followed by block replay, against rebuilding it from hierarchical state diffs stored at a finer
+// interval followed by a shorter replay. The chain has a block at every slot, and the loaded state is the one of the
+// last slot before the next archived point, which needs the longest replay. The stored state bytes per archived
+// point are the snappy compressed SSZ encoding of the archived state against the snapshot and diffs of the same
+// range; the blocks are stored by both layouts and are not counted.
+func BenchmarkArchivedState(b *testing.B) {
+	ctx := context.Background()
+	const archivePoint, diffInterval = primitives.Slot(32), primitives.Slot(8)
+	h, err := hdiff.NewHierarchy([]primitives.Slot{archivePoint, diffInterval})
+	require.NoError(b, err)
+
+	genesis, keys := util.DeterministicGenesisState(b, 64)
+	st := genesis.Copy()
+	blks := make([]interfaces.ReadOnlySignedBeaconBlock, 0, archivePoint-1)
+	states := make([]state.BeaconState, 0, archivePoint-1)
+	for slot := primitives.Slot(1); slot < archivePoint; slot++ {
+		var blk interfaces.ReadOnlySignedBeaconBlock
+		blk, st = benchmarkBlock(b, st, keys, slot)
+		blks = append(blks, blk)
+		states = append(states, st)
+	}
+	target, err := blks[len(blks)-1].Block().HashTreeRoot()
+	require.NoError(b, err)
+
+	// setupDB saves the genesis state at the archived point of slot 0, and the blocks and state summaries of the chain.
+	setupDB := func(b *testing.B) (db.Database, [32]byte) {
+		beaconDB := testDB.SetupDB(b)
+		require.NoError(b, beaconDB.SaveGenesisData(ctx, genesis))
+		genesisRoot, err := beaconDB.GenesisBlockRoot(ctx)
+		require.NoError(b, err)
+		for _, blk := range blks {
+			require.NoError(b, beaconDB.SaveBlock(ctx, blk))
+			root, err := blk.Block().HashTreeRoot()
+			require.NoError(b, err)
+			require.NoError(b, beaconDB.SaveStateSummary(ctx, &zondpb.StateSummary{Slot: blk.Block().Slot(), Root: root[:]}))
+		}
+		return beaconDB, genesisRoot
+	}
+
+	b.Run("replay", func(b *testing.B) {
+		beaconDB, _ := setupDB(b)
+		enc, err := genesis.MarshalSSZ()
+		require.NoError(b, err)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// Without the cached states of a running service.
+			_, err := New(beaconDB, doublylinkedtree.New()).StateByRoot(ctx, target)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(len(snappy.Encode(nil, enc))), "state_bytes/archive_point")
+	})
+	b.Run("diffs", func(b *testing.B) {
+		beaconDB, genesisRoot := setupDB(b)
+		service := New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h))
+		require.NoError(b, service.saveStateDiff(ctx, 0, genesisRoot, genesis))
+		for i, blk := range blks {
+			slot := blk.Block().Slot()
+			if slot%diffInterval != 0 {
+				continue
+			}
+			root, err := blk.Block().HashTreeRoot()
+			require.NoError(b, err)
+			require.NoError(b, service.saveStateDiff(ctx, slot, root, states[i]))
+		}
+		var size int
+		for slot := primitives.Slot(0); slot < archivePoint; slot += diffInterval {
+			enc, err := beaconDB.StateDiff(ctx, slot)
+			require.NoError(b, err)
+			size += len(enc)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := New(beaconDB, doublylinkedtree.New(), WithStateDiffHierarchy(h)).StateByRoot(ctx, target)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(size), "state_bytes/archive_point")
+	})
+}
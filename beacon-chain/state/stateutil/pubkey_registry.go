@@ -0,0 +1,144 @@
+package stateutil
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+)
+
+// PubkeyRegistry maps validator indices to parsed validator public keys. A validator keeps
+// its public key for the lifetime of the registry, so the registry is only ever appended
+// to and is shared by all the copies of a state. Keys are added as validators are appended
+// to the state or looked up for the first time.
+type PubkeyRegistry struct {
+	lock sync.RWMutex
+	keys []registeredPubkey
+}
+
+// registeredPubkey holds the serialized public key of a validator next to the parsed key, so
+// keys are compared without serializing the parsed key again. The serialized key is the one of
+// the validator, which is never modified in place.
+type registeredPubkey struct {
+	raw []byte
+	key dilithium.PublicKey
+}
+
+// NewPubkeyRegistry returns an empty registry with capacity for the given number of validators.
+func NewPubkeyRegistry(numValidators int) *PubkeyRegistry {
+	return &PubkeyRegistry{
+		keys: make([]registeredPubkey, 0, numValidators),
+	}
+}
+
+// Get the public key of the validator at the index, if it was added to the registry.
+func (r *PubkeyRegistry) Get(idx primitives.ValidatorIndex) (dilithium.PublicKey, bool) {
+	k, ok := r.get(idx)
+	return k.key, ok
+}
+
+// Add parses the public key and records it at the index, unless the index already holds
+// a key. The recorded key is returned, with false if it differs from the given key, which
+// happens when a copy of the state sharing the registry has a different validator at the
+// index.
+func (r *PubkeyRegistry) Add(idx primitives.ValidatorIndex, pubKey []byte) (dilithium.PublicKey, bool, error) {
+	if existing, ok := r.get(idx); ok {
+		return existing.key, bytes.Equal(existing.raw, pubKey), nil
+	}
+	key, err := dilithium.PublicKeyFromBytes(pubKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	// Another caller may have added a key since the read lock was released.
+	if uint64(idx) < uint64(len(r.keys)) && r.keys[idx].key != nil {
+		existing := r.keys[idx]
+		return existing.key, bytes.Equal(existing.raw, pubKey), nil
+	}
+	r.set(idx, registeredPubkey{raw: pubKey, key: key})
+	return key, true, nil
+}
+
+// Set records the public key parsed from pubKey at the index, replacing any key already
+// recorded there. It is only meant for registries which are not shared with other states.
+func (r *PubkeyRegistry) Set(idx primitives.ValidatorIndex, pubKey []byte, key dilithium.PublicKey) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.set(idx, registeredPubkey{raw: pubKey, key: key})
+}
+
+func (r *PubkeyRegistry) get(idx primitives.ValidatorIndex) (registeredPubkey, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if uint64(idx) >= uint64(len(r.keys)) || r.keys[idx].key == nil {
+		return registeredPubkey{}, false
+	}
+	return r.keys[idx], true
+}
+
+func (r *PubkeyRegistry) set(idx primitives.ValidatorIndex, k registeredPubkey) {
+	if uint64(idx) >= uint64(len(r.keys)) {
+		if uint64(idx) < uint64(cap(r.keys)) {
+			r.keys = r.keys[:idx+1]
+		} else {
+			keys := make([]registeredPubkey, idx+1, 2*(idx+1))
+			copy(keys, r.keys)
+			r.keys = keys
+		}
+	}
+	r.keys[idx] = k
+}
+
+// Copy returns a registry holding the same keys, which can be modified independently.
+// The parsed keys are immutable and shared between both registries.
+func (r *PubkeyRegistry) Copy() *PubkeyRegistry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	keys := make([]registeredPubkey, len(r.keys), cap(r.keys))
+	copy(keys, r.keys)
+	return &PubkeyRegistry{keys: keys}
+}
+
+// ForValidators returns a registry for a state holding the given validators. The registry is
+// returned as is, and stays shared, when every key it holds for the indices of the validators
+// matches their public key. Otherwise a registry holding only the matching keys is returned.
+// A nil registry returns an empty registry.
+func (r *PubkeyRegistry) ForValidators(vals []*zondpb.Validator) *PubkeyRegistry {
+	if r == nil {
+		return NewPubkeyRegistry(len(vals))
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	n := len(r.keys)
+	if n > len(vals) {
+		n = len(vals)
+	}
+	var keys []registeredPubkey
+	for i := 0; i < n; i++ {
+		if r.keys[i].key == nil || (vals[i] != nil && bytes.Equal(r.keys[i].raw, vals[i].PublicKey)) {
+			continue
+		}
+		// A key differs, so only the matching keys are kept in a new registry.
+		if keys == nil {
+			keys = make([]registeredPubkey, n, len(vals))
+			copy(keys, r.keys[:n])
+		}
+		keys[i] = registeredPubkey{}
+	}
+	if keys == nil {
+		return r
+	}
+	return &PubkeyRegistry{keys: keys}
+}
+
+// Len returns the number of indices covered by the registry, including the indices of
+// validators whose key was not added yet.
+func (r *PubkeyRegistry) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return len(r.keys)
+}
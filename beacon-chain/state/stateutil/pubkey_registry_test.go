@@ -0,0 +1,207 @@
+package stateutil_test
+
+import (
+	"encoding/binary"
+	"runtime"
+	"testing"
+
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/stateutil"
+	lruwrpr "github.com/theQRL/qrysm/v4/cache/lru"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func testPubkey(i uint64) []byte {
+	key := make([]byte, dilithium2.CryptoPublicKeyBytes)
+	binary.LittleEndian.PutUint64(key, i+1)
+	return key
+}
+
+func TestPubkeyRegistry_AddGet(t *testing.T) {
+	r := stateutil.NewPubkeyRegistry(2)
+	_, ok := r.Get(0)
+	assert.Equal(t, false, ok)
+
+	key, ok, err := r.Add(3, testPubkey(3))
+	require.NoError(t, err)
+	assert.Equal(t, true, ok)
+	assert.DeepEqual(t, testPubkey(3), key.Marshal())
+	assert.Equal(t, 4, r.Len())
+
+	got, ok := r.Get(3)
+	require.Equal(t, true, ok)
+	assert.Equal(t, key, got)
+	// Indices below an added index are not filled in.
+	_, ok = r.Get(1)
+	assert.Equal(t, false, ok)
+
+	// Adding the same key returns the recorded key.
+	again, ok, err := r.Add(3, testPubkey(3))
+	require.NoError(t, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, key, again)
+
+	_, _, err = r.Add(4, []byte{1, 2, 3})
+	require.ErrorContains(t, "public key must be", err)
+	_, ok = r.Get(4)
+	assert.Equal(t, false, ok)
+}
+
+func TestPubkeyRegistry_Conflict(t *testing.T) {
+	r := stateutil.NewPubkeyRegistry(0)
+	key, _, err := r.Add(0, testPubkey(0))
+	require.NoError(t, err)
+
+	recorded, ok, err := r.Add(0, testPubkey(1))
+	require.NoError(t, err)
+	assert.Equal(t, false, ok)
+	assert.Equal(t, key, recorded)
+
+	// A copy can be changed without affecting the original registry.
+	cpy := r.Copy()
+	other, err := dilithium.PublicKeyFromBytes(testPubkey(1))
+	require.NoError(t, err)
+	cpy.Set(0, testPubkey(1), other)
+	got, ok := cpy.Get(0)
+	require.Equal(t, true, ok)
+	assert.DeepEqual(t, testPubkey(1), got.Marshal())
+	got, ok = r.Get(0)
+	require.Equal(t, true, ok)
+	assert.DeepEqual(t, testPubkey(0), got.Marshal())
+}
+
+func TestPubkeyRegistry_ForValidators(t *testing.T) {
+	var nilRegistry *stateutil.PubkeyRegistry
+	assert.Equal(t, 0, nilRegistry.ForValidators([]*zondpb.Validator{{PublicKey: testPubkey(0)}}).Len())
+
+	r := stateutil.NewPubkeyRegistry(3)
+	key0, _, err := r.Add(0, testPubkey(0))
+	require.NoError(t, err)
+	_, _, err = r.Add(2, testPubkey(2))
+	require.NoError(t, err)
+
+	// The registry stays shared when every key matches, including with fewer validators.
+	vals := []*zondpb.Validator{{PublicKey: testPubkey(0)}, {PublicKey: testPubkey(5)}, {PublicKey: testPubkey(2)}}
+	assert.Equal(t, r, r.ForValidators(vals))
+	assert.Equal(t, r, r.ForValidators(vals[:1]))
+
+	// Only the matching keys are kept otherwise.
+	vals[2] = &zondpb.Validator{PublicKey: testPubkey(6)}
+	filtered := r.ForValidators(vals)
+	assert.NotEqual(t, r, filtered)
+	got, ok := filtered.Get(0)
+	require.Equal(t, true, ok)
+	assert.Equal(t, key0, got)
+	_, ok = filtered.Get(2)
+	assert.Equal(t, false, ok)
+	key, ok, err := filtered.Add(2, testPubkey(6))
+	require.NoError(t, err)
+	assert.Equal(t, true, ok)
+	assert.DeepEqual(t, testPubkey(6), key.Marshal())
+	got, ok = r.Get(2)
+	require.Equal(t, true, ok)
+	assert.DeepEqual(t, testPubkey(2), got.Marshal())
+}
+
+const benchmarkNumKeys = 100_000
+
+func benchmarkPubkeys() [][]byte {
+	keys := make([][]byte, benchmarkNumKeys)
+	for i := range keys {
+		keys[i] = testPubkey(uint64(i))
+	}
+	return keys
+}
+
+// lruPubkeyCache mirrors the global cache dilithiumt used to deduplicate parsed public
+// keys, keyed by the full public key.
+func lruPubkeyCache(keys [][]byte) (func(pubKey []byte) dilithium.PublicKey, interface{}) {
+	cache := lruwrpr.New(len(keys))
+	for _, k := range keys {
+		pk, err := dilithium.PublicKeyFromBytes(k)
+		if err != nil {
+			panic(err)
+		}
+		cache.Add(*(*[dilithium2.CryptoPublicKeyBytes]byte)(k), pk)
+	}
+	return func(pubKey []byte) dilithium.PublicKey {
+		cv, ok := cache.Get(*(*[dilithium2.CryptoPublicKeyBytes]byte)(pubKey))
+		if !ok {
+			return nil
+		}
+		pk, ok := cv.(dilithium.PublicKey)
+		if !ok {
+			return nil
+		}
+		return pk.Copy()
+	}, cache
+}
+
+func filledRegistry(keys [][]byte) *stateutil.PubkeyRegistry {
+	r := stateutil.NewPubkeyRegistry(len(keys))
+	for i, k := range keys {
+		if _, _, err := r.Add(primitives.ValidatorIndex(i), k); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+func BenchmarkPubkeyLookup(b *testing.B) {
+	keys := benchmarkPubkeys()
+
+	b.Run("registry", func(b *testing.B) {
+		r := filledRegistry(keys)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, ok := r.Get(primitives.ValidatorIndex(i % len(keys))); !ok {
+				b.Fatal("missing key")
+			}
+		}
+	})
+	b.Run("lru_cache", func(b *testing.B) {
+		get, _ := lruPubkeyCache(keys)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if get(keys[i%len(keys)]) == nil {
+				b.Fatal("missing key")
+			}
+		}
+	})
+}
+
+func BenchmarkPubkeyMemory(b *testing.B) {
+	keys := benchmarkPubkeys()
+	measure := func(b *testing.B, build func() interface{}) {
+		var total uint64
+		for i := 0; i < b.N; i++ {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			v := build()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			runtime.KeepAlive(v)
+			if after.HeapAlloc > before.HeapAlloc {
+				total += after.HeapAlloc - before.HeapAlloc
+			}
+		}
+		b.ReportMetric(float64(total)/float64(b.N)/float64(len(keys)), "bytes/key")
+	}
+
+	b.Run("registry", func(b *testing.B) {
+		measure(b, func() interface{} { return filledRegistry(keys) })
+	})
+	b.Run("lru_cache", func(b *testing.B) {
+		measure(b, func() interface{} {
+			_, cache := lruPubkeyCache(keys)
+			return cache
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	backfillRemainingSlots = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_remaining_slots",
+			Help: "Number of slots between genesis and the lowest backfilled block.",
+		},
+	)
+	backfillLowestSlot = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_lowest_slot",
+			Help: "Slot of the lowest block backfilled below the origin checkpoint.",
+		},
+	)
+	backfillBlocksImported = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "backfill_blocks_imported_total",
+			Help: "Count of blocks imported by backfill.",
+		},
+	)
+	backfillBatchFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backfill_batch_failures_total",
+			Help: "Count of backfill batches which could not be imported, by reason.",
+		},
+		[]string{"reason"},
+	)
+)
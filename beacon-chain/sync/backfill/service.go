@@ -0,0 +1,344 @@
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/runtime"
+)
+
+const (
+	defaultBatchSize     = 64
+	defaultBatchInterval = time.Second
+	// retryInterval is how long to wait before requesting a batch again after it could not be imported.
+	retryInterval = 5 * time.Second
+)
+
+var errBackfillStalled = errors.New("backfill reached genesis without linking to the genesis block")
+
+var _ runtime.Service = (*Service)(nil)
+
+// BlockFetcher requests ranges of finalized blocks from peers.
+type BlockFetcher interface {
+	// FetchBlocks returns the blocks of the slots in [start, start+count), sorted by slot, along with the peer
+	// which served them.
+	FetchBlocks(ctx context.Context, start primitives.Slot, count uint64) ([]blocks.ROBlock, peer.ID, error)
+	// BadResponse penalizes a peer which served blocks that failed verification.
+	BadResponse(pid peer.ID)
+}
+
+// Database describes the set of DB methods that the backfill Service needs to function.
+type Database interface {
+	BackfillDB
+	SaveBackfillBlocks(ctx context.Context, blks []blocks.ROBlock, childRoot [32]byte) error
+	State(ctx context.Context, blockRoot [32]byte) (state.BeaconState, error)
+}
+
+// ServiceOption represents a functional option for the backfill Service.
+type ServiceOption func(*Service) error
+
+// WithEnableBackfill toggles the backfill service, which is enabled by default.
+func WithEnableBackfill(enabled bool) ServiceOption {
+	return func(s *Service) error {
+		s.enabled = enabled
+		return nil
+	}
+}
+
+// WithBatchSize sets the number of slots requested from a peer at once.
+func WithBatchSize(n uint64) ServiceOption {
+	return func(s *Service) error {
+		if n == 0 {
+			return errors.New("backfill batch size must be greater than zero")
+		}
+		s.batchSize = n
+		return nil
+	}
+}
+
+// WithBatchInterval sets the minimum time between two batch requests, which throttles backfill.
+func WithBatchInterval(d time.Duration) ServiceOption {
+	return func(s *Service) error {
+		s.batchInterval = d
+		return nil
+	}
+}
+
+// WithInitialSyncComplete makes the service wait for initial sync to complete before backfilling, so that both
+// do not compete for peers.
+func WithInitialSyncComplete(c <-chan struct{}) ServiceOption {
+	return func(s *Service) error {
+		s.initialSyncComplete = c
+		return nil
+	}
+}
+
+// Service downloads the blocks between genesis and the origin checkpoint of a checkpoint synced node. Blocks are
+// requested backwards from the origin in batches, and a batch is only saved once it links to the lowest backfilled
+// block by parent root and all of its proposer signatures are valid. Progress is recorded through Status.Advance,
+// so that a restarted node resumes below the last saved batch.
+type Service struct {
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	su                  *Status
+	db                  Database
+	fetcher             BlockFetcher
+	cw                  startup.ClockWaiter
+	initialSyncComplete <-chan struct{}
+	enabled             bool
+	batchSize           uint64
+	batchInterval       time.Duration
+	retryInterval       time.Duration
+	err                 error
+}
+
+// NewService initializes the backfill Service.
+func NewService(ctx context.Context, su *Status, db Database, fetcher BlockFetcher, cw startup.ClockWaiter, opts ...ServiceOption) (*Service, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:           ctx,
+		cancel:        cancel,
+		su:            su,
+		db:            db,
+		fetcher:       fetcher,
+		cw:            cw,
+		enabled:       true,
+		batchSize:     defaultBatchSize,
+		batchInterval: defaultBatchInterval,
+		retryInterval: retryInterval,
+	}
+	for _, o := range opts {
+		if err := o(s); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Start backfills blocks until the gap between genesis and the origin checkpoint is closed.
+func (s *Service) Start() {
+	if !s.enabled {
+		log.Info("Backfill is disabled")
+		return
+	}
+	if s.su.Complete() {
+		log.Debug("No blocks to backfill")
+		return
+	}
+	clock, err := s.cw.WaitForClock(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("Backfill service failed to start while waiting for genesis data")
+		return
+	}
+	if s.initialSyncComplete != nil {
+		select {
+		case <-s.initialSyncComplete:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+	if err := s.run(clock.GenesisValidatorsRoot()); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		s.err = err
+		log.WithError(err).Error("Backfill stopped")
+	}
+}
+
+// Stop the backfill service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the backfill service, which reports the error that stopped backfill, if any.
+func (s *Service) Status() error {
+	return s.err
+}
+
+// cursor is the lowest backfilled block, which the next batch must link to.
+type cursor struct {
+	slot   primitives.Slot
+	root   [32]byte
+	parent [32]byte
+}
+
+func (s *Service) run(gvr [32]byte) error {
+	genesisRoot, err := s.db.GenesisBlockRoot(s.ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis block root")
+	}
+	originRoot, err := s.db.OriginCheckpointBlockRoot(s.ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get origin checkpoint block root")
+	}
+	origin, err := s.db.State(s.ctx, originRoot)
+	if err != nil {
+		return errors.Wrapf(err, "could not get origin checkpoint state, root=%#x", originRoot)
+	}
+	if origin == nil || origin.IsNil() {
+		return errors.Errorf("origin checkpoint state not found, root=%#x", originRoot)
+	}
+	v := newVerifier(origin, gvr)
+
+	low, err := s.lowest(genesisRoot, originRoot)
+	if err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{
+		"slot": low.slot,
+		"root": low.root,
+	}).Info("Starting backfill")
+
+	cur := low
+	// withheld holds the peers which answered with no blocks since the lowest backfilled block.
+	var withheld []peer.ID
+	for cur.parent != genesisRoot {
+		backfillRemainingSlots.Set(float64(cur.slot))
+		floor := s.su.StartGap()
+		if pruned := s.su.PrunedSlot(); pruned > floor+1 {
+			// blocks below the pruned slot are deleted again by history pruning, so they are not backfilled
+			if cur.slot <= pruned {
+				backfillRemainingSlots.Set(0)
+				log.WithField("prunedSlot", pruned).Info("Backfill reached pruned history")
+				return nil
+			}
+			floor = pruned - 1
+		}
+		if cur.slot <= floor+1 {
+			if cur == low {
+				return errors.Wrapf(errBackfillStalled, "lowest block slot=%d, parent root=%#x", low.slot, low.parent)
+			}
+			// empty responses skipped over the parent of the lowest block, try again from the top
+			s.penalize(withheld)
+			cur, withheld = low, nil
+			if err := s.wait(s.retryInterval); err != nil {
+				return err
+			}
+			continue
+		}
+		end := cur.slot
+		start := floor + 1
+		if end-start > primitives.Slot(s.batchSize) {
+			start = end - primitives.Slot(s.batchSize)
+		}
+		blks, pid, err := s.fetcher.FetchBlocks(s.ctx, start, uint64(end-start))
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return s.ctx.Err()
+			}
+			backfillBatchFailures.WithLabelValues("fetch").Inc()
+			log.WithError(err).WithField("start", start).Debug("Could not fetch backfill batch")
+			if err := s.wait(s.retryInterval); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(blks) == 0 {
+			// The range only contains skipped slots, or the peer withheld its blocks. The latter is detected when
+			// the next batch does not link to the lowest backfilled block.
+			withheld = append(withheld, pid)
+			cur.slot = start
+			continue
+		}
+		if err := v.verify(blks, start, end, cur.parent); err != nil {
+			backfillBatchFailures.WithLabelValues("verify").Inc()
+			log.WithError(err).WithFields(logrus.Fields{
+				"peer":  pid,
+				"start": start,
+			}).Debug("Backfill batch failed verification")
+			s.penalize(v.culprits(err, blks, start, end, pid, withheld))
+			// the batch may fail because an earlier, empty response skipped over blocks
+			cur, withheld = low, nil
+			if err := s.wait(s.retryInterval); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.save(blks, low.root); err != nil {
+			return err
+		}
+		lowest := blks[0]
+		low = cursor{slot: lowest.Block().Slot(), root: lowest.Root(), parent: lowest.Block().ParentRoot()}
+		cur, withheld = low, nil
+		backfillLowestSlot.Set(float64(low.slot))
+		if err := s.wait(s.batchInterval); err != nil {
+			return err
+		}
+	}
+
+	// All the slots below the lowest block are skipped, close the gap.
+	if err := s.su.Advance(s.ctx, s.su.StartGap(), low.root); err != nil {
+		return errors.Wrap(err, "could not complete backfill")
+	}
+	backfillRemainingSlots.Set(0)
+	log.Info("Backfill complete")
+	return nil
+}
+
+// lowest returns the lowest backfilled block, which is the origin checkpoint block if backfill has not started.
+func (s *Service) lowest(genesisRoot, originRoot [32]byte) (cursor, error) {
+	root, err := s.db.BackfillBlockRoot(s.ctx)
+	if err != nil {
+		return cursor{}, errors.Wrap(err, "could not get backfill block root")
+	}
+	if root == genesisRoot {
+		root = originRoot
+	}
+	b, err := s.db.Block(s.ctx, root)
+	if err != nil {
+		return cursor{}, errors.Wrapf(err, "could not get lowest backfilled block, root=%#x", root)
+	}
+	if err := blocks.BeaconBlockIsNil(b); err != nil {
+		return cursor{}, err
+	}
+	return cursor{slot: b.Block().Slot(), root: root, parent: b.Block().ParentRoot()}, nil
+}
+
+// save writes a verified batch, whose highest block is the parent of the lowest backfilled block child, as part
+// of the finalized chain and moves the backfill position down to its lowest block.
+func (s *Service) save(blks []blocks.ROBlock, child [32]byte) error {
+	if err := s.db.SaveBackfillBlocks(s.ctx, blks, child); err != nil {
+		backfillBatchFailures.WithLabelValues("save").Inc()
+		return errors.Wrap(err, "could not save backfill batch")
+	}
+	if err := s.su.Advance(s.ctx, blks[0].Block().Slot(), blks[0].Root()); err != nil {
+		return errors.Wrap(err, "could not advance backfill status")
+	}
+	backfillBlocksImported.Add(float64(len(blks)))
+	log.WithFields(logrus.Fields{
+		"lowestSlot":  blks[0].Block().Slot(),
+		"blocksSaved": len(blks),
+	}).Debug("Backfilled batch")
+	return nil
+}
+
+// penalize reports the peers which served a bad response.
+func (s *Service) penalize(pids []peer.ID) {
+	for _, pid := range pids {
+		s.fetcher.BadResponse(pid)
+	}
+}
+
+func (s *Service) wait(d time.Duration) error {
+	if d <= 0 {
+		return s.ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
@@ -0,0 +1,236 @@
+package backfill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// testChain builds a chain of signed blocks on top of a genesis block root, with one block for each of the given
+// slots.
+func testChain(t *testing.T, blockSlots ...primitives.Slot) (state.BeaconState, [32]byte, []blocks.ROBlock) {
+	st, keys := util.DeterministicGenesisState(t, 8)
+	genesisRoot := [32]byte{0xaa}
+	parent := genesisRoot
+	chain := make([]blocks.ROBlock, 0, len(blockSlots))
+	for _, slot := range blockSlots {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = slot
+		b.Block.ProposerIndex = primitives.ValidatorIndex(uint64(slot) % uint64(len(keys)))
+		b.Block.ParentRoot = parent[:]
+		sig, err := signing.ComputeDomainAndSign(st, slots.ToEpoch(slot), b.Block, params.BeaconConfig().DomainBeaconProposer, keys[b.Block.ProposerIndex])
+		require.NoError(t, err)
+		b.Signature = sig
+		chain = append(chain, testROBlock(t, b))
+		parent = chain[len(chain)-1].Root()
+	}
+	return st, genesisRoot, chain
+}
+
+func testROBlock(t *testing.T, b *zondpb.SignedBeaconBlock) blocks.ROBlock {
+	sb, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	rb, err := blocks.NewROBlock(sb)
+	require.NoError(t, err)
+	return rb
+}
+
+func TestVerifier(t *testing.T) {
+	st, genesisRoot, chain := testChain(t, 1, 2, 3, 4)
+	v := newVerifier(st, bytesutil.ToBytes32(st.GenesisValidatorsRoot()))
+
+	require.NoError(t, v.verify(chain, 1, 5, chain[3].Root()))
+	require.NoError(t, v.verify(chain[:2], 1, 3, chain[1].Root()))
+	require.Equal(t, genesisRoot, chain[0].Block().ParentRoot())
+
+	require.ErrorIs(t, v.verify(chain, 1, 5, chain[2].Root()), errChainBroken)
+	require.ErrorIs(t, v.verify([]blocks.ROBlock{chain[0], chain[2]}, 1, 5, chain[2].Root()), errChainBroken)
+	require.ErrorIs(t, v.verify(chain, 2, 5, chain[3].Root()), errOutOfRange)
+	require.ErrorIs(t, v.verify([]blocks.ROBlock{chain[1], chain[0]}, 1, 5, chain[0].Root()), errUnorderedBatch)
+
+	// The signature is not part of the block root, so a block carrying the signature of another block still links
+	// to the chain.
+	pb, err := chain[1].PbPhase0Block()
+	require.NoError(t, err)
+	other, err := chain[0].PbPhase0Block()
+	require.NoError(t, err)
+	forged := util.NewBeaconBlock()
+	forged.Block = pb.Block
+	forged.Signature = other.Signature
+	bad := testROBlock(t, forged)
+	require.Equal(t, chain[1].Root(), bad.Root())
+	require.ErrorIs(t, v.verify([]blocks.ROBlock{chain[0], bad}, 1, 3, chain[1].Root()), errInvalidSignatures)
+
+	// A batch which does not link after an empty response is only blamed on the empty response when its blocks are
+	// correctly linked and signed.
+	withheld := []peer.ID{"withholding"}
+	err = v.verify(chain[:2], 1, 3, chain[3].Root())
+	require.ErrorIs(t, err, errChainBroken)
+	require.DeepEqual(t, withheld, v.culprits(err, chain[:2], 1, 3, "serving", withheld))
+	require.DeepEqual(t, []peer.ID{"serving"}, v.culprits(err, chain[:2], 1, 3, "serving", nil))
+	forgedBatch := []blocks.ROBlock{chain[0], bad}
+	err = v.verify(forgedBatch, 1, 3, chain[3].Root())
+	require.ErrorIs(t, err, errChainBroken)
+	require.DeepEqual(t, []peer.ID{"serving"}, v.culprits(err, forgedBatch, 1, 3, "serving", withheld))
+	err = v.verify(chain, 2, 5, chain[3].Root())
+	require.DeepEqual(t, []peer.ID{"serving"}, v.culprits(err, chain, 2, 5, "serving", withheld))
+}
+
+type mockServiceDB struct {
+	*mockBackfillDB
+	st     state.BeaconState
+	blocks map[[32]byte]interfaces.ReadOnlySignedBeaconBlock
+}
+
+var _ Database = &mockServiceDB{}
+
+func (db *mockServiceDB) SaveBackfillBlocks(_ context.Context, blks []blocks.ROBlock, childRoot [32]byte) error {
+	child, ok := db.blocks[childRoot]
+	if !ok || child.Block().ParentRoot() != blks[len(blks)-1].Root() {
+		return errors.New("batch is not the parent of the lowest block")
+	}
+	for _, b := range blks {
+		db.blocks[b.Root()] = b
+	}
+	return nil
+}
+
+func (db *mockServiceDB) State(context.Context, [32]byte) (state.BeaconState, error) {
+	return db.st, nil
+}
+
+// mockFetcher serves the blocks of a chain. Requests listed in empty are answered with no blocks.
+type mockFetcher struct {
+	chain []blocks.ROBlock
+	empty map[primitives.Slot]bool
+	bad   []peer.ID
+}
+
+func (f *mockFetcher) FetchBlocks(_ context.Context, start primitives.Slot, count uint64) ([]blocks.ROBlock, peer.ID, error) {
+	pid := peer.ID("good")
+	if f.empty[start] {
+		delete(f.empty, start)
+		return nil, "withholding", nil
+	}
+	var blks []blocks.ROBlock
+	for _, b := range f.chain {
+		if b.Block().Slot() >= start && b.Block().Slot() < start+primitives.Slot(count) {
+			blks = append(blks, b)
+		}
+	}
+	return blks, pid, nil
+}
+
+func (f *mockFetcher) BadResponse(pid peer.ID) {
+	f.bad = append(f.bad, pid)
+}
+
+func TestService_Backfill(t *testing.T) {
+	ctx := context.Background()
+	st, genesisRoot, chain := testChain(t, 1, 2, 4, 5, 6, 9, 10, 11, 12, 13)
+	origin := chain[len(chain)-1]
+
+	var saved [][32]byte
+	mdb := &mockServiceDB{
+		st:     st,
+		blocks: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{origin.Root(): origin},
+	}
+	mdb.mockBackfillDB = &mockBackfillDB{
+		genesisBlockRoot:          goodBlockRoot(genesisRoot),
+		originCheckpointBlockRoot: goodBlockRoot(origin.Root()),
+		backfillBlockRoot: func(ctx context.Context) ([32]byte, error) {
+			if len(saved) == 0 {
+				return genesisRoot, nil
+			}
+			return saved[len(saved)-1], nil
+		},
+		saveBackfillBlockRoot: func(ctx context.Context, root [32]byte) error {
+			saved = append(saved, root)
+			return nil
+		},
+		block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+			return mdb.blocks[root], nil
+		},
+	}
+	su := NewStatus(mdb)
+	require.NoError(t, su.Reload(ctx))
+	require.Equal(t, origin.Block().Slot(), su.EndGap())
+	require.Equal(t, false, su.Complete())
+
+	// The withheld batch below slot 9 makes the next batch fail to link, which resets backfill to the lowest
+	// backfilled block. The next batch is correctly linked and signed, so the peer which withheld its blocks is
+	// blamed instead of the peer which served it.
+	f := &mockFetcher{chain: chain[:len(chain)-1], empty: map[primitives.Slot]bool{6: true}}
+	cw := startup.NewClockSynchronizer()
+	require.NoError(t, cw.SetClock(startup.NewClock(time.Now(), bytesutil.ToBytes32(st.GenesisValidatorsRoot()))))
+	s, err := NewService(ctx, su, mdb, f, cw, WithBatchSize(3), WithBatchInterval(0))
+	require.NoError(t, err)
+	s.retryInterval = 0
+	require.NoError(t, s.run(bytesutil.ToBytes32(st.GenesisValidatorsRoot())))
+
+	require.Equal(t, true, su.Complete())
+	require.Equal(t, len(chain), len(mdb.blocks))
+	require.DeepEqual(t, []peer.ID{"withholding"}, f.bad)
+	require.Equal(t, chain[0].Root(), saved[len(saved)-1])
+
+	// A restarted node finds backfill complete.
+	su = NewStatus(mdb)
+	require.NoError(t, su.Reload(ctx))
+	require.Equal(t, true, su.Complete())
+}
+
+func TestService_BackfillPruned(t *testing.T) {
+	ctx := context.Background()
+	st, genesisRoot, chain := testChain(t, 1, 2, 4, 5, 6, 9, 10, 11, 12, 13)
+	origin := chain[len(chain)-1]
+
+	mdb := &mockServiceDB{
+		st:     st,
+		blocks: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{origin.Root(): origin},
+	}
+	mdb.mockBackfillDB = &mockBackfillDB{
+		genesisBlockRoot:          goodBlockRoot(genesisRoot),
+		originCheckpointBlockRoot: goodBlockRoot(origin.Root()),
+		backfillBlockRoot:         goodBlockRoot(genesisRoot),
+		historyPrunedSlot: func(ctx context.Context) (primitives.Slot, error) {
+			return 7, nil
+		},
+		saveBackfillBlockRoot: func(ctx context.Context, root [32]byte) error {
+			return nil
+		},
+		block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+			return mdb.blocks[root], nil
+		},
+	}
+	su := NewStatus(mdb)
+	require.NoError(t, su.Reload(ctx))
+	require.Equal(t, false, su.Complete())
+
+	// Backfill stops at the pruned slot instead of downloading the blocks that pruning deletes again.
+	f := &mockFetcher{chain: chain[:len(chain)-1]}
+	cw := startup.NewClockSynchronizer()
+	require.NoError(t, cw.SetClock(startup.NewClock(time.Now(), bytesutil.ToBytes32(st.GenesisValidatorsRoot()))))
+	s, err := NewService(ctx, su, mdb, f, cw, WithBatchSize(3), WithBatchInterval(0))
+	require.NoError(t, err)
+	require.NoError(t, s.run(bytesutil.ToBytes32(st.GenesisValidatorsRoot())))
+	require.Equal(t, 5, len(mdb.blocks))
+	for _, b := range mdb.blocks {
+		require.Equal(t, true, b.Block().Slot() >= 7)
+	}
+	require.Equal(t, 0, len(f.bad))
+}
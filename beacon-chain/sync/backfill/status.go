@@ -2,9 +2,11 @@ package backfill
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
@@ -19,55 +21,124 @@ func NewStatus(store BackfillDB) *Status {
 
 // Status provides a way to update and query the status of a backfill process that may be necessary to track when
 // a node was initialized via checkpoint sync. With checkpoint sync, there will be a gap in node history from genesis
-// until the checkpoint sync origin block. Status provides the means to update the value keeping track of the lower
-// end of the missing block range via the Advance() method, to check whether a Slot is missing from the database
-// via the SlotCovered() method, and to see the current StartGap() and EndGap().
+// until the checkpoint sync origin block. Backfill fills the gap backwards from the origin block, so Status provides
+// the means to update the value keeping track of the upper end of the missing block range via the Advance() method,
+// to check whether a Slot is missing from the database via the SlotCovered() method, and to see the current
+// StartGap() and EndGap(). When history pruning is enabled, the blocks below PrunedSlot() are missing from the
+// database as well, except for the genesis block. Status is safe for concurrent use.
 type Status struct {
+	sync.RWMutex
 	start       primitives.Slot
 	end         primitives.Slot
+	pruned      primitives.Slot
 	store       BackfillDB
 	genesisSync bool
 }
 
 // SlotCovered uses StartGap() and EndGap() to determine if the given slot is covered by the current chain history.
 // If the slot is <= StartGap(), or >= EndGap(), the result is true.
-// If the slot is between StartGap() and EndGap(), the result is false.
+// If the slot is between StartGap() and EndGap(), or between genesis and PrunedSlot(), the result is false.
 func (s *Status) SlotCovered(sl primitives.Slot) bool {
+	return s.RangeCovered(sl, sl)
+}
+
+// RangeCovered determines if every slot in the range [start, end] is covered by the current chain history.
+func (s *Status) RangeCovered(start, end primitives.Slot) bool {
+	s.RLock()
+	defer s.RUnlock()
+	if s.rangePruned(start, end) {
+		return false
+	}
 	// short circuit if the node was synced from genesis
 	if s.genesisSync {
 		return true
 	}
-	if s.StartGap() < sl && sl < s.EndGap() {
+	if start < s.end && s.start < end && s.start+1 < s.end {
 		return false
 	}
 	return true
 }
 
+// RangePruned determines if any slot in the range [start, end], other than genesis, is below PrunedSlot().
+func (s *Status) RangePruned(start, end primitives.Slot) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.rangePruned(start, end)
+}
+
+func (s *Status) rangePruned(start, end primitives.Slot) bool {
+	return end > params.BeaconConfig().GenesisSlot && start < s.pruned
+}
+
 // StartGap returns the slot at the beginning of the range that needs to be backfilled.
 func (s *Status) StartGap() primitives.Slot {
+	s.RLock()
+	defer s.RUnlock()
 	return s.start
 }
 
 // EndGap returns the slot at the end of the range that needs to be backfilled.
 func (s *Status) EndGap() primitives.Slot {
+	s.RLock()
+	defer s.RUnlock()
 	return s.end
 }
 
+// PrunedSlot returns the slot below which the blocks were deleted by history pruning, except for the genesis block.
+func (s *Status) PrunedSlot() primitives.Slot {
+	s.RLock()
+	defer s.RUnlock()
+	return s.pruned
+}
+
+// Complete returns true if there is no block left to backfill between genesis and the origin checkpoint, either
+// because the node was synced from genesis, because backfill has finished, or because the rest of the gap is
+// below PrunedSlot().
+func (s *Status) Complete() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.genesisSync || s.end <= s.start || s.end <= s.pruned
+}
+
+// MarkPruned records that history pruning deleted the blocks below the given slot.
+func (s *Status) MarkPruned(slot primitives.Slot) {
+	s.Lock()
+	defer s.Unlock()
+	if slot > s.pruned {
+		s.pruned = slot
+	}
+}
+
 var ErrAdvancePastOrigin = errors.New("cannot advance backfill Status beyond the origin checkpoint slot")
 
-// Advance advances the backfill position to the given slot & root.
+// Advance moves the backfill position down to the given slot, recording root as the lowest backfilled block.
+// Every block from upTo to the origin checkpoint must already be saved. Once the parent of the lowest backfilled
+// block is the genesis block, backfill is completed by advancing to StartGap().
 // It updates the backfill block root entry in the database,
 // and also updates the Status value's copy of the backfill position slot.
 func (s *Status) Advance(ctx context.Context, upTo primitives.Slot, root [32]byte) error {
+	s.Lock()
+	defer s.Unlock()
 	if upTo > s.end {
-		return errors.Wrapf(ErrAdvancePastOrigin, "advance slot=%d, origin slot=%d", upTo, s.end)
+		return errors.Wrapf(ErrAdvancePastOrigin, "advance slot=%d, backfill slot=%d", upTo, s.end)
+	}
+	if err := s.store.SaveBackfillBlockRoot(ctx, root); err != nil {
+		return err
 	}
-	s.start = upTo
-	return s.store.SaveBackfillBlockRoot(ctx, root)
+	s.end = upTo
+	return nil
 }
 
 // Reload queries the database for backfill status, initializing the internal data and validating the database state.
+// The backfill block root points at the genesis block until the first batch of blocks has been backfilled.
 func (s *Status) Reload(ctx context.Context) error {
+	s.Lock()
+	defer s.Unlock()
+	pruned, err := s.store.HistoryPrunedSlot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error retrieving history pruned slot")
+	}
+	s.pruned = pruned
 	cpRoot, err := s.store.OriginCheckpointBlockRoot(ctx)
 	if err != nil {
 		// mark genesis sync and short circuit further lookups
@@ -82,11 +153,15 @@ func (s *Status) Reload(ctx context.Context) error {
 		return errors.Wrapf(err, "error retrieving block for origin checkpoint root=%#x", cpRoot)
 	}
 	if err := blocks.BeaconBlockIsNil(cpBlock); err != nil {
+		if s.pruned > 0 {
+			// the origin checkpoint block was pruned, along with the whole gap below it
+			return nil
+		}
 		return err
 	}
 	s.end = cpBlock.Block().Slot()
 
-	_, err = s.store.GenesisBlockRoot(ctx)
+	genesisRoot, err := s.store.GenesisBlockRoot(ctx)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFoundGenesisBlockRoot) {
 			return errors.Wrap(err, "genesis block root required for checkpoint sync")
@@ -101,14 +176,27 @@ func (s *Status) Reload(ctx context.Context) error {
 		}
 		return err
 	}
+	if bfRoot == genesisRoot {
+		// backfill has not been initiated, the gap ends at the origin checkpoint block
+		return nil
+	}
 	bfBlock, err := s.store.Block(ctx, bfRoot)
 	if err != nil {
 		return errors.Wrapf(err, "error retrieving block for backfill root=%#x", bfRoot)
 	}
 	if err := blocks.BeaconBlockIsNil(bfBlock); err != nil {
+		if s.pruned > 0 {
+			// the lowest backfilled block was pruned, so the rest of the gap is below the pruned slot
+			s.end = s.start
+			return nil
+		}
 		return err
 	}
-	s.start = bfBlock.Block().Slot()
+	s.end = bfBlock.Block().Slot()
+	if bfBlock.Block().ParentRoot() == genesisRoot {
+		// the lowest backfilled block is a child of genesis, so the gap has been closed
+		s.end = s.start
+	}
 	return nil
 }
 
@@ -118,5 +206,6 @@ type BackfillDB interface {
 	GenesisBlockRoot(ctx context.Context) ([32]byte, error)
 	OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	BackfillBlockRoot(ctx context.Context) ([32]byte, error)
+	HistoryPrunedSlot(ctx context.Context) (primitives.Slot, error)
 	Block(ctx context.Context, blockRoot [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error)
 }
@@ -9,7 +9,6 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 
 	"github.com/pkg/errors"
-	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
@@ -22,6 +21,7 @@ type mockBackfillDB struct {
 	genesisBlockRoot          func(ctx context.Context) ([32]byte, error)
 	originCheckpointBlockRoot func(ctx context.Context) ([32]byte, error)
 	backfillBlockRoot         func(ctx context.Context) ([32]byte, error)
+	historyPrunedSlot         func(ctx context.Context) (primitives.Slot, error)
 	block                     func(ctx context.Context, blockRoot [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error)
 }
 
@@ -55,6 +55,14 @@ func (db *mockBackfillDB) BackfillBlockRoot(ctx context.Context) ([32]byte, erro
 	return [32]byte{}, errEmptyMockDBMethod
 }
 
+// HistoryPrunedSlot defaults to a history that was never pruned.
+func (db *mockBackfillDB) HistoryPrunedSlot(ctx context.Context) (primitives.Slot, error) {
+	if db.historyPrunedSlot != nil {
+		return db.historyPrunedSlot(ctx)
+	}
+	return 0, nil
+}
+
 func (db *mockBackfillDB) Block(ctx context.Context, blockRoot [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
 	if db.block != nil {
 		return db.block(ctx, blockRoot)
@@ -105,11 +113,113 @@ func TestSlotCovered(t *testing.T) {
 			slot:   100,
 			result: true,
 		},
+		{
+			name:   "below pruned false",
+			status: &Status{genesisSync: true, pruned: 10},
+			slot:   9,
+			result: false,
+		},
+		{
+			name:   "genesis below pruned true",
+			status: &Status{genesisSync: true, pruned: 10},
+			slot:   0,
+			result: true,
+		},
+		{
+			name:   "equal pruned true",
+			status: &Status{genesisSync: true, pruned: 10},
+			slot:   10,
+			result: true,
+		},
 	}
 	for _, c := range cases {
 		result := c.status.SlotCovered(c.slot)
-		require.Equal(t, c.result, result)
+		require.Equal(t, c.result, result, c.name)
+	}
+}
+
+func TestRangeCovered(t *testing.T) {
+	cases := []struct {
+		name       string
+		start, end primitives.Slot
+		status     *Status
+		result     bool
+	}{
+		{
+			name:   "below gap true",
+			status: &Status{start: 5, end: 10},
+			start:  0,
+			end:    5,
+			result: true,
+		},
+		{
+			name:   "above gap true",
+			status: &Status{start: 5, end: 10},
+			start:  10,
+			end:    20,
+			result: true,
+		},
+		{
+			name:   "overlaps gap start false",
+			status: &Status{start: 5, end: 10},
+			start:  0,
+			end:    6,
+			result: false,
+		},
+		{
+			name:   "spans gap false",
+			status: &Status{start: 5, end: 10},
+			start:  0,
+			end:    20,
+			result: false,
+		},
+		{
+			name:   "empty gap true",
+			status: &Status{start: 5, end: 6},
+			start:  0,
+			end:    20,
+			result: true,
+		},
+		{
+			name:   "overlaps pruned false",
+			status: &Status{genesisSync: true, pruned: 10},
+			start:  0,
+			end:    10,
+			result: false,
+		},
+		{
+			name:   "above pruned true",
+			status: &Status{genesisSync: true, pruned: 10},
+			start:  10,
+			end:    20,
+			result: true,
+		},
 	}
+	for _, c := range cases {
+		require.Equal(t, c.result, c.status.RangeCovered(c.start, c.end), c.name)
+	}
+}
+
+func TestRangePruned(t *testing.T) {
+	s := &Status{start: 5, end: 10, pruned: 3}
+	require.Equal(t, false, s.RangePruned(0, 0), "genesis")
+	require.Equal(t, true, s.RangePruned(0, 3))
+	require.Equal(t, true, s.RangePruned(2, 20))
+	require.Equal(t, false, s.RangePruned(3, 20), "checkpoint sync gap")
+	require.Equal(t, false, (&Status{start: 5, end: 10}).RangePruned(0, 20))
+}
+
+func TestComplete(t *testing.T) {
+	require.Equal(t, true, (&Status{genesisSync: true}).Complete())
+	require.Equal(t, true, (&Status{start: 0, end: 0}).Complete())
+	require.Equal(t, false, (&Status{start: 0, end: 10}).Complete())
+	require.Equal(t, true, (&Status{start: 0, end: 10, pruned: 10}).Complete())
+
+	s := &Status{start: 0, end: 10}
+	s.MarkPruned(12)
+	s.MarkPruned(5)
+	require.Equal(t, primitives.Slot(12), s.PrunedSlot())
+	require.Equal(t, true, s.Complete())
 }
 
 func TestAdvance(t *testing.T) {
@@ -126,14 +236,20 @@ func TestAdvance(t *testing.T) {
 	copy(root[:], []byte{0x23, 0x23})
 	require.NoError(t, s.Advance(ctx, 90, root))
 	require.Equal(t, root, saveBackfillBuf[0])
-	not := s.SlotCovered(95)
-	require.Equal(t, false, not)
+	require.Equal(t, true, s.SlotCovered(95))
+	require.Equal(t, false, s.SlotCovered(85))
+	require.Equal(t, false, s.Complete())
 
 	// this should still be len 1 after failing to advance
 	require.Equal(t, 1, len(saveBackfillBuf))
 	require.ErrorIs(t, s.Advance(ctx, s.end+1, root), ErrAdvancePastOrigin)
 	// this has an element in it from the previous test, there shouldn't be an additional one
 	require.Equal(t, 1, len(saveBackfillBuf))
+
+	// advancing to the start of the gap completes backfill
+	require.NoError(t, s.Advance(ctx, s.start, root))
+	require.Equal(t, true, s.SlotCovered(85))
+	require.Equal(t, true, s.Complete())
 }
 
 func goodBlockRoot(root [32]byte) func(ctx context.Context) ([32]byte, error) {
@@ -163,10 +279,20 @@ func TestReload(t *testing.T) {
 
 	backfillSlot := primitives.Slot(50)
 	var backfillRoot [32]byte
-	copy(originRoot[:], []byte{0x02})
+	copy(backfillRoot[:], []byte{0x02})
 	backfillBlock, err := setupTestBlock(backfillSlot)
 	require.NoError(t, err)
 
+	var genesisRoot [32]byte
+	copy(genesisRoot[:], []byte{0x03})
+	genesisChildRaw := util.NewBeaconBlock()
+	genesisChildRaw.Block.Slot = 1
+	genesisChildRaw.Block.ParentRoot = genesisRoot[:]
+	genesisChild, err := blocks.NewSignedBeaconBlock(genesisChildRaw)
+	require.NoError(t, err)
+	var genesisChildRoot [32]byte
+	copy(genesisChildRoot[:], []byte{0x04})
+
 	cases := []struct {
 		name     string
 		db       BackfillDB
@@ -320,7 +446,7 @@ func TestReload(t *testing.T) {
 		{
 			name: "complete happy path",
 			db: &mockBackfillDB{
-				genesisBlockRoot:          goodBlockRoot(params.BeaconConfig().ZeroHash),
+				genesisBlockRoot:          goodBlockRoot(genesisRoot),
 				originCheckpointBlockRoot: goodBlockRoot(originRoot),
 				block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
 					switch root {
@@ -334,7 +460,83 @@ func TestReload(t *testing.T) {
 				backfillBlockRoot: goodBlockRoot(backfillRoot),
 			},
 			err:      derp,
-			expected: &Status{genesisSync: false, start: backfillSlot, end: originSlot},
+			expected: &Status{genesisSync: false, start: 0, end: backfillSlot},
+		},
+		{
+			name: "backfill not initiated",
+			db: &mockBackfillDB{
+				genesisBlockRoot:          goodBlockRoot(genesisRoot),
+				originCheckpointBlockRoot: goodBlockRoot(originRoot),
+				block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+					switch root {
+					case originRoot:
+						return originBlock, nil
+					}
+					return nil, errors.New("not derp")
+				},
+				backfillBlockRoot: goodBlockRoot(genesisRoot),
+			},
+			expected: &Status{genesisSync: false, start: 0, end: originSlot},
+		},
+		{
+			name: "backfill complete",
+			db: &mockBackfillDB{
+				genesisBlockRoot:          goodBlockRoot(genesisRoot),
+				originCheckpointBlockRoot: goodBlockRoot(originRoot),
+				block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+					switch root {
+					case originRoot:
+						return originBlock, nil
+					case genesisChildRoot:
+						return genesisChild, nil
+					}
+					return nil, errors.New("not derp")
+				},
+				backfillBlockRoot: goodBlockRoot(genesisChildRoot),
+			},
+			expected: &Status{genesisSync: false, start: 0, end: 0},
+		},
+		{
+			name: "origin block pruned",
+			db: &mockBackfillDB{
+				genesisBlockRoot:          goodBlockRoot(genesisRoot),
+				originCheckpointBlockRoot: goodBlockRoot(originRoot),
+				historyPrunedSlot: func(ctx context.Context) (primitives.Slot, error) {
+					return originSlot + 1, nil
+				},
+				block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+					return nil, nil
+				},
+			},
+			expected: &Status{genesisSync: false, start: 0, end: 0, pruned: originSlot + 1},
+		},
+		{
+			name: "backfill block pruned",
+			db: &mockBackfillDB{
+				genesisBlockRoot:          goodBlockRoot(genesisRoot),
+				originCheckpointBlockRoot: goodBlockRoot(originRoot),
+				historyPrunedSlot: func(ctx context.Context) (primitives.Slot, error) {
+					return backfillSlot + 1, nil
+				},
+				block: func(ctx context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+					switch root {
+					case originRoot:
+						return originBlock, nil
+					}
+					return nil, nil
+				},
+				backfillBlockRoot: goodBlockRoot(backfillRoot),
+			},
+			expected: &Status{genesisSync: false, start: 0, end: 0, pruned: backfillSlot + 1},
+		},
+		{
+			name: "history pruned slot error",
+			db: &mockBackfillDB{
+				historyPrunedSlot: func(ctx context.Context) (primitives.Slot, error) {
+					return 0, derp
+				},
+			},
+			err: derp,
 		},
 	}
 
@@ -354,5 +556,6 @@ func TestReload(t *testing.T) {
 		require.Equal(t, c.expected.genesisSync, s.genesisSync)
 		require.Equal(t, c.expected.start, s.start)
 		require.Equal(t, c.expected.end, s.end)
+		require.Equal(t, c.expected.pruned, s.pruned)
 	}
 }
@@ -0,0 +1,134 @@
+package backfill
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+var (
+	errChainBroken       = errors.New("backfill batch does not link to the lowest backfilled block")
+	errUnorderedBatch    = errors.New("backfill batch blocks are not in increasing slot order")
+	errOutOfRange        = errors.New("backfill batch block is outside the requested slot range")
+	errInvalidSignatures = errors.New("backfill batch contains an invalid proposer signature")
+)
+
+// publicKeyProvider looks up the public key of a validator. The origin checkpoint state satisfies it, since every
+// validator which proposed a block before the origin checkpoint is still part of its registry.
+type publicKeyProvider interface {
+	PublicKeyAtIndex(idx primitives.ValidatorIndex) (dilithium.PublicKey, error)
+}
+
+// verifier checks that a batch of blocks extends the backfilled chain downwards and that every block was signed
+// by its proposer.
+type verifier struct {
+	keys    publicKeyProvider
+	gvr     [32]byte
+	domains map[[4]byte][]byte
+}
+
+func newVerifier(keys publicKeyProvider, genesisValidatorsRoot [32]byte) *verifier {
+	return &verifier{
+		keys:    keys,
+		gvr:     genesisValidatorsRoot,
+		domains: make(map[[4]byte][]byte),
+	}
+}
+
+// verify checks a batch of blocks requested for the slots in [start, end), sorted by increasing slot. The highest
+// block of the batch must have the given root, which is the parent root of the lowest backfilled block, and every
+// other block must be the parent of the block above it. The proposer signatures of the whole batch are verified
+// at once.
+func (v *verifier) verify(blks []blocks.ROBlock, start, end primitives.Slot, expected [32]byte) error {
+	set := dilithium.NewSet()
+	for i := len(blks) - 1; i >= 0; i-- {
+		b := blks[i]
+		slot := b.Block().Slot()
+		if slot < start || slot >= end {
+			return errors.Wrapf(errOutOfRange, "slot=%d, range=[%d, %d)", slot, start, end)
+		}
+		if i > 0 && blks[i-1].Block().Slot() >= slot {
+			return errors.Wrapf(errUnorderedBatch, "slot=%d follows slot=%d", blks[i-1].Block().Slot(), slot)
+		}
+		if b.Root() != expected {
+			return errors.Wrapf(errChainBroken, "block root=%#x at slot=%d, expected root=%#x", b.Root(), slot, expected)
+		}
+		expected = b.Block().ParentRoot()
+		batch, err := v.signatureBatch(b)
+		if err != nil {
+			return err
+		}
+		set.Join(batch)
+	}
+	if len(set.Signatures) == 0 {
+		return nil
+	}
+	valid, err := set.VerifyVerbosely()
+	if err != nil {
+		return errors.Wrap(errInvalidSignatures, err.Error())
+	}
+	if !valid {
+		return errInvalidSignatures
+	}
+	return nil
+}
+
+func (v *verifier) signatureBatch(b blocks.ROBlock) (*dilithium.SignatureBatch, error) {
+	blk := b.Block()
+	pk, err := v.keys.PublicKeyAtIndex(blk.ProposerIndex())
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get public key of proposer %d", blk.ProposerIndex())
+	}
+	domain, err := v.domain(slots.ToEpoch(blk.Slot()))
+	if err != nil {
+		return nil, err
+	}
+	sig := b.Signature()
+	batch, err := signing.BlockSignatureBatchWithKey(pk, sig[:], domain, blk.HashTreeRoot)
+	if err != nil {
+		return nil, err
+	}
+	batch.Descriptions[0] = fmt.Sprintf("%s at slot %d", batch.Descriptions[0], blk.Slot())
+	return batch, nil
+}
+
+// domain returns the proposer domain of the fork active at the given epoch.
+func (v *verifier) domain(epoch primitives.Epoch) ([]byte, error) {
+	fork, err := forks.Fork(epoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get fork at epoch %d", epoch)
+	}
+	version := bytesutil.ToBytes4(fork.CurrentVersion)
+	if d, ok := v.domains[version]; ok {
+		return d, nil
+	}
+	d, err := signing.ComputeDomain(params.BeaconConfig().DomainBeaconProposer, fork.CurrentVersion, v.gvr[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not compute proposer domain of fork version %#x", version)
+	}
+	v.domains[version] = d
+	return d, nil
+}
+
+// culprits returns the peers to blame for a batch which failed verification with the given error. A batch which
+// does not link to the lowest backfilled block after empty responses is blamed on the peers which returned them
+// when its blocks are correctly linked and signed, as it is then part of the chain and the empty responses
+// withheld the blocks above it. Any other failure is blamed on the peer which served the batch.
+func (v *verifier) culprits(err error, blks []blocks.ROBlock, start, end primitives.Slot, pid peer.ID, withheld []peer.ID) []peer.ID {
+	if !errors.Is(err, errChainBroken) || len(withheld) == 0 {
+		return []peer.ID{pid}
+	}
+	if v.verify(blks, start, end, blks[len(blks)-1].Root()) != nil {
+		return []peer.ID{pid}
+	}
+	return withheld
+}
@@ -37,12 +37,12 @@ func (s *Service) verifierRoutine() {
 		case sig := <-s.signatureChan:
 			verifierBatch = append(verifierBatch, sig)
 			if len(verifierBatch) >= verifierLimit {
-				verifyBatch(verifierBatch)
+				s.verifyBatch(verifierBatch)
 				verifierBatch = []*signatureVerifier{}
 			}
 		case <-ticker.C:
 			if len(verifierBatch) > 0 {
-				verifyBatch(verifierBatch)
+				s.verifyBatch(verifierBatch)
 				verifierBatch = []*signatureVerifier{}
 			}
 		}
@@ -53,6 +53,12 @@ func (s *Service) validateWithBatchVerifier(ctx context.Context, message string,
 	ctx, span := trace.StartSpan(ctx, "sync.validateWithBatchVerifier")
 	defer span.End()
 
+	// Attestation signatures which were already verified are not verified again.
+	set = s.cfg.verifiedSigCache.FilterBatch(set)
+	if len(set.Signatures) == 0 {
+		return pubsub.ValidationAccept, nil
+	}
+
 	resChan := make(chan error)
 	verificationSet := &signatureVerifier{set: set.Copy(), resChan: resChan}
 	s.signatureChan <- verificationSet
@@ -63,8 +69,9 @@ func (s *Service) validateWithBatchVerifier(ctx context.Context, message string,
 	// of each signature set.
 	if resErr != nil {
 		log.WithError(resErr).Tracef("Could not perform batch verification of %s", message)
-		verified, err := set.Verify()
+		verified, err := set.VerifyVerboselyWithContext(ctx)
 		if err != nil {
+			logInvalidSignatures(message, err)
 			verErr := errors.Wrapf(err, "Could not verify %s", message)
 			tracing.AnnotateError(span, verErr)
 			return pubsub.ValidationReject, verErr
@@ -78,7 +85,7 @@ func (s *Service) validateWithBatchVerifier(ctx context.Context, message string,
 	return pubsub.ValidationAccept, nil
 }
 
-func verifyBatch(verifierBatch []*signatureVerifier) {
+func (s *Service) verifyBatch(verifierBatch []*signatureVerifier) {
 	if len(verifierBatch) == 0 {
 		return
 	}
@@ -91,7 +98,11 @@ func verifyBatch(verifierBatch []*signatureVerifier) {
 
 	aggSet, verificationErr = performBatchAggregation(aggSet)
 	if verificationErr == nil {
-		verified, err := aggSet.Verify()
+		start := time.Now()
+		verified, err := aggSet.VerifyWithContext(s.ctx)
+		if err == nil && verified {
+			s.cfg.verifiedSigCache.RecordVerificationTime(time.Since(start), aggSet)
+		}
 		switch {
 		case err != nil:
 			verificationErr = err
@@ -122,3 +133,14 @@ func performBatchAggregation(aggSet *dilithium.SignatureBatch) (*dilithium.Signa
 	}
 	return aggSet, nil
 }
+
+// logInvalidSignatures logs every invalid signature found in a gossip message.
+func logInvalidSignatures(message string, err error) {
+	var sigErr *dilithium.InvalidSignaturesError
+	if !errors.As(err, &sigErr) {
+		return
+	}
+	for _, sig := range sigErr.Invalid {
+		log.WithFields(sig.Fields()).WithField("gossipMessage", message).Debug("Invalid signature in gossip message")
+	}
+}
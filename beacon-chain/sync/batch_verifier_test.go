@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/theQRL/qrysm/v4/beacon-chain/cache"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/testing/assert"
@@ -33,6 +34,7 @@ func TestValidateWithBatchVerifier(t *testing.T) {
 		message       string
 		set           *dilithium.SignatureBatch
 		preFilledSets []*dilithium.SignatureBatch
+		verifiedSets  []*dilithium.SignatureBatch
 		want          pubsub.ValidationResult
 	}{
 		{
@@ -61,6 +63,13 @@ func TestValidateWithBatchVerifier(t *testing.T) {
 			preFilledSets: []*dilithium.SignatureBatch{validSet},
 			want:          pubsub.ValidationReject,
 		},
+		{
+			name:         "already verified set",
+			message:      "random",
+			set:          validSet,
+			verifiedSets: []*dilithium.SignatureBatch{validSet},
+			want:         pubsub.ValidationAccept,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -68,8 +77,12 @@ func TestValidateWithBatchVerifier(t *testing.T) {
 			svc := &Service{
 				ctx:           ctx,
 				cancel:        cancel,
+				cfg:           &config{verifiedSigCache: cache.NewVerifiedSignatureCache()},
 				signatureChan: make(chan *signatureVerifier, verifierLimit),
 			}
+			for _, st := range tt.verifiedSets {
+				svc.cfg.verifiedSigCache.AddBatch(st)
+			}
 			go svc.verifierRoutine()
 			for _, st := range tt.preFilledSets {
 				svc.signatureChan <- &signatureVerifier{set: st, resChan: make(chan error, 10)}
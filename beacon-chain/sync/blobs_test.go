@@ -24,6 +24,7 @@ import (
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/network/forks"
 	enginev1 "github.com/theQRL/qrysm/v4/proto/engine/v1"
+	zond "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	zondpb "github.com/theQRL/qrysm/v4/proto/zond/v1"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
@@ -198,12 +199,14 @@ func (c *blobsTestCase) setup(t *testing.T) (*Service, []*zondpb.BlobSidecar, fu
 
 	client := p2ptest.NewTestP2P(t)
 	s := &Service{
-		cfg:         &config{p2p: client, chain: c.chain, clock: clock, beaconDB: d},
+		cfg:         &config{p2p: client, chain: c.chain, clock: clock, beaconDB: d, blobStorage: db.SetupBlobStorage(t)},
 		rateLimiter: newRateLimiter(client),
 	}
 
-	byRootRate := params.BeaconNetworkConfig().MaxRequestBlobSidecars * fieldparams.MaxBlobsPerBlock
-	byRangeRate := params.BeaconNetworkConfig().MaxRequestBlobSidecars * fieldparams.MaxBlobsPerBlock
+	// The rate limiter charges the serialized size of the sidecars served.
+	sidecarSize := uint64((&zond.BlobSidecar{}).SizeSSZ())
+	byRootRate := params.BeaconNetworkConfig().MaxRequestBlobSidecars * fieldparams.MaxBlobsPerBlock * sidecarSize
+	byRangeRate := params.BeaconNetworkConfig().MaxRequestBlobSidecars * fieldparams.MaxBlobsPerBlock * sidecarSize
 	s.setRateCollector(p2p.RPCBlobSidecarsByRootTopicV1, leakybucket.NewCollector(0.000001, int64(byRootRate), time.Second, false))
 	s.setRateCollector(p2p.RPCBlobSidecarsByRangeTopicV1, leakybucket.NewCollector(0.000001, int64(byRangeRate), time.Second, false))
 
@@ -233,7 +236,7 @@ func (c *blobsTestCase) run(t *testing.T) {
 		}
 	}
 	for _, blobSidecars := range m {
-		require.NoError(t, s.cfg.beaconDB.SaveBlobSidecar(context.Background(), blobSidecars))
+		require.NoError(t, s.cfg.blobStorage.SaveBlobSidecar(context.Background(), blobSidecars))
 	}
 	if c.total != nil {
 		require.Equal(t, *c.total, len(expect))
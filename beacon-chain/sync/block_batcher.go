@@ -78,7 +78,7 @@ func (bb *blockRangeBatcher) next(ctx context.Context, stream libp2pcore.Stream)
 	if !more {
 		return blockBatch{}, false
 	}
-	if err := bb.limiter.validateRequest(stream, bb.size); err != nil {
+	if err := bb.limiter.validateResponse(stream); err != nil {
 		return blockBatch{err: errors.Wrap(err, "throttled by rate limiter")}, false
 	}
 
@@ -111,8 +111,6 @@ func (bb *blockRangeBatcher) next(ctx context.Context, stream libp2pcore.Stream)
 	// Filter and sort our retrieved blocks, so that we only return valid sets of blocks.
 	nb.lin, nb.nonlin, nb.err = bb.cf.filter(ctx, rob)
 
-	// Decrease allowed blocks capacity by the number of streamed blocks.
-	bb.limiter.add(stream, int64(1+nb.end.SubSlot(nb.start)))
 	bb.current = &nb
 	return *bb.current, true
 }
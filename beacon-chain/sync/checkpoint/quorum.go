@@ -0,0 +1,262 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/api/client/beacon"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state"
+	"github.com/theQRL/qrysm/v4/config/params"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+var (
+	errNoQuorum                      = errors.New("checkpoint sync providers did not reach a quorum on the finalized checkpoint")
+	errConflictingQuorums            = errors.New("checkpoint sync providers reached a quorum on conflicting finalized checkpoints")
+	errWeakSubjectivityCheckMismatch = errors.New("finalized checkpoint does not descend from the weak subjectivity checkpoint")
+)
+
+const (
+	// providerTimeout bounds the time a provider has to report its finalized checkpoint, so that a single slow
+	// provider does not hold back the others.
+	providerTimeout = 30 * time.Second
+	// quorumAttempts is the number of times the providers are polled before giving up on a quorum. Providers which
+	// straddle an epoch transition may briefly report different finalized checkpoints, polling again once they have
+	// all processed the transition lets them agree.
+	quorumAttempts = 3
+)
+
+// QuorumInitializer initializes the beacon node using checkpoint sync from several beacon node apis. The finalized
+// checkpoint of every provider is requested, and the node only starts from a checkpoint reported by a quorum of the
+// providers. The origin state and block are then downloaded from any of the agreeing providers and verified against
+// the agreed checkpoint root, so that no single provider is trusted.
+type QuorumInitializer struct {
+	providers  []*provider
+	quorum     int
+	ws         *zondpb.Checkpoint
+	timeout    time.Duration
+	retryDelay time.Duration
+	finalized  func(ctx context.Context, p *provider) (*zondpb.Checkpoint, error)
+}
+
+type provider struct {
+	host string
+	c    *beacon.Client
+}
+
+// NewQuorumInitializer creates a QuorumInitializer for the given beacon node hosts. A quorum of zero requires a
+// majority of the providers to agree. The weak subjectivity checkpoint is optional, when set the agreed checkpoint
+// must descend from it.
+func NewQuorumInitializer(hosts []string, quorum int, ws *zondpb.Checkpoint) (*QuorumInitializer, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no checkpoint sync providers")
+	}
+	if quorum == 0 {
+		quorum = len(hosts)/2 + 1
+	}
+	if quorum < 0 || quorum > len(hosts) {
+		return nil, fmt.Errorf("checkpoint sync quorum of %d is not between 1 and the number of providers (%d)", quorum, len(hosts))
+	}
+	providers := make([]*provider, len(hosts))
+	for i, h := range hosts {
+		c, err := beacon.NewClient(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", h)
+		}
+		providers[i] = &provider{host: h, c: c}
+	}
+	return &QuorumInitializer{
+		providers:  providers,
+		quorum:     quorum,
+		ws:         ws,
+		timeout:    providerTimeout,
+		retryDelay: time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second,
+		finalized: func(ctx context.Context, p *provider) (*zondpb.Checkpoint, error) {
+			return p.c.GetFinalizedCheckpoint(ctx, beacon.IdHead)
+		},
+	}, nil
+}
+
+// Initialize agrees on a finalized checkpoint with the providers, downloads the origin state and block of the
+// checkpoint and initializes database records to prepare the node to begin syncing from that point.
+func (qi *QuorumInitializer) Initialize(ctx context.Context, d db.Database) error {
+	origin, err := d.OriginCheckpointBlockRoot(ctx)
+	if err == nil && origin != params.BeaconConfig().ZeroHash {
+		log.Warnf("origin checkpoint root %#x found in db, ignoring checkpoint sync flags", origin)
+		return nil
+	} else {
+		if !errors.Is(err, db.ErrNotFound) {
+			return errors.Wrap(err, "error while checking database for origin root")
+		}
+	}
+
+	cp, agreeing, err := qi.agree(ctx)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"epoch":     cp.Epoch,
+		"root":      fmt.Sprintf("%#x", cp.Root),
+		"providers": len(agreeing),
+		"quorum":    qi.quorum,
+	}).Info("Checkpoint sync providers agreed on the finalized checkpoint")
+	if qi.ws != nil && qi.ws.Epoch > cp.Epoch {
+		return errors.Wrapf(errWeakSubjectivityCheckMismatch, "weak subjectivity epoch %d is after the finalized epoch %d", qi.ws.Epoch, cp.Epoch)
+	}
+
+	for _, p := range agreeing {
+		od, err := beacon.DownloadCheckpointData(ctx, p.c, cp)
+		if err != nil {
+			log.WithError(err).WithField("provider", p.host).Warn("Could not download checkpoint sync data")
+			continue
+		}
+		if err := verifyWeakSubjectivity(od.State(), cp, qi.ws); err != nil {
+			return err
+		}
+		return d.SaveOrigin(ctx, od.StateBytes(), od.BlockBytes())
+	}
+	return errors.Errorf("could not download checkpoint sync data from any of the %d agreeing providers", len(agreeing))
+}
+
+var _ Initializer = &QuorumInitializer{}
+
+// agree polls the providers until a quorum of them reports the same finalized checkpoint. The providers are polled
+// again when there is no quorum, as they disagree for a short time around an epoch transition. Conflicting quorums
+// are not retried.
+func (qi *QuorumInitializer) agree(ctx context.Context) (*zondpb.Checkpoint, []*provider, error) {
+	for attempt := 1; ; attempt++ {
+		cp, agreeing, err := agree(qi.poll(ctx), qi.quorum)
+		if err == nil || !errors.Is(err, errNoQuorum) || attempt == quorumAttempts {
+			return cp, agreeing, err
+		}
+		log.WithError(err).WithField("attempt", attempt).Warn("Checkpoint sync providers did not reach a quorum, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(qi.retryDelay):
+		}
+	}
+}
+
+// poll requests the finalized checkpoint of every provider in parallel. Each provider has at most the timeout of the
+// initializer to answer. The votes are in the order of the providers.
+func (qi *QuorumInitializer) poll(ctx context.Context) []*vote {
+	votes := make([]*vote, len(qi.providers))
+	var wg sync.WaitGroup
+	for i, p := range qi.providers {
+		wg.Add(1)
+		go func(i int, p *provider) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, qi.timeout)
+			defer cancel()
+			cp, err := qi.finalized(pctx, p)
+			votes[i] = &vote{p: p, cp: cp, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+	return votes
+}
+
+// vote is the finalized checkpoint reported by a provider, or the error which prevented to get it.
+type vote struct {
+	p   *provider
+	cp  *zondpb.Checkpoint
+	err error
+}
+
+// agree returns the checkpoint reported by at least quorum providers, along with these providers. The
+// error lists the checkpoint reported by every provider when there is no single such checkpoint.
+func agree(votes []*vote, quorum int) (*zondpb.Checkpoint, []*provider, error) {
+	type group struct {
+		cp        *zondpb.Checkpoint
+		providers []*provider
+	}
+	var groups []*group
+	for _, v := range votes {
+		if v.err != nil || v.cp == nil {
+			continue
+		}
+		var g *group
+		for _, other := range groups {
+			if other.cp.Epoch == v.cp.Epoch && bytes.Equal(other.cp.Root, v.cp.Root) {
+				g = other
+				break
+			}
+		}
+		if g == nil {
+			g = &group{cp: v.cp}
+			groups = append(groups, g)
+		}
+		g.providers = append(g.providers, v.p)
+	}
+
+	var agreed *group
+	for _, g := range groups {
+		if len(g.providers) < quorum {
+			continue
+		}
+		if agreed != nil {
+			return nil, nil, errors.Wrapf(errConflictingQuorums, "quorum=%d\n%s", quorum, report(votes))
+		}
+		agreed = g
+	}
+	if agreed == nil {
+		return nil, nil, errors.Wrapf(errNoQuorum, "quorum=%d\n%s", quorum, report(votes))
+	}
+	return agreed.cp, agreed.providers, nil
+}
+
+// report describes the finalized checkpoint reported by each provider, one provider per line.
+func report(votes []*vote) string {
+	lines := make([]string, len(votes))
+	for i, v := range votes {
+		switch {
+		case v.err != nil:
+			lines[i] = fmt.Sprintf("  %s: error=%v", v.p.host, v.err)
+		case v.cp == nil:
+			lines[i] = fmt.Sprintf("  %s: no finalized checkpoint", v.p.host)
+		default:
+			lines[i] = fmt.Sprintf("  %s: epoch=%d root=%#x", v.p.host, v.cp.Epoch, v.cp.Root)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// verifyWeakSubjectivity checks that the checkpoint state descends from the weak subjectivity checkpoint, using the
+// block roots of the state. A weak subjectivity checkpoint older than the block roots of the state can not be
+// checked here, the node verifies it once it has synced past it.
+func verifyWeakSubjectivity(st state.ReadOnlyBeaconState, cp, ws *zondpb.Checkpoint) error {
+	if ws == nil {
+		return nil
+	}
+	slot, err := slots.EpochStart(ws.Epoch)
+	if err != nil {
+		return err
+	}
+	var root []byte
+	switch {
+	case slot == st.Slot():
+		root = cp.Root
+	case slot+params.BeaconConfig().SlotsPerHistoricalRoot < st.Slot():
+		log.WithField("epoch", ws.Epoch).Warn("Weak subjectivity checkpoint is too old to be checked against the checkpoint sync state")
+		return nil
+	default:
+		root, err = helpers.BlockRootAtSlot(st, slot)
+		if err != nil {
+			return errors.Wrapf(err, "could not get block root at weak subjectivity slot %d", slot)
+		}
+	}
+	if !bytes.Equal(root, ws.Root) {
+		return errors.Wrapf(errWeakSubjectivityCheckMismatch, "block root at epoch %d is %#x, weak subjectivity root is %#x", ws.Epoch, root, ws.Root)
+	}
+	return nil
+}
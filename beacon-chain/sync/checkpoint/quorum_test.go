@@ -0,0 +1,175 @@
+package checkpoint
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+func TestNewQuorumInitializer(t *testing.T) {
+	hosts := []string{"http://a:3500", "http://b:3500", "http://c:3500"}
+	qi, err := NewQuorumInitializer(hosts, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, qi.quorum)
+	require.Equal(t, 3, len(qi.providers))
+
+	qi, err = NewQuorumInitializer(hosts, 3, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, qi.quorum)
+
+	_, err = NewQuorumInitializer(hosts, 4, nil)
+	require.ErrorContains(t, "is not between 1 and the number of providers", err)
+	_, err = NewQuorumInitializer(hosts, -1, nil)
+	require.ErrorContains(t, "is not between 1 and the number of providers", err)
+	_, err = NewQuorumInitializer(nil, 0, nil)
+	require.ErrorContains(t, "no checkpoint sync providers", err)
+}
+
+func TestAgree(t *testing.T) {
+	a := &zondpb.Checkpoint{Epoch: 10, Root: []byte{0xaa}}
+	b := &zondpb.Checkpoint{Epoch: 10, Root: []byte{0xbb}}
+	p := func(host string) *provider {
+		return &provider{host: host}
+	}
+
+	cp, agreeing, err := agree([]*vote{
+		{p: p("one"), cp: a},
+		{p: p("two"), cp: &zondpb.Checkpoint{Epoch: 10, Root: []byte{0xaa}}},
+		{p: p("three"), cp: b},
+	}, 2)
+	require.NoError(t, err)
+	require.DeepEqual(t, a, cp)
+	require.Equal(t, 2, len(agreeing))
+	require.Equal(t, "one", agreeing[0].host)
+	require.Equal(t, "two", agreeing[1].host)
+
+	_, _, err = agree([]*vote{
+		{p: p("one"), cp: a},
+		{p: p("two"), cp: b},
+		{p: p("three"), err: errors.New("connection refused")},
+		{p: p("four")},
+	}, 2)
+	require.ErrorIs(t, err, errNoQuorum)
+	require.ErrorContains(t, "one: epoch=10 root=0xaa", err)
+	require.ErrorContains(t, "two: epoch=10 root=0xbb", err)
+	require.ErrorContains(t, "three: error=connection refused", err)
+	require.ErrorContains(t, "four: no finalized checkpoint", err)
+
+	_, _, err = agree([]*vote{
+		{p: p("one"), cp: a},
+		{p: p("two"), cp: b},
+	}, 1)
+	require.ErrorIs(t, err, errConflictingQuorums)
+}
+
+func TestVerifyWeakSubjectivity(t *testing.T) {
+	st, err := util.NewBeaconStateCapella()
+	require.NoError(t, err)
+	epoch := primitives.Epoch(4)
+	stateSlot, err := slots.EpochStart(epoch)
+	require.NoError(t, err)
+	require.NoError(t, st.SetSlot(stateSlot))
+	wsSlot, err := slots.EpochStart(epoch - 1)
+	require.NoError(t, err)
+	wsRoot := [32]byte{0x01}
+	require.NoError(t, st.UpdateBlockRootAtIndex(uint64(wsSlot%params.BeaconConfig().SlotsPerHistoricalRoot), wsRoot))
+	cp := &zondpb.Checkpoint{Epoch: epoch, Root: []byte{0x02}}
+
+	require.NoError(t, verifyWeakSubjectivity(st, cp, nil))
+	require.NoError(t, verifyWeakSubjectivity(st, cp, &zondpb.Checkpoint{Epoch: epoch - 1, Root: wsRoot[:]}))
+	require.NoError(t, verifyWeakSubjectivity(st, cp, &zondpb.Checkpoint{Epoch: epoch, Root: cp.Root}))
+
+	err = verifyWeakSubjectivity(st, cp, &zondpb.Checkpoint{Epoch: epoch - 1, Root: []byte{0x03}})
+	require.ErrorIs(t, err, errWeakSubjectivityCheckMismatch)
+	err = verifyWeakSubjectivity(st, cp, &zondpb.Checkpoint{Epoch: epoch, Root: []byte{0x03}})
+	require.ErrorIs(t, err, errWeakSubjectivityCheckMismatch)
+}
+
+func TestQuorumInitializer_Agree(t *testing.T) {
+	a := &zondpb.Checkpoint{Epoch: 10, Root: []byte{0xaa}}
+	b := &zondpb.Checkpoint{Epoch: 11, Root: []byte{0xbb}}
+	qi, err := NewQuorumInitializer([]string{"http://a:3500", "http://b:3500", "http://c:3500"}, 3, nil)
+	require.NoError(t, err)
+	qi.retryDelay = time.Millisecond
+	qi.timeout = 100 * time.Millisecond
+
+	t.Run("parallel with deadline", func(t *testing.T) {
+		qi.quorum = 2
+		defer func() { qi.quorum = 3 }()
+		qi.finalized = func(ctx context.Context, p *provider) (*zondpb.Checkpoint, error) {
+			if p.host == "http://c:3500" {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return a, nil
+		}
+		cp, agreeing, err := qi.agree(context.Background())
+		require.NoError(t, err)
+		require.DeepEqual(t, a, cp)
+		require.Equal(t, 2, len(agreeing))
+		require.Equal(t, "http://a:3500", agreeing[0].host)
+		require.Equal(t, "http://b:3500", agreeing[1].host)
+	})
+	t.Run("epoch transition", func(t *testing.T) {
+		var mu sync.Mutex
+		polled := make(map[string]int)
+		qi.finalized = func(_ context.Context, p *provider) (*zondpb.Checkpoint, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polled[p.host]++
+			// The last provider has not processed the epoch transition the first time it is polled.
+			if p.host == "http://c:3500" && polled[p.host] == 1 {
+				return a, nil
+			}
+			return b, nil
+		}
+		cp, agreeing, err := qi.agree(context.Background())
+		require.NoError(t, err)
+		require.DeepEqual(t, b, cp)
+		require.Equal(t, 3, len(agreeing))
+		require.Equal(t, 2, polled["http://a:3500"])
+	})
+	t.Run("no quorum", func(t *testing.T) {
+		var mu sync.Mutex
+		polls := 0
+		qi.finalized = func(_ context.Context, p *provider) (*zondpb.Checkpoint, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polls++
+			if p.host == "http://c:3500" {
+				return b, nil
+			}
+			return a, nil
+		}
+		_, _, err := qi.agree(context.Background())
+		require.ErrorIs(t, err, errNoQuorum)
+		require.Equal(t, 3*quorumAttempts, polls)
+	})
+	t.Run("conflicting quorums", func(t *testing.T) {
+		qi.quorum = 1
+		defer func() { qi.quorum = 3 }()
+		polls := 0
+		var mu sync.Mutex
+		qi.finalized = func(_ context.Context, p *provider) (*zondpb.Checkpoint, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polls++
+			if p.host == "http://c:3500" {
+				return b, nil
+			}
+			return a, nil
+		}
+		_, _, err := qi.agree(context.Background())
+		require.ErrorIs(t, err, errConflictingQuorums)
+		require.Equal(t, 3, polls)
+	})
+}
@@ -0,0 +1,151 @@
+package genesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/api/client/beacon"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/crypto/hash"
+)
+
+var (
+	errNoQuorum           = errors.New("beacon node apis did not reach a quorum on the genesis state")
+	errConflictingQuorums = errors.New("beacon node apis reached a quorum on conflicting genesis states")
+)
+
+// providerTimeout bounds the time a beacon node api has to serve the genesis state.
+const providerTimeout = 5 * time.Minute
+
+// QuorumAPIInitializer initializes the genesis state of the beacon node from several beacon node apis. The genesis
+// state is downloaded from every api, and the node only loads a genesis state served by a quorum of them, so that
+// no single api is trusted.
+type QuorumAPIInitializer struct {
+	clients []*beacon.Client
+	quorum  int
+	timeout time.Duration
+	state   func(ctx context.Context, c *beacon.Client) ([]byte, error)
+}
+
+// NewQuorumAPIInitializer creates a QuorumAPIInitializer for the given beacon node hosts. A quorum of zero requires
+// a majority of the hosts to agree.
+func NewQuorumAPIInitializer(hosts []string, quorum int) (*QuorumAPIInitializer, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no beacon node apis for the genesis state")
+	}
+	if quorum == 0 {
+		quorum = len(hosts)/2 + 1
+	}
+	if quorum < 0 || quorum > len(hosts) {
+		return nil, fmt.Errorf("genesis state quorum of %d is not between 1 and the number of beacon node apis (%d)", quorum, len(hosts))
+	}
+	clients := make([]*beacon.Client, len(hosts))
+	for i, h := range hosts {
+		c, err := beacon.NewClient(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", h)
+		}
+		clients[i] = c
+	}
+	return &QuorumAPIInitializer{
+		clients: clients,
+		quorum:  quorum,
+		timeout: providerTimeout,
+		state: func(ctx context.Context, c *beacon.Client) ([]byte, error) {
+			return c.GetState(ctx, beacon.IdGenesis)
+		},
+	}, nil
+}
+
+// Initialize downloads the genesis state from every beacon node api in parallel, and loads the genesis state served
+// by a quorum of them into the database.
+func (qi *QuorumAPIInitializer) Initialize(ctx context.Context, d db.Database) error {
+	existing, err := d.GenesisState(ctx)
+	if err != nil {
+		return err
+	}
+	if existing != nil && !existing.IsNil() {
+		htr, err := existing.HashTreeRoot(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error while computing hash_tree_root of existing genesis state")
+		}
+		log.Warnf("database contains genesis with htr=%#x, ignoring remote genesis state parameter", htr)
+		return nil
+	}
+
+	states := make([]*genesisState, len(qi.clients))
+	var wg sync.WaitGroup
+	for i, c := range qi.clients {
+		wg.Add(1)
+		go func(i int, c *beacon.Client) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, qi.timeout)
+			defer cancel()
+			sb, err := qi.state(cctx, c)
+			states[i] = &genesisState{host: c.NodeURL(), sb: sb, err: err}
+		}(i, c)
+	}
+	wg.Wait()
+
+	sb, err := agree(states, qi.quorum)
+	if err != nil {
+		return err
+	}
+	log.WithField(
+		"hash", fmt.Sprintf("%#x", hash.FastSum256(sb)),
+	).Info("Beacon node apis agreed on the genesis state")
+	return d.LoadGenesis(ctx, sb)
+}
+
+var _ Initializer = &QuorumAPIInitializer{}
+
+// genesisState is the ssz-encoded genesis state served by a beacon node api, or the error which prevented to get it.
+type genesisState struct {
+	host string
+	sb   []byte
+	err  error
+}
+
+// agree returns the genesis state served by at least quorum beacon node apis. States are compared by the hash of
+// their ssz encoding. The error lists the state served by every api when there is no single such state.
+func agree(states []*genesisState, quorum int) ([]byte, error) {
+	counts := make(map[[32]byte]int)
+	var agreed []byte
+	for _, s := range states {
+		if s.err != nil || len(s.sb) == 0 {
+			continue
+		}
+		h := hash.FastSum256(s.sb)
+		counts[h]++
+		if counts[h] == quorum {
+			if agreed != nil {
+				return nil, errors.Wrapf(errConflictingQuorums, "quorum=%d\n%s", quorum, report(states))
+			}
+			agreed = s.sb
+		}
+	}
+	if agreed == nil {
+		return nil, errors.Wrapf(errNoQuorum, "quorum=%d\n%s", quorum, report(states))
+	}
+	return agreed, nil
+}
+
+// report describes the genesis state served by each beacon node api, one api per line.
+func report(states []*genesisState) string {
+	lines := make([]string, len(states))
+	for i, s := range states {
+		switch {
+		case s.err != nil:
+			lines[i] = fmt.Sprintf("  %s: error=%v", s.host, s.err)
+		case len(s.sb) == 0:
+			lines[i] = fmt.Sprintf("  %s: empty genesis state", s.host)
+		default:
+			lines[i] = fmt.Sprintf("  %s: hash=%#x", s.host, hash.FastSum256(s.sb))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,50 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestNewQuorumAPIInitializer(t *testing.T) {
+	hosts := []string{"http://a:3500", "http://b:3500", "http://c:3500"}
+	qi, err := NewQuorumAPIInitializer(hosts, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, qi.quorum)
+	require.Equal(t, 3, len(qi.clients))
+
+	_, err = NewQuorumAPIInitializer(hosts, 4)
+	require.ErrorContains(t, "is not between 1 and the number of beacon node apis", err)
+	_, err = NewQuorumAPIInitializer(nil, 0)
+	require.ErrorContains(t, "no beacon node apis", err)
+}
+
+func TestAgree(t *testing.T) {
+	a := []byte{0xaa, 0xaa}
+	b := []byte{0xbb, 0xbb}
+
+	sb, err := agree([]*genesisState{
+		{host: "one", sb: a},
+		{host: "two", sb: b},
+		{host: "three", sb: []byte{0xaa, 0xaa}},
+	}, 2)
+	require.NoError(t, err)
+	require.DeepEqual(t, a, sb)
+
+	_, err = agree([]*genesisState{
+		{host: "one", sb: a},
+		{host: "two", sb: b},
+		{host: "three", err: errors.New("connection refused")},
+		{host: "four"},
+	}, 2)
+	require.ErrorIs(t, err, errNoQuorum)
+	require.ErrorContains(t, "three: error=connection refused", err)
+	require.ErrorContains(t, "four: empty genesis state", err)
+
+	_, err = agree([]*genesisState{
+		{host: "one", sb: a},
+		{host: "two", sb: b},
+	}, 1)
+	require.ErrorIs(t, err, errConflictingQuorums)
+}
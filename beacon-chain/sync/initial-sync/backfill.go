@@ -0,0 +1,76 @@
+package initialsync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	prysmsync "github.com/theQRL/qrysm/v4/beacon-chain/sync"
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+)
+
+// BackfillFetcher fetches finalized block ranges on behalf of the backfill service. It shares the peer selection,
+// peer scoring and rate limiting of the fetcher used by initial sync.
+type BackfillFetcher struct {
+	sync.Mutex
+	ctx     context.Context
+	cfg     *Config
+	fetcher *blocksFetcher
+}
+
+// NewBackfillFetcher creates a fetcher for the backfill service. Only the P2P, DB, Chain and ClockWaiter fields of
+// the config are used.
+func NewBackfillFetcher(ctx context.Context, cfg *Config) *BackfillFetcher {
+	return &BackfillFetcher{ctx: ctx, cfg: cfg}
+}
+
+// blocksFetcher lazily creates the underlying fetcher, since it needs the genesis validators root.
+func (b *BackfillFetcher) blocksFetcher(ctx context.Context) (*blocksFetcher, error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.fetcher != nil {
+		return b.fetcher, nil
+	}
+	clock, err := b.cfg.ClockWaiter.WaitForClock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not wait for clock")
+	}
+	ctxMap, err := prysmsync.ContextByteVersionsForValRoot(clock.GenesisValidatorsRoot())
+	if err != nil {
+		return nil, err
+	}
+	b.fetcher = newBlocksFetcher(b.ctx, &blocksFetcherConfig{
+		clock:  clock,
+		ctxMap: ctxMap,
+		chain:  b.cfg.Chain,
+		p2p:    b.cfg.P2P,
+		db:     b.cfg.DB,
+		mode:   modeStopOnFinalizedEpoch,
+	})
+	return b.fetcher, nil
+}
+
+// FetchBlocks requests the blocks of the slots in [start, start+count) from a single peer, selected among the peers
+// which have finalized the range. The blocks are returned sorted by slot, along with the peer which served them.
+func (b *BackfillFetcher) FetchBlocks(ctx context.Context, start primitives.Slot, count uint64) ([]blocks.ROBlock, peer.ID, error) {
+	f, err := b.blocksFetcher(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	_, _, peers := f.calculateHeadAndTargetEpochs()
+	if len(peers) == 0 {
+		return nil, "", errNoPeersAvailable
+	}
+	bwb, pid, err := f.fetchBlocksFromPeer(ctx, start, count, peers)
+	if err != nil {
+		return nil, "", err
+	}
+	return blocks.BlockWithVerifiedBlobsSlice(bwb).ROBlocks(), pid, nil
+}
+
+// BadResponse lowers the score of a peer which served blocks that failed verification.
+func (b *BackfillFetcher) BadResponse(pid peer.ID) {
+	b.cfg.P2P.Peers().Scorers().BadResponsesScorer().Increment(pid)
+}
@@ -48,6 +48,9 @@ const (
 	// peerFilterCapacityWeight defines how peer's capacity affects peer's score. Provided as
 	// percentage, i.e. 0.3 means capacity will determine 30% of peer's score.
 	peerFilterCapacityWeight = 0.2
+	// peerFilterBandwidthWeight defines how peer's measured bandwidth and latency affect peer's block
+	// provider score. Provided as percentage, i.e. 0.3 means bandwidth will determine 30% of that score.
+	peerFilterBandwidthWeight = 0.3
 	// backtrackingMaxHops how many hops (during search for common ancestor in backtracking) to do
 	// before giving up.
 	backtrackingMaxHops = 128
@@ -517,7 +520,17 @@ func (f *blocksFetcher) requestBlocks(
 	}
 	f.rateLimiter.Add(pid.String(), int64(req.Count))
 	l.Unlock()
-	return prysmsync.SendBeaconBlocksByRangeRequest(ctx, f.chain, f.p2p, pid, req, nil)
+	sample := f.newResponseSample(pid)
+	blks, err := prysmsync.SendBeaconBlocksByRangeRequest(ctx, f.chain, f.p2p, pid, req, sample.process)
+	if err != nil {
+		// Requests cancelled by the fetcher say nothing about the peer, but failures and timeouts do.
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			sample.fail()
+		}
+		return nil, err
+	}
+	sample.record()
+	return blks, nil
 }
 
 func (f *blocksFetcher) requestBlobs(ctx context.Context, req *p2ppb.BlobSidecarsByRangeRequest, pid peer.ID) ([]*p2ppb.BlobSidecar, error) {
@@ -573,7 +586,17 @@ func (f *blocksFetcher) requestBlocksByRoot(
 	f.rateLimiter.Add(pid.String(), int64(len(*req)))
 	l.Unlock()
 
-	return prysmsync.SendBeaconBlocksByRootRequest(ctx, f.chain, f.p2p, pid, req, nil)
+	sample := f.newResponseSample(pid)
+	blks, err := prysmsync.SendBeaconBlocksByRootRequest(ctx, f.chain, f.p2p, pid, req, sample.process)
+	if err != nil {
+		// Requests cancelled by the fetcher say nothing about the peer, but failures and timeouts do.
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			sample.fail()
+		}
+		return nil, err
+	}
+	sample.record()
+	return blks, nil
 }
 
 // waitForBandwidth blocks up until peer's bandwidth is restored.
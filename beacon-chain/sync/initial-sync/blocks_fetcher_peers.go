@@ -11,6 +11,7 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers/scorers"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	mathutil "github.com/theQRL/qrysm/v4/math"
 	prysmTime "github.com/theQRL/qrysm/v4/time"
 	"github.com/theQRL/qrysm/v4/time/slots"
@@ -100,12 +101,19 @@ func (f *blocksFetcher) filterPeers(ctx context.Context, peers []peer.ID, peersP
 		return peers
 	}
 
-	// Sort peers using both block provider score and, custom, capacity based score (see
-	// peerFilterCapacityWeight if you want to give different weights to provider's and capacity
-	// scores).
+	// Sort peers using block provider score blended with the bandwidth score and, custom, capacity
+	// based score (see peerFilterBandwidthWeight and peerFilterCapacityWeight if you want to give
+	// different weights to provider's, bandwidth and capacity scores).
 	// Scores produced are used as weights, so peers are ordered probabilistically i.e. peer with
 	// a higher score has higher chance to end up higher in the list.
 	scorer := f.p2p.Peers().Scorers().BlockProviderScorer()
+	// Bandwidth scores are in [-1; 1], they are mapped onto the range of block provider scores. They are
+	// read before sorting, as the scoring function is called with the peer store locked.
+	bandwidthScorer := f.p2p.Peers().Scorers().BandwidthScorer()
+	bandwidthScores := make(map[peer.ID]float64, len(peers))
+	for _, pid := range peers {
+		bandwidthScores[pid] = (bandwidthScorer.Score(pid) + 1) / 2 * scorer.MaxScore()
+	}
 	peers = scorer.WeightSorted(f.rand, peers, func(peerID peer.ID, blockProviderScore float64) float64 {
 		remaining, capacity := float64(f.rateLimiter.Remaining(peerID.String())), float64(f.rateLimiter.Capacity())
 		// When capacity is close to exhaustion, allow less performant peer to take a chance.
@@ -114,13 +122,58 @@ func (f *blocksFetcher) filterPeers(ctx context.Context, peers []peer.ID, peersP
 			return 0.0
 		}
 		capScore := remaining / capacity
-		overallScore := blockProviderScore*(1.0-f.capacityWeight) + capScore*f.capacityWeight
+		providerScore := blockProviderScore*(1.0-peerFilterBandwidthWeight) + bandwidthScores[peerID]*peerFilterBandwidthWeight
+		overallScore := providerScore*(1.0-f.capacityWeight) + capScore*f.capacityWeight
 		return math.Round(overallScore*scorers.ScoreRoundingFactor) / scorers.ScoreRoundingFactor
 	})
 
 	return trimPeers(peers, peersPercentage)
 }
 
+// responseSample measures the latency and throughput of a blocks request to a peer, for the bandwidth
+// scorer.
+type responseSample struct {
+	scorer  *scorers.BandwidthScorer
+	pid     peer.ID
+	start   time.Time
+	latency time.Duration
+	size    uint64
+}
+
+// newResponseSample starts measuring a request to a peer. It must be created right before the request
+// is sent.
+func (f *blocksFetcher) newResponseSample(pid peer.ID) *responseSample {
+	return &responseSample{
+		scorer: f.p2p.Peers().Scorers().BandwidthScorer(),
+		pid:    pid,
+		start:  prysmTime.Now(),
+	}
+}
+
+// process accounts for a block of the response.
+func (s *responseSample) process(blk interfaces.ReadOnlySignedBeaconBlock) error {
+	if s.latency == 0 {
+		s.latency = time.Since(s.start)
+	}
+	s.size += uint64(blk.SizeSSZ())
+	return nil
+}
+
+// record reports a complete response to the bandwidth scorer. The latency of an empty response is the
+// time it took to complete.
+func (s *responseSample) record() {
+	elapsed := time.Since(s.start)
+	if s.latency == 0 {
+		s.latency = elapsed
+	}
+	s.scorer.RecordResponse(s.pid, s.size, s.latency, elapsed)
+}
+
+// fail reports a request which failed or timed out to the bandwidth scorer.
+func (s *responseSample) fail() {
+	s.scorer.RecordFailure(s.pid, time.Since(s.start))
+}
+
 // trimPeers limits peer list, returning only specified percentage of peers.
 // Takes system constraints into account (min/max peers to sync).
 func trimPeers(peers []peer.ID, peersPercentage float64) []peer.ID {
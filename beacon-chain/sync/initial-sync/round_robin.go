@@ -10,11 +10,13 @@ import (
 	"github.com/paulbellamy/ratecounter"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/blockchain"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/transition"
 	"github.com/theQRL/qrysm/v4/beacon-chain/sync"
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/time/slots"
 )
 
@@ -149,10 +151,29 @@ func (s *Service) processFetchedData(
 
 	// Use Batch Block Verify to process and verify batches directly.
 	if err := s.processBatchedBlocks(ctx, genesis, data.bwb, s.cfg.Chain.ReceiveBlockBatch); err != nil {
+		s.penalizeInvalidSignatures(data.pid, err)
 		log.WithError(err).Warn("Skip processing batched blocks")
 	}
 }
 
+// penalizeInvalidSignatures penalizes the peer which served blocks containing invalid signatures.
+func (s *Service) penalizeInvalidSignatures(pid peer.ID, err error) {
+	var sigErr *dilithium.InvalidSignaturesError
+	switch {
+	case errors.As(err, &sigErr):
+		s.cfg.P2P.Peers().Scorers().BadResponsesScorer().Increment(pid)
+		log.WithFields(logrus.Fields{
+			"pid":        pid,
+			"operations": sigErr.Descriptions(),
+			"count":      len(sigErr.Invalid),
+		}).Debug("Peer is penalized for blocks with invalid signatures")
+	case errors.Is(err, blockchain.ErrInvalidSignature):
+		// The invalid signatures are only located when the verbose signature verification is enabled.
+		s.cfg.P2P.Peers().Scorers().BadResponsesScorer().Increment(pid)
+		log.WithField("pid", pid).Debug("Peer is penalized for blocks with invalid signatures")
+	}
+}
+
 // processFetchedData processes data received from queue.
 func (s *Service) processFetchedDataRegSync(
 	ctx context.Context, genesis time.Time, startSlot primitives.Slot, data *blocksQueueFetchedData) {
@@ -163,12 +184,13 @@ func (s *Service) processFetchedDataRegSync(
 	blksWithoutParentCount := 0
 	for _, b := range data.bwb {
 		if len(b.Blobs) > 0 {
-			if err := s.cfg.DB.SaveBlobSidecar(ctx, b.Blobs); err != nil {
+			if err := s.cfg.BlobStorage.SaveBlobSidecar(ctx, b.Blobs); err != nil {
 				log.WithError(err).Warn("Failed to save blob sidecar")
 			}
 		}
 
 		if err := s.processBlock(ctx, genesis, b, blockReceiver); err != nil {
+			s.penalizeInvalidSignatures(data.pid, err)
 			switch {
 			case errors.Is(err, errBlockAlreadyProcessed):
 				log.WithError(err).Debug("Block is not processed")
@@ -326,7 +348,7 @@ func (s *Service) processBatchedBlocks(ctx context.Context, genesis time.Time,
 		if len(bb.Blobs) == 0 {
 			continue
 		}
-		if err := s.cfg.DB.SaveBlobSidecar(ctx, bb.Blobs); err != nil {
+		if err := s.cfg.BlobStorage.SaveBlobSidecar(ctx, bb.Blobs); err != nil {
 			return errors.Wrapf(err, "failed to save blobs for block %#x", bb.Block.Root())
 		}
 		blobCount += len(bb.Blobs)
@@ -36,6 +36,7 @@ type blockchainService interface {
 type Config struct {
 	P2P                 p2p.P2P
 	DB                  db.NoHeadAccessDatabase
+	BlobStorage         db.BlobStorage
 	Chain               blockchainService
 	StateNotifier       statefeed.Notifier
 	BlockNotifier       blockfeed.Notifier
@@ -89,6 +89,36 @@ var (
 			Buckets: []float64{5, 10, 50, 100, 150, 250, 500, 1000, 2000},
 		},
 	)
+	// Rate limiting of req/resp responses, charged by serialized bytes.
+	rpcResponseBytesByTopic = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rpc_response_bytes_total",
+			Help: "The number of serialized response bytes served to peers, by rpc topic",
+		},
+		[]string{"topic"},
+	)
+	// The peer labels are only set for connected peers, and removed when the peer disconnects.
+	rpcResponseBytesByPeer = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rpc_peer_response_bytes_total",
+			Help: "The number of serialized response bytes served to each connected peer, by rpc topic",
+		},
+		[]string{"peer", "topic"},
+	)
+	rpcThrottledResponsesByTopic = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rpc_throttled_responses_total",
+			Help: "The number of responses cut short by the rate limiter, by rpc topic",
+		},
+		[]string{"topic"},
+	)
+	rpcThrottledResponsesByPeer = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rpc_peer_throttled_responses_total",
+			Help: "The number of responses cut short by the rate limiter for each connected peer, by rpc topic",
+		},
+		[]string{"peer", "topic"},
+	)
 	arrivalBlockPropagationHistogram = promauto.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "block_arrival_latency_milliseconds",
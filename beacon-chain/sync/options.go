@@ -2,6 +2,7 @@ package sync
 
 import (
 	"github.com/theQRL/qrysm/v4/async/event"
+	"github.com/theQRL/qrysm/v4/beacon-chain/cache"
 	blockfeed "github.com/theQRL/qrysm/v4/beacon-chain/core/feed/block"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/feed/operation"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
@@ -14,6 +15,7 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
 	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/stategen"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
 )
 
 type Option func(s *Service) error
@@ -39,6 +41,13 @@ func WithDatabase(db db.NoHeadAccessDatabase) Option {
 	}
 }
 
+func WithBlobStorage(bs db.BlobStorage) Option {
+	return func(s *Service) error {
+		s.cfg.blobStorage = bs
+		return nil
+	}
+}
+
 func WithAttestationPool(attPool attestations.Pool) Option {
 	return func(s *Service) error {
 		s.cfg.attPool = attPool
@@ -143,3 +152,21 @@ func WithInitialSyncComplete(c chan struct{}) Option {
 		return nil
 	}
 }
+
+// WithBackfillStatus gives the service access to the range of blocks the node has, which are missing
+// between genesis and the checkpoint sync origin until backfill completes, or below the pruned slot.
+func WithBackfillStatus(bfs *backfill.Status) Option {
+	return func(s *Service) error {
+		s.cfg.backfillStatus = bfs
+		return nil
+	}
+}
+
+// WithVerifiedSignatureCache records the attestation signatures verified on gossip, so that they are not
+// verified again when the attestation is seen in another message or block.
+func WithVerifiedSignatureCache(c *cache.VerifiedSignatureCache) Option {
+	return func(s *Service) error {
+		s.cfg.verifiedSigCache = c
+		return nil
+	}
+}
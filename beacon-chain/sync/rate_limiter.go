@@ -6,9 +6,12 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers"
 	p2ptypes "github.com/theQRL/qrysm/v4/beacon-chain/p2p/types"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	leakybucket "github.com/theQRL/qrysm/v4/container/leaky-bucket"
@@ -19,7 +22,7 @@ const defaultBurstLimit = 5
 
 const leakyBucketPeriod = 1 * time.Second
 
-// Only allow in 2 batches per minute.
+// Period over which the response bytes served to a peer are counted.
 const blockBucketPeriod = 30 * time.Second
 
 // Dummy topic to validate all incoming rpc requests.
@@ -38,13 +41,13 @@ func newRateLimiter(p2pProvider p2p.P2P) *limiter {
 	addEncoding := func(topic string) string {
 		return topic + p2pProvider.Encoding().ProtocolSuffix()
 	}
-	// Initialize block limits.
-	allowedBlocksPerSecond := float64(flags.Get().BlockBatchLimit)
-	allowedBlocksBurst := int64(flags.Get().BlockBatchLimitBurstFactor * flags.Get().BlockBatchLimit)
+	// Initialize block limits, in serialized bytes per bucket period.
+	allowedBlockBytes := flags.Get().BlockBytesLimit * int(blockBucketPeriod.Seconds())
+	allowedBlockBytesBurst := int64(flags.Get().BlockBatchLimitBurstFactor * allowedBlockBytes)
 
-	// Initialize blob limits.
-	allowedBlobsPerSecond := float64(flags.Get().BlobBatchLimit)
-	allowedBlobsBurst := int64(flags.Get().BlobBatchLimitBurstFactor * flags.Get().BlobBatchLimit)
+	// Initialize blob limits, in serialized bytes per bucket period.
+	allowedBlobBytes := flags.Get().BlobBytesLimit * int(blockBucketPeriod.Seconds())
+	allowedBlobBytesBurst := int64(flags.Get().BlobBatchLimitBurstFactor * allowedBlobBytes)
 
 	// Set topic map for all rpc topics.
 	topicMap := make(map[string]*leakybucket.Collector, len(p2p.RPCTopicMappings))
@@ -58,13 +61,13 @@ func newRateLimiter(p2pProvider p2p.P2P) *limiter {
 	// Status Message
 	topicMap[addEncoding(p2p.RPCStatusTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
 
-	// Use a single collector for block requests
-	blockCollector := leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockBucketPeriod, false /* deleteEmptyBuckets */)
+	// Use a single collector for block requests, charged by the bytes of the blocks served.
+	blockCollector := leakybucket.NewCollector(float64(allowedBlockBytes), allowedBlockBytesBurst, blockBucketPeriod, false /* deleteEmptyBuckets */)
 	// Collector for V2
-	blockCollectorV2 := leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockBucketPeriod, false /* deleteEmptyBuckets */)
+	blockCollectorV2 := leakybucket.NewCollector(float64(allowedBlockBytes), allowedBlockBytesBurst, blockBucketPeriod, false /* deleteEmptyBuckets */)
 
-	// for BlobSidecarsByRoot and BlobSidecarsByRange
-	blobCollector := leakybucket.NewCollector(allowedBlobsPerSecond, allowedBlobsBurst, blockBucketPeriod, false)
+	// for BlobSidecarsByRoot and BlobSidecarsByRange, charged by the bytes of the sidecars served.
+	blobCollector := leakybucket.NewCollector(float64(allowedBlobBytes), allowedBlobBytesBurst, blockBucketPeriod, false)
 
 	// BlocksByRoots requests
 	topicMap[addEncoding(p2p.RPCBlocksByRootTopicV1)] = blockCollector
@@ -117,6 +120,31 @@ func (l *limiter) validateRequest(stream network.Stream, amt uint64) error {
 	return nil
 }
 
+// validates that the peer may still be served response bytes on the topic of the stream. Unlike a peer that
+// sends too many requests, a peer that used up its response bytes is not penalised: it receives a resource
+// unavailable response, asking it to back off until its bucket drains.
+func (l *limiter) validateResponse(stream network.Stream) error {
+	l.RLock()
+	defer l.RUnlock()
+
+	topic := string(stream.Protocol())
+
+	collector, err := l.retrieveCollector(topic)
+	if err != nil {
+		return err
+	}
+	key := stream.Conn().RemotePeer().String()
+	if collector.Remaining(key) > 0 {
+		return nil
+	}
+	rpcThrottledResponsesByTopic.WithLabelValues(topic).Inc()
+	if l.isConnected(stream.Conn().RemotePeer()) {
+		rpcThrottledResponsesByPeer.WithLabelValues(key, topic).Inc()
+	}
+	writeErrorResponseToStream(responseCodeResourceUnavailable, p2ptypes.ErrRateLimited.Error(), stream, l.p2p)
+	return p2ptypes.ErrRateLimited
+}
+
 // This is used to validate all incoming rpc streams from external peers.
 func (l *limiter) validateRawRpcRequest(stream network.Stream) error {
 	l.RLock()
@@ -157,6 +185,16 @@ func (l *limiter) add(stream network.Stream, amt int64) {
 	collector.Add(key, amt)
 }
 
+// adds the serialized size of a response chunk to our leaky bucket for the topic.
+func (l *limiter) addResponse(stream network.Stream, size int) {
+	l.add(stream, int64(size))
+	topic := string(stream.Protocol())
+	rpcResponseBytesByTopic.WithLabelValues(topic).Add(float64(size))
+	if pid := stream.Conn().RemotePeer(); l.isConnected(pid) {
+		rpcResponseBytesByPeer.WithLabelValues(pid.String(), topic).Add(float64(size))
+	}
+}
+
 // adds the cost to our leaky bucket for the peer.
 func (l *limiter) addRawStream(stream network.Stream) {
 	l.Lock()
@@ -174,6 +212,20 @@ func (l *limiter) addRawStream(stream network.Stream) {
 	collector.Add(key, 1)
 }
 
+// removes the metrics of a disconnected peer.
+func (_ *limiter) removePeer(pid peer.ID) {
+	labels := prometheus.Labels{"peer": pid.String()}
+	rpcResponseBytesByPeer.DeletePartialMatch(labels)
+	rpcThrottledResponsesByPeer.DeletePartialMatch(labels)
+}
+
+// only connected peers are labelled in the per-peer metrics, so that the metrics of a peer are not
+// recreated by a response racing its disconnection.
+func (l *limiter) isConnected(pid peer.ID) bool {
+	state, err := l.p2p.Peers().ConnectionState(pid)
+	return err == nil && state == peers.PeerConnected
+}
+
 // frees all the collectors and removes them.
 func (l *limiter) free() {
 	l.Lock()
@@ -8,9 +8,12 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p/peers"
 	mockp2p "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
 	p2ptypes "github.com/theQRL/qrysm/v4/beacon-chain/p2p/types"
+	leakybucket "github.com/theQRL/qrysm/v4/container/leaky-bucket"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
 	"github.com/theQRL/qrysm/v4/testing/util"
@@ -33,8 +36,8 @@ func TestRateLimiter_ExceedCapacity(t *testing.T) {
 	p1.Connect(p2)
 	rlimiter := newRateLimiter(p1)
 
-	// BlockByRange
-	topic := p2p.RPCBlocksByRangeTopicV1 + p1.Encoding().ProtocolSuffix()
+	// Status
+	topic := p2p.RPCStatusTopicV1 + p1.Encoding().ProtocolSuffix()
 
 	wg := sync.WaitGroup{}
 	p2.BHost.SetStreamHandler(protocol.ID(topic), func(stream network.Stream) {
@@ -48,7 +51,7 @@ func TestRateLimiter_ExceedCapacity(t *testing.T) {
 	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), protocol.ID(topic))
 	require.NoError(t, err, "could not create stream")
 
-	err = rlimiter.validateRequest(stream, 64)
+	err = rlimiter.validateRequest(stream, defaultBurstLimit)
 	require.NoError(t, err, "could not validate incoming request")
 
 	// Attempt to create an error, rate limit and lead to disconnect
@@ -62,6 +65,77 @@ func TestRateLimiter_ExceedCapacity(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_ExceedResponseBytes(t *testing.T) {
+	p1 := mockp2p.NewTestP2P(t)
+	p2 := mockp2p.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+	rlimiter := newRateLimiter(p1)
+
+	// BlockByRange
+	topic := p2p.RPCBlocksByRangeTopicV1 + p1.Encoding().ProtocolSuffix()
+	capacity := int64(4096)
+	rlimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, capacity, time.Second, false)
+
+	wg := sync.WaitGroup{}
+	p2.BHost.SetStreamHandler(protocol.ID(topic), func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := readStatusCodeNoDeadline(stream, p2.Encoding())
+		require.NoError(t, err, "could not read incoming stream")
+		assert.Equal(t, responseCodeResourceUnavailable, code, "not equal response codes")
+		assert.Equal(t, p2ptypes.ErrRateLimited.Error(), errMsg, "not equal errors")
+	})
+	wg.Add(1)
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), protocol.ID(topic))
+	require.NoError(t, err, "could not create stream")
+
+	// Responses are served until the bytes charged reach the capacity of the bucket.
+	require.NoError(t, rlimiter.validateResponse(stream))
+	rlimiter.addResponse(stream, int(capacity)-1)
+	require.NoError(t, rlimiter.validateResponse(stream))
+	rlimiter.addResponse(stream, 1)
+	assert.Equal(t, int64(0), rlimiter.limiterMap[topic].Remaining(p2.PeerID().String()))
+	assert.ErrorContains(t, p2ptypes.ErrRateLimited.Error(), rlimiter.validateResponse(stream))
+
+	// Throttling is back-pressure, not misbehaviour.
+	badResponses, err := p1.Peers().Scorers().BadResponsesScorer().Count(p2.PeerID())
+	require.NoError(t, err)
+	assert.Equal(t, 0, badResponses)
+	require.NoError(t, stream.Close(), "could not close stream")
+
+	if util.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestRateLimiter_PeerMetrics(t *testing.T) {
+	p1 := mockp2p.NewTestP2P(t)
+	p2 := mockp2p.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+	rlimiter := newRateLimiter(p1)
+
+	topic := p2p.RPCBlocksByRangeTopicV1 + p1.Encoding().ProtocolSuffix()
+	p2.BHost.SetStreamHandler(protocol.ID(topic), func(stream network.Stream) {})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), protocol.ID(topic))
+	require.NoError(t, err, "could not create stream")
+	labels := prometheus.Labels{"peer": p2.PeerID().String()}
+
+	// A peer which is not connected is left out of the per-peer metrics.
+	p1.Peers().SetConnectionState(p2.PeerID(), peers.PeerDisconnecting)
+	rlimiter.addResponse(stream, 100)
+	assert.Equal(t, 0, rpcResponseBytesByPeer.DeletePartialMatch(labels))
+
+	p1.Peers().SetConnectionState(p2.PeerID(), peers.PeerConnected)
+	rlimiter.addResponse(stream, 100)
+	rlimiter.removePeer(p2.PeerID())
+	assert.Equal(t, 0, rpcResponseBytesByPeer.DeletePartialMatch(labels), "peer metrics not removed")
+
+	rlimiter.addResponse(stream, 100)
+	assert.Equal(t, 1, rpcResponseBytesByPeer.DeletePartialMatch(labels))
+	require.NoError(t, stream.Close(), "could not close stream")
+}
+
 func TestRateLimiter_ExceedRawCapacity(t *testing.T) {
 	p1 := mockp2p.NewTestP2P(t)
 	p2 := mockp2p.NewTestP2P(t)
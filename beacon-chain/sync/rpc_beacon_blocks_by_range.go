@@ -37,6 +37,14 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 		tracing.AnnotateError(span, err)
 		return err
 	}
+	// Blocks deleted by history pruning cannot be told apart from skipped slots by the peer, so the whole request
+	// is refused instead of serving the part of the range we have. Blocks missing below the checkpoint sync origin
+	// are not refused, and are served as they are backfilled, like before history pruning.
+	if s.cfg.backfillStatus != nil && s.cfg.backfillStatus.RangePruned(rp.start, rp.end) {
+		s.writeErrorResponseToStream(responseCodeResourceUnavailable, p2ptypes.ErrResourceUnavailable.Error(), stream)
+		tracing.AnnotateError(span, p2ptypes.ErrResourceUnavailable)
+		return p2ptypes.ErrResourceUnavailable
+	}
 
 	blockLimiter, err := s.rateLimiter.topicCollector(string(stream.Protocol()))
 	if err != nil {
@@ -76,7 +84,10 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	}
 	if err := batch.error(); err != nil {
 		log.WithError(err).Debug("error in BlocksByRange batch")
-		s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+		// The rate limiter has already responded to a throttled peer.
+		if !errors.Is(err, p2ptypes.ErrRateLimited) {
+			s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+		}
 		tracing.AnnotateError(span, err)
 		return err
 	}
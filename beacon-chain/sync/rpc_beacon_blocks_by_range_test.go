@@ -22,6 +22,7 @@ import (
 	p2ptest "github.com/theQRL/qrysm/v4/beacon-chain/p2p/testing"
 	p2ptypes "github.com/theQRL/qrysm/v4/beacon-chain/p2p/types"
 	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	fieldparams "github.com/theQRL/qrysm/v4/config/fieldparams"
 	"github.com/theQRL/qrysm/v4/config/params"
@@ -63,11 +64,12 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 
 	clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
-	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
+	// Start service with the bytes of 160 blocks as allowed capacity (and almost zero capacity recovery).
 	r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}}, rateLimiter: newRateLimiter(p1)}
 	pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
 	topic := string(pcl)
-	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, int64(req.Count*10), time.Second, false)
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
+	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.001, int64(req.Count*10)*blockSize, time.Second, false)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
@@ -90,7 +92,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 
 	// Make sure that rate limiter doesn't limit capacity exceedingly.
 	remainingCapacity := r.rateLimiter.limiterMap[topic].Remaining(p2.PeerID().String())
-	expectedCapacity := int64(req.Count*10 - req.Count)
+	expectedCapacity := int64(req.Count*10-req.Count) * blockSize
 	require.Equal(t, expectedCapacity, remainingCapacity, "Unexpected rate limiting capacity")
 
 	if util.WaitTimeout(&wg, 1*time.Second) {
@@ -98,6 +100,112 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 }
 
+func TestRPCBeaconBlocksByRange_RefusesPrunedRange(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	d := db.SetupDB(t)
+	bfs := backfill.NewStatus(d)
+	bfs.MarkPruned(110)
+
+	clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
+	r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}, backfillStatus: bfs}, rateLimiter: newRateLimiter(p1)}
+	pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectFailure(t, responseCodeResourceUnavailable, p2ptypes.ErrResourceUnavailable.Error(), stream)
+	})
+
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	req := &zondpb.BeaconBlocksByRangeRequest{StartSlot: 100, Step: 1, Count: 16}
+	err = r.beaconBlocksByRangeRPCHandler(context.Background(), req, stream)
+	require.ErrorIs(t, err, p2ptypes.ErrResourceUnavailable)
+	if util.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+// finalizedChain answers IsCanonical from the finalized block roots index, like the blockchain service does for
+// blocks which are not in fork choice.
+type finalizedChain struct {
+	*chainMock.ChainService
+	db db2.ReadOnlyDatabase
+}
+
+func (c *finalizedChain) IsCanonical(ctx context.Context, root [32]byte) (bool, error) {
+	return c.db.IsFinalizedBlock(ctx, root), nil
+}
+
+func TestRPCBeaconBlocksByRange_ServesBackfilledRange(t *testing.T) {
+	ctx := context.Background()
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	d := db.SetupDB(t)
+
+	genesis := util.NewBeaconBlock()
+	genesisRoot, err := genesis.Block.HashTreeRoot()
+	require.NoError(t, err)
+	util.SaveBlock(t, ctx, d, genesis)
+	require.NoError(t, d.SaveGenesisBlockRoot(ctx, genesisRoot))
+	chain := make([]blocks.ROBlock, 0, 20)
+	parent := genesisRoot
+	for slot := primitives.Slot(1); slot <= 20; slot++ {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = slot
+		b.Block.ParentRoot = parent[:]
+		wsb, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		rb, err := blocks.NewROBlock(wsb)
+		require.NoError(t, err)
+		chain = append(chain, rb)
+		parent = rb.Root()
+	}
+	// The node was checkpoint synced from the block at slot 16, and backfilled the blocks below it.
+	origin := chain[15]
+	require.NoError(t, d.SaveBlock(ctx, origin))
+	originSaver, ok := d.(interface {
+		SaveOriginCheckpointBlockRoot(context.Context, [32]byte) error
+	})
+	require.Equal(t, true, ok)
+	require.NoError(t, originSaver.SaveOriginCheckpointBlockRoot(ctx, origin.Root()))
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	require.NoError(t, d.SaveState(ctx, st, origin.Root()))
+	originRoot := origin.Root()
+	require.NoError(t, d.SaveFinalizedCheckpoint(ctx, &zondpb.Checkpoint{Root: originRoot[:]}))
+	require.NoError(t, d.SaveBackfillBlocks(ctx, chain[:15], originRoot))
+
+	clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
+	mc := &finalizedChain{ChainService: &chainMock.ChainService{}, db: d}
+	r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: mc}, rateLimiter: newRateLimiter(p1)}
+	pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
+	req := &zondpb.BeaconBlocksByRangeRequest{StartSlot: 1, Step: 1, Count: 16}
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
+	r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, int64(req.Count*10)*blockSize, time.Second, false)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		for slot := req.StartSlot; slot < req.StartSlot.Add(req.Count); slot++ {
+			expectSuccess(t, stream)
+			res := util.NewBeaconBlock()
+			assert.NoError(t, r.cfg.p2p.Encoding().DecodeWithMaxLength(stream, res))
+			assert.Equal(t, slot, res.Block.Slot)
+		}
+	})
+
+	stream, err := p1.BHost.NewStream(ctx, p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	require.NoError(t, r.beaconBlocksByRangeRPCHandler(ctx, req, stream))
+	if util.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
 func TestRPCBeaconBlocksByRange_ReturnCorrectNumberBack(t *testing.T) {
 	p1 := p2ptest.NewTestP2P(t)
 	p2 := p2ptest.NewTestP2P(t)
@@ -313,11 +421,12 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsSortedBlocks(t *testing.T) {
 	}
 
 	clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
-	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
+	// Start service with the bytes of 160 blocks as allowed capacity (and almost zero capacity recovery).
 	r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}}, rateLimiter: newRateLimiter(p1)}
 	pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
 	topic := string(pcl)
-	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, int64(req.Count*10), time.Second, false)
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
+	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.001, int64(req.Count*10)*blockSize, time.Second, false)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -464,6 +573,10 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		return nil
 	}
 
+	// The rate limiter charges the serialized size of the blocks served, which is the same for all test blocks.
+	// The recovery rate is in bytes, a lower rate overflows the period of the bucket.
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
+
 	t.Run("high request count param and no overflow", func(t *testing.T) {
 		p1 := p2ptest.NewTestP2P(t)
 		p2 := p2ptest.NewTestP2P(t)
@@ -476,7 +589,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 
 		pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
 		topic := string(pcl)
-		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, capacity, time.Second, false)
+		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.001, capacity*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
 			Step:      5,
@@ -503,7 +616,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 
 		pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
 		topic := string(pcl)
-		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, capacity, time.Second, false)
+		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.001, capacity*blockSize, time.Second, false)
 
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -533,7 +646,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}}, rateLimiter: newRateLimiter(p1)}
 		pcl := protocol.ID(p2p.RPCBlocksByRangeTopicV1)
 		topic := string(pcl)
-		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.000001, capacity, time.Second, false)
+		r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(0.001, capacity*blockSize, time.Second, false)
 
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -670,6 +783,8 @@ func TestRPCBeaconBlocksByRange_validateRangeRequest(t *testing.T) {
 }
 
 func TestRPCBeaconBlocksByRange_EnforceResponseInvariants(t *testing.T) {
+	// The rate limiter charges the serialized size of the blocks served.
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
 	d := db.SetupDB(t)
 	hook := logTest.NewGlobal()
 	saveBlocks := func(req *zondpb.BeaconBlocksByRangeRequest) {
@@ -723,7 +838,7 @@ func TestRPCBeaconBlocksByRange_EnforceResponseInvariants(t *testing.T) {
 
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, chain: &chainMock.ChainService{}, clock: clock}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 448,
 			Step:      1,
@@ -747,6 +862,8 @@ func TestRPCBeaconBlocksByRange_EnforceResponseInvariants(t *testing.T) {
 }
 
 func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
+	// The rate limiter charges the serialized size of the blocks served.
+	blockSize := int64(util.NewBeaconBlock().SizeSSZ())
 	hook := logTest.NewGlobal()
 
 	saveBlocks := func(d db2.Database, chain *chainMock.ChainService, req *zondpb.BeaconBlocksByRangeRequest, finalized bool) {
@@ -892,7 +1009,7 @@ func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
 
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 1,
 			Step:      1,
@@ -924,7 +1041,7 @@ func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
 
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, clock: clock, chain: &chainMock.ChainService{}}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 1,
 			Step:      1,
@@ -959,7 +1076,7 @@ func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
 		assert.Equal(t, 1, len(p1.BHost.Network().Peers()), "Expected peers to be connected")
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, chain: &chainMock.ChainService{}, clock: clock}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 1,
 			Step:      1,
@@ -995,7 +1112,7 @@ func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
 
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, chain: &chainMock.ChainService{}, clock: clock}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 1,
 			Step:      1,
@@ -1036,7 +1153,7 @@ func TestRPCBeaconBlocksByRange_FilterBlocks(t *testing.T) {
 
 		clock := startup.NewClock(time.Unix(0, 0), [32]byte{})
 		r := &Service{cfg: &config{p2p: p1, beaconDB: d, chain: &chainMock.ChainService{}, clock: clock}, rateLimiter: newRateLimiter(p1)}
-		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.000001, 640, time.Second, false)
+		r.rateLimiter.limiterMap[string(pcl)] = leakybucket.NewCollector(0.001, 640*blockSize, time.Second, false)
 		req := &zondpb.BeaconBlocksByRangeRequest{
 			StartSlot: 1,
 			Step:      1,
@@ -63,7 +63,7 @@ func (s *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 		return errors.New("message is not type BeaconBlockByRootsReq")
 	}
 	blockRoots := *rawMsg
-	if err := s.rateLimiter.validateRequest(stream, uint64(len(blockRoots))); err != nil {
+	if err := s.rateLimiter.validateResponse(stream); err != nil {
 		return err
 	}
 	if len(blockRoots) == 0 {
@@ -79,9 +79,11 @@ func (s *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 		s.writeErrorResponseToStream(responseCodeInvalidRequest, "requested more than the max block limit", stream)
 		return errors.New("requested more than the max block limit")
 	}
-	s.rateLimiter.add(stream, int64(len(blockRoots)))
 
 	for _, root := range blockRoots {
+		if err := s.rateLimiter.validateResponse(stream); err != nil {
+			return err
+		}
 		blk, err := s.cfg.beaconDB.Block(ctx, root)
 		if err != nil {
 			log.WithError(err).Debug("Could not fetch block")
@@ -89,6 +91,8 @@ func (s *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 			return err
 		}
 		if err := blocks.BeaconBlockIsNil(blk); err != nil {
+			// Charge unknown roots, which serve no bytes, so that they are not free to request.
+			s.rateLimiter.add(stream, 1)
 			continue
 		}
 
@@ -150,5 +154,5 @@ func (s *Service) requestPendingBlobs(ctx context.Context, b interfaces.ReadOnly
 		log.WithFields(blobFields(sidecar)).Debug("Received blob sidecar gossip RPC")
 	}
 
-	return s.cfg.beaconDB.SaveBlobSidecar(ctx, blobSidecars)
+	return s.cfg.blobStorage.SaveBlobSidecar(ctx, blobSidecars)
 }
@@ -55,7 +55,7 @@ func TestRecentBeaconBlocksRPCHandler_ReturnsBlocks(t *testing.T) {
 	r.cfg.chain = &mock.ChainService{ValidatorsRoot: [32]byte{}}
 	pcl := protocol.ID(p2p.RPCBlocksByRootTopicV1)
 	topic := string(pcl)
-	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(10000, 10000, time.Second, false)
+	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(1000000, 1000000, time.Second, false)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -154,7 +154,7 @@ func TestRecentBeaconBlocksRPCHandler_ReturnsBlocks_ReconstructsPayload(t *testi
 	}, rateLimiter: newRateLimiter(p1)}
 	pcl := protocol.ID(p2p.RPCBlocksByRootTopicV1)
 	topic := string(pcl)
-	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(10000, 10000, time.Second, false)
+	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(1000000, 1000000, time.Second, false)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -228,7 +228,7 @@ func TestRecentBeaconBlocks_RPCRequestSent(t *testing.T) {
 	// Setup streams
 	pcl := protocol.ID("/eth2/beacon_chain/req/beacon_blocks_by_root/1/ssz_snappy")
 	topic := string(pcl)
-	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(10000, 10000, time.Second, false)
+	r.rateLimiter.limiterMap[topic] = leakybucket.NewCollector(1000000, 1000000, time.Second, false)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
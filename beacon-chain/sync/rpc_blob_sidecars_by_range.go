@@ -29,7 +29,7 @@ func (s *Service) streamBlobBatch(ctx context.Context, batch blockBatch, wQuota
 	defer span.End()
 	for _, b := range batch.canonical() {
 		root := b.Root()
-		scs, err := s.cfg.beaconDB.BlobSidecarsByRoot(ctx, b.Root())
+		scs, err := s.cfg.blobStorage.BlobSidecarsByRoot(ctx, b.Root())
 		if errors.Is(err, db.ErrNotFound) {
 			continue
 		}
@@ -38,14 +38,16 @@ func (s *Service) streamBlobBatch(ctx context.Context, batch blockBatch, wQuota
 			return wQuota, errors.Wrapf(err, "could not retrieve sidecars for block root %#x", root)
 		}
 		for _, sc := range scs {
-			SetStreamWriteDeadline(stream, defaultWriteDuration)
-			if chunkErr := WriteBlobSidecarChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), sc); chunkErr != nil {
+			if err := s.rateLimiter.validateResponse(stream); err != nil {
+				tracing.AnnotateError(span, err)
+				return wQuota, err
+			}
+			if chunkErr := s.chunkBlobSidecarWriter(stream, sc); chunkErr != nil {
 				log.WithError(chunkErr).Debug("Could not send a chunked response")
 				s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
 				tracing.AnnotateError(span, chunkErr)
 				return wQuota, chunkErr
 			}
-			s.rateLimiter.add(stream, 1)
 			wQuota -= 1
 			// Stop streaming results once the quota of writes for the request is consumed.
 			if wQuota == 0 {
@@ -70,7 +72,7 @@ func (s *Service) blobSidecarsByRangeRPCHandler(ctx context.Context, msg interfa
 	if !ok {
 		return errors.New("message is not type *pb.BlobsSidecarsByRangeRequest")
 	}
-	if err := s.rateLimiter.validateRequest(stream, 1); err != nil {
+	if err := s.rateLimiter.validateResponse(stream); err != nil {
 		return err
 	}
 	rp, err := validateBlobsByRange(r, s.cfg.chain.CurrentSlot())
@@ -102,7 +104,10 @@ func (s *Service) blobSidecarsByRangeRPCHandler(ctx context.Context, msg interfa
 	}
 	if err := batch.error(); err != nil {
 		log.WithError(err).Debug("error in BlocksByRange batch")
-		s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+		// The rate limiter has already responded to a throttled peer.
+		if !errors.Is(err, p2ptypes.ErrRateLimited) {
+			s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+		}
 		tracing.AnnotateError(span, err)
 		return err
 	}
@@ -80,10 +80,13 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 		if i != 0 && i%batchSize == 0 && ticker != nil {
 			<-ticker.C
 		}
-		s.rateLimiter.add(stream, 1)
+		if err := s.rateLimiter.validateResponse(stream); err != nil {
+			tracing.AnnotateError(span, err)
+			return err
+		}
 		root, idx := bytesutil.ToBytes32(blobIdents[i].BlockRoot), blobIdents[i].Index
 		if root != buff.root {
-			scs, err := s.cfg.beaconDB.BlobSidecarsByRoot(ctx, root)
+			scs, err := s.cfg.blobStorage.BlobSidecarsByRoot(ctx, root)
 			buff.root, buff.scs = root, scs
 			if err != nil {
 				if errors.Is(err, db.ErrNotFound) {
@@ -91,6 +94,8 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 					// are not processed when we reenter the outer loop.
 					buff.scs = nil
 					log.WithError(err).Debugf("BlobSidecar not found in db, root=%x, index=%d", root, idx)
+					// Charge unknown roots, which serve no bytes, so that they are not free to request.
+					s.rateLimiter.add(stream, 1)
 					continue
 				}
 				log.WithError(err).Errorf("unexpected db error retrieving BlobSidecar, root=%x, index=%d", root, idx)
@@ -100,6 +105,7 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 		}
 
 		if idx >= uint64(len(buff.scs)) {
+			s.rateLimiter.add(stream, 1)
 			continue
 		}
 		sc := buff.scs[idx]
@@ -112,8 +118,7 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 				Debugf("requested blob for block %#x before minimum_request_epoch", blobIdents[i].BlockRoot)
 			return types.ErrBlobLTMinRequest
 		}
-		SetStreamWriteDeadline(stream, defaultWriteDuration)
-		if chunkErr := WriteBlobSidecarChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), sc); chunkErr != nil {
+		if chunkErr := s.chunkBlobSidecarWriter(stream, sc); chunkErr != nil {
 			log.WithError(chunkErr).Debug("Could not send a chunked response")
 			s.writeErrorResponseToStream(responseCodeServerError, types.ErrGeneric.Error(), stream)
 			tracing.AnnotateError(span, chunkErr)
@@ -18,11 +18,26 @@ import (
 )
 
 // chunkBlockWriter writes the given message as a chunked response to the given network
-// stream.
+// stream, and charges its serialized size to the peer in the rate limiter.
 // response_chunk  ::= <result> | <context-bytes> | <encoding-dependent-header> | <encoded-payload>
 func (s *Service) chunkBlockWriter(stream libp2pcore.Stream, blk interfaces.ReadOnlySignedBeaconBlock) error {
 	SetStreamWriteDeadline(stream, defaultWriteDuration)
-	return WriteBlockChunk(stream, s.cfg.clock, s.cfg.p2p.Encoding(), blk)
+	if err := WriteBlockChunk(stream, s.cfg.clock, s.cfg.p2p.Encoding(), blk); err != nil {
+		return err
+	}
+	s.rateLimiter.addResponse(stream, blk.SizeSSZ())
+	return nil
+}
+
+// chunkBlobSidecarWriter writes the given blob sidecar as a chunked response to the given network
+// stream, and charges its serialized size to the peer in the rate limiter.
+func (s *Service) chunkBlobSidecarWriter(stream libp2pcore.Stream, sidecar *zondpb.BlobSidecar) error {
+	SetStreamWriteDeadline(stream, defaultWriteDuration)
+	if err := WriteBlobSidecarChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), sidecar); err != nil {
+		return err
+	}
+	s.rateLimiter.addResponse(stream, sidecar.SizeSSZ())
+	return nil
 }
 
 // WriteBlockChunk writes block chunk object to stream.
@@ -20,6 +20,7 @@ import (
 	"github.com/theQRL/qrysm/v4/async/abool"
 	"github.com/theQRL/qrysm/v4/async/event"
 	"github.com/theQRL/qrysm/v4/beacon-chain/blockchain"
+	"github.com/theQRL/qrysm/v4/beacon-chain/cache"
 	blockfeed "github.com/theQRL/qrysm/v4/beacon-chain/core/feed/block"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/feed/operation"
 	"github.com/theQRL/qrysm/v4/beacon-chain/db"
@@ -32,6 +33,7 @@ import (
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
 	"github.com/theQRL/qrysm/v4/beacon-chain/startup"
 	"github.com/theQRL/qrysm/v4/beacon-chain/state/stategen"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
 	lruwrpr "github.com/theQRL/qrysm/v4/cache/lru"
 	"github.com/theQRL/qrysm/v4/config/params"
 	leakybucket "github.com/theQRL/qrysm/v4/container/leaky-bucket"
@@ -74,6 +76,7 @@ type config struct {
 	attestationNotifier           operation.Notifier
 	p2p                           p2p.P2P
 	beaconDB                      db.NoHeadAccessDatabase
+	blobStorage                   db.BlobStorage
 	attPool                       attestations.Pool
 	exitPool                      voluntaryexits.PoolManager
 	slashingPool                  slashings.PoolManager
@@ -88,6 +91,8 @@ type config struct {
 	slasherAttestationsFeed       *event.Feed
 	slasherBlockHeadersFeed       *event.Feed
 	clock                         *startup.Clock
+	backfillStatus                *backfill.Status
+	verifiedSigCache              *cache.VerifiedSignatureCache
 }
 
 // This defines the interface for interacting with block chain service
@@ -104,6 +109,7 @@ type blockchainService interface {
 	blockchain.OptimisticModeFetcher
 	blockchain.SlashingReceiver
 	blockchain.ForkchoiceFetcher
+	blockchain.LightClientUpdateFetcher
 }
 
 // Service is responsible for handling all run time p2p related operations as the
@@ -180,8 +186,8 @@ func (s *Service) Start() {
 	go s.registerHandlers()
 
 	s.cfg.p2p.AddConnectionHandler(s.reValidatePeer, s.sendGoodbye)
-	s.cfg.p2p.AddDisconnectionHandler(func(_ context.Context, _ peer.ID) error {
-		// no-op
+	s.cfg.p2p.AddDisconnectionHandler(func(_ context.Context, pid peer.ID) error {
+		s.rateLimiter.removePeer(pid)
 		return nil
 	})
 	s.cfg.p2p.AddPingMethod(s.sendPingRequest)
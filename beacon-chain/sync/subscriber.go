@@ -125,6 +125,22 @@ func (s *Service) registerSubscribers(epoch primitives.Epoch, digest [4]byte) {
 		}
 	}
 
+	// Light client topics are only served when the light client server is enabled.
+	if features.Get().EnableLightClient && epoch >= params.BeaconConfig().AltairForkEpoch {
+		s.subscribe(
+			p2p.LightClientFinalityUpdateTopicFormat,
+			s.validateLightClientFinalityUpdate,
+			s.lightClientFinalityUpdateSubscriber,
+			digest,
+		)
+		s.subscribe(
+			p2p.LightClientOptimisticUpdateTopicFormat,
+			s.validateLightClientOptimisticUpdate,
+			s.lightClientOptimisticUpdateSubscriber,
+			digest,
+		)
+	}
+
 	// New Gossip Topic in Capella
 	if epoch >= params.BeaconConfig().CapellaForkEpoch {
 		s.subscribe(
@@ -27,7 +27,9 @@ func TestBeaconAggregateProofSubscriber_CanSaveAggregatedAttestation(t *testing.
 	a := &zondpb.SignedAggregateAttestationAndProof{
 		Message: &zondpb.AggregateAttestationAndProof{
 			Aggregate: util.HydrateAttestation(&zondpb.Attestation{
-				AggregationBits: bitfield.Bitlist{0x07},
+				AggregationBits:         bitfield.Bitlist{0x07},
+				Signature:               make([]byte, 2*dilithium2.CryptoBytes),
+				SignatureValidatorIndex: []uint64{0, 1},
 			}),
 			AggregatorIndex: 100,
 		},
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/go-bitfield"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/beacon-chain/blockchain"
 	chainMock "github.com/theQRL/qrysm/v4/beacon-chain/blockchain/testing"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
@@ -23,7 +24,11 @@ import (
 func TestService_beaconBlockSubscriber(t *testing.T) {
 	pooledAttestations := []*zondpb.Attestation{
 		// Aggregated.
-		util.HydrateAttestation(&zondpb.Attestation{AggregationBits: bitfield.Bitlist{0b00011111}}),
+		util.HydrateAttestation(&zondpb.Attestation{
+			AggregationBits:         bitfield.Bitlist{0b00011111},
+			Signature:               make([]byte, 4*dilithium2.CryptoBytes),
+			SignatureValidatorIndex: []uint64{0, 1, 2, 3},
+		}),
 		// Unaggregated.
 		util.HydrateAttestation(&zondpb.Attestation{AggregationBits: bitfield.Bitlist{0b00010001}}),
 	}
@@ -0,0 +1,25 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// Light client updates are produced by the node itself, so the received ones only need to be forwarded, which
+// their validators take care of.
+func (s *Service) lightClientFinalityUpdateSubscriber(_ context.Context, msg proto.Message) error {
+	if _, ok := msg.(*zondpbv2.LightClientFinalityUpdate); !ok {
+		return errors.Errorf("incorrect type of message received, wanted %T but got %T", &zondpbv2.LightClientFinalityUpdate{}, msg)
+	}
+	return nil
+}
+
+func (s *Service) lightClientOptimisticUpdateSubscriber(_ context.Context, msg proto.Message) error {
+	if _, ok := msg.(*zondpbv2.LightClientOptimisticUpdate); !ok {
+		return errors.Errorf("incorrect type of message received, wanted %T but got %T", &zondpbv2.LightClientOptimisticUpdate{}, msg)
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ func TestMain(m *testing.M) {
 		BlockBatchLimitBurstFactor: 10,
 		BlobBatchLimit:             8,
 		BlobBatchLimitBurstFactor:  2,
+		BlockBytesLimit:            2 * 1024 * 1024,
+		BlobBytesLimit:             64 * 1024,
 	})
 	defer func() {
 		flags.Init(resetFlags)
@@ -20,6 +20,7 @@ import (
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/monitoring/tracing"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	prysmTime "github.com/theQRL/qrysm/v4/time"
 	"github.com/theQRL/qrysm/v4/time/slots"
 	"go.opencensus.io/trace"
@@ -158,6 +159,18 @@ func (s *Service) validateAggregatedAtt(ctx context.Context, signed *zondpb.Sign
 		return pubsub.ValidationReject, wrappedErr
 	}
 
+	// Verify the signatures of the aggregate are in committee order, so that the aggregate can be
+	// merged with the other aggregates of its data in the pool.
+	committee, err := helpers.BeaconCommitteeFromState(ctx, bs, signed.Message.Aggregate.Data.Slot, signed.Message.Aggregate.Data.CommitteeIndex)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationIgnore, err
+	}
+	if err := attestation.ValidateSignerOrder(signed.Message.Aggregate, committee); err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationReject, err
+	}
+
 	// Verify selection proof reflects to the right validator.
 	selectionSigSet, err := validateSelectionIndex(ctx, bs, signed.Message.Aggregate.Data, signed.Message.AggregatorIndex, signed.Message.SelectionProof)
 	if err != nil {
@@ -184,7 +197,11 @@ func (s *Service) validateAggregatedAtt(ctx context.Context, signed *zondpb.Sign
 	set := dilithium.NewSet()
 	set.Join(selectionSigSet).Join(aggregatorSigSet).Join(attSigSet)
 
-	return s.validateWithBatchVerifier(ctx, "aggregate", set)
+	res, err := s.validateWithBatchVerifier(ctx, "aggregate", set)
+	if res == pubsub.ValidationAccept {
+		s.cfg.verifiedSigCache.AddBatch(attSigSet)
+	}
+	return res, err
 }
 
 func (s *Service) validateBlockInAttestation(ctx context.Context, satt *zondpb.SignedAggregateAttestationAndProof) bool {
@@ -20,7 +20,7 @@ import (
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 	"github.com/theQRL/qrysm/v4/container/slice"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
@@ -47,8 +47,8 @@ func setupValidAttesterSlashing(t *testing.T) (*zondpb.AttesterSlashing, state.B
 	assert.NoError(t, err)
 	sig0 := privKeys[0].Sign(hashTreeRoot[:])
 	sig1 := privKeys[1].Sign(hashTreeRoot[:])
-	aggregateSig := bls.AggregateSignatures([]bls.Signature{sig0, sig1})
-	att1.Signature = aggregateSig.Marshal()
+	aggregateSig := dilithium.UnaggregatedSignatures([]dilithium.Signature{sig0, sig1})
+	att1.Signature = aggregateSig
 
 	att2 := util.HydrateIndexedAttestation(&zondpb.IndexedAttestation{
 		AttestingIndices: []uint64{0, 1},
@@ -57,8 +57,8 @@ func setupValidAttesterSlashing(t *testing.T) (*zondpb.AttesterSlashing, state.B
 	assert.NoError(t, err)
 	sig0 = privKeys[0].Sign(hashTreeRoot[:])
 	sig1 = privKeys[1].Sign(hashTreeRoot[:])
-	aggregateSig = bls.AggregateSignatures([]bls.Signature{sig0, sig1})
-	att2.Signature = aggregateSig.Marshal()
+	aggregateSig = dilithium.UnaggregatedSignatures([]dilithium.Signature{sig0, sig1})
+	att2.Signature = aggregateSig
 
 	slashing := &zondpb.AttesterSlashing{
 		Attestation_1: att1,
@@ -221,6 +221,9 @@ func (s *Service) validateUnaggregatedAttWithState(ctx context.Context, a *zond.
 	if a.AggregationBits.Count() != 1 || a.AggregationBits.BitIndices()[0] >= len(committee) {
 		return pubsub.ValidationReject, errors.New("attestation bitfield is invalid")
 	}
+	if err := attestation.ValidateSignerOrder(a, committee); err != nil {
+		return pubsub.ValidationReject, err
+	}
 
 	set, err := blocks.AttestationSignatureBatch(ctx, bs, []*zond.Attestation{a})
 	if err != nil {
@@ -228,7 +231,11 @@ func (s *Service) validateUnaggregatedAttWithState(ctx context.Context, a *zond.
 		attBadSignatureBatchCount.Inc()
 		return pubsub.ValidationReject, err
 	}
-	return s.validateWithBatchVerifier(ctx, "attestation", set)
+	res, err := s.validateWithBatchVerifier(ctx, "attestation", set)
+	if res == pubsub.ValidationAccept {
+		s.cfg.verifiedSigCache.AddBatch(set)
+	}
+	return res, err
 }
 
 // Returns true if the attestation was already seen for the participating validator for the slot.
@@ -272,6 +272,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 				for i := 0; ; i++ {
 					if tt.msg.AggregationBits.BitAt(uint64(i)) {
 						tt.msg.Signature = keys[com[i]].Sign(attRoot[:]).Marshal()
+						tt.msg.SignatureValidatorIndex = []uint64{uint64(com[i])}
 						break
 					}
 				}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/monitoring/tracing"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	"go.opencensus.io/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// validateLightClientFinalityUpdate forwards a light client finality update only if it matches the update the
+// node created from its own view of the chain. The node produces a finality update only when it has a newer
+// finalized header than the previous one, so matching updates are never older than the ones already forwarded.
+func (s *Service) validateLightClientFinalityUpdate(ctx context.Context, pid peer.ID, msg *pubsub.Message) (pubsub.ValidationResult, error) {
+	// Validation runs on publish (not just subscriptions), so we should approve any message from
+	// ourselves.
+	if pid == s.cfg.p2p.PeerID() {
+		return pubsub.ValidationAccept, nil
+	}
+	if s.cfg.initialSync.Syncing() {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	ctx, span := trace.StartSpan(ctx, "sync.validateLightClientFinalityUpdate")
+	defer span.End()
+
+	m, err := s.decodePubsubMessage(msg)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationReject, err
+	}
+	update, ok := m.(*zondpbv2.LightClientFinalityUpdate)
+	if !ok {
+		return pubsub.ValidationReject, errWrongMessage
+	}
+	if update.SyncAggregate == nil || update.FinalizedHeader == nil || update.AttestedHeader == nil {
+		return pubsub.ValidationReject, errNilMessage
+	}
+	if s.lightClientUpdateIsEarly(update.SignatureSlot) {
+		return pubsub.ValidationIgnore, nil
+	}
+	if !proto.Equal(update, s.cfg.chain.LightClientFinalityUpdate()) {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	msg.ValidatorData = update
+	return pubsub.ValidationAccept, nil
+}
+
+// validateLightClientOptimisticUpdate forwards a light client optimistic update only if it matches the update the
+// node created from its own view of the chain. The node produces an optimistic update only when it has a newer
+// attested header than the previous one, so matching updates are never older than the ones already forwarded.
+func (s *Service) validateLightClientOptimisticUpdate(ctx context.Context, pid peer.ID, msg *pubsub.Message) (pubsub.ValidationResult, error) {
+	// Validation runs on publish (not just subscriptions), so we should approve any message from
+	// ourselves.
+	if pid == s.cfg.p2p.PeerID() {
+		return pubsub.ValidationAccept, nil
+	}
+	if s.cfg.initialSync.Syncing() {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	ctx, span := trace.StartSpan(ctx, "sync.validateLightClientOptimisticUpdate")
+	defer span.End()
+
+	m, err := s.decodePubsubMessage(msg)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationReject, err
+	}
+	update, ok := m.(*zondpbv2.LightClientOptimisticUpdate)
+	if !ok {
+		return pubsub.ValidationReject, errWrongMessage
+	}
+	if update.SyncAggregate == nil || update.AttestedHeader == nil {
+		return pubsub.ValidationReject, errNilMessage
+	}
+	if s.lightClientUpdateIsEarly(update.SignatureSlot) {
+		return pubsub.ValidationIgnore, nil
+	}
+	if !proto.Equal(update, s.cfg.chain.LightClientOptimisticUpdate()) {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	msg.ValidatorData = update
+	return pubsub.ValidationAccept, nil
+}
+
+// lightClientUpdateIsEarly returns true if an update is received before one third of its signature slot has
+// transpired, allowing for the maximum gossip clock disparity.
+func (s *Service) lightClientUpdateIsEarly(signatureSlot primitives.Slot) bool {
+	start := slots.StartTime(uint64(s.cfg.clock.GenesisTime().Unix()), signatureSlot)
+	due := start.Add(time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second / 3)
+	return s.cfg.clock.Now().Before(due.Add(-params.BeaconNetworkConfig().MaximumGossipClockDisparity))
+}
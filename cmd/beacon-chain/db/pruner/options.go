@@ -0,0 +1,34 @@
+package pruner
+
+import (
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/pruner"
+	"github.com/theQRL/qrysm/v4/beacon-chain/node"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// PruneHistory enables the deletion of the finalized blocks and states which are older than the retention period.
+	PruneHistory = &cli.BoolFlag{
+		Name: "prune-history",
+		Usage: "Deletes the finalized blocks and states which are older than --history-retention-epochs. " +
+			"A pruning node cannot serve older blocks and states to peers or to the beacon API.",
+	}
+	// RetentionEpochs sets the number of epochs before the finalized checkpoint for which the history is kept.
+	RetentionEpochs = &cli.Uint64Flag{
+		Name: "history-retention-epochs",
+		Usage: "Number of epochs before the finalized checkpoint for which blocks and states are kept when --prune-history is set. " +
+			"The node will error at start if the value is less than MIN_EPOCHS_FOR_BLOCK_REQUESTS.",
+		Value: uint64(params.BeaconNetworkConfig().MinEpochsForBlockRequests),
+	}
+)
+
+// BeaconNodeOptions sets the options of the pruner service from the command line flags.
+func BeaconNodeOptions(c *cli.Context) (node.Option, error) {
+	opts := []pruner.ServiceOption{
+		pruner.WithEnablePruning(c.Bool(PruneHistory.Name)),
+		pruner.WithRetentionEpochs(primitives.Epoch(c.Uint64(RetentionEpochs.Name))),
+	}
+	return node.WithPrunerOptions(opts), nil
+}
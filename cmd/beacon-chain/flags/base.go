@@ -151,30 +151,63 @@ var (
 		Usage: "The slot durations of when an archived state gets saved in the beaconDB.",
 		Value: 2048,
 	}
-	// BlockBatchLimit specifies the requested block batch size.
+	// StateDiffIntervals specifies the slot intervals of the layers of hierarchical state diffs that the states of the
+	// cold section of beaconDB are saved as, instead of saving full states at the archived points.
+	StateDiffIntervals = &cli.IntSliceFlag{
+		Name: "state-diff-intervals",
+		Usage: "Saves archived states as hierarchical state diffs in the beaconDB, with the given slot intervals between the " +
+			"states of each layer from the coarsest to the finest, e.g. 262144,32768,4096,512. States are saved in full at the " +
+			"coarsest interval and as a diff against the closest state of the next coarser layer otherwise. Overrides --slots-per-archive-point.",
+	}
+	// BlockBatchLimit specifies the requested block batch size. It no longer bounds the blocks served to a peer,
+	// which is rate limited by BlockBytesLimit.
 	BlockBatchLimit = &cli.IntFlag{
-		Name:  "block-batch-limit",
-		Usage: "The amount of blocks the local peer is bounded to request and respond to in a batch.",
+		Name: "block-batch-limit",
+		Usage: "The amount of blocks the local peer requests, and writes to a stream before waiting for the next " +
+			"second, in a batch. The blocks served to a peer are rate limited by --block-bytes-limit.",
 		Value: 64,
 	}
-	// BlockBatchLimitBurstFactor specifies the factor by which block batch size may increase.
+	// BlockBatchLimitBurstFactor specifies the factor by which the block bytes limit may increase.
 	BlockBatchLimitBurstFactor = &cli.IntFlag{
 		Name:  "block-batch-limit-burst-factor",
-		Usage: "The factor by which block batch limit may increase on burst.",
+		Usage: "The factor by which the block bytes limit may increase on burst.",
 		Value: 2,
 	}
-	// BlobBatchLimit specifies the requested blob batch size.
+	// BlobBatchLimit specifies the requested blob batch size. It no longer bounds the blobs served to a peer,
+	// which is rate limited by BlobBytesLimit.
 	BlobBatchLimit = &cli.IntFlag{
-		Name:  "blob-batch-limit",
-		Usage: "The amount of blobs the local peer is bounded to request and respond to in a batch.",
+		Name: "blob-batch-limit",
+		Usage: "The amount of blobs the local peer writes to a stream before waiting for the next second, in a " +
+			"batch. The blobs served to a peer are rate limited by --blob-bytes-limit.",
 		Value: 8,
 	}
-	// BlobBatchLimitBurstFactor specifies the factor by which blob batch size may increase.
+	// BlobBatchLimitBurstFactor specifies the factor by which the blob bytes limit may increase.
 	BlobBatchLimitBurstFactor = &cli.IntFlag{
 		Name:  "blob-batch-limit-burst-factor",
-		Usage: "The factor by which blob batch limit may increase on burst.",
+		Usage: "The factor by which the blob bytes limit may increase on burst.",
 		Value: 2,
 	}
+	// BlockBytesLimit specifies the amount of serialized block bytes per second served to a peer.
+	BlockBytesLimit = &cli.IntFlag{
+		Name: "block-bytes-limit",
+		Usage: "The amount of serialized block bytes per second the local peer serves to a peer. " +
+			"Bursts are bounded by --block-batch-limit-burst-factor.",
+		Value: 2 * 1024 * 1024,
+	}
+	// BlobBytesLimit specifies the amount of serialized blob sidecar bytes per second served to a peer.
+	BlobBytesLimit = &cli.IntFlag{
+		Name: "blob-bytes-limit",
+		Usage: "The amount of serialized blob sidecar bytes per second the local peer serves to a peer. " +
+			"Bursts are bounded by --blob-batch-limit-burst-factor.",
+		Value: 64 * 1024,
+	}
+	// SignatureVerificationConcurrency specifies the maximum number of signatures verified in parallel.
+	SignatureVerificationConcurrency = &cli.IntFlag{
+		Name: "signature-verification-concurrency",
+		Usage: "The maximum number of signatures verified in parallel when batch verifying signatures. " +
+			"Defaults to the number of available CPUs if not set.",
+		Value: 0,
+	}
 	// EnableDebugRPCEndpoints as /v1/beacon/state.
 	EnableDebugRPCEndpoints = &cli.BoolFlag{
 		Name:  "enable-debug-rpc-endpoints",
@@ -261,6 +294,18 @@ var (
 		Usage: "Directory for the slasher database",
 		Value: cmd.DefaultDataDir(),
 	}
+	// DBBackendFlag defines the storage engine of the beacon node database.
+	DBBackendFlag = &cli.StringFlag{
+		Name: "db-backend",
+		Usage: "Storage engine of the beacon node database, either bolt or pebble. " +
+			"An existing database has to be converted with `qrysmctl db convert` to change its storage engine.",
+		Value: "bolt",
+	}
+	// BlobStoragePathFlag defines the location of the blob sidecar files.
+	BlobStoragePathFlag = &cli.StringFlag{
+		Name:  "blob-path",
+		Usage: "Location of the blob sidecar files. Defaults to a blobs directory in the data directory.",
+	}
 	BlobRetentionEpoch = &cli.Uint64Flag{
 		Name:  "extend-blob-retention-epoch",
 		Usage: "Extend blob retention epoch period to beyond default 4096 epochs (~18 days). The node will error at start if input value is less than 4096 epochs.",
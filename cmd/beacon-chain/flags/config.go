@@ -15,6 +15,8 @@ type GlobalFlags struct {
 	BlockBatchLimitBurstFactor int
 	BlobBatchLimit             int
 	BlobBatchLimitBurstFactor  int
+	BlockBytesLimit            int
+	BlobBytesLimit             int
 }
 
 var globalConfig *GlobalFlags
@@ -44,6 +46,8 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 	cfg.BlobBatchLimit = ctx.Int(BlobBatchLimit.Name)
 	cfg.BlobBatchLimitBurstFactor = ctx.Int(BlobBatchLimitBurstFactor.Name)
 	cfg.BlockBatchLimitBurstFactor = ctx.Int(BlockBatchLimitBurstFactor.Name)
+	cfg.BlockBytesLimit = ctx.Int(BlockBytesLimit.Name)
+	cfg.BlobBytesLimit = ctx.Int(BlobBytesLimit.Name)
 	cfg.MinimumPeersPerSubnet = ctx.Int(MinPeersPerSubnet.Name)
 	configureMinimumPeers(ctx, cfg)
 
@@ -16,9 +16,11 @@ import (
 	"github.com/theQRL/qrysm/v4/cmd"
 	blockchaincmd "github.com/theQRL/qrysm/v4/cmd/beacon-chain/blockchain"
 	dbcommands "github.com/theQRL/qrysm/v4/cmd/beacon-chain/db"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/db/pruner"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/execution"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	jwtcommands "github.com/theQRL/qrysm/v4/cmd/beacon-chain/jwt"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/backfill"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/checkpoint"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/genesis"
 	"github.com/theQRL/qrysm/v4/config/features"
@@ -55,10 +57,14 @@ var appFlags = []cli.Flag{
 	flags.BlockBatchLimitBurstFactor,
 	flags.BlobBatchLimit,
 	flags.BlobBatchLimitBurstFactor,
+	flags.BlockBytesLimit,
+	flags.BlobBytesLimit,
+	flags.SignatureVerificationConcurrency,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropNumValidatorsFlag,
 	flags.InteropGenesisTimeFlag,
 	flags.SlotsPerArchivedPoint,
+	flags.StateDiffIntervals,
 	flags.EnableDebugRPCEndpoints,
 	flags.SubscribeToAllSubnets,
 	flags.HistoricalSlasherNode,
@@ -77,16 +83,19 @@ var appFlags = []cli.Flag{
 	flags.EngineEndpointTimeoutSeconds,
 	flags.LocalBlockValueBoost,
 	flags.BlobRetentionEpoch,
+	flags.BlobStoragePathFlag,
 	cmd.BackupWebhookOutputDir,
 	cmd.MinimalConfigFlag,
 	cmd.E2EConfigFlag,
 	cmd.RPCMaxPageSizeFlag,
 	cmd.BootstrapNode,
+	cmd.DNSDiscoveryURL,
 	cmd.NoDiscovery,
 	cmd.StaticPeers,
 	cmd.RelayNode,
 	cmd.P2PUDPPort,
 	cmd.P2PTCPPort,
+	cmd.P2PQUICPort,
 	cmd.P2PIP,
 	cmd.P2PHost,
 	cmd.P2PHostDNS,
@@ -130,9 +139,16 @@ var appFlags = []cli.Flag{
 	checkpoint.BlockPath,
 	checkpoint.StatePath,
 	checkpoint.RemoteURL,
+	checkpoint.Quorum,
+	backfill.DisableBackfill,
+	backfill.BatchSize,
+	backfill.BatchInterval,
+	pruner.PruneHistory,
+	pruner.RetentionEpochs,
 	genesis.StatePath,
 	genesis.BeaconAPIURL,
 	flags.SlasherDirFlag,
+	flags.DBBackendFlag,
 }
 
 func init() {
@@ -278,6 +294,8 @@ func startNode(ctx *cli.Context) error {
 	optFuncs := []func(*cli.Context) (node.Option, error){
 		genesis.BeaconNodeOptions,
 		checkpoint.BeaconNodeOptions,
+		backfill.BeaconNodeOptions,
+		pruner.BeaconNodeOptions,
 	}
 	for _, of := range optFuncs {
 		ofo, err := of(ctx)
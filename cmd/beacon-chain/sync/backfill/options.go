@@ -0,0 +1,41 @@
+package backfill
+
+import (
+	"time"
+
+	"github.com/theQRL/qrysm/v4/beacon-chain/node"
+	"github.com/theQRL/qrysm/v4/beacon-chain/sync/backfill"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// DisableBackfill prevents a checkpoint synced node from downloading the blocks between genesis and the
+	// checkpoint sync origin.
+	DisableBackfill = &cli.BoolFlag{
+		Name: "disable-backfill",
+		Usage: "Disables the download of the blocks between genesis and the checkpoint sync origin. " +
+			"Nodes which do not backfill cannot serve historical blocks to peers or to the beacon API.",
+	}
+	// BatchSize sets the number of slots requested from a peer in a single backfill batch.
+	BatchSize = &cli.Uint64Flag{
+		Name:  "backfill-batch-size",
+		Usage: "Number of slots requested from a peer in a single backfill batch.",
+		Value: 64,
+	}
+	// BatchInterval throttles backfill by setting the minimum time between two batch requests.
+	BatchInterval = &cli.DurationFlag{
+		Name:  "backfill-batch-interval",
+		Usage: "Minimum time between two backfill batch requests, used to limit the resources spent on backfill.",
+		Value: time.Second,
+	}
+)
+
+// BeaconNodeOptions sets the options of the backfill service from the command line flags.
+func BeaconNodeOptions(c *cli.Context) (node.Option, error) {
+	opts := []backfill.ServiceOption{
+		backfill.WithEnableBackfill(!c.Bool(DisableBackfill.Name)),
+		backfill.WithBatchSize(c.Uint64(BatchSize.Name)),
+		backfill.WithBatchInterval(c.Duration(BatchInterval.Name)),
+	}
+	return node.WithBackfillOptions(opts), nil
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/helpers"
 	"github.com/theQRL/qrysm/v4/beacon-chain/node"
 	"github.com/theQRL/qrysm/v4/beacon-chain/sync/checkpoint"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
 	"github.com/urfave/cli/v2"
 )
 
@@ -22,12 +24,21 @@ var (
 		Usage: "Rather than syncing from genesis, you can start processing from a ssz-serialized BeaconState+Block." +
 			" This flag allows you to specify a local file containing the checkpoint Block to load.",
 	}
-	RemoteURL = &cli.StringFlag{
+	RemoteURL = &cli.StringSliceFlag{
 		Name: "checkpoint-sync-url",
 		Usage: "URL of a synced beacon node to trust in obtaining checkpoint sync data. " +
+			"The flag can be repeated to use several providers, in which case the node only starts from a finalized " +
+			"checkpoint reported by a quorum of them (see --checkpoint-sync-quorum). " +
 			"As an additional safety measure, it is strongly recommended to only use this option in conjunction with " +
 			"--weak-subjectivity-checkpoint flag",
 	}
+	// Quorum is the number of checkpoint sync providers which must agree on the finalized checkpoint.
+	Quorum = &cli.IntFlag{
+		Name: "checkpoint-sync-quorum",
+		Usage: "Number of --checkpoint-sync-url providers which must report the same finalized checkpoint for the " +
+			"node to start from it. When --genesis-beacon-api-url is not set, it is also the number of providers which " +
+			"must serve the same genesis state. Defaults to a majority of the providers.",
+	}
 )
 
 // BeaconNodeOptions is responsible for determining if the checkpoint sync options have been used, and if so,
@@ -36,13 +47,28 @@ var (
 func BeaconNodeOptions(c *cli.Context) (node.Option, error) {
 	blockPath := c.Path(BlockPath.Name)
 	statePath := c.Path(StatePath.Name)
-	remoteURL := c.String(RemoteURL.Name)
-	if remoteURL != "" {
+	remoteURLs := c.StringSlice(RemoteURL.Name)
+	if len(remoteURLs) > 0 {
+		ws, err := helpers.ParseWeakSubjectivityInputString(c.String(flags.WeakSubjectivityCheckpoint.Name))
+		if err != nil {
+			return nil, err
+		}
+		quorum := c.Int(Quorum.Name)
+		if len(remoteURLs) == 1 && ws == nil && quorum == 0 {
+			return func(node *node.BeaconNode) error {
+				var err error
+				node.CheckpointInitializer, err = checkpoint.NewAPIInitializer(remoteURLs[0])
+				if err != nil {
+					return errors.Wrap(err, "error while constructing beacon node api client for checkpoint sync")
+				}
+				return nil
+			}, nil
+		}
 		return func(node *node.BeaconNode) error {
 			var err error
-			node.CheckpointInitializer, err = checkpoint.NewAPIInitializer(remoteURL)
+			node.CheckpointInitializer, err = checkpoint.NewQuorumInitializer(remoteURLs, quorum, ws)
 			if err != nil {
-				return errors.Wrap(err, "error while constructing beacon node api client for checkpoint sync")
+				return errors.Wrap(err, "error while constructing beacon node api clients for checkpoint sync")
 			}
 			return nil
 		}, nil
@@ -30,9 +30,20 @@ var (
 func BeaconNodeOptions(c *cli.Context) (node.Option, error) {
 	statePath := c.Path(StatePath.Name)
 	remoteURL := c.String(BeaconAPIURL.Name)
-	if remoteURL == "" && c.String(checkpoint.RemoteURL.Name) != "" {
-		log.Infof("using checkpoint sync url %s for value in --%s flag", c.String(checkpoint.RemoteURL.Name), BeaconAPIURL.Name)
-		remoteURL = c.String(checkpoint.RemoteURL.Name)
+	if checkpointURLs := c.StringSlice(checkpoint.RemoteURL.Name); remoteURL == "" && len(checkpointURLs) > 1 {
+		log.Infof("using a quorum of the checkpoint sync urls for value in --%s flag", BeaconAPIURL.Name)
+		quorum := c.Int(checkpoint.Quorum.Name)
+		return func(node *node.BeaconNode) error {
+			var err error
+			node.GenesisInitializer, err = genesis.NewQuorumAPIInitializer(checkpointURLs, quorum)
+			if err != nil {
+				return errors.Wrap(err, "error constructing beacon node api clients for genesis state init")
+			}
+			return nil
+		}, nil
+	} else if remoteURL == "" && len(checkpointURLs) == 1 {
+		log.Infof("using checkpoint sync url %s for value in --%s flag", checkpointURLs[0], BeaconAPIURL.Name)
+		remoteURL = checkpointURLs[0]
 	}
 	if remoteURL != "" {
 		return func(node *node.BeaconNode) error {
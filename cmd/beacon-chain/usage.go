@@ -6,7 +6,9 @@ import (
 	"sort"
 
 	"github.com/theQRL/qrysm/v4/cmd"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/db/pruner"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/flags"
+	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/backfill"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/checkpoint"
 	"github.com/theQRL/qrysm/v4/cmd/beacon-chain/sync/genesis"
 	"github.com/theQRL/qrysm/v4/config/features"
@@ -51,9 +53,11 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.RPCMaxPageSizeFlag,
 			cmd.NoDiscovery,
 			cmd.BootstrapNode,
+			cmd.DNSDiscoveryURL,
 			cmd.RelayNode,
 			cmd.P2PUDPPort,
 			cmd.P2PTCPPort,
+			cmd.P2PQUICPort,
 			cmd.DataDirFlag,
 			cmd.VerbosityFlag,
 			cmd.EnableTracingFlag,
@@ -110,10 +114,14 @@ var appHelpFlagGroups = []flagGroup{
 			flags.ExecutionJWTSecretFlag,
 			flags.SetGCPercent,
 			flags.SlotsPerArchivedPoint,
+			flags.StateDiffIntervals,
 			flags.BlockBatchLimit,
 			flags.BlockBatchLimitBurstFactor,
 			flags.BlobBatchLimit,
 			flags.BlobBatchLimitBurstFactor,
+			flags.BlockBytesLimit,
+			flags.BlobBytesLimit,
+			flags.SignatureVerificationConcurrency,
 			flags.EnableDebugRPCEndpoints,
 			flags.SubscribeToAllSubnets,
 			flags.HistoricalSlasherNode,
@@ -127,11 +135,19 @@ var appHelpFlagGroups = []flagGroup{
 			flags.MaxBuilderConsecutiveMissedSlots,
 			flags.EngineEndpointTimeoutSeconds,
 			flags.SlasherDirFlag,
+			flags.DBBackendFlag,
 			flags.LocalBlockValueBoost,
 			flags.BlobRetentionEpoch,
+			flags.BlobStoragePathFlag,
 			checkpoint.BlockPath,
 			checkpoint.StatePath,
 			checkpoint.RemoteURL,
+			checkpoint.Quorum,
+			backfill.DisableBackfill,
+			backfill.BatchSize,
+			backfill.BatchInterval,
+			pruner.PruneHistory,
+			pruner.RetentionEpochs,
 			genesis.StatePath,
 			genesis.BeaconAPIURL,
 		},
@@ -101,6 +101,12 @@ var (
 		Usage: "The address of bootstrap node. Beacon node will connect for peer discovery via DHT.  Multiple nodes can be passed by using the flag multiple times but not comma-separated. You can also pass YAML files containing multiple nodes.",
 		Value: cli.NewStringSlice(params.BeaconNetworkConfig().BootstrapNodes...),
 	}
+	// DNSDiscoveryURL specifies the EIP-1459 node trees that the beacon node discovers peers from.
+	DNSDiscoveryURL = &cli.StringSliceFlag{
+		Name: "dns-discovery-url",
+		Usage: "An enrtree:// URL of a signed list of nodes published in DNS (EIP-1459). Nodes of the list are used " +
+			"alongside discv5 to find peers. Multiple lists can be passed by using the flag multiple times.",
+	}
 	// RelayNode tells the beacon node which relay node to connect to.
 	RelayNode = &cli.StringFlag{
 		Name: "relay-node",
@@ -120,6 +126,12 @@ var (
 		Usage: "The port used by libp2p.",
 		Value: 13000,
 	}
+	// P2PQUICPort defines the udp port to be used by the libp2p quic transport.
+	P2PQUICPort = &cli.IntFlag{
+		Name:  "p2p-quic-port",
+		Usage: "The udp port used by libp2p's quic transport. Only used with --enable-quic.",
+		Value: 13000,
+	}
 	// P2PIP defines the local IP to be used by libp2p.
 	P2PIP = &cli.StringFlag{
 		Name:  "p2p-local-ip",
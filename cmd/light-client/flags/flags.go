@@ -0,0 +1,40 @@
+// Package flags contains all configuration runtime flags for
+// the light client.
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// BeaconNodeURLFlag defines a flag for the URL of the beacon node REST API serving light client updates.
+	BeaconNodeURLFlag = &cli.StringFlag{
+		Name:  "beacon-node-url",
+		Usage: "Full URL of the beacon node REST API serving light client updates. The beacon node must run with --enable-lightclient. eg http://localhost:3500",
+		Value: "http://localhost:3500",
+	}
+	// TrustedBlockRootFlag defines a flag for the root of the block the light client starts from.
+	TrustedBlockRootFlag = &cli.StringFlag{
+		Name: "trusted-block-root",
+		Usage: "Hex encoded root of the block the light client starts from, usually a recent finalized block root " +
+			"obtained from a trusted source. eg 0x1234...",
+		Required: true,
+	}
+	// GenesisValidatorsRootFlag defines a flag for the expected genesis validators root of the chain.
+	GenesisValidatorsRootFlag = &cli.StringFlag{
+		Name:  "genesis-validators-root",
+		Usage: "Hex encoded genesis validators root of the chain. When set, the light client refuses to follow a beacon node on another chain.",
+	}
+	// HTTPHostFlag defines a flag for the host of the light client API.
+	HTTPHostFlag = &cli.StringFlag{
+		Name:  "http-host",
+		Usage: "Host on which the light client API listens.",
+		Value: "127.0.0.1",
+	}
+	// HTTPPortFlag defines a flag for the port of the light client API.
+	HTTPPortFlag = &cli.IntFlag{
+		Name:  "http-port",
+		Usage: "Port on which the light client API listens, 0 disables the API.",
+		Value: 3600,
+	}
+)
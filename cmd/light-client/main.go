@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	runtimeDebug "runtime/debug"
+	"syscall"
+
+	joonix "github.com/joonix/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/api/client/beacon"
+	"github.com/theQRL/qrysm/v4/cmd"
+	"github.com/theQRL/qrysm/v4/cmd/light-client/flags"
+	"github.com/theQRL/qrysm/v4/config/features"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/io/logs"
+	lightclient "github.com/theQRL/qrysm/v4/light-client"
+	"github.com/theQRL/qrysm/v4/monitoring/journald"
+	prefixed "github.com/theQRL/qrysm/v4/runtime/logging/logrus-prefixed-formatter"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/urfave/cli/v2"
+)
+
+var appFlags = []cli.Flag{
+	cmd.VerbosityFlag,
+	cmd.LogFormat,
+	cmd.LogFileName,
+	cmd.ConfigFileFlag,
+	cmd.ChainConfigFileFlag,
+	flags.BeaconNodeURLFlag,
+	flags.TrustedBlockRootFlag,
+	flags.GenesisValidatorsRootFlag,
+	flags.HTTPHostFlag,
+	flags.HTTPPortFlag,
+}
+
+func init() {
+	appFlags = cmd.WrapFlags(append(appFlags, features.NetworkFlags...))
+}
+
+func main() {
+	app := cli.App{}
+	app.Name = "light-client"
+	app.Usage = "follows the Zond beacon chain from a trusted block root by verifying sync committee signatures"
+	app.Action = run
+	app.Version = version.Version()
+
+	app.Flags = appFlags
+
+	app.Before = func(ctx *cli.Context) error {
+		// Load flags from config file, if specified.
+		if err := cmd.LoadFlagsFromConfig(ctx, app.Flags); err != nil {
+			return err
+		}
+
+		verbosity := ctx.String(cmd.VerbosityFlag.Name)
+		level, err := logrus.ParseLevel(verbosity)
+		if err != nil {
+			return err
+		}
+		logrus.SetLevel(level)
+
+		format := ctx.String(cmd.LogFormat.Name)
+		switch format {
+		case "text":
+			formatter := new(prefixed.TextFormatter)
+			formatter.TimestampFormat = "2006-01-02 15:04:05"
+			formatter.FullTimestamp = true
+			// If persistent log files are written - we disable the log messages coloring because
+			// the colors are ANSI codes and seen as gibberish in the log files.
+			formatter.DisableColors = ctx.String(cmd.LogFileName.Name) != ""
+			logrus.SetFormatter(formatter)
+		case "fluentd":
+			f := joonix.NewFormatter()
+			if err := joonix.DisableTimestampFormat(f); err != nil {
+				panic(err)
+			}
+			logrus.SetFormatter(f)
+		case "json":
+			logrus.SetFormatter(&logrus.JSONFormatter{})
+		case "journald":
+			if err := journald.Enable(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown log format %s", format)
+		}
+
+		logFileName := ctx.String(cmd.LogFileName.Name)
+		if logFileName != "" {
+			if err := logs.ConfigurePersistentLogging(logFileName); err != nil {
+				log.WithError(err).Error("Failed to configuring logging to disk.")
+			}
+		}
+		return cmd.ValidateNoArgs(ctx)
+	}
+
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("Runtime panic: %v\n%v", x, string(runtimeDebug.Stack()))
+			panic(x)
+		}
+	}()
+
+	if err := app.Run(os.Args); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func run(ctx *cli.Context) error {
+	if err := features.ConfigureLightClient(ctx); err != nil {
+		return err
+	}
+	if ctx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		if err := params.LoadChainConfigFile(ctx.String(cmd.ChainConfigFileFlag.Name), nil); err != nil {
+			return err
+		}
+	}
+
+	trustedRoot, err := decodeRoot(ctx.String(flags.TrustedBlockRootFlag.Name))
+	if err != nil {
+		return errors.Wrapf(err, "invalid --%s", flags.TrustedBlockRootFlag.Name)
+	}
+	cfg := &lightclient.Config{
+		TrustedBlockRoot: trustedRoot,
+		HTTPHost:         ctx.String(flags.HTTPHostFlag.Name),
+		HTTPPort:         ctx.Int(flags.HTTPPortFlag.Name),
+	}
+	if ctx.IsSet(flags.GenesisValidatorsRootFlag.Name) {
+		gvr, err := decodeRoot(ctx.String(flags.GenesisValidatorsRootFlag.Name))
+		if err != nil {
+			return errors.Wrapf(err, "invalid --%s", flags.GenesisValidatorsRootFlag.Name)
+		}
+		cfg.GenesisValidatorsRoot = &gvr
+	}
+	cfg.BeaconNode, err = beacon.NewClient(ctx.String(flags.BeaconNodeURLFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	c, cancel := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	s, err := lightclient.NewService(c, cfg)
+	if err != nil {
+		return err
+	}
+	s.Start()
+	<-c.Done()
+	log.Info("Stopping light client")
+	return s.Stop()
+}
+
+func decodeRoot(s string) ([32]byte, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(b) != 32 {
+		return [32]byte{}, errors.Errorf("root has length %d, expected 32", len(b))
+	}
+	return bytesutil.ToBytes32(b), nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/urfave/cli/v2"
+)
+
+var convertFlags = struct {
+	Path string
+	From string
+	To   string
+}{}
+
+var convertCmd = &cli.Command{
+	Name:  "convert",
+	Usage: "convert the beacon db to another storage engine. The beacon node must be stopped",
+	Action: func(cliCtx *cli.Context) error {
+		if err := convertAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not convert db")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to the beaconchaindata directory containing the database",
+			Destination: &convertFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "from",
+			Usage:       "storage engine of the existing database, bolt or pebble. Detected from the database when not set",
+			Destination: &convertFlags.From,
+		},
+		&cli.StringFlag{
+			Name:        "to",
+			Usage:       "storage engine to convert the database to, bolt or pebble",
+			Destination: &convertFlags.To,
+			Required:    true,
+		},
+	},
+}
+
+func convertAction(_ *cli.Context) error {
+	flags := convertFlags
+	to, err := engine.ParseBackend(flags.To)
+	if err != nil {
+		return err
+	}
+	var from engine.Backend
+	if flags.From != "" {
+		from, err = engine.ParseBackend(flags.From)
+		if err != nil {
+			return err
+		}
+	} else {
+		var ok bool
+		from, ok, err = kv.DetectBackend(flags.Path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Errorf("no database found in %s", flags.Path)
+		}
+	}
+	if err := kv.ConvertBackend(flags.Path, from, to); err != nil {
+		return err
+	}
+	log.WithField("path", kv.KVStoreDatapath(flags.Path, to)).Infof(
+		"Converted database, start the beacon node with --db-backend=%s. The %s database at %s can be removed once the node runs",
+		to, from, kv.KVStoreDatapath(flags.Path, from),
+	)
+	return nil
+}
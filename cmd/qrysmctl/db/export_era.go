@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/era"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/filesystem"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/urfave/cli/v2"
+)
+
+var exportEraFlags = struct {
+	Path     string
+	BlobPath string
+	Output   string
+	Start    uint64
+	End      uint64
+}{}
+
+var exportEraCmd = &cli.Command{
+	Name:  "export-era",
+	Usage: "export the finalized history of the beacon db to era files. The beacon node must be stopped",
+	Action: func(cliCtx *cli.Context) error {
+		if err := exportEraAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not export era files")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to the beaconchaindata directory containing the database",
+			Destination: &exportEraFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "blob-path",
+			Usage:       "path to the blob storage directory of the beacon node. The blocks are exported without their blob sidecars when not set",
+			Destination: &exportEraFlags.BlobPath,
+		},
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "directory to write the era files to",
+			Destination: &exportEraFlags.Output,
+			Required:    true,
+		},
+		&cli.Uint64Flag{
+			Name:        "start",
+			Usage:       "first era to export",
+			Destination: &exportEraFlags.Start,
+		},
+		&cli.Uint64Flag{
+			Name:        "end",
+			Usage:       "last era to export. Defaults to the last finalized era",
+			Destination: &exportEraFlags.End,
+		},
+	},
+}
+
+func exportEraAction(cliCtx *cli.Context) error {
+	flags := exportEraFlags
+	ctx := context.Background()
+	backend, ok, err := kv.DetectBackend(flags.Path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("no database found in %s", flags.Path)
+	}
+	beaconDB, err := kv.NewKVStore(ctx, flags.Path, kv.WithBackend(backend))
+	if err != nil {
+		return errors.Wrap(err, "could not open database")
+	}
+	defer func() {
+		if err := beaconDB.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+	var blobs era.BlobGetter
+	if flags.BlobPath != "" {
+		// Opening the blob storage prunes the blobs older than the retention period, which must not happen here.
+		bs, err := filesystem.NewBlobStorage(flags.BlobPath, filesystem.WithBlobRetentionEpochs(primitives.Epoch(math.MaxUint64)))
+		if err != nil {
+			return err
+		}
+		blobs = bs
+	}
+	e, err := era.NewExporter(ctx, beaconDB, blobs)
+	if err != nil {
+		return err
+	}
+	end := e.LastEra()
+	if cliCtx.IsSet("end") {
+		end = flags.End
+	}
+	if flags.Start > end {
+		return errors.Errorf("start era %d is after end era %d", flags.Start, end)
+	}
+	for i := flags.Start; i <= end; i++ {
+		if _, err := e.Export(ctx, flags.Output, i); err != nil {
+			return errors.Wrapf(err, "could not export era %d", i)
+		}
+	}
+	log.WithField("output", flags.Output).Infof("Exported eras %d to %d", flags.Start, end)
+	return nil
+}
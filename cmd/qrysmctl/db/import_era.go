@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/era"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/filesystem"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/beacon-chain/state/genesis"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/urfave/cli/v2"
+)
+
+var importEraFlags = struct {
+	Path     string
+	Backend  string
+	BlobPath string
+	Input    string
+	GVR      string
+}{}
+
+var importEraCmd = &cli.Command{
+	Name:  "import-era",
+	Usage: "verify era files and import their history into an empty beacon db",
+	Action: func(cliCtx *cli.Context) error {
+		if err := importEraAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not import era files")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to the beaconchaindata directory to create the database in",
+			Destination: &importEraFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "backend",
+			Usage:       "storage engine of the database, bolt or pebble",
+			Value:       string(engine.Bolt),
+			Destination: &importEraFlags.Backend,
+		},
+		&cli.StringFlag{
+			Name:        "blob-path",
+			Usage:       "path to the blob storage directory of the beacon node. The blob sidecars are not imported when not set",
+			Destination: &importEraFlags.BlobPath,
+		},
+		&cli.StringFlag{
+			Name:        "input",
+			Usage:       "directory containing the era files, which are imported in order starting from era 0",
+			Destination: &importEraFlags.Input,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "genesis-validators-root",
+			Usage:       "hex encoded genesis validators root of the network, which the genesis state of era 0 must match. Defaults to the one of the embedded genesis state of the network",
+			Destination: &importEraFlags.GVR,
+		},
+	},
+}
+
+func importEraAction(_ *cli.Context) error {
+	flags := importEraFlags
+	ctx := context.Background()
+	backend, err := engine.ParseBackend(flags.Backend)
+	if err != nil {
+		return err
+	}
+	gvr, err := trustedGenesisValidatorsRoot(flags.GVR)
+	if err != nil {
+		return err
+	}
+	// The file names hold the network name followed by the zero padded era number, so they sort by era.
+	paths, err := filepath.Glob(filepath.Join(flags.Input, "*.era"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errors.Errorf("no era files found in %s", flags.Input)
+	}
+	sort.Strings(paths)
+	beaconDB, err := kv.NewKVStore(ctx, flags.Path, kv.WithBackend(backend))
+	if err != nil {
+		return errors.Wrap(err, "could not open database")
+	}
+	defer func() {
+		if err := beaconDB.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+	var blobs era.BlobSaver
+	if flags.BlobPath != "" {
+		// The beacon node prunes the blobs older than its own retention period once it starts.
+		bs, err := filesystem.NewBlobStorage(flags.BlobPath, filesystem.WithBlobRetentionEpochs(primitives.Epoch(math.MaxUint64)))
+		if err != nil {
+			return err
+		}
+		blobs = bs
+	}
+	i, err := era.NewImporter(ctx, beaconDB, blobs, gvr)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := i.Import(ctx, path); err != nil {
+			return errors.Wrapf(err, "could not import %s", path)
+		}
+	}
+	if err := i.Finish(ctx); err != nil {
+		return err
+	}
+	log.WithField("path", kv.KVStoreDatapath(flags.Path, backend)).Infof("Imported %d era files", len(paths))
+	return nil
+}
+
+// trustedGenesisValidatorsRoot returns the genesis validators root given on the command line, or the one of the
+// embedded genesis state of the network when none is given.
+func trustedGenesisValidatorsRoot(flag string) ([32]byte, error) {
+	if flag != "" {
+		b, err := hexutil.Decode(flag)
+		if err != nil || len(b) != 32 {
+			return [32]byte{}, errors.Errorf("invalid genesis validators root %s", flag)
+		}
+		return bytesutil.ToBytes32(b), nil
+	}
+	st, err := genesis.State(params.BeaconConfig().ConfigName)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not load the embedded genesis state, the genesis validators root must be set")
+	}
+	if st == nil {
+		return [32]byte{}, errors.Errorf("no embedded genesis state for network %s, the genesis validators root must be set", params.BeaconConfig().ConfigName)
+	}
+	return bytesutil.ToBytes32(st.GenesisValidatorsRoot()), nil
+}
@@ -3,14 +3,13 @@ package db
 import (
 	"bytes"
 	"fmt"
-	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/theQRL/go-zond/common/hexutil"
-	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/beacon-chain/db/kv/engine"
+	"github.com/theQRL/qrysm/v4/io/file"
 	"github.com/urfave/cli/v2"
-	bolt "go.etcd.io/bbolt"
 )
 
 var queryFlags = struct {
@@ -37,7 +36,7 @@ var queryCmd = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "path",
-			Usage:       "path to directory containing beaconchain.db",
+			Usage:       "path to beaconchain.db, or to the beaconchain.pebble directory",
 			Destination: &queryFlags.Path,
 		},
 		&cli.StringFlag{
@@ -65,7 +64,7 @@ func queryAction(_ *cli.Context) error {
 	return nil
 }
 
-func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
+func prefixScan(db engine.DB, bucket, prefix string, keysOnly bool) error {
 	if !keysOnly {
 		return errors.New("prefix scan with value display not implemented")
 	}
@@ -74,7 +73,7 @@ func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
 		return err
 	}
 	log.Infof("scanning for prefix=%#x", pb)
-	return db.View(func(tx *bolt.Tx) error {
+	return db.View(func(tx engine.Tx) error {
 		b := tx.Bucket([]byte(bucket))
 		c := b.Cursor()
 		for k, _ := c.Seek(pb); k != nil && bytes.HasPrefix(k, pb); k, _ = c.Next() {
@@ -84,19 +83,15 @@ func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
 	})
 }
 
-func getDB(path string) (*bolt.DB, error) {
-	bdb, err := bolt.Open(
-		path,
-		params.BeaconIoConfig().ReadWritePermissions,
-		&bolt.Options{
-			Timeout: 1 * time.Second,
-		},
-	)
+// getDB opens the database at path, which is a directory for pebble databases.
+func getDB(path string) (engine.DB, error) {
+	backend := engine.Bolt
+	isDir, err := file.HasDir(path)
 	if err != nil {
-		if errors.Is(err, bolt.ErrTimeout) {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
 		return nil, err
 	}
-	return bdb, nil
+	if isDir {
+		backend = engine.Pebble
+	}
+	return engine.Open(backend, path, nil)
 }
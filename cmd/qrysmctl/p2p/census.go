@@ -0,0 +1,211 @@
+package p2p
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/network/forks"
+)
+
+const (
+	censusFormatJSON = "json"
+	censusFormatCSV  = "csv"
+)
+
+// nodeRecord is what the crawler learned about a node, from its ENR and, when the node could be reached, from the
+// status and metadata handshake.
+type nodeRecord struct {
+	NodeID          string           `json:"node_id"`
+	PeerID          string           `json:"peer_id,omitempty"`
+	ENR             string           `json:"enr"`
+	IP              string           `json:"ip"`
+	TCPPort         int              `json:"tcp_port"`
+	ForkDigest      string           `json:"fork_digest,omitempty"`
+	NextForkVersion string           `json:"next_fork_version,omitempty"`
+	NextForkEpoch   primitives.Epoch `json:"next_fork_epoch,omitempty"`
+	Reachable       bool             `json:"reachable"`
+	Error           string           `json:"error,omitempty"`
+	Agent           string           `json:"agent,omitempty"`
+	HeadSlot        primitives.Slot  `json:"head_slot,omitempty"`
+	FinalizedEpoch  primitives.Epoch `json:"finalized_epoch,omitempty"`
+	Attnets         string           `json:"attnets,omitempty"`
+	Syncnets        string           `json:"syncnets,omitempty"`
+	LatencyMillis   int64            `json:"latency_ms,omitempty"`
+}
+
+// censusSummary counts the nodes of a census by fork digest, by the next fork they are ready for and by client.
+// Clients are only known for reachable nodes.
+type censusSummary struct {
+	Nodes       int            `json:"nodes"`
+	Reachable   int            `json:"reachable"`
+	ForkDigests map[string]int `json:"fork_digests"`
+	NextForks   map[string]int `json:"next_forks"`
+	Clients     map[string]int `json:"clients"`
+}
+
+// census is the result of a crawl of the network.
+type census struct {
+	Time    time.Time      `json:"time"`
+	Summary *censusSummary `json:"summary"`
+	Nodes   []*nodeRecord  `json:"nodes"`
+}
+
+// summarize counts the nodes of a census. Fork digests are annotated with the name of their fork when the genesis
+// validators root of the network is known.
+func summarize(nodes []*nodeRecord, genesisValidatorsRoot []byte) *censusSummary {
+	s := &censusSummary{
+		Nodes:       len(nodes),
+		ForkDigests: make(map[string]int),
+		NextForks:   make(map[string]int),
+		Clients:     make(map[string]int),
+	}
+	for _, n := range nodes {
+		if n.Reachable {
+			s.Reachable++
+			s.Clients[clientName(n.Agent)]++
+		}
+		if n.ForkDigest == "" {
+			continue
+		}
+		s.ForkDigests[forkDigestName(n.ForkDigest, genesisValidatorsRoot)]++
+		s.NextForks[nextForkName(n.NextForkVersion, n.NextForkEpoch)]++
+	}
+	return s
+}
+
+// clientName is the client implementation in a libp2p agent version, such as "qrysm" for "qrysm/v1.0.0/abcdef".
+func clientName(agent string) string {
+	if agent == "" {
+		return "unknown"
+	}
+	return strings.ToLower(strings.SplitN(agent, "/", 2)[0])
+}
+
+func forkDigestName(digest string, genesisValidatorsRoot []byte) string {
+	if len(genesisValidatorsRoot) == 0 {
+		return digest
+	}
+	b, err := hexutil.Decode(digest)
+	if err != nil || len(b) != 4 {
+		return digest
+	}
+	v, _, err := forks.RetrieveForkDataFromDigest(bytesutil.ToBytes4(b), genesisValidatorsRoot)
+	if err != nil {
+		return digest + " (unknown fork)"
+	}
+	return fmt.Sprintf("%s (%s)", digest, forkVersionName(v))
+}
+
+func nextForkName(nextVersion string, nextEpoch primitives.Epoch) string {
+	if nextVersion == "" || nextEpoch == params.BeaconConfig().FarFutureEpoch {
+		return "none scheduled"
+	}
+	name := nextVersion
+	if b, err := hexutil.Decode(nextVersion); err == nil && len(b) == 4 {
+		name = fmt.Sprintf("%s (%s)", nextVersion, forkVersionName(bytesutil.ToBytes4(b)))
+	}
+	return fmt.Sprintf("%s at epoch %d", name, nextEpoch)
+}
+
+func forkVersionName(v [4]byte) string {
+	if name, ok := params.BeaconConfig().ForkVersionNames[v]; ok {
+		return name
+	}
+	return "unknown fork"
+}
+
+// writeCensus writes a census in the given format. The JSON format includes the summary, the CSV format only has one
+// row per node.
+func writeCensus(w io.Writer, c *census, format string) error {
+	switch format {
+	case censusFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	case censusFormatCSV:
+		return writeCensusCSV(w, c.Nodes)
+	default:
+		return errors.Errorf("unknown census format %q, expected %s or %s", format, censusFormatJSON, censusFormatCSV)
+	}
+}
+
+var censusCSVHeader = []string{
+	"node_id", "peer_id", "ip", "tcp_port", "fork_digest", "next_fork_version", "next_fork_epoch", "reachable", "error",
+	"agent", "head_slot", "finalized_epoch", "attnets", "syncnets", "latency_ms", "enr",
+}
+
+func writeCensusCSV(w io.Writer, nodes []*nodeRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(censusCSVHeader); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		row := []string{
+			n.NodeID,
+			n.PeerID,
+			n.IP,
+			strconv.Itoa(n.TCPPort),
+			n.ForkDigest,
+			n.NextForkVersion,
+			strconv.FormatUint(uint64(n.NextForkEpoch), 10),
+			strconv.FormatBool(n.Reachable),
+			n.Error,
+			n.Agent,
+			strconv.FormatUint(uint64(n.HeadSlot), 10),
+			strconv.FormatUint(uint64(n.FinalizedEpoch), 10),
+			n.Attnets,
+			n.Syncnets,
+			strconv.FormatInt(n.LatencyMillis, 10),
+			n.ENR,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// logSummary logs the distributions of a census summary, most common values first.
+func logSummary(s *censusSummary) {
+	log.WithFields(logrus.Fields{
+		"nodes":     s.Nodes,
+		"reachable": s.Reachable,
+	}).Info("Crawl complete")
+	logDistribution("Fork digest", s.ForkDigests)
+	logDistribution("Next fork", s.NextForks)
+	logDistribution("Client", s.Clients)
+}
+
+func logDistribution(name string, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	total := 0
+	for k, c := range counts {
+		keys = append(keys, k)
+		total += c
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		log.WithFields(logrus.Fields{
+			"count":   counts[k],
+			"percent": fmt.Sprintf("%.1f", 100*float64(counts[k])/float64(total)),
+		}).Infof("%s: %s", name, k)
+	}
+}
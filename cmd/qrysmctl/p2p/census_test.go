@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/go-zond/crypto"
+	"github.com/theQRL/go-zond/p2p/enode"
+	"github.com/theQRL/go-zond/p2p/enr"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	pb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestRecordFromENR(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	forkID := &pb.ENRForkID{
+		CurrentForkDigest: []byte{0x01, 0x02, 0x03, 0x04},
+		NextForkVersion:   []byte{0x04, 0x00, 0x00, 0x00},
+		NextForkEpoch:     100,
+	}
+	enc, err := forkID.MarshalSSZ()
+	require.NoError(t, err)
+	var r enr.Record
+	r.Set(enr.WithEntry(params.BeaconNetworkConfig().ETH2Key, enc))
+	r.Set(enr.TCP(13000))
+	require.NoError(t, enode.SignV4(&r, key))
+	n, err := enode.New(enode.ValidSchemes, &r)
+	require.NoError(t, err)
+
+	rec := recordFromENR(n)
+	assert.Equal(t, n.ID().String(), rec.NodeID)
+	assert.Equal(t, 13000, rec.TCPPort)
+	assert.Equal(t, "0x01020304", rec.ForkDigest)
+	assert.Equal(t, "0x04000000", rec.NextForkVersion)
+	assert.Equal(t, forkID.NextForkEpoch, rec.NextForkEpoch)
+}
+
+func TestSummarize(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig()
+	gvr := bytes.Repeat([]byte{0x01}, 32)
+	digest, err := signing.ComputeForkDigest(cfg.CapellaForkVersion, gvr)
+	require.NoError(t, err)
+	capella := hexutil.Encode(digest[:])
+	deneb := hexutil.Encode(cfg.DenebForkVersion)
+
+	nodes := []*nodeRecord{
+		{ForkDigest: capella, NextForkVersion: deneb, NextForkEpoch: 10, Reachable: true, Agent: "Qrysm/v1.0.0/abc"},
+		{ForkDigest: capella, NextForkVersion: deneb, NextForkEpoch: 10, Reachable: true, Agent: "qrysm/v1.0.1/def"},
+		{ForkDigest: capella, NextForkVersion: hexutil.Encode(cfg.CapellaForkVersion), NextForkEpoch: cfg.FarFutureEpoch},
+		{ForkDigest: "0xdeadbeef", Reachable: true},
+		{Error: "no fork digest in enr"},
+	}
+	s := summarize(nodes, gvr)
+	assert.Equal(t, 5, s.Nodes)
+	assert.Equal(t, 3, s.Reachable)
+	assert.DeepEqual(t, map[string]int{
+		capella + " (capella)":      3,
+		"0xdeadbeef (unknown fork)": 1,
+	}, s.ForkDigests)
+	assert.DeepEqual(t, map[string]int{
+		fmt.Sprintf("%s (deneb) at epoch 10", deneb): 2,
+		"none scheduled": 2,
+	}, s.NextForks)
+	assert.DeepEqual(t, map[string]int{"qrysm": 2, "unknown": 1}, s.Clients)
+
+	// without the genesis validators root, fork digests can not be named
+	s = summarize(nodes, nil)
+	assert.Equal(t, 3, s.ForkDigests[capella])
+}
+
+func TestWriteCensus(t *testing.T) {
+	nodes := []*nodeRecord{
+		{NodeID: "a", ForkDigest: "0x01020304", Reachable: true, HeadSlot: 64, Attnets: "0xff"},
+		{NodeID: "b", Error: "could not connect, timeout"},
+	}
+	c := &census{Summary: summarize(nodes, nil), Nodes: nodes}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCensus(&buf, c, censusFormatJSON))
+	decoded := &census{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	require.DeepEqual(t, c.Nodes, decoded.Nodes)
+	require.DeepEqual(t, c.Summary, decoded.Summary)
+
+	buf.Reset()
+	require.NoError(t, writeCensus(&buf, c, censusFormatCSV))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(rows))
+	require.DeepEqual(t, censusCSVHeader, rows[0])
+	assert.Equal(t, "a", rows[1][0])
+	assert.Equal(t, "64", rows[1][10])
+	assert.Equal(t, "could not connect, timeout", rows[2][8])
+
+	require.ErrorContains(t, "unknown census format", writeCensus(&buf, c, "xml"))
+}
@@ -44,6 +44,26 @@ type client struct {
 }
 
 func newClient(beaconEndpoints []string, clientPort uint) (*client, error) {
+	if len(beaconEndpoints) == 0 {
+		return nil, errors.New("no specified beacon API endpoints")
+	}
+	c, err := newHostClient(clientPort)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(beaconEndpoints[0], grpc.WithInsecure())
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.beaconClient = pb.NewBeaconChainClient(conn)
+	c.nodeClient = pb.NewNodeClient(conn)
+	return c, nil
+}
+
+// newHostClient creates a client with a libp2p host but without a connection to a beacon node API, which is enough
+// for requests that do not need the view of the chain of a beacon node.
+func newHostClient(clientPort uint) (*client, error) {
 	ipAdd := ipAddr()
 	priv, err := privKey()
 	if err != nil {
@@ -69,20 +89,9 @@ func newClient(beaconEndpoints []string, clientPort uint) (*client, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not start libp2p")
 	}
-	if len(beaconEndpoints) == 0 {
-		return nil, errors.New("no specified beacon API endpoints")
-	}
-	conn, err := grpc.Dial(beaconEndpoints[0], grpc.WithInsecure())
-	if err != nil {
-		return nil, err
-	}
-	beaconClient := pb.NewBeaconChainClient(conn)
-	nodeClient := pb.NewNodeClient(conn)
 	return &client{
-		host:         h,
-		meta:         meta,
-		beaconClient: beaconClient,
-		nodeClient:   nodeClient,
+		host: h,
+		meta: meta,
 	}, nil
 }
 
@@ -223,6 +232,7 @@ func readMetadata() (metadata.Metadata, error) {
 	metaData := &pb.MetaDataV1{
 		SeqNumber: 0,
 		Attnets:   bitfield.NewBitvector64(),
+		Syncnets:  bitfield.NewBitvector4(),
 	}
 	return wrapper.WrappedMetadataV1(metaData), nil
 }
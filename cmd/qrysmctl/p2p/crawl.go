@@ -0,0 +1,412 @@
+package p2p
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/go-zond/p2p/discover"
+	"github.com/theQRL/go-zond/p2p/enode"
+	"github.com/theQRL/go-zond/p2p/enr"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	p2ptypes "github.com/theQRL/qrysm/v4/beacon-chain/p2p/types"
+	prysmsync "github.com/theQRL/qrysm/v4/beacon-chain/sync"
+	"github.com/theQRL/qrysm/v4/cmd"
+	"github.com/theQRL/qrysm/v4/config/params"
+	pb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/urfave/cli/v2"
+)
+
+var crawlFlags = struct {
+	ClientPort            uint
+	UDPPort               uint
+	Duration              time.Duration
+	DialTimeout           time.Duration
+	Concurrency           uint
+	Output                string
+	Format                string
+	GenesisValidatorsRoot string
+}{}
+
+var crawlCmd = &cli.Command{
+	Name: "crawl",
+	Usage: "Discover the nodes of the network with discv5, perform a status and metadata handshake with every " +
+		"reachable node and write a census of the network",
+	Action: func(cliCtx *cli.Context) error {
+		if err := cliActionCrawl(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not crawl the network")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		cmd.ChainConfigFileFlag,
+		cmd.BootstrapNode,
+		&cli.UintFlag{
+			Name:        "client-port",
+			Usage:       "port to use for the client as a libp2p host",
+			Destination: &crawlFlags.ClientPort,
+			Value:       13001,
+		},
+		&cli.UintFlag{
+			Name:        "udp-port",
+			Usage:       "port to use for discv5",
+			Destination: &crawlFlags.UDPPort,
+			Value:       12001,
+		},
+		&cli.DurationFlag{
+			Name:        "duration",
+			Usage:       "how long to look for new nodes",
+			Destination: &crawlFlags.Duration,
+			Value:       5 * time.Minute,
+		},
+		&cli.DurationFlag{
+			Name:        "dial-timeout",
+			Usage:       "timeout of the connection and handshake with a single node",
+			Destination: &crawlFlags.DialTimeout,
+			Value:       10 * time.Second,
+		},
+		&cli.UintFlag{
+			Name:        "concurrency",
+			Usage:       "number of nodes to handshake with at the same time",
+			Destination: &crawlFlags.Concurrency,
+			Value:       16,
+		},
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "path of the census file, the census is written to stdout if unset",
+			Destination: &crawlFlags.Output,
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "format of the census, json or csv",
+			Destination: &crawlFlags.Format,
+			Value:       censusFormatJSON,
+		},
+		&cli.StringFlag{
+			Name:        "genesis-validators-root",
+			Usage:       "hex encoded genesis validators root of the network, used to name the forks of fork digests",
+			Destination: &crawlFlags.GenesisValidatorsRoot,
+		},
+	},
+}
+
+func cliActionCrawl(cliCtx *cli.Context) error {
+	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		chainConfigFileName := cliCtx.String(cmd.ChainConfigFileFlag.Name)
+		if err := params.LoadChainConfigFile(chainConfigFileName, nil); err != nil {
+			return err
+		}
+	}
+	p2ptypes.InitializeDataMaps()
+
+	if crawlFlags.Format != censusFormatJSON && crawlFlags.Format != censusFormatCSV {
+		return errors.Errorf("unknown census format %q, expected %s or %s", crawlFlags.Format, censusFormatJSON, censusFormatCSV)
+	}
+	if crawlFlags.Concurrency == 0 {
+		return errors.New("concurrency must be greater than zero")
+	}
+	var gvr []byte
+	if crawlFlags.GenesisValidatorsRoot != "" {
+		var err error
+		gvr, err = hexutil.Decode(crawlFlags.GenesisValidatorsRoot)
+		if err != nil || len(gvr) != 32 {
+			return errors.Errorf("invalid genesis validators root %s", crawlFlags.GenesisValidatorsRoot)
+		}
+	}
+	var bootnodes []*enode.Node
+	for _, addr := range cliCtx.StringSlice(cmd.BootstrapNode.Name) {
+		n, err := enode.Parse(enode.ValidSchemes, addr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid bootstrap node %s", addr)
+		}
+		bootnodes = append(bootnodes, n)
+	}
+	if len(bootnodes) == 0 {
+		return errors.New("no bootstrap nodes to start the crawl from")
+	}
+
+	c, err := newHostClient(crawlFlags.ClientPort)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	c.registerCrawlHandlers()
+
+	listener, err := startCrawlListener(crawlFlags.UDPPort, bootnodes)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), crawlFlags.Duration)
+	defer cancel()
+	cr := &crawler{c: c, dialTimeout: crawlFlags.DialTimeout}
+	log.WithFields(logrus.Fields{
+		"bootnodes": len(bootnodes),
+		"duration":  crawlFlags.Duration,
+	}).Info("Crawling the network")
+	nodes := cr.crawl(ctx, listener.RandomNodes(), int(crawlFlags.Concurrency))
+
+	cs := &census{
+		Time:    time.Now().UTC(),
+		Summary: summarize(nodes, gvr),
+		Nodes:   nodes,
+	}
+	logSummary(cs.Summary)
+	var out io.Writer = os.Stdout
+	if crawlFlags.Output != "" {
+		f, err := os.Create(crawlFlags.Output)
+		if err != nil {
+			return errors.Wrap(err, "could not create census file")
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.WithError(err).Error("Could not close census file")
+			}
+		}()
+		out = f
+	}
+	return writeCensus(out, cs, crawlFlags.Format)
+}
+
+func startCrawlListener(udpPort uint, bootnodes []*enode.Node) (*discover.UDPv5, error) {
+	priv, err := privKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not set up discovery private key")
+	}
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4zero, Port: int(udpPort)})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen to UDP")
+	}
+	db, err := enode.OpenDB("")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open node's peer database")
+	}
+	localNode := enode.NewLocalNode(db, priv)
+	localNode.Set(enr.IP(ipAddr()))
+	localNode.Set(enr.UDP(udpPort))
+	listener, err := discover.ListenV5(conn, localNode, discover.Config{
+		PrivateKey: priv,
+		Bootnodes:  bootnodes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen to discV5")
+	}
+	return listener, nil
+}
+
+// crawler performs a status and metadata handshake with the nodes found by discovery.
+type crawler struct {
+	c           *client
+	dialTimeout time.Duration
+}
+
+// crawl visits every node of the iterator once, until the context is done, with up to concurrency handshakes at the
+// same time.
+func (cr *crawler) crawl(ctx context.Context, it enode.Iterator, concurrency int) []*nodeRecord {
+	go func() {
+		<-ctx.Done()
+		it.Close()
+	}()
+
+	var (
+		mu      sync.Mutex
+		records []*nodeRecord
+		wg      sync.WaitGroup
+	)
+	queue := make(chan *enode.Node)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				// nodes queued before the end of the crawl are still visited, with a context of their own
+				rec := cr.visit(context.Background(), n)
+				mu.Lock()
+				records = append(records, rec)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[enode.ID]bool)
+	for it.Next() {
+		n := it.Node()
+		if seen[n.ID()] {
+			continue
+		}
+		seen[n.ID()] = true
+		select {
+		case queue <- n:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if len(seen)%100 == 0 {
+			log.WithField("nodes", len(seen)).Info("Crawl progress")
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return records
+}
+
+// visit records the ENR of a node and, when the node advertises a libp2p port and a fork digest, connects to it and
+// performs the handshake.
+func (cr *crawler) visit(ctx context.Context, n *enode.Node) *nodeRecord {
+	rec := recordFromENR(n)
+	if n.TCP() == 0 {
+		rec.Error = "no tcp port in enr"
+		return rec
+	}
+	if rec.ForkDigest == "" {
+		rec.Error = "no fork digest in enr"
+		return rec
+	}
+	addrs, err := p2p.PeersFromStringAddrs([]string{n.String()})
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	info, err := peer.AddrInfoFromP2pAddr(addrs[0])
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	rec.PeerID = info.ID.String()
+
+	ctx, cancel := context.WithTimeout(ctx, cr.dialTimeout)
+	defer cancel()
+	if err := cr.c.host.Connect(ctx, *info); err != nil {
+		rec.Error = errors.Wrap(err, "could not connect").Error()
+		return rec
+	}
+	defer func() {
+		if err := cr.c.host.Network().ClosePeer(info.ID); err != nil {
+			log.WithError(err).Debug("Could not disconnect from peer")
+		}
+	}()
+	rec.Reachable = true
+	if agent, err := cr.c.host.Peerstore().Get(info.ID, "AgentVersion"); err == nil {
+		if s, ok := agent.(string); ok {
+			rec.Agent = s
+		}
+	}
+
+	digest, err := hexutil.Decode(rec.ForkDigest)
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	start := time.Now()
+	status, err := cr.c.requestStatus(ctx, info.ID, digest)
+	if err != nil {
+		rec.Error = errors.Wrap(err, "status request failed").Error()
+		return rec
+	}
+	rec.LatencyMillis = time.Since(start).Milliseconds()
+	rec.ForkDigest = hexutil.Encode(status.ForkDigest)
+	rec.HeadSlot = status.HeadSlot
+	rec.FinalizedEpoch = status.FinalizedEpoch
+
+	md, err := cr.c.requestMetadata(ctx, info.ID)
+	if err != nil {
+		rec.Error = errors.Wrap(err, "metadata request failed").Error()
+		return rec
+	}
+	rec.Attnets = hexutil.Encode(md.Attnets)
+	rec.Syncnets = hexutil.Encode(md.Syncnets)
+	return rec
+}
+
+// recordFromENR fills a record with the fields of the ENR of a node.
+func recordFromENR(n *enode.Node) *nodeRecord {
+	rec := &nodeRecord{
+		NodeID:  n.ID().String(),
+		ENR:     n.String(),
+		TCPPort: n.TCP(),
+	}
+	if n.IP() != nil {
+		rec.IP = n.IP().String()
+	}
+	if forkID, err := enrForkID(n.Record()); err == nil {
+		rec.ForkDigest = hexutil.Encode(forkID.CurrentForkDigest)
+		rec.NextForkVersion = hexutil.Encode(forkID.NextForkVersion)
+		rec.NextForkEpoch = forkID.NextForkEpoch
+	}
+	return rec
+}
+
+// enrForkID reads the fork id from the eth2 entry of an ENR.
+func enrForkID(record *enr.Record) (*pb.ENRForkID, error) {
+	enc := make([]byte, 16)
+	if err := record.Load(enr.WithEntry(params.BeaconNetworkConfig().ETH2Key, &enc)); err != nil {
+		return nil, err
+	}
+	forkID := &pb.ENRForkID{}
+	if err := forkID.UnmarshalSSZ(enc); err != nil {
+		return nil, err
+	}
+	return forkID, nil
+}
+
+// genesisStatus is a status at genesis with the given fork digest, which every peer of the same fork accepts.
+func genesisStatus(digest []byte) *pb.Status {
+	zero := params.BeaconConfig().ZeroHash
+	return &pb.Status{
+		ForkDigest:    digest,
+		FinalizedRoot: zero[:],
+		HeadRoot:      zero[:],
+	}
+}
+
+// requestStatus sends a status at genesis with the given fork digest to a peer and returns the status of the peer.
+func (c *client) requestStatus(ctx context.Context, pid peer.ID, digest []byte) (*pb.Status, error) {
+	stream, err := c.Send(ctx, genesisStatus(digest), p2p.RPCStatusTopicV1, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStream(stream)
+	code, errMsg, err := prysmsync.ReadStatusCode(stream, c.Encoding())
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, errors.New(errMsg)
+	}
+	resp := &pb.Status{}
+	if err := c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// requestMetadata requests the metadata of a peer, which holds the attestation and sync committee subnets it is
+// subscribed to.
+func (c *client) requestMetadata(ctx context.Context, pid peer.ID) (*pb.MetaDataV1, error) {
+	stream, err := c.Send(ctx, new(interface{}), p2p.RPCMetaDataTopicV2, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStream(stream)
+	code, errMsg, err := prysmsync.ReadStatusCode(stream, c.Encoding())
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, errors.New(errMsg)
+	}
+	md := &pb.MetaDataV1{}
+	if err := c.Encoding().DecodeWithMaxLength(stream, md); err != nil {
+		return nil, errors.Wrap(err, "could not decode metadata")
+	}
+	return md, nil
+}
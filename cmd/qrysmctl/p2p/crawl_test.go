@@ -0,0 +1,188 @@
+package p2p
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	libp2pcore "github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/security/noise"
+	"github.com/libp2p/go-libp2p/p2p/transport/tcp"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/go-zond/crypto"
+	"github.com/theQRL/go-zond/p2p/enode"
+	"github.com/theQRL/go-zond/p2p/enr"
+	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
+	prysmsync "github.com/theQRL/qrysm/v4/beacon-chain/sync"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/wrapper"
+	pb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+var testForkDigest = []byte{0x01, 0x02, 0x03, 0x04}
+
+// newTestHostClient creates a client listening on the loopback interface with the given key, which answers the
+// requests of the crawl.
+func newTestHostClient(t *testing.T, priv *ecdsa.PrivateKey) *client {
+	h, err := libp2p.New(
+		privKeyOption(priv),
+		libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		libp2p.UserAgent("Qrysm/test"),
+		libp2p.Transport(tcp.NewTCPTransport),
+		libp2p.Security(noise.ID, noise.New),
+		libp2p.Ping(false),
+	)
+	require.NoError(t, err)
+	meta, err := readMetadata()
+	require.NoError(t, err)
+	c := &client{host: h, meta: meta}
+	t.Cleanup(c.Close)
+	c.registerCrawlHandlers()
+	return c
+}
+
+// testNode signs the ENR of a node at the given tcp port, with the fork digest when it is set.
+func testNode(t *testing.T, priv *ecdsa.PrivateKey, tcpPort int, digest []byte) *enode.Node {
+	var r enr.Record
+	r.Set(enr.IP(net.IPv4(127, 0, 0, 1)))
+	if tcpPort != 0 {
+		r.Set(enr.TCP(tcpPort))
+	}
+	if digest != nil {
+		enc, err := (&pb.ENRForkID{
+			CurrentForkDigest: digest,
+			NextForkVersion:   params.BeaconConfig().GenesisForkVersion,
+			NextForkEpoch:     params.BeaconConfig().FarFutureEpoch,
+		}).MarshalSSZ()
+		require.NoError(t, err)
+		r.Set(enr.WithEntry(params.BeaconNetworkConfig().ETH2Key, enc))
+	}
+	require.NoError(t, enode.SignV4(&r, priv))
+	n, err := enode.New(enode.ValidSchemes, &r)
+	require.NoError(t, err)
+	return n
+}
+
+func tcpPort(t *testing.T, c *client) int {
+	port, err := c.host.Addrs()[0].ValueForProtocol(ma.P_TCP)
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return p
+}
+
+func TestCrawl(t *testing.T) {
+	nodeKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	node := newTestHostClient(t, nodeKey)
+	attnets := bitfield.NewBitvector64()
+	attnets.SetBitAt(3, true)
+	node.meta = wrapper.WrappedMetadataV1(&pb.MetaDataV1{SeqNumber: 1, Attnets: attnets, Syncnets: bitfield.Bitvector4{0x01}})
+	// The node answers with a status ahead of genesis, to tell it apart from the status sent by the crawler.
+	node.registerRPCHandler(p2p.RPCStatusTopicV1, func(_ context.Context, msg interface{}, stream libp2pcore.Stream) error {
+		defer closeStream(stream)
+		status := genesisStatus(msg.(*pb.Status).ForkDigest)
+		status.HeadSlot = 64
+		status.FinalizedEpoch = 1
+		if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+			return err
+		}
+		_, err := node.Encoding().EncodeWithMaxLength(stream, status)
+		return err
+	})
+
+	crawlerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cr := &crawler{c: newTestHostClient(t, crawlerKey), dialTimeout: 5 * time.Second}
+
+	noTCPKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	unreachableKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	reachable := testNode(t, nodeKey, tcpPort(t, node), testForkDigest)
+	noTCP := testNode(t, noTCPKey, 0, testForkDigest)
+	unreachable := testNode(t, unreachableKey, 1, testForkDigest)
+	nodes := []*enode.Node{reachable, noTCP, unreachable, reachable}
+
+	records := cr.crawl(context.Background(), enode.IterNodes(nodes), 2)
+	// Every node is visited once.
+	require.Equal(t, 3, len(records))
+	byID := make(map[string]*nodeRecord)
+	for _, rec := range records {
+		byID[rec.NodeID] = rec
+	}
+
+	rec, ok := byID[reachable.ID().String()]
+	require.Equal(t, true, ok)
+	assert.Equal(t, "", rec.Error)
+	assert.Equal(t, true, rec.Reachable)
+	assert.Equal(t, node.host.ID().String(), rec.PeerID)
+	assert.Equal(t, "Qrysm/test", rec.Agent)
+	assert.Equal(t, hexutil.Encode(testForkDigest), rec.ForkDigest)
+	assert.Equal(t, uint64(64), uint64(rec.HeadSlot))
+	assert.Equal(t, uint64(1), uint64(rec.FinalizedEpoch))
+	assert.Equal(t, hexutil.Encode(attnets), rec.Attnets)
+	assert.Equal(t, "0x01", rec.Syncnets)
+
+	rec, ok = byID[noTCP.ID().String()]
+	require.Equal(t, true, ok)
+	assert.Equal(t, "no tcp port in enr", rec.Error)
+	assert.Equal(t, false, rec.Reachable)
+
+	rec, ok = byID[unreachable.ID().String()]
+	require.Equal(t, true, ok)
+	assert.StringContains(t, "could not connect", rec.Error)
+	assert.Equal(t, false, rec.Reachable)
+}
+
+func TestCrawl_NoForkDigest(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	crawlerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cr := &crawler{c: newTestHostClient(t, crawlerKey), dialTimeout: time.Second}
+
+	rec := cr.visit(context.Background(), testNode(t, key, 13000, nil))
+	assert.Equal(t, "no fork digest in enr", rec.Error)
+	assert.Equal(t, false, rec.Reachable)
+}
+
+func TestCrawlHandlers(t *testing.T) {
+	crawlerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	crawler := newTestHostClient(t, crawlerKey)
+	nodeKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	node := newTestHostClient(t, nodeKey)
+	ctx := context.Background()
+	require.NoError(t, node.host.Connect(ctx, peer.AddrInfo{ID: crawler.host.ID(), Addrs: crawler.host.Addrs()}))
+
+	// The crawler answers the status of the node with a status at genesis of the same fork.
+	status, err := node.requestStatus(ctx, crawler.host.ID(), testForkDigest)
+	require.NoError(t, err)
+	assert.DeepEqual(t, genesisStatus(testForkDigest), status)
+
+	md, err := node.requestMetadata(ctx, crawler.host.ID())
+	require.NoError(t, err)
+	assert.DeepEqual(t, crawler.meta.AttnetsBitfield(), md.Attnets)
+	assert.DeepEqual(t, bitfield.NewBitvector4(), md.Syncnets)
+
+	stream, err := node.Send(ctx, new(interface{}), p2p.RPCMetaDataTopicV1, crawler.host.ID())
+	require.NoError(t, err)
+	defer closeStream(stream)
+	code, errMsg, err := prysmsync.ReadStatusCode(stream, node.Encoding())
+	require.NoError(t, err)
+	require.Equal(t, responseCodeSuccess, code, errMsg)
+	mdV0 := &pb.MetaDataV0{}
+	require.NoError(t, node.Encoding().DecodeWithMaxLength(stream, mdV0))
+	assert.Equal(t, crawler.meta.SequenceNumber(), mdV0.SeqNumber)
+}
@@ -2,11 +2,13 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 
 	libp2pcore "github.com/libp2p/go-libp2p/core"
 	"github.com/sirupsen/logrus"
 	"github.com/theQRL/qrysm/v4/beacon-chain/p2p"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/consensus-types/wrapper"
 	"github.com/theQRL/qrysm/v4/network/forks"
 	pb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/time/slots"
@@ -34,6 +36,17 @@ func (c *client) pingHandler(_ context.Context, _ interface{}, stream libp2pcore
 	return nil
 }
 
+// registerCrawlHandlers registers the handlers of the requests a node sends when the crawler connects to it. The
+// crawler has no view of the chain, so it answers the status of a node with a status at the genesis of the fork of
+// the node.
+func (c *client) registerCrawlHandlers() {
+	c.registerRPCHandler(p2p.RPCPingTopicV1, c.pingHandler)
+	c.registerRPCHandler(p2p.RPCStatusTopicV1, c.genesisStatusHandler)
+	c.registerRPCHandler(p2p.RPCGoodByeTopicV1, c.goodbyeHandler)
+	c.registerRPCHandler(p2p.RPCMetaDataTopicV1, c.metadataHandler)
+	c.registerRPCHandler(p2p.RPCMetaDataTopicV2, c.metadataHandler)
+}
+
 func (c *client) goodbyeHandler(_ context.Context, _ interface{}, _ libp2pcore.Stream) error {
 	return nil
 }
@@ -79,3 +92,42 @@ func (c *client) statusRPCHandler(ctx context.Context, _ interface{}, stream lib
 	_, err = c.Encoding().EncodeWithMaxLength(stream, status)
 	return err
 }
+
+// genesisStatusHandler responds to the incoming Status RPC of the peer with a status at genesis, with the fork digest
+// of the peer.
+func (c *client) genesisStatusHandler(_ context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	defer closeStream(stream)
+	m, ok := msg.(*pb.Status)
+	if !ok {
+		return fmt.Errorf("wrong message type for status, got %T, wanted *pb.Status", msg)
+	}
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		log.WithError(err).Debug("Could not write to stream")
+		return err
+	}
+	_, err := c.Encoding().EncodeWithMaxLength(stream, genesisStatus(m.ForkDigest))
+	return err
+}
+
+// metadataHandler responds to the incoming MetaData RPC of the peer with the metadata of the client, in the version of
+// the topic of the request.
+func (c *client) metadataHandler(_ context.Context, _ interface{}, stream libp2pcore.Stream) error {
+	defer closeStream(stream)
+	_, _, streamVersion, err := p2p.TopicDeconstructor(string(stream.Protocol()))
+	if err != nil {
+		return err
+	}
+	md := c.meta
+	if streamVersion == p2p.SchemaVersionV1 {
+		md = wrapper.WrappedMetadataV0(&pb.MetaDataV0{
+			SeqNumber: c.meta.SequenceNumber(),
+			Attnets:   c.meta.AttnetsBitfield(),
+		})
+	}
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		log.WithError(err).Debug("Could not write to stream")
+		return err
+	}
+	_, err = c.Encoding().EncodeWithMaxLength(stream, md)
+	return err
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/runtime/interop"
 	"github.com/theQRL/qrysm/v4/testing/assert"
 	"github.com/theQRL/qrysm/v4/testing/require"
@@ -24,10 +24,10 @@ func Test_genesisStateFromJSONValidators(t *testing.T) {
 }
 
 func createGenesisDepositData(t *testing.T, numKeys int) []*depositDataJSON {
-	pubKeys := make([]bls.PublicKey, numKeys)
-	privKeys := make([]bls.SecretKey, numKeys)
+	pubKeys := make([]dilithium.PublicKey, numKeys)
+	privKeys := make([]dilithium.DilithiumKey, numKeys)
 	for i := 0; i < numKeys; i++ {
-		randKey, err := bls.RandKey()
+		randKey, err := dilithium.RandKey()
 		require.NoError(t, err)
 		privKeys[i] = randKey
 		pubKeys[i] = randKey.PublicKey()
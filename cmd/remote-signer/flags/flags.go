@@ -0,0 +1,79 @@
+// Package flags contains all configuration runtime flags for
+// the remote signer.
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// HostFlag defines the host the remote signer listens on.
+	HostFlag = &cli.StringFlag{
+		Name:  "host",
+		Usage: "Host on which the remote signer listens for sign requests.",
+		Value: "127.0.0.1",
+	}
+	// PortFlag defines the port the remote signer listens on.
+	PortFlag = &cli.IntFlag{
+		Name:  "port",
+		Usage: "Port on which the remote signer listens for sign requests.",
+		Value: 9000,
+	}
+	// KeystoresDirFlag defines the directory of the keystores holding the signing keys.
+	KeystoresDirFlag = &cli.StringFlag{
+		Name:     "keystores-dir",
+		Usage:    "Directory of the keystores holding the signing keys. Version 1 and version 2 keystores are supported.",
+		Required: true,
+	}
+	// KeystoresPasswordFileFlag defines the file with the password of the keystores.
+	KeystoresPasswordFileFlag = &cli.StringFlag{
+		Name:     "keystores-password-file",
+		Usage:    "File containing the password which decrypts all the keystores.",
+		Required: true,
+	}
+	// SlashingProtectionDirFlag defines the directory of the slashing protection database.
+	SlashingProtectionDirFlag = &cli.StringFlag{
+		Name:     "slashing-protection-dir",
+		Usage:    "Directory of the slashing protection database. It has the format of the validator client database.",
+		Required: true,
+	}
+	// GenesisValidatorsRootFlag pins the network the remote signer signs for.
+	GenesisValidatorsRootFlag = &cli.StringFlag{
+		Name: "genesis-validators-root",
+		Usage: "Hex encoded genesis validators root of the network the remote signer signs for. " +
+			"Requests for other networks are rejected.",
+	}
+	// AllowUnverifiedSigningRootsFlag accepts client provided signing roots.
+	AllowUnverifiedSigningRootsFlag = &cli.BoolFlag{
+		Name: "allow-unverified-signing-roots",
+		Usage: "Sign the signing root sent by the client for the requests whose signing root cannot be computed " +
+			"from the request, which are the full blocks. Slashing protection of blocks is still enforced " +
+			"by slot, but the client is trusted to send the signing root matching the block.",
+	}
+	// BearerTokenFileFlag defines the file with the token clients have to authenticate with.
+	BearerTokenFileFlag = &cli.StringFlag{
+		Name:  "bearer-token-file",
+		Usage: "File containing a token which clients have to send as a bearer token in the Authorization header.",
+	}
+	// TLSCertFlag defines the TLS certificate of the remote signer.
+	TLSCertFlag = &cli.StringFlag{
+		Name:  "tls-cert",
+		Usage: "Certificate for secure HTTPS connections. Pass this and the tls-key flag in order to use HTTPS.",
+	}
+	// TLSKeyFlag defines the TLS key of the remote signer.
+	TLSKeyFlag = &cli.StringFlag{
+		Name:  "tls-key",
+		Usage: "Key for secure HTTPS connections. Pass this and the tls-cert flag in order to use HTTPS.",
+	}
+	// TLSClientCAFlag enables mutual TLS.
+	TLSClientCAFlag = &cli.StringFlag{
+		Name:  "tls-client-ca",
+		Usage: "CA certificate which client certificates have to be signed with. Enables mutual TLS authentication.",
+	}
+	// MonitoringPortFlag defines the http port used to serve prometheus metrics.
+	MonitoringPortFlag = &cli.IntFlag{
+		Name:  "monitoring-port",
+		Usage: "Port used to listening and respond metrics for prometheus.",
+		Value: 8082,
+	}
+)
@@ -0,0 +1,206 @@
+// Package main is the entrypoint of the remote signer, which signs the requests of
+// validator clients using the remote-web3signer keymanager with local keystores.
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	runtimeDebug "runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
+
+	joonix "github.com/joonix/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/cmd"
+	"github.com/theQRL/qrysm/v4/cmd/remote-signer/flags"
+	"github.com/theQRL/qrysm/v4/io/file"
+	"github.com/theQRL/qrysm/v4/io/logs"
+	"github.com/theQRL/qrysm/v4/monitoring/journald"
+	"github.com/theQRL/qrysm/v4/monitoring/prometheus"
+	"github.com/theQRL/qrysm/v4/runtime"
+	prefixed "github.com/theQRL/qrysm/v4/runtime/logging/logrus-prefixed-formatter"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/validator/db/kv"
+	remotesigner "github.com/theQRL/qrysm/v4/validator/remote-signer"
+	"github.com/urfave/cli/v2"
+)
+
+var appFlags = []cli.Flag{
+	cmd.VerbosityFlag,
+	cmd.LogFormat,
+	cmd.LogFileName,
+	cmd.ConfigFileFlag,
+	cmd.MonitoringHostFlag,
+	cmd.DisableMonitoringFlag,
+	flags.MonitoringPortFlag,
+	flags.HostFlag,
+	flags.PortFlag,
+	flags.KeystoresDirFlag,
+	flags.KeystoresPasswordFileFlag,
+	flags.SlashingProtectionDirFlag,
+	flags.GenesisValidatorsRootFlag,
+	flags.AllowUnverifiedSigningRootsFlag,
+	flags.BearerTokenFileFlag,
+	flags.TLSCertFlag,
+	flags.TLSKeyFlag,
+	flags.TLSClientCAFlag,
+}
+
+func init() {
+	appFlags = cmd.WrapFlags(appFlags)
+}
+
+func main() {
+	app := cli.App{}
+	app.Name = "remote-signer"
+	app.Usage = "signs the requests of validator clients using the remote-web3signer keymanager with local keystores"
+	app.Action = run
+	app.Version = version.Version()
+
+	app.Flags = appFlags
+
+	app.Before = func(ctx *cli.Context) error {
+		// Load flags from config file, if specified.
+		if err := cmd.LoadFlagsFromConfig(ctx, app.Flags); err != nil {
+			return err
+		}
+
+		verbosity := ctx.String(cmd.VerbosityFlag.Name)
+		level, err := logrus.ParseLevel(verbosity)
+		if err != nil {
+			return err
+		}
+		logrus.SetLevel(level)
+
+		format := ctx.String(cmd.LogFormat.Name)
+		switch format {
+		case "text":
+			formatter := new(prefixed.TextFormatter)
+			formatter.TimestampFormat = "2006-01-02 15:04:05"
+			formatter.FullTimestamp = true
+			// If persistent log files are written - we disable the log messages coloring because
+			// the colors are ANSI codes and seen as gibberish in the log files.
+			formatter.DisableColors = ctx.String(cmd.LogFileName.Name) != ""
+			logrus.SetFormatter(formatter)
+		case "fluentd":
+			f := joonix.NewFormatter()
+			if err := joonix.DisableTimestampFormat(f); err != nil {
+				panic(err)
+			}
+			logrus.SetFormatter(f)
+		case "json":
+			logrus.SetFormatter(&logrus.JSONFormatter{})
+		case "journald":
+			if err := journald.Enable(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown log format %s", format)
+		}
+
+		logFileName := ctx.String(cmd.LogFileName.Name)
+		if logFileName != "" {
+			if err := logs.ConfigurePersistentLogging(logFileName); err != nil {
+				log.WithError(err).Error("Failed to configuring logging to disk.")
+			}
+		}
+		return cmd.ValidateNoArgs(ctx)
+	}
+
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("Runtime panic: %v\n%v", x, string(runtimeDebug.Stack()))
+			panic(x)
+		}
+	}()
+
+	if err := app.Run(os.Args); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func run(cliCtx *cli.Context) error {
+	password, err := readSecretFile(cliCtx.String(flags.KeystoresPasswordFileFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "could not read keystores password")
+	}
+	var bearerToken string
+	if cliCtx.IsSet(flags.BearerTokenFileFlag.Name) {
+		bearerToken, err = readSecretFile(cliCtx.String(flags.BearerTokenFileFlag.Name))
+		if err != nil {
+			return errors.Wrap(err, "could not read bearer token")
+		}
+		if bearerToken == "" {
+			return errors.New("bearer token file is empty")
+		}
+	}
+	var genesisValidatorsRoot []byte
+	if cliCtx.IsSet(flags.GenesisValidatorsRootFlag.Name) {
+		genesisValidatorsRoot, err = hexutil.Decode(cliCtx.String(flags.GenesisValidatorsRootFlag.Name))
+		if err != nil || len(genesisValidatorsRoot) != 32 {
+			return errors.New("genesis validators root must be 32 hex encoded bytes")
+		}
+	}
+
+	db, err := kv.NewKVStore(cliCtx.Context, cliCtx.String(flags.SlashingProtectionDirFlag.Name), &kv.Config{})
+	if err != nil {
+		return errors.Wrap(err, "could not open slashing protection database")
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.WithError(err).Error("Could not close slashing protection database")
+		}
+	}()
+
+	signer, err := remotesigner.New(cliCtx.Context, &remotesigner.Config{
+		Addr:                        net.JoinHostPort(cliCtx.String(flags.HostFlag.Name), strconv.Itoa(cliCtx.Int(flags.PortFlag.Name))),
+		KeystoresDir:                cliCtx.String(flags.KeystoresDirFlag.Name),
+		KeystoresPassword:           password,
+		DB:                          db,
+		GenesisValidatorsRoot:       genesisValidatorsRoot,
+		AllowUnverifiedSigningRoots: cliCtx.Bool(flags.AllowUnverifiedSigningRootsFlag.Name),
+		BearerToken:                 bearerToken,
+		TLSCertFile:                 cliCtx.String(flags.TLSCertFlag.Name),
+		TLSKeyFile:                  cliCtx.String(flags.TLSKeyFlag.Name),
+		TLSClientCAFile:             cliCtx.String(flags.TLSClientCAFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	services := runtime.NewServiceRegistry()
+	if err := services.RegisterService(signer); err != nil {
+		return err
+	}
+	if !cliCtx.Bool(cmd.DisableMonitoringFlag.Name) {
+		addr := net.JoinHostPort(
+			cliCtx.String(cmd.MonitoringHostFlag.Name),
+			strconv.Itoa(cliCtx.Int(flags.MonitoringPortFlag.Name)),
+		)
+		if err := services.RegisterService(prometheus.NewService(addr, services)); err != nil {
+			return err
+		}
+	}
+	services.StartAll()
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+	services.StopAll()
+	return nil
+}
+
+func readSecretFile(path string) (string, error) {
+	b, err := file.ReadFileAsBytes(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,188 @@
+package reencrypt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+var (
+	reencryptFlags = struct {
+		Keystores           string
+		Folder              string
+		KDF                 string
+		Argon2idTime        uint
+		Argon2idMemory      uint
+		Argon2idParallelism uint
+		ScryptN             int
+		PBKDF2Iterations    int
+	}{}
+	log = logrus.WithField("prefix", "reencrypt")
+)
+
+var Commands = []*cli.Command{
+	{
+		Name:  "reencrypt-keystores",
+		Usage: "Re-encrypts existing keystores with a new password and key derivation parameters",
+		Action: func(cliCtx *cli.Context) error {
+			if err := cliActionReencrypt(cliCtx); err != nil {
+				log.WithError(err).Fatal("Could not re-encrypt keystores")
+			}
+			return nil
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "keystores",
+				Usage:       "Path to a keystore file or to a directory containing keystore files",
+				Destination: &reencryptFlags.Keystores,
+				Required:    true,
+			},
+			&cli.StringFlag{
+				Name:        "folder",
+				Usage:       "Directory the re-encrypted keystores are written to",
+				Destination: &reencryptFlags.Folder,
+				Value:       "reencrypted_keys",
+			},
+			&cli.StringFlag{
+				Name:        "kdf",
+				Usage:       "Key derivation function of the re-encrypted keystores: argon2id, scrypt or pbkdf2",
+				Destination: &reencryptFlags.KDF,
+				Value:       keystore.KDFArgon2id,
+			},
+			&cli.UintFlag{
+				Name:        "argon2id-time",
+				Usage:       "Number of passes of argon2id",
+				Destination: &reencryptFlags.Argon2idTime,
+				Value:       keystore.DefaultArgon2idTime,
+			},
+			&cli.UintFlag{
+				Name:        "argon2id-memory",
+				Usage:       "Memory used by argon2id, in KiB",
+				Destination: &reencryptFlags.Argon2idMemory,
+				Value:       keystore.DefaultArgon2idMemory,
+			},
+			&cli.UintFlag{
+				Name:        "argon2id-parallelism",
+				Usage:       "Number of threads used by argon2id",
+				Destination: &reencryptFlags.Argon2idParallelism,
+				Value:       keystore.DefaultArgon2idParallelism,
+			},
+			&cli.IntFlag{
+				Name:        "scrypt-n",
+				Usage:       "CPU and memory cost parameter of scrypt",
+				Destination: &reencryptFlags.ScryptN,
+				Value:       keystore.DefaultScryptN,
+			},
+			&cli.IntFlag{
+				Name:        "pbkdf2-iterations",
+				Usage:       "Number of iterations of pbkdf2",
+				Destination: &reencryptFlags.PBKDF2Iterations,
+				Value:       keystore.DefaultPBKDF2Iterations,
+			},
+		},
+	},
+}
+
+func cliActionReencrypt(_ *cli.Context) error {
+	params, err := kdfParams()
+	if err != nil {
+		return err
+	}
+	keystorePaths, err := keystoreFiles(reencryptFlags.Keystores)
+	if err != nil {
+		return err
+	}
+	if len(keystorePaths) == 0 {
+		return fmt.Errorf("no keystores found in %s", reencryptFlags.Keystores)
+	}
+
+	fmt.Println("Enter the current password of the keystore(s)")
+	oldPassword, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Create a new password that secures your validator keystore(s)")
+	newPassword, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Re-enter password ")
+	reEnterNewPassword, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+
+	if string(newPassword) != string(reEnterNewPassword) {
+		return fmt.Errorf("password mismatch")
+	}
+
+	for _, keystorePath := range keystorePaths {
+		ks, err := keystore.ReadDilithiumKeystore(keystorePath)
+		if err != nil {
+			return fmt.Errorf("could not read keystore %s: %w", keystorePath, err)
+		}
+		reencrypted, err := keystore.ReencryptDilithiumKeystore(ks, string(oldPassword), string(newPassword), params)
+		if err != nil {
+			return fmt.Errorf("could not re-encrypt keystore %s: %w", keystorePath, err)
+		}
+		outputPath := filepath.Join(reencryptFlags.Folder, filepath.Base(keystorePath))
+		if err := reencrypted.Save(outputPath); err != nil {
+			return fmt.Errorf("could not save keystore %s: %w", outputPath, err)
+		}
+		log.WithField("pubkey", reencrypted.PubKey).Infof("Saved re-encrypted keystore to %s", outputPath)
+	}
+	return nil
+}
+
+func kdfParams() (*keystore.KDFParams, error) {
+	params, err := keystore.DefaultKDFParams(reencryptFlags.KDF)
+	if err != nil {
+		return nil, err
+	}
+	switch params.Function {
+	case keystore.KDFArgon2id:
+		if reencryptFlags.Argon2idParallelism > 255 {
+			return nil, fmt.Errorf("argon2id parallelism must be at most 255, got %d", reencryptFlags.Argon2idParallelism)
+		}
+		params.Argon2idTime = uint32(reencryptFlags.Argon2idTime)
+		params.Argon2idMemory = uint32(reencryptFlags.Argon2idMemory)
+		params.Argon2idParallelism = uint8(reencryptFlags.Argon2idParallelism)
+	case keystore.KDFScrypt:
+		params.ScryptN = reencryptFlags.ScryptN
+	case keystore.KDFPBKDF2:
+		params.PBKDF2Iterations = reencryptFlags.PBKDF2Iterations
+	}
+	return params, nil
+}
+
+// keystoreFiles returns the path itself if it is a file, or the JSON files it contains
+// if it is a directory.
+func keystoreFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	return files, nil
+}
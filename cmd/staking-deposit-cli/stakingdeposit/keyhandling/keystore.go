@@ -9,13 +9,13 @@ import (
 	"io"
 	"log"
 	"os"
-	"reflect"
 	"runtime"
 
 	"github.com/google/uuid"
 	"github.com/theQRL/go-qrllib/common"
 	"github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/cmd/staking-deposit-cli/misc"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -60,46 +60,31 @@ func (k *Keystore) Save(fileFolder string) error {
 	return nil
 }
 
+// Decrypt returns the seed stored in the keystore. Keystores written by this tool as well
+// as version 2 keystores, which derive the key with scrypt, pbkdf2 or argon2id, are supported.
 func (k *Keystore) Decrypt(password string) [common.SeedSize]byte {
-	salt, ok := k.Crypto.KDF.Params["salt"]
-	if !ok {
-		panic("salt not found in KDF Params")
+	if k.Version > keystore.DilithiumKeystoreVersion {
+		panic(fmt.Errorf("unsupported keystore version %d", k.Version))
 	}
-	binSalt := misc.DecodeHex(salt.(string))
-	decryptionKey, err := passwordToDecryptionKey(password, binSalt)
+	b, err := json.Marshal(k.Crypto)
 	if err != nil {
-		panic(fmt.Errorf("passwordToDecryptionKey | reason %v", err))
+		panic(fmt.Errorf("failed to marshal keystore crypto | reason %v", err))
 	}
-
-	binCipherMessage := misc.DecodeHex(k.Crypto.Cipher.Message)
-
-	checksum := CheckSumDecryptionKeyAndMessage(decryptionKey[16:32], binCipherMessage)
-	strChecksum := misc.EncodeHex(checksum[:])
-	if !reflect.DeepEqual(strChecksum, k.Crypto.Checksum.Message) {
-		panic(fmt.Errorf("checksum check failed | expected %s | found %s",
-			strChecksum, k.Crypto.Checksum.Message))
+	c := &keystore.KeystoreCrypto{}
+	if err := json.Unmarshal(b, c); err != nil {
+		panic(fmt.Errorf("failed to unmarshal keystore crypto | reason %v", err))
 	}
-
-	block, err := aes.NewCipher(decryptionKey[:16])
+	decrypted, err := c.Decrypt(password)
 	if err != nil {
-		panic(fmt.Errorf("aes.NewCipher failed | reason %v", err))
+		panic(fmt.Errorf("failed to decrypt keystore | reason %v", err))
 	}
 
 	var seed [common.SeedSize]uint8
-	cipherText := misc.DecodeHex(k.Crypto.Cipher.Message)
-	if len(cipherText) != len(seed) {
-		panic(fmt.Errorf("invalid cipher text length | expected length %d | actual length %d",
-			len(seed), len(cipherText)))
+	if len(decrypted) != len(seed) {
+		panic(fmt.Errorf("invalid seed length | expected length %d | actual length %d",
+			len(seed), len(decrypted)))
 	}
-	aesIV, ok := k.Crypto.Cipher.Params["iv"]
-	if !ok {
-		panic(fmt.Errorf("aesIV not found in Cipher Params"))
-	}
-	binAESIV := misc.DecodeHex(aesIV.(string))
-
-	stream := cipher.NewCTR(block, binAESIV)
-	stream.XORKeyStream(seed[:], cipherText)
-
+	copy(seed[:], decrypted)
 	return seed
 }
 
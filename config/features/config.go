@@ -72,6 +72,9 @@ type Flags struct {
 
 	AggregateParallel bool // AggregateParallel aggregates attestations in parallel.
 
+	EnableLightClient bool // EnableLightClient enables the light client server: REST endpoints, gossip and persisted updates.
+	EnableQUIC        bool // EnableQUIC enables the libp2p quic transport next to tcp.
+
 	// KeystoreImportDebounceInterval specifies the time duration the validator waits to reload new keys if they have
 	// changed on disk. This feature is for advanced use cases only.
 	KeystoreImportDebounceInterval time.Duration
@@ -165,6 +168,12 @@ func applySepoliaFeatureFlags(ctx *cli.Context) {
 func applyHoleskyFeatureFlags(ctx *cli.Context) {
 }
 
+// ConfigureLightClient sets the global config based
+// on what flags are enabled for the light client.
+func ConfigureLightClient(ctx *cli.Context) error {
+	return configureTestnet(ctx)
+}
+
 // ConfigureBeaconChain sets the global config based
 // on what flags are enabled for the beacon-chain client.
 func ConfigureBeaconChain(ctx *cli.Context) error {
@@ -255,6 +264,14 @@ func ConfigureBeaconChain(ctx *cli.Context) error {
 		logEnabled(enableEIP4881)
 		cfg.EnableEIP4881 = true
 	}
+	if ctx.IsSet(enableLightClient.Name) {
+		logEnabled(enableLightClient)
+		cfg.EnableLightClient = true
+	}
+	if ctx.IsSet(enableQUIC.Name) {
+		logEnabled(enableQUIC)
+		cfg.EnableQUIC = true
+	}
 	cfg.AggregateIntervals = [3]time.Duration{aggregateFirstInterval.Value, aggregateSecondInterval.Value, aggregateThirdInterval.Value}
 	Init(cfg)
 	return nil
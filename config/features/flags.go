@@ -163,6 +163,14 @@ var (
 		Name:  "disable-aggregate-parallel",
 		Usage: "Disables parallel aggregation of attestations",
 	}
+	enableLightClient = &cli.BoolFlag{
+		Name:  "enable-lightclient",
+		Usage: "Enables the light client server: serves light client data over the beacon API and gossip, and stores the best update of each sync committee period",
+	}
+	enableQUIC = &cli.BoolFlag{
+		Name:  "enable-quic",
+		Usage: "Enables the libp2p quic transport next to tcp, listening on --p2p-quic-port. Peers advertising quic are dialed over quic first",
+	}
 )
 
 // devModeFlags holds list of flags that are set when development mode is on.
@@ -219,6 +227,8 @@ var BeaconChainFlags = append(deprecatedBeaconFlags, append(deprecatedFlags, []c
 	disableResourceManager,
 	DisableRegistrationCache,
 	disableAggregateParallel,
+	enableLightClient,
+	enableQUIC,
 }...)...)
 
 // E2EBeaconChainFlags contains a list of the beacon chain feature flags to be tested in E2E.
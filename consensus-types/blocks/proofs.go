@@ -0,0 +1,172 @@
+package blocks
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	field_params "github.com/theQRL/qrysm/v4/config/fieldparams"
+	"github.com/theQRL/qrysm/v4/config/params"
+	consensus_types "github.com/theQRL/qrysm/v4/consensus-types"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/crypto/hash"
+	"github.com/theQRL/qrysm/v4/encoding/ssz"
+	"github.com/theQRL/qrysm/v4/runtime/version"
+)
+
+const (
+	// bodyFieldRootsLength is the number of leaves of the tree of the block body fields, which holds up to 16 fields.
+	bodyFieldRootsLength = 16
+	// executionPayloadFieldIndex is the index of the execution payload among the block body fields.
+	executionPayloadFieldIndex = 9
+)
+
+// PayloadProof returns the Merkle branch of the execution payload of a Bellatrix or later block body against the
+// root of the body, which proves the generalized index 25 of the body.
+func PayloadProof(body interfaces.ReadOnlyBeaconBlockBody) ([][]byte, error) {
+	b, ok := body.(*BeaconBlockBody)
+	if !ok || b.IsNil() {
+		return nil, errNilBlockBody
+	}
+	if b.version < version.Bellatrix {
+		return nil, consensus_types.ErrNotSupported("PayloadProof", b.version)
+	}
+	roots, err := computeBlockBodyFieldRoots(b)
+	if err != nil {
+		return nil, err
+	}
+	hasher := ssz.NewHasherFunc(hash.CustomSHA256Hasher())
+	branch := ssz.ConstructProof(hasher, uint64(len(roots)), bodyFieldRootsLength, func(i uint64) []byte {
+		return roots[i][:]
+	}, executionPayloadFieldIndex)
+	proof := make([][]byte, len(branch))
+	for i := range branch {
+		proof[i] = make([]byte, len(branch[i]))
+		copy(proof[i], branch[i][:])
+	}
+	return proof, nil
+}
+
+// computeBlockBodyFieldRoots returns the hash tree roots of the fields of the block body, in the order of the
+// fields of its version.
+func computeBlockBodyFieldRoots(b *BeaconBlockBody) ([][32]byte, error) {
+	cfg := params.BeaconConfig()
+	roots := make([][32]byte, 0, bodyFieldRootsLength)
+
+	randao, err := byteVectorRoot(b.randaoReveal[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "randao reveal")
+	}
+	roots = append(roots, randao)
+	eth1Data, err := b.eth1Data.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "eth1 data")
+	}
+	roots = append(roots, eth1Data, b.graffiti)
+	proposerSlashings, err := listRoot(b.proposerSlashings, cfg.MaxProposerSlashings)
+	if err != nil {
+		return nil, errors.Wrap(err, "proposer slashings")
+	}
+	attesterSlashings, err := listRoot(b.attesterSlashings, cfg.MaxAttesterSlashings)
+	if err != nil {
+		return nil, errors.Wrap(err, "attester slashings")
+	}
+	attestations, err := listRoot(b.attestations, cfg.MaxAttestations)
+	if err != nil {
+		return nil, errors.Wrap(err, "attestations")
+	}
+	deposits, err := listRoot(b.deposits, cfg.MaxDeposits)
+	if err != nil {
+		return nil, errors.Wrap(err, "deposits")
+	}
+	exits, err := listRoot(b.voluntaryExits, cfg.MaxVoluntaryExits)
+	if err != nil {
+		return nil, errors.Wrap(err, "voluntary exits")
+	}
+	roots = append(roots, proposerSlashings, attesterSlashings, attestations, deposits, exits)
+	if b.version < version.Altair {
+		return roots, nil
+	}
+
+	syncAggregate, err := b.syncAggregate.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "sync aggregate")
+	}
+	roots = append(roots, syncAggregate)
+	if b.version < version.Bellatrix {
+		return roots, nil
+	}
+
+	execution := b.executionPayload
+	if b.isBlinded {
+		execution = b.executionPayloadHeader
+	}
+	if execution == nil || execution.IsNil() {
+		return nil, errors.New("nil execution payload")
+	}
+	// The root of a payload is the root of its header, as the header holds the roots of the payload lists.
+	payload, err := execution.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "execution payload")
+	}
+	roots = append(roots, payload)
+	if b.version < version.Capella {
+		return roots, nil
+	}
+
+	changes, err := listRoot(b.dilithiumToExecutionChanges, cfg.MaxDilithiumToExecutionChanges)
+	if err != nil {
+		return nil, errors.Wrap(err, "dilithium to execution changes")
+	}
+	roots = append(roots, changes)
+	if b.version < version.Deneb {
+		return roots, nil
+	}
+
+	commitments := make([][32]byte, len(b.blobKzgCommitments))
+	for i, c := range b.blobKzgCommitments {
+		commitments[i], err = byteVectorRoot(c)
+		if err != nil {
+			return nil, errors.Wrapf(err, "blob kzg commitment %d", i)
+		}
+	}
+	kzgCommitments, err := mixInListLength(commitments, field_params.MaxBlobCommitmentsPerBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "blob kzg commitments")
+	}
+	return append(roots, kzgCommitments), nil
+}
+
+// byteVectorRoot returns the hash tree root of a fixed size byte vector.
+func byteVectorRoot(b []byte) ([32]byte, error) {
+	chunks, err := ssz.PackByChunk([][]byte{b})
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return ssz.BitwiseMerkleize(chunks, uint64(len(chunks)), uint64(len(chunks)))
+}
+
+// listRoot returns the hash tree root of a list of containers with the given maximum length.
+func listRoot[T interface{ HashTreeRoot() ([32]byte, error) }](items []T, limit uint64) ([32]byte, error) {
+	roots := make([][32]byte, len(items))
+	for i, item := range items {
+		root, err := item.HashTreeRoot()
+		if err != nil {
+			return [32]byte{}, err
+		}
+		roots[i] = root
+	}
+	return mixInListLength(roots, limit)
+}
+
+// mixInListLength merkleizes the roots of the elements of a list with the given maximum length, and mixes in the
+// length of the list.
+func mixInListLength(roots [][32]byte, limit uint64) ([32]byte, error) {
+	length := uint64(len(roots))
+	root, err := ssz.BitwiseMerkleize(roots, length, limit)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	lengthBytes := make([]byte, 32)
+	binary.LittleEndian.PutUint64(lengthBytes, length)
+	return ssz.MixInLength(root, lengthBytes), nil
+}
@@ -0,0 +1,75 @@
+package blocks
+
+import (
+	"testing"
+
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	consensus_types "github.com/theQRL/qrysm/v4/consensus-types"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/container/trie"
+	"github.com/theQRL/qrysm/v4/encoding/ssz"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestComputeBlockBodyFieldRoots(t *testing.T) {
+	for name, body := range map[string]*BeaconBlockBody{
+		"phase0":            bodyPhase0(),
+		"altair":            bodyAltair(),
+		"bellatrix":         bodyBellatrix(t),
+		"blinded bellatrix": bodyBlindedBellatrix(t),
+		"capella":           bodyCapella(t),
+		"blinded capella":   bodyBlindedCapella(t),
+		"deneb":             bodyDeneb(t),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sizeDilithiumKeys(body)
+			roots, err := computeBlockBodyFieldRoots(body)
+			require.NoError(t, err)
+			want, err := body.HashTreeRoot()
+			require.NoError(t, err)
+			// The field roots are the leaves of the tree of the body root.
+			assert.Equal(t, want, ssz.MerkleizeVector(roots, uint64(len(roots))))
+		})
+	}
+}
+
+func TestPayloadProof(t *testing.T) {
+	for name, body := range map[string]*BeaconBlockBody{
+		"bellatrix":       bodyBellatrix(t),
+		"blinded capella": bodyBlindedCapella(t),
+		"deneb":           bodyDeneb(t),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sizeDilithiumKeys(body)
+			proof, err := PayloadProof(body)
+			require.NoError(t, err)
+			require.Equal(t, 4, len(proof))
+			var execution interfaces.ExecutionData
+			if body.isBlinded {
+				execution = body.executionPayloadHeader
+			} else {
+				execution = body.executionPayload
+			}
+			payloadRoot, err := execution.HashTreeRoot()
+			require.NoError(t, err)
+			bodyRoot, err := body.HashTreeRoot()
+			require.NoError(t, err)
+			assert.Equal(t, true, trie.VerifyMerkleProof(bodyRoot[:], payloadRoot[:], 25, proof))
+			assert.Equal(t, false, trie.VerifyMerkleProof(bodyRoot[:], make([]byte, 32), 25, proof))
+		})
+	}
+
+	_, err := PayloadProof(bodyAltair())
+	require.ErrorIs(t, err, consensus_types.ErrUnsupportedField)
+}
+
+// sizeDilithiumKeys sizes the public keys of the body fixtures as Dilithium public keys.
+func sizeDilithiumKeys(body *BeaconBlockBody) {
+	for _, d := range body.deposits {
+		d.Data.PublicKey = make([]byte, dilithium2.CryptoPublicKeyBytes)
+	}
+	for _, c := range body.dilithiumToExecutionChanges {
+		c.Message.FromDilithiumPubkey = make([]byte, dilithium2.CryptoPublicKeyBytes)
+	}
+}
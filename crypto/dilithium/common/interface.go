@@ -0,0 +1,74 @@
+// Package common provides the interfaces that are implemented by the signature schemes behind
+// github.com/theQRL/qrysm/crypto/dilithium.
+//
+// This package should not be used by downstream consumers. These interfaces are re-exported by
+// github.com/theQRL/qrysm/crypto/dilithium. This package exists to prevent an import circular
+// dependency.
+//
+// The interfaces are designed for post-quantum signature schemes, whose signatures cannot be
+// aggregated: every signer of a message contributes its own signature, which is verified against
+// its own public key.
+package common
+
+import "fmt"
+
+// SchemeID identifies a signature scheme.
+type SchemeID uint8
+
+const (
+	// Dilithium is the round 3 Dilithium5 scheme of go-qrllib.
+	Dilithium SchemeID = iota + 1
+	// MLDSA87 is the ML-DSA-87 scheme of FIPS 204.
+	MLDSA87
+)
+
+// String returns the name of the signature scheme.
+func (id SchemeID) String() string {
+	switch id {
+	case Dilithium:
+		return "dilithium"
+	case MLDSA87:
+		return "ml-dsa-87"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(id))
+	}
+}
+
+// Scheme is a signature scheme. Keys and signatures are only valid for the scheme which created
+// them.
+type Scheme interface {
+	ID() SchemeID
+	// SeedLength is the length in bytes of the seed a secret key is derived from.
+	SeedLength() int
+	// PublicKeyLength is the length in bytes of a marshaled public key.
+	PublicKeyLength() int
+	// SignatureLength is the length in bytes of a marshaled signature.
+	SignatureLength() int
+	RandKey() (SecretKey, error)
+	SecretKeyFromSeed(seed []byte) (SecretKey, error)
+	PublicKeyFromBytes(pubKey []byte) (PublicKey, error)
+	SignatureFromBytes(sig []byte) (Signature, error)
+}
+
+// SecretKey represents a secret or private key.
+type SecretKey interface {
+	PublicKey() PublicKey
+	Sign(msg []byte) Signature
+	// Marshal returns the seed the secret key is derived from.
+	Marshal() []byte
+}
+
+// PublicKey represents a public key.
+type PublicKey interface {
+	Marshal() []byte
+	Copy() PublicKey
+	Equals(p2 PublicKey) bool
+}
+
+// Signature represents the signature of a single signer.
+type Signature interface {
+	// Verify returns false if the public key is not of the scheme of the signature.
+	Verify(pubKey PublicKey, msg []byte) bool
+	Marshal() []byte
+	Copy() Signature
+}
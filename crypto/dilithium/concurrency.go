@@ -0,0 +1,27 @@
+package dilithium
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// verificationConcurrency is the maximum number of goroutines used to
+// verify a batch of signatures. A value of zero or less defaults to
+// GOMAXPROCS.
+var verificationConcurrency atomic.Int64
+
+// SetVerificationConcurrency sets the maximum number of signatures that are
+// verified concurrently by VerifyMultipleSignatures. Passing a value of zero
+// or less restores the default of GOMAXPROCS.
+func SetVerificationConcurrency(limit int) {
+	verificationConcurrency.Store(int64(limit))
+}
+
+// VerificationConcurrency returns the maximum number of signatures that are
+// verified concurrently by VerifyMultipleSignatures.
+func VerificationConcurrency() int {
+	if limit := int(verificationConcurrency.Load()); limit > 0 {
+		return limit
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -1,59 +1,61 @@
 package dilithium
 
 import (
-	"github.com/theQRL/qrysm/v4/crypto/bls/common"
-	"github.com/theQRL/qrysm/v4/crypto/dilithium/dilithiumt"
+	"fmt"
 )
 
-// TODO (cyyber): Rename SecretKeyFromBytes to SecretKeyFromSeed
 func SecretKeyFromBytes(seed []byte) (DilithiumKey, error) {
-	return dilithiumt.SecretKeyFromBytes(seed)
+	return scheme.SecretKeyFromSeed(seed)
 }
 
 func PublicKeyFromBytes(pubKey []byte) (PublicKey, error) {
-	return dilithiumt.PublicKeyFromBytes(pubKey)
+	return scheme.PublicKeyFromBytes(pubKey)
 }
 
 func SignatureFromBytes(sig []byte) (Signature, error) {
-	return dilithiumt.SignatureFromBytes(sig)
-}
-
-func MultipleSignaturesFromBytes(sigs [][]byte) ([]Signature, error) {
-	return dilithiumt.MultipleSignaturesFromBytes(sigs)
-}
-
-func AggregatePublicKeys(pubs [][]byte) (PublicKey, error) {
-	return dilithiumt.AggregatePublicKeys(pubs)
-}
-
-func AggregateMultiplePubkeys(pubs []PublicKey) PublicKey {
-	return dilithiumt.AggregateMultiplePubkeys(pubs)
-}
-
-func AggregateSignatures(sigs []common.Signature) common.Signature {
-	return dilithiumt.AggregateSignatures(sigs)
-}
-
-func UnaggregatedSignatures(sigs []common.Signature) []byte {
-	return dilithiumt.UnaggregatedSignatures(sigs)
-}
-
-func AggregateCompressedSignatures(multiSigs [][]byte) (common.Signature, error) {
-	return dilithiumt.AggregateCompressedSignatures(multiSigs)
-}
-
-func VerifySignature(sig []byte, msg [32]byte, pubKey common.PublicKey) (bool, error) {
-	return dilithiumt.VerifySignature(sig, msg, pubKey)
-}
-
-func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys [][]common.PublicKey) (bool, error) {
-	return dilithiumt.VerifyMultipleSignatures(sigs, msgs, pubKeys)
-}
-
-func NewAggregateSignature() common.Signature {
-	return dilithiumt.NewAggregateSignature()
-}
-
-func RandKey() (common.SecretKey, error) {
-	return dilithiumt.RandKey()
+	return scheme.SignatureFromBytes(sig)
+}
+
+func MultipleSignaturesFromBytes(multiSigs [][]byte) ([]Signature, error) {
+	if len(multiSigs) == 0 {
+		return nil, fmt.Errorf("0 signatures provided to the method")
+	}
+	wrappedSigs := make([]Signature, len(multiSigs))
+	for i, s := range multiSigs {
+		sig, err := scheme.SignatureFromBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		wrappedSigs[i] = sig
+	}
+	return wrappedSigs, nil
+}
+
+// UnaggregatedSignatures concatenates the signatures. Signatures cannot be aggregated, so a
+// message signed by several signers carries the signature of each of them.
+func UnaggregatedSignatures(sigs []Signature) []byte {
+	if len(sigs) == 0 {
+		return nil
+	}
+
+	sigLen := scheme.SignatureLength()
+	unaggregatedSigns := make([]byte, sigLen*len(sigs))
+	offset := 0
+	for i := 0; i < len(sigs); i++ {
+		copy(unaggregatedSigns[offset:offset+sigLen], sigs[i].Marshal())
+		offset += sigLen
+	}
+	return unaggregatedSigns
+}
+
+func VerifySignature(sig []byte, msg [32]byte, pubKey PublicKey) (bool, error) {
+	rSig, err := SignatureFromBytes(sig)
+	if err != nil {
+		return false, err
+	}
+	return rSig.Verify(pubKey, msg[:]), nil
+}
+
+func RandKey() (DilithiumKey, error) {
+	return scheme.RandKey()
 }
@@ -5,7 +5,7 @@ import (
 
 	common2 "github.com/theQRL/go-qrllib/common"
 	"github.com/theQRL/go-qrllib/dilithium"
-	"github.com/theQRL/qrysm/v4/crypto/bls/common"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium/common"
 	"github.com/theQRL/qrysm/v4/crypto/rand"
 )
 
@@ -40,7 +40,7 @@ func SecretKeyFromBytes(seed []byte) (common.SecretKey, error) {
 	return &dilithiumKey{d: d}, nil
 }
 
-// PublicKey obtains the public key corresponding to the BLS secret key.
+// PublicKey obtains the public key corresponding to the secret key.
 func (d *dilithiumKey) PublicKey() common.PublicKey {
 	p := d.d.GetPK()
 	return &PublicKey{p: &p}
@@ -54,7 +54,7 @@ func (d *dilithiumKey) Sign(msg []byte) common.Signature {
 	return &Signature{s: &signature}
 }
 
-// Marshal a secret key into a LittleEndian byte slice.
+// Marshal returns the seed of the secret key.
 func (d *dilithiumKey) Marshal() []byte {
 	keyBytes := d.d.GetSeed()
 	return keyBytes[:]
@@ -0,0 +1,44 @@
+package dilithiumt
+
+import (
+	common2 "github.com/theQRL/go-qrllib/common"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium/common"
+)
+
+// Scheme is the round 3 Dilithium5 signature scheme of go-qrllib.
+type Scheme struct{}
+
+var _ common.Scheme = Scheme{}
+
+func (Scheme) ID() common.SchemeID {
+	return common.Dilithium
+}
+
+func (Scheme) SeedLength() int {
+	return common2.SeedSize
+}
+
+func (Scheme) PublicKeyLength() int {
+	return dilithium2.CryptoPublicKeyBytes
+}
+
+func (Scheme) SignatureLength() int {
+	return dilithium2.CryptoBytes
+}
+
+func (Scheme) RandKey() (common.SecretKey, error) {
+	return RandKey()
+}
+
+func (Scheme) SecretKeyFromSeed(seed []byte) (common.SecretKey, error) {
+	return SecretKeyFromBytes(seed)
+}
+
+func (Scheme) PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
+	return PublicKeyFromBytes(pubKey)
+}
+
+func (Scheme) SignatureFromBytes(sig []byte) (common.Signature, error) {
+	return SignatureFromBytes(sig)
+}
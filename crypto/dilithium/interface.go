@@ -1,12 +1,18 @@
 package dilithium
 
-import "github.com/theQRL/qrysm/v4/crypto/bls/common"
+import "github.com/theQRL/qrysm/v4/crypto/dilithium/common"
 
-// PublicKey represents a BLS public key.
+// PublicKey represents a public key of the signature scheme.
 type PublicKey = common.PublicKey
 
-// DilithiumKey represents a BLS secret or private key.
+// DilithiumKey represents a secret or private key of the signature scheme.
 type DilithiumKey = common.SecretKey
 
-// Signature represents a BLS signature.
+// Signature represents a signature of the signature scheme.
 type Signature = common.Signature
+
+// Scheme represents a signature scheme.
+type Scheme = common.Scheme
+
+// SchemeID identifies a signature scheme.
+type SchemeID = common.SchemeID
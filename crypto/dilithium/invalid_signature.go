@@ -0,0 +1,92 @@
+package dilithium
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+)
+
+// InvalidSignature describes a single signature of a signature batch
+// which failed verification.
+type InvalidSignature struct {
+	// BatchIndex is the index of the signature set within the batch.
+	BatchIndex int
+	// SignerIndex is the position of the signer within the signature set.
+	SignerIndex int
+	// Description is the label of the signature set, such as "attestation signature".
+	Description string
+	// ValidatorIndex is the index of the signer. It is only set if HasValidatorIndex is true.
+	ValidatorIndex    primitives.ValidatorIndex
+	HasValidatorIndex bool
+	// Message is the signing root the signature was checked against.
+	Message   [32]byte
+	PublicKey []byte
+	Signature []byte
+	// Err is the error returned while verifying the signature, if any.
+	Err error
+}
+
+// String returns a short, human readable description of the invalid signature.
+func (s *InvalidSignature) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "signature '%s' is invalid. batch index: %d, signer index: %d", s.Description, s.BatchIndex, s.SignerIndex)
+	if s.HasValidatorIndex {
+		fmt.Fprintf(&b, ", validator index: %d", s.ValidatorIndex)
+	}
+	fmt.Fprintf(&b, ", public key: %#x, message: %#x", s.PublicKey, s.Message)
+	if s.Err != nil {
+		fmt.Fprintf(&b, ", error: %v", s.Err)
+	}
+	return b.String()
+}
+
+// Fields returns the invalid signature details as structured logging fields.
+func (s *InvalidSignature) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"description": s.Description,
+		"batchIndex":  s.BatchIndex,
+		"signerIndex": s.SignerIndex,
+		"message":     fmt.Sprintf("%#x", s.Message),
+		"publicKey":   fmt.Sprintf("%#x", s.PublicKey),
+	}
+	if s.HasValidatorIndex {
+		fields["validatorIndex"] = s.ValidatorIndex
+	}
+	if s.Err != nil {
+		fields["error"] = s.Err
+	}
+	return fields
+}
+
+// InvalidSignaturesError is returned by SignatureBatch.VerifyVerbosely and
+// lists every invalid signature found in the batch.
+type InvalidSignaturesError struct {
+	Invalid []*InvalidSignature
+}
+
+// Error implements the error interface.
+func (e *InvalidSignaturesError) Error() string {
+	var b strings.Builder
+	b.WriteString("some signatures are invalid. details:")
+	for _, s := range e.Invalid {
+		b.WriteString("\n")
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// Descriptions returns the distinct descriptions of the invalid signatures,
+// in the order they were found.
+func (e *InvalidSignaturesError) Descriptions() []string {
+	seen := make(map[string]bool)
+	var descs []string
+	for _, s := range e.Invalid {
+		if seen[s.Description] {
+			continue
+		}
+		seen[s.Description] = true
+		descs = append(descs, s.Description)
+	}
+	return descs
+}
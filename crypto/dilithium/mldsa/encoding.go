@@ -0,0 +1,156 @@
+package mldsa
+
+// packBits packs the values into out, bitLen bits per value with the least significant bit
+// first, following SimpleBitPack of FIPS 204.
+func packBits(out []byte, values *[n]uint32, bitLen uint) {
+	var acc uint64
+	var accLen uint
+	i := 0
+	for _, v := range values {
+		acc |= uint64(v) << accLen
+		accLen += bitLen
+		for accLen >= 8 {
+			out[i] = byte(acc)
+			i++
+			acc >>= 8
+			accLen -= 8
+		}
+	}
+}
+
+// unpackBits is the inverse of packBits.
+func unpackBits(values *[n]uint32, in []byte, bitLen uint) {
+	var acc uint64
+	var accLen uint
+	i := 0
+	mask := uint64(1)<<bitLen - 1
+	for j := range values {
+		for accLen < bitLen {
+			acc |= uint64(in[i]) << accLen
+			i++
+			accLen += 8
+		}
+		values[j] = uint32(acc & mask)
+		acc >>= bitLen
+		accLen -= bitLen
+	}
+}
+
+// packZ encodes a polynomial with centered coefficients in [-γ1+1, γ1] as γ1 - z on 20 bits.
+func packZ(out []byte, p *poly) {
+	var v [n]uint32
+	for i, c := range p {
+		v[i] = uint32(gamma1 - centered(c))
+	}
+	packBits(out, &v, 20)
+}
+
+func unpackZ(p *poly, in []byte) {
+	var v [n]uint32
+	unpackBits(&v, in, 20)
+	for i := range p {
+		p[i] = modQ(gamma1 - int64(v[i]))
+	}
+}
+
+// pkEncode encodes the public key ρ || t1, following Algorithm 22 of FIPS 204.
+func pkEncode(rho []byte, t1 *polyVecK) []byte {
+	pk := make([]byte, PublicKeySize)
+	copy(pk, rho)
+	for i := range t1 {
+		var v [n]uint32
+		for j, c := range t1[i] {
+			v[j] = uint32(c)
+		}
+		packBits(pk[seedBytes+i*polyT1Bytes:], &v, 10)
+	}
+	return pk
+}
+
+// pkDecode decodes a public key, following Algorithm 23 of FIPS 204.
+func pkDecode(pk []byte) ([]byte, *polyVecK) {
+	var t1 polyVecK
+	for i := range t1 {
+		var v [n]uint32
+		unpackBits(&v, pk[seedBytes+i*polyT1Bytes:], 10)
+		for j := range t1[i] {
+			t1[i][j] = int32(v[j])
+		}
+	}
+	return pk[:seedBytes], &t1
+}
+
+// w1Encode encodes the high bits of the commitment, following Algorithm 28 of FIPS 204.
+func w1Encode(w1 *polyVecK) []byte {
+	out := make([]byte, k*polyW1Bytes)
+	for i := range w1 {
+		var v [n]uint32
+		for j, c := range w1[i] {
+			v[j] = uint32(c)
+		}
+		packBits(out[i*polyW1Bytes:], &v, 4)
+	}
+	return out
+}
+
+// sigEncode encodes the signature c̃ || z || h, following Algorithm 26 of FIPS 204.
+func sigEncode(m *mode, ctilde []byte, z *polyVecL, h *[k][n]bool) []byte {
+	sig := make([]byte, m.signatureSize())
+	copy(sig, ctilde)
+	off := m.ctildeBytes
+	for i := range z {
+		packZ(sig[off:], &z[i])
+		off += polyZBytes
+	}
+	// Hints are encoded as the positions of their non-zero coefficients, followed by the number of
+	// positions up to the end of each polynomial.
+	y := sig[off:]
+	idx := 0
+	for i := range h {
+		for j, set := range h[i] {
+			if set {
+				y[idx] = byte(j)
+				idx++
+			}
+		}
+		y[omega+i] = byte(idx)
+	}
+	return sig
+}
+
+// sigDecode decodes a signature, following Algorithm 27 of FIPS 204. It returns false if the hint
+// is malformed, so that every signature has a single valid encoding.
+func sigDecode(m *mode, sig []byte) ([]byte, *polyVecL, *[k][n]bool, bool) {
+	if len(sig) != m.signatureSize() {
+		return nil, nil, nil, false
+	}
+	ctilde := sig[:m.ctildeBytes]
+	off := m.ctildeBytes
+	var z polyVecL
+	for i := range z {
+		unpackZ(&z[i], sig[off:])
+		off += polyZBytes
+	}
+	y := sig[off:]
+	var h [k][n]bool
+	idx := 0
+	for i := range h {
+		end := int(y[omega+i])
+		if end < idx || end > omega {
+			return nil, nil, nil, false
+		}
+		first := idx
+		for ; idx < end; idx++ {
+			if idx > first && y[idx-1] >= y[idx] {
+				return nil, nil, nil, false
+			}
+			h[i][y[idx]] = true
+		}
+	}
+	for ; idx < omega; idx++ {
+		if y[idx] != 0 {
+			return nil, nil, nil, false
+		}
+	}
+	return ctilde, &z, &h, true
+}
@@ -0,0 +1,246 @@
+package mldsa
+
+import (
+	"crypto/subtle"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// PrivateKey is an ML-DSA-87 private key. It signs deterministically, with an empty context
+// string, as ML-DSA.Sign of FIPS 204 with the randomness fixed to zero.
+type PrivateKey struct {
+	seed [SeedSize]byte
+	key  *key
+}
+
+// NewKeyFromSeed generates the key pair of the seed ξ, following ML-DSA.KeyGen_internal of
+// FIPS 204.
+func NewKeyFromSeed(seed *[SeedSize]byte) *PrivateKey {
+	return &PrivateKey{seed: *seed, key: newKey(mlDSA87, seed[:])}
+}
+
+// Seed returns the seed ξ the key was generated from.
+func (sk *PrivateKey) Seed() [SeedSize]byte {
+	return sk.seed
+}
+
+// PublicKey returns the encoded public key.
+func (sk *PrivateKey) PublicKey() [PublicKeySize]byte {
+	return *(*[PublicKeySize]byte)(sk.key.pk)
+}
+
+// Sign returns the signature of the message.
+func (sk *PrivateKey) Sign(msg []byte) [SignatureSize]byte {
+	return *(*[SignatureSize]byte)(sk.key.sign(msg))
+}
+
+// Verify reports whether sig is a valid signature of the message by the public key.
+func Verify(pk *[PublicKeySize]byte, msg []byte, sig *[SignatureSize]byte) bool {
+	return verify(mlDSA87, pk[:], msg, sig[:])
+}
+
+// key is an expanded private key. The secret vectors, t0 and the matrix are kept in the NTT
+// domain.
+type key struct {
+	mode  *mode
+	pk    []byte
+	kSeed [seedBytes]byte
+	tr    []byte
+	a     *[k]polyVecL
+	s1    polyVecL
+	s2    polyVecK
+	t0    polyVecK
+}
+
+func newKey(m *mode, xi []byte) *key {
+	h := sha3.NewShake256()
+	_, _ = h.Write(xi)
+	if m.fips {
+		_, _ = h.Write([]byte{k, l})
+	}
+	rho := make([]byte, seedBytes)
+	rhoPrime := make([]byte, 64)
+	sk := &key{mode: m}
+	_, _ = h.Read(rho)
+	_, _ = h.Read(rhoPrime)
+	_, _ = h.Read(sk.kSeed[:])
+
+	sk.a = expandA(rho)
+	s1, s2 := expandS(rhoPrime)
+	sk.s1 = s1
+	for i := range sk.s1 {
+		sk.s1[i].ntt()
+	}
+	t := mulMatrix(sk.a, &sk.s1)
+	var t1 polyVecK
+	for i := range t {
+		t[i].invNTT()
+		t[i].add(&t[i], &s2[i])
+		for j, c := range t[i] {
+			t1[i][j], sk.t0[i][j] = power2Round(c)
+		}
+		sk.t0[i].ntt()
+		sk.s2[i] = s2[i]
+		sk.s2[i].ntt()
+	}
+	sk.pk = pkEncode(rho, &t1)
+	sk.tr = make([]byte, m.trBytes)
+	sha3.ShakeSum256(sk.tr, sk.pk)
+	return sk
+}
+
+// messageRepresentative returns μ, the hash of the public key hash and the message.
+func messageRepresentative(m *mode, tr, msg []byte) []byte {
+	mu := make([]byte, 64)
+	h := sha3.NewShake256()
+	_, _ = h.Write(tr)
+	if m.fips {
+		// The message is prefixed with the domain separator of pure signatures and the length of
+		// the empty context string.
+		_, _ = h.Write([]byte{0, 0})
+	}
+	_, _ = h.Write(msg)
+	_, _ = h.Read(mu)
+	return mu
+}
+
+// commitmentHash returns c̃, the hash of μ and the high bits of the commitment.
+func commitmentHash(m *mode, mu []byte, w1 *polyVecK) []byte {
+	ctilde := make([]byte, m.ctildeBytes)
+	h := sha3.NewShake256()
+	_, _ = h.Write(mu)
+	_, _ = h.Write(w1Encode(w1))
+	_, _ = h.Read(ctilde)
+	return ctilde
+}
+
+// sign follows ML-DSA.Sign_internal of FIPS 204.
+func (sk *key) sign(msg []byte) []byte {
+	m := sk.mode
+	mu := messageRepresentative(m, sk.tr, msg)
+	rhoPrime := make([]byte, 64)
+	h := sha3.NewShake256()
+	_, _ = h.Write(sk.kSeed[:])
+	if m.fips {
+		// Deterministic signing uses zero randomness.
+		_, _ = h.Write(make([]byte, 32))
+	}
+	_, _ = h.Write(mu)
+	_, _ = h.Read(rhoPrime)
+
+	for kappa := 0; ; kappa += l {
+		y := expandMask(rhoPrime, kappa)
+		yHat := y
+		for i := range yHat {
+			yHat[i].ntt()
+		}
+		w := mulMatrix(sk.a, &yHat)
+		var w1 polyVecK
+		for i := range w {
+			w[i].invNTT()
+			for j, c := range w[i] {
+				w1[i][j] = highBits(c)
+			}
+		}
+		ctilde := commitmentHash(m, mu, &w1)
+		c := sampleInBall(ctilde)
+		c.ntt()
+
+		var z polyVecL
+		if !sk.response(&z, &c, &y) {
+			continue
+		}
+		var hint [k][n]bool
+		if !sk.hint(&hint, &c, &w) {
+			continue
+		}
+		return sigEncode(m, ctilde, &z, &hint)
+	}
+}
+
+// response sets z = y + cs1 and reports whether it does not leak the secret vector.
+func (sk *key) response(z *polyVecL, c *poly, y *polyVecL) bool {
+	for i := range z {
+		z[i].mulNTT(c, &sk.s1[i])
+		z[i].invNTT()
+		z[i].add(&z[i], &y[i])
+		if z[i].infNorm() >= gamma1-beta {
+			return false
+		}
+	}
+	return true
+}
+
+// hint sets the hint which recovers the high bits of w from w - cs2 + ct0, and reports whether
+// neither it nor the low bits of w - cs2 leak the secret vectors.
+func (sk *key) hint(hint *[k][n]bool, c *poly, w *polyVecK) bool {
+	count := 0
+	var cs2, ct0, r poly
+	for i := range w {
+		cs2.mulNTT(c, &sk.s2[i])
+		cs2.invNTT()
+		r.sub(&w[i], &cs2)
+		for _, v := range r {
+			if r0 := lowBits(v); r0 >= gamma2-beta || r0 <= -(gamma2-beta) {
+				return false
+			}
+		}
+		ct0.mulNTT(c, &sk.t0[i])
+		ct0.invNTT()
+		if ct0.infNorm() >= gamma2 {
+			return false
+		}
+		r.add(&r, &ct0)
+		for j := range r {
+			hint[i][j] = makeHint(q-ct0[j], r[j])
+			if hint[i][j] {
+				count++
+			}
+		}
+	}
+	return count <= omega
+}
+
+// verify follows ML-DSA.Verify_internal of FIPS 204.
+func verify(m *mode, pk, msg, sig []byte) bool {
+	if len(pk) != PublicKeySize {
+		return false
+	}
+	ctilde, z, hint, ok := sigDecode(m, sig)
+	if !ok {
+		return false
+	}
+	for i := range z {
+		if z[i].infNorm() >= gamma1-beta {
+			return false
+		}
+	}
+	rho, t1 := pkDecode(pk)
+	a := expandA(rho)
+	tr := make([]byte, m.trBytes)
+	sha3.ShakeSum256(tr, pk)
+	mu := messageRepresentative(m, tr, msg)
+	c := sampleInBall(ctilde)
+	c.ntt()
+
+	zHat := *z
+	for i := range zHat {
+		zHat[i].ntt()
+	}
+	w := mulMatrix(a, &zHat)
+	var w1 polyVecK
+	var ct1 poly
+	for i := range w {
+		for j, v := range t1[i] {
+			ct1[j] = modQ(int64(v) << d)
+		}
+		ct1.ntt()
+		ct1.mulNTT(&c, &ct1)
+		w[i].sub(&w[i], &ct1)
+		w[i].invNTT()
+		for j, v := range w[i] {
+			w1[i][j] = useHint(hint[i][j], v)
+		}
+	}
+	return subtle.ConstantTimeCompare(ctilde, commitmentHash(m, mu, &w1)) == 1
+}
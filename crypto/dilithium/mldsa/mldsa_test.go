@@ -0,0 +1,164 @@
+package mldsa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	common2 "github.com/theQRL/go-qrllib/common"
+	"github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"golang.org/x/crypto/sha3"
+)
+
+// round3 is the round 3 Dilithium5 scheme implemented by go-qrllib.
+var round3 = &mode{ctildeBytes: 32, trBytes: 32}
+
+func testSeed(b byte) [SeedSize]byte {
+	var seed [SeedSize]byte
+	for i := range seed {
+		seed[i] = b + byte(i)
+	}
+	return seed
+}
+
+func TestNTT(t *testing.T) {
+	var a, b poly
+	for i := range a {
+		a[i] = modQ(int64(i)*7919 + 3)
+		b[i] = modQ(int64(n-i)*104729 - 11)
+	}
+
+	// The product in the NTT domain is the product modulo X^256+1.
+	var want poly
+	for i := range a {
+		for j := range b {
+			prod := int64(a[i]) * int64(b[j]) % q
+			if i+j < n {
+				want[i+j] = modQ(int64(want[i+j]) + prod)
+			} else {
+				want[i+j-n] = modQ(int64(want[i+j-n]) - prod)
+			}
+		}
+	}
+	aHat, bHat := a, b
+	aHat.ntt()
+	bHat.ntt()
+	var got poly
+	got.mulNTT(&aHat, &bHat)
+	got.invNTT()
+	assert.DeepEqual(t, want, got)
+
+	aHat.invNTT()
+	assert.DeepEqual(t, a, aHat)
+}
+
+func TestRound3MatchesQrllib(t *testing.T) {
+	msgs := [][]byte{nil, []byte("msg"), make([]byte, 32), make([]byte, 1000)}
+	for s := byte(0); s < 4; s++ {
+		var qrlSeed [common2.SeedSize]byte
+		for i := range qrlSeed {
+			qrlSeed[i] = 17*s + byte(i)
+		}
+		d, err := dilithium.NewDilithiumFromSeed(qrlSeed)
+		require.NoError(t, err)
+		var xi [SeedSize]byte
+		sha3.ShakeSum256(xi[:], qrlSeed[:])
+		sk := newKey(round3, xi[:])
+
+		pk := d.GetPK()
+		require.DeepEqual(t, pk[:], sk.pk)
+		for _, msg := range msgs {
+			want, err := d.Sign(msg)
+			require.NoError(t, err)
+			got := sk.sign(msg)
+			require.DeepEqual(t, want[:], got)
+			assert.Equal(t, true, verify(round3, sk.pk, msg, got))
+		}
+	}
+}
+
+// TestKnownAnswer checks the key and signature of a fixed seed against the Go standard library
+// implementation of ML-DSA-87, with deterministic signing and an empty context.
+func TestKnownAnswer(t *testing.T) {
+	seed := testSeed(0)
+	sk := NewKeyFromSeed(&seed)
+	pk := sk.PublicKey()
+	sig := sk.Sign([]byte("message"))
+
+	pkRoot := sha256.Sum256(pk[:])
+	sigRoot := sha256.Sum256(sig[:])
+	assert.Equal(t, "91dc389cfaa01470b7f66eee45a4ae9026d154817c754dfe22298b3fa241ffcd", hex.EncodeToString(pkRoot[:]))
+	assert.Equal(t, "b20f48bbdf5ddaef42efc83ccf823ca8e74d7692c3c61968d80d8f8385b6594b", hex.EncodeToString(sigRoot[:]))
+}
+
+// TestAccumulated checks the keys and signatures of seeds read from a SHAKE128 stream against the
+// accumulated ML-DSA-87 vectors of the Go standard library, which are shared by the
+// implementations cross-checked against the NIST ACVP vectors of FIPS 204.
+func TestAccumulated(t *testing.T) {
+	s := sha3.NewShake128()
+	o := sha3.NewShake128()
+	var seed [SeedSize]byte
+	for i := 0; i < 100; i++ {
+		_, err := s.Read(seed[:])
+		require.NoError(t, err)
+		sk := NewKeyFromSeed(&seed)
+		pk := sk.PublicKey()
+		_, err = o.Write(pk[:])
+		require.NoError(t, err)
+		sig := sk.Sign(nil)
+		_, err = o.Write(sig[:])
+		require.NoError(t, err)
+		require.Equal(t, true, Verify(&pk, nil, &sig))
+	}
+	sum := make([]byte, 32)
+	_, err := o.Read(sum)
+	require.NoError(t, err)
+	assert.Equal(t, "8c3ad714777622b8f21ce31bb35f71394f23bc0fcf3c78ace5d608990f3b061b", hex.EncodeToString(sum))
+}
+
+func TestSignVerify(t *testing.T) {
+	seed := testSeed(1)
+	sk := NewKeyFromSeed(&seed)
+	assert.Equal(t, seed, sk.Seed())
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	sig := sk.Sign(msg)
+	assert.Equal(t, true, Verify(&pk, msg, &sig))
+	// Signing is deterministic.
+	assert.Equal(t, sig, sk.Sign(msg))
+
+	assert.Equal(t, false, Verify(&pk, []byte("other message"), &sig))
+	otherSeed := testSeed(2)
+	otherPK := NewKeyFromSeed(&otherSeed).PublicKey()
+	assert.Equal(t, false, Verify(&otherPK, msg, &sig))
+
+	tampered := sig
+	tampered[100] ^= 1
+	assert.Equal(t, false, Verify(&pk, msg, &tampered))
+
+	// Signatures of the round 3 scheme are not valid ML-DSA signatures.
+	assert.Equal(t, false, verify(mlDSA87, pk[:], msg, newKey(round3, seed[:]).sign(msg)))
+}
+
+func TestVerify_MalformedHint(t *testing.T) {
+	seed := testSeed(3)
+	sk := NewKeyFromSeed(&seed)
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	sig := sk.Sign(msg)
+	hint := sig[SignatureSize-omega-k:]
+
+	// The number of hints of the last polynomial is above ω.
+	bad := sig
+	bad[SignatureSize-1] = omega + 1
+	assert.Equal(t, false, Verify(&pk, msg, &bad))
+
+	// Padding after the last hint must be zero.
+	bad = sig
+	count := int(hint[omega+k-1])
+	require.Equal(t, true, count < omega)
+	bad[SignatureSize-omega-k+omega-1] = 1
+	assert.Equal(t, false, Verify(&pk, msg, &bad))
+}
@@ -0,0 +1,58 @@
+// Package mldsa is a pure-Go reference implementation of the ML-DSA-87 signature scheme of
+// FIPS 204, for use behind the crypto/dilithium interfaces when building with the mldsa tag.
+//
+// It favours readability over speed: coefficients are kept in [0, q) and reduced with plain
+// modular arithmetic, and no attempt is made at constant time execution. It must not be used to
+// sign with keys which need to stay secret from a local attacker.
+package mldsa
+
+const (
+	n = 256
+	q = 8380417
+	d = 13
+	k = 8
+	l = 7
+	// eta bounds the coefficients of the secret vectors.
+	eta = 2
+	// tau is the number of non-zero coefficients of the challenge polynomial.
+	tau    = 60
+	beta   = tau * eta
+	gamma1 = 1 << 19
+	gamma2 = (q - 1) / 32
+	omega  = 75
+
+	seedBytes = 32
+	// polyT1Bytes is the size of a polynomial of t1, with 10 bit coefficients.
+	polyT1Bytes = n * 10 / 8
+	// polyZBytes is the size of a polynomial of z, with 20 bit coefficients.
+	polyZBytes = n * 20 / 8
+	// polyW1Bytes is the size of a polynomial of w1, with 4 bit coefficients.
+	polyW1Bytes = n * 4 / 8
+
+	// SeedSize is the size of the seed ξ keys are generated from.
+	SeedSize = seedBytes
+	// PublicKeySize is the size of an encoded public key.
+	PublicKeySize = seedBytes + k*polyT1Bytes
+	// SignatureSize is the size of an encoded signature.
+	SignatureSize = 64 + l*polyZBytes + omega + k
+)
+
+// mode holds the parameters which differ between ML-DSA-87 and the round 3 Dilithium5 scheme it
+// was standardised from. The round 3 mode only exists to check this implementation against
+// go-qrllib.
+type mode struct {
+	// ctildeBytes is the size of the commitment hash c̃.
+	ctildeBytes int
+	// trBytes is the size of the public key hash tr.
+	trBytes int
+	// fips enables the domain separation of FIPS 204: the dimensions of the matrix are hashed
+	// with the seed, messages are prefixed with an empty context, and signing randomness is
+	// mixed into the mask seed.
+	fips bool
+}
+
+var mlDSA87 = &mode{ctildeBytes: 64, trBytes: 64, fips: true}
+
+func (m *mode) signatureSize() int {
+	return m.ctildeBytes + l*polyZBytes + omega + k
+}
@@ -0,0 +1,295 @@
+package mldsa
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// poly is a polynomial of Z_q[X]/(X^256+1), with coefficients in [0, q).
+type poly [n]int32
+
+type polyVecL [l]poly
+
+type polyVecK [k]poly
+
+// zetas holds the powers of the 512th root of unity 1753 used by the NTT, in bit-reversed order.
+var zetas [n]int32
+
+func init() {
+	for i := range zetas {
+		z := int64(1)
+		for e := bits.Reverse8(uint8(i)); e > 0; e-- {
+			z = z * 1753 % q
+		}
+		zetas[i] = int32(z)
+	}
+}
+
+// modQ returns a mod q in [0, q).
+func modQ(a int64) int32 {
+	r := a % q
+	if r < 0 {
+		r += q
+	}
+	return int32(r)
+}
+
+// centered returns the representative of a in (-q/2, q/2].
+func centered(a int32) int32 {
+	if a > (q-1)/2 {
+		return a - q
+	}
+	return a
+}
+
+// ntt transforms the polynomial to the NTT domain in place, following Algorithm 41 of FIPS 204.
+func (p *poly) ntt() {
+	m := 0
+	for length := 128; length >= 1; length >>= 1 {
+		for start := 0; start < n; start += 2 * length {
+			m++
+			z := int64(zetas[m])
+			for j := start; j < start+length; j++ {
+				t := z * int64(p[j+length]) % q
+				p[j+length] = modQ(int64(p[j]) - t)
+				p[j] = modQ(int64(p[j]) + t)
+			}
+		}
+	}
+}
+
+// invNTT transforms the polynomial back from the NTT domain in place, following Algorithm 42 of
+// FIPS 204.
+func (p *poly) invNTT() {
+	m := n
+	for length := 1; length < n; length <<= 1 {
+		for start := 0; start < n; start += 2 * length {
+			m--
+			z := int64(q - zetas[m])
+			for j := start; j < start+length; j++ {
+				t := p[j]
+				p[j] = modQ(int64(t) + int64(p[j+length]))
+				p[j+length] = modQ(z * int64(modQ(int64(t)-int64(p[j+length]))))
+			}
+		}
+	}
+	// 256^-1 mod q.
+	const f = 8347681
+	for j := range p {
+		p[j] = modQ(f * int64(p[j]))
+	}
+}
+
+func (p *poly) add(a, b *poly) {
+	for i := range p {
+		p[i] = modQ(int64(a[i]) + int64(b[i]))
+	}
+}
+
+func (p *poly) sub(a, b *poly) {
+	for i := range p {
+		p[i] = modQ(int64(a[i]) - int64(b[i]))
+	}
+}
+
+// mulNTT sets p to the product of a and b, which are in the NTT domain.
+func (p *poly) mulNTT(a, b *poly) {
+	for i := range p {
+		p[i] = modQ(int64(a[i]) * int64(b[i]))
+	}
+}
+
+// infNorm returns the largest absolute value of the centered coefficients of the polynomial.
+func (p *poly) infNorm() int32 {
+	var m int32
+	for _, c := range p {
+		c = centered(c)
+		if c < 0 {
+			c = -c
+		}
+		if c > m {
+			m = c
+		}
+	}
+	return m
+}
+
+// mulMatrix returns Â ∘ v̂, where the matrix and the vector are in the NTT domain.
+func mulMatrix(a *[k]polyVecL, v *polyVecL) polyVecK {
+	var w polyVecK
+	var t poly
+	for i := range w {
+		for j := range v {
+			t.mulNTT(&a[i][j], &v[j])
+			w[i].add(&w[i], &t)
+		}
+	}
+	return w
+}
+
+// expandA samples the matrix Â in the NTT domain from the seed ρ, following Algorithm 32 of
+// FIPS 204.
+func expandA(rho []byte) *[k]polyVecL {
+	var a [k]polyVecL
+	seed := make([]byte, seedBytes+2)
+	copy(seed, rho)
+	for r := 0; r < k; r++ {
+		for s := 0; s < l; s++ {
+			seed[seedBytes] = byte(s)
+			seed[seedBytes+1] = byte(r)
+			a[r][s] = rejNTTPoly(seed)
+		}
+	}
+	return &a
+}
+
+// rejNTTPoly samples a polynomial with uniform coefficients by rejection, following Algorithm 30
+// of FIPS 204.
+func rejNTTPoly(seed []byte) poly {
+	var p poly
+	g := sha3.NewShake128()
+	_, _ = g.Write(seed)
+	var b [3]byte
+	for j := 0; j < n; {
+		_, _ = g.Read(b[:])
+		z := int32(b[2]&0x7f)<<16 | int32(b[1])<<8 | int32(b[0])
+		if z < q {
+			p[j] = z
+			j++
+		}
+	}
+	return p
+}
+
+// expandS samples the secret vectors s1 and s2 from the seed ρ', following Algorithm 33 of
+// FIPS 204.
+func expandS(rhoPrime []byte) (polyVecL, polyVecK) {
+	var s1 polyVecL
+	var s2 polyVecK
+	seed := make([]byte, len(rhoPrime)+2)
+	copy(seed, rhoPrime)
+	for r := range s1 {
+		binary.LittleEndian.PutUint16(seed[len(rhoPrime):], uint16(r))
+		s1[r] = rejBoundedPoly(seed)
+	}
+	for r := range s2 {
+		binary.LittleEndian.PutUint16(seed[len(rhoPrime):], uint16(r+l))
+		s2[r] = rejBoundedPoly(seed)
+	}
+	return s1, s2
+}
+
+// rejBoundedPoly samples a polynomial with coefficients in [-η, η] by rejection, following
+// Algorithm 31 of FIPS 204.
+func rejBoundedPoly(seed []byte) poly {
+	var p poly
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+	var b [1]byte
+	for j := 0; j < n; {
+		_, _ = h.Read(b[:])
+		for _, z := range [2]byte{b[0] & 0x0f, b[0] >> 4} {
+			if z < 15 && j < n {
+				p[j] = modQ(eta - int64(z%5))
+				j++
+			}
+		}
+	}
+	return p
+}
+
+// expandMask samples the masking vector y from the mask seed and the counter κ, following
+// Algorithm 34 of FIPS 204.
+func expandMask(rho []byte, kappa int) polyVecL {
+	var y polyVecL
+	seed := make([]byte, len(rho)+2)
+	copy(seed, rho)
+	buf := make([]byte, polyZBytes)
+	for r := range y {
+		binary.LittleEndian.PutUint16(seed[len(rho):], uint16(kappa+r))
+		sha3.ShakeSum256(buf, seed)
+		unpackZ(&y[r], buf)
+	}
+	return y
+}
+
+// sampleInBall samples the challenge polynomial with τ coefficients in {-1, 1} from the
+// commitment hash c̃, following Algorithm 29 of FIPS 204.
+func sampleInBall(ctilde []byte) poly {
+	var c poly
+	h := sha3.NewShake256()
+	_, _ = h.Write(ctilde)
+	var s [8]byte
+	_, _ = h.Read(s[:])
+	signs := binary.LittleEndian.Uint64(s[:])
+	var j [1]byte
+	for i := n - tau; i < n; i++ {
+		for {
+			_, _ = h.Read(j[:])
+			if int(j[0]) <= i {
+				break
+			}
+		}
+		c[i] = c[j[0]]
+		c[j[0]] = 1
+		if signs&1 == 1 {
+			c[j[0]] = q - 1
+		}
+		signs >>= 1
+	}
+	return c
+}
+
+// power2Round splits r into r1·2^d + r0 with r0 in (-2^(d-1), 2^(d-1)], following Algorithm 35
+// of FIPS 204. r0 is returned mod q.
+func power2Round(r int32) (int32, int32) {
+	r0 := r & (1<<d - 1)
+	if r0 > 1<<(d-1) {
+		r0 -= 1 << d
+	}
+	return (r - r0) >> d, modQ(int64(r0))
+}
+
+// decompose splits r into r1·2γ2 + r0 with r0 in (-γ2, γ2], following Algorithm 36 of FIPS 204.
+// r0 is returned centered.
+func decompose(r int32) (int32, int32) {
+	r0 := r % (2 * gamma2)
+	if r0 > gamma2 {
+		r0 -= 2 * gamma2
+	}
+	if r-r0 == q-1 {
+		return 0, r0 - 1
+	}
+	return (r - r0) / (2 * gamma2), r0
+}
+
+func highBits(r int32) int32 {
+	r1, _ := decompose(r)
+	return r1
+}
+
+func lowBits(r int32) int32 {
+	_, r0 := decompose(r)
+	return r0
+}
+
+// makeHint reports whether adding z to r changes its high bits, following Algorithm 39 of
+// FIPS 204.
+func makeHint(z, r int32) bool {
+	return highBits(r) != highBits(modQ(int64(r)+int64(z)))
+}
+
+// useHint returns the high bits of r corrected by the hint, following Algorithm 40 of FIPS 204.
+func useHint(h bool, r int32) int32 {
+	const m = (q - 1) / (2 * gamma2)
+	r1, r0 := decompose(r)
+	if !h {
+		return r1
+	}
+	if r0 > 0 {
+		return (r1 + 1) % m
+	}
+	return (r1 - 1 + m) % m
+}
@@ -0,0 +1,134 @@
+package mldsa
+
+import (
+	"fmt"
+
+	"github.com/theQRL/qrysm/v4/crypto/dilithium/common"
+	"github.com/theQRL/qrysm/v4/crypto/rand"
+	"golang.org/x/crypto/sha3"
+)
+
+// KeySeedSize is the size of the seeds secret keys of the scheme are derived from. It is the
+// size of the Dilithium seeds held by keystores, so that the same keystores can be used with
+// both schemes. The seed ξ of the key is the SHAKE256 hash of the seed, as go-qrllib does for
+// Dilithium keys.
+const KeySeedSize = 48
+
+// Scheme is the ML-DSA-87 signature scheme.
+type Scheme struct{}
+
+var _ common.Scheme = Scheme{}
+
+func (Scheme) ID() common.SchemeID {
+	return common.MLDSA87
+}
+
+func (Scheme) SeedLength() int {
+	return KeySeedSize
+}
+
+func (Scheme) PublicKeyLength() int {
+	return PublicKeySize
+}
+
+func (Scheme) SignatureLength() int {
+	return SignatureSize
+}
+
+func (s Scheme) RandKey() (common.SecretKey, error) {
+	var seed [KeySeedSize]byte
+	if _, err := rand.NewGenerator().Read(seed[:]); err != nil {
+		return nil, err
+	}
+	return s.SecretKeyFromSeed(seed[:])
+}
+
+func (Scheme) SecretKeyFromSeed(seed []byte) (common.SecretKey, error) {
+	if len(seed) != KeySeedSize {
+		return nil, fmt.Errorf("secret key must be %d bytes", KeySeedSize)
+	}
+	sk := &secretKey{}
+	copy(sk.seed[:], seed)
+	var xi [SeedSize]byte
+	sha3.ShakeSum256(xi[:], seed)
+	sk.k = NewKeyFromSeed(&xi)
+	return sk, nil
+}
+
+func (Scheme) PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
+	if len(pubKey) != PublicKeySize {
+		return nil, fmt.Errorf("public key must be %d bytes", PublicKeySize)
+	}
+	var p [PublicKeySize]byte
+	copy(p[:], pubKey)
+	return &publicKey{p: &p}, nil
+}
+
+func (Scheme) SignatureFromBytes(sig []byte) (common.Signature, error) {
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("signature must be %d bytes", SignatureSize)
+	}
+	var s [SignatureSize]byte
+	copy(s[:], sig)
+	return &signature{s: &s}, nil
+}
+
+type secretKey struct {
+	seed [KeySeedSize]byte
+	k    *PrivateKey
+}
+
+func (sk *secretKey) PublicKey() common.PublicKey {
+	p := sk.k.PublicKey()
+	return &publicKey{p: &p}
+}
+
+func (sk *secretKey) Sign(msg []byte) common.Signature {
+	s := sk.k.Sign(msg)
+	return &signature{s: &s}
+}
+
+// Marshal returns the seed of the secret key.
+func (sk *secretKey) Marshal() []byte {
+	seed := sk.seed
+	return seed[:]
+}
+
+type publicKey struct {
+	p *[PublicKeySize]byte
+}
+
+func (p *publicKey) Marshal() []byte {
+	return p.p[:]
+}
+
+func (p *publicKey) Copy() common.PublicKey {
+	np := *p.p
+	return &publicKey{p: &np}
+}
+
+func (p *publicKey) Equals(p2 common.PublicKey) bool {
+	other, ok := p2.(*publicKey)
+	return ok && *p.p == *other.p
+}
+
+type signature struct {
+	s *[SignatureSize]byte
+}
+
+func (s *signature) Verify(pubKey common.PublicKey, msg []byte) bool {
+	p, ok := pubKey.(*publicKey)
+	if !ok {
+		return false
+	}
+	return Verify(p.p, msg, s.s)
+}
+
+func (s *signature) Marshal() []byte {
+	return s.s[:]
+}
+
+func (s *signature) Copy() common.Signature {
+	ns := *s.s
+	return &signature{s: &ns}
+}
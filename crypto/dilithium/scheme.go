@@ -0,0 +1,33 @@
+package dilithium
+
+// The signature scheme behind this package is selected at build time. Dilithium is used by
+// default, and ML-DSA-87 when building with the mldsa tag:
+//
+//	go build -tags=mldsa ./...
+//	bazel build --config=mldsa //...
+//
+// ML-DSA-87 public keys have the size of Dilithium ones, but its signatures are 32 bytes longer.
+// The swap covers the code which signs, verifies and slices signatures through the sizes of this
+// package. The SSZ types generated from the protobuf definitions keep fixed Dilithium sized
+// signature fields, so consensus containers carrying a single signature cannot hold ML-DSA-87
+// ones, and the mldsa build cannot join a network.
+
+// CurrentScheme returns the signature scheme selected at build time.
+func CurrentScheme() Scheme {
+	return scheme
+}
+
+// SeedLength returns the length in bytes of the seed secret keys are derived from.
+func SeedLength() int {
+	return scheme.SeedLength()
+}
+
+// PublicKeyLength returns the length in bytes of a marshaled public key.
+func PublicKeyLength() int {
+	return scheme.PublicKeyLength()
+}
+
+// SignatureLength returns the length in bytes of the signature of a single signer.
+func SignatureLength() int {
+	return scheme.SignatureLength()
+}
@@ -0,0 +1,7 @@
+//go:build !mldsa
+
+package dilithium
+
+import "github.com/theQRL/qrysm/v4/crypto/dilithium/dilithiumt"
+
+var scheme Scheme = dilithiumt.Scheme{}
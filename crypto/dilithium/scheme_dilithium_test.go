@@ -0,0 +1,16 @@
+//go:build !mldsa
+
+package dilithium
+
+import (
+	"testing"
+
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+)
+
+// The SSZ types are generated with fixed signature sizes, which must match the default scheme.
+func TestScheme_SSZSignatureLength(t *testing.T) {
+	size := (&zondpb.SignedBeaconBlockHeader{}).SizeSSZ() - (&zondpb.BeaconBlockHeader{}).SizeSSZ()
+	assert.Equal(t, SignatureLength(), size)
+}
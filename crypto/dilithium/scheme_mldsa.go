@@ -0,0 +1,7 @@
+//go:build mldsa
+
+package dilithium
+
+import "github.com/theQRL/qrysm/v4/crypto/dilithium/mldsa"
+
+var scheme Scheme = mldsa.Scheme{}
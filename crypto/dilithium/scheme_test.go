@@ -0,0 +1,52 @@
+package dilithium
+
+import (
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestScheme_Sizes(t *testing.T) {
+	sk, err := RandKey()
+	require.NoError(t, err)
+	assert.Equal(t, SeedLength(), len(sk.Marshal()))
+	assert.Equal(t, PublicKeyLength(), len(sk.PublicKey().Marshal()))
+	assert.Equal(t, SignatureLength(), len(sk.Sign([]byte("msg")).Marshal()))
+
+	_, err = SecretKeyFromBytes(make([]byte, SeedLength()-1))
+	assert.ErrorContains(t, "secret key must be", err)
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeyLength()+1))
+	assert.ErrorContains(t, "public key must be", err)
+	_, err = SignatureFromBytes(make([]byte, SignatureLength()-1))
+	assert.ErrorContains(t, "signature must be", err)
+}
+
+func TestScheme_SignVerify(t *testing.T) {
+	sk, err := RandKey()
+	require.NoError(t, err)
+	other, err := RandKey()
+	require.NoError(t, err)
+	msg := [32]byte{'m', 's', 'g'}
+	sig := sk.Sign(msg[:])
+	assert.Equal(t, true, sig.Verify(sk.PublicKey(), msg[:]))
+	assert.Equal(t, false, sig.Verify(other.PublicKey(), msg[:]))
+	assert.Equal(t, false, sig.Verify(sk.PublicKey(), []byte("other msg")))
+
+	// Keys and signatures survive a round trip through their encoding.
+	restored, err := SecretKeyFromBytes(sk.Marshal())
+	require.NoError(t, err)
+	pub, err := PublicKeyFromBytes(restored.PublicKey().Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, true, pub.Equals(sk.PublicKey()))
+	assert.Equal(t, false, pub.Equals(other.PublicKey()))
+	valid, err := VerifySignature(sig.Marshal(), msg, pub)
+	require.NoError(t, err)
+	assert.Equal(t, true, valid)
+
+	// Copies do not share memory with the original.
+	cpy := sig.Copy()
+	cpy.Marshal()[0] ^= 0xff
+	assert.Equal(t, true, sig.Verify(pub, msg[:]))
+	assert.Equal(t, false, cpy.Verify(pub, msg[:]))
+}
@@ -1,16 +1,12 @@
 package dilithium
 
 import (
-	"encoding/hex"
-	"fmt"
+	"context"
 
 	"github.com/pkg/errors"
-	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
 )
 
-// AggregatedSignature represents aggregated signature produced by AggregateBatch()
-const AggregatedSignature = "dilithium aggregated signature"
-
 // SignatureBatch refers to the defined set of
 // signatures and its respective public keys and
 // messages required to verify it.
@@ -19,6 +15,9 @@ type SignatureBatch struct {
 	PublicKeys   [][]PublicKey
 	Messages     [][32]byte
 	Descriptions []string
+	// ValidatorIndices optionally holds the validator index of every signer,
+	// in the same order as PublicKeys. It is only used for error reporting.
+	ValidatorIndices [][]primitives.ValidatorIndex
 }
 
 // NewSet constructs an empty signature batch object.
@@ -33,6 +32,11 @@ func NewSet() *SignatureBatch {
 
 // Join merges the provided signature batch to out current one.
 func (s *SignatureBatch) Join(set *SignatureBatch) *SignatureBatch {
+	if len(s.ValidatorIndices) > 0 || len(set.ValidatorIndices) > 0 {
+		// Keep validator indices aligned with signatures when only one of the batches carries them.
+		s.ValidatorIndices = padValidatorIndices(s.ValidatorIndices, len(s.Signatures))
+		s.ValidatorIndices = append(s.ValidatorIndices, padValidatorIndices(set.ValidatorIndices, len(set.Signatures))...)
+	}
 	s.Signatures = append(s.Signatures, set.Signatures...)
 	s.PublicKeys = append(s.PublicKeys, set.PublicKeys...)
 	s.Messages = append(s.Messages, set.Messages...)
@@ -45,42 +49,145 @@ func (s *SignatureBatch) Verify() (bool, error) {
 	return VerifyMultipleSignatures(s.Signatures, s.Messages, s.PublicKeys)
 }
 
-// VerifyVerbosely verifies signatures as a whole at first, if fails, fallback
-// to verify each single signature to identify invalid ones.
+// VerifyWithContext verifies the current signature batch, aborting any
+// outstanding signature checks once the provided context is done.
+func (s *SignatureBatch) VerifyWithContext(ctx context.Context) (bool, error) {
+	return VerifyMultipleSignaturesWithContext(ctx, s.Signatures, s.Messages, s.PublicKeys)
+}
+
+// VerifyVerbosely verifies signatures as a whole at first, if fails, locates the
+// invalid signatures by bisecting the batch. The returned error is an
+// *InvalidSignaturesError describing every invalid signature.
 func (s *SignatureBatch) VerifyVerbosely() (bool, error) {
-	valid, err := s.Verify()
+	return s.VerifyVerboselyWithContext(context.Background())
+}
+
+// VerifyVerboselyWithContext verifies the batch like VerifyVerbosely, aborting both the
+// verification and the search of the invalid signatures once the provided context is done.
+func (s *SignatureBatch) VerifyVerboselyWithContext(ctx context.Context) (bool, error) {
+	valid, err := s.VerifyWithContext(ctx)
 	if err != nil || valid {
 		return valid, err
 	}
 
-	// if signature batch is invalid, we then verify signatures one by one.
+	invalid, err := s.findInvalidSignatures(ctx, true /* knownInvalid */)
+	if err != nil {
+		return false, err
+	}
+	if len(invalid) == 0 {
+		return false, errors.New("signature batch failed verification, but no invalid signature could be found")
+	}
+	return false, &InvalidSignaturesError{Invalid: invalid}
+}
 
-	errmsg := "some signatures are invalid. details:"
-	for i := 0; i < len(s.Signatures); i++ {
-		for j, pubKey := range s.PublicKeys[i] {
-			offset := j * dilithium2.CryptoBytes
-			sig := s.Signatures[i][offset : offset+dilithium2.CryptoBytes]
-			msg := s.Messages[i]
-
-			valid, err := VerifySignature(sig, msg, pubKey)
-			if !valid {
-				desc := s.Descriptions[i]
-				if err != nil {
-					errmsg += fmt.Sprintf("\nsignature '%s' is invalid."+
-						" signature: 0x%s, public key: 0x%s, message: 0x%v, error: %v",
-						desc, hex.EncodeToString(sig), hex.EncodeToString(pubKey.Marshal()),
-						hex.EncodeToString(msg[:]), err)
-				} else {
-					errmsg += fmt.Sprintf("\nsignature '%s' is invalid."+
-						" signature: 0x%s, public key: 0x%s, message: 0x%v",
-						desc, hex.EncodeToString(sig), hex.EncodeToString(pubKey.Marshal()),
-						hex.EncodeToString(msg[:]))
-				}
-			}
+// FindInvalidSignatures returns every signature of the batch which fails verification.
+// Invalid signatures are located by recursively bisecting the batch, so that valid
+// halves are verified only once.
+func (s *SignatureBatch) FindInvalidSignatures() ([]*InvalidSignature, error) {
+	return s.findInvalidSignatures(context.Background(), false /* knownInvalid */)
+}
+
+func (s *SignatureBatch) findInvalidSignatures(ctx context.Context, knownInvalid bool) ([]*InvalidSignature, error) {
+	if len(s.Signatures) != len(s.PublicKeys) || len(s.Signatures) != len(s.Messages) {
+		return nil, errors.Errorf("mismatch number of signatures, publickeys and messages in signature batch. "+
+			"Signatures %d, Public Keys %d , Messages %d", len(s.Signatures), len(s.PublicKeys), len(s.Messages))
+	}
+	sigLen := SignatureLength()
+	var pairs []signaturePair
+	for i := range s.Signatures {
+		if len(s.Signatures[i]) != len(s.PublicKeys[i])*sigLen {
+			return nil, errors.Errorf("signature %d must be %d bytes for %d public keys, got %d",
+				i, len(s.PublicKeys[i])*sigLen, len(s.PublicKeys[i]), len(s.Signatures[i]))
+		}
+		for j := range s.PublicKeys[i] {
+			pairs = append(pairs, signaturePair{batchIndex: i, signerIndex: j})
 		}
 	}
 
-	return false, errors.Errorf(errmsg)
+	var invalid []*InvalidSignature
+	if err := s.bisect(ctx, pairs, knownInvalid, &invalid); err != nil {
+		return nil, err
+	}
+	return invalid, nil
+}
+
+// signaturePair identifies a single signer of a signature set in the batch.
+type signaturePair struct {
+	batchIndex  int
+	signerIndex int
+}
+
+func (s *SignatureBatch) bisect(ctx context.Context, pairs []signaturePair, knownInvalid bool, invalid *[]*InvalidSignature) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(pairs) == 1 {
+		sig, pubKey, msg := s.pair(pairs[0])
+		valid, err := VerifySignature(sig, msg, pubKey)
+		if !valid {
+			*invalid = append(*invalid, s.invalidSignature(pairs[0], err))
+		}
+		return nil
+	}
+	if !knownInvalid {
+		sigs := make([][]byte, len(pairs))
+		msgs := make([][32]byte, len(pairs))
+		pubKeys := make([][]PublicKey, len(pairs))
+		for i, p := range pairs {
+			sig, pubKey, msg := s.pair(p)
+			sigs[i] = sig
+			msgs[i] = msg
+			pubKeys[i] = []PublicKey{pubKey}
+		}
+		valid, err := VerifyMultipleSignaturesWithContext(ctx, sigs, msgs, pubKeys)
+		if err != nil {
+			return err
+		}
+		if valid {
+			return nil
+		}
+	}
+	mid := len(pairs) / 2
+	if err := s.bisect(ctx, pairs[:mid], false, invalid); err != nil {
+		return err
+	}
+	return s.bisect(ctx, pairs[mid:], false, invalid)
+}
+
+func (s *SignatureBatch) pair(p signaturePair) ([]byte, PublicKey, [32]byte) {
+	sigLen := SignatureLength()
+	offset := p.signerIndex * sigLen
+	return s.Signatures[p.batchIndex][offset : offset+sigLen],
+		s.PublicKeys[p.batchIndex][p.signerIndex],
+		s.Messages[p.batchIndex]
+}
+
+func (s *SignatureBatch) invalidSignature(p signaturePair, err error) *InvalidSignature {
+	sig, pubKey, msg := s.pair(p)
+	res := &InvalidSignature{
+		BatchIndex:  p.batchIndex,
+		SignerIndex: p.signerIndex,
+		Message:     msg,
+		PublicKey:   pubKey.Marshal(),
+		Signature:   sig,
+		Err:         err,
+	}
+	if p.batchIndex < len(s.Descriptions) {
+		res.Description = s.Descriptions[p.batchIndex]
+	}
+	res.ValidatorIndex, res.HasValidatorIndex = s.validatorIndex(p.batchIndex, p.signerIndex)
+	return res
+}
+
+// validatorIndex returns the validator index of the signer at the given position, if it is known.
+func (s *SignatureBatch) validatorIndex(batchIndex, signerIndex int) (primitives.ValidatorIndex, bool) {
+	if batchIndex >= len(s.ValidatorIndices) || signerIndex >= len(s.ValidatorIndices[batchIndex]) {
+		return 0, false
+	}
+	return s.ValidatorIndices[batchIndex][signerIndex], true
 }
 
 // Copy the attached signature batch and return it
@@ -105,11 +212,19 @@ func (s *SignatureBatch) Copy() *SignatureBatch {
 		copy(messages[i][:], s.Messages[i][:])
 	}
 	copy(descriptions, s.Descriptions)
+	var validatorIndices [][]primitives.ValidatorIndex
+	if len(s.ValidatorIndices) > 0 {
+		validatorIndices = make([][]primitives.ValidatorIndex, len(s.ValidatorIndices))
+		for i := range s.ValidatorIndices {
+			validatorIndices[i] = append([]primitives.ValidatorIndex(nil), s.ValidatorIndices[i]...)
+		}
+	}
 	return &SignatureBatch{
-		Signatures:   signatures,
-		PublicKeys:   pubkeys,
-		Messages:     messages,
-		Descriptions: descriptions,
+		Signatures:       signatures,
+		PublicKeys:       pubkeys,
+		Messages:         messages,
+		Descriptions:     descriptions,
+		ValidatorIndices: validatorIndices,
 	}
 }
 
@@ -150,6 +265,8 @@ func (s *SignatureBatch) RemoveDuplicates() (int, *SignatureBatch, error) {
 	pubs := s.PublicKeys[:0]
 	msgs := s.Messages[:0]
 	descs := s.Descriptions[:0]
+	valIndices := padValidatorIndices(s.ValidatorIndices, len(s.Signatures))
+	vals := valIndices[:0]
 
 	for i := 0; i < len(s.Signatures); i++ {
 		if duplicateSet[i] {
@@ -159,19 +276,23 @@ func (s *SignatureBatch) RemoveDuplicates() (int, *SignatureBatch, error) {
 		pubs = append(pubs, s.PublicKeys[i])
 		msgs = append(msgs, s.Messages[i])
 		descs = append(descs, s.Descriptions[i])
+		vals = append(vals, valIndices[i])
 	}
 
 	s.Signatures = sigs
 	s.PublicKeys = pubs
 	s.Messages = msgs
 	s.Descriptions = descs
+	if len(s.ValidatorIndices) > 0 {
+		s.ValidatorIndices = vals
+	}
 
 	return len(duplicateSet), s, nil
 }
 
-// AggregateBatch aggregates common messages in the provided batch to
-// reduce the number of pairings required when we finally verify the
-// whole batch.
+// AggregateBatch groups signature sets with common messages in the provided
+// batch. Dilithium signatures cannot be aggregated, so every set keeps its own
+// message, description and validator indices.
 func (s *SignatureBatch) AggregateBatch() (*SignatureBatch, error) {
 	if len(s.Signatures) != len(s.PublicKeys) || len(s.Signatures) != len(s.Messages) || len(s.Signatures) != len(s.Descriptions) {
 		return s, errors.Errorf("mismatch number of signatures, publickeys, messages and descriptions in signature batch. "+
@@ -180,7 +301,9 @@ func (s *SignatureBatch) AggregateBatch() (*SignatureBatch, error) {
 	if len(s.Signatures) == 0 {
 		return s, nil
 	}
+	valIndices := padValidatorIndices(s.ValidatorIndices, len(s.Signatures))
 	msgMap := make(map[[32]byte]*SignatureBatch)
+	var msgOrder [][32]byte
 
 	for i := 0; i < len(s.Messages); i++ {
 		currMsg := s.Messages[i]
@@ -190,32 +313,34 @@ func (s *SignatureBatch) AggregateBatch() (*SignatureBatch, error) {
 			currBatch.Messages = append(currBatch.Messages, s.Messages[i])
 			currBatch.PublicKeys = append(currBatch.PublicKeys, s.PublicKeys[i])
 			currBatch.Descriptions = append(currBatch.Descriptions, s.Descriptions[i])
+			currBatch.ValidatorIndices = append(currBatch.ValidatorIndices, valIndices[i])
 			continue
 		}
 		currBatch = &SignatureBatch{
-			Signatures:   [][]byte{s.Signatures[i]},
-			Messages:     [][32]byte{s.Messages[i]},
-			PublicKeys:   [][]PublicKey{s.PublicKeys[i]},
-			Descriptions: []string{s.Descriptions[i]},
+			Signatures:       [][]byte{s.Signatures[i]},
+			Messages:         [][32]byte{s.Messages[i]},
+			PublicKeys:       [][]PublicKey{s.PublicKeys[i]},
+			Descriptions:     []string{s.Descriptions[i]},
+			ValidatorIndices: [][]primitives.ValidatorIndex{valIndices[i]},
 		}
 		msgMap[currMsg] = currBatch
+		msgOrder = append(msgOrder, currMsg)
 	}
 	newSt := NewSet()
-	for rt, b := range msgMap {
-		if len(b.PublicKeys) > 1 {
-			//aggPub := AggregateMultiplePubkeys(b.PublicKeys)
-			//aggSig, err := AggregateCompressedSignatures(b.Signatures)
-			//if err != nil {
-			//	return nil, err
-			//}
-			copiedRt := rt
-			//b.PublicKeys = [][]PublicKey{aggPub}
-			//b.Signatures = [][]byte{aggSig.Marshal()}
-			b.Messages = [][32]byte{copiedRt}
-			b.Descriptions = []string{AggregatedSignature}
-		}
-		newObj := *b
-		newSt = newSt.Join(&newObj)
+	for _, rt := range msgOrder {
+		newSt = newSt.Join(msgMap[rt])
+	}
+	if len(s.ValidatorIndices) == 0 {
+		newSt.ValidatorIndices = nil
 	}
 	return newSt, nil
 }
+
+// padValidatorIndices extends the provided validator indices with empty
+// entries until it holds an entry for each of the n signature sets.
+func padValidatorIndices(indices [][]primitives.ValidatorIndex, n int) [][]primitives.ValidatorIndex {
+	for len(indices) < n {
+		indices = append(indices, nil)
+	}
+	return indices
+}
@@ -0,0 +1,150 @@
+package dilithium
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+const testDescription = "test signature"
+
+// newSignatureBatch creates a batch of numSets signature sets, each signed by signersPerSet signers.
+// Validator indices are assigned sequentially to the signers.
+func newSignatureBatch(t *testing.T, numSets, signersPerSet int) *SignatureBatch {
+	set := NewSet()
+	valIdx := primitives.ValidatorIndex(0)
+	for i := 0; i < numSets; i++ {
+		msg := [32]byte{'m', 's', 'g', byte(i)}
+		var sigs []Signature
+		var pubKeys []PublicKey
+		var indices []primitives.ValidatorIndex
+		for j := 0; j < signersPerSet; j++ {
+			key, err := RandKey()
+			require.NoError(t, err)
+			sigs = append(sigs, key.Sign(msg[:]))
+			pubKeys = append(pubKeys, key.PublicKey())
+			indices = append(indices, valIdx)
+			valIdx++
+		}
+		set.Join(&SignatureBatch{
+			Signatures:       [][]byte{UnaggregatedSignatures(sigs)},
+			PublicKeys:       [][]PublicKey{pubKeys},
+			Messages:         [][32]byte{msg},
+			Descriptions:     []string{testDescription},
+			ValidatorIndices: [][]primitives.ValidatorIndex{indices},
+		})
+	}
+	return set
+}
+
+func TestVerifyVerbosely_AllSignaturesValid(t *testing.T) {
+	set := newSignatureBatch(t, 3, 2)
+	valid, err := set.VerifyVerbosely()
+	require.NoError(t, err)
+	assert.Equal(t, true, valid)
+}
+
+func TestVerifyVerbosely_LocatesInvalidSignatures(t *testing.T) {
+	set := newSignatureBatch(t, 4, 3)
+	// Corrupt the message of the second set, and swap two signers of the last set.
+	set.Messages[1] = [32]byte{'b', 'a', 'd'}
+	set.PublicKeys[3][0], set.PublicKeys[3][2] = set.PublicKeys[3][2], set.PublicKeys[3][0]
+
+	valid, err := set.VerifyVerbosely()
+	assert.Equal(t, false, valid)
+	var sigErr *InvalidSignaturesError
+	require.Equal(t, true, errors.As(err, &sigErr))
+	require.Equal(t, 5, len(sigErr.Invalid))
+
+	type position struct{ batch, signer int }
+	want := []position{{1, 0}, {1, 1}, {1, 2}, {3, 0}, {3, 2}}
+	for i, sig := range sigErr.Invalid {
+		assert.Equal(t, want[i].batch, sig.BatchIndex)
+		assert.Equal(t, want[i].signer, sig.SignerIndex)
+		assert.Equal(t, testDescription, sig.Description)
+		assert.Equal(t, set.Messages[sig.BatchIndex], sig.Message)
+		assert.Equal(t, true, sig.HasValidatorIndex)
+		assert.Equal(t, set.ValidatorIndices[sig.BatchIndex][sig.SignerIndex], sig.ValidatorIndex)
+	}
+	assert.DeepEqual(t, []string{testDescription}, sigErr.Descriptions())
+	assert.ErrorContains(t, "validator index: 9", err)
+}
+
+func TestVerifyVerboselyWithContext_Cancelled(t *testing.T) {
+	set := newSignatureBatch(t, 2, 2)
+	set.Messages[1] = [32]byte{'b', 'a', 'd'}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	valid, err := set.VerifyVerboselyWithContext(ctx)
+	assert.Equal(t, false, valid)
+	require.ErrorIs(t, err, context.Canceled)
+	var sigErr *InvalidSignaturesError
+	assert.Equal(t, false, errors.As(err, &sigErr))
+}
+
+func TestFindInvalidSignatures_WithoutValidatorIndices(t *testing.T) {
+	set := newSignatureBatch(t, 2, 1)
+	set.ValidatorIndices = nil
+	set.Messages[0] = [32]byte{'b', 'a', 'd'}
+
+	invalid, err := set.FindInvalidSignatures()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(invalid))
+	assert.Equal(t, 0, invalid[0].BatchIndex)
+	assert.Equal(t, false, invalid[0].HasValidatorIndex)
+
+	set.Signatures[1] = set.Signatures[1][1:]
+	_, err = set.FindInvalidSignatures()
+	assert.ErrorContains(t, "signature 1 must be", err)
+}
+
+func TestJoin_AlignsValidatorIndices(t *testing.T) {
+	withIndices := newSignatureBatch(t, 1, 1)
+	withoutIndices := newSignatureBatch(t, 2, 1)
+	withoutIndices.ValidatorIndices = nil
+
+	set := NewSet().Join(withoutIndices).Join(withIndices)
+	require.Equal(t, 3, len(set.ValidatorIndices))
+	assert.Equal(t, 0, len(set.ValidatorIndices[0]))
+	assert.Equal(t, 0, len(set.ValidatorIndices[1]))
+	assert.DeepEqual(t, withIndices.ValidatorIndices[0], set.ValidatorIndices[2])
+
+	copied := set.Copy()
+	assert.DeepEqual(t, set.ValidatorIndices, copied.ValidatorIndices)
+}
+
+func TestAggregateBatch_KeepsSetsAligned(t *testing.T) {
+	set := newSignatureBatch(t, 2, 2)
+	set.Join(set.Copy())
+
+	aggSet, err := set.AggregateBatch()
+	require.NoError(t, err)
+	require.Equal(t, 4, len(aggSet.Signatures))
+	require.Equal(t, 4, len(aggSet.Messages))
+	require.Equal(t, 4, len(aggSet.Descriptions))
+	require.Equal(t, 4, len(aggSet.ValidatorIndices))
+	// Sets with a common message are grouped together, in order of first appearance.
+	assert.Equal(t, aggSet.Messages[0], aggSet.Messages[1])
+	assert.Equal(t, aggSet.Messages[2], aggSet.Messages[3])
+	assert.Equal(t, testDescription, aggSet.Descriptions[1])
+
+	valid, err := aggSet.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, true, valid)
+}
+
+func TestRemoveDuplicates_KeepsValidatorIndices(t *testing.T) {
+	set := newSignatureBatch(t, 2, 1)
+	set.Join(set.Copy())
+
+	num, set, err := set.RemoveDuplicates()
+	require.NoError(t, err)
+	assert.Equal(t, 2, num)
+	require.Equal(t, 2, len(set.Signatures))
+	assert.DeepEqual(t, [][]primitives.ValidatorIndex{{0}, {1}}, set.ValidatorIndices)
+}
@@ -0,0 +1,122 @@
+package dilithium
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// VerifyMultipleSignatures verifies every signature in the provided batch against
+// its respective public keys and message. Signatures cannot be aggregated, so
+// each (signature, public key) pair is checked individually by a bounded pool of
+// workers. Verification stops as soon as an invalid signature is found.
+func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys [][]PublicKey) (bool, error) {
+	return VerifyMultipleSignaturesWithContext(context.Background(), sigs, msgs, pubKeys)
+}
+
+// VerifyMultipleSignaturesWithContext verifies the batch like VerifyMultipleSignatures,
+// and additionally aborts any outstanding verification once the provided context is
+// done. A batch which could not be fully verified before the context was done is
+// reported as invalid together with the context error.
+func VerifyMultipleSignaturesWithContext(ctx context.Context, sigs [][]byte, msgs [][32]byte, pubKeys [][]PublicKey) (bool, error) {
+	if len(sigs) == 0 || len(pubKeys) == 0 {
+		return false, nil
+	}
+
+	length := len(sigs)
+	if length != len(pubKeys) || length != len(msgs) {
+		return false, errors.Errorf("provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d,M %d",
+			length, len(pubKeys), len(msgs))
+	}
+	sigLen := scheme.SignatureLength()
+	total := 0
+	for i := range sigs {
+		if len(sigs[i]) != len(pubKeys[i])*sigLen {
+			return false, errors.Errorf("signature %d must be %d bytes for %d public keys, got %d",
+				i, len(pubKeys[i])*sigLen, len(pubKeys[i]), len(sigs[i]))
+		}
+		total += len(pubKeys[i])
+	}
+
+	limit := VerificationConcurrency()
+	if limit > total {
+		limit = total
+	}
+	// Avoid the goroutine overhead for a single worker.
+	if limit <= 1 {
+		for i := range sigs {
+			for pubKeyIndex, pubKey := range pubKeys[i] {
+				if err := ctx.Err(); err != nil {
+					return false, err
+				}
+				offset := pubKeyIndex * sigLen
+				sig := sigs[i][offset : offset+sigLen]
+				if ok, err := VerifySignature(sig, msgs[i], pubKey); !ok {
+					return ok, err
+				}
+			}
+		}
+		return true, nil
+	}
+
+	type job struct {
+		sigIndex    int
+		pubKeyIndex int
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan job)
+	var (
+		wg        sync.WaitGroup
+		failOnce  sync.Once
+		verifyErr error
+		invalid   bool
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			invalid = true
+			verifyErr = err
+			cancel()
+		})
+	}
+	wg.Add(limit)
+	for w := 0; w < limit; w++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				offset := j.pubKeyIndex * sigLen
+				sig := sigs[j.sigIndex][offset : offset+sigLen]
+				if ok, err := VerifySignature(sig, msgs[j.sigIndex], pubKeys[j.sigIndex][j.pubKeyIndex]); !ok {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range sigs {
+		for pubKeyIndex := range pubKeys[i] {
+			select {
+			case jobs <- job{sigIndex: i, pubKeyIndex: pubKeyIndex}:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if invalid {
+		return false, verifyErr
+	}
+	// The parent context may have been cancelled before every signature was checked.
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
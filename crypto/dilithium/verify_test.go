@@ -0,0 +1,111 @@
+package dilithium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func generateSignatureBatch(t testing.TB, numSigs, keysPerSig int) ([][]byte, [][32]byte, [][]PublicKey) {
+	sigs := make([][]byte, numSigs)
+	msgs := make([][32]byte, numSigs)
+	pubKeys := make([][]PublicKey, numSigs)
+	for i := 0; i < numSigs; i++ {
+		msgs[i] = [32]byte{'s', 'i', 'g', 'n', 'e', 'd', byte(i)}
+		var signatures []Signature
+		for j := 0; j < keysPerSig; j++ {
+			sk, err := RandKey()
+			require.NoError(t, err)
+			signatures = append(signatures, sk.Sign(msgs[i][:]))
+			pubKeys[i] = append(pubKeys[i], sk.PublicKey())
+		}
+		sigs[i] = UnaggregatedSignatures(signatures)
+	}
+	return sigs, msgs, pubKeys
+}
+
+func TestVerifyMultipleSignatures(t *testing.T) {
+	sigs, msgs, pubKeys := generateSignatureBatch(t, 4, 3)
+	for _, limit := range []int{1, 2, 16} {
+		SetVerificationConcurrency(limit)
+		valid, err := VerifyMultipleSignatures(sigs, msgs, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t, true, valid, "limit %d", limit)
+	}
+	SetVerificationConcurrency(0)
+}
+
+func TestVerifyMultipleSignatures_InvalidSignature(t *testing.T) {
+	sigs, msgs, pubKeys := generateSignatureBatch(t, 4, 3)
+	// Swap the messages of two entries so their signatures no longer match.
+	msgs[1], msgs[3] = msgs[3], msgs[1]
+	for _, limit := range []int{1, 2, 16} {
+		SetVerificationConcurrency(limit)
+		valid, err := VerifyMultipleSignatures(sigs, msgs, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t, false, valid, "limit %d", limit)
+	}
+	SetVerificationConcurrency(0)
+}
+
+func TestVerifyMultipleSignatures_InvalidLengths(t *testing.T) {
+	sigs, msgs, pubKeys := generateSignatureBatch(t, 2, 2)
+
+	_, err := VerifyMultipleSignatures(sigs, msgs[:1], pubKeys)
+	assert.ErrorContains(t, "differing lengths", err)
+
+	sigs[1] = sigs[1][:len(sigs[1])-1]
+	_, err = VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	assert.ErrorContains(t, "signature 1 must be", err)
+
+	valid, err := VerifyMultipleSignatures(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, false, valid)
+}
+
+func TestVerifyMultipleSignaturesWithContext_Cancelled(t *testing.T) {
+	sigs, msgs, pubKeys := generateSignatureBatch(t, 2, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, limit := range []int{1, 4} {
+		SetVerificationConcurrency(limit)
+		valid, err := VerifyMultipleSignaturesWithContext(ctx, sigs, msgs, pubKeys)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, false, valid)
+	}
+	SetVerificationConcurrency(0)
+}
+
+func TestVerificationConcurrency(t *testing.T) {
+	SetVerificationConcurrency(3)
+	assert.Equal(t, 3, VerificationConcurrency())
+	SetVerificationConcurrency(-1)
+	assert.Equal(t, true, VerificationConcurrency() > 0)
+	SetVerificationConcurrency(0)
+}
+
+// 128 attestations with 16 signers each, similar in size to a full block.
+func benchmarkVerifyMultipleSignatures(b *testing.B, limit int) {
+	sigs, msgs, pubKeys := generateSignatureBatch(b, 128, 16)
+	SetVerificationConcurrency(limit)
+	defer SetVerificationConcurrency(0)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		valid, err := VerifyMultipleSignatures(sigs, msgs, pubKeys)
+		if err != nil || !valid {
+			b.Fatal("could not verify signatures")
+		}
+	}
+}
+
+func BenchmarkVerifyMultipleSignatures_Serial(b *testing.B) {
+	benchmarkVerifyMultipleSignatures(b, 1)
+}
+
+func BenchmarkVerifyMultipleSignatures_Parallel(b *testing.B) {
+	benchmarkVerifyMultipleSignatures(b, 0)
+}
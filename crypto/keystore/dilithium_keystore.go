@@ -0,0 +1,521 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/sha256-simd"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// DilithiumKeystoreVersion is the version of the keystores produced by EncryptDilithiumKeystore.
+	DilithiumKeystoreVersion = 2
+
+	// KDFArgon2id derives the decryption key with Argon2id.
+	KDFArgon2id = "argon2id"
+	// KDFScrypt derives the decryption key with scrypt.
+	KDFScrypt = "scrypt"
+	// KDFPBKDF2 derives the decryption key with PBKDF2 over HMAC-SHA256.
+	KDFPBKDF2 = "pbkdf2"
+	// KDFShake256 is the key derivation used by version 1 keystores, which hashes the
+	// password and salt with SHAKE256. It is only supported for decryption.
+	KDFShake256 = "custom"
+
+	cipherAES128CTR = "aes-128-ctr"
+	checksumSHA256  = "sha256"
+	pbkdf2PRF       = "hmac-sha256"
+	kdfDKLen        = 32
+	kdfSaltLen      = 32
+)
+
+// Default key derivation parameters of the new keystores. The Argon2id parameters follow
+// the second recommended option of RFC 9106, the others match EIP-2335.
+const (
+	DefaultArgon2idTime        = 3
+	DefaultArgon2idMemory      = 64 * 1024 // KiB
+	DefaultArgon2idParallelism = 4
+	DefaultScryptN             = 1 << 18
+	DefaultScryptR             = 8
+	DefaultScryptP             = 1
+	DefaultPBKDF2Iterations    = 1 << 18
+)
+
+// maxArgon2idMemory bounds the memory requested by the parameters of a keystore being
+// decrypted, so a crafted keystore cannot exhaust the memory of the host.
+const maxArgon2idMemory = 4 * 1024 * 1024 // KiB
+
+// maxArgon2idTime bounds the number of passes over the memory of a keystore being decrypted,
+// so a crafted keystore cannot keep the host busy with a huge amount of work.
+const maxArgon2idTime = 32
+
+// maxPbkdf2Iterations bounds the iteration count of a keystore being decrypted, 64 times the
+// default one.
+const maxPbkdf2Iterations = 1 << 24
+
+// maxScryptCost bounds the n*r*p product of a keystore being decrypted. Scrypt uses 128*n*r
+// bytes of memory and 128*n*r*p bytes of work, which are both kept within maxArgon2idMemory.
+const maxScryptCost = maxArgon2idMemory * 1024 / 128
+
+// maxKDFDKLen bounds the derived key length of a keystore being decrypted, only the first
+// kdfDKLen bytes of which are used.
+const maxKDFDKLen = 64
+
+// KDFParams defines the key derivation function used to encrypt a keystore and its cost
+// parameters. Only the parameters of the selected function are used.
+type KDFParams struct {
+	Function string
+
+	Argon2idTime        uint32
+	Argon2idMemory      uint32 // KiB
+	Argon2idParallelism uint8
+
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	PBKDF2Iterations int
+}
+
+// DefaultKDFParams returns the default parameters of the given key derivation function.
+func DefaultKDFParams(function string) (*KDFParams, error) {
+	switch function {
+	case KDFArgon2id:
+		return &KDFParams{
+			Function:            KDFArgon2id,
+			Argon2idTime:        DefaultArgon2idTime,
+			Argon2idMemory:      DefaultArgon2idMemory,
+			Argon2idParallelism: DefaultArgon2idParallelism,
+		}, nil
+	case KDFScrypt:
+		return &KDFParams{
+			Function: KDFScrypt,
+			ScryptN:  DefaultScryptN,
+			ScryptR:  DefaultScryptR,
+			ScryptP:  DefaultScryptP,
+		}, nil
+	case KDFPBKDF2:
+		return &KDFParams{
+			Function:         KDFPBKDF2,
+			PBKDF2Iterations: DefaultPBKDF2Iterations,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported KDF for encryption: %s", function)
+	}
+}
+
+// KeystoreModule is one of the kdf, checksum or cipher modules of a keystore.
+type KeystoreModule struct {
+	Function string                 `json:"function"`
+	Params   map[string]interface{} `json:"params"`
+	Message  string                 `json:"message"`
+}
+
+// KeystoreCrypto holds the encrypted secret of a keystore along with everything needed
+// to decrypt it, except for the password.
+type KeystoreCrypto struct {
+	KDF      *KeystoreModule `json:"kdf"`
+	Checksum *KeystoreModule `json:"checksum"`
+	Cipher   *KeystoreModule `json:"cipher"`
+}
+
+// DilithiumKeystore is an EIP-2335 style keystore for a Dilithium seed.
+type DilithiumKeystore struct {
+	Crypto      *KeystoreCrypto `json:"crypto"`
+	Description string          `json:"description"`
+	PubKey      string          `json:"pubkey"`
+	Path        string          `json:"path"`
+	UUID        string          `json:"uuid"`
+	Version     uint            `json:"version"`
+}
+
+// EncryptDilithiumKeystore encrypts a Dilithium seed into a new keystore, deriving the
+// decryption key from the password with the given parameters.
+func EncryptDilithiumKeystore(seed []byte, password, path string, params *KDFParams) (*DilithiumKeystore, error) {
+	secretKey, err := dilithium.SecretKeyFromBytes(seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize secret key from seed")
+	}
+	c, err := EncryptCrypto(seed, password, params)
+	if err != nil {
+		return nil, err
+	}
+	return &DilithiumKeystore{
+		Crypto:  c,
+		PubKey:  hex.EncodeToString(secretKey.PublicKey().Marshal()),
+		Path:    path,
+		UUID:    uuid.NewRandom().String(),
+		Version: DilithiumKeystoreVersion,
+	}, nil
+}
+
+// ParseDilithiumKeystore parses a version 1 or version 2 keystore from its JSON encoding.
+func ParseDilithiumKeystore(data []byte) (*DilithiumKeystore, error) {
+	k := &DilithiumKeystore{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, errors.Wrap(err, "could not parse keystore")
+	}
+	if k.Version > DilithiumKeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", k.Version)
+	}
+	if k.Crypto == nil {
+		return nil, errors.New("keystore has no crypto field")
+	}
+	return k, nil
+}
+
+// ReadDilithiumKeystore reads and parses the keystore at the given path.
+func ReadDilithiumKeystore(filename string) (*DilithiumKeystore, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- ReadFile is safe
+	if err != nil {
+		return nil, err
+	}
+	return ParseDilithiumKeystore(data)
+}
+
+// Decrypt returns the seed stored in the keystore.
+func (k *DilithiumKeystore) Decrypt(password string) ([]byte, error) {
+	if k.Crypto == nil {
+		return nil, errors.New("keystore has no crypto field")
+	}
+	return k.Crypto.Decrypt(password)
+}
+
+// Save writes the keystore to the given path.
+func (k *DilithiumKeystore) Save(filename string) error {
+	b, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(filename, b)
+}
+
+// ReencryptDilithiumKeystore decrypts the keystore with the old password and encrypts the
+// seed again with the new password and key derivation parameters. The metadata of the
+// keystore is kept, and the result is always a keystore of the latest version.
+func ReencryptDilithiumKeystore(k *DilithiumKeystore, oldPassword, newPassword string, params *KDFParams) (*DilithiumKeystore, error) {
+	seed, err := k.Decrypt(oldPassword)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := dilithium.SecretKeyFromBytes(seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize secret key from seed")
+	}
+	pubKey := hex.EncodeToString(secretKey.PublicKey().Marshal())
+	if k.PubKey != "" && !strings.EqualFold(strings.TrimPrefix(k.PubKey, "0x"), pubKey) {
+		return nil, fmt.Errorf("keystore public key %s does not match the decrypted seed", k.PubKey)
+	}
+	c, err := EncryptCrypto(seed, newPassword, params)
+	if err != nil {
+		return nil, err
+	}
+	id := k.UUID
+	if id == "" {
+		id = uuid.NewRandom().String()
+	}
+	return &DilithiumKeystore{
+		Crypto:      c,
+		Description: k.Description,
+		PubKey:      pubKey,
+		Path:        k.Path,
+		UUID:        id,
+		Version:     DilithiumKeystoreVersion,
+	}, nil
+}
+
+// EncryptCrypto encrypts the secret with the password, deriving the decryption key with
+// the given parameters.
+func EncryptCrypto(secret []byte, password string, params *KDFParams) (*KeystoreCrypto, error) {
+	if params == nil {
+		return nil, errors.New("nil KDF params")
+	}
+	salt := make([]byte, kdfSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "reading from crypto/rand failed")
+	}
+	kdf, err := newKDFModule(params, salt)
+	if err != nil {
+		return nil, err
+	}
+	decryptionKey, err := deriveDecryptionKey(kdf, password)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, errors.Wrap(err, "reading from crypto/rand failed")
+	}
+	cipherText, err := aesCTRXOR(decryptionKey[:16], secret, iv)
+	if err != nil {
+		return nil, err
+	}
+	checksum := keystoreChecksum(decryptionKey, cipherText)
+
+	return &KeystoreCrypto{
+		KDF: kdf,
+		Checksum: &KeystoreModule{
+			Function: checksumSHA256,
+			Params:   map[string]interface{}{},
+			Message:  hex.EncodeToString(checksum),
+		},
+		Cipher: &KeystoreModule{
+			Function: cipherAES128CTR,
+			Params:   map[string]interface{}{"iv": hex.EncodeToString(iv)},
+			Message:  hex.EncodeToString(cipherText),
+		},
+	}, nil
+}
+
+// Decrypt returns the secret protected by the password. ErrDecrypt is returned when the
+// checksum does not match, which usually means the password is incorrect.
+func (c *KeystoreCrypto) Decrypt(password string) ([]byte, error) {
+	if c.KDF == nil {
+		return nil, errors.New("kdf module cannot be nil")
+	}
+	if c.Checksum == nil {
+		return nil, errors.New("checksum module cannot be nil")
+	}
+	if c.Cipher == nil {
+		return nil, errors.New("cipher module cannot be nil")
+	}
+	if c.Checksum.Function != checksumSHA256 {
+		return nil, fmt.Errorf("unsupported checksum: %s", c.Checksum.Function)
+	}
+	if c.Cipher.Function != cipherAES128CTR {
+		return nil, fmt.Errorf("cipher not supported: %s", c.Cipher.Function)
+	}
+
+	decryptionKey, err := deriveDecryptionKey(c.KDF, password)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := decodeHex(c.Cipher.Message)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cipher message")
+	}
+	checksum, err := decodeHex(c.Checksum.Message)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid checksum message")
+	}
+	if !bytes.Equal(keystoreChecksum(decryptionKey, cipherText), checksum) {
+		return nil, ErrDecrypt
+	}
+	iv, err := hexParam(c.Cipher.Params, "iv")
+	if err != nil {
+		return nil, err
+	}
+	return aesCTRXOR(decryptionKey[:16], cipherText, iv)
+}
+
+// DecryptCryptoFields decrypts the crypto field of a keystore, as found in the JSON
+// encoding of version 1 and version 2 keystores.
+func DecryptCryptoFields(fields map[string]interface{}, password string) ([]byte, error) {
+	if fields == nil {
+		return nil, errors.New("crypto fields cannot be nil")
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	c := &KeystoreCrypto{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrap(err, "could not parse crypto fields")
+	}
+	return c.Decrypt(password)
+}
+
+func newKDFModule(params *KDFParams, salt []byte) (*KeystoreModule, error) {
+	m := &KeystoreModule{
+		Function: params.Function,
+		Params: map[string]interface{}{
+			"dklen": kdfDKLen,
+			"salt":  hex.EncodeToString(salt),
+		},
+	}
+	switch params.Function {
+	case KDFArgon2id:
+		if params.Argon2idTime == 0 || params.Argon2idMemory == 0 || params.Argon2idParallelism == 0 {
+			return nil, errors.New("argon2id time, memory and parallelism must be positive")
+		}
+		m.Params["t"] = params.Argon2idTime
+		m.Params["m"] = params.Argon2idMemory
+		m.Params["p"] = params.Argon2idParallelism
+	case KDFScrypt:
+		if params.ScryptN <= 1 || params.ScryptR <= 0 || params.ScryptP <= 0 {
+			return nil, errors.New("scrypt n must be greater than 1, r and p must be positive")
+		}
+		m.Params["n"] = params.ScryptN
+		m.Params["r"] = params.ScryptR
+		m.Params["p"] = params.ScryptP
+	case KDFPBKDF2:
+		if params.PBKDF2Iterations <= 0 {
+			return nil, errors.New("pbkdf2 iterations must be positive")
+		}
+		m.Params["c"] = params.PBKDF2Iterations
+		m.Params["prf"] = pbkdf2PRF
+	default:
+		return nil, fmt.Errorf("unsupported KDF for encryption: %s", params.Function)
+	}
+	// Round trip the parameters through JSON, so the module is identical to a decoded one.
+	b, err := json.Marshal(m.Params)
+	if err != nil {
+		return nil, err
+	}
+	m.Params = map[string]interface{}{}
+	if err := json.Unmarshal(b, &m.Params); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func deriveDecryptionKey(kdf *KeystoreModule, password string) ([]byte, error) {
+	salt, err := hexParam(kdf.Params, "salt")
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	switch kdf.Function {
+	case KDFShake256:
+		key, err = shake256Key(password, salt)
+	case KDFArgon2id:
+		var t, m, p, dkLen int
+		if t, err = intParam(kdf.Params, "t"); err != nil {
+			return nil, err
+		}
+		if m, err = intParam(kdf.Params, "m"); err != nil {
+			return nil, err
+		}
+		if p, err = intParam(kdf.Params, "p"); err != nil {
+			return nil, err
+		}
+		if dkLen, err = dkLenParam(kdf.Params); err != nil {
+			return nil, err
+		}
+		if t <= 0 || t > maxArgon2idTime || m <= 0 || m > maxArgon2idMemory || p <= 0 || p > 255 {
+			return nil, fmt.Errorf("invalid argon2id params t=%d m=%d p=%d", t, m, p)
+		}
+		key = argon2.IDKey([]byte(password), salt, uint32(t), uint32(m), uint8(p), uint32(dkLen))
+	case KDFScrypt:
+		var n, r, p, dkLen int
+		if n, err = intParam(kdf.Params, "n"); err != nil {
+			return nil, err
+		}
+		if r, err = intParam(kdf.Params, "r"); err != nil {
+			return nil, err
+		}
+		if p, err = intParam(kdf.Params, "p"); err != nil {
+			return nil, err
+		}
+		if dkLen, err = dkLenParam(kdf.Params); err != nil {
+			return nil, err
+		}
+		// Scrypt requires n to be a power of two greater than 1.
+		if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 || n > maxScryptCost || r > maxScryptCost/n || p > maxScryptCost/(n*r) {
+			return nil, fmt.Errorf("invalid scrypt params n=%d r=%d p=%d", n, r, p)
+		}
+		key, err = scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case KDFPBKDF2:
+		var c, dkLen int
+		if c, err = intParam(kdf.Params, "c"); err != nil {
+			return nil, err
+		}
+		if dkLen, err = dkLenParam(kdf.Params); err != nil {
+			return nil, err
+		}
+		prf, ok := kdf.Params["prf"].(string)
+		if !ok || prf != pbkdf2PRF {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF: %v", kdf.Params["prf"])
+		}
+		if c <= 0 || c > maxPbkdf2Iterations {
+			return nil, fmt.Errorf("invalid pbkdf2 iteration count %d", c)
+		}
+		key = pbkdf2.Key([]byte(password), salt, c, dkLen, sha256.New)
+	default:
+		return nil, fmt.Errorf("unsupported KDF: %s", kdf.Function)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < kdfDKLen {
+		return nil, fmt.Errorf("decryption key must be at least %d bytes, got %d", kdfDKLen, len(key))
+	}
+	return key, nil
+}
+
+// dkLenParam returns the derived key length of the KDF parameters, which must be long enough
+// for the decryption key and is bounded by maxKDFDKLen.
+func dkLenParam(params map[string]interface{}) (int, error) {
+	dkLen, err := intParam(params, "dklen")
+	if err != nil {
+		return 0, err
+	}
+	if dkLen < kdfDKLen || dkLen > maxKDFDKLen {
+		return 0, fmt.Errorf("invalid dklen %d, must be between %d and %d", dkLen, kdfDKLen, maxKDFDKLen)
+	}
+	return dkLen, nil
+}
+
+// shake256Key derives the decryption key of version 1 keystores.
+func shake256Key(password string, salt []byte) ([]byte, error) {
+	h := sha3.NewShake256()
+	if _, err := h.Write([]byte(password)); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(salt); err != nil {
+		return nil, err
+	}
+	key := make([]byte, kdfDKLen)
+	if _, err := h.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func keystoreChecksum(decryptionKey, cipherText []byte) []byte {
+	h := sha256.New()
+	h.Write(decryptionKey[16:32])
+	h.Write(cipherText)
+	return h.Sum(nil)
+}
+
+func hexParam(params map[string]interface{}, name string) ([]byte, error) {
+	s, ok := params[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid %s param", name)
+	}
+	b, err := decodeHex(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s param", name)
+	}
+	return b, nil
+}
+
+func intParam(params map[string]interface{}, name string) (int, error) {
+	switch v := params[name].(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("missing or invalid %s param", name)
+	}
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
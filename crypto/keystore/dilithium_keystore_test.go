@@ -0,0 +1,186 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	keystorev1 "github.com/theQRL/go-zond-wallet-encryptor-keystore"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+// lightKDFParams returns cheap parameters, so the tests do not spend seconds deriving keys.
+func lightKDFParams(function string) *KDFParams {
+	switch function {
+	case KDFArgon2id:
+		return &KDFParams{Function: KDFArgon2id, Argon2idTime: 1, Argon2idMemory: 64, Argon2idParallelism: 1}
+	case KDFScrypt:
+		return &KDFParams{Function: KDFScrypt, ScryptN: LightScryptN, ScryptR: scryptR, ScryptP: 1}
+	default:
+		return &KDFParams{Function: KDFPBKDF2, PBKDF2Iterations: 16}
+	}
+}
+
+func randomSeed(t *testing.T) ([]byte, dilithium.DilithiumKey) {
+	key, err := dilithium.RandKey()
+	require.NoError(t, err)
+	return key.Marshal(), key
+}
+
+func TestDilithiumKeystore_EncryptDecrypt(t *testing.T) {
+	for _, function := range []string{KDFArgon2id, KDFScrypt, KDFPBKDF2} {
+		t.Run(function, func(t *testing.T) {
+			seed, key := randomSeed(t)
+			ks, err := EncryptDilithiumKeystore(seed, "password", "m/12381/3600/0/0/0", lightKDFParams(function))
+			require.NoError(t, err)
+			assert.Equal(t, uint(DilithiumKeystoreVersion), ks.Version)
+			assert.Equal(t, function, ks.Crypto.KDF.Function)
+			assert.Equal(t, hex.EncodeToString(key.PublicKey().Marshal()), ks.PubKey)
+			assert.Equal(t, "m/12381/3600/0/0/0", ks.Path)
+
+			enc, err := json.Marshal(ks)
+			require.NoError(t, err)
+			parsed, err := ParseDilithiumKeystore(enc)
+			require.NoError(t, err)
+			decrypted, err := parsed.Decrypt("password")
+			require.NoError(t, err)
+			assert.DeepEqual(t, seed, decrypted)
+
+			_, err = parsed.Decrypt("wrong")
+			require.ErrorIs(t, err, ErrDecrypt)
+		})
+	}
+}
+
+func TestDilithiumKeystore_DecryptsVersion1(t *testing.T) {
+	seed, _ := randomSeed(t)
+	fields, err := keystorev1.New().Encrypt(seed, "password")
+	require.NoError(t, err)
+
+	decrypted, err := DecryptCryptoFields(fields, "password")
+	require.NoError(t, err)
+	assert.DeepEqual(t, seed, decrypted)
+
+	_, err = DecryptCryptoFields(fields, "wrong")
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestDecryptCryptoFields_Version2(t *testing.T) {
+	seed, _ := randomSeed(t)
+	c, err := EncryptCrypto(seed, "password", lightKDFParams(KDFScrypt))
+	require.NoError(t, err)
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	fields := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(b, &fields))
+
+	decrypted, err := DecryptCryptoFields(fields, "password")
+	require.NoError(t, err)
+	assert.DeepEqual(t, seed, decrypted)
+}
+
+func TestDilithiumKeystore_Reencrypt(t *testing.T) {
+	seed, _ := randomSeed(t)
+	ks, err := EncryptDilithiumKeystore(seed, "old", "m/12381/3600/1/0/0", lightKDFParams(KDFPBKDF2))
+	require.NoError(t, err)
+	ks.Description = "validator 1"
+
+	_, err = ReencryptDilithiumKeystore(ks, "wrong", "new", lightKDFParams(KDFArgon2id))
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	reencrypted, err := ReencryptDilithiumKeystore(ks, "old", "new", lightKDFParams(KDFArgon2id))
+	require.NoError(t, err)
+	assert.Equal(t, KDFArgon2id, reencrypted.Crypto.KDF.Function)
+	assert.Equal(t, ks.UUID, reencrypted.UUID)
+	assert.Equal(t, ks.PubKey, reencrypted.PubKey)
+	assert.Equal(t, ks.Path, reencrypted.Path)
+	assert.Equal(t, ks.Description, reencrypted.Description)
+
+	fileName := filepath.Join(t.TempDir(), "keys", "keystore.json")
+	require.NoError(t, reencrypted.Save(fileName))
+	saved, err := ReadDilithiumKeystore(fileName)
+	require.NoError(t, err)
+	decrypted, err := saved.Decrypt("new")
+	require.NoError(t, err)
+	assert.DeepEqual(t, seed, decrypted)
+}
+
+func TestDilithiumKeystore_ReencryptPubKeyMismatch(t *testing.T) {
+	seed, _ := randomSeed(t)
+	ks, err := EncryptDilithiumKeystore(seed, "password", "", lightKDFParams(KDFPBKDF2))
+	require.NoError(t, err)
+	_, other := randomSeed(t)
+	ks.PubKey = hex.EncodeToString(other.PublicKey().Marshal())
+
+	_, err = ReencryptDilithiumKeystore(ks, "password", "password", lightKDFParams(KDFPBKDF2))
+	assert.ErrorContains(t, "does not match the decrypted seed", err)
+}
+
+func TestDilithiumKeystore_UnsupportedVersion(t *testing.T) {
+	_, err := ParseDilithiumKeystore([]byte(`{"version": 3, "crypto": {}}`))
+	assert.ErrorContains(t, "unsupported keystore version 3", err)
+}
+
+func TestDilithiumKeystore_RejectsExcessiveArgon2idMemory(t *testing.T) {
+	seed, _ := randomSeed(t)
+	c, err := EncryptCrypto(seed, "password", lightKDFParams(KDFArgon2id))
+	require.NoError(t, err)
+	c.KDF.Params["m"] = float64(maxArgon2idMemory + 1)
+	_, err = c.Decrypt("password")
+	assert.ErrorContains(t, "invalid argon2id params", err)
+}
+
+func TestDilithiumKeystore_RejectsExcessiveArgon2idTime(t *testing.T) {
+	seed, _ := randomSeed(t)
+	c, err := EncryptCrypto(seed, "password", lightKDFParams(KDFArgon2id))
+	require.NoError(t, err)
+	c.KDF.Params["t"] = float64(maxArgon2idTime + 1)
+	_, err = c.Decrypt("password")
+	assert.ErrorContains(t, "invalid argon2id params", err)
+}
+
+func TestDilithiumKeystore_RejectsExcessivePbkdf2Iterations(t *testing.T) {
+	seed, _ := randomSeed(t)
+	c, err := EncryptCrypto(seed, "password", lightKDFParams(KDFPBKDF2))
+	require.NoError(t, err)
+	c.KDF.Params["c"] = float64(maxPbkdf2Iterations + 1)
+	_, err = c.Decrypt("password")
+	assert.ErrorContains(t, "invalid pbkdf2 iteration count", err)
+}
+
+func TestDilithiumKeystore_RejectsExcessiveScryptParams(t *testing.T) {
+	seed, _ := randomSeed(t)
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		err    string
+	}{
+		{name: "n not a power of two", params: map[string]interface{}{"n": float64(LightScryptN + 1)}, err: "invalid scrypt params"},
+		{name: "memory", params: map[string]interface{}{"n": float64(1 << 30)}, err: "invalid scrypt params"},
+		{name: "cost", params: map[string]interface{}{"p": float64(maxScryptCost)}, err: "invalid scrypt params"},
+		{name: "dklen", params: map[string]interface{}{"dklen": float64(1 << 30)}, err: "invalid dklen"},
+		{name: "short dklen", params: map[string]interface{}{"dklen": float64(16)}, err: "invalid dklen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := EncryptCrypto(seed, "password", lightKDFParams(KDFScrypt))
+			require.NoError(t, err)
+			for k, v := range tt.params {
+				c.KDF.Params[k] = v
+			}
+			_, err = c.Decrypt("password")
+			assert.ErrorContains(t, tt.err, err)
+		})
+	}
+}
+
+func TestDefaultKDFParams(t *testing.T) {
+	params, err := DefaultKDFParams(KDFArgon2id)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(DefaultArgon2idMemory), params.Argon2idMemory)
+	_, err = DefaultKDFParams(KDFShake256)
+	assert.ErrorContains(t, "unsupported KDF for encryption", err)
+}
@@ -7,14 +7,14 @@ import (
 	"testing"
 
 	"github.com/pborman/uuid"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	"github.com/theQRL/qrysm/v4/testing/require"
 )
 
 func TestMarshalAndUnmarshal(t *testing.T) {
 	testID := uuid.NewRandom()
-	blsKey, err := bls.RandKey()
+	blsKey, err := dilithium.RandKey()
 	require.NoError(t, err)
 
 	key := &Key{
@@ -45,9 +45,8 @@ func TestStoreRandomKey(t *testing.T) {
 }
 
 func TestNewKeyFromBLS(t *testing.T) {
-	b := []byte("hi")
-	b32 := bytesutil.ToBytes32(b)
-	blskey, err := bls.SecretKeyFromBytes(b32[:])
+	seed := bytesutil.PadTo([]byte("hi"), dilithium.SeedLength())
+	blskey, err := dilithium.SecretKeyFromBytes(seed)
 	require.NoError(t, err)
 	key, err := NewKeyFromDilithium(blskey)
 	require.NoError(t, err)
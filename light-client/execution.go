@@ -0,0 +1,43 @@
+package lightclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/container/trie"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+)
+
+// ExecutionHeader holds the fields of the execution payload of a verified beacon block.
+type ExecutionHeader struct {
+	BlockNumber uint64
+	BlockHash   [32]byte
+	StateRoot   [32]byte
+}
+
+// executionHeader downloads the execution payload header of the block of a verified header and returns its fields.
+// The payload header is only trusted once its Merkle branch matches the body root of the header.
+func executionHeader(ctx context.Context, node BeaconNode, header *zondpbv1.BeaconBlockHeader) (*ExecutionHeader, error) {
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute header root")
+	}
+	payload, branch, err := node.GetLightClientExecutionHeader(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	payloadRoot, err := payload.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute execution payload header root")
+	}
+	if !trie.VerifyMerkleProof(header.BodyRoot, payloadRoot[:], zondpbv2.ExecutionPayloadIndex, branch) {
+		return nil, errors.Wrapf(errInvalidMerkleBranch, "execution branch of block %#x", root)
+	}
+	return &ExecutionHeader{
+		BlockNumber: payload.BlockNumber(),
+		BlockHash:   bytesutil.ToBytes32(payload.BlockHash()),
+		StateRoot:   bytesutil.ToBytes32(payload.StateRoot()),
+	}, nil
+}
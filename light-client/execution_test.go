@@ -0,0 +1,56 @@
+package lightclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+)
+
+type mockExecutionNode struct {
+	BeaconNode
+	execution interfaces.ExecutionData
+	branch    [][]byte
+}
+
+func (n *mockExecutionNode) GetLightClientExecutionHeader(context.Context, [32]byte) (interfaces.ExecutionData, [][]byte, error) {
+	return n.execution, n.branch, nil
+}
+
+func TestExecutionHeader(t *testing.T) {
+	ctx := context.Background()
+	b := util.NewBeaconBlockCapella()
+	b.Block.Body.ExecutionPayload.BlockNumber = 7
+	b.Block.Body.ExecutionPayload.StateRoot[0] = 1
+	blk, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	body := blk.Block().Body()
+	bodyRoot, err := body.HashTreeRoot()
+	require.NoError(t, err)
+	header := &zondpbv1.BeaconBlockHeader{
+		Slot:       blk.Block().Slot(),
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   bodyRoot[:],
+	}
+	payload, err := body.Execution()
+	require.NoError(t, err)
+	branch, err := blocks.PayloadProof(body)
+	require.NoError(t, err)
+
+	execution, err := executionHeader(ctx, &mockExecutionNode{execution: payload, branch: branch}, header)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), execution.BlockNumber)
+	require.Equal(t, byte(1), execution.StateRoot[0])
+
+	// A payload which is not the payload of the block does not match the branch.
+	b.Block.Body.ExecutionPayload.StateRoot[0] = 2
+	forged, err := blocks.WrappedExecutionPayloadCapella(b.Block.Body.ExecutionPayload, 0)
+	require.NoError(t, err)
+	_, err = executionHeader(ctx, &mockExecutionNode{execution: forged, branch: branch}, header)
+	require.ErrorIs(t, err, errInvalidMerkleBranch)
+}
@@ -0,0 +1,100 @@
+package lightclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/common/hexutil"
+	http2 "github.com/theQRL/qrysm/v4/network/http"
+)
+
+const (
+	finalizedHeaderPath  = "/lightclient/v1/headers/finalized"
+	optimisticHeaderPath = "/lightclient/v1/headers/optimistic"
+)
+
+// HeaderResponse is the local API representation of a VerifiedHeader.
+type HeaderResponse struct {
+	Data *Header `json:"data"`
+}
+
+// Header is a verified beacon block header, along with the execution payload fields of its block.
+type Header struct {
+	Slot                 string `json:"slot"`
+	ProposerIndex        string `json:"proposer_index"`
+	Root                 string `json:"root"`
+	ParentRoot           string `json:"parent_root"`
+	StateRoot            string `json:"state_root"`
+	BodyRoot             string `json:"body_root"`
+	ExecutionBlockNumber string `json:"execution_block_number"`
+	ExecutionBlockHash   string `json:"execution_block_hash"`
+	ExecutionStateRoot   string `json:"execution_state_root"`
+}
+
+// server exposes the headers verified by the light client over a small local HTTP API.
+type server struct {
+	s   *Service
+	srv *http.Server
+}
+
+func newServer(s *Service, host string, port int) *server {
+	srv := &server{s: s}
+	mux := http.NewServeMux()
+	mux.HandleFunc(finalizedHeaderPath, srv.handler(s.Finalized))
+	mux.HandleFunc(optimisticHeaderPath, srv.handler(s.Optimistic))
+	srv.srv = &http.Server{
+		Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second,
+	}
+	return srv
+}
+
+func (srv *server) start() {
+	go func() {
+		log.WithField("address", srv.srv.Addr).Info("Starting light client API")
+		if err := srv.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("Light client API failed")
+		}
+	}()
+}
+
+func (srv *server) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return srv.srv.Shutdown(ctx)
+}
+
+func (*server) handler(header func() *VerifiedHeader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http2.HandleError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h := header()
+		if h == nil {
+			http2.HandleError(w, "Light client is not bootstrapped yet", http.StatusServiceUnavailable)
+			return
+		}
+		http2.WriteJson(w, &HeaderResponse{Data: headerToJson(h)})
+	}
+}
+
+func headerToJson(h *VerifiedHeader) *Header {
+	return &Header{
+		Slot:                 fmt.Sprintf("%d", h.Header.Slot),
+		ProposerIndex:        fmt.Sprintf("%d", h.Header.ProposerIndex),
+		Root:                 hexutil.Encode(h.Root[:]),
+		ParentRoot:           hexutil.Encode(h.Header.ParentRoot),
+		StateRoot:            hexutil.Encode(h.Header.StateRoot),
+		BodyRoot:             hexutil.Encode(h.Header.BodyRoot),
+		ExecutionBlockNumber: fmt.Sprintf("%d", h.Execution.BlockNumber),
+		ExecutionBlockHash:   hexutil.Encode(h.Execution.BlockHash[:]),
+		ExecutionStateRoot:   hexutil.Encode(h.Execution.StateRoot[:]),
+	}
+}
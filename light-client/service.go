@@ -0,0 +1,279 @@
+package lightclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theQRL/qrysm/v4/api/client/beacon"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/runtime"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+// maxRequestUpdates is the maximum number of updates requested from the beacon node at once.
+const maxRequestUpdates = 128
+
+var _ runtime.Service = (*Service)(nil)
+
+// BeaconNode is the subset of the beacon node API used by the light client. The responses are not trusted, every
+// update is verified against the sync committee known to the store before it is applied.
+type BeaconNode interface {
+	GetGenesis(ctx context.Context) (*beacon.Genesis, error)
+	GetLightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*zondpbv2.LightClientBootstrap, error)
+	GetLightClientExecutionHeader(ctx context.Context, blockRoot [32]byte) (interfaces.ExecutionData, [][]byte, error)
+	GetLightClientUpdatesByRange(ctx context.Context, startPeriod, count uint64) ([]*zondpbv2.LightClientUpdate, error)
+	GetLightClientFinalityUpdate(ctx context.Context) (*zondpbv2.LightClientFinalityUpdate, error)
+	GetLightClientOptimisticUpdate(ctx context.Context) (*zondpbv2.LightClientOptimisticUpdate, error)
+}
+
+// Config for the light client service.
+type Config struct {
+	// BeaconNode serves the bootstrap and the updates of the light client.
+	BeaconNode BeaconNode
+	// TrustedBlockRoot is the root of the block the light client starts from, usually a recent finalized block
+	// obtained from a trusted source.
+	TrustedBlockRoot [32]byte
+	// GenesisValidatorsRoot, when set, is checked against the genesis validators root served by the beacon node.
+	GenesisValidatorsRoot *[32]byte
+	// HTTPHost and HTTPPort are the address of the local API of the light client. The API is disabled when
+	// HTTPPort is zero.
+	HTTPHost string
+	HTTPPort int
+}
+
+// VerifiedHeader is a beacon block header verified by the light client, along with the execution payload fields
+// of its block.
+type VerifiedHeader struct {
+	Header    *zondpbv1.BeaconBlockHeader
+	Root      [32]byte
+	Execution *ExecutionHeader
+}
+
+// Service follows the chain from a trusted block root by verifying the light client updates served by a beacon
+// node. It tracks the finalized and optimistic headers of the chain and the execution state roots of their blocks.
+type Service struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	cfg        *Config
+	server     *server
+	lock       sync.RWMutex
+	store      *Store
+	genesis    time.Time
+	finalized  *VerifiedHeader
+	optimistic *VerifiedHeader
+	err        error
+}
+
+// NewService initializes the light client service.
+func NewService(ctx context.Context, cfg *Config) (*Service, error) {
+	if cfg.BeaconNode == nil {
+		return nil, errors.New("light client requires a beacon node")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+	}
+	if cfg.HTTPPort != 0 {
+		s.server = newServer(s, cfg.HTTPHost, cfg.HTTPPort)
+	}
+	return s, nil
+}
+
+// Start bootstraps the light client from the trusted block root and follows the chain until the service is
+// stopped.
+func (s *Service) Start() {
+	if s.server != nil {
+		s.server.start()
+	}
+	go func() {
+		if err := s.run(); err != nil && !errors.Is(err, context.Canceled) {
+			s.lock.Lock()
+			s.err = err
+			s.lock.Unlock()
+			log.WithError(err).Error("Light client stopped")
+		}
+	}()
+}
+
+// Stop the light client service.
+func (s *Service) Stop() error {
+	s.cancel()
+	if s.server != nil {
+		return s.server.stop()
+	}
+	return nil
+}
+
+// Status of the light client service, which reports the error that stopped the light client, if any.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.err
+}
+
+// Finalized returns the latest verified finalized header, or nil if the light client is not bootstrapped yet.
+func (s *Service) Finalized() *VerifiedHeader {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.finalized
+}
+
+// Optimistic returns the latest verified optimistic header, or nil if the light client is not bootstrapped yet.
+func (s *Service) Optimistic() *VerifiedHeader {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.optimistic
+}
+
+func (s *Service) run() error {
+	if err := s.bootstrap(); err != nil {
+		return err
+	}
+	ticker := slots.NewSlotTicker(s.genesis, params.BeaconConfig().SecondsPerSlot)
+	defer ticker.Done()
+	for {
+		select {
+		case slot := <-ticker.C():
+			if err := s.sync(slot); err != nil {
+				if s.ctx.Err() != nil {
+					return s.ctx.Err()
+				}
+				log.WithError(err).WithField("slot", slot).Warn("Could not sync light client")
+			}
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+}
+
+// bootstrap initializes the store from the bootstrap of the trusted block root.
+func (s *Service) bootstrap() error {
+	genesis, err := s.cfg.BeaconNode.GetGenesis(s.ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis")
+	}
+	if s.cfg.GenesisValidatorsRoot != nil && *s.cfg.GenesisValidatorsRoot != genesis.GenesisValidatorsRoot {
+		return errors.Errorf("beacon node genesis validators root %#x does not match the expected root %#x",
+			genesis.GenesisValidatorsRoot, *s.cfg.GenesisValidatorsRoot)
+	}
+	bootstrap, err := s.cfg.BeaconNode.GetLightClientBootstrap(s.ctx, s.cfg.TrustedBlockRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get light client bootstrap")
+	}
+	store, err := NewStore(s.cfg.TrustedBlockRoot, bootstrap, genesis.GenesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.store = store
+	s.genesis = genesis.Time
+	s.lock.Unlock()
+	log.WithFields(logrus.Fields{
+		"slot": bootstrap.Header.Slot,
+		"root": s.cfg.TrustedBlockRoot,
+	}).Info("Bootstrapped light client")
+	return s.sync(slots.CurrentSlot(uint64(genesis.Time.Unix())))
+}
+
+// sync catches up with the sync committee periods the store is missing, then applies the latest finality and
+// optimistic updates of the beacon node.
+func (s *Service) sync(currentSlot primitives.Slot) error {
+	s.lock.Lock()
+	if err := s.syncLocked(currentSlot); err != nil {
+		s.lock.Unlock()
+		return err
+	}
+	finalized, optimistic := s.store.FinalizedHeader(), s.store.OptimisticHeader()
+	s.lock.Unlock()
+
+	return s.updateHeaders(finalized, optimistic)
+}
+
+func (s *Service) syncLocked(currentSlot primitives.Slot) error {
+	currentPeriod := computePeriod(currentSlot)
+	for s.store.Period() < currentPeriod || !s.store.IsNextSyncCommitteeKnown() {
+		start := s.store.Period()
+		count := currentPeriod - start + 1
+		if count > maxRequestUpdates {
+			count = maxRequestUpdates
+		}
+		updates, err := s.cfg.BeaconNode.GetLightClientUpdatesByRange(s.ctx, start, count)
+		if err != nil {
+			return errors.Wrap(err, "could not get light client updates")
+		}
+		for _, u := range updates {
+			if err := s.store.ProcessUpdate(u, currentSlot); err != nil {
+				log.WithError(err).WithField("signatureSlot", u.SignatureSlot).Debug("Skipping light client update")
+			}
+		}
+		if s.store.Period() == start {
+			// The beacon node has no update moving the store to the next period yet.
+			break
+		}
+	}
+
+	finality, err := s.cfg.BeaconNode.GetLightClientFinalityUpdate(s.ctx)
+	if err != nil {
+		log.WithError(err).Debug("Could not get light client finality update")
+	} else if err := s.store.ProcessFinalityUpdate(finality, currentSlot); err != nil && !errors.Is(err, errIrrelevantUpdate) {
+		log.WithError(err).Debug("Could not process light client finality update")
+	}
+	optimistic, err := s.cfg.BeaconNode.GetLightClientOptimisticUpdate(s.ctx)
+	if err != nil {
+		log.WithError(err).Debug("Could not get light client optimistic update")
+	} else if err := s.store.ProcessOptimisticUpdate(optimistic, currentSlot); err != nil && !errors.Is(err, errIrrelevantUpdate) {
+		log.WithError(err).Debug("Could not process light client optimistic update")
+	}
+	if s.store.ForceUpdate(currentSlot) {
+		log.WithField("slot", s.store.FinalizedHeader().Slot).Warn("Forced light client update after the update timeout")
+	}
+	return nil
+}
+
+// updateHeaders fetches the execution payload fields of the blocks of headers which changed since the last slot.
+func (s *Service) updateHeaders(finalized, optimistic *zondpbv1.BeaconBlockHeader) error {
+	f, err := s.verifiedHeader(s.Finalized(), finalized)
+	if err != nil {
+		return errors.Wrap(err, "could not update finalized header")
+	}
+	o, err := s.verifiedHeader(s.Optimistic(), optimistic)
+	if err != nil {
+		return errors.Wrap(err, "could not update optimistic header")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.finalized == nil || s.finalized.Root != f.Root {
+		log.WithFields(logrus.Fields{
+			"slot":           f.Header.Slot,
+			"root":           f.Root,
+			"executionState": f.Execution.StateRoot,
+		}).Info("New finalized header")
+	}
+	s.finalized = f
+	s.optimistic = o
+	return nil
+}
+
+func (s *Service) verifiedHeader(previous *VerifiedHeader, header *zondpbv1.BeaconBlockHeader) (*VerifiedHeader, error) {
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.Root == root {
+		return previous, nil
+	}
+	execution, err := executionHeader(s.ctx, s.cfg.BeaconNode, header)
+	if err != nil {
+		return nil, err
+	}
+	return &VerifiedHeader{Header: header, Root: root, Execution: execution}, nil
+}
@@ -0,0 +1,493 @@
+package lightclient
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-bitfield"
+	lightclientcore "github.com/theQRL/qrysm/v4/beacon-chain/core/light-client"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/container/trie"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+var (
+	errUntrustedBootstrap     = errors.New("bootstrap header does not match the trusted block root")
+	errInvalidMerkleBranch    = errors.New("invalid merkle branch")
+	errNotEnoughParticipants  = errors.New("not enough sync committee participants")
+	errInvalidUpdateSlots     = errors.New("update slots are not ordered")
+	errIrrelevantUpdate       = errors.New("update is not relevant to the store")
+	errUnknownSyncCommittee   = errors.New("update is signed by an unknown sync committee")
+	errInvalidSyncCommittee   = errors.New("update next sync committee does not match the known next sync committee")
+	errInvalidSyncAggregate   = errors.New("invalid sync aggregate")
+	errInvalidUpdateSignature = errors.New("invalid sync committee signature")
+)
+
+// Store is the state of a light client, as defined by the LightClientStore of the Altair light client sync protocol.
+// Headers only advance after an update proves them against a block signed by the sync committee known to the
+// store, starting from the sync committee of a trusted block. A Store is not safe for concurrent use.
+type Store struct {
+	genesisValidatorsRoot         [32]byte
+	finalizedHeader               *zondpbv1.BeaconBlockHeader
+	currentSyncCommittee          *zondpbv2.SyncCommittee
+	nextSyncCommittee             *zondpbv2.SyncCommittee
+	bestValidUpdate               *zondpbv2.LightClientUpdate
+	optimisticHeader              *zondpbv1.BeaconBlockHeader
+	previousMaxActiveParticipants uint64
+	currentMaxActiveParticipants  uint64
+}
+
+// NewStore initializes a store from the bootstrap of a trusted block root.
+//
+// Spec code:
+// def initialize_light_client_store(trusted_block_root: Root,
+//
+//	                                bootstrap: LightClientBootstrap) -> LightClientStore:
+//	assert is_valid_light_client_header(bootstrap.header)
+//	assert hash_tree_root(bootstrap.header.beacon) == trusted_block_root
+//
+//	assert is_valid_merkle_branch(
+//	    leaf=hash_tree_root(bootstrap.current_sync_committee),
+//	    branch=bootstrap.current_sync_committee_branch,
+//	    depth=floorlog2(CURRENT_SYNC_COMMITTEE_INDEX),
+//	    index=get_subtree_index(CURRENT_SYNC_COMMITTEE_INDEX),
+//	    root=bootstrap.header.beacon.state_root,
+//	)
+func NewStore(trustedBlockRoot [32]byte, bootstrap *zondpbv2.LightClientBootstrap, genesisValidatorsRoot [32]byte) (*Store, error) {
+	if bootstrap == nil || bootstrap.Header == nil || bootstrap.CurrentSyncCommittee == nil {
+		return nil, errors.New("nil bootstrap")
+	}
+	root, err := bootstrap.Header.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute bootstrap header root")
+	}
+	if root != trustedBlockRoot {
+		return nil, errors.Wrapf(errUntrustedBootstrap, "header root=%#x, trusted root=%#x", root, trustedBlockRoot)
+	}
+	if err := verifySyncCommitteeBranch(bootstrap.CurrentSyncCommittee, bootstrap.CurrentSyncCommitteeBranch, zondpbv2.CurrentSyncCommitteeIndex, bootstrap.Header.StateRoot); err != nil {
+		return nil, errors.Wrap(err, "could not verify current sync committee")
+	}
+	return &Store{
+		genesisValidatorsRoot: genesisValidatorsRoot,
+		finalizedHeader:       bootstrap.Header,
+		currentSyncCommittee:  bootstrap.CurrentSyncCommittee,
+		optimisticHeader:      bootstrap.Header,
+	}, nil
+}
+
+// FinalizedHeader is the latest finalized header known to the store.
+func (s *Store) FinalizedHeader() *zondpbv1.BeaconBlockHeader {
+	return s.finalizedHeader
+}
+
+// OptimisticHeader is the latest header signed by a sufficient part of the sync committee.
+func (s *Store) OptimisticHeader() *zondpbv1.BeaconBlockHeader {
+	return s.optimisticHeader
+}
+
+// Period is the sync committee period of the finalized header of the store.
+func (s *Store) Period() uint64 {
+	return computePeriod(s.finalizedHeader.Slot)
+}
+
+// IsNextSyncCommitteeKnown returns true if the store knows the sync committee of the period after its finalized
+// header.
+func (s *Store) IsNextSyncCommitteeKnown() bool {
+	return s.nextSyncCommittee != nil
+}
+
+// ProcessUpdate validates an update and applies it to the store when it finalizes a newer header, or the next sync
+// committee, with a supermajority of the sync committee.
+//
+// Spec code:
+// def process_light_client_update(store: LightClientStore,
+//
+//	                              update: LightClientUpdate,
+//	                              current_slot: Slot,
+//	                              genesis_validators_root: Root) -> None:
+//	validate_light_client_update(store, update, current_slot, genesis_validators_root)
+//
+//	sync_committee_bits = update.sync_aggregate.sync_committee_bits
+//
+//	# Update the best update in case we have to force-update to it if the timeout elapses
+//	if (
+//	    store.best_valid_update is None
+//	    or is_better_update(update, store.best_valid_update)
+//	):
+//	    store.best_valid_update = update
+//
+//	# Track the maximum number of active participants in the committee signatures
+//	store.current_max_active_participants = max(
+//	    store.current_max_active_participants,
+//	    sum(sync_committee_bits),
+//	)
+//
+//	# Update the optimistic header
+//	if (
+//	    sum(sync_committee_bits) > get_safety_threshold(store)
+//	    and update.attested_header.beacon.slot > store.optimistic_header.beacon.slot
+//	):
+//	    store.optimistic_header = update.attested_header
+//
+//	# Update finalized header
+//	update_has_finalized_next_sync_committee = (
+//	    not is_next_sync_committee_known(store)
+//	    and is_sync_committee_update(update) and is_finality_update(update) and (
+//	        compute_sync_committee_period_at_slot(update.finalized_header.beacon.slot)
+//	        == compute_sync_committee_period_at_slot(update.attested_header.beacon.slot)
+//	    )
+//	)
+//	if (
+//	    sum(sync_committee_bits) * 3 >= len(sync_committee_bits) * 2
+//	    and (
+//	        update.finalized_header.beacon.slot > store.finalized_header.beacon.slot
+//	        or update_has_finalized_next_sync_committee
+//	    )
+//	):
+//	    # Normal update through 2/3 threshold
+//	    apply_light_client_update(store, update)
+//	    store.best_valid_update = None
+func (s *Store) ProcessUpdate(update *zondpbv2.LightClientUpdate, currentSlot primitives.Slot) error {
+	if err := s.validateUpdate(update, currentSlot); err != nil {
+		return err
+	}
+	bits := bitfield.Bitvector16(update.SyncAggregate.SyncCommitteeBits)
+
+	if s.bestValidUpdate == nil || lightclientcore.IsBetterUpdate(update, s.bestValidUpdate) {
+		s.bestValidUpdate = update
+	}
+	if bits.Count() > s.currentMaxActiveParticipants {
+		s.currentMaxActiveParticipants = bits.Count()
+	}
+	if bits.Count() > s.safetyThreshold() && update.AttestedHeader.Slot > s.optimisticHeader.Slot {
+		s.optimisticHeader = update.AttestedHeader
+	}
+
+	hasFinalizedNextSyncCommittee := !s.IsNextSyncCommitteeKnown() &&
+		update.IsSyncCommiteeUpdate() && update.IsFinalityUpdate() &&
+		computePeriod(update.FinalizedHeader.Slot) == computePeriod(update.AttestedHeader.Slot)
+	if lightclientcore.HasSupermajority(bits.Count(), bits.Len()) &&
+		(update.FinalizedHeader.Slot > s.finalizedHeader.Slot || hasFinalizedNextSyncCommittee) {
+		s.applyUpdate(update)
+		s.bestValidUpdate = nil
+	}
+	return nil
+}
+
+// ProcessFinalityUpdate processes a finality update, which is an update without the next sync committee.
+func (s *Store) ProcessFinalityUpdate(update *zondpbv2.LightClientFinalityUpdate, currentSlot primitives.Slot) error {
+	return s.ProcessUpdate(&zondpbv2.LightClientUpdate{
+		AttestedHeader:  update.AttestedHeader,
+		FinalizedHeader: update.FinalizedHeader,
+		FinalityBranch:  update.FinalityBranch,
+		SyncAggregate:   update.SyncAggregate,
+		SignatureSlot:   update.SignatureSlot,
+	}, currentSlot)
+}
+
+// ProcessOptimisticUpdate processes an optimistic update, which is an update without finality and without the next
+// sync committee.
+func (s *Store) ProcessOptimisticUpdate(update *zondpbv2.LightClientOptimisticUpdate, currentSlot primitives.Slot) error {
+	return s.ProcessUpdate(&zondpbv2.LightClientUpdate{
+		AttestedHeader: update.AttestedHeader,
+		SyncAggregate:  update.SyncAggregate,
+		SignatureSlot:  update.SignatureSlot,
+	}, currentSlot)
+}
+
+// ForceUpdate applies the best valid update once the finalized header of the store is older than a sync committee
+// period, so that a light client makes progress when the chain does not finalize. It returns true if the store was
+// updated.
+//
+// Spec code:
+// def process_light_client_store_force_update(store: LightClientStore, current_slot: Slot) -> None:
+//
+//	if (
+//	    current_slot > store.finalized_header.beacon.slot + UPDATE_TIMEOUT
+//	    and store.best_valid_update is not None
+//	):
+//	    # Forced best update when the update timeout has elapsed.
+//	    # Because the apply logic waits for `finalized_header.beacon.slot` to indicate sync committee finality,
+//	    # the `attested_header` may be treated as `finalized_header` in extended periods of non-finality
+//	    # to guarantee progression into later sync committee periods according to `is_better_update`.
+//	    if store.best_valid_update.finalized_header.beacon.slot <= store.finalized_header.beacon.slot:
+//	        store.best_valid_update.finalized_header = store.best_valid_update.attested_header
+//	    apply_light_client_update(store, store.best_valid_update)
+//	    store.best_valid_update = None
+func (s *Store) ForceUpdate(currentSlot primitives.Slot) bool {
+	if currentSlot <= s.finalizedHeader.Slot+updateTimeout() || s.bestValidUpdate == nil {
+		return false
+	}
+	if s.bestValidUpdate.FinalizedHeader == nil || s.bestValidUpdate.FinalizedHeader.Slot <= s.finalizedHeader.Slot {
+		s.bestValidUpdate.FinalizedHeader = s.bestValidUpdate.AttestedHeader
+	}
+	s.applyUpdate(s.bestValidUpdate)
+	s.bestValidUpdate = nil
+	return true
+}
+
+// validateUpdate checks an update against the store.
+//
+// Spec code:
+// def validate_light_client_update(store: LightClientStore,
+//
+//	                               update: LightClientUpdate,
+//	                               current_slot: Slot,
+//	                               genesis_validators_root: Root) -> None:
+//	# Verify sync committee has sufficient participants
+//	sync_aggregate = update.sync_aggregate
+//	assert sum(sync_aggregate.sync_committee_bits) >= MIN_SYNC_COMMITTEE_PARTICIPANTS
+//
+//	# Verify update does not skip a sync committee period
+//	assert current_slot >= update.signature_slot > update.attested_header.beacon.slot >= update.finalized_header.beacon.slot
+//	store_period = compute_sync_committee_period_at_slot(store.finalized_header.beacon.slot)
+//	update_signature_period = compute_sync_committee_period_at_slot(update.signature_slot)
+//	if is_next_sync_committee_known(store):
+//	    assert update_signature_period in (store_period, store_period + 1)
+//	else:
+//	    assert update_signature_period == store_period
+//
+//	# Verify update is relevant
+//	update_attested_period = compute_sync_committee_period_at_slot(update.attested_header.beacon.slot)
+//	update_has_next_sync_committee = not is_next_sync_committee_known(store) and (
+//	    is_sync_committee_update(update) and update_attested_period == store_period
+//	)
+//	assert (
+//	    update.attested_header.beacon.slot > store.finalized_header.beacon.slot
+//	    or update_has_next_sync_committee
+//	)
+//
+//	# Verify that the `finality_branch`, if present, confirms `finalized_header`
+//	# to match the finalized checkpoint root saved in the state of `attested_header`.
+//	# Note that the genesis finalized checkpoint root is represented as a zero hash.
+//	...
+//	# Verify that the `next_sync_committee`, if present, actually is the next sync committee saved in the
+//	# state of the `attested_header`
+//	...
+//	# Verify sync committee aggregate signature
+//	if update_signature_period == store_period:
+//	    sync_committee = store.current_sync_committee
+//	else:
+//	    sync_committee = store.next_sync_committee
+//	...
+func (s *Store) validateUpdate(update *zondpbv2.LightClientUpdate, currentSlot primitives.Slot) error {
+	if update == nil || update.AttestedHeader == nil || update.SyncAggregate == nil {
+		return errors.New("nil light client update")
+	}
+	bits := bitfield.Bitvector16(update.SyncAggregate.SyncCommitteeBits)
+	if bits.Count() < params.BeaconConfig().MinSyncCommitteeParticipants {
+		return errors.Wrapf(errNotEnoughParticipants, "participants=%d", bits.Count())
+	}
+
+	finalizedSlot := primitives.Slot(0)
+	if update.FinalizedHeader != nil {
+		finalizedSlot = update.FinalizedHeader.Slot
+	}
+	if currentSlot < update.SignatureSlot || update.SignatureSlot <= update.AttestedHeader.Slot || update.AttestedHeader.Slot < finalizedSlot {
+		return errors.Wrapf(errInvalidUpdateSlots, "current slot=%d, signature slot=%d, attested slot=%d, finalized slot=%d",
+			currentSlot, update.SignatureSlot, update.AttestedHeader.Slot, finalizedSlot)
+	}
+	storePeriod := s.Period()
+	signaturePeriod := computePeriod(update.SignatureSlot)
+	if signaturePeriod != storePeriod && (!s.IsNextSyncCommitteeKnown() || signaturePeriod != storePeriod+1) {
+		return errors.Wrapf(errUnknownSyncCommittee, "store period=%d, signature period=%d", storePeriod, signaturePeriod)
+	}
+
+	attestedPeriod := computePeriod(update.AttestedHeader.Slot)
+	hasNextSyncCommittee := !s.IsNextSyncCommitteeKnown() && update.IsSyncCommiteeUpdate() && attestedPeriod == storePeriod
+	if update.AttestedHeader.Slot <= s.finalizedHeader.Slot && !hasNextSyncCommittee {
+		return errors.Wrapf(errIrrelevantUpdate, "attested slot=%d, finalized slot=%d", update.AttestedHeader.Slot, s.finalizedHeader.Slot)
+	}
+
+	if !update.IsFinalityUpdate() {
+		if !isEmptyHeader(update.FinalizedHeader) {
+			return errors.New("update without finality branch has a finalized header")
+		}
+	} else {
+		var finalizedRoot [32]byte
+		if update.FinalizedHeader == nil {
+			return errors.New("update with finality branch has no finalized header")
+		}
+		if update.FinalizedHeader.Slot == params.BeaconConfig().GenesisSlot {
+			if !isEmptyHeader(update.FinalizedHeader) {
+				return errors.New("genesis finalized header is not empty")
+			}
+		} else {
+			var err error
+			finalizedRoot, err = update.FinalizedHeader.HashTreeRoot()
+			if err != nil {
+				return errors.Wrap(err, "could not compute finalized header root")
+			}
+		}
+		if !trie.VerifyMerkleProof(update.AttestedHeader.StateRoot, finalizedRoot[:], zondpbv2.FinalizedRootIndex, update.FinalityBranch) {
+			return errors.Wrap(errInvalidMerkleBranch, "finality branch")
+		}
+	}
+
+	if !update.IsSyncCommiteeUpdate() {
+		if update.NextSyncCommittee != nil && len(update.NextSyncCommittee.Pubkeys) != 0 {
+			return errors.New("update without next sync committee branch has a next sync committee")
+		}
+	} else {
+		if update.NextSyncCommittee == nil {
+			return errors.New("update with next sync committee branch has no next sync committee")
+		}
+		if attestedPeriod == storePeriod && s.IsNextSyncCommitteeKnown() && !update.NextSyncCommittee.Equals(s.nextSyncCommittee) {
+			return errInvalidSyncCommittee
+		}
+		if err := verifySyncCommitteeBranch(update.NextSyncCommittee, update.NextSyncCommitteeBranch, zondpbv2.NextSyncCommitteeIndex, update.AttestedHeader.StateRoot); err != nil {
+			return errors.Wrap(err, "could not verify next sync committee")
+		}
+	}
+
+	committee := s.currentSyncCommittee
+	if signaturePeriod != storePeriod {
+		committee = s.nextSyncCommittee
+	}
+	return verifySyncAggregate(committee, update.SyncAggregate, update.AttestedHeader, update.SignatureSlot, s.genesisValidatorsRoot)
+}
+
+// applyUpdate moves the store to the finalized header and the next sync committee of an update.
+//
+// Spec code:
+// def apply_light_client_update(store: LightClientStore, update: LightClientUpdate) -> None:
+//
+//	store_period = compute_sync_committee_period_at_slot(store.finalized_header.beacon.slot)
+//	update_finalized_period = compute_sync_committee_period_at_slot(update.finalized_header.beacon.slot)
+//	if not is_next_sync_committee_known(store):
+//	    assert update_finalized_period == store_period
+//	    store.next_sync_committee = update.next_sync_committee
+//	elif update_finalized_period == store_period + 1:
+//	    store.current_sync_committee = store.next_sync_committee
+//	    store.next_sync_committee = update.next_sync_committee
+//	    store.previous_max_active_participants = store.current_max_active_participants
+//	    store.current_max_active_participants = 0
+//	if update.finalized_header.beacon.slot > store.finalized_header.beacon.slot:
+//	    store.finalized_header = update.finalized_header
+//	    if store.finalized_header.beacon.slot > store.optimistic_header.beacon.slot:
+//	        store.optimistic_header = store.finalized_header
+func (s *Store) applyUpdate(update *zondpbv2.LightClientUpdate) {
+	storePeriod := s.Period()
+	finalizedPeriod := computePeriod(update.FinalizedHeader.Slot)
+	if !s.IsNextSyncCommitteeKnown() {
+		if finalizedPeriod != storePeriod {
+			return
+		}
+		s.nextSyncCommittee = update.NextSyncCommittee
+	} else if finalizedPeriod == storePeriod+1 {
+		s.currentSyncCommittee = s.nextSyncCommittee
+		s.nextSyncCommittee = update.NextSyncCommittee
+		s.previousMaxActiveParticipants = s.currentMaxActiveParticipants
+		s.currentMaxActiveParticipants = 0
+	}
+	if update.FinalizedHeader.Slot > s.finalizedHeader.Slot {
+		s.finalizedHeader = update.FinalizedHeader
+		if s.finalizedHeader.Slot > s.optimisticHeader.Slot {
+			s.optimisticHeader = s.finalizedHeader
+		}
+	}
+}
+
+// safetyThreshold is the number of participants an update needs to advance the optimistic header.
+func (s *Store) safetyThreshold() uint64 {
+	if s.previousMaxActiveParticipants > s.currentMaxActiveParticipants {
+		return s.previousMaxActiveParticipants / 2
+	}
+	return s.currentMaxActiveParticipants / 2
+}
+
+// verifySyncAggregate verifies that the participants of a sync aggregate signed the attested header. The signature
+// of the aggregate is the concatenation of the signatures of the participants, in committee order.
+func verifySyncAggregate(
+	committee *zondpbv2.SyncCommittee,
+	aggregate *zondpbv1.SyncAggregate,
+	attestedHeader *zondpbv1.BeaconBlockHeader,
+	signatureSlot primitives.Slot,
+	genesisValidatorsRoot [32]byte,
+) error {
+	if committee == nil {
+		return errUnknownSyncCommittee
+	}
+	bits := bitfield.Bitvector16(aggregate.SyncCommitteeBits)
+	if bits.Len() != uint64(len(committee.Pubkeys)) {
+		return errors.Wrapf(errInvalidSyncAggregate, "%d bits for a committee of %d members", bits.Len(), len(committee.Pubkeys))
+	}
+	participants := bits.BitIndices()
+	sigLen := len(participants) * dilithium.SignatureLength()
+	sig := aggregate.SyncCommitteeSignature
+	// The signature may be zero padded up to the size of a full committee.
+	if len(sig) < sigLen || !bytes.Equal(sig[sigLen:], make([]byte, len(sig)-sigLen)) {
+		return errors.Wrapf(errInvalidSyncAggregate, "signature of %d bytes for %d participants", len(sig), len(participants))
+	}
+	pubKeys := make([]dilithium.PublicKey, len(participants))
+	for i, idx := range participants {
+		pk, err := dilithium.PublicKeyFromBytes(committee.Pubkeys[idx])
+		if err != nil {
+			return errors.Wrapf(err, "could not decode public key of sync committee member %d", idx)
+		}
+		pubKeys[i] = pk
+	}
+
+	previousSlot := signatureSlot
+	if previousSlot > 0 {
+		previousSlot--
+	}
+	fork, err := forks.Fork(slots.ToEpoch(previousSlot))
+	if err != nil {
+		return errors.Wrap(err, "could not get fork of signature slot")
+	}
+	domain, err := signing.ComputeDomain(params.BeaconConfig().DomainSyncCommittee, fork.CurrentVersion, genesisValidatorsRoot[:])
+	if err != nil {
+		return errors.Wrap(err, "could not compute sync committee domain")
+	}
+	signingRoot, err := signing.ComputeSigningRoot(attestedHeader, domain)
+	if err != nil {
+		return errors.Wrap(err, "could not compute signing root")
+	}
+	valid, err := dilithium.VerifyMultipleSignatures([][]byte{sig[:sigLen]}, [][32]byte{signingRoot}, [][]dilithium.PublicKey{pubKeys})
+	if err != nil {
+		return errors.Wrap(errInvalidUpdateSignature, err.Error())
+	}
+	if !valid {
+		return errInvalidUpdateSignature
+	}
+	return nil
+}
+
+func verifySyncCommitteeBranch(committee *zondpbv2.SyncCommittee, branch [][]byte, gindex uint64, stateRoot []byte) error {
+	root, err := committee.HashTreeRoot()
+	if err != nil {
+		return errors.Wrap(err, "could not compute sync committee root")
+	}
+	if !trie.VerifyMerkleProof(stateRoot, root[:], gindex, branch) {
+		return errors.Wrap(errInvalidMerkleBranch, "sync committee branch")
+	}
+	return nil
+}
+
+func isEmptyHeader(h *zondpbv1.BeaconBlockHeader) bool {
+	if h == nil {
+		return true
+	}
+	zero := make([]byte, 32)
+	return h.Slot == 0 && h.ProposerIndex == 0 &&
+		(len(h.ParentRoot) == 0 || bytes.Equal(h.ParentRoot, zero)) &&
+		(len(h.StateRoot) == 0 || bytes.Equal(h.StateRoot, zero)) &&
+		(len(h.BodyRoot) == 0 || bytes.Equal(h.BodyRoot, zero))
+}
+
+func computePeriod(slot primitives.Slot) uint64 {
+	return slots.SyncCommitteePeriod(slots.ToEpoch(slot))
+}
+
+// updateTimeout is the number of slots after which the best valid update is force applied, which is a sync
+// committee period.
+func updateTimeout() primitives.Slot {
+	return params.BeaconConfig().SlotsPerEpoch * primitives.Slot(params.BeaconConfig().EpochsPerSyncCommitteePeriod)
+}
@@ -0,0 +1,215 @@
+package lightclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	zondpbv1 "github.com/theQRL/qrysm/v4/proto/zond/v1"
+	zondpbv2 "github.com/theQRL/qrysm/v4/proto/zond/v2"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/testing/util"
+	"github.com/theQRL/qrysm/v4/time/slots"
+)
+
+var testGenesisValidatorsRoot = [32]byte{'g', 'v', 'r'}
+
+type testChain struct {
+	t    *testing.T
+	keys []dilithium.DilithiumKey
+}
+
+func newTestChain(t *testing.T) *testChain {
+	keys := make([]dilithium.DilithiumKey, params.BeaconConfig().SyncCommitteeSize)
+	for i := range keys {
+		k, err := dilithium.RandKey()
+		require.NoError(t, err)
+		keys[i] = k
+	}
+	return &testChain{t: t, keys: keys}
+}
+
+func (c *testChain) committee() *zondpb.SyncCommittee {
+	pubKeys := make([][]byte, len(c.keys))
+	for i, k := range c.keys {
+		pubKeys[i] = k.PublicKey().Marshal()
+	}
+	return &zondpb.SyncCommittee{Pubkeys: pubKeys, AggregatePubkey: make([]byte, 41472)}
+}
+
+// header returns the header of a block at the given slot, along with its post-state, which finalized the given
+// checkpoint root.
+func (c *testChain) header(slot primitives.Slot, finalizedRoot [32]byte) (*zondpbv1.BeaconBlockHeader, *zondpbv2.SyncCommittee, [][]byte, [][]byte, [][]byte) {
+	ctx := context.Background()
+	st, err := util.NewBeaconStateCapella()
+	require.NoError(c.t, err)
+	require.NoError(c.t, st.SetSlot(slot))
+	require.NoError(c.t, st.SetCurrentSyncCommittee(c.committee()))
+	require.NoError(c.t, st.SetNextSyncCommittee(c.committee()))
+	require.NoError(c.t, st.SetFinalizedCheckpoint(&zondpb.Checkpoint{Epoch: slots.ToEpoch(slot), Root: finalizedRoot[:]}))
+	stateRoot, err := st.HashTreeRoot(ctx)
+	require.NoError(c.t, err)
+	currentProof, err := st.CurrentSyncCommitteeProof(ctx)
+	require.NoError(c.t, err)
+	nextProof, err := st.NextSyncCommitteeProof(ctx)
+	require.NoError(c.t, err)
+	finalityProof, err := st.FinalizedRootProof(ctx)
+	require.NoError(c.t, err)
+	h := &zondpbv1.BeaconBlockHeader{
+		Slot:       slot,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  stateRoot[:],
+		BodyRoot:   make([]byte, 32),
+	}
+	committee := c.committee()
+	return h, &zondpbv2.SyncCommittee{Pubkeys: committee.Pubkeys, AggregatePubkey: committee.AggregatePubkey}, currentProof, nextProof, finalityProof
+}
+
+func (c *testChain) bootstrap(slot primitives.Slot) (*zondpbv2.LightClientBootstrap, [32]byte) {
+	h, committee, proof, _, _ := c.header(slot, [32]byte{})
+	root, err := h.HashTreeRoot()
+	require.NoError(c.t, err)
+	return &zondpbv2.LightClientBootstrap{Header: h, CurrentSyncCommittee: committee, CurrentSyncCommitteeBranch: proof}, root
+}
+
+// update returns an update finalizing a header at finalizedSlot, attested at attestedSlot and signed by the given
+// number of sync committee members.
+func (c *testChain) update(finalizedSlot, attestedSlot primitives.Slot, participants uint64) *zondpbv2.LightClientUpdate {
+	finalized := &zondpbv1.BeaconBlockHeader{
+		Slot:       finalizedSlot,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  bytesutil.PadTo([]byte("state"), 32),
+		BodyRoot:   make([]byte, 32),
+	}
+	finalizedRoot, err := finalized.HashTreeRoot()
+	require.NoError(c.t, err)
+	attested, committee, _, nextProof, finalityProof := c.header(attestedSlot, finalizedRoot)
+
+	signatureSlot := attestedSlot + 1
+	fork, err := forks.Fork(slots.ToEpoch(attestedSlot))
+	require.NoError(c.t, err)
+	domain, err := signing.ComputeDomain(params.BeaconConfig().DomainSyncCommittee, fork.CurrentVersion, testGenesisValidatorsRoot[:])
+	require.NoError(c.t, err)
+	signingRoot, err := signing.ComputeSigningRoot(attested, domain)
+	require.NoError(c.t, err)
+	bits := bitfield.NewBitvector16()
+	var sig []byte
+	for i := uint64(0); i < participants; i++ {
+		bits.SetBitAt(i, true)
+		sig = append(sig, c.keys[i].Sign(signingRoot[:]).Marshal()...)
+	}
+	return &zondpbv2.LightClientUpdate{
+		AttestedHeader:          attested,
+		NextSyncCommittee:       committee,
+		NextSyncCommitteeBranch: nextProof,
+		FinalizedHeader:         finalized,
+		FinalityBranch:          finalityProof,
+		SyncAggregate:           &zondpbv1.SyncAggregate{SyncCommitteeBits: bits, SyncCommitteeSignature: sig},
+		SignatureSlot:           signatureSlot,
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	c := newTestChain(t)
+	bootstrap, root := c.bootstrap(8)
+
+	s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.DeepEqual(t, bootstrap.Header, s.FinalizedHeader())
+	require.DeepEqual(t, bootstrap.Header, s.OptimisticHeader())
+	require.Equal(t, false, s.IsNextSyncCommitteeKnown())
+
+	_, err = NewStore([32]byte{'a'}, bootstrap, testGenesisValidatorsRoot)
+	require.ErrorIs(t, err, errUntrustedBootstrap)
+
+	bootstrap.CurrentSyncCommitteeBranch[0] = make([]byte, 32)
+	_, err = NewStore(root, bootstrap, testGenesisValidatorsRoot)
+	require.ErrorIs(t, err, errInvalidMerkleBranch)
+}
+
+func TestStore_ProcessUpdate(t *testing.T) {
+	c := newTestChain(t)
+	bootstrap, root := c.bootstrap(8)
+	size := params.BeaconConfig().SyncCommitteeSize
+
+	t.Run("applies finalized header and next sync committee", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		update := c.update(16, 24, size)
+		require.NoError(t, s.ProcessUpdate(update, 26))
+		require.DeepEqual(t, update.FinalizedHeader, s.FinalizedHeader())
+		require.DeepEqual(t, update.AttestedHeader, s.OptimisticHeader())
+		require.Equal(t, true, s.IsNextSyncCommitteeKnown())
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		update := c.update(16, 24, size)
+		update.SyncAggregate.SyncCommitteeSignature[0] ^= 0xff
+		require.ErrorIs(t, s.ProcessUpdate(update, 26), errInvalidUpdateSignature)
+		require.DeepEqual(t, bootstrap.Header, s.FinalizedHeader())
+	})
+	t.Run("participant without signature", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		update := c.update(16, 24, size-1)
+		bitfield.Bitvector16(update.SyncAggregate.SyncCommitteeBits).SetBitAt(size-1, true)
+		require.ErrorIs(t, s.ProcessUpdate(update, 26), errInvalidSyncAggregate)
+	})
+	t.Run("wrong genesis validators root", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, [32]byte{'b'})
+		require.NoError(t, err)
+		require.ErrorIs(t, s.ProcessUpdate(c.update(16, 24, size), 26), errInvalidUpdateSignature)
+	})
+	t.Run("invalid finality branch", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		update := c.update(16, 24, size)
+		update.FinalizedHeader.Slot = 17
+		require.ErrorIs(t, s.ProcessUpdate(update, 26), errInvalidMerkleBranch)
+	})
+	t.Run("signature slot in the future", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		require.ErrorIs(t, s.ProcessUpdate(c.update(16, 24, size), 24), errInvalidUpdateSlots)
+	})
+	t.Run("no participants", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		require.ErrorIs(t, s.ProcessUpdate(c.update(16, 24, 0), 26), errNotEnoughParticipants)
+	})
+	t.Run("unknown sync committee", func(t *testing.T) {
+		s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		period := updateTimeout()
+		require.ErrorIs(t, s.ProcessUpdate(c.update(period, period+8, size), period+10), errUnknownSyncCommittee)
+	})
+}
+
+func TestStore_ForceUpdate(t *testing.T) {
+	c := newTestChain(t)
+	bootstrap, root := c.bootstrap(8)
+	s, err := NewStore(root, bootstrap, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+
+	// Without a supermajority, the update only moves the optimistic header.
+	participants := params.BeaconConfig().SyncCommitteeSize * 2 / 3
+	update := c.update(16, 24, participants)
+	require.NoError(t, s.ProcessUpdate(update, 26))
+	require.DeepEqual(t, bootstrap.Header, s.FinalizedHeader())
+	require.DeepEqual(t, update.AttestedHeader, s.OptimisticHeader())
+
+	require.Equal(t, false, s.ForceUpdate(8+updateTimeout()))
+	require.DeepEqual(t, bootstrap.Header, s.FinalizedHeader())
+	require.Equal(t, true, s.ForceUpdate(9+updateTimeout()))
+	require.DeepEqual(t, update.FinalizedHeader, s.FinalizedHeader())
+	require.Equal(t, true, s.IsNextSyncCommitteeKnown())
+	require.Equal(t, false, s.ForceUpdate(9+updateTimeout()))
+}
@@ -5,16 +5,16 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation"
 )
 
 // attList represents list of attestations, defined for easier en masse operations (filtering, sorting).
 type attList []*zondpb.Attestation
 
-// BLS aggregate signature aliases for testing / benchmark substitution. These methods are
-// significantly more expensive than the inner logic of AggregateAttestations so they must be
-// substituted for benchmarks which analyze AggregateAttestations.
-var aggregateSignatures = dilithium.AggregateSignatures
+// Signature aliases for testing / benchmark substitution. These methods are significantly more
+// expensive than the inner logic of AggregateAttestations so they must be substituted for
+// benchmarks which analyze AggregateAttestations.
 var unaggregatedSignatures = dilithium.UnaggregatedSignatures
 var signatureFromBytes = dilithium.SignatureFromBytes
 
@@ -98,22 +98,7 @@ func AggregatePair(a1, a2 *zondpb.Attestation) (*zondpb.Attestation, error) {
 		return baseAtt, nil
 	}
 
-	newBits, err := baseAtt.AggregationBits.Or(newAtt.AggregationBits)
-	if err != nil {
-		return nil, err
-	}
-	newSig, err := signatureFromBytes(newAtt.Signature)
-	if err != nil {
-		return nil, err
-	}
-	baseSig, err := signatureFromBytes(baseAtt.Signature)
-	if err != nil {
-		return nil, err
-	}
-
-	aggregatedSig := aggregateSignatures([]dilithium.Signature{baseSig, newSig})
-	baseAtt.Signature = aggregatedSig.Marshal()
-	baseAtt.AggregationBits = newBits
-
-	return baseAtt, nil
+	// Signatures cannot be aggregated, the pair is merged into an attestation carrying the
+	// signatures of both.
+	return attestation.MergeSigners(baseAtt, newAtt)
 }
@@ -5,7 +5,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/go-bitfield"
-	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation"
@@ -120,26 +119,6 @@ func NewMaxCover(atts []*zondpb.Attestation) *aggregation.MaxCoverProblem {
 	return &aggregation.MaxCoverProblem{Candidates: candidates}
 }
 
-// aggregate returns list as an aggregated attestation.
-func (al attList) aggregate(coverage bitfield.Bitlist) (*zondpb.Attestation, error) {
-	if len(al) < 2 {
-		return nil, errors.Wrap(ErrInvalidAttestationCount, "cannot aggregate")
-	}
-	signs := make([]dilithium.Signature, len(al))
-	for i := 0; i < len(al); i++ {
-		sig, err := signatureFromBytes(al[i].Signature)
-		if err != nil {
-			return nil, err
-		}
-		signs[i] = sig
-	}
-	return &zondpb.Attestation{
-		AggregationBits: coverage,
-		Data:            zondpb.CopyAttestationData(al[0].Data),
-		Signature:       aggregateSignatures(signs).Marshal(),
-	}, nil
-}
-
 // padSelectedKeys adds additional value to every key.
 func padSelectedKeys(keys []int, pad int) []int {
 	for i, key := range keys {
@@ -165,18 +144,19 @@ func aggregateAttestations(atts []*zondpb.Attestation, keys []int, coverage *bit
 			targetIdx = idx
 		}
 	}
+	sigLen := dilithium.SignatureLength()
 	var attsKeys []int
 	attsMap := make(map[int][]byte)
 	sigValidatorIndexMap := make(map[int][]uint64)
 	for _, att := range atts {
 		for i, index := range att.AggregationBits.BitIndices() {
 			attsKeys = append(attsKeys, index)
-			offset := i * dilithium2.CryptoBytes
+			offset := i * sigLen
 			// Ignore if the validator index in committee already exists
 			if _, found := attsMap[index]; found {
 				continue
 			}
-			attsMap[index] = append(attsMap[index], att.Signature[offset:offset+dilithium2.CryptoBytes]...)
+			attsMap[index] = append(attsMap[index], att.Signature[offset:offset+sigLen]...)
 			sigValidatorIndexMap[index] = append(sigValidatorIndexMap[index], att.SignatureValidatorIndex[i])
 		}
 	}
@@ -197,9 +177,8 @@ func aggregateAttestations(atts []*zondpb.Attestation, keys []int, coverage *bit
 	// Put aggregated attestation at a position of the first selected attestation.
 	atts[targetIdx] = &zondpb.Attestation{
 		// Append size byte, which will be unnecessary on switch to Bitlist64.
-		AggregationBits: coverage.ToBitlist(),
-		Data:            data,
-		//Signature:              aggregateSignatures(signs).Marshal(),
+		AggregationBits:         coverage.ToBitlist(),
+		Data:                    data,
 		Signature:               unaggregatedSignatures(signs),
 		SignatureValidatorIndex: signatureValidatorIndex,
 	}
@@ -1,6 +1,7 @@
 package sync_contribution
 
 import (
+	"github.com/pkg/errors"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	v2 "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation"
@@ -88,17 +89,26 @@ func aggregate(c1, c2 *v2.SyncCommitteeContribution) (*v2.SyncCommitteeContribut
 	if err != nil {
 		return nil, err
 	}
-	newSig, err := dilithium.SignatureFromBytes(newContribution.Signature)
-	if err != nil {
-		return nil, err
+	// Signatures cannot be aggregated, a contribution carries the signature of each of its
+	// participants in the order of its aggregation bits. The participants of the pair are
+	// disjoint, so their signatures are merged in that order.
+	sigLen := dilithium.SignatureLength()
+	baseIndices := baseContribution.AggregationBits.BitIndices()
+	newIndices := newContribution.AggregationBits.BitIndices()
+	if len(baseContribution.Signature) != len(baseIndices)*sigLen || len(newContribution.Signature) != len(newIndices)*sigLen {
+		return nil, errors.New("contribution signatures do not match its aggregation bits")
 	}
-	baseSig, err := dilithium.SignatureFromBytes(baseContribution.Signature)
-	if err != nil {
-		return nil, err
+	sigs := make([]byte, 0, len(baseContribution.Signature)+len(newContribution.Signature))
+	for i, j := 0, 0; i < len(baseIndices) || j < len(newIndices); {
+		if j == len(newIndices) || (i < len(baseIndices) && baseIndices[i] < newIndices[j]) {
+			sigs = append(sigs, baseContribution.Signature[i*sigLen:(i+1)*sigLen]...)
+			i++
+		} else {
+			sigs = append(sigs, newContribution.Signature[j*sigLen:(j+1)*sigLen]...)
+			j++
+		}
 	}
-
-	aggregatedSig := dilithium.AggregateSignatures([]dilithium.Signature{baseSig, newSig})
-	baseContribution.Signature = aggregatedSig.Marshal()
+	baseContribution.Signature = sigs
 	baseContribution.AggregationBits = newBits
 
 	return baseContribution, nil
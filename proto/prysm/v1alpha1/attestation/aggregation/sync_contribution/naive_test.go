@@ -6,7 +6,7 @@ import (
 	"testing"
 
 	"github.com/theQRL/go-bitfield"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation"
 	aggtesting "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation/aggregation/testing"
@@ -21,13 +21,13 @@ func TestAggregateAttestations_aggregate(t *testing.T) {
 		want *zondpb.SyncCommitteeContribution
 	}{
 		{
-			a1:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x02}, Signature: bls.NewAggregateSignature().Marshal()},
-			a2:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x01}, Signature: bls.NewAggregateSignature().Marshal()},
+			a1:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x02}, Signature: make([]byte, dilithium.SignatureLength())},
+			a2:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x01}, Signature: make([]byte, dilithium.SignatureLength())},
 			want: &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x03}},
 		},
 		{
-			a1:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x01}, Signature: bls.NewAggregateSignature().Marshal()},
-			a2:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x02}, Signature: bls.NewAggregateSignature().Marshal()},
+			a1:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x01}, Signature: make([]byte, dilithium.SignatureLength())},
+			a2:   &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x02}, Signature: make([]byte, dilithium.SignatureLength())},
 			want: &zondpb.SyncCommitteeContribution{AggregationBits: bitfield.Bitvector128{0x03}},
 		},
 	}
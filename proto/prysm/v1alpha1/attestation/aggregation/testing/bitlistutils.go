@@ -76,23 +76,31 @@ func Bitlists64WithMultipleBitSet(t testing.TB, n, length, count uint64) []*bitf
 	return lists
 }
 
-// MakeAttestationsFromBitlists creates list of attestations from list of bitlist.
+// MakeAttestationsFromBitlists creates list of attestations from list of bitlist. Each attestation
+// carries an empty signature for every set bit, and uses the bit indices as validator indices.
 func MakeAttestationsFromBitlists(bl []bitfield.Bitlist) []*zondpb.Attestation {
 	atts := make([]*zondpb.Attestation, len(bl))
 	for i, b := range bl {
+		indices := b.BitIndices()
+		sigValIndices := make([]uint64, len(indices))
+		for j, idx := range indices {
+			sigValIndices[j] = uint64(idx)
+		}
 		atts[i] = &zondpb.Attestation{
 			AggregationBits: b,
 			Data: &zondpb.AttestationData{
 				Slot:           42,
 				CommitteeIndex: 1,
 			},
-			Signature: dilithium.NewAggregateSignature().Marshal(),
+			Signature:               make([]byte, len(indices)*dilithium.SignatureLength()),
+			SignatureValidatorIndex: sigValIndices,
 		}
 	}
 	return atts
 }
 
-// MakeSyncContributionsFromBitVector creates list of sync contributions from list of bitvector.
+// MakeSyncContributionsFromBitVector creates list of sync contributions from list of bitvector. Each
+// contribution carries an empty signature for every set bit.
 func MakeSyncContributionsFromBitVector(bl []bitfield.Bitvector128) []*zondpb.SyncCommitteeContribution {
 	c := make([]*zondpb.SyncCommitteeContribution, len(bl))
 	for i, b := range bl {
@@ -100,7 +108,7 @@ func MakeSyncContributionsFromBitVector(bl []bitfield.Bitvector128) []*zondpb.Sy
 			Slot:              primitives.Slot(1),
 			SubcommitteeIndex: 2,
 			AggregationBits:   b,
-			Signature:         dilithium.NewAggregateSignature().Marshal(),
+			Signature:         make([]byte, len(b.BitIndices())*dilithium.SignatureLength()),
 		}
 	}
 	return c
@@ -10,7 +10,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/theQRL/go-bitfield"
-	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
@@ -51,9 +50,10 @@ func ConvertToIndexed(ctx context.Context, attestation *zondpb.Attestation, comm
 	signatureValidatorIndex := make([]uint64, len(attestation.SignatureValidatorIndex))
 	copy(signatureValidatorIndex, attestation.SignatureValidatorIndex)
 	sigsMap := make(map[uint64][]byte)
+	sigLen := dilithium.SignatureLength()
 	for i, validatorIndex := range signatureValidatorIndex {
-		offset := i * dilithium2.CryptoBytes
-		sigsMap[validatorIndex] = attestation.Signature[offset : offset+dilithium2.CryptoBytes]
+		offset := i * sigLen
+		sigsMap[validatorIndex] = attestation.Signature[offset : offset+sigLen]
 	}
 	signatures := make([]byte, 0, len(attestation.Signature))
 
@@ -132,13 +132,16 @@ func VerifyIndexedAttestationSig(ctx context.Context, indexedAtt *zondpb.Indexed
 		return errors.Wrap(err, "could not get signing root of object")
 	}
 
-	sig, err := dilithium.SignatureFromBytes(indexedAtt.Signature)
+	if len(indices) == 0 {
+		return nil
+	}
+	// Signatures cannot be aggregated, so the attestation carries the signature of every
+	// attester, in the order of the attesting indices.
+	valid, err := dilithium.VerifyMultipleSignaturesWithContext(ctx, [][]byte{indexedAtt.Signature}, [][32]byte{messageHash}, [][]dilithium.PublicKey{pubKeys})
 	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to signature")
+		return errors.Wrap(err, "could not verify signatures")
 	}
-
-	voted := len(indices) > 0
-	if voted && !sig.FastAggregateVerify(pubKeys, messageHash) {
+	if !valid {
 		return signing.ErrSigFailedToVerify
 	}
 	return nil
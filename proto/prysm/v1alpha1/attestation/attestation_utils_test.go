@@ -5,9 +5,9 @@ import (
 	"testing"
 
 	"github.com/theQRL/go-bitfield"
-	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
 	"github.com/theQRL/qrysm/v4/config/params"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	zond "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
 	"github.com/theQRL/qrysm/v4/testing/assert"
@@ -75,7 +75,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 			wantedErr: "nil or missing indexed attestation data",
 		},
@@ -86,7 +86,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 			wantedErr: "expected non-empty",
 		},
@@ -97,7 +97,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 			wantedErr: "indices count exceeds",
 		},
@@ -108,7 +108,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 			wantedErr: "not uniquely sorted",
 		},
@@ -119,7 +119,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 		},
 		{
@@ -129,7 +129,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 		},
 		{
@@ -139,7 +139,7 @@ func TestIsValidAttestationIndices(t *testing.T) {
 				Data: &zond.AttestationData{
 					Target: &zond.Checkpoint{},
 				},
-				Signature: make([]byte, dilithium2.CryptoBytes),
+				Signature: make([]byte, dilithium.SignatureLength()),
 			},
 		},
 	}
@@ -176,7 +176,7 @@ func BenchmarkIsValidAttestationIndices(b *testing.B) {
 		Data: &zond.AttestationData{
 			Target: &zond.Checkpoint{},
 		},
-		Signature: make([]byte, dilithium2.CryptoBytes),
+		Signature: make([]byte, dilithium.SignatureLength()),
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,202 @@
+package attestation
+
+import (
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+)
+
+// Dilithium signatures cannot be aggregated, so an attestation carries the signature of each of
+// its signers. An attestation is in canonical form when its i-th signature and i-th signature
+// validator index belong to the committee member of its i-th set aggregation bit. The signers of
+// canonical attestations with the same data can then be merged, subtracted and extracted without
+// knowing the committee.
+
+var (
+	// ErrNonCanonicalSigners is returned when the signatures of an attestation do not match its
+	// aggregation bits.
+	ErrNonCanonicalSigners = errors.New("attestation signers are not in canonical form")
+	// ErrSignerMismatch is returned when attestations with the same data disagree on the
+	// validator at a committee position.
+	ErrSignerMismatch = errors.New("attestations have different validators at the same committee position")
+)
+
+// ValidateSignerOrder checks that the attestation is in canonical form for the committee: it has
+// one signature per set aggregation bit, and its signature validator indices are the committee
+// members of its set aggregation bits, in committee order.
+func ValidateSignerOrder(att *zondpb.Attestation, committee []primitives.ValidatorIndex) error {
+	if err := validateSignerCounts(att); err != nil {
+		return err
+	}
+	if att.AggregationBits.Len() != uint64(len(committee)) {
+		return errors.Errorf("aggregation bits length %d is not equal to committee length %d", att.AggregationBits.Len(), len(committee))
+	}
+	for i, pos := range att.AggregationBits.BitIndices() {
+		if att.SignatureValidatorIndex[i] != uint64(committee[pos]) {
+			return errors.Wrapf(ErrNonCanonicalSigners, "signature %d is from validator %d, expected validator %d at committee position %d",
+				i, att.SignatureValidatorIndex[i], committee[pos], pos)
+		}
+	}
+	return nil
+}
+
+// MergeSigners returns an attestation signed by all the signers of the attestations, which must
+// have the same data and be in canonical form. A signer of several attestations is included once,
+// with its signature from the first of them.
+func MergeSigners(atts ...*zondpb.Attestation) (*zondpb.Attestation, error) {
+	if len(atts) == 0 {
+		return nil, errors.New("no attestations to merge")
+	}
+	s, err := newSignerSet(atts[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, att := range atts {
+		if err := s.add(att, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s.attestation(), nil
+}
+
+// SubtractSigners returns an attestation signed by the signers of att which did not sign other.
+// Both attestations must have the same data and be in canonical form. The returned attestation
+// has no aggregation bits set when all the signers of att signed other.
+func SubtractSigners(att, other *zondpb.Attestation) (*zondpb.Attestation, error) {
+	s, err := newSignerSet(att)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.add(att, nil); err != nil {
+		return nil, err
+	}
+	o, err := newSignerSet(att)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.add(other, nil); err != nil {
+		return nil, err
+	}
+	for pos, sig := range o.sigs {
+		if sig == nil || s.sigs[pos] == nil {
+			continue
+		}
+		if s.indices[pos] != o.indices[pos] {
+			return nil, errors.Wrapf(ErrSignerMismatch, "position %d", pos)
+		}
+		s.sigs[pos] = nil
+	}
+	return s.attestation(), nil
+}
+
+// ExtractSigners returns an attestation signed by the signers of att at the committee positions
+// set in bits. The attestation must be in canonical form, and positions of bits which att is not
+// signed at are ignored.
+func ExtractSigners(att *zondpb.Attestation, bits bitfield.Bitlist) (*zondpb.Attestation, error) {
+	s, err := newSignerSet(att)
+	if err != nil {
+		return nil, err
+	}
+	if bits.Len() != s.bitsLen {
+		return nil, bitfield.ErrBitlistDifferentLength
+	}
+	if err := s.add(att, bits); err != nil {
+		return nil, err
+	}
+	return s.attestation(), nil
+}
+
+// validateSignerCounts checks that the attestation has one signature and one signature validator
+// index per set aggregation bit.
+func validateSignerCounts(att *zondpb.Attestation) error {
+	if att == nil || att.Data == nil || att.Data.Source == nil || att.Data.Target == nil || att.AggregationBits == nil {
+		return errors.New("nil or missing attestation data or aggregation bits")
+	}
+	count := att.AggregationBits.Count()
+	if uint64(len(att.SignatureValidatorIndex)) != count {
+		return errors.Wrapf(ErrNonCanonicalSigners, "%d signature validator indices for %d aggregation bits", len(att.SignatureValidatorIndex), count)
+	}
+	if uint64(len(att.Signature)) != count*uint64(dilithium.SignatureLength()) {
+		return errors.Wrapf(ErrNonCanonicalSigners, "signature length %d for %d aggregation bits", len(att.Signature), count)
+	}
+	return nil
+}
+
+// signerSet holds the signers of attestations with the same data by committee position.
+type signerSet struct {
+	data    *zondpb.AttestationData
+	bitsLen uint64
+	indices []uint64
+	sigs    [][]byte
+}
+
+func newSignerSet(att *zondpb.Attestation) (*signerSet, error) {
+	if err := validateSignerCounts(att); err != nil {
+		return nil, err
+	}
+	n := att.AggregationBits.Len()
+	return &signerSet{
+		data:    att.Data,
+		bitsLen: n,
+		indices: make([]uint64, n),
+		sigs:    make([][]byte, n),
+	}, nil
+}
+
+// add records the signers of the attestation which are not in the set yet, restricted to the
+// positions set in filter when it is not nil.
+func (s *signerSet) add(att *zondpb.Attestation, filter bitfield.Bitlist) error {
+	if err := validateSignerCounts(att); err != nil {
+		return err
+	}
+	if att.AggregationBits.Len() != s.bitsLen {
+		return bitfield.ErrBitlistDifferentLength
+	}
+	if !AttDataIsEqual(s.data, att.Data) {
+		return errors.New("attestations have different data")
+	}
+	sigLen := dilithium.SignatureLength()
+	for i, pos := range att.AggregationBits.BitIndices() {
+		if filter != nil && !filter.BitAt(uint64(pos)) {
+			continue
+		}
+		idx := att.SignatureValidatorIndex[i]
+		if s.sigs[pos] != nil {
+			if s.indices[pos] != idx {
+				return errors.Wrapf(ErrSignerMismatch, "position %d", pos)
+			}
+			continue
+		}
+		s.indices[pos] = idx
+		s.sigs[pos] = att.Signature[i*sigLen : (i+1)*sigLen]
+	}
+	return nil
+}
+
+// attestation returns a canonical attestation of the signers in the set. Its data and signatures
+// do not share memory with the attestations added to the set.
+func (s *signerSet) attestation() *zondpb.Attestation {
+	count := 0
+	for _, sig := range s.sigs {
+		if sig != nil {
+			count++
+		}
+	}
+	att := &zondpb.Attestation{
+		AggregationBits:         bitfield.NewBitlist(s.bitsLen),
+		Data:                    zondpb.CopyAttestationData(s.data),
+		Signature:               make([]byte, 0, count*dilithium.SignatureLength()),
+		SignatureValidatorIndex: make([]uint64, 0, count),
+	}
+	for pos, sig := range s.sigs {
+		if sig == nil {
+			continue
+		}
+		att.AggregationBits.SetBitAt(uint64(pos), true)
+		att.Signature = append(att.Signature, sig...)
+		att.SignatureValidatorIndex = append(att.SignatureValidatorIndex, s.indices[pos])
+	}
+	return att
+}
@@ -0,0 +1,189 @@
+package attestation_test
+
+import (
+	"testing"
+
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	zond "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/attestation"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+var signersTestCommittee = []primitives.ValidatorIndex{40, 12, 7, 93, 5, 61, 28, 14}
+
+func signersTestData(slot primitives.Slot) *zond.AttestationData {
+	return &zond.AttestationData{
+		Slot:            slot,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &zond.Checkpoint{Root: make([]byte, 32)},
+		Target:          &zond.Checkpoint{Root: make([]byte, 32)},
+	}
+}
+
+func signersTestSig(idx primitives.ValidatorIndex) []byte {
+	sig := make([]byte, dilithium.SignatureLength())
+	sig[0] = byte(idx)
+	return sig
+}
+
+// signersTestAtt returns a canonical attestation signed by the committee members at the positions.
+func signersTestAtt(data *zond.AttestationData, positions ...int) *zond.Attestation {
+	att := &zond.Attestation{
+		AggregationBits:         bitfield.NewBitlist(uint64(len(signersTestCommittee))),
+		Data:                    data,
+		Signature:               []byte{},
+		SignatureValidatorIndex: []uint64{},
+	}
+	for _, pos := range positions {
+		att.AggregationBits.SetBitAt(uint64(pos), true)
+	}
+	for _, pos := range att.AggregationBits.BitIndices() {
+		att.Signature = append(att.Signature, signersTestSig(signersTestCommittee[pos])...)
+		att.SignatureValidatorIndex = append(att.SignatureValidatorIndex, uint64(signersTestCommittee[pos]))
+	}
+	return att
+}
+
+func bitsAt(positions ...int) bitfield.Bitlist {
+	bits := bitfield.NewBitlist(uint64(len(signersTestCommittee)))
+	for _, pos := range positions {
+		bits.SetBitAt(uint64(pos), true)
+	}
+	return bits
+}
+
+func TestValidateSignerOrder(t *testing.T) {
+	data := signersTestData(1)
+	require.NoError(t, attestation.ValidateSignerOrder(signersTestAtt(data, 0, 3, 7), signersTestCommittee))
+	require.NoError(t, attestation.ValidateSignerOrder(signersTestAtt(data), signersTestCommittee))
+
+	swapped := signersTestAtt(data, 0, 3)
+	swapped.SignatureValidatorIndex[0], swapped.SignatureValidatorIndex[1] = swapped.SignatureValidatorIndex[1], swapped.SignatureValidatorIndex[0]
+	err := attestation.ValidateSignerOrder(swapped, signersTestCommittee)
+	require.ErrorIs(t, err, attestation.ErrNonCanonicalSigners)
+
+	wrongSigner := signersTestAtt(data, 2)
+	wrongSigner.SignatureValidatorIndex[0] = 1000
+	require.ErrorIs(t, attestation.ValidateSignerOrder(wrongSigner, signersTestCommittee), attestation.ErrNonCanonicalSigners)
+
+	missingSig := signersTestAtt(data, 1, 2)
+	missingSig.Signature = missingSig.Signature[:dilithium.SignatureLength()]
+	require.ErrorIs(t, attestation.ValidateSignerOrder(missingSig, signersTestCommittee), attestation.ErrNonCanonicalSigners)
+
+	missingIndex := signersTestAtt(data, 1, 2)
+	missingIndex.SignatureValidatorIndex = missingIndex.SignatureValidatorIndex[:1]
+	require.ErrorIs(t, attestation.ValidateSignerOrder(missingIndex, signersTestCommittee), attestation.ErrNonCanonicalSigners)
+
+	assert.ErrorContains(t, "is not equal to committee length", attestation.ValidateSignerOrder(signersTestAtt(data, 1), signersTestCommittee[:4]))
+	assert.ErrorContains(t, "nil or missing", attestation.ValidateSignerOrder(&zond.Attestation{}, signersTestCommittee))
+}
+
+func TestMergeSigners(t *testing.T) {
+	data := signersTestData(1)
+	a := signersTestAtt(data, 0, 2, 5)
+	b := signersTestAtt(data, 2, 3)
+	c := signersTestAtt(data, 7)
+
+	merged, err := attestation.MergeSigners(a, b, c)
+	require.NoError(t, err)
+	assert.DeepEqual(t, signersTestAtt(data, 0, 2, 3, 5, 7), merged)
+	require.NoError(t, attestation.ValidateSignerOrder(merged, signersTestCommittee))
+
+	// The merged attestation does not share memory with its inputs.
+	merged.Signature[0] = 0xff
+	merged.Data.Slot = 2
+	assert.Equal(t, primitives.Slot(1), a.Data.Slot)
+	assert.DeepEqual(t, signersTestSig(signersTestCommittee[0]), a.Signature[:dilithium.SignatureLength()])
+
+	// A single attestation is copied.
+	single, err := attestation.MergeSigners(a)
+	require.NoError(t, err)
+	assert.DeepEqual(t, a, single)
+
+	// The signature of the first attestation holding a signer is kept.
+	other := signersTestAtt(data, 2)
+	other.Signature[1] = 1
+	merged, err = attestation.MergeSigners(other, a)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), merged.Signature[dilithium.SignatureLength()+1])
+}
+
+func TestMergeSigners_Errors(t *testing.T) {
+	data := signersTestData(1)
+	_, err := attestation.MergeSigners()
+	assert.ErrorContains(t, "no attestations to merge", err)
+
+	_, err = attestation.MergeSigners(signersTestAtt(data, 1), signersTestAtt(signersTestData(2), 2))
+	assert.ErrorContains(t, "different data", err)
+
+	short := &zond.Attestation{AggregationBits: bitfield.NewBitlist(4), Data: data}
+	_, err = attestation.MergeSigners(signersTestAtt(data, 1), short)
+	require.ErrorIs(t, err, bitfield.ErrBitlistDifferentLength)
+
+	conflict := signersTestAtt(data, 1)
+	conflict.SignatureValidatorIndex[0] = 1000
+	_, err = attestation.MergeSigners(signersTestAtt(data, 1, 2), conflict)
+	require.ErrorIs(t, err, attestation.ErrSignerMismatch)
+
+	inconsistent := signersTestAtt(data, 1, 2)
+	inconsistent.Signature = inconsistent.Signature[1:]
+	_, err = attestation.MergeSigners(signersTestAtt(data, 3), inconsistent)
+	require.ErrorIs(t, err, attestation.ErrNonCanonicalSigners)
+}
+
+func TestSubtractSigners(t *testing.T) {
+	data := signersTestData(1)
+	att := signersTestAtt(data, 0, 2, 3, 5)
+
+	remaining, err := attestation.SubtractSigners(att, signersTestAtt(data, 2, 5, 6))
+	require.NoError(t, err)
+	assert.DeepEqual(t, signersTestAtt(data, 0, 3), remaining)
+
+	remaining, err = attestation.SubtractSigners(att, signersTestAtt(data, 0, 2, 3, 4, 5))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), remaining.AggregationBits.Count())
+	assert.Equal(t, 0, len(remaining.Signature))
+	assert.Equal(t, 0, len(remaining.SignatureValidatorIndex))
+
+	remaining, err = attestation.SubtractSigners(att, signersTestAtt(data))
+	require.NoError(t, err)
+	assert.DeepEqual(t, att, remaining)
+
+	_, err = attestation.SubtractSigners(att, signersTestAtt(signersTestData(2), 2))
+	assert.ErrorContains(t, "different data", err)
+
+	conflict := signersTestAtt(data, 2)
+	conflict.SignatureValidatorIndex[0] = 1000
+	_, err = attestation.SubtractSigners(att, conflict)
+	require.ErrorIs(t, err, attestation.ErrSignerMismatch)
+}
+
+func TestExtractSigners(t *testing.T) {
+	data := signersTestData(1)
+	att := signersTestAtt(data, 0, 2, 3, 5)
+
+	extracted, err := attestation.ExtractSigners(att, bitsAt(2, 4, 5))
+	require.NoError(t, err)
+	assert.DeepEqual(t, signersTestAtt(data, 2, 5), extracted)
+	require.NoError(t, attestation.ValidateSignerOrder(extracted, signersTestCommittee))
+
+	extracted, err = attestation.ExtractSigners(att, bitsAt())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), extracted.AggregationBits.Count())
+
+	_, err = attestation.ExtractSigners(att, bitfield.NewBitlist(4))
+	require.ErrorIs(t, err, bitfield.ErrBitlistDifferentLength)
+
+	// Extracting the signers of another attestation and subtracting them gives back the original
+	// signers.
+	subset, err := attestation.ExtractSigners(att, signersTestAtt(data, 0, 5).AggregationBits)
+	require.NoError(t, err)
+	rest, err := attestation.SubtractSigners(att, subset)
+	require.NoError(t, err)
+	merged, err := attestation.MergeSigners(rest, subset)
+	require.NoError(t, err)
+	assert.DeepEqual(t, att, merged)
+}
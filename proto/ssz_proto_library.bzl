@@ -26,6 +26,9 @@ mainnet = {
     "extra_data.size": "32",
     "max_blobs_per_block.size": "6",
     "max_blob_commitments.size":"4096",
+    "current_sync_committee_branch.depth": "5",
+    "next_sync_committee_branch.depth": "5",
+    "finality_branch.depth": "6",
 }
 
 minimal = {
@@ -47,6 +50,9 @@ minimal = {
     "extra_data.size": "32",
     "max_blobs_per_block.size": "6",
     "max_blob_commitments.size":"16",
+    "current_sync_committee_branch.depth": "5",
+    "next_sync_committee_branch.depth": "5",
+    "finality_branch.depth": "6",
 }
 
 ###### Rules definitions #######
@@ -6,8 +6,10 @@ import (
 )
 
 const (
-	NextSyncCommitteeIndex = uint64(55)
-	FinalizedRootIndex     = uint64(105)
+	CurrentSyncCommitteeIndex = uint64(54)
+	NextSyncCommitteeIndex    = uint64(55)
+	FinalizedRootIndex        = uint64(105)
+	ExecutionPayloadIndex     = uint64(25)
 )
 
 func (x *SyncCommittee) Equals(other *SyncCommittee) bool {
@@ -23,7 +25,7 @@ func (x *SyncCommittee) Equals(other *SyncCommittee) bool {
 }
 
 func FloorLog2(x uint64) int {
-	return bits.Len64(uint64(x - 1))
+	return bits.Len64(x) - 1
 }
 
 func isEmptyWithLength(bb [][]byte, length uint64) bool {
@@ -35,7 +37,7 @@ func isEmptyWithLength(bb [][]byte, length uint64) bool {
 		return false
 	}
 	for _, b := range bb {
-		if !bytes.Equal(b, []byte{}) {
+		if !bytes.Equal(b, make([]byte, 32)) {
 			return false
 		}
 	}
@@ -35,6 +35,7 @@ type componentHandler struct {
 	validatorNodes           e2etypes.MultipleComponentRunners
 	lighthouseBeaconNodes    e2etypes.MultipleComponentRunners
 	lighthouseValidatorNodes e2etypes.MultipleComponentRunners
+	lightClient              e2etypes.ComponentRunner
 }
 
 func NewComponentHandler(cfg *e2etypes.E2EConfig, t *testing.T) *componentHandler {
@@ -234,6 +235,21 @@ func (c *componentHandler) setup() {
 		})
 		c.lighthouseValidatorNodes = lighthouseValidatorNodes
 	}
+
+	if config.UseLightClient {
+		// Light client, following the first beacon node.
+		lightClient := components.NewLightClient()
+		g.Go(func() error {
+			if err := helpers.ComponentsStarted(ctx, []e2etypes.ComponentRunner{beaconNodes}); err != nil {
+				return errors.Wrap(err, "light client requires beacon nodes to run")
+			}
+			if err := lightClient.Start(ctx); err != nil {
+				return errors.Wrap(err, "failed to start light client")
+			}
+			return nil
+		})
+		c.lightClient = lightClient
+	}
 	c.group = g
 }
 
@@ -250,6 +266,9 @@ func (c *componentHandler) required() []e2etypes.ComponentRunner {
 	if multiClientActive {
 		requiredComponents = append(requiredComponents, []e2etypes.ComponentRunner{c.keygen, c.lighthouseBeaconNodes, c.lighthouseValidatorNodes}...)
 	}
+	if c.cfg.UseLightClient {
+		requiredComponents = append(requiredComponents, c.lightClient)
+	}
 	return requiredComponents
 }
 
@@ -0,0 +1,124 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"syscall"
+
+	"github.com/bazelbuild/rules_go/go/tools/bazel"
+	"github.com/pkg/errors"
+	"github.com/theQRL/qrysm/v4/api/client/beacon"
+	cmdshared "github.com/theQRL/qrysm/v4/cmd"
+	"github.com/theQRL/qrysm/v4/cmd/light-client/flags"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/io/file"
+	"github.com/theQRL/qrysm/v4/testing/endtoend/helpers"
+	e2e "github.com/theQRL/qrysm/v4/testing/endtoend/params"
+	e2etypes "github.com/theQRL/qrysm/v4/testing/endtoend/types"
+)
+
+var _ e2etypes.ComponentRunner = (*LightClient)(nil)
+
+// LightClient represents a light client following the first beacon node of the test, starting from the genesis
+// block root.
+type LightClient struct {
+	e2etypes.ComponentRunner
+	started chan struct{}
+	cmd     *exec.Cmd
+}
+
+// NewLightClient creates and returns a light client.
+func NewLightClient() *LightClient {
+	return &LightClient{
+		started: make(chan struct{}, 1),
+	}
+}
+
+// Start starts the light client.
+func (lc *LightClient) Start(ctx context.Context) error {
+	binaryPath, found := bazel.FindBinary("cmd/light-client", "light-client")
+	if !found {
+		log.Info(binaryPath)
+		return errors.New("light client binary not found")
+	}
+	stdOutFile, err := helpers.DeleteAndCreateFile(e2e.TestParams.LogPath, e2e.LightClientLogFileName)
+	if err != nil {
+		return err
+	}
+	cfgDir := path.Join(e2e.TestParams.TestPath, "config/light-client")
+	if err := file.MkdirAll(cfgDir); err != nil {
+		return err
+	}
+	cfgPath := path.Join(cfgDir, "beacon-config.yaml")
+	if err := file.WriteFile(cfgPath, params.ConfigToYaml(params.BeaconConfig().Copy())); err != nil {
+		return err
+	}
+
+	beaconNodeURL := fmt.Sprintf("http://127.0.0.1:%d", e2e.TestParams.Ports.PrysmBeaconNodeGatewayPort)
+	client, err := beacon.NewClient(beaconNodeURL)
+	if err != nil {
+		return err
+	}
+	trustedRoot, err := client.GetBlockRoot(ctx, beacon.IdGenesis)
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis block root")
+	}
+
+	args := []string{
+		fmt.Sprintf("--%s=%s", cmdshared.LogFileName.Name, stdOutFile.Name()),
+		fmt.Sprintf("--%s=%s", cmdshared.VerbosityFlag.Name, "debug"),
+		fmt.Sprintf("--%s=%s", cmdshared.ChainConfigFileFlag.Name, cfgPath),
+		fmt.Sprintf("--%s=%s", flags.BeaconNodeURLFlag.Name, beaconNodeURL),
+		fmt.Sprintf("--%s=%#x", flags.TrustedBlockRootFlag.Name, trustedRoot),
+		fmt.Sprintf("--%s=%d", flags.HTTPPortFlag.Name, e2e.TestParams.Ports.LightClientHTTPPort),
+	}
+	cmd := exec.CommandContext(ctx, binaryPath, args...) // #nosec G204 -- Safe
+	// Write stderr to log files.
+	stderr, err := os.Create(path.Join(e2e.TestParams.LogPath, "light_client_stderr.log"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stderr.Close(); err != nil {
+			log.WithError(err).Error("Failed to close stderr file")
+		}
+	}()
+	cmd.Stderr = stderr
+	log.Infof("Starting light client with flags: %s", strings.Join(args, " "))
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start light client: %w", err)
+	}
+	if err = helpers.WaitForTextInFile(stdOutFile, "Bootstrapped light client"); err != nil {
+		return fmt.Errorf("could not bootstrap light client: %w", err)
+	}
+
+	// Mark light client as ready.
+	close(lc.started)
+
+	lc.cmd = cmd
+	return cmd.Wait()
+}
+
+// Started checks whether the light client is started and ready to be queried.
+func (lc *LightClient) Started() <-chan struct{} {
+	return lc.started
+}
+
+// Pause pauses the component and its underlying process.
+func (lc *LightClient) Pause() error {
+	return lc.cmd.Process.Signal(syscall.SIGSTOP)
+}
+
+// Resume resumes the component and its underlying process.
+func (lc *LightClient) Resume() error {
+	return lc.cmd.Process.Signal(syscall.SIGCONT)
+}
+
+// Stop stops the component and its underlying process.
+func (lc *LightClient) Stop() error {
+	return lc.cmd.Process.Kill()
+}
@@ -2,7 +2,6 @@ package components
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,7 +9,6 @@ import (
 	"os"
 	"os/exec"
 	"path"
-	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -19,25 +17,20 @@ import (
 	"github.com/pkg/errors"
 	"github.com/theQRL/go-zond/common/hexutil"
 	"github.com/theQRL/qrysm/v4/config/params"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
 	"github.com/theQRL/qrysm/v4/io/file"
 	"github.com/theQRL/qrysm/v4/runtime/interop"
 	e2e "github.com/theQRL/qrysm/v4/testing/endtoend/params"
 	e2etypes "github.com/theQRL/qrysm/v4/testing/endtoend/types"
-	"gopkg.in/yaml.v2"
 )
 
 const Web3RemoteSignerPort = 9000
 
-var _ e2etypes.ComponentRunner = (*Web3RemoteSigner)(nil)
+// web3RemoteSignerKeystorePassword decrypts the keystores of the remote signer.
+const web3RemoteSignerKeystorePassword = "password"
 
-// rawKeyFile used for consensys's web3signer config files.
-// See: https://docs.web3signer.consensys.net/en/latest/Reference/Key-Configuration-Files/#raw-unencrypted-files
-type rawKeyFile struct {
-	Type       string `yaml:"type"`       // always "file-raw" for this test.
-	KeyType    string `yaml:"keyType"`    // always "BLS" for this test.
-	PrivateKey string `yaml:"privateKey"` // hex encoded private key with 0x prefix.
-}
+var _ e2etypes.ComponentRunner = (*Web3RemoteSigner)(nil)
 
 type Web3RemoteSigner struct {
 	ctx     context.Context
@@ -51,48 +44,36 @@ func NewWeb3RemoteSigner() *Web3RemoteSigner {
 	}
 }
 
-// Start the web3remotesigner component with a keystore populated with the deterministic validator
-// keys.
+// Start the remote signer component with keystores of the deterministic validator keys.
 func (w *Web3RemoteSigner) Start(ctx context.Context) error {
 	w.ctx = ctx
 
-	binaryPath, found := bazel.FindBinary("", "web3signer")
+	binaryPath, found := bazel.FindBinary("cmd/remote-signer", "remote-signer")
 	if !found {
-		return errors.New("web3signer binary not found")
+		return errors.New("remote signer binary not found")
 	}
 
 	keystorePath := path.Join(bazel.TestTmpDir(), "web3signerkeystore")
 	if err := writeKeystoreKeys(ctx, keystorePath, params.BeaconConfig().MinGenesisActiveValidatorCount); err != nil {
 		return err
 	}
-	websignerDataDir := path.Join(bazel.TestTmpDir(), "web3signerdata")
-	if err := os.MkdirAll(websignerDataDir, 0750); err != nil {
+	passwordFile := path.Join(bazel.TestTmpDir(), "web3signerpassword")
+	if err := file.WriteFile(passwordFile, []byte(web3RemoteSignerKeystorePassword)); err != nil {
 		return err
 	}
-
-	testDir, err := createTestnetDir()
-	if err != nil {
+	websignerDataDir := path.Join(bazel.TestTmpDir(), "web3signerdata")
+	if err := file.MkdirAll(websignerDataDir); err != nil {
 		return err
 	}
 
-	network := "minimal"
-	if len(testDir) > 0 {
-		// A file path to yaml config file is acceptable network argument.
-		network = testDir
-	}
-
 	args := []string{
-		// Global flags
-		fmt.Sprintf("--key-store-path=%s", keystorePath),
-		fmt.Sprintf("--data-path=%s", websignerDataDir),
-		fmt.Sprintf("--http-listen-port=%d", Web3RemoteSignerPort),
-		"--logging=ALL",
-		// Command
-		"eth2",
-		// Command flags
-		"--network=" + network,
-		"--slashing-protection-enabled=false", // Otherwise, a postgres DB is required.
-		"--key-manager-api-enabled=true",
+		fmt.Sprintf("--keystores-dir=%s", keystorePath),
+		fmt.Sprintf("--keystores-password-file=%s", passwordFile),
+		fmt.Sprintf("--slashing-protection-dir=%s", websignerDataDir),
+		fmt.Sprintf("--port=%d", Web3RemoteSignerPort),
+		fmt.Sprintf("--monitoring-port=%d", Web3RemoteSignerPort+1),
+		// The validator client sends full blocks, whose signing root is not computed by the remote signer.
+		"--allow-unverified-signing-roots",
 	}
 
 	cmd := exec.CommandContext(ctx, binaryPath, args...) // #nosec G204 -- Test code is safe to do this.
@@ -109,7 +90,7 @@ func (w *Web3RemoteSigner) Start(ctx context.Context) error {
 	}()
 	cmd.Stderr = stderr
 
-	log.Infof("Starting web3signer with flags: %s %s", binaryPath, strings.Join(args, " "))
+	log.Infof("Starting remote signer with flags: %s %s", binaryPath, strings.Join(args, " "))
 	if err = cmd.Start(); err != nil {
 		return err
 	}
@@ -167,8 +148,8 @@ func (w *Web3RemoteSigner) wait(ctx context.Context) {
 	}
 }
 
-// PublicKeys queries the web3signer and returns the response keys.
-func (w *Web3RemoteSigner) PublicKeys(ctx context.Context) ([]bls.PublicKey, error) {
+// PublicKeys queries the remote signer and returns the response keys.
+func (w *Web3RemoteSigner) PublicKeys(ctx context.Context) ([]dilithium.PublicKey, error) {
 	w.wait(ctx)
 
 	client := &http.Client{}
@@ -197,7 +178,7 @@ func (w *Web3RemoteSigner) PublicKeys(ctx context.Context) ([]bls.PublicKey, err
 		return nil, errors.New("no keys returned")
 	}
 
-	pks := make([]bls.PublicKey, 0, len(keys))
+	pks := make([]dilithium.PublicKey, 0, len(keys))
 	for _, key := range keys {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -206,7 +187,7 @@ func (w *Web3RemoteSigner) PublicKeys(ctx context.Context) ([]bls.PublicKey, err
 		if err != nil {
 			return nil, err
 		}
-		pk, err := bls.PublicKeyFromBytes(raw)
+		pk, err := dilithium.PublicKeyFromBytes(raw)
 		if err != nil {
 			return nil, err
 		}
@@ -215,35 +196,31 @@ func (w *Web3RemoteSigner) PublicKeys(ctx context.Context) ([]bls.PublicKey, err
 	return pks, nil
 }
 
+// writeKeystoreKeys writes a keystore of each of the deterministic validator keys, encrypted with cheap key
+// derivation parameters so the remote signer starts quickly.
 func writeKeystoreKeys(ctx context.Context, keystorePath string, numKeys uint64) error {
-	if err := os.MkdirAll(keystorePath, 0750); err != nil {
+	if err := file.MkdirAll(keystorePath); err != nil {
 		return err
 	}
 
-	priv, pub, err := interop.DeterministicallyGenerateKeys(0, numKeys)
+	priv, _, err := interop.DeterministicallyGenerateKeys(0, numKeys)
 	if err != nil {
 		return err
 	}
-	for i, p := range pub {
-		log.Infof("web3signer file added %s, key index %v", hexutil.Encode(p.Marshal()), i)
-	}
+	kdf := &keystore.KDFParams{Function: keystore.KDFPBKDF2, PBKDF2Iterations: 1024}
 	for i, pk := range priv {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		rkf := &rawKeyFile{
-			Type:       "file-raw",
-			KeyType:    "BLS",
-			PrivateKey: hexutil.Encode(pk.Marshal()),
-		}
-		b, err := yaml.Marshal(rkf)
+		ks, err := keystore.EncryptDilithiumKeystore(pk.Marshal(), web3RemoteSignerKeystorePassword, "", kdf)
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(path.Join(keystorePath, fmt.Sprintf("key-0x%s.yaml", hex.EncodeToString(pub[i].Marshal()))), b, 0600); err != nil {
+		if err := ks.Save(path.Join(keystorePath, fmt.Sprintf("keystore-%d.json", i))); err != nil {
 			return err
 		}
 	}
+	log.Infof("Wrote %d keystores for the remote signer", len(priv))
 
 	return nil
 }
@@ -251,21 +228,3 @@ func writeKeystoreKeys(ctx context.Context, keystorePath string, numKeys uint64)
 func (w *Web3RemoteSigner) UnderlyingProcess() *os.Process {
 	return w.cmd.Process
 }
-
-func createTestnetDir() (string, error) {
-	testNetDir := e2e.TestParams.TestPath + "/web3signer-testnet"
-	configPath := filepath.Join(testNetDir, "config.yaml")
-	rawYaml := params.ConfigToYaml(params.BeaconConfig())
-	// Add in deposit contract in yaml
-	depContractStr := fmt.Sprintf("\nDEPOSIT_CONTRACT_ADDRESS: %s", params.BeaconConfig().DepositContractAddress)
-	rawYaml = append(rawYaml, []byte(depContractStr)...)
-
-	if err := file.MkdirAll(testNetDir); err != nil {
-		return "", err
-	}
-	if err := file.WriteFile(configPath, rawYaml); err != nil {
-		return "", err
-	}
-
-	return configPath, nil
-}
@@ -12,6 +12,8 @@ import (
 )
 
 func TestWeb3RemoteSigner_StartsAndReturnsPublicKeys(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	params.OverrideBeaconConfig(params.E2ETestConfig().Copy())
 	require.NoError(t, e2eparams.Init(t, 0))
 
 	wsc := components.NewWeb3RemoteSigner()
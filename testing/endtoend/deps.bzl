@@ -4,14 +4,6 @@ lighthouse_version = "v4.0.1"
 lighthouse_archive_name = "lighthouse-%s-x86_64-unknown-linux-gnu-portable.tar.gz" % lighthouse_version
 
 def e2e_deps():
-    http_archive(
-        name = "web3signer",
-        urls = ["https://artifacts.consensys.net/public/web3signer/raw/names/web3signer.tar.gz/versions/23.3.1/web3signer-23.3.1.tar.gz"],
-        sha256 = "32dfbfd8d5900f19aa426d3519724dd696e6529b7ec2f99e0cb1690dae52b3d6",
-        build_file = "@qrysm//testing/endtoend:web3signer.BUILD",
-        strip_prefix = "web3signer-23.3.1",
-    )
-
     http_archive(
         name = "lighthouse",
         sha256 = "bb41eaa2f01b1231c1a8b24f1b6296c134c654ecc2b24c7f2c877f97420503f1",
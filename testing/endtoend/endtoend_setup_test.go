@@ -90,6 +90,9 @@ func e2eMinimal(t *testing.T, v int, cfgo ...types.E2EConfigOpt) *testRunner {
 	if testConfig.UseBuilder {
 		testConfig.Evaluators = append(testConfig.Evaluators, ev.BuilderIsActive)
 	}
+	if testConfig.UseLightClient {
+		testConfig.Evaluators = append(testConfig.Evaluators, ev.LightClientFollowsFinality)
+	}
 
 	return newTestRunner(t, testConfig)
 }
@@ -173,6 +176,9 @@ func e2eMainnet(t *testing.T, usePrysmSh, useMultiClient bool, cfg *params.Beaco
 	if testConfig.UseBuilder {
 		testConfig.Evaluators = append(testConfig.Evaluators, ev.BuilderIsActive)
 	}
+	if testConfig.UseLightClient {
+		testConfig.Evaluators = append(testConfig.Evaluators, ev.LightClientFollowsFinality)
+	}
 	return newTestRunner(t, testConfig)
 }
 
@@ -0,0 +1,80 @@
+package evaluators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	lightclient "github.com/theQRL/qrysm/v4/light-client"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	e2e "github.com/theQRL/qrysm/v4/testing/endtoend/params"
+	"github.com/theQRL/qrysm/v4/testing/endtoend/policies"
+	e2etypes "github.com/theQRL/qrysm/v4/testing/endtoend/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// LightClientFollowsFinality checks that the light client finalized a block of the canonical chain, without
+// running ahead of the beacon node it follows.
+var LightClientFollowsFinality = e2etypes.Evaluator{
+	Name:       "light_client_follows_finality_%d",
+	Policy:     policies.AfterNthEpoch(4),
+	Evaluation: lightClientFollowsFinality,
+}
+
+func lightClientFollowsFinality(_ *e2etypes.EvaluationContext, conns ...*grpc.ClientConn) error {
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/lightclient/v1/headers/finalized", e2e.TestParams.Ports.LightClientHTTPPort))
+	if err != nil {
+		return errors.Wrap(err, "could not request light client finalized header")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("expected status code OK for light client, received %v with body %s", resp.StatusCode, body)
+	}
+	header := &lightclient.HeaderResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(header); err != nil {
+		return errors.Wrap(err, "could not decode light client finalized header")
+	}
+	if err = resp.Body.Close(); err != nil {
+		return err
+	}
+	slot, err := strconv.ParseUint(header.Data.Slot, 10, 64)
+	if err != nil {
+		return err
+	}
+	if slot == 0 {
+		return errors.New("light client did not finalize any block after genesis")
+	}
+	root, err := hexutil.Decode(header.Data.Root)
+	if err != nil {
+		return err
+	}
+
+	client := zondpb.NewBeaconChainClient(conns[0])
+	head, err := client.GetChainHead(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get chain head")
+	}
+	if primitives.Slot(slot) > head.FinalizedSlot {
+		return fmt.Errorf("light client finalized slot %d is ahead of the beacon node finalized slot %d", slot, head.FinalizedSlot)
+	}
+	blks, err := client.ListBeaconBlocks(context.Background(), &zondpb.ListBlocksRequest{
+		QueryFilter: &zondpb.ListBlocksRequest_Root{Root: root},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not get light client finalized block %#x", root)
+	}
+	if len(blks.BlockContainers) != 1 || !blks.BlockContainers[0].Canonical {
+		return fmt.Errorf("light client finalized block %#x at slot %d is not canonical", root, slot)
+	}
+	return nil
+}
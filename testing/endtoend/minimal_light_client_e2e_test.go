@@ -0,0 +1,13 @@
+package endtoend
+
+import (
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/runtime/version"
+	"github.com/theQRL/qrysm/v4/testing/endtoend/types"
+)
+
+func TestEndToEnd_MinimalConfig_WithLightClient(t *testing.T) {
+	r := e2eMinimal(t, version.Phase0, types.WithLightClient())
+	r.run()
+}
@@ -56,6 +56,7 @@ type ports struct {
 	ValidatorMetricsPort            int
 	ValidatorGatewayPort            int
 	JaegerTracingPort               int
+	LightClientHTTPPort             int
 }
 
 type paths struct{}
@@ -111,6 +112,9 @@ var BeaconNodeLogFileName = "beacon-%d.log"
 // ValidatorLogFileName is the file name used for the validator client logs.
 var ValidatorLogFileName = "vals-%d.log"
 
+// LightClientLogFileName is the file name used for the light client logs.
+var LightClientLogFileName = "light-client.log"
+
 // StandardBeaconCount is a global constant for the count of beacon nodes of standard E2E tests.
 var StandardBeaconCount = 2
 
@@ -158,6 +162,8 @@ const (
 
 	JaegerTracingPort = 9150
 
+	LightClientHTTPPort = 7150
+
 	StartupBufferSecs = 15
 )
 
@@ -358,6 +364,10 @@ func initializeStandardPorts(shardCount, shardIndex int, ports *ports, existingR
 	if err != nil {
 		return err
 	}
+	lightClientHTTPPort, err := port(LightClientHTTPPort, shardCount, shardIndex, existingRegistrations)
+	if err != nil {
+		return err
+	}
 	ports.BootNodePort = bootnodePort
 	ports.BootNodeMetricsPort = bootnodeMetricsPort
 	ports.Eth1Port = eth1Port
@@ -374,6 +384,7 @@ func initializeStandardPorts(shardCount, shardIndex int, ports *ports, existingR
 	ports.ValidatorMetricsPort = validatorMetricsPort
 	ports.ValidatorGatewayPort = validatorGatewayPort
 	ports.JaegerTracingPort = jaegerTracingPort
+	ports.LightClientHTTPPort = lightClientHTTPPort
 	return nil
 }
 
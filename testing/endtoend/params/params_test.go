@@ -30,10 +30,11 @@ func TestStandardPorts(t *testing.T) {
 	var existingRegistrations []int
 	testPorts := &ports{}
 	assert.NoError(t, initializeStandardPorts(2, 0, testPorts, &existingRegistrations))
-	assert.Equal(t, 16, len(existingRegistrations))
+	assert.Equal(t, 17, len(existingRegistrations))
 	assert.NotEqual(t, 0, testPorts.PrysmBeaconNodeGatewayPort)
 	assert.NotEqual(t, 0, testPorts.PrysmBeaconNodeTCPPort)
 	assert.NotEqual(t, 0, testPorts.JaegerTracingPort)
+	assert.NotEqual(t, 0, testPorts.LightClientHTTPPort)
 }
 
 func TestMulticlientPorts(t *testing.T) {
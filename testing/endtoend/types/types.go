@@ -51,6 +51,14 @@ func WithBuilder() E2EConfigOpt {
 	}
 }
 
+// WithLightClient runs a light client following the first beacon node, which serves light client updates.
+func WithLightClient() E2EConfigOpt {
+	return func(cfg *E2EConfig) {
+		cfg.UseLightClient = true
+		cfg.BeaconFlags = append(cfg.BeaconFlags, "--enable-lightclient")
+	}
+}
+
 // E2EConfig defines the struct for all configurations needed for E2E testing.
 type E2EConfig struct {
 	TestCheckpointSync      bool
@@ -64,6 +72,7 @@ type E2EConfig struct {
 	UseValidatorCrossClient bool
 	UseBeaconRestApi        bool
 	UseBuilder              bool
+	UseLightClient          bool
 	EpochsToRun             uint64
 	Seed                    int64
 	TracingSinkEndpoint     string
@@ -0,0 +1,51 @@
+package dilithium
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestBatchVerify(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "batch_verify")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &BatchVerifyTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+
+			pubKeys := make([][]dilithium.PublicKey, len(test.Input.Pubkeys))
+			for i, signers := range test.Input.Pubkeys {
+				pubKeys[i] = make([]dilithium.PublicKey, len(signers))
+				for j, pubKey := range signers {
+					pkBytes, err := hex.DecodeString(pubKey[2:])
+					require.NoError(t, err)
+					pubKeys[i][j], err = dilithium.PublicKeyFromBytes(pkBytes)
+					require.NoError(t, err)
+				}
+			}
+			msgs := make([][32]byte, len(test.Input.Messages))
+			for i, msg := range test.Input.Messages {
+				msgBytes, err := hex.DecodeString(msg[2:])
+				require.NoError(t, err)
+				msgs[i] = bytesutil.ToBytes32(msgBytes)
+			}
+			sigs := make([][]byte, len(test.Input.Signatures))
+			for i, sig := range test.Input.Signatures {
+				sigBytes, err := hex.DecodeString(sig[2:])
+				require.NoError(t, err)
+				sigs[i] = sigBytes
+			}
+
+			verified, err := dilithium.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+			if err != nil {
+				require.Equal(t, false, test.Output, "Could not verify batch: %v", err)
+				return
+			}
+			require.Equal(t, test.Output, verified)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package dilithium
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestDeserializationPubkey(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "deserialization_pubkey")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &DeserializationPubkeyTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+			pkBytes, err := hex.DecodeString(test.Input.Pubkey[2:])
+			require.NoError(t, err)
+			_, err = dilithium.PublicKeyFromBytes(pkBytes)
+			require.Equal(t, test.Output, err == nil)
+		})
+	}
+}
+
+func TestDeserializationSignature(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "deserialization_signature")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &DeserializationSignatureTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+			sigBytes, err := hex.DecodeString(test.Input.Signature[2:])
+			require.NoError(t, err)
+			_, err = dilithium.SignatureFromBytes(sigBytes)
+			require.Equal(t, test.Output, err == nil)
+		})
+	}
+}
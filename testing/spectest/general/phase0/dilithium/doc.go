@@ -0,0 +1,7 @@
+// Package dilithium includes known-answer tests of the signature scheme behind crypto/dilithium.
+//
+// Unlike the BLS vectors, the vectors are not part of the consensus spec tests. They are
+// generated by //tools/dilithium-vectors-gen from a reference implementation of each scheme, which
+// does not share code with crypto/dilithium, and checked into testdata/<scheme>/<handler>, with one
+// folder per scheme, so that the suite runs against the scheme selected at build time.
+package dilithium
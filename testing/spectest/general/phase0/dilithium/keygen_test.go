@@ -0,0 +1,37 @@
+package dilithium
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestKeygen(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "keygen")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &KeygenTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+			seed, err := hex.DecodeString(test.Input.Seed[2:])
+			require.NoError(t, err)
+			sk, err := dilithium.SecretKeyFromBytes(seed)
+			if test.Output == "" {
+				require.NotNil(t, err, "Secret key of invalid seed %#x was derived", seed)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, true, bytes.Equal(seed, sk.Marshal()), "Secret key does not marshal to its seed")
+
+			outputBytes, err := hex.DecodeString(test.Output[2:])
+			require.NoError(t, err)
+			if !bytes.Equal(outputBytes, sk.PublicKey().Marshal()) {
+				t.Fatalf("Public key does not match the expected output. Expected %#x but received %#x",
+					outputBytes, sk.PublicKey().Marshal())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package dilithium
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestSign(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "sign")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &SignMsgTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+			seed, err := hex.DecodeString(test.Input.Seed[2:])
+			require.NoError(t, err)
+			sk, err := dilithium.SecretKeyFromBytes(seed)
+			require.NoError(t, err)
+			msgBytes, err := hex.DecodeString(test.Input.Message[2:])
+			require.NoError(t, err)
+			sig := sk.Sign(msgBytes)
+
+			if !sig.Verify(sk.PublicKey(), msgBytes) {
+				t.Fatal("could not verify signature")
+			}
+
+			outputBytes, err := hex.DecodeString(test.Output[2:])
+			require.NoError(t, err)
+			if !bytes.Equal(outputBytes, sig.Marshal()) {
+				t.Fatalf("Signature does not match the expected output. Expected %#x but received %#x",
+					outputBytes, sig.Marshal())
+			}
+		})
+	}
+}
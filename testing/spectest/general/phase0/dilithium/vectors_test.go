@@ -0,0 +1,28 @@
+package dilithium
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+// testFolders returns the test cases of the handler for the signature scheme of the build, along
+// with the path of the folder holding them.
+func testFolders(t *testing.T, handler string) ([]os.DirEntry, string) {
+	folderPath := path.Join("testdata", dilithium.CurrentScheme().ID().String(), handler)
+	testFolders, err := os.ReadDir(folderPath)
+	require.NoError(t, err)
+	if len(testFolders) == 0 {
+		t.Fatalf("No test folders found at %s", folderPath)
+	}
+	return testFolders, folderPath
+}
+
+func testData(t *testing.T, folderPath, name string) []byte {
+	file, err := os.ReadFile(path.Join(folderPath, name, "data.yaml")) // #nosec G304
+	require.NoError(t, err)
+	return file
+}
@@ -0,0 +1,38 @@
+package dilithium
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+func TestVerify(t *testing.T) {
+	testFolders, testFolderPath := testFolders(t, "verify")
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			test := &VerifyMsgTest{}
+			require.NoError(t, yaml.Unmarshal(testData(t, testFolderPath, folder.Name()), test))
+			pkBytes, err := hex.DecodeString(test.Input.Pubkey[2:])
+			require.NoError(t, err)
+			pk, err := dilithium.PublicKeyFromBytes(pkBytes)
+			if err != nil {
+				require.Equal(t, false, test.Output, "Could not deserialize public key: %v", err)
+				return
+			}
+			sigBytes, err := hex.DecodeString(test.Input.Signature[2:])
+			require.NoError(t, err)
+			sig, err := dilithium.SignatureFromBytes(sigBytes)
+			if err != nil {
+				require.Equal(t, false, test.Output, "Could not deserialize signature: %v", err)
+				return
+			}
+			msgBytes, err := hex.DecodeString(test.Input.Message[2:])
+			require.NoError(t, err)
+
+			require.Equal(t, test.Output, sig.Verify(pk, msgBytes))
+		})
+	}
+}
@@ -21,13 +21,13 @@ import (
 	"github.com/theQRL/qrysm/v4/config/params"
 	consensusblocks "github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
 )
 
 // DeterministicGenesisStateAltair returns a genesis state in hard fork 1 format made using the deterministic deposits.
-func DeterministicGenesisStateAltair(t testing.TB, numValidators uint64) (state.BeaconState, []bls.SecretKey) {
+func DeterministicGenesisStateAltair(t testing.TB, numValidators uint64) (state.BeaconState, []dilithium.DilithiumKey) {
 	deposits, privKeys, err := DeterministicDepositsAndKeys(numValidators)
 	if err != nil {
 		t.Fatal(errors.Wrapf(err, "failed to get %d deposits", numValidators))
@@ -278,8 +278,8 @@ func NewBeaconBlockAltair() *zondpb.SignedBeaconBlockAltair {
 func BlockSignatureAltair(
 	bState state.BeaconState,
 	block *zondpb.BeaconBlockAltair,
-	privKeys []bls.SecretKey,
-) (bls.Signature, error) {
+	privKeys []dilithium.DilithiumKey,
+) (dilithium.Signature, error) {
 	var err error
 	wsb, err := consensusblocks.NewSignedBeaconBlock(&zondpb.SignedBeaconBlockAltair{Block: block})
 	if err != nil {
@@ -318,7 +318,7 @@ func BlockSignatureAltair(
 // Use BlockGenConfig to declare the conditions you would like the block generated under.
 func GenerateFullBlockAltair(
 	bState state.BeaconState,
-	privs []bls.SecretKey,
+	privs []dilithium.DilithiumKey,
 	conf *BlockGenConfig,
 	slot primitives.Slot,
 ) (*zondpb.SignedBeaconBlockAltair, error) {
@@ -16,7 +16,7 @@ import (
 	"github.com/theQRL/qrysm/v4/consensus-types/blocks"
 	"github.com/theQRL/qrysm/v4/consensus-types/interfaces"
 	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
-	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
 	"github.com/theQRL/qrysm/v4/crypto/rand"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	enginev1 "github.com/theQRL/qrysm/v4/proto/engine/v1"
@@ -95,7 +95,7 @@ func NewBlobsidecar() *zondpb.SignedBlobSidecar {
 // Use BlockGenConfig to declare the conditions you would like the block generated under.
 func GenerateFullBlock(
 	bState state.BeaconState,
-	privs []bls.SecretKey,
+	privs []dilithium.DilithiumKey,
 	conf *BlockGenConfig,
 	slot primitives.Slot,
 ) (*zondpb.SignedBeaconBlock, error) {
@@ -217,7 +217,7 @@ func GenerateFullBlock(
 // GenerateProposerSlashingForValidator for a specific validator index.
 func GenerateProposerSlashingForValidator(
 	bState state.BeaconState,
-	priv bls.SecretKey,
+	priv dilithium.DilithiumKey,
 	idx primitives.ValidatorIndex,
 ) (*zondpb.ProposerSlashing, error) {
 	header1 := HydrateSignedBeaconHeader(&zondpb.SignedBeaconBlockHeader{
@@ -256,7 +256,7 @@ func GenerateProposerSlashingForValidator(
 
 func generateProposerSlashings(
 	bState state.BeaconState,
-	privs []bls.SecretKey,
+	privs []dilithium.DilithiumKey,
 	numSlashings uint64,
 ) ([]*zondpb.ProposerSlashing, error) {
 	proposerSlashings := make([]*zondpb.ProposerSlashing, numSlashings)
@@ -277,7 +277,7 @@ func generateProposerSlashings(
 // GenerateAttesterSlashingForValidator for a specific validator index.
 func GenerateAttesterSlashingForValidator(
 	bState state.BeaconState,
-	priv bls.SecretKey,
+	priv dilithium.DilithiumKey,
 	idx primitives.ValidatorIndex,
 ) (*zondpb.AttesterSlashing, error) {
 	currentEpoch := time.CurrentEpoch(bState)
@@ -333,7 +333,7 @@ func GenerateAttesterSlashingForValidator(
 
 func generateAttesterSlashings(
 	bState state.BeaconState,
-	privs []bls.SecretKey,
+	privs []dilithium.DilithiumKey,
 	numSlashings uint64,
 ) ([]*zondpb.AttesterSlashing, error) {
 	attesterSlashings := make([]*zondpb.AttesterSlashing, numSlashings)
@@ -375,7 +375,7 @@ func generateDepositsAndEth1Data(
 	return currentDeposits[previousDepsLen:], eth1Data, nil
 }
 
-func GenerateVoluntaryExits(bState state.BeaconState, k bls.SecretKey, idx primitives.ValidatorIndex) (*zondpb.SignedVoluntaryExit, error) {
+func GenerateVoluntaryExits(bState state.BeaconState, k dilithium.DilithiumKey, idx primitives.ValidatorIndex) (*zondpb.SignedVoluntaryExit, error) {
 	currentEpoch := time.CurrentEpoch(bState)
 	exit := &zondpb.SignedVoluntaryExit{
 		Exit: &zondpb.VoluntaryExit{
@@ -393,7 +393,7 @@ func GenerateVoluntaryExits(bState state.BeaconState, k bls.SecretKey, idx primi
 
 func generateVoluntaryExits(
 	bState state.BeaconState,
-	privs []bls.SecretKey,
+	privs []dilithium.DilithiumKey,
 	numExits uint64,
 ) ([]*zondpb.SignedVoluntaryExit, error) {
 	currentEpoch := time.CurrentEpoch(bState)
@@ -0,0 +1,374 @@
+// Package main generates the known-answer test vectors of the signature schemes behind
+// crypto/dilithium, which are checked into testing/spectest/general/phase0/dilithium/testdata.
+//
+// The expected outputs are computed with a reference implementation of each scheme, which does not
+// share code with crypto/dilithium, so that the vectors catch regressions of the implementation
+// they test rather than record its current behaviour. The dilithium vectors are computed with
+// go-qrllib, called directly instead of through crypto/dilithium/dilithiumt. The ml-dsa-87
+// vectors are computed with crypto/mldsa of the Go standard library, which is validated against
+// the NIST ACVP vectors of FIPS 204 and is only available when building with Go 1.27 or later:
+//
+//	go run ./tools/dilithium-vectors-gen --scheme=dilithium --output-dir=$PWD/testing/spectest/general/phase0/dilithium/testdata --overwrite
+//	go run ./tools/dilithium-vectors-gen --scheme=ml-dsa-87 --output-dir=$PWD/testing/spectest/general/phase0/dilithium/testdata --overwrite
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/io/file"
+	vectors "github.com/theQRL/qrysm/v4/testing/spectest/general/phase0/dilithium"
+)
+
+var (
+	scheme    = flag.String("scheme", "dilithium", "Signature scheme to generate the test vectors of, dilithium or ml-dsa-87")
+	outputDir = flag.String("output-dir", "", "Directory to write the test vectors to")
+	overwrite = flag.Bool("overwrite", false, "If test vectors of the scheme exist in the output directory, they will be replaced")
+)
+
+func main() {
+	flag.Parse()
+	if *outputDir == "" {
+		log.Fatal("Please specify --output-dir to write the test vectors to")
+	}
+	ref, err := referenceOf(*scheme)
+	if err != nil {
+		log.WithError(err).Fatal("Could not get the reference implementation of the scheme")
+	}
+
+	schemeDir := path.Join(*outputDir, *scheme)
+	exists, err := file.HasDir(schemeDir)
+	if err != nil {
+		log.WithError(err).Fatal("Could not check the output directory")
+	}
+	if exists {
+		if !*overwrite {
+			log.Fatal("The test vectors exist. Use a different output directory or the --overwrite flag")
+		}
+		// Remove the previous vectors so that no stale test case is left behind.
+		if err := os.RemoveAll(schemeDir); err != nil {
+			log.WithError(err).Fatal("Could not remove previous test vectors")
+		}
+	}
+
+	log.Printf("Generating test vectors of the %s scheme in %s", *scheme, schemeDir)
+	g := &generator{dir: schemeDir, ref: ref}
+	for _, gen := range []func() error{
+		g.keygen,
+		g.sign,
+		g.verify,
+		g.batchVerify,
+		g.deserialization,
+	} {
+		if err := gen(); err != nil {
+			log.WithError(err).Fatal("Could not generate test vectors")
+		}
+	}
+}
+
+type generator struct {
+	dir string
+	ref reference
+}
+
+// write saves the test case as <handler>/<name>/data.yaml.
+func (g *generator) write(handler, name string, test interface{}) error {
+	enc, err := yaml.Marshal(test)
+	if err != nil {
+		return err
+	}
+	caseDir := path.Join(g.dir, handler, name)
+	if err := file.MkdirAll(caseDir); err != nil {
+		return err
+	}
+	return file.WriteFile(path.Join(caseDir, "data.yaml"), enc)
+}
+
+// seed returns the i-th deterministic key seed.
+func (g *generator) seed(i int) []byte {
+	s := make([]byte, g.ref.seedLength())
+	for j := range s {
+		s[j] = byte(i*len(s) + j)
+	}
+	return s
+}
+
+func message(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func (g *generator) keygen() error {
+	cases := []struct {
+		name string
+		seed []byte
+	}{
+		{name: "zero_seed", seed: make([]byte, g.ref.seedLength())},
+		{name: "seed_0", seed: g.seed(0)},
+		{name: "seed_1", seed: g.seed(1)},
+		{name: "max_seed", seed: bytes.Repeat([]byte{0xff}, g.ref.seedLength())},
+		{name: "empty_seed", seed: []byte{}},
+		{name: "short_seed", seed: g.seed(0)[:32]},
+		{name: "long_seed", seed: append(g.seed(0), 0)},
+	}
+	for _, c := range cases {
+		test := &vectors.KeygenTest{}
+		test.Input.Seed = hexutil.Encode(c.seed)
+		// Invalid seeds have no output.
+		if len(c.seed) == g.ref.seedLength() {
+			pk, err := g.ref.publicKey(c.seed)
+			if err != nil {
+				return err
+			}
+			test.Output = hexutil.Encode(pk)
+		}
+		if err := g.write("keygen", c.name, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) sign() error {
+	cases := []struct {
+		name string
+		key  int
+		msg  []byte
+	}{
+		{name: "empty_message", key: 0, msg: []byte{}},
+		{name: "zero_message", key: 1, msg: message(0x00)},
+		{name: "message_0xab", key: 2, msg: message(0xab)},
+		{name: "long_message", key: 0, msg: bytes.Repeat([]byte("qrysm"), 100)},
+	}
+	for _, c := range cases {
+		sig, err := g.ref.sign(g.seed(c.key), c.msg)
+		if err != nil {
+			return err
+		}
+		test := &vectors.SignMsgTest{}
+		test.Input.Seed = hexutil.Encode(g.seed(c.key))
+		test.Input.Message = hexutil.Encode(c.msg)
+		test.Output = hexutil.Encode(sig)
+		if err := g.write("sign", c.name, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) verify() error {
+	pk, err := g.ref.publicKey(g.seed(0))
+	if err != nil {
+		return err
+	}
+	otherPk, err := g.ref.publicKey(g.seed(1))
+	if err != nil {
+		return err
+	}
+	msg := message(0x56)
+	sig, err := g.ref.sign(g.seed(0), msg)
+	if err != nil {
+		return err
+	}
+	tamper := func(f func(s []byte)) []byte {
+		s := bytes.Clone(sig)
+		f(s)
+		return s
+	}
+
+	cases := []struct {
+		name  string
+		pk    []byte
+		msg   []byte
+		sig   []byte
+		valid bool
+	}{
+		{name: "valid", pk: pk, msg: msg, sig: sig, valid: true},
+		{name: "wrong_message", pk: pk, msg: message(0x57), sig: sig},
+		{name: "wrong_pubkey", pk: otherPk, msg: msg, sig: sig},
+		{name: "tampered_challenge", pk: pk, msg: msg, sig: tamper(func(s []byte) { s[0] ^= 1 })},
+		{name: "tampered_response", pk: pk, msg: msg, sig: tamper(func(s []byte) { s[len(s)/2] ^= 1 })},
+		// The hints are encoded at the end of the signature, with the number of hints of the last
+		// polynomial in the last byte. 0xff is above the bound of the number of hints.
+		{name: "tampered_hint", pk: pk, msg: msg, sig: tamper(func(s []byte) { s[len(s)-1] = 0xff })},
+		{name: "zero_signature", pk: pk, msg: msg, sig: make([]byte, len(sig))},
+		{name: "truncated_signature", pk: pk, msg: msg, sig: sig[:len(sig)-1]},
+		{name: "empty_signature", pk: pk, msg: msg, sig: []byte{}},
+		{name: "truncated_pubkey", pk: pk[:len(pk)-1], msg: msg, sig: sig},
+	}
+	for _, c := range cases {
+		// The output is the verdict of the reference implementation, the expected verdict only
+		// guards against a test case which does not test what its name says.
+		output := g.ref.verify(c.pk, c.msg, c.sig)
+		if output != c.valid {
+			return errors.Errorf("reference implementation verified test case %s as %t", c.name, output)
+		}
+		test := &vectors.VerifyMsgTest{}
+		test.Input.Pubkey = hexutil.Encode(c.pk)
+		test.Input.Message = hexutil.Encode(c.msg)
+		test.Input.Signature = hexutil.Encode(c.sig)
+		test.Output = output
+		if err := g.write("verify", c.name, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) batchVerify() error {
+	pks := make([][]byte, 3)
+	for i := range pks {
+		pk, err := g.ref.publicKey(g.seed(i))
+		if err != nil {
+			return err
+		}
+		pks[i] = pk
+	}
+	msgs := [][]byte{message(0x01), message(0x02)}
+	// sign returns the signatures of the signers of the message, concatenated in the order of the
+	// signers.
+	sign := func(msg []byte, signers ...int) ([]byte, error) {
+		var sigs []byte
+		for _, s := range signers {
+			sig, err := g.ref.sign(g.seed(s), msg)
+			if err != nil {
+				return nil, err
+			}
+			sigs = append(sigs, sig...)
+		}
+		return sigs, nil
+	}
+
+	type signed struct {
+		msg     int
+		signers []int
+	}
+	cases := []struct {
+		name    string
+		signers [][]int
+		sigs    []signed
+		valid   bool
+	}{
+		{
+			name:    "single_signer",
+			signers: [][]int{{0}},
+			sigs:    []signed{{0, []int{0}}},
+			valid:   true,
+		},
+		{
+			name:    "multiple_signers",
+			signers: [][]int{{0}, {1, 2}},
+			sigs:    []signed{{0, []int{0}}, {1, []int{1, 2}}},
+			valid:   true,
+		},
+		{
+			name:    "invalid_signature",
+			signers: [][]int{{0}, {1, 2}},
+			sigs:    []signed{{0, []int{0}}, {0, []int{1, 2}}},
+		},
+		{
+			name:    "swapped_signers",
+			signers: [][]int{{0}, {1, 2}},
+			sigs:    []signed{{0, []int{0}}, {1, []int{2, 1}}},
+		},
+		{
+			name:    "missing_signature",
+			signers: [][]int{{0}, {1, 2}},
+			sigs:    []signed{{0, []int{0}}, {1, []int{1}}},
+		},
+	}
+	for _, c := range cases {
+		test := &vectors.BatchVerifyTest{}
+		output := true
+		for i, signers := range c.signers {
+			sigs, err := sign(msgs[c.sigs[i].msg], c.sigs[i].signers...)
+			if err != nil {
+				return err
+			}
+			hexPks := make([]string, len(signers))
+			for j, s := range signers {
+				hexPks[j] = hexutil.Encode(pks[s])
+			}
+			test.Input.Pubkeys = append(test.Input.Pubkeys, hexPks)
+			test.Input.Messages = append(test.Input.Messages, hexutil.Encode(msgs[i]))
+			test.Input.Signatures = append(test.Input.Signatures, hexutil.Encode(sigs))
+			output = output && g.verifySigners(pks, signers, msgs[i], sigs)
+		}
+		if output != c.valid {
+			return errors.Errorf("reference implementation verified test case %s as %t", c.name, output)
+		}
+		test.Output = output
+		if err := g.write("batch_verify", c.name, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySigners verifies the concatenated signatures of the signers of the message with the
+// reference implementation.
+func (g *generator) verifySigners(pks [][]byte, signers []int, msg, sigs []byte) bool {
+	sigLen := g.ref.signatureLength()
+	if len(sigs) != len(signers)*sigLen {
+		return false
+	}
+	for i, s := range signers {
+		if !g.ref.verify(pks[s], msg, sigs[i*sigLen:(i+1)*sigLen]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) deserialization() error {
+	pk, err := g.ref.publicKey(g.seed(0))
+	if err != nil {
+		return err
+	}
+	sig, err := g.ref.sign(g.seed(0), message(0x56))
+	if err != nil {
+		return err
+	}
+	if len(pk) != g.ref.publicKeyLength() || len(sig) != g.ref.signatureLength() {
+		return errors.New("scheme sizes do not match its keys and signatures")
+	}
+
+	for _, c := range []struct {
+		name string
+		b    []byte
+	}{
+		{name: "valid", b: pk},
+		{name: "empty", b: []byte{}},
+		{name: "short", b: pk[:len(pk)-1]},
+		{name: "long", b: append(bytes.Clone(pk), 0)},
+	} {
+		test := &vectors.DeserializationPubkeyTest{}
+		test.Input.Pubkey = hexutil.Encode(c.b)
+		test.Output = len(c.b) == g.ref.publicKeyLength()
+		if err := g.write("deserialization_pubkey", c.name, test); err != nil {
+			return err
+		}
+	}
+	for _, c := range []struct {
+		name string
+		b    []byte
+	}{
+		{name: "valid", b: sig},
+		{name: "empty", b: []byte{}},
+		{name: "short", b: sig[:len(sig)-1]},
+		{name: "long", b: append(bytes.Clone(sig), 0)},
+	} {
+		test := &vectors.DeserializationSignatureTest{}
+		test.Input.Signature = hexutil.Encode(c.b)
+		test.Output = len(c.b) == g.ref.signatureLength()
+		if err := g.write("deserialization_signature", c.name, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+	qrllibcommon "github.com/theQRL/go-qrllib/common"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+)
+
+// reference is an implementation of a signature scheme which the expected outputs of the vectors
+// are computed with.
+type reference interface {
+	seedLength() int
+	publicKeyLength() int
+	signatureLength() int
+	// publicKey derives the public key of the seed.
+	publicKey(seed []byte) ([]byte, error)
+	// sign signs the message with the key derived from the seed.
+	sign(seed, msg []byte) ([]byte, error)
+	// verify verifies the signature of the message, and rejects malformed inputs.
+	verify(pk, msg, sig []byte) bool
+}
+
+// referenceOf returns the reference implementation of the scheme with the given name.
+func referenceOf(scheme string) (reference, error) {
+	switch scheme {
+	case "dilithium":
+		return qrllibDilithium{}, nil
+	case "ml-dsa-87":
+		return stdlibMLDSA87()
+	default:
+		return nil, errors.Errorf("unknown scheme %q", scheme)
+	}
+}
+
+// qrllibDilithium is the round 3 Dilithium5 implementation of go-qrllib.
+type qrllibDilithium struct{}
+
+func (qrllibDilithium) seedLength() int {
+	return qrllibcommon.SeedSize
+}
+
+func (qrllibDilithium) publicKeyLength() int {
+	return dilithium2.CryptoPublicKeyBytes
+}
+
+func (qrllibDilithium) signatureLength() int {
+	return dilithium2.CryptoBytes
+}
+
+func (qrllibDilithium) key(seed []byte) (*dilithium2.Dilithium, error) {
+	var s [qrllibcommon.SeedSize]uint8
+	if len(seed) != len(s) {
+		return nil, errors.Errorf("seed must be %d bytes", len(s))
+	}
+	copy(s[:], seed)
+	return dilithium2.NewDilithiumFromSeed(s)
+}
+
+func (r qrllibDilithium) publicKey(seed []byte) ([]byte, error) {
+	d, err := r.key(seed)
+	if err != nil {
+		return nil, err
+	}
+	pk := d.GetPK()
+	return pk[:], nil
+}
+
+func (r qrllibDilithium) sign(seed, msg []byte) ([]byte, error) {
+	d, err := r.key(seed)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := d.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:], nil
+}
+
+func (qrllibDilithium) verify(pk, msg, sig []byte) bool {
+	var p [dilithium2.CryptoPublicKeyBytes]uint8
+	var s [dilithium2.CryptoBytes]uint8
+	if len(pk) != len(p) || len(sig) != len(s) {
+		return false
+	}
+	copy(p[:], pk)
+	copy(s[:], sig)
+	return dilithium2.Verify(msg, s, &p)
+}
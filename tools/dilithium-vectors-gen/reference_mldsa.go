@@ -0,0 +1,65 @@
+//go:build go1.27
+
+package main
+
+import (
+	"crypto/mldsa"
+	"crypto/sha3"
+
+	"github.com/pkg/errors"
+)
+
+// mldsaSeedLength is the size of the seeds that crypto/dilithium/mldsa derives the seed ξ of
+// ML-DSA-87 keys from, by hashing them with SHAKE256.
+const mldsaSeedLength = 48
+
+// stdlibMLDSA is the ML-DSA-87 implementation of the Go standard library. It signs
+// deterministically with an empty context, as crypto/dilithium/mldsa does.
+type stdlibMLDSA struct{}
+
+func stdlibMLDSA87() (reference, error) {
+	return stdlibMLDSA{}, nil
+}
+
+func (stdlibMLDSA) seedLength() int {
+	return mldsaSeedLength
+}
+
+func (stdlibMLDSA) publicKeyLength() int {
+	return mldsa.MLDSA87().PublicKeySize()
+}
+
+func (stdlibMLDSA) signatureLength() int {
+	return mldsa.MLDSA87().SignatureSize()
+}
+
+func (stdlibMLDSA) key(seed []byte) (*mldsa.PrivateKey, error) {
+	if len(seed) != mldsaSeedLength {
+		return nil, errors.Errorf("seed must be %d bytes", mldsaSeedLength)
+	}
+	return mldsa.NewPrivateKey(mldsa.MLDSA87(), sha3.SumSHAKE256(seed, 32))
+}
+
+func (r stdlibMLDSA) publicKey(seed []byte) ([]byte, error) {
+	sk, err := r.key(seed)
+	if err != nil {
+		return nil, err
+	}
+	return sk.PublicKey().Bytes(), nil
+}
+
+func (r stdlibMLDSA) sign(seed, msg []byte) ([]byte, error) {
+	sk, err := r.key(seed)
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignDeterministic(msg, &mldsa.Options{})
+}
+
+func (stdlibMLDSA) verify(pk, msg, sig []byte) bool {
+	p, err := mldsa.NewPublicKey(mldsa.MLDSA87(), pk)
+	if err != nil {
+		return false
+	}
+	return mldsa.Verify(p, msg, sig, &mldsa.Options{}) == nil
+}
@@ -0,0 +1,9 @@
+//go:build !go1.27
+
+package main
+
+import "github.com/pkg/errors"
+
+func stdlibMLDSA87() (reference, error) {
+	return nil, errors.New("the ML-DSA-87 reference implementation of the Go standard library requires Go 1.27 or later")
+}
@@ -0,0 +1,171 @@
+// This binary publishes a list of nodes as an EIP-1459 node tree. It reads the ENRs of the nodes, for instance the
+// output of a crawl of the network, signs the tree and writes its TXT records as a DNS zone file, which can be
+// loaded into the zone of the tree domain. Beacon nodes find the nodes with --dns-discovery-url set to the printed
+// enrtree:// URL.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/theQRL/go-zond/crypto"
+	"github.com/theQRL/go-zond/p2p/dnsdisc"
+	"github.com/theQRL/go-zond/p2p/enode"
+	_ "github.com/theQRL/qrysm/v4/runtime/maxprocs"
+)
+
+// maxTXTStringLength is the maximum length of a single character string of a TXT record. Longer records are split
+// into several strings, which resolvers join again.
+const maxTXTStringLength = 255
+
+type linkFlags []string
+
+func (l *linkFlags) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *linkFlags) Set(value string) error {
+	if _, _, err := dnsdisc.ParseURL(value); err != nil {
+		return err
+	}
+	*l = append(*l, value)
+	return nil
+}
+
+var (
+	nodesFile = flag.String("nodes", "", "Path to a file with the ENRs of the nodes to publish, one per line")
+	domain    = flag.String("domain", "", "Domain name of the tree, e.g. nodes.example.org")
+	keyFile   = flag.String("private-key", "", "Path to a file with the hex encoded secp256k1 key which signs the tree")
+	seq       = flag.Uint("seq", 1, "Sequence number of the tree, which must increase with every published update")
+	ttl       = flag.Uint("ttl", 300, "Time to live of the records, in seconds")
+	outfile   = flag.String("out", "", "Filepath to write the zone file, defaults to stdout")
+	links     linkFlags
+)
+
+func main() {
+	flag.Var(&links, "link", "enrtree:// URL of another tree to link to, this flag may be used multiple times")
+	flag.Parse()
+
+	if *nodesFile == "" || *domain == "" || *keyFile == "" {
+		log.Fatal("The -nodes, -domain and -private-key flags are required")
+	}
+	key, err := crypto.LoadECDSA(*keyFile)
+	if err != nil {
+		log.WithError(err).Fatal("Could not load signing key")
+	}
+	f, err := os.Open(*nodesFile) // #nosec G304
+	if err != nil {
+		log.WithError(err).Fatal("Could not open nodes file")
+	}
+	nodes, err := readNodes(f)
+	if closeErr := f.Close(); closeErr != nil {
+		log.WithError(closeErr).Error("Could not close nodes file")
+	}
+	if err != nil {
+		log.WithError(err).Fatal("Could not read nodes")
+	}
+
+	tree, err := dnsdisc.MakeTree(*seq, nodes, links)
+	if err != nil {
+		log.WithError(err).Fatal("Could not create node tree")
+	}
+	url, err := tree.Sign(key, *domain)
+	if err != nil {
+		log.WithError(err).Fatal("Could not sign node tree")
+	}
+
+	out := os.Stdout
+	if *outfile != "" {
+		out, err = os.Create(*outfile)
+		if err != nil {
+			log.WithError(err).Fatal("Could not create zone file")
+		}
+		defer func() {
+			if err := out.Close(); err != nil {
+				log.WithError(err).Error("Could not close zone file")
+			}
+		}()
+	}
+	if err := writeZone(out, *domain, *ttl, tree.ToTXT(*domain)); err != nil {
+		log.WithError(err).Fatal("Could not write zone file")
+	}
+	log.WithFields(log.Fields{
+		"nodes": len(nodes),
+		"seq":   tree.Seq(),
+		"url":   url,
+	}).Info("Published node tree")
+}
+
+// readNodes parses one ENR per line, skipping empty lines and lines starting with '#'. When a node is listed more
+// than once, the record with the highest sequence number is kept.
+func readNodes(r io.Reader) ([]*enode.Node, error) {
+	index := make(map[enode.ID]int)
+	var nodes []*enode.Node
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		n, err := enode.Parse(enode.ValidSchemes, text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid node record on line %d", line)
+		}
+		if i, ok := index[n.ID()]; ok {
+			if n.Seq() > nodes[i].Seq() {
+				nodes[i] = n
+			}
+			continue
+		}
+		index[n.ID()] = len(nodes)
+		nodes = append(nodes, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// writeZone writes the TXT records of a tree as a zone file with the tree domain as origin. The root record comes
+// first, the other records are sorted by name.
+func writeZone(w io.Writer, domain string, ttl uint, records map[string]string) error {
+	names := make([]string, 0, len(records))
+	for name := range records {
+		if name != domain {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{domain}, names...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n$TTL %d\n", domain, ttl)
+	for _, name := range names {
+		label := strings.TrimSuffix(name, "."+domain)
+		if name == domain {
+			label = "@"
+		}
+		fmt.Fprintf(&b, "%s\tIN\tTXT\t%s\n", label, quoteTXT(records[name]))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quoteTXT quotes the value of a TXT record, split into strings of at most maxTXTStringLength characters. Record
+// values only use base32, base64 and URL characters, so no escaping is needed.
+func quoteTXT(value string) string {
+	var parts []string
+	for len(value) > maxTXTStringLength {
+		parts = append(parts, `"`+value[:maxTXTStringLength]+`"`)
+		value = value[maxTXTStringLength:]
+	}
+	parts = append(parts, `"`+value+`"`)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/theQRL/go-zond/crypto"
+	"github.com/theQRL/go-zond/p2p/dnsdisc"
+	"github.com/theQRL/go-zond/p2p/enode"
+	"github.com/theQRL/go-zond/p2p/enr"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+)
+
+// zoneResolver serves the TXT records of a zone file, standing in for a DNS server loaded with it.
+type zoneResolver map[string]string
+
+func (z zoneResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := z[name]; ok {
+		return []string{txt}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+var quoted = regexp.MustCompile(`"([^"]*)"`)
+
+// parseZone reads the records written by writeZone, joining the strings of each record like a resolver does.
+func parseZone(t *testing.T, zone string) zoneResolver {
+	records := make(zoneResolver)
+	var origin string
+	scanner := bufio.NewScanner(strings.NewReader(zone))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "$ORIGIN ") {
+			origin = strings.TrimSuffix(strings.TrimPrefix(line, "$ORIGIN "), ".")
+			continue
+		}
+		if strings.HasPrefix(line, "$") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 4)
+		require.Equal(t, 4, len(fields), line)
+		name := fields[0] + "." + origin
+		if fields[0] == "@" {
+			name = origin
+		}
+		var value string
+		for _, m := range quoted.FindAllStringSubmatch(fields[3], -1) {
+			require.Equal(t, true, len(m[1]) <= maxTXTStringLength)
+			value += m[1]
+		}
+		records[name] = value
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func testNode(t *testing.T, seq uint64) *enode.Node {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	var r enr.Record
+	r.SetSeq(seq)
+	r.Set(enr.IP(net.IPv4(127, 0, 0, 1)))
+	r.Set(enr.TCP(13000))
+	r.Set(enr.UDP(12000))
+	require.NoError(t, enode.SignV4(&r, key))
+	n, err := enode.New(enode.ValidSchemes, &r)
+	require.NoError(t, err)
+	return n
+}
+
+func TestReadNodes(t *testing.T) {
+	a, b := testNode(t, 1), testNode(t, 1)
+	list := strings.Join([]string{"# crawled nodes", a.String(), "", b.String(), a.String()}, "\n")
+	nodes, err := readNodes(strings.NewReader(list))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(nodes))
+	assert.Equal(t, a.ID(), nodes[0].ID())
+	assert.Equal(t, b.ID(), nodes[1].ID())
+
+	_, err = readNodes(strings.NewReader(a.String() + "\nenr:invalid"))
+	require.ErrorContains(t, "invalid node record on line 2", err)
+}
+
+func TestWriteZone(t *testing.T) {
+	nodes := make([]*enode.Node, 20)
+	for i := range nodes {
+		nodes[i] = testNode(t, 1)
+	}
+	tree, err := dnsdisc.MakeTree(3, nodes, nil)
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	domain := "nodes.example.org"
+	url, err := tree.Sign(key, domain)
+	require.NoError(t, err)
+
+	var zone bytes.Buffer
+	require.NoError(t, writeZone(&zone, domain, 300, tree.ToTXT(domain)))
+	require.Equal(t, true, strings.HasPrefix(zone.String(), "$ORIGIN nodes.example.org.\n$TTL 300\n@\tIN\tTXT\t\"enrtree-root:v1 "))
+
+	// A client resolving the zone verifies the signed tree and finds every node.
+	c := dnsdisc.NewClient(dnsdisc.Config{Resolver: parseZone(t, zone.String()), RateLimit: 1000})
+	synced, err := c.SyncTree(url)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), synced.Seq())
+	assert.Equal(t, len(nodes), len(synced.Nodes()))
+}
+
+func TestQuoteTXT(t *testing.T) {
+	assert.Equal(t, `"abc"`, quoteTXT("abc"))
+	long := strings.Repeat("a", maxTXTStringLength) + "bc"
+	assert.Equal(t, `"`+strings.Repeat("a", maxTXTStringLength)+`" "bc"`, quoteTXT(long))
+}
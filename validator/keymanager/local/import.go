@@ -10,8 +10,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/schollz/progressbar/v3"
 	"github.com/sirupsen/logrus"
-	keystorev4 "github.com/theQRL/go-zond-wallet-encryptor-keystore"
 	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
 	zondpbservice "github.com/theQRL/qrysm/v4/proto/zond/service"
 	"github.com/theQRL/qrysm/v4/validator/keymanager"
 )
@@ -33,7 +33,6 @@ func (km *Keymanager) ImportKeystores(
 	if len(passwords) != len(keystores) {
 		return nil, ErrMismatchedNumPasswords
 	}
-	enc := keystorev4.New()
 	bar := initializeProgressBar(len(keystores), "Importing accounts...")
 	keys := map[string]string{}
 	statuses := make([]*zondpbservice.ImportedKeystoreStatus, len(keystores))
@@ -48,7 +47,7 @@ func (km *Keymanager) ImportKeystores(
 	for i := 0; i < len(keystores); i++ {
 		var privKeyBytes []byte
 		var pubKeyBytes []byte
-		privKeyBytes, pubKeyBytes, _, err = km.attemptDecryptKeystore(enc, keystores[i], passwords[i])
+		privKeyBytes, pubKeyBytes, _, err = km.attemptDecryptKeystore(keystores[i], passwords[i])
 		if err != nil {
 			statuses[i] = &zondpbservice.ImportedKeystoreStatus{
 				Status:  zondpbservice.ImportedKeystoreStatus_ERROR,
@@ -124,30 +123,34 @@ func (km *Keymanager) ImportKeypairs(ctx context.Context, privKeys, pubKeys [][]
 }
 
 // Retrieves the private key and public key from an EIP-2335 keystore file
-// by decrypting using a specified password. If the password fails,
-// it prompts the user for the correct password until it confirms.
+// by decrypting using a specified password. Both version 1 keystores and
+// version 2 keystores, which use scrypt, pbkdf2 or argon2id, are supported.
 func (_ *Keymanager) attemptDecryptKeystore(
-	enc *keystorev4.Encryptor, keystore *keymanager.Keystore, password string,
+	ks *keymanager.Keystore, password string,
 ) ([]byte, []byte, string, error) {
 	// Attempt to decrypt the keystore with the specifies password.
 	var privKeyBytes []byte
 	var err error
-	privKeyBytes, err = enc.Decrypt(keystore.Crypto, password)
-	doesNotDecrypt := err != nil && strings.Contains(err.Error(), keymanager.IncorrectPasswordErrMsg)
+	if ks.Version > keystore.DilithiumKeystoreVersion {
+		return nil, nil, "", fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	privKeyBytes, err = keystore.DecryptCryptoFields(ks.Crypto, password)
+	doesNotDecrypt := errors.Is(err, keystore.ErrDecrypt) ||
+		(err != nil && strings.Contains(err.Error(), keymanager.IncorrectPasswordErrMsg))
 	if doesNotDecrypt {
 		return nil, nil, "", fmt.Errorf(
 			"incorrect password for key 0x%s",
-			keystore.Pubkey,
+			ks.Pubkey,
 		)
 	}
-	if err != nil && !strings.Contains(err.Error(), keymanager.IncorrectPasswordErrMsg) {
+	if err != nil {
 		return nil, nil, "", errors.Wrap(err, "could not decrypt keystore")
 	}
 	var pubKeyBytes []byte
 	// Attempt to use the pubkey present in the keystore itself as a field. If unavailable,
 	// then utilize the public key directly from the private key.
-	if keystore.Pubkey != "" {
-		pubKeyBytes, err = hex.DecodeString(strings.TrimPrefix(keystore.Pubkey, "0x"))
+	if ks.Pubkey != "" {
+		pubKeyBytes, err = hex.DecodeString(strings.TrimPrefix(ks.Pubkey, "0x"))
 		if err != nil {
 			return nil, nil, "", errors.Wrap(err, "could not decode pubkey from keystore")
 		}
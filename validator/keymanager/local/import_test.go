@@ -2,14 +2,18 @@ package local
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/google/uuid"
 	logTest "github.com/sirupsen/logrus/hooks/test"
+	keystorev1 "github.com/theQRL/go-zond-wallet-encryptor-keystore"
 	"github.com/theQRL/go-zond/common/hexutil"
 	"github.com/theQRL/qrysm/v4/crypto/bls"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
 	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
 	zondpbservice "github.com/theQRL/qrysm/v4/proto/zond/service"
 	"github.com/theQRL/qrysm/v4/testing/assert"
@@ -261,3 +265,61 @@ func TestLocalKeymanager_ImportKeystores(t *testing.T) {
 		require.DeepEqual(t, dr.accountsStore, copyStore)
 	})
 }
+
+func TestLocalKeymanager_ImportKeystores_Versions(t *testing.T) {
+	ctx := context.Background()
+	wallet := &mock.Wallet{
+		Files:          make(map[string]map[string][]byte),
+		WalletPassword: password,
+	}
+	dr := &Keymanager{
+		wallet:        wallet,
+		accountsStore: &accountStore{},
+	}
+
+	// Version 1 keystore, as produced by the staking deposit CLI.
+	v1Key, err := dilithium.RandKey()
+	require.NoError(t, err)
+	v1Crypto, err := keystorev1.New().Encrypt(v1Key.Marshal(), password)
+	require.NoError(t, err)
+	v1 := &keymanager.Keystore{
+		Crypto:  v1Crypto,
+		Pubkey:  fmt.Sprintf("%x", v1Key.PublicKey().Marshal()),
+		Version: 1,
+	}
+
+	// Version 2 keystore, using argon2id.
+	v2Key, err := dilithium.RandKey()
+	require.NoError(t, err)
+	params := &keystore.KDFParams{Function: keystore.KDFArgon2id, Argon2idTime: 1, Argon2idMemory: 64, Argon2idParallelism: 1}
+	v2Keystore, err := keystore.EncryptDilithiumKeystore(v2Key.Marshal(), password, "", params)
+	require.NoError(t, err)
+	enc, err := json.Marshal(v2Keystore)
+	require.NoError(t, err)
+	v2 := &keymanager.Keystore{}
+	require.NoError(t, json.Unmarshal(enc, v2))
+
+	statuses, err := dr.ImportKeystores(ctx, []*keymanager.Keystore{v1, v2}, []string{password, password})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(statuses))
+	for _, status := range statuses {
+		require.Equal(t, zondpbservice.ImportedKeystoreStatus_IMPORTED, status.Status, status.Message)
+	}
+	require.Equal(t, 2, len(dr.accountsStore.Seeds))
+	imported := map[string]bool{}
+	for _, seed := range dr.accountsStore.Seeds {
+		imported[string(seed)] = true
+	}
+	assert.Equal(t, true, imported[string(v1Key.Marshal())])
+	assert.Equal(t, true, imported[string(v2Key.Marshal())])
+
+	// A wrong password is reported as such for both versions.
+	v1.Pubkey = "aa"
+	v2.Pubkey = "bb"
+	statuses, err = dr.ImportKeystores(ctx, []*keymanager.Keystore{v1, v2}, []string{"wrong", "wrong"})
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.Equal(t, zondpbservice.ImportedKeystoreStatus_ERROR, status.Status)
+		assert.StringContains(t, "incorrect password", status.Message)
+	}
+}
@@ -56,10 +56,15 @@ func MapAttestation(attestation *zondpb.Attestation) (*Attestation, error) {
 	if err != nil {
 		return nil, err
 	}
+	var signatureValidatorIndex []string
+	for _, idx := range attestation.SignatureValidatorIndex {
+		signatureValidatorIndex = append(signatureValidatorIndex, fmt.Sprint(idx))
+	}
 	return &Attestation{
-		AggregationBits: []byte(attestation.AggregationBits),
-		Data:            data,
-		Signature:       attestation.Signature,
+		AggregationBits:         []byte(attestation.AggregationBits),
+		Data:                    data,
+		Signature:               attestation.Signature,
+		SignatureValidatorIndex: signatureValidatorIndex,
 	}, nil
 }
 
@@ -136,9 +136,10 @@ type AggregateAndProof struct {
 
 // Attestation a sub property of AggregateAndProofSignRequest.
 type Attestation struct {
-	AggregationBits hexutil.Bytes    `json:"aggregation_bits"` /*hex bitlist*/
-	Data            *AttestationData `json:"data"`
-	Signature       hexutil.Bytes    `json:"signature"`
+	AggregationBits         hexutil.Bytes    `json:"aggregation_bits"` /*hex bitlist*/
+	Data                    *AttestationData `json:"data"`
+	Signature               hexutil.Bytes    `json:"signature"`
+	SignatureValidatorIndex []string         `json:"signature_validator_index"` /* uint64 */
 }
 
 // AttestationData a sub property of Attestation.
@@ -0,0 +1,5 @@
+// Package remotesigner implements a remote signer serving the remote-web3signer/v1 API.
+// It signs with Dilithium keys loaded from local keystores and enforces EIP-3076
+// slashing protection with the validator slashing protection database, so key custody
+// can be separated from the validator client.
+package remotesigner
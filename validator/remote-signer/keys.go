@@ -0,0 +1,43 @@
+package remotesigner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+)
+
+// loadKeys decrypts all the keystores with a .json extension in the directory with the
+// password. Both version 1 and version 2 keystores are supported.
+func loadKeys(dir, password string) (map[[dilithium2.CryptoPublicKeyBytes]byte]dilithium.DilithiumKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read keystores directory")
+	}
+	keys := make(map[[dilithium2.CryptoPublicKeyBytes]byte]dilithium.DilithiumKey)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		ks, err := keystore.ReadDilithiumKeystore(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore %s", path)
+		}
+		seed, err := ks.Decrypt(password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt keystore %s", path)
+		}
+		secretKey, err := dilithium.SecretKeyFromBytes(seed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not initialize secret key from keystore %s", path)
+		}
+		keys[bytesutil.ToBytes2592(secretKey.PublicKey().Marshal())] = secretKey
+	}
+	return keys, nil
+}
@@ -0,0 +1,5 @@
+package remotesigner
+
+import "github.com/sirupsen/logrus"
+
+var log = logrus.WithField("prefix", "remote-signer")
@@ -0,0 +1,17 @@
+package remotesigner
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	signRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "remote_signer_sign_requests_total",
+		Help: "The number of sign requests received, by request type.",
+	}, []string{"type"})
+	signRequestsFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "remote_signer_sign_requests_failed_total",
+		Help: "The number of sign requests which were not signed, by reason.",
+	}, []string{"reason"})
+)
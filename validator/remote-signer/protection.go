@@ -0,0 +1,136 @@
+package remotesigner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/slashings"
+	"github.com/theQRL/qrysm/v4/validator/db/iface"
+	"github.com/theQRL/qrysm/v4/validator/db/kv"
+)
+
+// errSlashable marks sign requests rejected by the slashing protection.
+var errSlashable = errors.New("rejected by slashing protection")
+
+// slashingProtection enforces EIP-3076 slashing protection rules with the same checks
+// as the validator client, against the validator slashing protection database.
+type slashingProtection struct {
+	db iface.ValidatorDB
+	// locks serializes the check and the update of the history of a public key, so two
+	// concurrent requests cannot both pass the check.
+	locks sync.Map
+}
+
+func newSlashingProtection(db iface.ValidatorDB) *slashingProtection {
+	return &slashingProtection{db: db}
+}
+
+func (p *slashingProtection) lock(pubKey [dilithium2.CryptoPublicKeyBytes]byte) func() {
+	l, _ := p.locks.LoadOrStore(pubKey, &sync.Mutex{})
+	mu, ok := l.(*sync.Mutex)
+	if !ok {
+		panic("unexpected lock type")
+	}
+	mu.Lock()
+	return mu.Unlock
+}
+
+// checkAndSaveAttestation rejects slashable attestations and records the others in the
+// attesting history of the public key.
+func (p *slashingProtection) checkAndSaveAttestation(
+	ctx context.Context, pubKey [dilithium2.CryptoPublicKeyBytes]byte, data *zondpb.AttestationData, signingRoot [32]byte,
+) error {
+	unlock := p.lock(pubKey)
+	defer unlock()
+
+	// Based on EIP3076, refuse to sign any attestation with source epoch less
+	// than the minimum source epoch present in that signer’s attestations.
+	lowestSourceEpoch, exists, err := p.db.LowestSignedSourceEpoch(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	if exists && data.Source.Epoch < lowestSourceEpoch {
+		return errors.Wrapf(errSlashable,
+			"could not sign attestation lower than lowest source epoch in db, %d < %d",
+			data.Source.Epoch,
+			lowestSourceEpoch,
+		)
+	}
+	existingSigningRoot, err := p.db.SigningRootAtTargetEpoch(ctx, pubKey, data.Target.Epoch)
+	if err != nil {
+		return err
+	}
+	signingRootsDiffer := slashings.SigningRootsDiffer(existingSigningRoot, signingRoot)
+
+	// Based on EIP3076, refuse to sign any attestation with target epoch less
+	// than or equal to the minimum target epoch present in that signer’s attestations.
+	lowestTargetEpoch, exists, err := p.db.LowestSignedTargetEpoch(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	if signingRootsDiffer && exists && data.Target.Epoch <= lowestTargetEpoch {
+		return errors.Wrapf(errSlashable,
+			"could not sign attestation lower than or equal to lowest target epoch in db, %d <= %d",
+			data.Target.Epoch,
+			lowestTargetEpoch,
+		)
+	}
+	indexedAtt := &zondpb.IndexedAttestation{Data: data}
+	slashingKind, err := p.db.CheckSlashableAttestation(ctx, pubKey, signingRoot, indexedAtt)
+	if err != nil {
+		switch slashingKind {
+		case kv.DoubleVote, kv.SurroundingVote, kv.SurroundedVote:
+			return errors.Wrap(errSlashable, err.Error())
+		}
+		return err
+	}
+	if err := p.db.SaveAttestationForPubKey(ctx, pubKey, signingRoot, indexedAtt); err != nil {
+		return errors.Wrap(err, "could not save attestation history for validator public key")
+	}
+	return nil
+}
+
+// checkAndSaveProposal rejects double proposals and records the others in the proposal
+// history of the public key.
+func (p *slashingProtection) checkAndSaveProposal(
+	ctx context.Context, pubKey [dilithium2.CryptoPublicKeyBytes]byte, slot primitives.Slot, signingRoot [32]byte,
+) error {
+	unlock := p.lock(pubKey)
+	defer unlock()
+
+	prevSigningRoot, proposalAtSlotExists, err := p.db.ProposalHistoryForSlot(ctx, pubKey, slot)
+	if err != nil {
+		return errors.Wrap(err, "failed to get proposal history")
+	}
+	lowestSignedProposalSlot, lowestProposalExists, err := p.db.LowestSignedProposal(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+
+	// A proposal for the same slot is only signed again if it has the same signing root.
+	signingRootIsDifferent := prevSigningRoot == params.BeaconConfig().ZeroHash || prevSigningRoot != signingRoot
+	if proposalAtSlotExists && signingRootIsDifferent {
+		return errors.Wrap(errSlashable, "attempted to sign a double proposal")
+	}
+
+	// Based on EIP3076, refuse to sign any proposal with slot less than or equal to
+	// the minimum signed proposal present in the DB for that public key.
+	if lowestProposalExists && signingRootIsDifferent && lowestSignedProposalSlot >= slot {
+		return errors.Wrap(errSlashable, fmt.Sprintf(
+			"could not sign block with slot <= lowest signed slot in db, lowest signed slot: %d >= block slot: %d",
+			lowestSignedProposalSlot,
+			slot,
+		))
+	}
+
+	if err := p.db.SaveProposalHistoryForSlot(ctx, pubKey, slot, signingRoot[:]); err != nil {
+		return errors.Wrap(err, "failed to save updated proposal history")
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	dilithium2 "github.com/theQRL/go-qrllib/dilithium"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/validator/db/iface"
+)
+
+const (
+	upcheckPath    = "/upcheck"
+	publicKeysPath = "/api/v1/eth2/publicKeys"
+	signPath       = "/api/v1/eth2/sign/{identifier}"
+	reloadPath     = "/reload"
+
+	// maxSignRequestSize bounds the size of a sign request body. Full blocks are the
+	// largest requests.
+	maxSignRequestSize = 16 << 20
+)
+
+// Config for the remote signer.
+type Config struct {
+	// Addr is the host:port the signer listens on.
+	Addr string
+	// KeystoresDir is the directory of the keystores holding the signing keys.
+	KeystoresDir string
+	// KeystoresPassword decrypts all the keystores.
+	KeystoresPassword string
+	// DB is the slashing protection database.
+	DB iface.ValidatorDB
+	// GenesisValidatorsRoot, if set, is the only genesis validators root the signer signs for.
+	// Otherwise the root saved in the slashing protection database is used, if any.
+	GenesisValidatorsRoot []byte
+	// AllowUnverifiedSigningRoots accepts the signing root sent by the client for the
+	// block requests whose signing root cannot be computed from the request.
+	AllowUnverifiedSigningRoots bool
+	// BearerToken, if set, has to be sent by clients in the Authorization header.
+	BearerToken string
+	// TLSCertFile and TLSKeyFile enable TLS.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile requires clients to present a certificate signed by this CA.
+	TLSClientCAFile string
+}
+
+// Server serves the remote-web3signer/v1 API with the keys of local keystores.
+type Server struct {
+	ctx                   context.Context
+	cancel                context.CancelFunc
+	cfg                   *Config
+	server                *http.Server
+	protection            *slashingProtection
+	genesisValidatorsRoot []byte
+	keysLock              sync.RWMutex
+	keys                  map[[dilithium2.CryptoPublicKeyBytes]byte]dilithium.DilithiumKey
+	failStatusLock        sync.RWMutex
+	failStatus            error
+}
+
+// New loads the keys and sets up the HTTP server of the remote signer.
+func New(ctx context.Context, cfg *Config) (*Server, error) {
+	if cfg.DB == nil {
+		return nil, errors.New("a slashing protection database is required")
+	}
+	keys, err := loadKeys(cfg.KeystoresDir, cfg.KeystoresPassword)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := pinGenesisValidatorsRoot(ctx, cfg.DB, cfg.GenesisValidatorsRoot)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys := make([][dilithium2.CryptoPublicKeyBytes]byte, 0, len(keys))
+	for pubKey := range keys {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if err := cfg.DB.UpdatePublicKeysBuckets(pubKeys); err != nil {
+		return nil, errors.Wrap(err, "could not initialize slashing protection for the public keys")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Server{
+		ctx:                   ctx,
+		cancel:                cancel,
+		cfg:                   cfg,
+		protection:            newSlashingProtection(cfg.DB),
+		genesisValidatorsRoot: gvr,
+		keys:                  keys,
+	}
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.server = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.router(),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: time.Second,
+	}
+	log.WithField("numKeys", len(keys)).Info("Loaded signing keys")
+	return s, nil
+}
+
+// Start the remote signer.
+func (s *Server) Start() {
+	go func() {
+		log.WithField("address", s.cfg.Addr).Info("Starting remote signer")
+		var err error
+		if s.server.TLSConfig != nil {
+			err = s.server.ListenAndServeTLS(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		} else {
+			log.Warn("TLS is disabled, requests and signatures are sent in clear text")
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Errorf("Could not listen to host:port :%s", s.cfg.Addr)
+			s.failStatusLock.Lock()
+			s.failStatus = err
+			s.failStatusLock.Unlock()
+		}
+	}()
+}
+
+// Stop the remote signer gracefully.
+func (s *Server) Stop() error {
+	defer s.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Status checks for any service failure conditions.
+func (s *Server) Status() error {
+	s.failStatusLock.RLock()
+	defer s.failStatusLock.RUnlock()
+	return s.failStatus
+}
+
+func (s *Server) router() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc(upcheckPath, s.upcheck).Methods(http.MethodGet)
+	r.HandleFunc(publicKeysPath, s.publicKeys).Methods(http.MethodGet)
+	r.HandleFunc(signPath, s.sign).Methods(http.MethodPost)
+	r.HandleFunc(reloadPath, s.reload).Methods(http.MethodPost)
+	r.Use(s.authMiddleware)
+	return r
+}
+
+// authMiddleware requires the bearer token, if configured, on every endpoint but /upcheck.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.BearerToken != "" && r.URL.Path != upcheckPath {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.BearerToken)) != 1 {
+				signRequestsFailedTotal.WithLabelValues("unauthorized").Inc()
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (_ *Server) upcheck(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, "OK")
+}
+
+func (s *Server) publicKeys(w http.ResponseWriter, _ *http.Request) {
+	s.keysLock.RLock()
+	pubKeys := make([]string, 0, len(s.keys))
+	for pubKey := range s.keys {
+		pubKeys = append(pubKeys, hexutil.Encode(pubKey[:]))
+	}
+	s.keysLock.RUnlock()
+	sort.Strings(pubKeys)
+	writeJSON(w, pubKeys)
+}
+
+// reload loads the keystores again, so keys can be added or removed without a restart.
+func (s *Server) reload(w http.ResponseWriter, _ *http.Request) {
+	keys, err := loadKeys(s.cfg.KeystoresDir, s.cfg.KeystoresPassword)
+	if err != nil {
+		log.WithError(err).Error("Could not reload keystores")
+		http.Error(w, "could not reload keystores", http.StatusInternalServerError)
+		return
+	}
+	pubKeys := make([][dilithium2.CryptoPublicKeyBytes]byte, 0, len(keys))
+	for pubKey := range keys {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if err := s.cfg.DB.UpdatePublicKeysBuckets(pubKeys); err != nil {
+		log.WithError(err).Error("Could not initialize slashing protection for the public keys")
+		http.Error(w, "could not reload keystores", http.StatusInternalServerError)
+		return
+	}
+	s.keysLock.Lock()
+	s.keys = keys
+	s.keysLock.Unlock()
+	log.WithField("numKeys", len(keys)).Info("Reloaded signing keys")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request) {
+	pubKeyBytes, err := hexutil.Decode(mux.Vars(r)["identifier"])
+	if err != nil || len(pubKeyBytes) != dilithium2.CryptoPublicKeyBytes {
+		signRequestsFailedTotal.WithLabelValues("bad_request").Inc()
+		http.Error(w, "invalid public key", http.StatusBadRequest)
+		return
+	}
+	var pubKey [dilithium2.CryptoPublicKeyBytes]byte
+	copy(pubKey[:], pubKeyBytes)
+	s.keysLock.RLock()
+	key, ok := s.keys[pubKey]
+	s.keysLock.RUnlock()
+	if !ok {
+		signRequestsFailedTotal.WithLabelValues("unknown_key").Inc()
+		http.Error(w, "public key not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignRequestSize))
+	if err != nil {
+		signRequestsFailedTotal.WithLabelValues("bad_request").Inc()
+		http.Error(w, "could not read request body", http.StatusBadRequest)
+		return
+	}
+	data, err := parseSignRequest(body, s.cfg.AllowUnverifiedSigningRoots)
+	if err == nil {
+		err = s.checkGenesisValidatorsRoot(data)
+	}
+	if err != nil {
+		signRequestsFailedTotal.WithLabelValues("bad_request").Inc()
+		log.WithError(err).Warn("Rejected invalid sign request")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	signRequestsTotal.WithLabelValues(data.requestType).Inc()
+
+	switch {
+	case data.attestation != nil:
+		err = s.protection.checkAndSaveAttestation(r.Context(), pubKey, data.attestation, data.signingRoot)
+	case data.blockSlot != nil:
+		err = s.protection.checkAndSaveProposal(r.Context(), pubKey, *data.blockSlot, data.signingRoot)
+	}
+	if errors.Is(err, errSlashable) {
+		signRequestsFailedTotal.WithLabelValues("slashable").Inc()
+		log.WithError(err).WithField("pubkey", fmt.Sprintf("%#x", pubKey[:8])).Warn("Refused to sign slashable message")
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		signRequestsFailedTotal.WithLabelValues("internal").Inc()
+		log.WithError(err).Error("Could not check slashing protection")
+		http.Error(w, "could not check slashing protection", http.StatusInternalServerError)
+		return
+	}
+
+	sig := key.Sign(data.signingRoot[:])
+	writeJSON(w, &struct {
+		Signature hexutil.Bytes `json:"signature"`
+	}{Signature: sig.Marshal()})
+}
+
+func (s *Server) checkGenesisValidatorsRoot(data *signingData) error {
+	if len(s.genesisValidatorsRoot) == 0 || data.genesisValidatorsRoot == nil {
+		return nil
+	}
+	if !bytes.Equal(s.genesisValidatorsRoot, data.genesisValidatorsRoot) {
+		return badRequest(fmt.Errorf(
+			"genesis validators root %#x does not match the genesis validators root %#x of the signer",
+			data.genesisValidatorsRoot,
+			s.genesisValidatorsRoot,
+		))
+	}
+	return nil
+}
+
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if s.cfg.TLSCertFile == "" && s.cfg.TLSKeyFile == "" {
+		if s.cfg.TLSClientCAFile != "" {
+			return nil, errors.New("client certificate authentication requires a TLS certificate and key")
+		}
+		return nil, nil
+	}
+	if s.cfg.TLSCertFile == "" || s.cfg.TLSKeyFile == "" {
+		return nil, errors.New("both a TLS certificate and key are required")
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.cfg.TLSClientCAFile != "" {
+		caCert, err := os.ReadFile(s.cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read client CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("could not parse client CA certificate")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// pinGenesisValidatorsRoot returns the genesis validators root the signer signs for. A
+// configured root is saved in the slashing protection database, and has to match the
+// root already saved there.
+func pinGenesisValidatorsRoot(ctx context.Context, db iface.ValidatorDB, configured []byte) ([]byte, error) {
+	saved, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read genesis validators root")
+	}
+	if len(configured) == 0 {
+		return saved, nil
+	}
+	if len(saved) != 0 && !bytes.Equal(saved, configured) {
+		return nil, fmt.Errorf(
+			"genesis validators root %#x does not match the root %#x of the slashing protection database",
+			configured,
+			saved,
+		)
+	}
+	if len(saved) == 0 {
+		if err := db.SaveGenesisValidatorsRoot(ctx, configured); err != nil {
+			return nil, errors.Wrap(err, "could not save genesis validators root")
+		}
+	}
+	return configured, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
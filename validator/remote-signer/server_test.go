@@ -0,0 +1,244 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/crypto/keystore"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/validator/db/kv"
+)
+
+const testPassword = "password"
+
+func writeKeystore(t *testing.T, dir string) dilithium.DilithiumKey {
+	key, err := dilithium.RandKey()
+	require.NoError(t, err)
+	ks, err := keystore.EncryptDilithiumKeystore(key.Marshal(), testPassword, "", &keystore.KDFParams{
+		Function:         keystore.KDFPBKDF2,
+		PBKDF2Iterations: 16,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ks.Save(filepath.Join(dir, fmt.Sprintf("keystore-%x.json", key.PublicKey().Marshal()[:8]))))
+	return key
+}
+
+func setupServer(t *testing.T, cfg *Config) (*Server, *httptest.Server, dilithium.DilithiumKey) {
+	ctx := context.Background()
+	db, err := kv.NewKVStore(ctx, t.TempDir(), &kv.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	cfg.KeystoresDir = filepath.Join(t.TempDir(), "keystores")
+	cfg.KeystoresPassword = testPassword
+	cfg.DB = db
+	key := writeKeystore(t, cfg.KeystoresDir)
+
+	s, err := New(ctx, cfg)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s.server.Handler)
+	t.Cleanup(srv.Close)
+	return s, srv, key
+}
+
+func signRequest(t *testing.T, srv *httptest.Server, pubKey []byte, body []byte, token string) (*http.Response, []byte) {
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/eth2/sign/"+hexutil.Encode(pubKey), bytes.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+	buf := new(bytes.Buffer)
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	return resp, buf.Bytes()
+}
+
+func TestServer_Upcheck(t *testing.T) {
+	_, srv, _ := setupServer(t, &Config{BearerToken: "secret"})
+	resp, err := srv.Client().Get(srv.URL + "/upcheck")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var status string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	assert.Equal(t, "OK", status)
+}
+
+func TestServer_PublicKeys(t *testing.T) {
+	_, srv, key := setupServer(t, &Config{})
+	resp, err := srv.Client().Get(srv.URL + "/api/v1/eth2/publicKeys")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+	var pubKeys []string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pubKeys))
+	assert.DeepEqual(t, []string{hexutil.Encode(key.PublicKey().Marshal())}, pubKeys)
+}
+
+func TestServer_BearerToken(t *testing.T) {
+	_, srv, key := setupServer(t, &Config{BearerToken: "secret"})
+	body := attestationRequestBody(t, testAttestationData(1, 2), nil)
+
+	resp, _ := signRequest(t, srv, key.PublicKey().Marshal(), body, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), body, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), body, "secret")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The token is only accepted with the bearer scheme.
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/eth2/publicKeys", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "secret")
+	resp, err = srv.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_SignAttestation(t *testing.T) {
+	_, srv, key := setupServer(t, &Config{})
+	data := testAttestationData(1, 2)
+	root := expectedSigningRoot(t, data.Slot, data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester, data)
+
+	resp, body := signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, data, root[:]), "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	sigResp := struct {
+		Signature hexutil.Bytes `json:"signature"`
+	}{}
+	require.NoError(t, json.Unmarshal(body, &sigResp))
+	sig, err := dilithium.SignatureFromBytes(sigResp.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, true, sig.Verify(key.PublicKey(), root[:]))
+
+	// Signing the same attestation again is allowed.
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, data, root[:]), "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A different attestation with the same target is a double vote.
+	doubleVote := testAttestationData(1, 2)
+	doubleVote.BeaconBlockRoot = bytesutil.PadTo([]byte("other block"), 32)
+	resp, body = signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, doubleVote, nil), "")
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.StringContains(t, errSlashable.Error(), string(body))
+
+	// A surrounding vote is slashable as well.
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(0, 3), nil), "")
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(2, 3), nil), "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServer_SignBlockDoubleProposal(t *testing.T) {
+	_, srv, key := setupServer(t, &Config{AllowUnverifiedSigningRoots: true})
+	blockRequest := func(root byte) []byte {
+		return []byte(fmt.Sprintf(`{
+			"type": "BLOCK",
+			"fork_info": {"fork": {"previous_version": "0x00000000", "current_version": "0x00000000", "epoch": "0"}, "genesis_validators_root": "%s"},
+			"signingRoot": "%#064x",
+			"block": {"slot": "5", "proposer_index": "1"}
+		}`, hexutil.Encode(testGenesisValidatorsRoot), root))
+	}
+	resp, _ := signRequest(t, srv, key.PublicKey().Marshal(), blockRequest(1), "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = signRequest(t, srv, key.PublicKey().Marshal(), blockRequest(1), "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, body := signRequest(t, srv, key.PublicKey().Marshal(), blockRequest(2), "")
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.StringContains(t, "double proposal", string(body))
+}
+
+func TestServer_SignUnknownKey(t *testing.T) {
+	_, srv, _ := setupServer(t, &Config{})
+	other, err := dilithium.RandKey()
+	require.NoError(t, err)
+	resp, _ := signRequest(t, srv, other.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(1, 2), nil), "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = signRequest(t, srv, []byte{1, 2, 3}, attestationRequestBody(t, testAttestationData(1, 2), nil), "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_GenesisValidatorsRootMismatch(t *testing.T) {
+	_, srv, key := setupServer(t, &Config{GenesisValidatorsRoot: bytesutil.PadTo([]byte("another network"), 32)})
+	resp, body := signRequest(t, srv, key.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(1, 2), nil), "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.StringContains(t, "does not match the genesis validators root", string(body))
+}
+
+func TestServer_Reload(t *testing.T) {
+	s, srv, _ := setupServer(t, &Config{})
+	added := writeKeystore(t, s.cfg.KeystoresDir)
+	resp, _ := signRequest(t, srv, added.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(1, 2), nil), "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	reloadResp, err := srv.Client().Post(srv.URL+"/reload", "application/json", nil)
+	require.NoError(t, err)
+	require.NoError(t, reloadResp.Body.Close())
+	assert.Equal(t, http.StatusOK, reloadResp.StatusCode)
+
+	resp, _ = signRequest(t, srv, added.PublicKey().Marshal(), attestationRequestBody(t, testAttestationData(1, 2), nil), "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestPinGenesisValidatorsRoot(t *testing.T) {
+	ctx := context.Background()
+	db, err := kv.NewKVStore(ctx, t.TempDir(), &kv.Config{})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	gvr, err := pinGenesisValidatorsRoot(ctx, db, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(gvr))
+
+	gvr, err = pinGenesisValidatorsRoot(ctx, db, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	assert.DeepEqual(t, testGenesisValidatorsRoot, gvr)
+
+	// The pinned root is used when none is configured.
+	gvr, err = pinGenesisValidatorsRoot(ctx, db, nil)
+	require.NoError(t, err)
+	assert.DeepEqual(t, testGenesisValidatorsRoot, gvr)
+
+	_, err = pinGenesisValidatorsRoot(ctx, db, bytesutil.PadTo([]byte("another network"), 32))
+	assert.ErrorContains(t, "does not match the root", err)
+}
+
+func TestServer_TLSConfig(t *testing.T) {
+	s := &Server{cfg: &Config{TLSClientCAFile: "ca.pem"}}
+	_, err := s.tlsConfig()
+	assert.ErrorContains(t, "requires a TLS certificate and key", err)
+
+	s.cfg = &Config{TLSCertFile: "cert.pem"}
+	_, err = s.tlsConfig()
+	assert.ErrorContains(t, "both a TLS certificate and key are required", err)
+
+	s.cfg = &Config{}
+	tlsCfg, err := s.tlsConfig()
+	require.NoError(t, err)
+	assert.Equal(t, true, tlsCfg == nil)
+}
@@ -0,0 +1,523 @@
+package remotesigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	fssz "github.com/prysmaticlabs/fastssz"
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/go-zond/common/hexutil"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	v1 "github.com/theQRL/qrysm/v4/validator/keymanager/remote-web3signer/v1"
+)
+
+// Sign request types, as sent by the remote-web3signer keymanager.
+const (
+	typeBlock                             = "BLOCK"
+	typeBlockV2                           = "BLOCK_V2"
+	typeAttestation                       = "ATTESTATION"
+	typeAggregationSlot                   = "AGGREGATION_SLOT"
+	typeAggregateAndProof                 = "AGGREGATE_AND_PROOF"
+	typeRandaoReveal                      = "RANDAO_REVEAL"
+	typeVoluntaryExit                     = "VOLUNTARY_EXIT"
+	typeSyncCommitteeMessage              = "SYNC_COMMITTEE_MESSAGE"
+	typeSyncCommitteeSelectionProof       = "SYNC_COMMITTEE_SELECTION_PROOF"
+	typeSyncCommitteeContributionAndProof = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	typeValidatorRegistration             = "VALIDATOR_REGISTRATION"
+)
+
+const (
+	errUnverifiableSigningRootFmt    = "the signing root of %s requests cannot be computed from the request"
+	errSigningRootMismatchFmt        = "signing root %#x does not match the signing root %#x computed from the request"
+	errMissingForkInfoFmt            = "fork info is required for %s requests"
+	errUnsupportedSignRequestTypeFmt = "unsupported sign request type %q"
+	errSigningRootRequiredFmt        = "a 32 byte signing root is required for %s requests"
+)
+
+// errBadRequest marks errors caused by a malformed or unacceptable sign request.
+var errBadRequest = errors.New("bad sign request")
+
+// signingData is what the signer needs to know about a sign request: the root to sign
+// and, for slashable messages, the data checked against the slashing protection history.
+type signingData struct {
+	requestType           string
+	signingRoot           [32]byte
+	genesisValidatorsRoot []byte
+	// attestation is set for attestation requests.
+	attestation *zondpb.AttestationData
+	// blockSlot is set for block requests.
+	blockSlot *primitives.Slot
+}
+
+// parseSignRequest decodes a sign request and computes the signing root of the object
+// it carries. The signing root sent by the client is only trusted for the block requests
+// whose object cannot be reconstructed, and only if allowUnverified is set.
+func parseSignRequest(body []byte, allowUnverified bool) (*signingData, error) {
+	header := struct {
+		Type        string        `json:"type"`
+		SigningRoot hexutil.Bytes `json:"signingRoot"`
+	}{}
+	if err := json.Unmarshal(body, &header); err != nil {
+		return nil, badRequest(errors.Wrap(err, "could not decode sign request"))
+	}
+
+	var data *signingData
+	var err error
+	switch header.Type {
+	case typeAttestation:
+		data, err = attestationSigningData(body)
+	case typeBlockV2:
+		data, err = blockV2SigningData(body, allowUnverified)
+	case typeBlock:
+		data, err = blockSigningData(body, allowUnverified)
+	case typeRandaoReveal:
+		data, err = randaoRevealSigningData(body)
+	case typeAggregationSlot:
+		data, err = aggregationSlotSigningData(body)
+	case typeVoluntaryExit:
+		data, err = voluntaryExitSigningData(body)
+	case typeSyncCommitteeMessage:
+		data, err = syncCommitteeMessageSigningData(body)
+	case typeSyncCommitteeSelectionProof:
+		data, err = syncCommitteeSelectionProofSigningData(body)
+	case typeValidatorRegistration:
+		data, err = validatorRegistrationSigningData(body)
+	case typeAggregateAndProof:
+		data, err = aggregateAndProofSigningData(body)
+	case typeSyncCommitteeContributionAndProof:
+		data, err = contributionAndProofSigningData(body)
+	default:
+		return nil, badRequest(fmt.Errorf(errUnsupportedSignRequestTypeFmt, header.Type))
+	}
+	if err != nil {
+		if errors.Is(err, errBadRequest) {
+			return nil, err
+		}
+		return nil, badRequest(err)
+	}
+	data.requestType = header.Type
+
+	if data.signingRoot == ([32]byte{}) {
+		// Only unverified requests leave the root empty, and they have to provide it.
+		if len(header.SigningRoot) != 32 {
+			return nil, badRequest(fmt.Errorf(errSigningRootRequiredFmt, header.Type))
+		}
+		copy(data.signingRoot[:], header.SigningRoot)
+	} else if len(header.SigningRoot) != 0 && !bytes.Equal(header.SigningRoot, data.signingRoot[:]) {
+		return nil, badRequest(fmt.Errorf(errSigningRootMismatchFmt, []byte(header.SigningRoot), data.signingRoot))
+	}
+	return data, nil
+}
+
+func attestationSigningData(body []byte) (*signingData, error) {
+	req := &v1.AttestationSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.Attestation == nil {
+		return nil, errors.New("attestation is required")
+	}
+	data, err := attestationData(req.Attestation)
+	if err != nil {
+		return nil, err
+	}
+	d, err := computeSigningData(req.ForkInfo, typeAttestation, data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester, data)
+	if err != nil {
+		return nil, err
+	}
+	d.attestation = data
+	return d, nil
+}
+
+func blockV2SigningData(body []byte, allowUnverified bool) (*signingData, error) {
+	req := struct {
+		ForkInfo    *v1.ForkInfo `json:"fork_info"`
+		BeaconBlock *struct {
+			Version     string                `json:"version"`
+			BlockHeader *v1.BeaconBlockHeader `json:"block_header"`
+			Block       *v1.BeaconBlockAltair `json:"block"`
+		} `json:"beacon_block"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if req.BeaconBlock == nil {
+		return nil, errors.New("beacon block is required")
+	}
+	if req.BeaconBlock.BlockHeader == nil {
+		if req.BeaconBlock.Block == nil {
+			return nil, errors.New("block header is required")
+		}
+		// The body root of a full block cannot be computed, as its attestations miss the
+		// indices of their signers.
+		slot, err := parseSlot(req.BeaconBlock.Block.Slot)
+		if err != nil {
+			return nil, err
+		}
+		return unverifiedBlockSigningData(req.ForkInfo, typeBlockV2, slot, allowUnverified)
+	}
+	header, err := beaconBlockHeader(req.BeaconBlock.BlockHeader)
+	if err != nil {
+		return nil, err
+	}
+	d, err := computeSigningData(req.ForkInfo, typeBlockV2, slots.ToEpoch(header.Slot), params.BeaconConfig().DomainBeaconProposer, header)
+	if err != nil {
+		return nil, err
+	}
+	d.blockSlot = &header.Slot
+	return d, nil
+}
+
+func blockSigningData(body []byte, allowUnverified bool) (*signingData, error) {
+	req := &v1.BlockSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.Block == nil {
+		return nil, errors.New("block is required")
+	}
+	slot, err := parseSlot(req.Block.Slot)
+	if err != nil {
+		return nil, err
+	}
+	return unverifiedBlockSigningData(req.ForkInfo, typeBlock, slot, allowUnverified)
+}
+
+func unverifiedBlockSigningData(forkInfo *v1.ForkInfo, requestType string, slot primitives.Slot, allowUnverified bool) (*signingData, error) {
+	if !allowUnverified {
+		return nil, fmt.Errorf(errUnverifiableSigningRootFmt, requestType)
+	}
+	if forkInfo == nil {
+		return nil, fmt.Errorf(errMissingForkInfoFmt, requestType)
+	}
+	return &signingData{
+		genesisValidatorsRoot: forkInfo.GenesisValidatorsRoot,
+		blockSlot:             &slot,
+	}, nil
+}
+
+func aggregateAndProofSigningData(body []byte) (*signingData, error) {
+	req := &v1.AggregateAndProofSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.AggregateAndProof == nil || req.AggregateAndProof.Aggregate == nil || req.AggregateAndProof.Aggregate.Data == nil {
+		return nil, errors.New("aggregate and proof is required")
+	}
+	aggregatorIndex, err := parseUint(req.AggregateAndProof.AggregatorIndex, "aggregator index")
+	if err != nil {
+		return nil, err
+	}
+	data, err := attestationData(req.AggregateAndProof.Aggregate.Data)
+	if err != nil {
+		return nil, err
+	}
+	signatureValidatorIndex := make([]uint64, len(req.AggregateAndProof.Aggregate.SignatureValidatorIndex))
+	for i, idx := range req.AggregateAndProof.Aggregate.SignatureValidatorIndex {
+		if signatureValidatorIndex[i], err = parseUint(idx, "signature validator index"); err != nil {
+			return nil, err
+		}
+	}
+	aggregate := &zondpb.AggregateAttestationAndProof{
+		AggregatorIndex: primitives.ValidatorIndex(aggregatorIndex),
+		Aggregate: &zondpb.Attestation{
+			AggregationBits:         bitfield.Bitlist(req.AggregateAndProof.Aggregate.AggregationBits),
+			Data:                    data,
+			Signature:               req.AggregateAndProof.Aggregate.Signature,
+			SignatureValidatorIndex: signatureValidatorIndex,
+		},
+		SelectionProof: req.AggregateAndProof.SelectionProof,
+	}
+	return computeSigningData(req.ForkInfo, typeAggregateAndProof, slots.ToEpoch(data.Slot), params.BeaconConfig().DomainAggregateAndProof, aggregate)
+}
+
+func contributionAndProofSigningData(body []byte) (*signingData, error) {
+	req := &v1.SyncCommitteeContributionAndProofSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.ContributionAndProof == nil || req.ContributionAndProof.Contribution == nil {
+		return nil, errors.New("contribution and proof is required")
+	}
+	aggregatorIndex, err := parseUint(req.ContributionAndProof.AggregatorIndex, "aggregator index")
+	if err != nil {
+		return nil, err
+	}
+	c := req.ContributionAndProof.Contribution
+	slot, err := parseSlot(c.Slot)
+	if err != nil {
+		return nil, err
+	}
+	subcommitteeIndex, err := parseUint(c.SubcommitteeIndex, "subcommittee index")
+	if err != nil {
+		return nil, err
+	}
+	if len(c.BeaconBlockRoot) != 32 {
+		return nil, errors.New("beacon block root must be 32 bytes")
+	}
+	contribution := &zondpb.ContributionAndProof{
+		AggregatorIndex: primitives.ValidatorIndex(aggregatorIndex),
+		Contribution: &zondpb.SyncCommitteeContribution{
+			Slot:              slot,
+			BlockRoot:         c.BeaconBlockRoot,
+			SubcommitteeIndex: subcommitteeIndex,
+			AggregationBits:   bitfield.Bitvector16(c.AggregationBits),
+			Signature:         c.Signature,
+		},
+		SelectionProof: req.ContributionAndProof.SelectionProof,
+	}
+	return computeSigningData(req.ForkInfo, typeSyncCommitteeContributionAndProof, slots.ToEpoch(slot), params.BeaconConfig().DomainContributionAndProof, contribution)
+}
+
+func randaoRevealSigningData(body []byte) (*signingData, error) {
+	req := &v1.RandaoRevealSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.RandaoReveal == nil {
+		return nil, errors.New("randao reveal is required")
+	}
+	epoch, err := parseUint(req.RandaoReveal.Epoch, "epoch")
+	if err != nil {
+		return nil, err
+	}
+	sszEpoch := primitives.SSZUint64(epoch)
+	return computeSigningData(req.ForkInfo, typeRandaoReveal, primitives.Epoch(epoch), params.BeaconConfig().DomainRandao, &sszEpoch)
+}
+
+func aggregationSlotSigningData(body []byte) (*signingData, error) {
+	req := &v1.AggregationSlotSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.AggregationSlot == nil {
+		return nil, errors.New("aggregation slot is required")
+	}
+	slot, err := parseSlot(req.AggregationSlot.Slot)
+	if err != nil {
+		return nil, err
+	}
+	sszSlot := primitives.SSZUint64(slot)
+	return computeSigningData(req.ForkInfo, typeAggregationSlot, slots.ToEpoch(slot), params.BeaconConfig().DomainSelectionProof, &sszSlot)
+}
+
+func voluntaryExitSigningData(body []byte) (*signingData, error) {
+	req := &v1.VoluntaryExitSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.VoluntaryExit == nil {
+		return nil, errors.New("voluntary exit is required")
+	}
+	epoch, err := parseUint(req.VoluntaryExit.Epoch, "epoch")
+	if err != nil {
+		return nil, err
+	}
+	validatorIndex, err := parseUint(req.VoluntaryExit.ValidatorIndex, "validator index")
+	if err != nil {
+		return nil, err
+	}
+	exit := &zondpb.VoluntaryExit{
+		Epoch:          primitives.Epoch(epoch),
+		ValidatorIndex: primitives.ValidatorIndex(validatorIndex),
+	}
+	return computeSigningData(req.ForkInfo, typeVoluntaryExit, exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, exit)
+}
+
+func syncCommitteeMessageSigningData(body []byte) (*signingData, error) {
+	req := &v1.SyncCommitteeMessageSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.SyncCommitteeMessage == nil {
+		return nil, errors.New("sync committee message is required")
+	}
+	slot, err := parseSlot(req.SyncCommitteeMessage.Slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.SyncCommitteeMessage.BeaconBlockRoot) != 32 {
+		return nil, errors.New("beacon block root must be 32 bytes")
+	}
+	sszRoot := primitives.SSZBytes(req.SyncCommitteeMessage.BeaconBlockRoot)
+	return computeSigningData(req.ForkInfo, typeSyncCommitteeMessage, slots.ToEpoch(slot), params.BeaconConfig().DomainSyncCommittee, &sszRoot)
+}
+
+func syncCommitteeSelectionProofSigningData(body []byte) (*signingData, error) {
+	req := &v1.SyncCommitteeSelectionProofSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.SyncAggregatorSelectionData == nil {
+		return nil, errors.New("sync aggregator selection data is required")
+	}
+	slot, err := parseSlot(req.SyncAggregatorSelectionData.Slot)
+	if err != nil {
+		return nil, err
+	}
+	subcommitteeIndex, err := parseUint(req.SyncAggregatorSelectionData.SubcommitteeIndex, "subcommittee index")
+	if err != nil {
+		return nil, err
+	}
+	data := &zondpb.SyncAggregatorSelectionData{
+		Slot:              slot,
+		SubcommitteeIndex: subcommitteeIndex,
+	}
+	return computeSigningData(req.ForkInfo, typeSyncCommitteeSelectionProof, slots.ToEpoch(slot), params.BeaconConfig().DomainSyncCommitteeSelectionProof, data)
+}
+
+func validatorRegistrationSigningData(body []byte) (*signingData, error) {
+	req := &v1.ValidatorRegistrationSignRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	if req.ValidatorRegistration == nil {
+		return nil, errors.New("validator registration is required")
+	}
+	gasLimit, err := parseUint(req.ValidatorRegistration.GasLimit, "gas limit")
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := parseUint(req.ValidatorRegistration.Timestamp, "timestamp")
+	if err != nil {
+		return nil, err
+	}
+	reg := &zondpb.ValidatorRegistrationV1{
+		FeeRecipient: req.ValidatorRegistration.FeeRecipient,
+		GasLimit:     gasLimit,
+		Timestamp:    timestamp,
+		Pubkey:       req.ValidatorRegistration.Pubkey,
+	}
+	// Registrations are signed with the genesis fork version and an empty genesis validators root.
+	domain, err := signing.ComputeDomain(params.BeaconConfig().DomainApplicationBuilder, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	root, err := signing.ComputeSigningRoot(reg, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &signingData{signingRoot: root}, nil
+}
+
+func computeSigningData(
+	forkInfo *v1.ForkInfo, requestType string, epoch primitives.Epoch, domainType [4]byte, obj fssz.HashRoot,
+) (*signingData, error) {
+	if forkInfo == nil || forkInfo.Fork == nil {
+		return nil, fmt.Errorf(errMissingForkInfoFmt, requestType)
+	}
+	forkEpoch, err := parseUint(forkInfo.Fork.Epoch, "fork epoch")
+	if err != nil {
+		return nil, err
+	}
+	fork := &zondpb.Fork{
+		PreviousVersion: forkInfo.Fork.PreviousVersion,
+		CurrentVersion:  forkInfo.Fork.CurrentVersion,
+		Epoch:           primitives.Epoch(forkEpoch),
+	}
+	domain, err := signing.Domain(fork, epoch, domainType, forkInfo.GenesisValidatorsRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signature domain")
+	}
+	root, err := signing.ComputeSigningRoot(obj, domain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	return &signingData{
+		signingRoot:           root,
+		genesisValidatorsRoot: forkInfo.GenesisValidatorsRoot,
+	}, nil
+}
+
+func attestationData(data *v1.AttestationData) (*zondpb.AttestationData, error) {
+	slot, err := parseSlot(data.Slot)
+	if err != nil {
+		return nil, err
+	}
+	committeeIndex, err := parseUint(data.Index, "committee index")
+	if err != nil {
+		return nil, err
+	}
+	source, err := checkpoint(data.Source)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid source")
+	}
+	target, err := checkpoint(data.Target)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid target")
+	}
+	if len(data.BeaconBlockRoot) != 32 {
+		return nil, errors.New("beacon block root must be 32 bytes")
+	}
+	return &zondpb.AttestationData{
+		Slot:            slot,
+		CommitteeIndex:  primitives.CommitteeIndex(committeeIndex),
+		BeaconBlockRoot: data.BeaconBlockRoot,
+		Source:          source,
+		Target:          target,
+	}, nil
+}
+
+func checkpoint(c *v1.Checkpoint) (*zondpb.Checkpoint, error) {
+	if c == nil {
+		return nil, errors.New("checkpoint is required")
+	}
+	epoch, err := parseUint(c.Epoch, "epoch")
+	if err != nil {
+		return nil, err
+	}
+	root, err := hexutil.Decode(c.Root)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid root")
+	}
+	if len(root) != 32 {
+		return nil, errors.New("root must be 32 bytes")
+	}
+	return &zondpb.Checkpoint{Epoch: primitives.Epoch(epoch), Root: root}, nil
+}
+
+func beaconBlockHeader(h *v1.BeaconBlockHeader) (*zondpb.BeaconBlockHeader, error) {
+	slot, err := parseSlot(h.Slot)
+	if err != nil {
+		return nil, err
+	}
+	proposerIndex, err := parseUint(h.ProposerIndex, "proposer index")
+	if err != nil {
+		return nil, err
+	}
+	if len(h.ParentRoot) != 32 || len(h.StateRoot) != 32 || len(h.BodyRoot) != 32 {
+		return nil, errors.New("block header roots must be 32 bytes")
+	}
+	return &zondpb.BeaconBlockHeader{
+		Slot:          slot,
+		ProposerIndex: primitives.ValidatorIndex(proposerIndex),
+		ParentRoot:    h.ParentRoot,
+		StateRoot:     h.StateRoot,
+		BodyRoot:      h.BodyRoot,
+	}, nil
+}
+
+func parseSlot(s string) (primitives.Slot, error) {
+	slot, err := parseUint(s, "slot")
+	return primitives.Slot(slot), err
+}
+
+func parseUint(s, name string) (uint64, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", name)
+	}
+	return v, nil
+}
+
+func badRequest(err error) error {
+	return fmt.Errorf("%w: %v", errBadRequest, err)
+}
@@ -0,0 +1,233 @@
+package remotesigner
+
+import (
+	"encoding/json"
+	"testing"
+
+	fssz "github.com/prysmaticlabs/fastssz"
+	"github.com/theQRL/go-bitfield"
+	"github.com/theQRL/qrysm/v4/beacon-chain/core/signing"
+	"github.com/theQRL/qrysm/v4/config/params"
+	"github.com/theQRL/qrysm/v4/consensus-types/primitives"
+	"github.com/theQRL/qrysm/v4/crypto/dilithium"
+	"github.com/theQRL/qrysm/v4/encoding/bytesutil"
+	"github.com/theQRL/qrysm/v4/network/forks"
+	zondpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1"
+	validatorpb "github.com/theQRL/qrysm/v4/proto/prysm/v1alpha1/validator-client"
+	"github.com/theQRL/qrysm/v4/testing/assert"
+	"github.com/theQRL/qrysm/v4/testing/require"
+	"github.com/theQRL/qrysm/v4/time/slots"
+	v1 "github.com/theQRL/qrysm/v4/validator/keymanager/remote-web3signer/v1"
+)
+
+var testGenesisValidatorsRoot = bytesutil.PadTo([]byte("genesis validators root"), 32)
+
+// expectedSigningRoot computes the signing root the way the validator client does.
+func expectedSigningRoot(t *testing.T, slot primitives.Slot, epoch primitives.Epoch, domainType [4]byte, obj fssz.HashRoot) [32]byte {
+	fork, err := forks.Fork(slots.ToEpoch(slot))
+	require.NoError(t, err)
+	domain, err := signing.Domain(fork, epoch, domainType, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	root, err := signing.ComputeSigningRoot(obj, domain)
+	require.NoError(t, err)
+	return root
+}
+
+func testAttestationData(source, target primitives.Epoch) *zondpb.AttestationData {
+	return &zondpb.AttestationData{
+		Slot:            params.BeaconConfig().SlotsPerEpoch.Mul(uint64(target)),
+		CommitteeIndex:  1,
+		BeaconBlockRoot: bytesutil.PadTo([]byte("block"), 32),
+		Source:          &zondpb.Checkpoint{Epoch: source, Root: bytesutil.PadTo([]byte("source"), 32)},
+		Target:          &zondpb.Checkpoint{Epoch: target, Root: bytesutil.PadTo([]byte("target"), 32)},
+	}
+}
+
+func attestationRequestBody(t *testing.T, data *zondpb.AttestationData, signingRoot []byte) []byte {
+	req, err := v1.GetAttestationSignRequest(&validatorpb.SignRequest{
+		SigningRoot: signingRoot,
+		SigningSlot: data.Slot,
+		Object:      &validatorpb.SignRequest_AttestationData{AttestationData: data},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	return body
+}
+
+func TestParseSignRequest_Attestation(t *testing.T) {
+	data := testAttestationData(1, 2)
+	root := expectedSigningRoot(t, data.Slot, data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester, data)
+
+	parsed, err := parseSignRequest(attestationRequestBody(t, data, root[:]), false)
+	require.NoError(t, err)
+	assert.Equal(t, typeAttestation, parsed.requestType)
+	assert.Equal(t, root, parsed.signingRoot)
+	assert.DeepEqual(t, testGenesisValidatorsRoot, parsed.genesisValidatorsRoot)
+	assert.DeepEqual(t, data, parsed.attestation)
+
+	// The signing root is optional.
+	parsed, err = parseSignRequest(attestationRequestBody(t, data, nil), false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+}
+
+func TestParseSignRequest_SigningRootMismatch(t *testing.T) {
+	data := testAttestationData(1, 2)
+	_, err := parseSignRequest(attestationRequestBody(t, data, bytesutil.PadTo([]byte("other"), 32)), true)
+	require.ErrorIs(t, err, errBadRequest)
+	assert.ErrorContains(t, "does not match the signing root", err)
+}
+
+func TestParseSignRequest_RandaoReveal(t *testing.T) {
+	epoch := primitives.Epoch(3)
+	slot := params.BeaconConfig().SlotsPerEpoch.Mul(uint64(epoch))
+	sszEpoch := primitives.SSZUint64(epoch)
+	root := expectedSigningRoot(t, slot, epoch, params.BeaconConfig().DomainRandao, &sszEpoch)
+	req, err := v1.GetRandaoRevealSignRequest(&validatorpb.SignRequest{
+		SigningRoot: root[:],
+		SigningSlot: slot,
+		Object:      &validatorpb.SignRequest_Epoch{Epoch: epoch},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	parsed, err := parseSignRequest(body, false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+	assert.Equal(t, true, parsed.attestation == nil && parsed.blockSlot == nil)
+}
+
+func TestParseSignRequest_VoluntaryExit(t *testing.T) {
+	exit := &zondpb.VoluntaryExit{Epoch: 2, ValidatorIndex: 7}
+	slot := params.BeaconConfig().SlotsPerEpoch.Mul(uint64(exit.Epoch))
+	root := expectedSigningRoot(t, slot, exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, exit)
+	req, err := v1.GetVoluntaryExitSignRequest(&validatorpb.SignRequest{
+		SigningRoot: root[:],
+		SigningSlot: slot,
+		Object:      &validatorpb.SignRequest_Exit{Exit: exit},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	parsed, err := parseSignRequest(body, false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+}
+
+func TestParseSignRequest_AggregationSlot(t *testing.T) {
+	slot := primitives.Slot(33)
+	sszSlot := primitives.SSZUint64(slot)
+	root := expectedSigningRoot(t, slot, slots.ToEpoch(slot), params.BeaconConfig().DomainSelectionProof, &sszSlot)
+	req, err := v1.GetAggregationSlotSignRequest(&validatorpb.SignRequest{
+		SigningRoot: root[:],
+		SigningSlot: slot,
+		Object:      &validatorpb.SignRequest_Slot{Slot: slot},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	parsed, err := parseSignRequest(body, false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+}
+
+func TestParseSignRequest_AggregateAndProof(t *testing.T) {
+	data := testAttestationData(1, 2)
+	aggregate := &zondpb.AggregateAttestationAndProof{
+		AggregatorIndex: 3,
+		Aggregate: &zondpb.Attestation{
+			AggregationBits:         bitfield.Bitlist{0b1101},
+			Data:                    data,
+			Signature:               bytesutil.PadTo([]byte("signature"), 2*dilithium.SignatureLength()),
+			SignatureValidatorIndex: []uint64{5, 9},
+		},
+		SelectionProof: bytesutil.PadTo([]byte("selection proof"), dilithium.SignatureLength()),
+	}
+	root := expectedSigningRoot(t, data.Slot, slots.ToEpoch(data.Slot), params.BeaconConfig().DomainAggregateAndProof, aggregate)
+	req, err := v1.GetAggregateAndProofSignRequest(&validatorpb.SignRequest{
+		SigningRoot: root[:],
+		SigningSlot: data.Slot,
+		Object:      &validatorpb.SignRequest_AggregateAttestationAndProof{AggregateAttestationAndProof: aggregate},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	parsed, err := parseSignRequest(body, false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+
+	// The signers of the aggregate are covered by the signing root.
+	req.AggregateAndProof.Aggregate.SignatureValidatorIndex = []string{"5", "10"}
+	body, err = json.Marshal(req)
+	require.NoError(t, err)
+	_, err = parseSignRequest(body, true)
+	require.ErrorIs(t, err, errBadRequest)
+	assert.ErrorContains(t, "does not match the signing root", err)
+}
+
+func TestParseSignRequest_ContributionAndProof(t *testing.T) {
+	slot := params.BeaconConfig().SlotsPerEpoch.Mul(2) + 1
+	contribution := &zondpb.ContributionAndProof{
+		AggregatorIndex: 3,
+		Contribution: &zondpb.SyncCommitteeContribution{
+			Slot:              slot,
+			BlockRoot:         bytesutil.PadTo([]byte("block"), 32),
+			SubcommitteeIndex: 1,
+			AggregationBits:   bitfield.Bitvector16{0b101, 0},
+			Signature:         bytesutil.PadTo([]byte("signature"), 2*dilithium.SignatureLength()),
+		},
+		SelectionProof: bytesutil.PadTo([]byte("selection proof"), dilithium.SignatureLength()),
+	}
+	root := expectedSigningRoot(t, slot, slots.ToEpoch(slot), params.BeaconConfig().DomainContributionAndProof, contribution)
+	req, err := v1.GetSyncCommitteeContributionAndProofSignRequest(&validatorpb.SignRequest{
+		SigningRoot: root[:],
+		SigningSlot: slot,
+		Object:      &validatorpb.SignRequest_ContributionAndProof{ContributionAndProof: contribution},
+	}, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	parsed, err := parseSignRequest(body, false)
+	require.NoError(t, err)
+	assert.Equal(t, root, parsed.signingRoot)
+}
+
+func TestParseSignRequest_UnverifiableRequests(t *testing.T) {
+	body := []byte(`{
+		"type": "BLOCK",
+		"fork_info": {"fork": {"previous_version": "0x00000000", "current_version": "0x00000000", "epoch": "0"}, "genesis_validators_root": "0x00"},
+		"block": {"slot": "1"},
+		"signingRoot": "0x0000000000000000000000000000000000000000000000000000000000000001"
+	}`)
+	_, err := parseSignRequest(body, false)
+	require.ErrorIs(t, err, errBadRequest)
+	assert.ErrorContains(t, "cannot be computed from the request", err)
+
+	parsed, err := parseSignRequest(body, true)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), parsed.signingRoot[31])
+
+	// Unverified requests have to provide the signing root.
+	_, err = parseSignRequest([]byte(`{
+		"type": "BLOCK",
+		"fork_info": {"fork": {"previous_version": "0x00000000", "current_version": "0x00000000", "epoch": "0"}, "genesis_validators_root": "0x00"},
+		"block": {"slot": "1"}
+	}`), true)
+	require.ErrorIs(t, err, errBadRequest)
+	assert.ErrorContains(t, "a 32 byte signing root is required", err)
+}
+
+func TestParseSignRequest_UnsupportedType(t *testing.T) {
+	_, err := parseSignRequest([]byte(`{"type": "DEPOSIT"}`), true)
+	require.ErrorIs(t, err, errBadRequest)
+	assert.ErrorContains(t, `unsupported sign request type "DEPOSIT"`, err)
+
+	_, err = parseSignRequest([]byte(`not json`), true)
+	require.ErrorIs(t, err, errBadRequest)
+}